@@ -31,6 +31,9 @@ import (
 	"github.com/hexagon-codes/ai-core/tool"
 	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/internal/util"
+	"github.com/hexagon-codes/hexagon/llm/parser"
+	"github.com/hexagon-codes/hexagon/llm/prompt"
+	"github.com/hexagon-codes/hexagon/security/guard"
 	"github.com/hexagon-codes/hexagon/stream"
 )
 
@@ -41,6 +44,9 @@ type Input struct {
 
 	// Context 额外上下文
 	Context map[string]any `json:"context,omitempty"`
+
+	// ThreadID 会话线程 ID，配合 WithThreadStore 自动加载和保存对话历史（仅 ReActAgent）
+	ThreadID string `json:"thread_id,omitempty"`
 }
 
 // Output 是 Agent 的输出
@@ -115,6 +121,15 @@ type Config struct {
 	// LLM LLM 提供者
 	LLM llm.Provider
 
+	// LLMFallbacks 主 Provider 失败时依次尝试的备用 Provider
+	LLMFallbacks []llm.Provider
+
+	// LLMFallbackOn 判断错误是否触发降级，为 nil 时除 context 取消/超时外都降级
+	LLMFallbackOn func(error) bool
+
+	// LLMCircuitBreakers Provider 熔断器，为 nil 时不熔断
+	LLMCircuitBreakers *LLMCircuitBreakers
+
 	// Tools 可用工具列表
 	Tools []tool.Tool
 
@@ -126,6 +141,48 @@ type Config struct {
 
 	// Verbose 是否输出详细日志
 	Verbose bool
+
+	// OutputGuard 最终回复的输出守卫
+	OutputGuard guard.Guard
+
+	// OutputGuardAction 输出未通过守卫时的动作，默认 guard.ActionBlock
+	OutputGuardAction guard.GuardAction
+
+	// ToolPolicy 工具访问策略，为 nil 时不限制
+	ToolPolicy *ToolPolicy
+
+	// PromptTemplate 系统提示词模板，设置后替代 SystemPrompt
+	PromptTemplate prompt.Renderer
+
+	// ContextProviders 每次运行时注入系统提示词的动态上下文
+	ContextProviders []ContextProvider
+
+	// OutputParser 最终回复的输出解析器，为 nil 时不解析
+	OutputParser parser.AnyParser
+
+	// OutputParserRetries 解析失败时请求模型修正的最大次数
+	OutputParserRetries int
+
+	// SemanticCache 语义缓存，为 nil 时不缓存
+	SemanticCache *SemanticCache
+
+	// SemanticCacheThreshold 缓存命中的相似度阈值，<= 0 时使用缓存的默认阈值
+	SemanticCacheThreshold float64
+
+	// CacheScope 语义缓存的隔离范围，为空时使用 Agent 名称
+	CacheScope string
+
+	// ExactCache 按完整请求精确匹配的 LLM 响应缓存，为 nil 时不缓存
+	ExactCache *ExactCache
+
+	// ToolCache 工具结果缓存，为 nil 时不缓存
+	ToolCache *ToolCache
+
+	// RunBudget 单次运行的预算，零值不限制
+	RunBudget RunBudget
+
+	// ThreadStore 会话线程存储，为 nil 时不持久化对话；仅 ReActAgent 使用
+	ThreadStore ThreadStore
 }
 
 // Option 是 Agent 配置选项
@@ -201,6 +258,32 @@ func WithRole(role Role) Option {
 	}
 }
 
+// WithOutputGuards 设置输出守卫
+//
+// Agent 返回最终回复前用守卫链（ChainModeAll）检查回复内容，
+// 未通过时按 OutputGuardAction 处理，参见 WithOutputGuardAction。
+func WithOutputGuards(guards ...guard.Guard) Option {
+	return func(c *Config) {
+		if chain, ok := c.OutputGuard.(*guard.GuardChain); ok {
+			for _, g := range guards {
+				chain.Add(g)
+			}
+			return
+		}
+		c.OutputGuard = guard.NewGuardChain(guard.ChainModeAll, guards...)
+	}
+}
+
+// WithOutputGuardAction 设置输出未通过守卫时的动作
+//   - guard.ActionBlock: 拒绝返回回复（默认）
+//   - guard.ActionRedact: 脱敏后返回，无法脱敏时拒绝
+//   - guard.ActionWarn / guard.ActionLog: 原样返回并记录检查结果
+func WithOutputGuardAction(action guard.GuardAction) Option {
+	return func(c *Config) {
+		c.OutputGuardAction = action
+	}
+}
+
 // MemorySetter 允许外部替换 Agent 的记忆系统
 //
 // 用于共享记忆场景：Team 通过此接口将 Agent 原始记忆包装为 SharedMemoryProxy，
@@ -212,6 +295,12 @@ type MemorySetter interface {
 // BaseAgent 提供 Agent 的基础实现
 type BaseAgent struct {
 	config Config
+
+	// ownedMemory 未注入 Memory 时内部创建的记忆，由 Close 负责关闭
+	ownedMemory memory.Memory
+
+	// lifecycle 跟踪进行中的运行
+	lifecycle runTracker
 }
 
 // SetMemory 替换 Agent 的记忆系统
@@ -234,11 +323,35 @@ func NewBaseAgent(opts ...Option) *BaseAgent {
 		opt(&cfg)
 	}
 
+	a := &BaseAgent{}
 	if cfg.Memory == nil {
 		cfg.Memory = memory.NewBuffer(100)
+		a.ownedMemory = cfg.Memory
 	}
+	a.config = cfg
 
-	return &BaseAgent{config: cfg}
+	return a
+}
+
+// Close 关闭 Agent
+//
+// 拒绝新的运行，取消进行中的运行并等待其返回（受 ctx 限制），
+// 然后关闭 Agent 自行创建的资源。注入的 LLM、工具和记忆不会被关闭。
+// 可重复调用。
+func (a *BaseAgent) Close(ctx context.Context) error {
+	first, err := a.lifecycle.shutdown(ctx)
+	if err != nil {
+		return err
+	}
+	if first && a.ownedMemory != nil {
+		return closeResource(ctx, a.ownedMemory)
+	}
+	return nil
+}
+
+// beginRun 登记一次运行，Close 时会取消返回的 context
+func (a *BaseAgent) beginRun(ctx context.Context) (context.Context, func(), error) {
+	return a.lifecycle.begin(ctx)
 }
 
 // ID 返回 Agent ID
@@ -283,17 +396,38 @@ func (a *BaseAgent) Config() Config {
 
 // Invoke 执行 Agent
 // BaseAgent 提供简单的 LLM 对话实现，子类可以覆盖此方法实现更复杂的逻辑
+// opts 支持 WithRunTemperature、WithRunSeed 等单次运行选项
 func (a *BaseAgent) Invoke(ctx context.Context, input Input, opts ...core.Option) (Output, error) {
 	if a.config.LLM == nil {
-		return Output{}, fmt.Errorf("LLM provider not configured")
+		return Output{}, fmt.Errorf("LLM provider %w", core.ErrNotConfigured)
+	}
+
+	ctx, done, err := a.beginRun(ctx)
+	if err != nil {
+		return Output{}, err
+	}
+	defer done()
+
+	ctx, _ = takeRunSampling(ContextWithRunOptions(ctx, opts...), a.config.LLM)
+	cached, cacheRun, hit := a.cacheLookup(ctx, input, nil)
+	if hit {
+		return cached, nil
+	}
+
+	ctx, budget, cancel := a.beginBudget(ctx)
+	defer cancel()
+
+	systemPrompt, err := a.systemPrompt(ctx, input)
+	if err != nil {
+		return Output{}, err
 	}
 
 	// 构建消息
 	messages := make([]llm.Message, 0, 2)
-	if a.config.SystemPrompt != "" {
+	if systemPrompt != "" {
 		messages = append(messages, llm.Message{
 			Role:    llm.RoleSystem,
-			Content: a.config.SystemPrompt,
+			Content: systemPrompt,
 		})
 	}
 	messages = append(messages, llm.Message{
@@ -302,17 +436,26 @@ func (a *BaseAgent) Invoke(ctx context.Context, input Input, opts ...core.Option
 	})
 
 	// 调用 LLM
-	resp, err := a.config.LLM.Complete(ctx, llm.CompletionRequest{
+	resp, err := completeLLM(ctx, a.config.LLM, llm.CompletionRequest{
 		Messages: messages,
 	})
 	if err != nil {
-		return Output{}, fmt.Errorf("LLM completion failed: %w", err)
+		return Output{}, fmt.Errorf("LLM completion failed: %w", budget.err(ctx, err))
 	}
 
-	return Output{
+	output, err := a.parseOutput(ctx, input, systemPrompt, Output{
 		Content: resp.Content,
 		Usage:   resp.Usage,
-	}, nil
+	})
+	if err != nil {
+		return Output{}, err
+	}
+	output, err = a.guardOutput(ctx, output)
+	if err != nil {
+		return Output{}, err
+	}
+	a.cacheStore(ctx, cacheRun, input, output)
+	return output, nil
 }
 
 // Run 是 Invoke 的别名（向后兼容）
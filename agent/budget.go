@@ -0,0 +1,335 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	agentruntime "github.com/hexagon-codes/hexagon/runtime"
+)
+
+// ErrBudgetExceeded 运行预算耗尽
+// 具体的计数信息见 *BudgetExceededError
+var ErrBudgetExceeded = errors.New("agent: run budget exceeded")
+
+// MetadataBudgetExceeded 预算耗尽后生成总结回复时，输出元数据中记录 *BudgetExceededError 的键
+const MetadataBudgetExceeded = "budget_exceeded"
+
+// budgetSummaryTimeout 预算耗尽后总结调用的超时时间
+const budgetSummaryTimeout = 30 * time.Second
+
+// RunBudget 单次运行的资源上限，各项 <= 0 时不限制
+type RunBudget struct {
+	// MaxTokens 累计 Token 上限
+	MaxTokens int
+
+	// MaxToolCalls 累计工具调用次数上限
+	MaxToolCalls int
+
+	// MaxWallClock 运行时长上限
+	MaxWallClock time.Duration
+
+	// Summarize 预算耗尽时是否让模型基于已有信息再做一次（不带工具的）总结回复
+	Summarize bool
+}
+
+// IsZero 是否未设置任何上限
+func (b RunBudget) IsZero() bool {
+	return b.MaxTokens <= 0 && b.MaxToolCalls <= 0 && b.MaxWallClock <= 0
+}
+
+// BudgetLimit 被突破的预算项
+type BudgetLimit string
+
+const (
+	BudgetLimitTokens    BudgetLimit = "tokens"     // Token 上限
+	BudgetLimitToolCalls BudgetLimit = "tool_calls" // 工具调用次数上限
+	BudgetLimitWallClock BudgetLimit = "wall_clock" // 运行时长上限
+)
+
+// BudgetExceededError 预算耗尽错误，携带突破时的计数
+// errors.Is(err, ErrBudgetExceeded) 为 true
+type BudgetExceededError struct {
+	// Limit 被突破的预算项
+	Limit BudgetLimit
+
+	// Budget 被突破的预算
+	Budget RunBudget
+
+	// Tokens 已使用的 Token 数
+	Tokens int
+
+	// ToolCalls 已执行的工具调用次数
+	ToolCalls int
+
+	// Elapsed 已运行时长
+	Elapsed time.Duration
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("agent: run budget exceeded (%s): tokens=%d/%d tool_calls=%d/%d elapsed=%s/%s",
+		e.Limit, e.Tokens, e.Budget.MaxTokens, e.ToolCalls, e.Budget.MaxToolCalls,
+		e.Elapsed.Round(time.Millisecond), e.Budget.MaxWallClock)
+}
+
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// WithRunBudget 设置单次运行的预算
+//
+// 所有基于 BaseAgent 的 Agent（ReAct、PlanExecute、Reflection、SelfDiscovery 及
+// BaseAgent.Invoke）在每次调用 LLM 和工具前检查预算，超出 maxTokens、maxToolCalls 或
+// maxWallClock 时中止运行并返回 *BudgetExceededError。各项 <= 0 时不限制。
+// 通过 ContextWithRunBudget 或 SwarmRunner 共享预算时，嵌套和交接的运行累计计数。
+func WithRunBudget(maxTokens, maxToolCalls int, maxWallClock time.Duration) Option {
+	return func(c *Config) {
+		c.RunBudget.MaxTokens = maxTokens
+		c.RunBudget.MaxToolCalls = maxToolCalls
+		c.RunBudget.MaxWallClock = maxWallClock
+	}
+}
+
+// WithBudgetSummary 设置预算耗尽时不返回错误，而是让模型基于已有信息做一次总结回复
+// 总结回复的输出元数据 MetadataBudgetExceeded 中记录 *BudgetExceededError。
+// 总结基于 ReAct 循环的对话，其他 Agent 仍返回 *BudgetExceededError。
+func WithBudgetSummary() Option {
+	return func(c *Config) {
+		c.RunBudget.Summarize = true
+	}
+}
+
+// ContextWithRunBudget 在 context 中设置共享预算
+//
+// 此 context 下所有 Agent 运行（包括团队成员、Agent 工具和 Swarm 交接）累计计数，
+// 运行时长从调用时开始计算。Agent 自身的预算仍然单独生效。
+func ContextWithRunBudget(ctx context.Context, budget RunBudget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, newBudgetTracker(budget, budgetFromContext(ctx)))
+}
+
+// budgetKey 预算计数器的 context key
+type budgetKey struct{}
+
+// budgetFromContext 获取 context 中的预算计数器
+func budgetFromContext(ctx context.Context) *budgetTracker {
+	t, _ := ctx.Value(budgetKey{}).(*budgetTracker)
+	return t
+}
+
+// budgetTracker 预算计数器，计数同时累加到上级计数器
+type budgetTracker struct {
+	budget RunBudget
+	start  time.Time
+	parent *budgetTracker
+
+	mu        sync.Mutex
+	tokens    int
+	toolCalls int
+}
+
+func newBudgetTracker(budget RunBudget, parent *budgetTracker) *budgetTracker {
+	return &budgetTracker{budget: budget, start: time.Now(), parent: parent}
+}
+
+// check 检查计数器及其上级是否超出预算
+func (t *budgetTracker) check() error {
+	for ; t != nil; t = t.parent {
+		t.mu.Lock()
+		limit := t.exceededLocked()
+		t.mu.Unlock()
+		if limit != "" {
+			return t.exceeded(limit)
+		}
+	}
+	return nil
+}
+
+// exceededLocked 返回已突破的预算项，未突破时为空
+func (t *budgetTracker) exceededLocked() BudgetLimit {
+	switch {
+	case t.budget.MaxTokens > 0 && t.tokens >= t.budget.MaxTokens:
+		return BudgetLimitTokens
+	case t.budget.MaxWallClock > 0 && time.Since(t.start) >= t.budget.MaxWallClock:
+		return BudgetLimitWallClock
+	}
+	return ""
+}
+
+// exceeded 构造预算耗尽错误
+func (t *budgetTracker) exceeded(limit BudgetLimit) *BudgetExceededError {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &BudgetExceededError{
+		Limit:     limit,
+		Budget:    t.budget,
+		Tokens:    t.tokens,
+		ToolCalls: t.toolCalls,
+		Elapsed:   time.Since(t.start),
+	}
+}
+
+// addTokens 累加 Token 使用
+func (t *budgetTracker) addTokens(usage llm.Usage) {
+	n := usage.TotalTokens
+	if n == 0 {
+		n = usage.PromptTokens + usage.CompletionTokens
+	}
+	for ; t != nil; t = t.parent {
+		t.mu.Lock()
+		t.tokens += n
+		t.mu.Unlock()
+	}
+}
+
+// acquireToolCall 占用一次工具调用额度，任一级计数器额度用尽时返回错误且不计数
+func (t *budgetTracker) acquireToolCall() error {
+	for c := t; c != nil; c = c.parent {
+		c.mu.Lock()
+		full := c.budget.MaxToolCalls > 0 && c.toolCalls >= c.budget.MaxToolCalls
+		c.mu.Unlock()
+		if full {
+			return c.exceeded(BudgetLimitToolCalls)
+		}
+	}
+	for c := t; c != nil; c = c.parent {
+		c.mu.Lock()
+		c.toolCalls++
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// deadline 计数器及其上级中最早的运行时长截止时间
+func (t *budgetTracker) deadline() (time.Time, bool) {
+	var deadline time.Time
+	for ; t != nil; t = t.parent {
+		if t.budget.MaxWallClock <= 0 {
+			continue
+		}
+		if d := t.start.Add(t.budget.MaxWallClock); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+	return deadline, !deadline.IsZero()
+}
+
+// errBudgetDeadline 运行时长截止时 context 的取消原因
+var errBudgetDeadline = errors.New("agent: run budget deadline")
+
+// budgetRun 单次运行的预算状态
+type budgetRun struct {
+	tracker *budgetTracker
+
+	// state 运行时最近一次观察到的状态，用于预算耗尽后的总结
+	state *agentruntime.State
+}
+
+// beginBudget 开始预算计数
+//
+// Agent 设置了预算时创建计数器（挂在 context 中的共享计数器之下），否则沿用共享计数器；
+// 都没有时返回的 run 为 nil。存在运行时长上限时，返回的 context 在截止时取消。
+func (a *BaseAgent) beginBudget(ctx context.Context) (context.Context, *budgetRun, context.CancelFunc) {
+	tracker := budgetFromContext(ctx)
+	if !a.config.RunBudget.IsZero() {
+		tracker = newBudgetTracker(a.config.RunBudget, tracker)
+		ctx = context.WithValue(ctx, budgetKey{}, tracker)
+	}
+	if tracker == nil {
+		return ctx, nil, func() {}
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if deadline, ok := tracker.deadline(); ok {
+		ctx, cancel = context.WithDeadlineCause(ctx, deadline, errBudgetDeadline)
+	}
+	return ctx, &budgetRun{tracker: tracker}, cancel
+}
+
+// middleware 返回执行预算检查的运行时中间件
+func (r *budgetRun) middleware() []agentruntime.Middleware {
+	if r == nil {
+		return nil
+	}
+	return []agentruntime.Middleware{agentruntime.MiddlewareFuncSet{
+		BeforeLLMFunc: func(ctx context.Context, state *agentruntime.State) error {
+			r.state = state
+			return r.tracker.check()
+		},
+		AfterLLMFunc: func(ctx context.Context, state *agentruntime.State, resp *llm.CompletionResponse) error {
+			r.tracker.addTokens(resp.Usage)
+			return nil
+		},
+		BeforeToolFunc: func(ctx context.Context, state *agentruntime.State, call llm.ToolCall) error {
+			r.state = state
+			if err := r.tracker.check(); err != nil {
+				return err
+			}
+			return r.tracker.acquireToolCall()
+		},
+	}}
+}
+
+// exceeded 将运行错误转换为预算耗尽错误，与预算无关时返回 nil
+func (r *budgetRun) exceeded(ctx context.Context, err error) *BudgetExceededError {
+	if r == nil || err == nil {
+		return nil
+	}
+	var budgetErr *BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		return budgetErr
+	}
+	if errors.Is(context.Cause(ctx), errBudgetDeadline) {
+		for t := r.tracker; t != nil; t = t.parent {
+			if t.budget.MaxWallClock > 0 && time.Since(t.start) >= t.budget.MaxWallClock {
+				return t.exceeded(BudgetLimitWallClock)
+			}
+		}
+	}
+	return nil
+}
+
+// err 将与预算有关的运行错误转换为 *BudgetExceededError，其他错误原样返回
+func (r *budgetRun) err(ctx context.Context, err error) error {
+	if budgetErr := r.exceeded(ctx, err); budgetErr != nil {
+		return budgetErr
+	}
+	return err
+}
+
+// summarizeOnBudget 预算耗尽后基于已有对话让模型给出最终回复
+// 总结调用不受预算限制，但 Token 使用仍计入计数器
+func (a *BaseAgent) summarizeOnBudget(ctx context.Context, run *budgetRun, budgetErr *BudgetExceededError) (Output, error) {
+	if run.state == nil {
+		return Output{}, budgetErr
+	}
+	// 总结调用不检查预算，Token 使用在下面手动计入
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), budgetSummaryTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, budgetKey{}, (*budgetTracker)(nil))
+
+	messages := append([]llm.Message(nil), run.state.Messages...)
+	messages = append(messages, llm.Message{
+		Role: llm.RoleUser,
+		Content: "You have run out of budget and cannot call any more tools. " +
+			"Give your best final answer using only the information gathered so far.",
+	})
+	resp, err := completeLLM(ctx, a.config.LLM, llm.CompletionRequest{Messages: messages})
+	if err != nil {
+		return Output{}, fmt.Errorf("%w (summary failed: %v)", budgetErr, err)
+	}
+	run.tracker.addTokens(resp.Usage)
+
+	output := outputFromRuntime(agentruntimeResultFromState(run.state))
+	output.Content = resp.Content
+	output.Usage = mergeUsage(output.Usage, resp.Usage)
+	metadata := make(map[string]any, len(output.Metadata)+1)
+	for k, v := range output.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataBudgetExceeded] = budgetErr
+	output.Metadata = metadata
+	return output, nil
+}
@@ -0,0 +1,213 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/tool"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// loopingLLM 每次都请求调用 search 工具，不带工具的请求返回总结
+func loopingLLM(name string, tokens int) *mock.LLMProvider {
+	return mock.NewLLMProvider(name).WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		if len(req.Tools) == 0 {
+			return &llm.CompletionResponse{Content: "summary", Usage: llm.Usage{TotalTokens: 10}}, nil
+		}
+		return &llm.CompletionResponse{
+			ToolCalls: []llm.ToolCall{{ID: "call", Name: "search", Arguments: `{}`}},
+			Usage:     llm.Usage{TotalTokens: tokens},
+		}, nil
+	})
+}
+
+func TestRunBudget_ToolCalls(t *testing.T) {
+	search := mock.NewTool("search")
+	a := NewReAct(
+		WithLLM(loopingLLM("budget", 10)),
+		WithTools(search),
+		WithMaxIterations(20),
+		WithRunBudget(0, 2, 0),
+	)
+
+	_, err := a.Run(context.Background(), Input{Query: "loop"})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	var budgetErr *BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("expected *BudgetExceededError, got %T", err)
+	}
+	if budgetErr.Limit != BudgetLimitToolCalls || budgetErr.ToolCalls != 2 {
+		t.Errorf("unexpected counters: %+v", budgetErr)
+	}
+	if search.CallCount() != 2 {
+		t.Errorf("expected 2 tool executions, got %d", search.CallCount())
+	}
+}
+
+func TestRunBudget_Tokens(t *testing.T) {
+	a := NewReAct(
+		WithLLM(loopingLLM("budget", 60)),
+		WithTools(mock.NewTool("search")),
+		WithMaxIterations(20),
+		WithRunBudget(100, 0, 0),
+	)
+
+	_, err := a.Run(context.Background(), Input{Query: "loop"})
+	var budgetErr *BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("expected *BudgetExceededError, got %v", err)
+	}
+	if budgetErr.Limit != BudgetLimitTokens || budgetErr.Tokens != 120 {
+		t.Errorf("unexpected counters: %+v", budgetErr)
+	}
+}
+
+func TestRunBudget_WallClock(t *testing.T) {
+	slow := mock.NewTool("search", mock.WithToolExecuteFn(func(ctx context.Context, args map[string]any) (tool.Result, error) {
+		select {
+		case <-ctx.Done():
+			return tool.Result{}, ctx.Err()
+		case <-time.After(time.Second):
+			return tool.NewResult("late"), nil
+		}
+	}))
+	a := NewReAct(
+		WithLLM(loopingLLM("budget", 10)),
+		WithTools(slow),
+		WithRunBudget(0, 0, 50*time.Millisecond),
+	)
+
+	_, err := a.Run(context.Background(), Input{Query: "loop"})
+	var budgetErr *BudgetExceededError
+	if !errors.As(err, &budgetErr) || budgetErr.Limit != BudgetLimitWallClock {
+		t.Fatalf("expected wall clock budget error, got %v", err)
+	}
+}
+
+func TestRunBudget_Summarize(t *testing.T) {
+	provider := loopingLLM("budget", 10)
+	a := NewReAct(
+		WithLLM(provider),
+		WithTools(mock.NewTool("search")),
+		WithMaxIterations(20),
+		WithRunBudget(0, 1, 0),
+		WithBudgetSummary(),
+	)
+
+	output, err := a.Run(context.Background(), Input{Query: "loop"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Content != "summary" {
+		t.Errorf("expected summary content, got %q", output.Content)
+	}
+	if _, ok := output.Metadata[MetadataBudgetExceeded].(*BudgetExceededError); !ok {
+		t.Errorf("expected budget error in metadata, got %v", output.Metadata)
+	}
+	if len(output.ToolCalls) != 1 {
+		t.Errorf("expected the executed tool call to be kept, got %d", len(output.ToolCalls))
+	}
+	if len(provider.LastCall().Tools) != 0 {
+		t.Error("summary call must not offer tools")
+	}
+}
+
+func TestRunBudget_SharedAcrossRuns(t *testing.T) {
+	a := NewReAct(
+		WithLLM(mock.NewLLMProvider("budget").
+			AddToolCallResponse([]llm.ToolCall{{ID: "1", Name: "search", Arguments: `{}`}}).
+			AddResponse("first").
+			AddToolCallResponse([]llm.ToolCall{{ID: "2", Name: "search", Arguments: `{}`}}).
+			AddResponse("second")),
+		WithTools(mock.NewTool("search")),
+	)
+
+	ctx := ContextWithRunBudget(context.Background(), RunBudget{MaxToolCalls: 1})
+	if _, err := a.Run(ctx, Input{Query: "one"}); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if _, err := a.Run(ctx, Input{Query: "two"}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected shared budget to be exhausted, got %v", err)
+	}
+}
+
+func TestSwarmRunner_Budget(t *testing.T) {
+	second := NewReAct(
+		WithName("second"),
+		WithLLM(mock.NewLLMProvider("second").
+			AddToolCallResponse([]llm.ToolCall{{ID: "2", Name: "search", Arguments: `{}`}}).
+			AddResponse("done")),
+		WithTools(mock.NewTool("search")),
+	)
+	first := NewReAct(
+		WithName("first"),
+		WithLLM(mock.NewLLMProvider("first").
+			AddToolCallResponse([]llm.ToolCall{{ID: "1", Name: "transfer_to_second", Arguments: `{"reason":"next"}`}}).
+			AddResponse("handing off")),
+		WithTools(TransferTo(second)),
+		WithRunBudget(0, 1, 0),
+	)
+
+	_, err := NewSwarmRunner(first).Run(context.Background(), Input{Query: "go"})
+	var budgetErr *BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("expected budget to accumulate across handoffs, got %v", err)
+	}
+	if budgetErr.ToolCalls != 1 {
+		t.Errorf("expected 1 counted tool call, got %d", budgetErr.ToolCalls)
+	}
+}
+
+func TestRunBudget_NonReActAgents(t *testing.T) {
+	newLLM := func() *mock.LLMProvider {
+		return mock.NewLLMProvider("budget").WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+			return &llm.CompletionResponse{Content: "draft", Usage: llm.Usage{TotalTokens: 60}}, nil
+		})
+	}
+	tests := []struct {
+		name  string
+		agent func(provider llm.Provider) Agent
+	}{
+		{"reflection", func(p llm.Provider) Agent {
+			return NewReflection([]Option{WithLLM(p), WithRunBudget(100, 0, 0)},
+				WithReflectionMaxIterations(5), WithReflectionMinIterations(5))
+		}},
+		{"self discovery", func(p llm.Provider) Agent {
+			return NewSelfDiscovery([]Option{WithLLM(p), WithRunBudget(100, 0, 0)})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newLLM()
+			_, err := tt.agent(provider).Run(context.Background(), Input{Query: "task"})
+			if !errors.Is(err, ErrBudgetExceeded) {
+				t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+			}
+			if provider.CallCount() != 2 {
+				t.Errorf("expected the run to stop after 2 LLM calls, got %d", provider.CallCount())
+			}
+		})
+	}
+}
+
+func TestRunBudget_SharedWithBaseAgent(t *testing.T) {
+	provider := mock.NewLLMProvider("budget").WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		return &llm.CompletionResponse{Content: "ok", Usage: llm.Usage{TotalTokens: 60}}, nil
+	})
+	a := NewBaseAgent(WithLLM(provider))
+	ctx := ContextWithRunBudget(context.Background(), RunBudget{MaxTokens: 100})
+
+	for i := range 2 {
+		if _, err := a.Invoke(ctx, Input{Query: "hi"}); err != nil {
+			t.Fatalf("run %d failed: %v", i, err)
+		}
+	}
+	if _, err := a.Invoke(ctx, Input{Query: "hi"}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected shared budget to stop the third run, got %v", err)
+	}
+}
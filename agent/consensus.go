@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/internal/util"
 )
 
@@ -358,7 +359,7 @@ func (p *ConsensusProtocol) collectVotes(ctx context.Context, poll *Poll) ([]Vot
 func (p *ConsensusProtocol) requestVote(ctx context.Context, poll *Poll, agentID string) (*Vote, error) {
 	agent, ok := p.network.GetAgent(agentID)
 	if !ok {
-		return nil, fmt.Errorf("agent %s not found", agentID)
+		return nil, fmt.Errorf("agent %s %w", agentID, core.ErrNotFound)
 	}
 
 	// 构建投票请求
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ContextProvider 在每次运行开始时生成注入系统提示词的动态上下文
+//
+// 返回空字符串表示本次不注入；返回错误时运行失败。
+type ContextProvider func(ctx context.Context) (string, error)
+
+// WithContextProvider 添加动态上下文提供者
+//
+// 每次运行开始时调用 provider，结果加在系统提示词之前，
+// 用于注入当前日期、用户信息、租户配置等随请求变化的内容，无需为每个请求重新创建 Agent。
+// 多个提供者按添加顺序拼接，注入后的系统提示词可在 LLMStartEvent.Messages 中查看。
+//
+// 示例：
+//
+//	agent := NewReAct(
+//	    WithLLM(provider),
+//	    WithContextProvider(func(ctx context.Context) (string, error) {
+//	        return "当前日期：" + time.Now().Format("2006-01-02"), nil
+//	    }),
+//	)
+func WithContextProvider(provider ContextProvider) Option {
+	return func(c *Config) {
+		c.ContextProviders = append(c.ContextProviders, provider)
+	}
+}
+
+// WithContextVariables 将 context 中的上下文变量注入系统提示词
+//
+// 每次运行开始时从 VariablesFromContext 和 SafeVariablesFromContext 读取 keys 对应的变量，
+// 以 "key: value" 逐行注入；未指定 keys 时按键名顺序注入全部变量，缺失的变量跳过。
+//
+// 示例：
+//
+//	agent := NewReAct(WithLLM(provider), WithContextVariables("user_name", "tenant"))
+//	ctx = ContextWithVariables(ctx, ContextVariables{"user_name": "Alice", "tenant": "acme"})
+//	agent.Run(ctx, Input{Query: "..."})
+func WithContextVariables(keys ...string) Option {
+	return WithContextProvider(func(ctx context.Context) (string, error) {
+		return formatContextVariables(contextVariables(ctx), keys), nil
+	})
+}
+
+// contextVariables 合并 context 中的普通和线程安全上下文变量，后者优先
+func contextVariables(ctx context.Context) ContextVariables {
+	vars := make(ContextVariables)
+	vars.Merge(VariablesFromContext(ctx))
+	if safe := SafeVariablesFromContext(ctx); safe != nil {
+		vars.Merge(safe.Clone())
+	}
+	return vars
+}
+
+// formatContextVariables 将变量格式化为 "key: value" 行
+func formatContextVariables(vars ContextVariables, keys []string) string {
+	if len(keys) == 0 {
+		keys = make([]string, 0, len(vars))
+		for k := range vars {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+	}
+
+	var sb strings.Builder
+	for _, k := range keys {
+		v, ok := vars[k]
+		if !ok {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		fmt.Fprintf(&sb, "%s: %v", k, v)
+	}
+	return sb.String()
+}
+
+// ContextWithInstructions 在 context 中设置本次运行附加的系统指令
+//
+// 指令追加在系统提示词（含动态上下文）之后，用于由调用方逐请求补充的要求，
+// 如 OpenAI 兼容接口中客户端发送的 system 消息；多次设置时以最内层为准。
+// 对使用系统提示词的 Agent 生效（ReActAgent、BaseAgent、ReflectionAgent、SelfDiscoveryAgent）。
+func ContextWithInstructions(ctx context.Context, instructions string) context.Context {
+	return context.WithValue(ctx, instructionsKey{}, instructions)
+}
+
+// instructionsKey 附加系统指令的 context key
+type instructionsKey struct{}
+
+// injectContext 将上下文提供者的结果加在系统提示词之前，将附加指令加在之后
+func (a *BaseAgent) injectContext(ctx context.Context, systemPrompt string) (string, error) {
+	instructions, _ := ctx.Value(instructionsKey{}).(string)
+	instructions = strings.TrimSpace(instructions)
+	if len(a.config.ContextProviders) == 0 && instructions == "" {
+		return systemPrompt, nil
+	}
+
+	parts := make([]string, 0, len(a.config.ContextProviders)+2)
+	for _, provider := range a.config.ContextProviders {
+		text, err := provider(ctx)
+		if err != nil {
+			return "", fmt.Errorf("context provider: %w", err)
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if systemPrompt != "" {
+		parts = append(parts, systemPrompt)
+	}
+	if instructions != "" {
+		parts = append(parts, instructions)
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
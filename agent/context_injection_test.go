@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// llmStartRecorder 记录 LLM 开始事件的钩子
+type llmStartRecorder struct {
+	fallbackRecorder
+	starts []*hooks.LLMStartEvent
+}
+
+func (h *llmStartRecorder) OnLLMStart(_ context.Context, e *hooks.LLMStartEvent) error {
+	h.starts = append(h.starts, e)
+	return nil
+}
+
+func TestWithContextProvider(t *testing.T) {
+	mockLLM := mock.NewLLMProvider("ctx")
+	mockLLM.AddResponse("ok").AddResponse("ok")
+
+	calls := 0
+	a := NewReAct(
+		WithLLM(mockLLM),
+		WithSystemPrompt("You are helpful."),
+		WithContextProvider(func(ctx context.Context) (string, error) {
+			calls++
+			return "Today is 2026-01-01.", nil
+		}),
+		WithContextVariables("user_name", "tenant"),
+	)
+
+	recorder := &llmStartRecorder{}
+	manager := hooks.NewManager()
+	manager.RegisterLLMHook(recorder)
+	ctx := hooks.ContextWithManager(context.Background(), manager)
+	ctx = ContextWithVariables(ctx, ContextVariables{"user_name": "Alice", "tenant": "acme", "other": 1})
+
+	if _, err := a.Run(ctx, Input{Query: "hi"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	want := "Today is 2026-01-01.\n\nuser_name: Alice\ntenant: acme\n\nYou are helpful."
+	if got := mockLLM.LastCall().Messages[0].Content; got != want {
+		t.Errorf("unexpected system prompt: %q", got)
+	}
+
+	// 注入的内容在 LLMStartEvent 中可见
+	if len(recorder.starts) != 1 {
+		t.Fatalf("expected 1 LLM start event, got %d", len(recorder.starts))
+	}
+	if msg, ok := recorder.starts[0].Messages[0].(llm.Message); !ok || msg.Content != want {
+		t.Errorf("expected injected prompt in LLMStartEvent, got %+v", recorder.starts[0].Messages[0])
+	}
+
+	// 每次运行重新求值，缺失的变量跳过
+	if _, err := a.Run(context.Background(), Input{Query: "hi"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected provider to run once per Run, got %d", calls)
+	}
+	if got := mockLLM.LastCall().Messages[0].Content; got != "Today is 2026-01-01.\n\nYou are helpful." {
+		t.Errorf("unexpected system prompt without variables: %q", got)
+	}
+}
+
+func TestWithContextProvider_Error(t *testing.T) {
+	errTenant := errors.New("tenant lookup failed")
+	mockLLM := mock.NewLLMProvider("ctx")
+	a := NewReAct(WithLLM(mockLLM), WithContextProvider(func(context.Context) (string, error) {
+		return "", errTenant
+	}))
+
+	if _, err := a.Run(context.Background(), Input{Query: "hi"}); !errors.Is(err, errTenant) {
+		t.Errorf("expected provider error, got %v", err)
+	}
+	if mockLLM.CallCount() != 0 {
+		t.Errorf("expected no LLM call on provider error, got %d calls", mockLLM.CallCount())
+	}
+}
+
+func TestContextWithInstructions(t *testing.T) {
+	mockLLM := mock.NewLLMProvider("ctx").AddResponse("ok").AddResponse("ok")
+	a := NewReAct(WithLLM(mockLLM), WithSystemPrompt("You are helpful."))
+
+	ctx := ContextWithInstructions(context.Background(), "Answer in French.")
+	if _, err := a.Run(ctx, Input{Query: "hi"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got := mockLLM.LastCall().Messages[0].Content; got != "You are helpful.\n\nAnswer in French." {
+		t.Errorf("unexpected system prompt: %q", got)
+	}
+
+	if _, err := a.Run(context.Background(), Input{Query: "hi"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got := mockLLM.LastCall().Messages[0].Content; got != "You are helpful." {
+		t.Errorf("instructions should apply to one run only, got %q", got)
+	}
+}
+
+func TestWithContextVariables_All(t *testing.T) {
+	safe := NewSafeContextVariables()
+	safe.Set("b", 2)
+	ctx := ContextWithSafeVariables(context.Background(), safe)
+	ctx = ContextWithVariables(ctx, ContextVariables{"a": 1, "b": 1})
+
+	if got := formatContextVariables(contextVariables(ctx), nil); got != "a: 1\nb: 2" {
+		t.Errorf("unexpected variables: %q", got)
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/llm/tokenizer"
 )
 
 // ============== 对话消息 ==============
@@ -49,6 +51,7 @@ type ConversationAgent struct {
 	messages  []ConvMessage
 	maxTurns  int // 最大轮次（0 不限）
 	maxTokens int // Token 预算上限（0 不限）
+	counter   tokenizer.Counter
 	mu        sync.RWMutex
 }
 
@@ -69,6 +72,13 @@ func WithConvMaxTokens(n int) ConversationOption {
 	}
 }
 
+// WithConvTokenCounter 设置 Token 计数器，默认使用 tokenizer.Default()
+func WithConvTokenCounter(counter tokenizer.Counter) ConversationOption {
+	return func(c *ConversationAgent) {
+		c.counter = counter
+	}
+}
+
 // NewConversation 创建多轮对话 Agent
 func NewConversation(agent Agent, opts ...ConversationOption) *ConversationAgent {
 	c := &ConversationAgent{
@@ -138,7 +148,7 @@ func (c *ConversationAgent) Chat(ctx context.Context, userMessage string) (Outpu
 // buildContext 构建对话上下文字符串（需持锁调用）
 //
 // 从最近的消息向前填充，确保不超过 Token 预算。
-// Token 估算：每 4 个字符约 1 个 Token。
+// Token 数由配置的计数器计算（默认 tokenizer.Default()）。
 func (c *ConversationAgent) buildContext() string {
 	msgs := c.messages
 
@@ -162,13 +172,11 @@ func (c *ConversationAgent) buildContext() string {
 
 	// 应用 Token 预算
 	if c.maxTokens > 0 {
+		counter := tokenizer.OrDefault(c.counter)
 		usedTokens := 0
 		startIdx := len(msgs)
 		for i := len(msgs) - 1; i >= 0; i-- {
-			tokens := len(msgs[i].Content) / 4
-			if tokens == 0 {
-				tokens = 1
-			}
+			tokens := max(counter.Count(msgs[i].Content), 1)
 			if usedTokens+tokens > c.maxTokens {
 				break
 			}
@@ -116,7 +116,7 @@ func (d *DeepAgent) runAtDepth(ctx context.Context, input Input, depth int) (Out
 
 	agentLLM := d.agent.LLM()
 	if agentLLM == nil {
-		return Output{}, fmt.Errorf("DeepAgent %q: LLM not configured", d.config.Name)
+		return Output{}, fmt.Errorf("DeepAgent %q: LLM %w", d.config.Name, core.ErrNotConfigured)
 	}
 
 	// 构建工具定义
@@ -135,7 +135,7 @@ func (d *DeepAgent) runAtDepth(ctx context.Context, input Input, depth int) (Out
 			return Output{}, ctx.Err()
 		}
 
-		resp, err := agentLLM.Complete(ctx, llm.CompletionRequest{
+		resp, err := completeLLM(ctx, agentLLM, llm.CompletionRequest{
 			Messages: messages,
 			Tools:    toolDefs,
 		})
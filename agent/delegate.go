@@ -0,0 +1,161 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/tool"
+	hexatool "github.com/hexagon-codes/hexagon/tool"
+)
+
+// ============== 委派工具 ==============
+
+// DefaultMaxDelegationDepth 默认的最大委派深度
+const DefaultMaxDelegationDepth = 3
+
+// ErrDelegationDepthExceeded 委派嵌套层数超出上限
+var ErrDelegationDepthExceeded = errors.New("agent: delegation depth exceeded")
+
+// DelegateOption 委派工具配置选项
+type DelegateOption func(*delegateTool)
+
+// WithMaxDelegationDepth 设置最大委派深度，默认 3
+//
+// 深度按 context 逐层累计：父 Agent 委派给子 Agent 为第 1 层，
+// 子 Agent 再委派为第 2 层，依此类推。超出上限时工具不执行子 Agent，
+// 而是返回失败结果，由模型自行完成任务，避免 Agent 互相委派形成无限递归。
+func WithMaxDelegationDepth(n int) DelegateOption {
+	return func(t *delegateTool) {
+		if n > 0 {
+			t.maxDepth = n
+		}
+	}
+}
+
+// WithDelegationBudget 设置每次委派的运行预算
+//
+// 子 Agent 的 Token、工具调用和运行时长同时计入父运行的预算，
+// 因此父运行设置的预算也会限制委派的总开销。各项 <= 0 时不限制。
+func WithDelegationBudget(maxTokens, maxToolCalls int, maxWallClock time.Duration) DelegateOption {
+	return func(t *delegateTool) {
+		t.budget = RunBudget{MaxTokens: maxTokens, MaxToolCalls: maxToolCalls, MaxWallClock: maxWallClock}
+	}
+}
+
+// DelegateTool 将子 Agent 包装为委派工具
+//
+// 模型调用工具时以 query 参数运行子 Agent，并将其输出作为工具结果返回。
+// 与 TransferTo 的交接不同，委派后控制权仍在父 Agent，适合把有边界的子任务
+// 交给专门的 Agent 处理后继续推理。子 Agent 出错或超出委派深度时返回失败结果
+// 而不是错误，父 Agent 的运行不会中断。
+//
+// name 为空时使用 delegate_<子 Agent 名称>，description 为空时使用子 Agent 的描述。
+//
+// 示例：
+//
+//	researcher := agent.NewReAct(agent.WithName("researcher"), agent.WithTools(search))
+//	lead := agent.NewReAct(
+//	    agent.WithTools(agent.DelegateTool(researcher, "research", "Research a topic and summarize findings",
+//	        agent.WithDelegationBudget(20000, 10, 2*time.Minute),
+//	    )),
+//	)
+func DelegateTool(subAgent Agent, name, description string, opts ...DelegateOption) tool.Tool {
+	t := &delegateTool{
+		agent:       subAgent,
+		name:        name,
+		description: description,
+		maxDepth:    DefaultMaxDelegationDepth,
+	}
+	if t.name == "" {
+		t.name = "delegate_" + subAgent.Name()
+	}
+	if t.description == "" {
+		t.description = subAgent.Description()
+	}
+	if t.description == "" {
+		t.description = fmt.Sprintf("Delegate a subtask to agent %q and return its answer", subAgent.Name())
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// DelegateToolInput 委派工具的输入参数
+type DelegateToolInput struct {
+	// Query 交给子 Agent 的任务描述
+	Query string `json:"query" desc:"Self-contained description of the subtask for the agent" required:"true"`
+
+	// Context 额外上下文
+	Context map[string]any `json:"context,omitempty" desc:"Additional context to pass to the agent"`
+}
+
+// delegateTool 委派工具的内部实现
+type delegateTool struct {
+	agent       Agent
+	name        string
+	description string
+	maxDepth    int
+	budget      RunBudget
+}
+
+func (t *delegateTool) Name() string { return t.name }
+
+func (t *delegateTool) Description() string { return t.description }
+
+func (t *delegateTool) Schema() *llm.Schema {
+	return llm.SchemaOf[DelegateToolInput]()
+}
+
+func (t *delegateTool) Validate(args map[string]any) error {
+	if query, _ := args["query"].(string); query == "" {
+		return fmt.Errorf("query is required")
+	}
+	return nil
+}
+
+// Cacheable 委派每次都会重新运行子 Agent，结果不进入工具缓存
+func (t *delegateTool) Cacheable() bool { return false }
+
+func (t *delegateTool) Execute(ctx context.Context, args map[string]any) (tool.Result, error) {
+	query, _ := args["query"].(string)
+	agentCtx, _ := args["context"].(map[string]any)
+
+	depth := DelegationDepth(ctx) + 1
+	if depth > t.maxDepth {
+		err := fmt.Errorf("%w: depth %d exceeds limit %d", ErrDelegationDepthExceeded, depth, t.maxDepth)
+		return tool.Result{Success: false, Error: fmt.Sprintf("%v; complete the task yourself", err)}, nil
+	}
+
+	ctx = context.WithValue(ctx, delegationDepthKey{}, depth)
+	if !t.budget.IsZero() {
+		ctx = ContextWithRunBudget(ctx, t.budget)
+	}
+
+	output, err := t.agent.Run(ctx, Input{Query: query, Context: agentCtx})
+	if err != nil {
+		return tool.Result{
+			Success: false,
+			Error:   fmt.Sprintf("agent %q failed: %v", t.agent.Name(), err),
+		}, nil
+	}
+	return tool.Result{Success: true, Output: output.Content}, nil
+}
+
+// delegationDepthKey 委派深度的 context key
+type delegationDepthKey struct{}
+
+// DelegationDepth 返回 ctx 所处的委派深度，不在委派中时为 0
+func DelegationDepth(ctx context.Context) int {
+	depth, _ := ctx.Value(delegationDepthKey{}).(int)
+	return depth
+}
+
+// 确保实现了 Tool 和 Cacheable 接口
+var (
+	_ tool.Tool          = (*delegateTool)(nil)
+	_ hexatool.Cacheable = (*delegateTool)(nil)
+)
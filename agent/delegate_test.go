@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	hexatool "github.com/hexagon-codes/hexagon/tool"
+)
+
+func TestDelegateTool_Execute(t *testing.T) {
+	var gotDepth int
+	sub := newMockAgent("researcher", func(ctx context.Context, input Input) (Output, error) {
+		gotDepth = DelegationDepth(ctx)
+		return Output{Content: "found: " + input.Query}, nil
+	})
+
+	dt := DelegateTool(sub, "", "")
+	if dt.Name() != "delegate_researcher" {
+		t.Fatalf("unexpected name %q", dt.Name())
+	}
+	if dt.Description() != sub.Description() {
+		t.Fatalf("unexpected description %q", dt.Description())
+	}
+	if hexatool.IsCacheable(dt) {
+		t.Fatal("delegate tool must not be cacheable")
+	}
+	if err := dt.Validate(map[string]any{}); err == nil {
+		t.Fatal("expected validation error for missing query")
+	}
+
+	result, err := dt.Execute(context.Background(), map[string]any{"query": "go generics"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || result.Output != "found: go generics" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if gotDepth != 1 {
+		t.Fatalf("expected depth 1 inside sub-agent, got %d", gotDepth)
+	}
+}
+
+func TestDelegateTool_DepthLimit(t *testing.T) {
+	// 子 Agent 无条件再次委派给自己，深度上限应终止递归
+	var runs int
+	self := newMockAgent("loop", nil)
+	delegate := DelegateTool(self, "loop", "", WithMaxDelegationDepth(2))
+	self.runFunc = func(ctx context.Context, input Input) (Output, error) {
+		runs++
+		result, err := delegate.Execute(ctx, map[string]any{"query": input.Query})
+		if err != nil {
+			return Output{}, err
+		}
+		if !result.Success {
+			return Output{Content: result.Error}, nil
+		}
+		return Output{Content: result.Output.(string)}, nil
+	}
+
+	result, err := delegate.Execute(context.Background(), map[string]any{"query": "again"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 2 {
+		t.Fatalf("expected 2 nested runs, got %d", runs)
+	}
+	if !result.Success || !strings.Contains(result.Output.(string), ErrDelegationDepthExceeded.Error()) {
+		t.Fatalf("expected depth error to surface as observation, got %+v", result)
+	}
+}
+
+func TestDelegateTool_Budget(t *testing.T) {
+	var gotBudget *budgetTracker
+	sub := newMockAgent("worker", func(ctx context.Context, input Input) (Output, error) {
+		gotBudget = budgetFromContext(ctx)
+		return Output{}, errors.New("boom")
+	})
+
+	parent := ContextWithRunBudget(context.Background(), RunBudget{MaxToolCalls: 5})
+	dt := DelegateTool(sub, "work", "do work", WithDelegationBudget(0, 2, 0))
+	result, err := dt.Execute(parent, map[string]any{"query": "task"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success || !strings.Contains(result.Error, "boom") {
+		t.Fatalf("expected failed result carrying agent error, got %+v", result)
+	}
+	if gotBudget == nil || gotBudget.budget.MaxToolCalls != 2 {
+		t.Fatal("expected delegation budget in sub-agent context")
+	}
+	if gotBudget.parent != budgetFromContext(parent) {
+		t.Fatal("expected delegation budget to count against the parent budget")
+	}
+}
@@ -0,0 +1,254 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/llm/cache"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+	agentruntime "github.com/hexagon-codes/hexagon/runtime"
+)
+
+// exactCacheKeyPrefix 精确缓存键前缀
+const exactCacheKeyPrefix = "agent_exact:"
+
+// ExactCache 按完整请求精确匹配的 LLM 响应缓存
+//
+// 缓存键为规范化请求的 SHA-256：Provider 名称、模型、全部消息、工具定义、
+// ToolChoice 以及采样参数（MaxTokens、Temperature、TopP、Stop、ResponseFormat、随机种子）。
+// 任何一项不同都不会命中，适合 temperature 为 0 等确定性调用，
+// 同一 Agent 对同一输入重复运行时直接复用回复，不再调用模型。
+//
+// 缓存粒度为单次 LLM 调用，而非整次运行：
+//   - 工具定义属于缓存键，增减工具不会命中旧缓存
+//   - 含工具调用的响应同样被缓存，但工具本身仍会真实执行（保留副作用）
+//   - 工具结果会进入下一轮请求的消息，结果不同时后续调用自然不命中
+//
+// 记忆中的历史消息同样属于缓存键，对话历史变化后相同的问题不会命中。
+//
+// 与 SemanticCache 不同，ExactCache 不区分 Agent：完全相同的请求在 Agent 间共享回复。
+// 仅缓存非流式调用（Complete），Stream 调用直接透传。
+type ExactCache struct {
+	store cache.Cache
+	ttl   time.Duration
+
+	hits        atomic.Int64
+	misses      atomic.Int64
+	tokensSaved atomic.Int64
+}
+
+// ExactCacheOption ExactCache 配置选项
+type ExactCacheOption func(*ExactCache)
+
+// WithExactCacheStore 设置缓存存储（如 Redis 实现的 cache.Cache）
+// 默认使用 cache.NewMemoryCache(cache.DefaultCacheConfig())
+func WithExactCacheStore(store cache.Cache) ExactCacheOption {
+	return func(c *ExactCache) {
+		if store != nil {
+			c.store = store
+		}
+	}
+}
+
+// WithExactCacheTTL 设置缓存有效期，<= 0 时使用存储自身的过期策略
+func WithExactCacheTTL(ttl time.Duration) ExactCacheOption {
+	return func(c *ExactCache) {
+		c.ttl = ttl
+	}
+}
+
+// NewExactCache 创建精确缓存
+func NewExactCache(opts ...ExactCacheOption) *ExactCache {
+	c := &ExactCache{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.store == nil {
+		c.store = cache.NewMemoryCache(cache.DefaultCacheConfig())
+	}
+	return c
+}
+
+// ExactCacheStats 精确缓存统计
+type ExactCacheStats struct {
+	// Hits 命中次数
+	Hits int64 `json:"hits"`
+
+	// Misses 未命中次数
+	Misses int64 `json:"misses"`
+
+	// HitRate 命中率
+	HitRate float64 `json:"hit_rate"`
+
+	// TokensSaved 命中节省的 token 数
+	TokensSaved int64 `json:"tokens_saved"`
+}
+
+// Stats 返回缓存统计
+func (c *ExactCache) Stats() ExactCacheStats {
+	hits, misses := c.hits.Load(), c.misses.Load()
+	stats := ExactCacheStats{Hits: hits, Misses: misses, TokensSaved: c.tokensSaved.Load()}
+	if total := hits + misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	return stats
+}
+
+// Clear 清空缓存
+func (c *ExactCache) Clear(ctx context.Context) error {
+	return c.store.Clear(ctx)
+}
+
+// Wrap 为 Provider 加上精确缓存
+func (c *ExactCache) Wrap(p llm.Provider) llm.Provider {
+	return &exactCacheProvider{Provider: p, cache: c}
+}
+
+// exactCacheRequest 参与缓存键计算的请求字段
+type exactCacheRequest struct {
+	Provider       string               `json:"provider"`
+	Model          string               `json:"model"`
+	Messages       []llm.Message        `json:"messages"`
+	Tools          []llm.ToolDefinition `json:"tools,omitempty"`
+	ToolChoice     any                  `json:"tool_choice,omitempty"`
+	MaxTokens      int                  `json:"max_tokens,omitempty"`
+	Temperature    *float64             `json:"temperature,omitempty"`
+	TopP           *float64             `json:"top_p,omitempty"`
+	Stop           []string             `json:"stop,omitempty"`
+	ResponseFormat *llm.ResponseFormat  `json:"response_format,omitempty"`
+	Seed           any                  `json:"seed,omitempty"`
+}
+
+// key 计算请求的缓存键
+// Metadata 和 User 只用于追踪，不影响生成结果，不参与计算；元数据中的随机种子除外
+func (c *ExactCache) key(provider string, req llm.CompletionRequest) (string, error) {
+	data, err := json.Marshal(exactCacheRequest{
+		Provider:       provider,
+		Model:          req.Model,
+		Messages:       req.Messages,
+		Tools:          req.Tools,
+		ToolChoice:     req.ToolChoice,
+		MaxTokens:      req.MaxTokens,
+		Temperature:    req.Temperature,
+		TopP:           req.TopP,
+		Stop:           req.Stop,
+		ResponseFormat: req.ResponseFormat,
+		Seed:           req.Metadata[agentruntime.MetadataSeed],
+	})
+	if err != nil {
+		return "", fmt.Errorf("exact cache: marshal request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return exactCacheKeyPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// lookup 查找缓存的响应，存储故障视为未命中
+func (c *ExactCache) lookup(ctx context.Context, key string) (*llm.CompletionResponse, bool) {
+	entry, err := c.store.Get(ctx, key)
+	if err != nil || entry == nil {
+		if err != nil && !errors.Is(err, cache.ErrCacheMiss) && !errors.Is(err, cache.ErrCacheExpired) {
+			logger.FromContext(ctx).WarnContext(ctx, "exact cache lookup failed", logger.Err(err))
+		}
+		c.misses.Add(1)
+		return nil, false
+	}
+	var resp llm.CompletionResponse
+	if err := json.Unmarshal(entry.Response, &resp); err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "exact cache entry corrupted", logger.Err(err))
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	c.tokensSaved.Add(int64(resp.Usage.TotalTokens))
+	return &resp, true
+}
+
+// put 写入响应，失败只记录日志
+func (c *ExactCache) put(ctx context.Context, key string, resp *llm.CompletionResponse) {
+	data, err := json.Marshal(resp)
+	if err == nil {
+		entry := &cache.CacheEntry{
+			Key:        key,
+			Response:   data,
+			Model:      resp.Model,
+			TokensUsed: resp.Usage.TotalTokens,
+			CreatedAt:  time.Now(),
+		}
+		if c.ttl > 0 {
+			entry.ExpiresAt = entry.CreatedAt.Add(c.ttl)
+		}
+		err = c.store.Set(ctx, key, entry)
+	}
+	if err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "exact cache store failed", logger.Err(err))
+	}
+}
+
+// exactCacheProvider 带精确缓存的 Provider
+type exactCacheProvider struct {
+	llm.Provider
+	cache *ExactCache
+}
+
+// SupportsSeed 与被包装的 Provider 一致
+func (p *exactCacheProvider) SupportsSeed() bool {
+	return agentruntime.SupportsSeed(p.Provider)
+}
+
+func (p *exactCacheProvider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	key, err := p.cache.key(p.Name(), req)
+	if err != nil {
+		return p.Provider.Complete(ctx, req)
+	}
+	if resp, ok := p.cache.lookup(ctx, key); ok {
+		// 命中不消耗 token，Usage 清零以免重复计入预算和成本
+		resp.Usage = llm.Usage{}
+		return resp, nil
+	}
+
+	resp, err := p.Provider.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.cache.put(ctx, key, resp)
+	return resp, nil
+}
+
+// WithExactCache 为 Agent 启用精确缓存
+//
+// 对主 Provider 和备用 Provider 的每次非流式调用按完整请求精确匹配缓存，
+// 详见 ExactCache。c 为 nil 时使用内存缓存。
+// 缓存仅在模型输出确定（如 temperature 为 0）时才安全，由调用方保证。
+// 作用于 ReActAgent 的推理循环。
+//
+// 示例：
+//
+//	exact := agent.NewExactCache(agent.WithExactCacheStore(redisCache))
+//	a := agent.NewReAct(agent.WithLLM(provider), agent.WithExactCache(exact))
+//	fmt.Println(exact.Stats().HitRate)
+func WithExactCache(c *ExactCache) Option {
+	return func(cfg *Config) {
+		if c == nil {
+			c = NewExactCache()
+		}
+		cfg.ExactCache = c
+	}
+}
+
+// exactCached 配置了精确缓存时包装 Provider
+func (a *BaseAgent) exactCached(p llm.Provider) llm.Provider {
+	if a.config.ExactCache == nil || p == nil {
+		return p
+	}
+	return a.config.ExactCache.Wrap(p)
+}
+
+// 确保实现了 Provider 接口
+var _ llm.Provider = (*exactCacheProvider)(nil)
@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func TestWithExactCache_HitOnSameRequest(t *testing.T) {
+	provider := mock.NewLLMProvider("openai").WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		last := req.Messages[len(req.Messages)-1].Content
+		return &llm.CompletionResponse{Content: "answer to " + last, Usage: llm.Usage{TotalTokens: 10}}, nil
+	})
+	exact := NewExactCache()
+
+	run := func(query string) Output {
+		t.Helper()
+		// 每次使用新的记忆，保证相同输入产生相同请求
+		a := NewReAct(WithLLM(provider), WithMemory(memory.NewBuffer(10)), WithExactCache(exact))
+		output, err := a.Run(context.Background(), Input{Query: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return output
+	}
+
+	first := run("hi")
+	second := run("hi")
+	if second.Content != first.Content {
+		t.Errorf("expected cached content %q, got %q", first.Content, second.Content)
+	}
+	if provider.CallCount() != 1 {
+		t.Errorf("expected 1 provider call, got %d", provider.CallCount())
+	}
+	if second.Usage.TotalTokens != 0 {
+		t.Errorf("expected cached run to use no tokens, got %d", second.Usage.TotalTokens)
+	}
+
+	run("bye")
+	if provider.CallCount() != 2 {
+		t.Errorf("expected different query to miss, got %d calls", provider.CallCount())
+	}
+
+	stats := exact.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.TokensSaved != 10 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestWithExactCache_ToolsInKey(t *testing.T) {
+	provider := mock.NewLLMProvider("openai").WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		if req.Messages[len(req.Messages)-1].Role == llm.RoleTool {
+			return &llm.CompletionResponse{Content: "done"}, nil
+		}
+		if len(req.Tools) > 0 {
+			return &llm.CompletionResponse{ToolCalls: []llm.ToolCall{{ID: "1", Name: "search", Arguments: `{}`}}}, nil
+		}
+		return &llm.CompletionResponse{Content: "no tools"}, nil
+	})
+	exact := NewExactCache()
+	search := mock.NewTool("search")
+
+	for range 2 {
+		a := NewReAct(WithLLM(provider), WithMemory(memory.NewBuffer(10)), WithExactCache(exact), WithTools(search))
+		output, err := a.Run(context.Background(), Input{Query: "find"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Content != "done" {
+			t.Errorf("expected done, got %q", output.Content)
+		}
+	}
+	// 第二次运行两轮 LLM 调用都命中，但工具仍然执行
+	if provider.CallCount() != 2 {
+		t.Errorf("expected 2 provider calls, got %d", provider.CallCount())
+	}
+	if search.CallCount() != 2 {
+		t.Errorf("expected tool to run on every run, got %d", search.CallCount())
+	}
+
+	// 工具定义不同的请求不命中
+	a := NewReAct(WithLLM(provider), WithMemory(memory.NewBuffer(10)), WithExactCache(exact))
+	output, err := a.Run(context.Background(), Input{Query: "find"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Content != "no tools" {
+		t.Errorf("expected request without tools to miss, got %q", output.Content)
+	}
+}
+
+func TestExactCache_KeyIgnoresMetadata(t *testing.T) {
+	exact := NewExactCache()
+	temp := 0.0
+	req := llm.CompletionRequest{
+		Model:       "gpt-4o",
+		Messages:    []llm.Message{{Role: llm.RoleUser, Content: "hi"}},
+		Temperature: &temp,
+	}
+	base, err := exact.key("openai", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withMeta := req
+	withMeta.Metadata = map[string]any{"run_id": "run-1"}
+	if k, _ := exact.key("openai", withMeta); k != base {
+		t.Error("expected metadata not to affect the key")
+	}
+
+	hotter := 0.7
+	withTemp := req
+	withTemp.Temperature = &hotter
+	if k, _ := exact.key("openai", withTemp); k == base {
+		t.Error("expected temperature to affect the key")
+	}
+	if k, _ := exact.key("anthropic", req); k == base {
+		t.Error("expected provider to affect the key")
+	}
+}
@@ -133,6 +133,13 @@ type SwarmRunner struct {
 
 	// Verbose 详细输出
 	Verbose bool
+
+	// Budget 整个 Swarm 运行（包括所有交接）共享的预算
+	// 为零值时使用 InitialAgent 通过 WithRunBudget 设置的预算
+	Budget RunBudget
+
+	// lifecycle 跟踪进行中的运行
+	lifecycle runTracker
 }
 
 // NewSwarmRunner 创建 Swarm 运行器
@@ -146,6 +153,25 @@ func NewSwarmRunner(initialAgent Agent) *SwarmRunner {
 
 // Run 运行 Swarm
 func (s *SwarmRunner) Run(ctx context.Context, input Input) (Output, error) {
+	ctx, done, err := s.lifecycle.begin(ctx)
+	if err != nil {
+		return Output{}, err
+	}
+	defer done()
+
+	// 交接链中每个 Agent 的运行都以 Swarm 运行为父运行
+	ctx, _ = enterRun(ctx)
+
+	budget := s.Budget
+	if budget.IsZero() {
+		if c, ok := s.InitialAgent.(interface{ Config() Config }); ok {
+			budget = c.Config().RunBudget
+		}
+	}
+	if !budget.IsZero() {
+		ctx = ContextWithRunBudget(ctx, budget)
+	}
+
 	currentAgent := s.InitialAgent
 	currentInput := input
 	handoffCount := 0
@@ -156,6 +182,9 @@ func (s *SwarmRunner) Run(ctx context.Context, input Input) (Output, error) {
 			return Output{}, ctx.Err()
 		default:
 		}
+		if err := budgetFromContext(ctx).check(); err != nil {
+			return Output{}, err
+		}
 
 		// 执行当前 Agent
 		output, err := currentAgent.Run(ctx, currentInput)
@@ -188,6 +217,15 @@ func (s *SwarmRunner) Run(ctx context.Context, input Input) (Output, error) {
 	return Output{}, fmt.Errorf("max handoffs (%d) exceeded", s.MaxHandoffs)
 }
 
+// Close 关闭运行器
+//
+// 拒绝新的运行，取消进行中的运行并等待其返回（受 ctx 限制）。
+// 参与交接的 Agent 由调用方注入，不会被关闭。可重复调用。
+func (s *SwarmRunner) Close(ctx context.Context) error {
+	_, err := s.lifecycle.shutdown(ctx)
+	return err
+}
+
 // extractHandoff 从输出中提取交接信息
 func (s *SwarmRunner) extractHandoff(output Output) *Handoff {
 	for _, tc := range output.ToolCalls {
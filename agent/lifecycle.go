@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrClosed Agent / Team / SwarmRunner 已关闭
+var ErrClosed = errors.New("agent: 已关闭")
+
+// Closer 可释放资源的组件
+//
+// BaseAgent（及嵌入它的 ReActAgent、PlanExecuteAgent 等）、Team 和 SwarmRunner 均实现此接口。
+// Close 会拒绝新的运行、取消进行中的运行并等待其返回，随后关闭组件自己创建的资源。
+//
+// 资源归属约定：
+//   - 通过 Option 注入的 LLM、Tools、Memory、成员 Agent 属于调用方，Close 不会关闭它们
+//   - 组件内部创建的资源（如未注入 Memory 时创建的默认缓冲记忆）由组件关闭
+//
+// 钩子在运行过程中同步触发，Close 等待运行返回即保证所有钩子已执行完毕。
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Close 关闭实现了 Closer 的组件，未实现时直接返回 nil
+func Close(ctx context.Context, v any) error {
+	if c, ok := v.(Closer); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+// closeResource 关闭组件自有资源
+// 支持 Close(ctx) error 与 io.Closer 两种形式
+func closeResource(ctx context.Context, v any) error {
+	switch c := v.(type) {
+	case Closer:
+		return c.Close(ctx)
+	case io.Closer:
+		return c.Close()
+	}
+	return nil
+}
+
+// runTracker 跟踪进行中的运行，用于关闭时取消并等待
+//
+// 零值可用。
+type runTracker struct {
+	mu      sync.Mutex
+	closed  bool
+	nextID  uint64
+	cancels map[uint64]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// begin 登记一次运行
+// 返回可被 Close 取消的 context 和结束回调；已关闭时返回 ErrClosed
+func (t *runTracker) begin(ctx context.Context) (context.Context, func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ctx, func() {}, ErrClosed
+	}
+	if t.cancels == nil {
+		t.cancels = make(map[uint64]context.CancelFunc)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	id := t.nextID
+	t.nextID++
+	t.cancels[id] = cancel
+	t.wg.Add(1)
+
+	var once sync.Once
+	done := func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.cancels, id)
+			t.mu.Unlock()
+			cancel()
+			t.wg.Done()
+		})
+	}
+	return runCtx, done, nil
+}
+
+// shutdown 拒绝新运行、取消进行中的运行并等待其结束
+// 返回 true 表示首次关闭；ctx 到期时返回 ctx 的错误
+func (t *runTracker) shutdown(ctx context.Context) (bool, error) {
+	t.mu.Lock()
+	first := !t.closed
+	t.closed = true
+	for _, cancel := range t.cancels {
+		cancel()
+	}
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return first, nil
+	case <-ctx.Done():
+		return first, ctx.Err()
+	}
+}
+
+var (
+	_ Closer = (*BaseAgent)(nil)
+	_ Closer = (*Team)(nil)
+	_ Closer = (*SwarmRunner)(nil)
+)
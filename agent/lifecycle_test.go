@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// blockingProvider 阻塞直到 context 取消
+type blockingProvider struct {
+	mockLLMProvider
+	started chan struct{}
+}
+
+func (p *blockingProvider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	close(p.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBaseAgentClose(t *testing.T) {
+	provider := &blockingProvider{started: make(chan struct{})}
+	a := NewBaseAgent(WithLLM(provider))
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := a.Invoke(context.Background(), Input{Query: "hi"})
+		errCh <- err
+	}()
+	<-provider.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := a.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Close 返回时进行中的运行已被取消并返回
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	default:
+		t.Fatal("in-flight run still running after Close")
+	}
+
+	if _, err := a.Invoke(context.Background(), Input{Query: "hi"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if err := a.Close(ctx); err != nil {
+		t.Errorf("second Close should be a no-op, got %v", err)
+	}
+}
+
+func TestCloseTimeout(t *testing.T) {
+	var tr runTracker
+	_, done, err := tr.begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer done()
+
+	// 运行未响应取消时，Close 受 ctx 限制返回
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := tr.shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestTeamAndSwarmClose(t *testing.T) {
+	member := NewBaseAgent(WithLLM(&mockLLMProvider{response: "ok"}))
+	team := NewTeam("team", WithAgents(member))
+	swarm := NewSwarmRunner(member)
+
+	ctx := context.Background()
+	if err := Close(ctx, team); err != nil {
+		t.Fatalf("team Close failed: %v", err)
+	}
+	if err := Close(ctx, swarm); err != nil {
+		t.Fatalf("swarm Close failed: %v", err)
+	}
+
+	if _, err := team.Run(ctx, Input{Query: "hi"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed from team, got %v", err)
+	}
+	if _, err := swarm.Run(ctx, Input{Query: "hi"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed from swarm, got %v", err)
+	}
+
+	// 注入的成员 Agent 不随 Team 关闭
+	if _, err := member.Run(ctx, Input{Query: "hi"}); err != nil {
+		t.Errorf("member agent should remain usable, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/hooks"
+	agentruntime "github.com/hexagon-codes/hexagon/runtime"
+)
+
+// LLMCircuitBreakers 按 Provider 名称维护的熔断器集合
+//
+// 每个 Provider（按 Name 区分）一个 core.CircuitBreaker，统计经 Wrap 包装后的调用成败。
+// 熔断器打开时调用立即返回 core.ErrCircuitOpen，不再请求该 Provider；
+// 配合 WithLLMFallback 时直接切换到下一个备用 Provider。
+// 状态变化时触发 hooks.LLMCircuitHook 事件。
+//
+// 同一个 LLMCircuitBreakers 可以在多个 Agent 间共享，汇总同一 Provider 的健康状况。
+type LLMCircuitBreakers struct {
+	config core.CircuitBreakerConfig
+
+	mu       sync.Mutex
+	circuits map[string]*providerCircuit
+}
+
+// NewLLMCircuitBreakers 创建熔断器集合，config 为 nil 时使用 core.DefaultCircuitBreakerConfig
+// config.OnStateChange 对每个 Provider 的状态变化都会调用
+func NewLLMCircuitBreakers(config *core.CircuitBreakerConfig) *LLMCircuitBreakers {
+	if config == nil {
+		config = core.DefaultCircuitBreakerConfig()
+	}
+	return &LLMCircuitBreakers{
+		config:   *config,
+		circuits: make(map[string]*providerCircuit),
+	}
+}
+
+// Wrap 包装 Provider，调用前检查熔断器并记录调用结果
+func (b *LLMCircuitBreakers) Wrap(p llm.Provider) llm.Provider {
+	return &circuitProvider{Provider: p, circuit: b.circuit(p.Name())}
+}
+
+// Health 返回各 Provider 的熔断器状态快照
+//
+// 打开状态在熔断超时后的下一次调用时才转为半开，
+// 因此超时后尚未被调用的 Provider 仍报告为 open。
+func (b *LLMCircuitBreakers) Health() map[string]core.CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	health := make(map[string]core.CircuitState, len(b.circuits))
+	for name, c := range b.circuits {
+		health[name] = c.breaker.State()
+	}
+	return health
+}
+
+// Unhealthy 返回熔断器未关闭的 Provider 名称（已排序）
+func (b *LLMCircuitBreakers) Unhealthy() []string {
+	var names []string
+	for name, state := range b.Health() {
+		if state != core.CircuitClosed {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// circuit 获取或创建 Provider 的熔断器
+func (b *LLMCircuitBreakers) circuit(name string) *providerCircuit {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.circuits[name]; ok {
+		return c
+	}
+
+	c := &providerCircuit{name: name}
+	cfg := b.config
+	onChange := b.config.OnStateChange
+	cfg.OnStateChange = func(from, to core.CircuitState) {
+		// 由 providerCircuit.do 持锁调用，转换先暂存，释放锁后再触发钩子
+		c.pending = append(c.pending, circuitTransition{from: from, to: to})
+		if onChange != nil {
+			onChange(from, to)
+		}
+	}
+	c.breaker = core.NewCircuitBreaker(&cfg)
+	b.circuits[name] = c
+	return c
+}
+
+// circuitTransition 熔断器状态转换
+type circuitTransition struct {
+	from, to core.CircuitState
+}
+
+// providerCircuit 单个 Provider 的熔断器
+type providerCircuit struct {
+	name    string
+	breaker *core.CircuitBreaker
+
+	// mu 串行化熔断器操作，使状态转换能与触发它的调用 context 对应
+	mu      sync.Mutex
+	pending []circuitTransition
+}
+
+// allow 检查是否允许调用
+func (c *providerCircuit) allow(ctx context.Context) bool {
+	var allowed bool
+	c.do(ctx, nil, func() { allowed = c.breaker.Allow() })
+	return allowed
+}
+
+// record 记录调用结果，context 取消和超时不计为失败
+func (c *providerCircuit) record(ctx context.Context, err error) {
+	switch {
+	case err == nil:
+		c.do(ctx, nil, c.breaker.RecordSuccess)
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+	default:
+		c.do(ctx, err, c.breaker.RecordFailure)
+	}
+}
+
+// do 执行熔断器操作，并为期间发生的状态转换触发钩子
+func (c *providerCircuit) do(ctx context.Context, cause error, op func()) {
+	c.mu.Lock()
+	op()
+	transitions := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	hookManager := hooks.ManagerFromContext(ctx)
+	if hookManager == nil {
+		return
+	}
+	for _, t := range transitions {
+		event := &hooks.LLMCircuitEvent{Provider: c.name, From: t.from.String(), To: t.to.String()}
+		if t.to == core.CircuitOpen {
+			event.Error = cause
+		}
+		_ = hookManager.TriggerLLMCircuit(ctx, event)
+	}
+}
+
+// circuitProvider 带熔断器的 Provider
+type circuitProvider struct {
+	llm.Provider
+	circuit *providerCircuit
+}
+
+// SupportsSeed 与被包装的 Provider 一致
+func (p *circuitProvider) SupportsSeed() bool {
+	return agentruntime.SupportsSeed(p.Provider)
+}
+
+func (p *circuitProvider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if !p.circuit.allow(ctx) {
+		return nil, fmt.Errorf("provider %s: %w", p.circuit.name, core.ErrCircuitOpen)
+	}
+	resp, err := p.Provider.Complete(ctx, req)
+	p.circuit.record(ctx, err)
+	return resp, err
+}
+
+// Stream 仅统计流的建立，流建立后的中途错误不计入熔断器
+func (p *circuitProvider) Stream(ctx context.Context, req llm.CompletionRequest) (*llm.Stream, error) {
+	if !p.circuit.allow(ctx) {
+		return nil, fmt.Errorf("provider %s: %w", p.circuit.name, core.ErrCircuitOpen)
+	}
+	stream, err := p.Provider.Stream(ctx, req)
+	p.circuit.record(ctx, err)
+	return stream, err
+}
+
+// WithLLMCircuitBreaker 为 Agent 的主 Provider 和备用 Provider 启用熔断
+//
+// 熔断器打开的 Provider 不再被调用；配置了 WithLLMFallback 时立即降级到下一个 Provider，
+// 且不受 WithLLMFallbackOn 限制。b 为 nil 时使用默认配置。
+// 作用于 ReActAgent 的推理循环。
+//
+// 示例：
+//
+//	breakers := agent.NewLLMCircuitBreakers(nil)
+//	a := agent.NewReAct(
+//	    agent.WithLLM(openaiProvider),
+//	    agent.WithLLMFallback(anthropicProvider),
+//	    agent.WithLLMCircuitBreaker(breakers),
+//	)
+//	fmt.Println(breakers.Health()) // map[anthropic:closed openai:open]
+func WithLLMCircuitBreaker(b *LLMCircuitBreakers) Option {
+	return func(c *Config) {
+		if b == nil {
+			b = NewLLMCircuitBreakers(nil)
+		}
+		c.LLMCircuitBreakers = b
+	}
+}
+
+// circuitWrapped 配置了熔断器时包装 Provider
+func (a *BaseAgent) circuitWrapped(p llm.Provider) llm.Provider {
+	if a.config.LLMCircuitBreakers == nil || p == nil {
+		return p
+	}
+	return a.config.LLMCircuitBreakers.Wrap(p)
+}
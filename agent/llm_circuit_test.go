@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// circuitRecorder 记录熔断事件的 LLM 钩子
+type circuitRecorder struct {
+	fallbackRecorder
+	circuits []*hooks.LLMCircuitEvent
+}
+
+func (h *circuitRecorder) OnLLMCircuit(_ context.Context, e *hooks.LLMCircuitEvent) error {
+	h.circuits = append(h.circuits, e)
+	return nil
+}
+
+func TestWithLLMCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	primary := mock.NewLLMProvider("primary").WithResponseFn(func(llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		if healthy.Load() {
+			return &llm.CompletionResponse{Content: "from primary"}, nil
+		}
+		return nil, errRateLimited
+	})
+	backup := mock.NewLLMProvider("backup").WithResponseFn(func(llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		return &llm.CompletionResponse{Content: "from backup"}, nil
+	})
+
+	recorder := &circuitRecorder{}
+	manager := hooks.NewManager()
+	manager.RegisterLLMHook(recorder)
+	ctx := hooks.ContextWithManager(context.Background(), manager)
+
+	breakers := NewLLMCircuitBreakers(&core.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		SuccessThreshold: 1,
+		Timeout:          50 * time.Millisecond,
+	})
+	// WithLLMFallbackErrors 不匹配 ErrCircuitOpen，熔断时仍应降级
+	a := NewReAct(WithLLM(primary), WithLLMFallback(backup),
+		WithLLMFallbackErrors(errRateLimited), WithLLMCircuitBreaker(breakers))
+
+	for i := 0; i < 3; i++ {
+		output, err := a.Run(ctx, Input{Query: "hi"})
+		if err != nil || output.Content != "from backup" {
+			t.Fatalf("run %d: expected backup response, got %q %v", i, output.Content, err)
+		}
+	}
+	if primary.CallCount() != 2 {
+		t.Errorf("expected open circuit to skip primary, got %d calls", primary.CallCount())
+	}
+	health := breakers.Health()
+	if health["primary"] != core.CircuitOpen || health["backup"] != core.CircuitClosed {
+		t.Errorf("unexpected health: %v", health)
+	}
+	if got := breakers.Unhealthy(); len(got) != 1 || got[0] != "primary" {
+		t.Errorf("unexpected unhealthy providers: %v", got)
+	}
+	if len(recorder.circuits) != 1 {
+		t.Fatalf("expected 1 circuit event, got %d", len(recorder.circuits))
+	}
+	if e := recorder.circuits[0]; e.Provider != "primary" || e.From != "closed" || e.To != "open" || !errors.Is(e.Error, errRateLimited) {
+		t.Errorf("unexpected circuit event: %+v", e)
+	}
+	if len(recorder.events) != 3 || !errors.Is(recorder.events[2].Error, core.ErrCircuitOpen) {
+		t.Errorf("expected third fallback to be caused by open circuit, got %d events", len(recorder.events))
+	}
+
+	// 熔断超时后半开试探成功，恢复为关闭
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	output, err := a.Run(ctx, Input{Query: "hi"})
+	if err != nil || output.Content != "from primary" {
+		t.Fatalf("expected primary to recover, got %q %v", output.Content, err)
+	}
+	if breakers.Health()["primary"] != core.CircuitClosed {
+		t.Errorf("expected primary closed, got %v", breakers.Health()["primary"])
+	}
+	if len(recorder.circuits) != 3 || recorder.circuits[1].To != "half-open" || recorder.circuits[2].To != "closed" {
+		t.Errorf("expected half-open then closed events, got %d events", len(recorder.circuits))
+	}
+}
+
+func TestWithLLMCircuitBreaker_NoFallback(t *testing.T) {
+	primary := mock.NewLLMProvider("solo").AddErrorResponse(errRateLimited)
+	breakers := NewLLMCircuitBreakers(&core.CircuitBreakerConfig{FailureThreshold: 1, Timeout: time.Minute})
+	a := NewReAct(WithLLM(primary), WithLLMCircuitBreaker(breakers))
+
+	if _, err := a.Run(context.Background(), Input{Query: "hi"}); !errors.Is(err, errRateLimited) {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+	if _, err := a.Run(context.Background(), Input{Query: "hi"}); !errors.Is(err, core.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if primary.CallCount() != 1 {
+		t.Errorf("expected 1 call, got %d", primary.CallCount())
+	}
+}
@@ -0,0 +1,144 @@
+package agent
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/core"
+	agentruntime "github.com/hexagon-codes/hexagon/runtime"
+)
+
+// WithLLMFallback 设置备用 LLM Provider，按顺序组成降级链
+//
+// 主 Provider（WithLLM）调用失败且错误满足 WithLLMFallbackOn 时，
+// 在同一次运行内用相同的对话消息依次尝试备用 Provider，对调用方透明。
+// 降级后本次运行的后续轮次继续使用切换后的 Provider；下一次运行重新从主 Provider 开始。
+// 每次切换触发 hooks.LLMFallbackHook 事件。
+// 作用于 ReActAgent 的推理循环。
+//
+// 示例：
+//
+//	agent.NewReAct(
+//	    agent.WithLLM(openaiProvider),
+//	    agent.WithLLMFallback(anthropicProvider, deepseekProvider),
+//	)
+func WithLLMFallback(providers ...llm.Provider) Option {
+	return func(c *Config) {
+		for _, p := range providers {
+			if p != nil {
+				c.LLMFallbacks = append(c.LLMFallbacks, p)
+			}
+		}
+	}
+}
+
+// WithLLMFallbackOn 设置触发降级的错误判断
+// 默认除 context 取消和超时外的所有错误都触发降级
+//
+// Provider 返回的错误已按 HTTP 状态等补充了类别，可以用 errors.Is 判断，
+// 如只在暂时性错误时降级：agent.WithLLMFallbackOn(core.IsRetryable)
+func WithLLMFallbackOn(fn func(error) bool) Option {
+	return func(c *Config) {
+		c.LLMFallbackOn = fn
+	}
+}
+
+// WithLLMFallbackErrors 仅在错误匹配（errors.Is）任一给定错误时降级
+func WithLLMFallbackErrors(errs ...error) Option {
+	return WithLLMFallbackOn(func(err error) bool {
+		for _, target := range errs {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// providerSelector 返回本次运行的 Provider 选择器
+// 未配置备用 Provider 时使用单 Provider 选择器
+func (a *BaseAgent) providerSelector() agentruntime.ProviderSelector {
+	primary := agentruntime.StaticProviderSelector{
+		Provider: a.wrapProvider(a.config.LLM),
+		Name:     a.config.LLM.Name(),
+	}
+	if len(a.config.LLMFallbacks) == 0 {
+		return primary
+	}
+	return &fallbackSelector{
+		primary:   primary,
+		fallbacks: a.config.LLMFallbacks,
+		shouldTry: a.config.LLMFallbackOn,
+		wrap:      a.wrapProvider,
+	}
+}
+
+// wrapProvider 按配置包装 Provider：熔断在内，缓存在外，缓存命中不经过熔断器
+// 最内层为 Provider 的错误补充类别（见 core.ClassifyError），熔断和降级判断都能使用
+func (a *BaseAgent) wrapProvider(p llm.Provider) llm.Provider {
+	return a.exactCached(a.circuitWrapped(classifiedProvider(p)))
+}
+
+// classifyingProvider 为调用错误补充类别的 Provider
+type classifyingProvider struct {
+	llm.Provider
+}
+
+// classifiedProvider 包装 Provider，p 为 nil 时返回 nil
+func classifiedProvider(p llm.Provider) llm.Provider {
+	if p == nil {
+		return nil
+	}
+	return &classifyingProvider{Provider: p}
+}
+
+// SupportsSeed 与被包装的 Provider 一致
+func (p *classifyingProvider) SupportsSeed() bool {
+	return agentruntime.SupportsSeed(p.Provider)
+}
+
+func (p *classifyingProvider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	resp, err := p.Provider.Complete(ctx, req)
+	return resp, core.ClassifyError(err)
+}
+
+func (p *classifyingProvider) Stream(ctx context.Context, req llm.CompletionRequest) (*llm.Stream, error) {
+	stream, err := p.Provider.Stream(ctx, req)
+	return stream, core.ClassifyError(err)
+}
+
+// fallbackSelector 按顺序降级的 Provider 选择器，每次运行创建一个
+type fallbackSelector struct {
+	primary   agentruntime.StaticProviderSelector
+	fallbacks []llm.Provider
+	shouldTry func(error) bool
+	wrap      func(llm.Provider) llm.Provider
+
+	// next 下一个尝试的备用 Provider 下标
+	next int
+}
+
+func (s *fallbackSelector) Select(ctx context.Context, req agentruntime.Request) (agentruntime.ProviderSelection, error) {
+	return s.primary.Select(ctx, req)
+}
+
+func (s *fallbackSelector) Fallback(ctx context.Context, _ agentruntime.ProviderSelection, err error) (agentruntime.ProviderSelection, error) {
+	if ctx.Err() != nil || s.next >= len(s.fallbacks) || !s.shouldFallback(err) {
+		return agentruntime.ProviderSelection{}, agentruntime.ErrNoFallback
+	}
+	p := s.fallbacks[s.next]
+	s.next++
+	return agentruntime.ProviderSelection{Provider: s.wrap(p), Name: p.Name()}, nil
+}
+
+func (s *fallbackSelector) shouldFallback(err error) bool {
+	// 熔断的 Provider 没有被调用，总是降级
+	if errors.Is(err, core.ErrCircuitOpen) {
+		return true
+	}
+	if s.shouldTry != nil {
+		return s.shouldTry(err)
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
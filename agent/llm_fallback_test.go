@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+var errRateLimited = errors.New("rate limited")
+
+// fallbackRecorder 记录降级事件的 LLM 钩子
+type fallbackRecorder struct {
+	events []*hooks.LLMFallbackEvent
+}
+
+func (h *fallbackRecorder) Name() string  { return "fallback-recorder" }
+func (h *fallbackRecorder) Enabled() bool { return true }
+func (h *fallbackRecorder) OnLLMStart(context.Context, *hooks.LLMStartEvent) error {
+	return nil
+}
+func (h *fallbackRecorder) OnLLMEnd(context.Context, *hooks.LLMEndEvent) error { return nil }
+func (h *fallbackRecorder) OnLLMStream(context.Context, *hooks.LLMStreamEvent) error {
+	return nil
+}
+func (h *fallbackRecorder) OnLLMFallback(_ context.Context, e *hooks.LLMFallbackEvent) error {
+	h.events = append(h.events, e)
+	return nil
+}
+
+func TestWithLLMFallback_Chain(t *testing.T) {
+	primary := mock.NewLLMProvider("openai").AddErrorResponse(errRateLimited)
+	second := mock.NewLLMProvider("anthropic").AddErrorResponse(errRateLimited)
+	third := mock.NewLLMProvider("deepseek").AddResponse("from deepseek")
+
+	recorder := &fallbackRecorder{}
+	manager := hooks.NewManager()
+	manager.RegisterLLMHook(recorder)
+
+	a := NewReAct(WithLLM(primary), WithLLMFallback(second, third))
+	output, err := a.Run(hooks.ContextWithManager(context.Background(), manager), Input{Query: "hi"})
+	if err != nil {
+		t.Fatalf("expected fallback to succeed, got %v", err)
+	}
+	if output.Content != "from deepseek" {
+		t.Errorf("expected deepseek response, got %q", output.Content)
+	}
+
+	// 备用 Provider 收到相同的对话
+	want := primary.LastCall().Messages
+	got := third.LastCall().Messages
+	if len(got) != len(want) || got[len(got)-1].Content != "hi" {
+		t.Errorf("expected conversation to be preserved, got %+v", got)
+	}
+
+	if len(recorder.events) != 2 {
+		t.Fatalf("expected 2 fallback events, got %d", len(recorder.events))
+	}
+	if e := recorder.events[0]; e.From != "openai" || e.To != "anthropic" || !errors.Is(e.Error, errRateLimited) {
+		t.Errorf("unexpected first event: %+v", e)
+	}
+	if e := recorder.events[1]; e.From != "anthropic" || e.To != "deepseek" {
+		t.Errorf("unexpected second event: %+v", e)
+	}
+}
+
+func TestWithLLMFallback_StickyWithinRun(t *testing.T) {
+	primary := mock.NewLLMProvider("primary").AddErrorResponse(errRateLimited).AddResponse("primary again")
+	backup := mock.NewLLMProvider("backup").
+		AddToolCallResponse([]llm.ToolCall{{ID: "1", Name: "search", Arguments: `{}`}}).
+		AddResponse("backup done")
+
+	a := NewReAct(WithLLM(primary), WithLLMFallback(backup), WithTools(mock.NewTool("search")))
+	output, err := a.Run(context.Background(), Input{Query: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Content != "backup done" || primary.CallCount() != 1 || backup.CallCount() != 2 {
+		t.Errorf("expected later turns to stay on backup, got %q (primary=%d backup=%d)",
+			output.Content, primary.CallCount(), backup.CallCount())
+	}
+
+	// 下一次运行重新从主 Provider 开始
+	output, err = a.Run(context.Background(), Input{Query: "again"})
+	if err != nil || output.Content != "primary again" {
+		t.Errorf("expected new run to start on primary, got %q %v", output.Content, err)
+	}
+}
+
+func TestWithLLMFallbackErrors(t *testing.T) {
+	primary := mock.NewLLMProvider("primary").AddErrorResponse(errors.New("invalid request"))
+	backup := mock.NewLLMProvider("backup").AddResponse("ok")
+
+	a := NewReAct(WithLLM(primary), WithLLMFallback(backup), WithLLMFallbackErrors(errRateLimited))
+	if _, err := a.Run(context.Background(), Input{Query: "hi"}); err == nil {
+		t.Fatal("expected non-matching error to fail without fallback")
+	}
+	if backup.CallCount() != 0 {
+		t.Errorf("backup should not be called, got %d calls", backup.CallCount())
+	}
+}
+
+func TestWithLLMFallback_ClassifiedErrors(t *testing.T) {
+	primary := mock.NewLLMProvider("openai").AddErrorResponse(errors.New("openai api error: 503 Service Unavailable"))
+	backup := mock.NewLLMProvider("anthropic").AddResponse("from anthropic")
+	a := NewReAct(WithLLM(primary), WithLLMFallback(backup), WithLLMFallbackOn(core.IsRetryable))
+
+	output, err := a.Run(context.Background(), Input{Query: "hi"})
+	if err != nil || output.Content != "from anthropic" {
+		t.Fatalf("expected fallback on unavailable provider, got %q %v", output.Content, err)
+	}
+
+	// 上下文过长重试无益，不降级，错误可按类别判断
+	primary = mock.NewLLMProvider("openai").AddErrorResponse(errors.New("openai api error: 400 Bad Request, body: context_length_exceeded"))
+	backup = mock.NewLLMProvider("anthropic").AddResponse("from anthropic")
+	a = NewReAct(WithLLM(primary), WithLLMFallback(backup), WithLLMFallbackOn(core.IsRetryable))
+	if _, err := a.Run(context.Background(), Input{Query: "hi"}); !errors.Is(err, core.ErrContextTooLong) {
+		t.Errorf("expected ErrContextTooLong, got %v", err)
+	}
+	if backup.CallCount() != 0 {
+		t.Errorf("expected no fallback, got %d backup calls", backup.CallCount())
+	}
+
+	if _, err := NewReAct().Run(context.Background(), Input{Query: "hi"}); !errors.Is(err, core.ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/observe/logger"
+)
+
+// logRunEnd 记录 Agent 运行结束日志
+func logRunEnd(ctx context.Context, agentName string, start time.Time, err error) {
+	l := logger.FromContext(ctx)
+	if err != nil {
+		l.ErrorContext(ctx, "agent run failed",
+			logger.String("agent_name", agentName), logger.Elapsed(time.Since(start)), logger.Err(err))
+		return
+	}
+	l.InfoContext(ctx, "agent run finished",
+		logger.String("agent_name", agentName), logger.Elapsed(time.Since(start)))
+}
+
+// logToolCall 记录工具调用日志
+func logToolCall(ctx context.Context, toolName string, start time.Time, err error) {
+	l := logger.FromContext(ctx)
+	if err != nil {
+		l.WarnContext(ctx, "tool call failed",
+			logger.ToolName(toolName), logger.Elapsed(time.Since(start)), logger.Err(err))
+		return
+	}
+	l.DebugContext(ctx, "tool call finished",
+		logger.ToolName(toolName), logger.Elapsed(time.Since(start)))
+}
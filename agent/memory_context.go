@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/ai-core/memory"
+)
+
+// ContextWithMemory 在 context 中设置本次运行使用的记忆，覆盖 Agent 配置的 Memory
+//
+// 用于同一个 Agent 实例服务多个会话的场景（如聊天服务按会话隔离历史）：
+// 运行时从该记忆读取历史上下文，并将本轮对话写回该记忆。
+// 仅对 SupportsContextMemory 为 true 的 Agent 生效。
+func ContextWithMemory(ctx context.Context, mem memory.Memory) context.Context {
+	return context.WithValue(ctx, memoryKey{}, mem)
+}
+
+// memoryKey 运行记忆的 context key
+type memoryKey struct{}
+
+// runMemory 返回本次运行使用的记忆：优先 context 中的记忆，其次 Agent 配置
+func (a *BaseAgent) runMemory(ctx context.Context) memory.Memory {
+	if mem, ok := ctx.Value(memoryKey{}).(memory.Memory); ok && mem != nil {
+		return mem
+	}
+	return a.config.Memory
+}
+
+// ContextMemorySupporter 由读写 ContextWithMemory 记忆的 Agent 实现
+//
+// 只有这类 Agent 能在共享实例时按会话隔离历史（如 serve.ChatServer）；
+// 包装其他 Agent 的实现应转发内层 Agent 的结果。
+type ContextMemorySupporter interface {
+	SupportsContextMemory() bool
+}
+
+// SupportsContextMemory 报告 a 是否读写 ContextWithMemory 设置的记忆
+//
+// 目前仅 ReActAgent 支持；其他 Agent 不读取历史，也不写回对话。
+func SupportsContextMemory(a Agent) bool {
+	s, ok := a.(ContextMemorySupporter)
+	return ok && s.SupportsContextMemory()
+}
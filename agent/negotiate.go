@@ -17,6 +17,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // ============== 能力定义 ==============
@@ -586,7 +588,7 @@ func (n *Negotiator) findBestMatch(requirement *CapabilityRequirement, agents []
 func (n *Negotiator) DeclareCapability(agentID string, capability CapabilitySpec) error {
 	agent, exists := n.registry.Get(agentID)
 	if !exists {
-		return fmt.Errorf("agent not found: %s", agentID)
+		return fmt.Errorf("agent %w: %s", core.ErrNotFound, agentID)
 	}
 
 	// 添加能力
@@ -603,7 +605,7 @@ func (n *Negotiator) DeclareCapability(agentID string, capability CapabilitySpec
 func (n *Negotiator) QueryCapabilities(agentID string) ([]CapabilitySpec, error) {
 	agent, exists := n.registry.Get(agentID)
 	if !exists {
-		return nil, fmt.Errorf("agent not found: %s", agentID)
+		return nil, fmt.Errorf("agent %w: %s", core.ErrNotFound, agentID)
 	}
 
 	specs := make([]CapabilitySpec, len(agent.Capabilities))
@@ -9,6 +9,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/events"
 	"github.com/hexagon-codes/hexagon/internal/util"
 )
 
@@ -258,6 +260,12 @@ type AgentNetwork struct {
 	// HeartbeatInterval 心跳间隔
 	heartbeatInterval time.Duration
 
+	// deadLetters 投递或处理失败的消息
+	deadLetters *deadLetterQueue
+
+	// bus 生命周期事件总线
+	bus *events.Bus[events.Lifecycle]
+
 	// Running 运行状态
 	running bool
 
@@ -279,6 +287,7 @@ func NewAgentNetwork(name string, opts ...NetworkOption) *AgentNetwork {
 		inboxSize:         100,
 		routerQueueSize:   10000,
 		heartbeatInterval: 30 * time.Second,
+		deadLetters:       newDeadLetterQueue(),
 	}
 
 	for _, opt := range opts {
@@ -327,6 +336,16 @@ func WithHeartbeatInterval(interval time.Duration) NetworkOption {
 	}
 }
 
+// WithNetworkEventBus 将网络的生命周期事件发布到事件总线
+//
+// Start 和 Stop 发布 RunStarted / RunCompleted，Agent 注册、注销和心跳超时分别发布
+// MemberJoined / MemberLeft / MemberOffline，Target 为 Agent ID。
+func WithNetworkEventBus(bus *events.Bus[events.Lifecycle]) NetworkOption {
+	return func(n *AgentNetwork) {
+		n.bus = bus
+	}
+}
+
 // ID 返回网络 ID
 func (n *AgentNetwork) ID() string {
 	return n.id
@@ -345,9 +364,8 @@ func (n *AgentNetwork) Topology() NetworkTopology {
 // Register 注册 Agent 到网络
 func (n *AgentNetwork) Register(agent Agent) error {
 	n.mu.Lock()
-	defer n.mu.Unlock()
-
 	if _, exists := n.nodes[agent.ID()]; exists {
+		n.mu.Unlock()
 		return fmt.Errorf("agent %s already registered", agent.ID())
 	}
 
@@ -365,7 +383,9 @@ func (n *AgentNetwork) Register(agent Agent) error {
 
 	// 根据拓扑建立连接
 	n.updateTopology()
+	n.mu.Unlock()
 
+	n.publish(context.Background(), events.MemberJoined, agent.ID())
 	return nil
 }
 
@@ -374,11 +394,10 @@ func (n *AgentNetwork) Register(agent Agent) error {
 // 线程安全：此方法使用安全的 channel 关闭机制，不会因为重复关闭或并发发送而 panic。
 func (n *AgentNetwork) Unregister(agentID string) error {
 	n.mu.Lock()
-	defer n.mu.Unlock()
-
 	node, exists := n.nodes[agentID]
 	if !exists {
-		return fmt.Errorf("agent %s not found", agentID)
+		n.mu.Unlock()
+		return fmt.Errorf("agent %s %w", agentID, core.ErrNotFound)
 	}
 
 	// 安全关闭收件箱（使用 sync.Once 确保只关闭一次）
@@ -397,7 +416,9 @@ func (n *AgentNetwork) Unregister(agentID string) error {
 		}
 		other.Neighbors = newNeighbors
 	}
+	n.mu.Unlock()
 
+	n.publish(context.Background(), events.MemberLeft, agentID)
 	return nil
 }
 
@@ -505,7 +526,7 @@ func (n *AgentNetwork) HandleMessage(ctx context.Context, msg *NetworkMessage) (
 func (n *AgentNetwork) defaultHandler(ctx context.Context, msg *NetworkMessage) (*NetworkMessage, error) {
 	agent, ok := n.GetAgent(msg.To)
 	if !ok {
-		return nil, fmt.Errorf("agent %s not found", msg.To)
+		return nil, fmt.Errorf("agent %s %w", msg.To, core.ErrNotFound)
 	}
 
 	// 将消息内容作为输入传递给 Agent
@@ -556,6 +577,7 @@ func (n *AgentNetwork) Start(ctx context.Context) error {
 	// 启动心跳检测
 	go n.heartbeatLoop(ctx)
 
+	n.publish(ctx, events.RunStarted, "")
 	return nil
 }
 
@@ -564,8 +586,6 @@ func (n *AgentNetwork) Start(ctx context.Context) error {
 // 线程安全：此方法使用安全的 channel 关闭机制，不会因为重复关闭或并发发送而 panic。
 func (n *AgentNetwork) Stop() {
 	n.mu.Lock()
-	defer n.mu.Unlock()
-
 	n.running = false
 	n.router.Stop()
 
@@ -573,6 +593,9 @@ func (n *AgentNetwork) Stop() {
 	for _, node := range n.nodes {
 		node.CloseInbox()
 	}
+	n.mu.Unlock()
+
+	n.publish(context.Background(), events.RunCompleted, "")
 }
 
 // heartbeatLoop 心跳检测循环
@@ -593,16 +616,38 @@ func (n *AgentNetwork) heartbeatLoop(ctx context.Context) {
 // checkHeartbeats 检查心跳
 func (n *AgentNetwork) checkHeartbeats() {
 	n.mu.Lock()
-	defer n.mu.Unlock()
-
 	timeout := n.heartbeatInterval * 3
 	now := time.Now()
 
-	for _, node := range n.nodes {
+	var offline []string
+	for id, node := range n.nodes {
 		if now.Sub(node.LastHeartbeat) > timeout {
+			if node.Status != NodeStatusOffline {
+				offline = append(offline, id)
+			}
 			node.Status = NodeStatusOffline
 		}
 	}
+	n.mu.Unlock()
+
+	for _, id := range offline {
+		n.publish(context.Background(), events.MemberOffline, id)
+	}
+}
+
+// publish 发布生命周期事件，不能在持有 n.mu 时调用，避免处理器回调网络方法时死锁
+func (n *AgentNetwork) publish(ctx context.Context, typ events.LifecycleType, target string) {
+	if n.bus == nil {
+		return
+	}
+	n.bus.Publish(ctx, events.Lifecycle{
+		Source:    events.SourceNetwork,
+		Type:      typ,
+		Name:      n.name,
+		RunID:     n.id,
+		Target:    target,
+		Timestamp: time.Now(),
+	})
 }
 
 // updateTopology 更新网络拓扑
@@ -710,7 +755,7 @@ func (n *AgentNetwork) Connect(agent1ID, agent2ID string) error {
 	node2, ok2 := n.nodes[agent2ID]
 
 	if !ok1 || !ok2 {
-		return fmt.Errorf("one or both agents not found")
+		return fmt.Errorf("one or both agents %w", core.ErrNotFound)
 	}
 
 	// 添加双向连接
@@ -733,7 +778,7 @@ func (n *AgentNetwork) Disconnect(agent1ID, agent2ID string) error {
 	node2, ok2 := n.nodes[agent2ID]
 
 	if !ok1 || !ok2 {
-		return fmt.Errorf("one or both agents not found")
+		return fmt.Errorf("one or both agents %w", core.ErrNotFound)
 	}
 
 	node1.Neighbors = removeString(node1.Neighbors, agent2ID)
@@ -749,7 +794,7 @@ func (n *AgentNetwork) GetNeighbors(agentID string) ([]Agent, error) {
 
 	node, ok := n.nodes[agentID]
 	if !ok {
-		return nil, fmt.Errorf("agent %s not found", agentID)
+		return nil, fmt.Errorf("agent %s %w", agentID, core.ErrNotFound)
 	}
 
 	neighbors := make([]Agent, 0, len(node.Neighbors))
@@ -794,6 +839,7 @@ func (n *AgentNetwork) Stats() NetworkStats {
 		MessagesSent:   n.router.messagesSent.Load(),
 		MessagesRecv:   n.router.messagesRecv.Load(),
 		MessagesFailed: n.router.messagesFailed.Load(),
+		DeadLetters:    n.deadLetters.len(),
 	}
 }
 
@@ -808,6 +854,7 @@ type NetworkStats struct {
 	MessagesSent   int64  `json:"messages_sent"`
 	MessagesRecv   int64  `json:"messages_recv"`
 	MessagesFailed int64  `json:"messages_failed"`
+	DeadLetters    int    `json:"dead_letters"`
 }
 
 // ============== MessageRouter ==============
@@ -959,15 +1006,24 @@ func (r *MessageRouter) RequestResponse(ctx context.Context, msg *NetworkMessage
 	}
 }
 
-// deliver 投递消息
+// deliver 投递消息，失败的消息进入死信队列
+func (r *MessageRouter) deliver(ctx context.Context, msg *NetworkMessage) error {
+	err := r.tryDeliver(ctx, msg)
+	if err != nil {
+		r.deadLetter(msg, DeadLetterStageDeliver, err)
+	}
+	return err
+}
+
+// tryDeliver 投递消息到目标收件箱
 //
 // 线程安全：此方法会检查收件箱是否已关闭，避免向已关闭的 channel 发送消息导致 panic。
 // 计数器使用原子操作确保并发安全。
-func (r *MessageRouter) deliver(ctx context.Context, msg *NetworkMessage) error {
+func (r *MessageRouter) tryDeliver(ctx context.Context, msg *NetworkMessage) error {
 	node, ok := r.network.GetNode(msg.To)
 	if !ok {
 		r.messagesFailed.Add(1)
-		return fmt.Errorf("target agent %s not found", msg.To)
+		return fmt.Errorf("target agent %s %w", msg.To, core.ErrNotFound)
 	}
 
 	// 在持有节点读锁的情况下检查关闭状态并发送，消除 TOCTOU 竞态
@@ -1051,17 +1107,23 @@ func (r *MessageRouter) processMessage(ctx context.Context, msg *NetworkMessage)
 		}
 	}
 
-	// 调用网络的消息处理器
-	response, err := r.network.HandleMessage(ctx, msg)
-	if err != nil {
+	if err := r.handle(ctx, msg); err != nil {
 		r.messagesFailed.Add(1)
-		return
+		r.deadLetter(msg, DeadLetterStageHandle, err)
 	}
+}
 
-	// 如果有响应，发送回去
+// handle 调用网络的消息处理器，有响应时发送回去
+// 响应投递失败时响应消息进入死信队列，不视为处理失败
+func (r *MessageRouter) handle(ctx context.Context, msg *NetworkMessage) error {
+	response, err := r.network.HandleMessage(ctx, msg)
+	if err != nil {
+		return err
+	}
 	if response != nil && msg.From != "" {
-		r.deliver(ctx, response)
+		_ = r.deliver(ctx, response)
 	}
+	return nil
 }
 
 // 辅助函数
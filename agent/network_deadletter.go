@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/internal/util"
+)
+
+// ErrDeadLetterNotFound 死信未找到
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetterStage 消息失败的阶段
+type DeadLetterStage string
+
+const (
+	// DeadLetterStageDeliver 投递到收件箱失败（目标不存在、离线、收件箱已满或已关闭）
+	DeadLetterStageDeliver DeadLetterStage = "deliver"
+
+	// DeadLetterStageHandle 消息处理器返回错误
+	DeadLetterStageHandle DeadLetterStage = "handle"
+)
+
+// DeadLetter 未能投递或处理失败的网络消息
+type DeadLetter struct {
+	// ID 死信 ID（同一消息广播给多个目标时，每个失败的副本各有一个死信）
+	ID string `json:"id"`
+
+	// Message 原始消息
+	Message *NetworkMessage `json:"message"`
+
+	// Stage 失败阶段
+	Stage DeadLetterStage `json:"stage"`
+
+	// Error 失败原因
+	Error error `json:"-"`
+
+	// Attempts 失败次数（包括重新投递）
+	Attempts int `json:"attempts"`
+
+	// FailedAt 最后一次失败时间
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// WithDeadLetterCapacity 设置死信队列容量，满时丢弃最旧的死信
+// 默认 1000
+func WithDeadLetterCapacity(n int) NetworkOption {
+	return func(net *AgentNetwork) {
+		if n > 0 {
+			net.deadLetters.capacity = n
+		}
+	}
+}
+
+// WithDeadLetterHandler 设置死信回调，每条消息进入死信队列时调用
+// 用于记录日志或上报指标；回调同步执行，应尽快返回
+func WithDeadLetterHandler(fn func(DeadLetter)) NetworkOption {
+	return func(net *AgentNetwork) {
+		net.deadLetters.onDeadLetter = fn
+	}
+}
+
+// deadLetterQueue 有界死信队列
+type deadLetterQueue struct {
+	capacity     int
+	onDeadLetter func(DeadLetter)
+
+	items []DeadLetter
+	mu    sync.Mutex
+}
+
+func newDeadLetterQueue() *deadLetterQueue {
+	return &deadLetterQueue{capacity: 1000}
+}
+
+// add 加入死信，超出容量时丢弃最旧的
+func (q *deadLetterQueue) add(dl DeadLetter) {
+	q.mu.Lock()
+	if len(q.items) >= q.capacity {
+		q.items = q.items[len(q.items)-q.capacity+1:]
+	}
+	q.items = append(q.items, dl)
+	fn := q.onDeadLetter
+	q.mu.Unlock()
+
+	if fn != nil {
+		fn(dl)
+	}
+}
+
+// take 取出指定死信
+func (q *deadLetterQueue) take(id string) (DeadLetter, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, dl := range q.items {
+		if dl.ID == id {
+			q.items = append(q.items[:i], q.items[i+1:]...)
+			return dl, true
+		}
+	}
+	return DeadLetter{}, false
+}
+
+// list 返回死信快照，按失败时间从旧到新
+func (q *deadLetterQueue) list() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]DeadLetter(nil), q.items...)
+}
+
+// len 返回死信数量
+func (q *deadLetterQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// purge 清空死信队列
+func (q *deadLetterQueue) purge() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := len(q.items)
+	q.items = nil
+	return n
+}
+
+// DeadLetters 返回死信队列的快照，按失败时间从旧到新
+//
+// 投递失败（目标不存在、离线、收件箱已满或已关闭）和处理器返回错误的消息
+// 都会进入死信队列，而不是被静默丢弃。
+func (n *AgentNetwork) DeadLetters() []DeadLetter {
+	return n.deadLetters.list()
+}
+
+// Redeliver 重新投递指定死信
+//
+// 投递阶段失败的消息重新投递到目标收件箱，处理阶段失败的消息重新交给处理器。
+// 再次失败时死信以新的失败原因回到队列，Attempts 加一，并返回该错误。
+func (n *AgentNetwork) Redeliver(ctx context.Context, id string) error {
+	dl, ok := n.deadLetters.take(id)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+
+	var err error
+	switch dl.Stage {
+	case DeadLetterStageHandle:
+		err = n.router.handle(ctx, dl.Message)
+	default:
+		err = n.router.tryDeliver(ctx, dl.Message)
+	}
+	if err != nil {
+		dl.Error = err
+		dl.Attempts++
+		dl.FailedAt = time.Now()
+		n.deadLetters.add(dl)
+		return fmt.Errorf("redeliver %s: %w", id, err)
+	}
+	return nil
+}
+
+// RedeliverAll 重新投递所有死信，返回汇总的失败原因
+func (n *AgentNetwork) RedeliverAll(ctx context.Context) error {
+	var errs []error
+	for _, dl := range n.deadLetters.list() {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := n.Redeliver(ctx, dl.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PurgeDeadLetters 清空死信队列，返回清除的数量
+func (n *AgentNetwork) PurgeDeadLetters() int {
+	return n.deadLetters.purge()
+}
+
+// deadLetter 记录失败的消息
+func (r *MessageRouter) deadLetter(msg *NetworkMessage, stage DeadLetterStage, err error) {
+	r.network.deadLetters.add(DeadLetter{
+		ID:       util.GenerateID("dlq"),
+		Message:  msg,
+		Stage:    stage,
+		Error:    err,
+		Attempts: 1,
+		FailedAt: time.Now(),
+	})
+}
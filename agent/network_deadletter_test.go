@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNetworkDeadLetter_DeliverAndRedeliver(t *testing.T) {
+	var captured []DeadLetter
+	network := NewAgentNetwork("test",
+		WithNetworkInboxSize(1),
+		WithDeadLetterHandler(func(dl DeadLetter) { captured = append(captured, dl) }),
+	)
+	if err := network.Register(newMockAgent("a", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := network.Register(newMockAgent("b", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := network.SendTo(ctx, "a-id", "b-id", "first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := network.SendTo(ctx, "a-id", "b-id", "second"); err == nil {
+		t.Fatal("expected inbox full error")
+	}
+
+	letters := network.DeadLetters()
+	if len(letters) != 1 || len(captured) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d (handler saw %d)", len(letters), len(captured))
+	}
+	dl := letters[0]
+	if dl.Stage != DeadLetterStageDeliver || dl.Message.Content != "second" || dl.Attempts != 1 {
+		t.Errorf("unexpected dead letter: %+v", dl)
+	}
+
+	// 收件箱仍满时重新投递失败，死信回到队列
+	if err := network.Redeliver(ctx, dl.ID); err == nil {
+		t.Fatal("expected redelivery to fail while inbox is full")
+	}
+	if letters := network.DeadLetters(); len(letters) != 1 || letters[0].Attempts != 2 {
+		t.Fatalf("expected dead letter to be requeued with 2 attempts, got %+v", letters)
+	}
+
+	node, _ := network.GetNode("b-id")
+	<-node.Inbox
+	if err := network.RedeliverAll(ctx); err != nil {
+		t.Fatalf("expected redelivery to succeed, got %v", err)
+	}
+	if msg := <-node.Inbox; msg.Content != "second" {
+		t.Errorf("expected redelivered message, got %v", msg.Content)
+	}
+	if network.Stats().DeadLetters != 0 {
+		t.Errorf("expected empty dead letter queue, got %d", network.Stats().DeadLetters)
+	}
+	if err := network.Redeliver(ctx, dl.ID); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+}
+
+func TestNetworkDeadLetter_HandlerFailure(t *testing.T) {
+	network := NewAgentNetwork("test", WithDeadLetterCapacity(1))
+	failures := 1
+	network.RegisterHandler("orders", func(ctx context.Context, msg *NetworkMessage) (*NetworkMessage, error) {
+		if failures > 0 {
+			failures--
+			return nil, errors.New("downstream unavailable")
+		}
+		return nil, nil
+	})
+
+	ctx := context.Background()
+	for range 2 {
+		msg := NewMessage("a", "b", MessageTypeEvent, "order")
+		msg.Topic = "orders"
+		network.router.processMessage(ctx, msg)
+	}
+	// 第二条消息处理成功，只有第一条进入死信队列
+	letters := network.DeadLetters()
+	if len(letters) != 1 || letters[0].Stage != DeadLetterStageHandle {
+		t.Fatalf("expected 1 handler dead letter, got %+v", letters)
+	}
+	if err := network.Redeliver(ctx, letters[0].ID); err != nil {
+		t.Fatalf("expected handler redelivery to succeed, got %v", err)
+	}
+	if n := network.PurgeDeadLetters(); n != 0 {
+		t.Errorf("expected empty queue, purged %d", n)
+	}
+}
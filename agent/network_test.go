@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/events"
+)
+
+func TestAgentNetwork_EventBus(t *testing.T) {
+	bus := events.NewBus[events.Lifecycle]()
+	var mu sync.Mutex
+	var got []string
+	bus.Subscribe(func(ctx context.Context, e events.Lifecycle) {
+		mu.Lock()
+		defer mu.Unlock()
+		if e.Source != events.SourceNetwork || e.Name != "team" || e.RunID == "" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		got = append(got, fmt.Sprintf("%s:%s", e.Target, e.Type))
+	})
+
+	network := NewAgentNetwork("team", WithNetworkEventBus(bus))
+	// 处理器中回调网络方法不应死锁
+	bus.Subscribe(func(ctx context.Context, e events.Lifecycle) {
+		_ = network.ListAgents()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := network.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := network.Register(newMockAgent("a", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := network.Unregister("a-id"); err != nil {
+		t.Fatal(err)
+	}
+	network.Stop()
+
+	want := "[:run.started a-id:member.joined a-id:member.left :run.completed]"
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(got) != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+}
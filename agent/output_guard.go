@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/hexagon/observe/logger"
+	"github.com/hexagon-codes/hexagon/security/guard"
+)
+
+// MetadataOutputGuard 输出未通过守卫但仍被返回（脱敏或仅告警）时，
+// Output.Metadata 中记录 *guard.CheckResult 的键
+const MetadataOutputGuard = "output_guard"
+
+// guardOutput 使用输出守卫检查最终回复
+// 未配置守卫时原样返回；被拒绝时返回包装了 guard.ErrOutputBlocked 的错误
+func (a *BaseAgent) guardOutput(ctx context.Context, output Output) (Output, error) {
+	g := a.config.OutputGuard
+	if g == nil || !g.Enabled() {
+		return output, nil
+	}
+
+	action := a.config.OutputGuardAction
+	if action == "" {
+		action = guard.ActionBlock
+	}
+
+	content, result, err := guard.GuardOutput(ctx, g, output.Content, action)
+	if err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "agent output rejected by guard",
+			logger.String("agent_name", a.Name()), logger.Err(err))
+		return Output{}, err
+	}
+	if result.Passed {
+		return output, nil
+	}
+
+	logger.FromContext(ctx).WarnContext(ctx, "agent output flagged by guard",
+		logger.String("agent_name", a.Name()),
+		logger.String("action", string(action)),
+		logger.String("reason", result.Reason))
+
+	output.Content = content
+	if output.Metadata == nil {
+		output.Metadata = make(map[string]any)
+	}
+	output.Metadata[MetadataOutputGuard] = result
+	return output, nil
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/llm/parser"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+)
+
+// MetadataParsedOutput Output.Metadata 中记录输出解析结果的键
+const MetadataParsedOutput = "parsed_output"
+
+// ErrOutputParse 最终回复在重试后仍无法被输出解析器解析
+var ErrOutputParse = errors.New("agent: output parse failed")
+
+// WithOutputParser 设置输出解析器
+//
+// 解析器的格式说明会追加到系统提示词中。最终回复解析失败时，
+// 将解析错误作为反馈请求模型修正，最多重试 maxRetries 次；
+// 解析结果可通过 ParsedOutput 获取。
+func WithOutputParser[T any](p parser.Parser[T], maxRetries int) Option {
+	return func(c *Config) {
+		c.OutputParser = parser.Any(p)
+		c.OutputParserRetries = maxRetries
+	}
+}
+
+// ParsedOutput 获取输出解析器的解析结果
+func ParsedOutput[T any](output Output) (T, bool) {
+	v, ok := output.Metadata[MetadataParsedOutput].(T)
+	return v, ok
+}
+
+// formatInstructions 将输出解析器的格式说明追加到系统提示词
+func (a *BaseAgent) formatInstructions(systemPrompt string) string {
+	if a.config.OutputParser == nil {
+		return systemPrompt
+	}
+	instructions := a.config.OutputParser.GetFormatInstructions()
+	if instructions == "" {
+		return systemPrompt
+	}
+	if systemPrompt == "" {
+		return instructions
+	}
+	return systemPrompt + "\n\n" + instructions
+}
+
+// parseOutput 使用输出解析器解析最终回复
+//
+// 解析失败时携带原始问题、上次回复和解析错误请求模型修正，
+// 修正调用不使用工具。未配置解析器时原样返回。
+func (a *BaseAgent) parseOutput(ctx context.Context, input Input, systemPrompt string, output Output) (Output, error) {
+	p := a.config.OutputParser
+	if p == nil {
+		return output, nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		value, err := p.ParseAny(ctx, output.Content)
+		if err == nil {
+			if output.Metadata == nil {
+				output.Metadata = make(map[string]any)
+			}
+			output.Metadata[MetadataParsedOutput] = value
+			return output, nil
+		}
+		if attempt >= a.config.OutputParserRetries {
+			return Output{}, fmt.Errorf("%w after %d retries: %w", ErrOutputParse, attempt, err)
+		}
+
+		logger.FromContext(ctx).DebugContext(ctx, "agent output parse failed, retrying",
+			logger.String("agent_name", a.Name()), logger.Err(err))
+
+		var messages []llm.Message
+		if systemPrompt != "" {
+			messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: systemPrompt})
+		}
+		messages = append(messages,
+			llm.Message{Role: llm.RoleUser, Content: input.Query},
+			llm.Message{Role: llm.RoleAssistant, Content: output.Content},
+			llm.Message{Role: llm.RoleUser, Content: fmt.Sprintf(
+				"Your previous answer could not be parsed: %v\n\n%s\n\nRespond again with only the corrected answer.",
+				err, p.GetFormatInstructions())},
+		)
+
+		resp, cerr := completeLLM(ctx, a.config.LLM, llm.CompletionRequest{Messages: messages})
+		if cerr != nil {
+			return Output{}, fmt.Errorf("output parse retry failed: %w", cerr)
+		}
+		output.Content = resp.Content
+		output.Usage = mergeUsage(output.Usage, resp.Usage)
+	}
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/llm/parser"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func TestReActAgentOutputParser(t *testing.T) {
+	type answer struct {
+		Value int `json:"value"`
+	}
+
+	mockLLM := mock.NewLLMProvider("parser")
+	mockLLM.AddResponse("the answer is four")
+	mockLLM.AddResponse(`{"value": 4}`)
+
+	a := NewReAct(WithLLM(mockLLM), WithOutputParser(parser.NewJSONParser[answer](), 1))
+	out, err := a.Run(context.Background(), Input{Query: "2+2?"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	got, ok := ParsedOutput[answer](out)
+	if !ok || got.Value != 4 {
+		t.Fatalf("expected parsed value 4, got %+v (ok=%v)", got, ok)
+	}
+
+	calls := mockLLM.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 LLM calls, got %d", len(calls))
+	}
+	if !strings.Contains(calls[0].Messages[0].Content, "JSON") {
+		t.Error("expected format instructions in system prompt")
+	}
+	retry := calls[1].Messages
+	if last := retry[len(retry)-1].Content; !strings.Contains(last, "could not be parsed") {
+		t.Errorf("expected parse error feedback, got %q", last)
+	}
+}
+
+func TestBaseAgentOutputParserExhausted(t *testing.T) {
+	mockLLM := mock.NewLLMProvider("parser")
+	mockLLM.AddResponse("maybe")
+	mockLLM.AddResponse("perhaps")
+
+	a := NewBaseAgent(WithLLM(mockLLM), WithOutputParser(parser.NewBoolParser(), 1))
+	_, err := a.Invoke(context.Background(), Input{Query: "ok?"})
+	if !errors.Is(err, ErrOutputParse) || !errors.Is(err, parser.ErrParseFailure) {
+		t.Fatalf("expected ErrOutputParse wrapping ErrParseFailure, got %v", err)
+	}
+	if mockLLM.CallCount() != 2 {
+		t.Errorf("expected 2 LLM calls, got %d", mockLLM.CallCount())
+	}
+}
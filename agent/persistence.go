@@ -177,25 +177,29 @@ type FileCheckpointStore struct {
 }
 
 // safePath 校验并返回安全的文件路径，防止路径穿越攻击
-//
-// 确保最终路径在 s.dir 目录内。
 func (s *FileCheckpointStore) safePath(id string) (string, error) {
+	return safeJSONPath(s.dir, "检查点", id)
+}
+
+// safeJSONPath 返回 dir 下 {id}.json 的路径，确保最终路径在 dir 目录内
+// kind 用于错误信息（如 "检查点"、"会话"）
+func safeJSONPath(dir, kind, id string) (string, error) {
 	// 禁止包含路径分隔符和特殊序列
 	if strings.ContainsAny(id, "/\\") || strings.Contains(id, "..") {
-		return "", fmt.Errorf("检查点 ID 包含非法字符: %s", id)
+		return "", fmt.Errorf("%s ID 包含非法字符: %s", kind, id)
 	}
-	path := filepath.Join(s.dir, id+".json")
+	path := filepath.Join(dir, id+".json")
 	// 二次校验：确保 Clean 后仍在 dir 内
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return "", fmt.Errorf("解析路径失败: %w", err)
 	}
-	absDir, err := filepath.Abs(s.dir)
+	absDir, err := filepath.Abs(dir)
 	if err != nil {
 		return "", fmt.Errorf("解析目录路径失败: %w", err)
 	}
 	if !strings.HasPrefix(absPath, absDir+string(filepath.Separator)) {
-		return "", fmt.Errorf("检查点 ID 路径穿越: %s", id)
+		return "", fmt.Errorf("%s ID 路径穿越: %s", kind, id)
 	}
 	return path, nil
 }
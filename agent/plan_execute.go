@@ -137,11 +137,31 @@ func NewPlanExecute(opts []Option, peOpts ...PlanExecuteOption) *PlanExecuteAgen
 //  5. 汇总所有结果生成最终回复
 func (a *PlanExecuteAgent) Run(ctx context.Context, input Input) (Output, error) {
 	if a.config.LLM == nil {
-		return Output{}, fmt.Errorf("LLM provider not configured")
+		return Output{}, fmt.Errorf("LLM provider %w", core.ErrNotConfigured)
 	}
 
-	// 生成运行 ID
-	runID := util.GenerateID("run")
+	ctx, done, err := a.beginRun(ctx)
+	if err != nil {
+		return Output{}, err
+	}
+	defer done()
+
+	ctx, _ = takeRunSampling(ctx, a.config.LLM)
+	ctx, budget, cancel := a.beginBudget(ctx)
+	defer cancel()
+	output, err := a.run(ctx, input)
+	return output, budget.err(ctx, err)
+}
+
+// run 执行一次运行，预算计数由 Run 开启
+func (a *PlanExecuteAgent) run(ctx context.Context, input Input) (Output, error) {
+	cached, cacheRun, hit := a.cacheLookup(ctx, input, nil)
+	if hit {
+		return cached, nil
+	}
+
+	// 分配运行 ID
+	ctx, runID := a.startRun(ctx)
 	startTime := time.Now()
 
 	// 获取钩子管理器
@@ -150,9 +170,10 @@ func (a *PlanExecuteAgent) Run(ctx context.Context, input Input) (Output, error)
 	// 触发运行开始钩子
 	if hookManager != nil {
 		if err := hookManager.TriggerRunStart(ctx, &hooks.RunStartEvent{
-			RunID:   runID,
-			AgentID: a.ID(),
-			Input:   input,
+			RunID:       runID,
+			ParentRunID: ParentRunIDFromContext(ctx),
+			AgentID:     a.ID(),
+			Input:       input,
 		}); err != nil {
 			return Output{}, fmt.Errorf("run start hook failed: %w", err)
 		}
@@ -267,6 +288,10 @@ func (a *PlanExecuteAgent) Run(ctx context.Context, input Input) (Output, error)
 	output.Metadata["completed_steps"] = a.countCompletedSteps(plan)
 	output.Metadata["replan_count"] = replanCount
 
+	if runErr == nil {
+		output, runErr = a.guardOutput(ctx, output)
+	}
+
 	// 触发运行结束钩子
 	if hookManager != nil {
 		if runErr != nil {
@@ -278,10 +303,11 @@ func (a *PlanExecuteAgent) Run(ctx context.Context, input Input) (Output, error)
 			})
 		} else {
 			hookManager.TriggerRunEnd(ctx, &hooks.RunEndEvent{
-				RunID:    runID,
-				AgentID:  a.ID(),
-				Output:   output,
-				Duration: time.Since(startTime).Milliseconds(),
+				RunID:       runID,
+				ParentRunID: ParentRunIDFromContext(ctx),
+				AgentID:     a.ID(),
+				Output:      output,
+				Duration:    time.Since(startTime).Milliseconds(),
 			})
 		}
 	}
@@ -290,6 +316,7 @@ func (a *PlanExecuteAgent) Run(ctx context.Context, input Input) (Output, error)
 		return Output{}, runErr
 	}
 
+	a.cacheStore(ctx, cacheRun, input, output)
 	return output, nil
 }
 
@@ -307,7 +334,7 @@ func (a *PlanExecuteAgent) createPlan(ctx context.Context, runID, goal string) (
 // planWithLLM 使用 LLM 直接生成计划
 func (a *PlanExecuteAgent) planWithLLM(ctx context.Context, runID, goal string) (*planner.Plan, error) {
 	// 构建工具描述
-	toolsDesc := a.buildToolsDescription()
+	toolsDesc := a.buildToolsDescription(ctx)
 
 	prompt := fmt.Sprintf(`你是一个任务规划专家。请将以下目标分解为可执行的步骤序列。
 
@@ -369,13 +396,14 @@ func (a *PlanExecuteAgent) planWithLLM(ctx context.Context, runID, goal string)
 }
 
 // buildToolsDescription 构建工具描述
-func (a *PlanExecuteAgent) buildToolsDescription() string {
-	if len(a.config.Tools) == 0 {
+func (a *PlanExecuteAgent) buildToolsDescription(ctx context.Context) string {
+	tools := a.allowedTools(ctx)
+	if len(tools) == 0 {
 		return "无可用工具"
 	}
 
 	var builder strings.Builder
-	for i, t := range a.config.Tools {
+	for i, t := range tools {
 		builder.WriteString(fmt.Sprintf("%d. %s: %s\n", i+1, t.Name(), t.Description()))
 	}
 	return builder.String()
@@ -477,6 +505,14 @@ func (a *PlanExecuteAgent) executeTool(ctx context.Context, runID string, step *
 		}, nil
 	}
 
+	if !a.toolAllowed(ctx, step.Action.Name) {
+		return &planner.StepResult{
+			Success:  false,
+			Error:    fmt.Sprintf("tool '%s' not permitted", step.Action.Name),
+			Duration: time.Since(startTime).Milliseconds(),
+		}, nil
+	}
+
 	toolID := util.GenerateID("tool")
 
 	// 触发工具开始钩子
@@ -489,9 +525,13 @@ func (a *PlanExecuteAgent) executeTool(ctx context.Context, runID string, step *
 		})
 	}
 
-	// 执行工具
-	result, err := targetTool.Execute(ctx, step.Action.Parameters)
+	// 执行工具（占用预算中的工具调用额度）
+	if err := budgetFromContext(ctx).acquireToolCall(); err != nil {
+		return nil, err
+	}
+	result, err := executeTool(ctx, a.config.ToolCache, a.toolCacheScope(ctx, ""), targetTool, step.Action.Parameters)
 	duration := time.Since(startTime).Milliseconds()
+	logToolCall(ctx, step.Action.Name, startTime, err)
 
 	// 触发工具结束钩子
 	if hookManager != nil {
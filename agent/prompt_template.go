@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/hexagon-codes/hexagon/llm/prompt"
+)
+
+// PromptVarQuery 渲染提示词模板时用户查询对应的变量名
+const PromptVarQuery = "query"
+
+// WithPromptTemplate 使用模板生成系统提示词
+//
+// 每次运行时以 Input.Context 和用户查询（变量 "query"）渲染模板，
+// 渲染结果替代 SystemPrompt；缺少变量时运行返回错误。
+func WithPromptTemplate(tpl prompt.Renderer) Option {
+	return func(c *Config) {
+		c.PromptTemplate = tpl
+	}
+}
+
+// systemPrompt 返回本次运行的系统提示词
+// 配置了 PromptTemplate 时渲染模板，否则使用 SystemPrompt；
+// 配置了 OutputParser 时追加其格式说明，配置了 ContextProviders 时在前面注入动态上下文
+func (a *BaseAgent) systemPrompt(ctx context.Context, input Input) (string, error) {
+	if a.config.PromptTemplate == nil {
+		return a.injectContext(ctx, a.formatInstructions(a.config.SystemPrompt))
+	}
+
+	vars := make(map[string]any, len(input.Context)+1)
+	maps.Copy(vars, input.Context)
+	vars[PromptVarQuery] = input.Query
+
+	text, err := a.config.PromptTemplate.Render(vars)
+	if err != nil {
+		return "", fmt.Errorf("render prompt template: %w", err)
+	}
+	return a.injectContext(ctx, a.formatInstructions(text))
+}
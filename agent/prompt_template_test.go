@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/llm/prompt"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func TestReActAgentPromptTemplate(t *testing.T) {
+	tpl, err := prompt.New("system", "You are {{.role}}. Answer: {{.query}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockLLM := mock.NewLLMProvider("prompt")
+	mockLLM.AddResponse("ok")
+	a := NewReAct(WithLLM(mockLLM), WithPromptTemplate(tpl))
+
+	_, err = a.Run(context.Background(), Input{
+		Query:   "hi",
+		Context: map[string]any{"role": "a tester"},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	call := mockLLM.LastCall()
+	if got := call.Messages[0].Content; got != "You are a tester. Answer: hi" {
+		t.Errorf("unexpected system prompt: %q", got)
+	}
+
+	// 缺少变量时运行失败，不调用 LLM
+	_, err = a.Run(context.Background(), Input{Query: "hi"})
+	if !errors.Is(err, prompt.ErrMissingVariable) {
+		t.Errorf("expected ErrMissingVariable, got %v", err)
+	}
+	if mockLLM.CallCount() != 1 {
+		t.Errorf("expected no LLM call on render error, got %d calls", mockLLM.CallCount())
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/hooks"
 	"github.com/hexagon-codes/hexagon/internal/util"
+	"github.com/hexagon-codes/hexagon/observe/logger"
 	agentruntime "github.com/hexagon-codes/hexagon/runtime"
 	"github.com/hexagon-codes/hexagon/stream"
 )
@@ -57,31 +58,80 @@ func NewReAct(opts ...Option) *ReActAgent {
 // Run 执行 ReAct Agent
 func (a *ReActAgent) Run(ctx context.Context, input Input) (Output, error) {
 	if a.config.LLM == nil {
-		return Output{}, fmt.Errorf("LLM provider not configured")
+		return Output{}, fmt.Errorf("LLM provider %w", core.ErrNotConfigured)
+	}
+
+	ctx, done, err := a.beginRun(ctx)
+	if err != nil {
+		return Output{}, err
+	}
+	defer done()
+
+	ctx, sampling := takeRunSampling(ctx, a.config.LLM)
+	ctx, runID := a.startRun(ctx)
+	ctx, err = a.beginThread(ctx, input)
+	if err != nil {
+		return Output{}, err
+	}
+
+	cached, cacheRun, hit := a.cacheLookup(ctx, input, a.runMemory(ctx))
+	if hit {
+		a.saveThread(ctx, runID, input, cached)
+		return cached, nil
+	}
+
+	ctx, budget, cancel := a.beginBudget(ctx)
+	defer cancel()
+
+	systemPrompt, err := a.systemPrompt(ctx, input)
+	if err != nil {
+		return Output{}, err
 	}
 
-	runID := util.GenerateID("run")
 	startTime := time.Now()
 	hookManager := hooks.ManagerFromContext(ctx)
+	logger.FromContext(ctx).DebugContext(ctx, "agent run started", logger.String("agent_name", a.Name()))
 
+	tools := a.allowedTools(ctx)
 	runner := agentruntime.NewRunner(agentruntime.Config{
-		ProviderSelector: agentruntime.StaticProviderSelector{
-			Provider: a.config.LLM,
-			Name:     a.config.LLM.Name(),
+		ProviderSelector: a.providerSelector(),
+		ToolExecutor: &agentToolExecutor{
+			tools:       tools,
+			runID:       runID,
+			hookManager: hookManager,
+			allow:       a.toolAllowed,
+			cache:       a.config.ToolCache,
+			cacheScope:  a.toolCacheScope(ctx, input.ThreadID),
 		},
-		ToolExecutor:    &agentToolExecutor{tools: a.config.Tools, runID: runID, hookManager: hookManager},
+		Middleware:      budget.middleware(),
 		DefaultMaxTurns: a.config.MaxIterations,
 	})
 
-	result, err := runner.RunWithSink(ctx, agentruntime.Request{
+	req := agentruntime.Request{
 		ID:       runID,
-		Messages: a.buildInitialMessages(ctx, input),
-		Tools:    a.buildToolDefinitions(),
+		Messages: a.buildInitialMessages(ctx, input, systemPrompt),
+		Tools:    buildToolDefinitions(tools),
 		Limits: agentruntime.Limits{
 			MaxTurns: a.config.MaxIterations,
 		},
-	}, a.runtimeHookSink(runID, input, startTime, hookManager))
+		Sampling: sampling,
+	}
+	sink := a.runtimeHookSink(runID, input, startTime, hookManager)
+	if onToken := a.tokenHandler(ctx); onToken != nil {
+		req.StreamMode = agentruntime.StreamModeTokens
+		sink = tokenSink(onToken, sink)
+	}
+	result, err := runner.RunWithSink(ctx, req, sink)
 	output := outputFromRuntime(result)
+	if budgetErr := budget.exceeded(ctx, err); budgetErr != nil {
+		err = budgetErr
+		if a.config.RunBudget.Summarize {
+			// 总结回复不完整，不写入语义缓存
+			cacheRun = nil
+			output, err = a.summarizeOnBudget(ctx, budget, budgetErr)
+		}
+	}
+	logRunEnd(ctx, a.Name(), startTime, err)
 	if err != nil {
 		if hookManager != nil {
 			hookManager.TriggerError(ctx, &hooks.ErrorEvent{
@@ -94,9 +144,20 @@ func (a *ReActAgent) Run(ctx context.Context, input Input) (Output, error) {
 		return Output{}, err
 	}
 
+	output, err = a.parseOutput(ctx, input, systemPrompt, output)
+	if err != nil {
+		return Output{}, err
+	}
+
+	output, err = a.guardOutput(ctx, output)
+	if err != nil {
+		return Output{}, err
+	}
+	a.cacheStore(ctx, cacheRun, input, output)
+
 	// 保存到记忆（保存失败不影响主流程，但通过钩子报告错误）
-	if a.config.Memory != nil {
-		if err := a.saveToMemory(ctx, input, output); err != nil {
+	if mem := a.runMemory(ctx); mem != nil {
+		if err := a.saveToMemory(ctx, mem, input, output); err != nil {
 			// 记忆保存失败不应阻止返回成功的输出
 			// 通过错误钩子报告，便于监控和调试
 			if hookManager != nil {
@@ -109,6 +170,7 @@ func (a *ReActAgent) Run(ctx context.Context, input Input) (Output, error) {
 			}
 		}
 	}
+	a.saveThread(ctx, runID, input, output)
 
 	return output, nil
 }
@@ -122,17 +184,25 @@ func (a *ReActAgent) runtimeHookSink(runID string, input Input, start time.Time,
 		switch event.Type {
 		case agentruntime.EventRunStarted:
 			return hookManager.TriggerRunStart(ctx, &hooks.RunStartEvent{
-				RunID:   runID,
-				AgentID: a.ID(),
-				Input:   input,
+				RunID:       runID,
+				ParentRunID: ParentRunIDFromContext(ctx),
+				AgentID:     a.ID(),
+				Input:       input,
 			})
 		case agentruntime.EventLLMStarted:
 			llmStart = time.Now()
-			return hookManager.TriggerLLMStart(ctx, &hooks.LLMStartEvent{
+			provider, _ := event.Metadata["provider"].(string)
+			sampling := event.State.Request.Sampling
+			start := &hooks.LLMStartEvent{
 				RunID:    runID,
-				Provider: a.config.LLM.Name(),
+				Provider: provider,
 				Messages: convertMessagesToAny(event.State.Messages),
-			})
+				Seed:     sampling.Seed,
+			}
+			if sampling.Temperature != nil {
+				start.Temperature = *sampling.Temperature
+			}
+			return hookManager.TriggerLLMStart(ctx, start)
 		case agentruntime.EventLLMCompleted:
 			if event.Response == nil {
 				return nil
@@ -144,12 +214,23 @@ func (a *ReActAgent) runtimeHookSink(runID string, input Input, start time.Time,
 				CompletionTokens: event.Response.Usage.CompletionTokens,
 				Duration:         time.Since(llmStart).Milliseconds(),
 			})
+		case agentruntime.EventProviderFallback:
+			from, _ := event.Metadata["from"].(string)
+			to, _ := event.Metadata["to"].(string)
+			return hookManager.TriggerLLMFallback(ctx, &hooks.LLMFallbackEvent{
+				RunID:   runID,
+				AgentID: a.ID(),
+				From:    from,
+				To:      to,
+				Error:   event.Error,
+			})
 		case agentruntime.EventRunFinished:
 			return hookManager.TriggerRunEnd(ctx, &hooks.RunEndEvent{
-				RunID:    runID,
-				AgentID:  a.ID(),
-				Output:   outputFromRuntime(agentruntimeResultFromState(event.State)),
-				Duration: time.Since(start).Milliseconds(),
+				RunID:       runID,
+				ParentRunID: ParentRunIDFromContext(ctx),
+				AgentID:     a.ID(),
+				Output:      outputFromRuntime(agentruntimeResultFromState(event.State)),
+				Duration:    time.Since(start).Milliseconds(),
 			})
 		case agentruntime.EventRunFailed:
 			return hookManager.TriggerError(ctx, &hooks.ErrorEvent{
@@ -167,6 +248,15 @@ type agentToolExecutor struct {
 	tools       []tool.Tool
 	runID       string
 	hookManager *hooks.Manager
+
+	// allow 调用前的权限检查，为 nil 时不检查
+	allow func(ctx context.Context, name string) bool
+
+	// cache 工具结果缓存，为 nil 时不缓存
+	cache *ToolCache
+
+	// cacheScope 工具结果缓存的隔离范围
+	cacheScope string
 }
 
 func (e *agentToolExecutor) Execute(ctx context.Context, call llm.ToolCall) (agentruntime.ToolResult, error) {
@@ -181,6 +271,10 @@ func (e *agentToolExecutor) Execute(ctx context.Context, call llm.ToolCall) (age
 		msg := fmt.Sprintf("Error: tool '%s' not found", call.Name)
 		return agentruntime.ToolResult{Content: msg, Error: msg}, nil
 	}
+	if e.allow != nil && !e.allow(ctx, call.Name) {
+		msg := fmt.Sprintf("Error: tool '%s' not permitted", call.Name)
+		return agentruntime.ToolResult{Content: msg, Error: msg}, nil
+	}
 	args, err := tool.ParseArgs(call.Arguments)
 	if err != nil {
 		msg := fmt.Sprintf("Error: failed to parse arguments: %v", err)
@@ -200,7 +294,8 @@ func (e *agentToolExecutor) Execute(ctx context.Context, call llm.ToolCall) (age
 		})
 	}
 	start := time.Now()
-	toolResult, execErr := targetTool.Execute(ctx, args)
+	toolResult, execErr := executeTool(ctx, e.cache, e.cacheScope, targetTool, args)
+	logToolCall(ctx, call.Name, start, execErr)
 	if e.hookManager != nil {
 		e.hookManager.TriggerToolEnd(ctx, &hooks.ToolEndEvent{
 			RunID:    e.runID,
@@ -218,6 +313,9 @@ func (e *agentToolExecutor) Execute(ctx context.Context, call llm.ToolCall) (age
 	return agentruntime.ToolResult{Content: formatToolResult(toolResult), Raw: toolResult}, nil
 }
 
+// MetadataFinishReason ReActAgent 输出元数据中最后一次 LLM 调用的结束原因（如 stop、length），Provider 未返回时不设置
+const MetadataFinishReason = "finish_reason"
+
 func outputFromRuntime(result *agentruntime.Result) Output {
 	if result == nil {
 		return Output{}
@@ -299,7 +397,7 @@ func (a *ReActAgent) executeToolCallsWithHooks(ctx context.Context, runID string
 
 		// 执行工具
 		toolStartTime := time.Now()
-		toolResult, err := targetTool.Execute(ctx, args)
+		toolResult, err := executeTool(ctx, a.config.ToolCache, a.toolCacheScope(ctx, ""), targetTool, args)
 		toolDuration := time.Since(toolStartTime).Milliseconds()
 
 		// 触发工具结束钩子
@@ -349,16 +447,17 @@ func convertMessagesToAny(messages []llm.Message) []any {
 // 参数：
 //   - ctx: 上下文，用于记忆查询的超时和取消控制
 //   - input: 用户输入
+//   - systemPrompt: 本次运行的系统提示词
 //
 // 返回构建好的消息列表
-func (a *ReActAgent) buildInitialMessages(ctx context.Context, input Input) []llm.Message {
+func (a *ReActAgent) buildInitialMessages(ctx context.Context, input Input, systemPrompt string) []llm.Message {
 	messages := []llm.Message{
-		{Role: llm.RoleSystem, Content: a.config.SystemPrompt},
+		{Role: llm.RoleSystem, Content: systemPrompt},
 	}
 
 	// 从记忆中获取历史上下文
-	if a.config.Memory != nil {
-		entries, _ := a.config.Memory.Search(ctx, memory.SearchQuery{
+	if mem := a.runMemory(ctx); mem != nil {
+		entries, _ := mem.Search(ctx, memory.SearchQuery{
 			Limit:     10,
 			OrderDesc: true,
 		})
@@ -382,13 +481,13 @@ func (a *ReActAgent) buildInitialMessages(ctx context.Context, input Input) []ll
 }
 
 // buildToolDefinitions 构建工具定义
-func (a *ReActAgent) buildToolDefinitions() []llm.ToolDefinition {
-	if len(a.config.Tools) == 0 {
+func buildToolDefinitions(tools []tool.Tool) []llm.ToolDefinition {
+	if len(tools) == 0 {
 		return nil
 	}
 
-	defs := make([]llm.ToolDefinition, len(a.config.Tools))
-	for i, t := range a.config.Tools {
+	defs := make([]llm.ToolDefinition, len(tools))
+	for i, t := range tools {
 		defs[i] = llm.ToolDefinition{
 			Type: "function",
 			Function: llm.ToolFunctionDef{
@@ -447,14 +546,15 @@ func truncateToolResult(s string, maxLen int) string {
 //
 // 参数：
 //   - ctx: 上下文
+//   - mem: 写入的记忆（见 runMemory）
 //   - input: 用户输入
 //   - output: Agent 输出
 //
 // 返回：
 //   - 保存过程中遇到的第一个错误，如果全部成功则返回 nil
-func (a *ReActAgent) saveToMemory(ctx context.Context, input Input, output Output) error {
+func (a *ReActAgent) saveToMemory(ctx context.Context, mem memory.Memory, input Input, output Output) error {
 	// 保存用户输入
-	if err := a.config.Memory.Save(ctx, memory.Entry{
+	if err := mem.Save(ctx, memory.Entry{
 		Role:    "user",
 		Content: input.Query,
 	}); err != nil {
@@ -467,7 +567,7 @@ func (a *ReActAgent) saveToMemory(ctx context.Context, input Input, output Outpu
 		for _, tc := range output.ToolCalls {
 			fmt.Fprintf(&toolSummary, "Called %s: %s\n", tc.Name, tc.Result.String())
 		}
-		if err := a.config.Memory.Save(ctx, memory.Entry{
+		if err := mem.Save(ctx, memory.Entry{
 			Role:    "tool",
 			Content: toolSummary.String(),
 		}); err != nil {
@@ -476,7 +576,7 @@ func (a *ReActAgent) saveToMemory(ctx context.Context, input Input, output Outpu
 	}
 
 	// 保存 Agent 回复
-	if err := a.config.Memory.Save(ctx, memory.Entry{
+	if err := mem.Save(ctx, memory.Entry{
 		Role:    "assistant",
 		Content: output.Content,
 	}); err != nil {
@@ -486,14 +586,20 @@ func (a *ReActAgent) saveToMemory(ctx context.Context, input Input, output Outpu
 	return nil
 }
 
+// SupportsContextMemory 实现 ContextMemorySupporter：运行时读写 ContextWithMemory 设置的记忆
+func (a *ReActAgent) SupportsContextMemory() bool {
+	return true
+}
+
 // Invoke 执行 ReAct Agent（实现 Runnable 接口）
+// opts 支持 WithRunTemperature、WithRunSeed 等单次运行选项
 func (a *ReActAgent) Invoke(ctx context.Context, input Input, opts ...core.Option) (Output, error) {
-	return a.Run(ctx, input)
+	return a.Run(ContextWithRunOptions(ctx, opts...), input)
 }
 
 // Stream 流式执行 ReAct Agent
 func (a *ReActAgent) Stream(ctx context.Context, input Input, opts ...core.Option) (*stream.StreamReader[Output], error) {
-	output, err := a.Run(ctx, input)
+	output, err := a.Run(ContextWithRunOptions(ctx, opts...), input)
 	if err != nil {
 		return nil, err
 	}
@@ -504,7 +610,7 @@ func (a *ReActAgent) Stream(ctx context.Context, input Input, opts ...core.Optio
 func (a *ReActAgent) Batch(ctx context.Context, inputs []Input, opts ...core.Option) ([]Output, error) {
 	results := make([]Output, len(inputs))
 	for i, input := range inputs {
-		output, err := a.Run(ctx, input)
+		output, err := a.Run(ContextWithRunOptions(ctx, opts...), input)
 		if err != nil {
 			return nil, err
 		}
@@ -554,4 +660,7 @@ func (a *ReActAgent) BatchStream(ctx context.Context, inputs []Input, opts ...co
 }
 
 // 确保实现了 Agent 接口
-var _ Agent = (*ReActAgent)(nil)
+var (
+	_ Agent                  = (*ReActAgent)(nil)
+	_ ContextMemorySupporter = (*ReActAgent)(nil)
+)
@@ -1,10 +1,17 @@
 package agent
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+	"github.com/hexagon-codes/hexagon/security/guard"
 	"github.com/hexagon-codes/hexagon/testing/mock"
 )
 
@@ -183,3 +190,101 @@ func TestReActAgentStream(t *testing.T) {
 		t.Error("expected at least one output")
 	}
 }
+
+func TestReActAgentTokenHandler(t *testing.T) {
+	agent := NewReAct(WithLLM(mock.FixedProvider("你好")))
+
+	var tokens []string
+	ctx := ContextWithTokenHandler(context.Background(), func(content string) error {
+		tokens = append(tokens, content)
+		return nil
+	})
+	output, err := agent.Run(ctx, Input{Query: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Content != "你好" || strings.Join(tokens, "|") != "你|好" {
+		t.Errorf("output = %q, tokens = %q", output.Content, tokens)
+	}
+
+	// 回调返回错误时中止运行
+	stop := errors.New("client gone")
+	ctx = ContextWithTokenHandler(context.Background(), func(string) error { return stop })
+	if _, err := agent.Run(ctx, Input{Query: "hi"}); !errors.Is(err, stop) {
+		t.Errorf("expected handler error, got %v", err)
+	}
+
+	if !SupportsContextMemory(agent) || SupportsContextMemory(NewReflection(nil)) {
+		t.Error("only ReActAgent should support context memory")
+	}
+}
+
+func TestReActAgentLogging(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	ctx := logger.ContextWithLogger(context.Background(), logger.FromSlog(slog.New(handler)))
+
+	mockLLM := mock.NewLLMProvider("react-log")
+	mockLLM.AddToolCallResponse([]llm.ToolCall{
+		{ID: "call_1", Type: "function", Name: "search", Arguments: `{}`},
+	})
+	mockLLM.AddResponse("done")
+
+	agent := NewReAct(
+		WithName("react-log"),
+		WithLLM(mockLLM),
+		WithTools(mock.FixedTool("search", "ok")),
+	)
+	if _, err := agent.Run(ctx, Input{Query: "hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msgs := map[string]map[string]any{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		msgs[record["msg"].(string)] = record
+	}
+
+	for _, msg := range []string{"agent run started", "tool call finished", "agent run finished"} {
+		record, ok := msgs[msg]
+		if !ok {
+			t.Fatalf("missing log %q in %s", msg, buf.String())
+		}
+		if record["run_id"] == nil || record["run_id"] == "" || record["agent_id"] != agent.ID() {
+			t.Errorf("log %q missing correlation ids: %v", msg, record)
+		}
+	}
+	if msgs["tool call finished"]["tool_name"] != "search" {
+		t.Errorf("expected tool_name=search, got %v", msgs["tool call finished"]["tool_name"])
+	}
+}
+
+func TestReActAgentOutputGuards(t *testing.T) {
+	newAgent := func(opts ...Option) *ReActAgent {
+		mockLLM := mock.NewLLMProvider("guarded")
+		mockLLM.AddResponse("邮箱是 leak@example.com")
+		return NewReAct(append([]Option{WithLLM(mockLLM)}, opts...)...)
+	}
+
+	_, err := newAgent(WithOutputGuards(guard.NewPIIGuard())).Run(context.Background(), Input{Query: "hi"})
+	if !errors.Is(err, guard.ErrOutputBlocked) {
+		t.Fatalf("expected ErrOutputBlocked, got %v", err)
+	}
+
+	output, err := newAgent(
+		WithOutputGuards(guard.NewPIIGuard()),
+		WithOutputGuardAction(guard.ActionRedact),
+	).Run(context.Background(), Input{Query: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(output.Content, "leak@example.com") {
+		t.Errorf("expected redacted content, got %q", output.Content)
+	}
+	if _, ok := output.Metadata[MetadataOutputGuard].(*guard.CheckResult); !ok {
+		t.Error("expected guard result in metadata")
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/hexagon-codes/ai-core/llm"
 	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/hooks"
-	"github.com/hexagon-codes/hexagon/internal/util"
 	"github.com/hexagon-codes/hexagon/stream"
 )
 
@@ -166,11 +165,31 @@ func NewReflection(opts []Option, rOpts ...ReflectionOption) *ReflectionAgent {
 //  4. 返回最终输出
 func (a *ReflectionAgent) Run(ctx context.Context, input Input) (Output, error) {
 	if a.config.LLM == nil {
-		return Output{}, fmt.Errorf("LLM provider not configured")
+		return Output{}, fmt.Errorf("LLM provider %w", core.ErrNotConfigured)
 	}
 
-	// 生成运行 ID
-	runID := util.GenerateID("run")
+	ctx, done, err := a.beginRun(ctx)
+	if err != nil {
+		return Output{}, err
+	}
+	defer done()
+
+	ctx, _ = takeRunSampling(ctx, a.config.LLM)
+	ctx, budget, cancel := a.beginBudget(ctx)
+	defer cancel()
+	output, err := a.run(ctx, input)
+	return output, budget.err(ctx, err)
+}
+
+// run 执行一次运行，预算计数由 Run 开启
+func (a *ReflectionAgent) run(ctx context.Context, input Input) (Output, error) {
+	cached, cacheRun, hit := a.cacheLookup(ctx, input, nil)
+	if hit {
+		return cached, nil
+	}
+
+	// 分配运行 ID
+	ctx, runID := a.startRun(ctx)
 	startTime := time.Now()
 
 	// 获取钩子管理器
@@ -179,9 +198,10 @@ func (a *ReflectionAgent) Run(ctx context.Context, input Input) (Output, error)
 	// 触发运行开始钩子
 	if hookManager != nil {
 		if err := hookManager.TriggerRunStart(ctx, &hooks.RunStartEvent{
-			RunID:   runID,
-			AgentID: a.ID(),
-			Input:   input,
+			RunID:       runID,
+			ParentRunID: ParentRunIDFromContext(ctx),
+			AgentID:     a.ID(),
+			Input:       input,
 		}); err != nil {
 			return Output{}, fmt.Errorf("run start hook failed: %w", err)
 		}
@@ -243,13 +263,30 @@ func (a *ReflectionAgent) Run(ctx context.Context, input Input) (Output, error)
 		"reflections":   a.summarizeReflections(reflections),
 	}
 
+	if a.config.OutputParser != nil {
+		systemPrompt, err := a.systemPrompt(ctx, input)
+		if err != nil {
+			return Output{}, err
+		}
+		if bestOutput, err = a.parseOutput(ctx, input, systemPrompt, bestOutput); err != nil {
+			return Output{}, err
+		}
+	}
+
+	bestOutput, err := a.guardOutput(ctx, bestOutput)
+	if err != nil {
+		return Output{}, err
+	}
+	a.cacheStore(ctx, cacheRun, input, bestOutput)
+
 	// 触发运行结束钩子
 	if hookManager != nil {
 		hookManager.TriggerRunEnd(ctx, &hooks.RunEndEvent{
-			RunID:    runID,
-			AgentID:  a.ID(),
-			Output:   bestOutput,
-			Duration: time.Since(startTime).Milliseconds(),
+			RunID:       runID,
+			ParentRunID: ParentRunIDFromContext(ctx),
+			AgentID:     a.ID(),
+			Output:      bestOutput,
+			Duration:    time.Since(startTime).Milliseconds(),
 		})
 	}
 
@@ -258,6 +295,11 @@ func (a *ReflectionAgent) Run(ctx context.Context, input Input) (Output, error)
 
 // executeTask 执行任务
 func (a *ReflectionAgent) executeTask(ctx context.Context, runID string, input Input, feedback string, iteration int, hookManager *hooks.Manager) (Output, error) {
+	systemPrompt, err := a.systemPrompt(ctx, input)
+	if err != nil {
+		return Output{}, err
+	}
+
 	// 构建提示
 	var promptBuilder strings.Builder
 	promptBuilder.WriteString(input.Query)
@@ -268,10 +310,10 @@ func (a *ReflectionAgent) executeTask(ctx context.Context, runID string, input I
 	}
 
 	messages := []llm.Message{}
-	if a.config.SystemPrompt != "" {
+	if systemPrompt != "" {
 		messages = append(messages, llm.Message{
 			Role:    llm.RoleSystem,
-			Content: a.config.SystemPrompt,
+			Content: systemPrompt,
 		})
 	}
 	messages = append(messages, llm.Message{
@@ -329,7 +371,7 @@ func (a *ReflectionAgent) reflectWithLLM(ctx context.Context, input Input, outpu
 
 只返回 JSON，不要其他内容。`, input.Query, output.Content)
 
-	resp, err := runCompletionWithRuntime(ctx, a.config.LLM, util.GenerateID("run"), []llm.Message{
+	resp, err := runCompletionWithRuntime(ctx, a.config.LLM, RunIDFromContext(ctx), []llm.Message{
 		{Role: llm.RoleUser, Content: prompt},
 	}, nil)
 	if err != nil {
@@ -511,7 +553,7 @@ func (r *LLMReflector) Reflect(ctx context.Context, input Input, output Output)
 
 只返回 JSON，不要其他内容。`, input.Query, output.Content)
 
-	resp, err := runCompletionWithRuntime(ctx, r.llm, util.GenerateID("run"), []llm.Message{
+	resp, err := runCompletionWithRuntime(ctx, r.llm, RunIDFromContext(ctx), []llm.Message{
 		{Role: llm.RoleUser, Content: prompt},
 	}, nil)
 	if err != nil {
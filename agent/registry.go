@@ -16,6 +16,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // ============== Agent 注册表 ==============
@@ -321,7 +323,7 @@ func (r *Registry) Deregister(id string) error {
 
 	info, exists := r.agents[id]
 	if !exists {
-		return fmt.Errorf("agent not found: %s", id)
+		return fmt.Errorf("agent %w: %s", core.ErrNotFound, id)
 	}
 
 	delete(r.agents, id)
@@ -581,7 +583,7 @@ func (r *Registry) Heartbeat(id string) error {
 
 	info, exists := r.agents[id]
 	if !exists {
-		return fmt.Errorf("agent not found: %s", id)
+		return fmt.Errorf("agent %w: %s", core.ErrNotFound, id)
 	}
 
 	info.LastHeartbeat = time.Now()
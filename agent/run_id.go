@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/hexagon/internal/util"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+)
+
+// Run ID 和父 Run ID 保存在 observe/logger 的 context 中（logger.ContextWithRunID、
+// logger.ContextWithParentRunID），本文件只记录 ID 是否尚未被运行使用。
+
+// pendingRunIDKey context 中由 ContextWithRunID 指定、尚未被运行使用的 Run ID 的键
+type pendingRunIDKey struct{}
+
+// activeRunID 返回 ctx 中正在执行的运行的 ID，由 ContextWithRunID 指定、尚未使用的 ID 不算
+func activeRunID(ctx context.Context) string {
+	id := logger.RunIDFromContext(ctx)
+	if pending, _ := ctx.Value(pendingRunIDKey{}).(string); pending != "" && pending == id {
+		return ""
+	}
+	return id
+}
+
+// ContextWithRunID 指定下一次运行使用的 Run ID
+//
+// Agent、Team 和 SwarmRunner 的 Run 优先使用 ctx 中指定的 ID，未指定时自动生成。
+// 同一 ID 写入该次运行的所有钩子事件（RunID）和框架日志（run_id），用于关联排查。
+// 在运行内部调用时，原运行成为新运行的父运行。
+//
+// 使用示例：
+//
+//	ctx = agent.ContextWithRunID(ctx, requestID)
+//	output, err := a.Run(ctx, agent.Input{Query: "hi"})
+func ContextWithRunID(ctx context.Context, id string) context.Context {
+	parentID := logger.ParentRunIDFromContext(ctx)
+	if active := activeRunID(ctx); active != "" {
+		parentID = active
+	}
+	ctx = context.WithValue(ctx, pendingRunIDKey{}, id)
+	ctx = logger.ContextWithParentRunID(ctx, parentID)
+	return logger.ContextWithRunID(ctx, id)
+}
+
+// RunIDFromContext 返回 ctx 所属运行的 Run ID
+// 在工具、钩子和嵌套 Agent 中可用于关联当前运行；无运行时返回空字符串
+func RunIDFromContext(ctx context.Context) string {
+	return logger.RunIDFromContext(ctx)
+}
+
+// ParentRunIDFromContext 返回 ctx 所属运行的父 Run ID
+// 团队成员、Swarm 交接的目标 Agent 等嵌套运行的父运行为外层运行；顶层运行返回空字符串
+func ParentRunIDFromContext(ctx context.Context) string {
+	return logger.ParentRunIDFromContext(ctx)
+}
+
+// enterRun 为一次运行分配 Run ID 并写入 ctx
+//
+// 优先使用 ContextWithRunID 指定的 ID；ctx 已处于某次运行中时生成新 ID，
+// 并将外层运行记为父运行。
+func enterRun(ctx context.Context) (context.Context, string) {
+	id := logger.RunIDFromContext(ctx)
+	parentID := logger.ParentRunIDFromContext(ctx)
+	if active := activeRunID(ctx); id == "" || active != "" {
+		id = util.GenerateID("run")
+		parentID = active
+	}
+
+	ctx = context.WithValue(ctx, pendingRunIDKey{}, "")
+	ctx = logger.ContextWithParentRunID(ctx, parentID)
+	return logger.ContextWithRunID(ctx, id), id
+}
+
+// startRun 为 Agent 的一次运行分配 Run ID，并将 run_id 和 agent_id 写入日志上下文
+func (a *BaseAgent) startRun(ctx context.Context) (context.Context, string) {
+	ctx, runID := enterRun(ctx)
+	return logger.ContextWithAgentID(ctx, a.ID()), runID
+}
@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/tool"
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// runRecorder 记录运行开始事件的钩子
+type runRecorder struct {
+	mu     sync.Mutex
+	starts []*hooks.RunStartEvent
+}
+
+func (h *runRecorder) Name() string  { return "run-recorder" }
+func (h *runRecorder) Enabled() bool { return true }
+func (h *runRecorder) OnStart(_ context.Context, e *hooks.RunStartEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.starts = append(h.starts, e)
+	return nil
+}
+func (h *runRecorder) OnEnd(context.Context, *hooks.RunEndEvent) error { return nil }
+func (h *runRecorder) OnError(context.Context, *hooks.ErrorEvent) error {
+	return nil
+}
+
+func TestContextWithRunID_FlowsIntoRun(t *testing.T) {
+	var toolRunID string
+	probe := mock.NewTool("probe", mock.WithToolExecuteFn(func(ctx context.Context, _ map[string]any) (tool.Result, error) {
+		toolRunID = RunIDFromContext(ctx)
+		return tool.NewResult("ok"), nil
+	}))
+	provider := mock.NewLLMProvider("mock").
+		AddToolCallResponse([]llm.ToolCall{{ID: "1", Name: "probe", Arguments: `{}`}}).
+		AddResponse("done")
+
+	recorder := &runRecorder{}
+	manager := hooks.NewManager()
+	manager.RegisterRunHook(recorder)
+	ctx := hooks.ContextWithManager(context.Background(), manager)
+
+	a := NewReAct(WithLLM(provider), WithTools(probe))
+	if _, err := a.Run(ContextWithRunID(ctx, "req-42"), Input{Query: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.starts) != 1 || recorder.starts[0].RunID != "req-42" {
+		t.Fatalf("expected run start with req-42, got %+v", recorder.starts)
+	}
+	if recorder.starts[0].ParentRunID != "" {
+		t.Errorf("expected top-level run without parent, got %q", recorder.starts[0].ParentRunID)
+	}
+	if toolRunID != "req-42" {
+		t.Errorf("expected tool to see run ID req-42, got %q", toolRunID)
+	}
+}
+
+func TestEnterRun_Generated(t *testing.T) {
+	ctx, first := enterRun(context.Background())
+	if first == "" || RunIDFromContext(ctx) != first {
+		t.Fatalf("expected generated run ID in context, got %q", first)
+	}
+	if ParentRunIDFromContext(ctx) != "" {
+		t.Error("expected no parent for top-level run")
+	}
+
+	// 运行内部的运行生成新 ID，并以外层运行为父运行
+	child, second := enterRun(ctx)
+	if second == first {
+		t.Error("expected nested run to get a new ID")
+	}
+	if ParentRunIDFromContext(child) != first {
+		t.Errorf("expected parent %q, got %q", first, ParentRunIDFromContext(child))
+	}
+
+	// 运行内部显式指定的 ID 同样以外层运行为父运行
+	explicit, third := enterRun(ContextWithRunID(ctx, "sub-1"))
+	if third != "sub-1" || ParentRunIDFromContext(explicit) != first {
+		t.Errorf("expected sub-1 with parent %q, got %q with parent %q", first, third, ParentRunIDFromContext(explicit))
+	}
+}
+
+func TestRunID_SharedWithLogger(t *testing.T) {
+	// Run ID 只有一份，agent 与 observe/logger 读写同一个 context 值
+	ctx, id := enterRun(context.Background())
+	if logger.RunIDFromContext(ctx) != id {
+		t.Errorf("expected logger to see run ID %q, got %q", id, logger.RunIDFromContext(ctx))
+	}
+
+	// 外部通过 logger 写入的 Run ID 视为正在执行的运行
+	outer := logger.ContextWithRunID(context.Background(), "req-7")
+	if RunIDFromContext(outer) != "req-7" {
+		t.Errorf("expected agent to see logger run ID, got %q", RunIDFromContext(outer))
+	}
+	child, childID := enterRun(outer)
+	if childID == "req-7" || ParentRunIDFromContext(child) != "req-7" {
+		t.Errorf("expected nested run under req-7, got %q with parent %q", childID, ParentRunIDFromContext(child))
+	}
+}
+
+func TestTeamRun_MemberRunsAreChildren(t *testing.T) {
+	recorder := &runRecorder{}
+	manager := hooks.NewManager()
+	manager.RegisterRunHook(recorder)
+	ctx := hooks.ContextWithManager(context.Background(), manager)
+
+	team := NewTeam("team", WithAgents(
+		NewReAct(WithLLM(mock.NewLLMProvider("a").AddResponse("first"))),
+		NewReAct(WithLLM(mock.NewLLMProvider("b").AddResponse("second"))),
+	))
+	if _, err := team.Run(ContextWithRunID(ctx, "team-run"), Input{Query: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.starts) != 2 {
+		t.Fatalf("expected 2 member runs, got %d", len(recorder.starts))
+	}
+	for _, e := range recorder.starts {
+		if e.ParentRunID != "team-run" {
+			t.Errorf("expected member parent team-run, got %q", e.ParentRunID)
+		}
+		if e.RunID == "team-run" || e.RunID == "" {
+			t.Errorf("expected member to get its own run ID, got %q", e.RunID)
+		}
+	}
+	if recorder.starts[0].RunID == recorder.starts[1].RunID {
+		t.Error("expected distinct member run IDs")
+	}
+}
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+	agentruntime "github.com/hexagon-codes/hexagon/runtime"
+)
+
+// ============== 单次运行采样参数 ==============
+
+// runSamplingExtraKey core.Options.Extra 中记录采样参数的键
+const runSamplingExtraKey = "agent.run_sampling"
+
+// WithRunTemperature 设置本次运行的采样温度，覆盖 Provider 的默认值
+//
+// 与其他单次运行选项一样，传给 Invoke/Stream，或通过 ContextWithRunOptions 传给 Run：
+//
+//	output, err := a.Invoke(ctx, input, agent.WithRunTemperature(0), agent.WithRunSeed(42))
+func WithRunTemperature(t float64) core.Option {
+	return runSamplingOption(func(s *agentruntime.Sampling) {
+		s.Temperature = &t
+	})
+}
+
+// WithRunSeed 设置本次运行的随机种子
+//
+// llm.CompletionRequest 没有种子字段，种子通过请求元数据 "seed" 传给 Provider，
+// 只有实现 runtime.SeedSupporter 的 Provider 会使用。其他 Provider 忽略种子，
+// 此时运行开始时记录一条警告，种子只参与精确缓存的键和 LLMStartEvent.Seed。
+func WithRunSeed(seed int64) core.Option {
+	return runSamplingOption(func(s *agentruntime.Sampling) {
+		s.Seed = &seed
+	})
+}
+
+// WithRunMaxTokens 设置本次运行每次 LLM 调用的最大生成 Token 数
+func WithRunMaxTokens(n int) core.Option {
+	return runSamplingOption(func(s *agentruntime.Sampling) {
+		if n > 0 {
+			s.MaxTokens = n
+		}
+	})
+}
+
+// runSamplingOption 创建修改采样参数的选项，多个选项按顺序合并
+func runSamplingOption(fn func(*agentruntime.Sampling)) core.Option {
+	return core.OptionFunc(func(o *core.Options) {
+		if o.Extra == nil {
+			o.Extra = make(map[string]any)
+		}
+		s, _ := o.Extra[runSamplingExtraKey].(agentruntime.Sampling)
+		fn(&s)
+		o.Extra[runSamplingExtraKey] = s
+	})
+}
+
+// ContextWithRunOptions 为下一次 Run 设置单次运行选项
+//
+// Agent 接口的 Run 不接受选项，评测等需要固定采样参数的场景可通过 context 传入，
+// 不修改共享的 Agent 配置。选项只作用于下一次运行，不会传递给其中的
+// Agent 工具、团队成员等嵌套运行。
+//
+// 使用示例：
+//
+//	ctx = agent.ContextWithRunOptions(ctx, agent.WithRunTemperature(0), agent.WithRunSeed(42))
+//	output, err := a.Run(ctx, agent.Input{Query: "hi"})
+func ContextWithRunOptions(ctx context.Context, opts ...core.Option) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	s, ok := core.ApplyOptions(opts...).Extra[runSamplingExtraKey].(agentruntime.Sampling)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, runSamplingKey{}, mergeSampling(samplingFromContext(ctx), s))
+}
+
+// runSamplingKey 采样参数的 context key
+type runSamplingKey struct{}
+
+// samplingFromContext 获取 context 中的采样参数
+func samplingFromContext(ctx context.Context) agentruntime.Sampling {
+	s, _ := ctx.Value(runSamplingKey{}).(agentruntime.Sampling)
+	return s
+}
+
+// activeSamplingKey 当前运行生效的采样参数的 context key
+// 由 takeRunSampling 设置，completeLLM 和 runCompletionWithRuntime 据此设置每次 LLM 调用
+type activeSamplingKey struct{}
+
+// activeSampling 获取当前运行生效的采样参数
+func activeSampling(ctx context.Context) agentruntime.Sampling {
+	s, _ := ctx.Value(activeSamplingKey{}).(agentruntime.Sampling)
+	return s
+}
+
+// takeRunSampling 取出本次运行的采样参数，并从 ctx 中移除，避免传递给嵌套运行
+//
+// 返回的 ctx 中记录本次运行生效的采样参数（覆盖上级运行的设置），
+// 运行中经 completeLLM 和 runCompletionWithRuntime 的 LLM 调用都会使用。
+// provider 不支持随机种子时记录警告。
+func takeRunSampling(ctx context.Context, provider llm.Provider) (context.Context, agentruntime.Sampling) {
+	s := samplingFromContext(ctx)
+	if s.IsZero() && activeSampling(ctx).IsZero() {
+		return ctx, s
+	}
+	if s.Seed != nil && provider != nil && !agentruntime.SupportsSeed(provider) {
+		logger.FromContext(ctx).WarnContext(ctx, "run seed is ignored by provider",
+			"provider", provider.Name(), "seed", *s.Seed)
+	}
+	ctx = context.WithValue(ctx, runSamplingKey{}, agentruntime.Sampling{})
+	return context.WithValue(ctx, activeSamplingKey{}, s), s
+}
+
+// mergeSampling 合并采样参数，override 中设置的项优先
+func mergeSampling(base, override agentruntime.Sampling) agentruntime.Sampling {
+	if override.Temperature != nil {
+		base.Temperature = override.Temperature
+	}
+	if override.MaxTokens > 0 {
+		base.MaxTokens = override.MaxTokens
+	}
+	if override.Seed != nil {
+		base.Seed = override.Seed
+	}
+	return base
+}
+
+// sampledRequest 将采样参数应用到直接调用 LLM 的请求
+func sampledRequest(req llm.CompletionRequest, s agentruntime.Sampling) llm.CompletionRequest {
+	if s.Temperature != nil {
+		req.Temperature = s.Temperature
+	}
+	if s.MaxTokens > 0 {
+		req.MaxTokens = s.MaxTokens
+	}
+	if s.Seed != nil {
+		metadata := make(map[string]any, len(req.Metadata)+1)
+		for k, v := range req.Metadata {
+			metadata[k] = v
+		}
+		metadata[agentruntime.MetadataSeed] = *s.Seed
+		req.Metadata = metadata
+	}
+	return req
+}
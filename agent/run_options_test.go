@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+	agentruntime "github.com/hexagon-codes/hexagon/runtime"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func TestRunOptions_OverrideSampling(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").AddResponse("first").AddResponse("second")
+	recorder := &llmStartRecorder{}
+	manager := hooks.NewManager()
+	manager.RegisterLLMHook(recorder)
+	ctx := hooks.ContextWithManager(context.Background(), manager)
+
+	a := NewReAct(WithLLM(provider))
+	if _, err := a.Invoke(ctx, Input{Query: "hi"},
+		WithRunTemperature(0), WithRunSeed(42), WithRunMaxTokens(128)); err != nil {
+		t.Fatal(err)
+	}
+
+	req := provider.LastCall()
+	if req.Temperature == nil || *req.Temperature != 0 {
+		t.Errorf("expected temperature 0, got %v", req.Temperature)
+	}
+	if req.MaxTokens != 128 {
+		t.Errorf("expected max tokens 128, got %d", req.MaxTokens)
+	}
+	if req.Metadata[agentruntime.MetadataSeed] != int64(42) {
+		t.Errorf("expected seed 42 in metadata, got %v", req.Metadata)
+	}
+	if len(recorder.starts) != 1 || recorder.starts[0].Seed == nil || *recorder.starts[0].Seed != 42 {
+		t.Fatalf("expected seed recorded in LLMStartEvent, got %+v", recorder.starts)
+	}
+
+	// 覆盖只作用于本次运行
+	if _, err := a.Run(ctx, Input{Query: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	req = provider.LastCall()
+	if req.Temperature != nil || req.MaxTokens != 0 || req.Metadata[agentruntime.MetadataSeed] != nil {
+		t.Errorf("expected agent defaults on the next run, got %+v", req)
+	}
+}
+
+func TestContextWithRunOptions_NotInheritedByNestedRuns(t *testing.T) {
+	subProvider := mock.NewLLMProvider("sub").AddResponse("sub answer")
+	sub := NewReAct(WithName("sub"), WithLLM(subProvider))
+
+	provider := mock.NewLLMProvider("parent").
+		AddToolCallResponse([]llm.ToolCall{{ID: "1", Name: "agent_sub", Arguments: `{"message":"go"}`}}).
+		AddResponse("done")
+	a := NewReAct(WithLLM(provider), WithTools(AgentAsTool(sub)))
+
+	ctx := ContextWithRunOptions(context.Background(), WithRunTemperature(0.2))
+	if _, err := a.Run(ctx, Input{Query: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, call := range provider.Calls() {
+		if call.Temperature == nil || *call.Temperature != 0.2 {
+			t.Errorf("expected every parent call to use temperature 0.2, got %v", call.Temperature)
+		}
+	}
+	if got := subProvider.LastCall(); got == nil || got.Temperature != nil {
+		t.Errorf("expected nested run to keep its own defaults, got %+v", got)
+	}
+}
+
+func TestRunOptions_AppliedByAllAgents(t *testing.T) {
+	tests := []struct {
+		name  string
+		agent func(provider llm.Provider) Agent
+	}{
+		{"reflection", func(p llm.Provider) Agent {
+			return NewReflection([]Option{WithLLM(p)}, WithReflectionMaxIterations(1))
+		}},
+		{"self discovery", func(p llm.Provider) Agent {
+			return NewSelfDiscovery([]Option{WithLLM(p)})
+		}},
+		{"plan execute", func(p llm.Provider) Agent {
+			return NewPlanExecute([]Option{WithLLM(p)})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := mock.NewLLMProvider("mock").WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+				return &llm.CompletionResponse{Content: `{"steps": [{"description": "answer"}]}`}, nil
+			})
+			ctx := ContextWithRunOptions(context.Background(), WithRunTemperature(0.1), WithRunMaxTokens(64))
+			if _, err := tt.agent(provider).Run(ctx, Input{Query: "hi"}); err != nil {
+				t.Fatal(err)
+			}
+			if provider.CallCount() == 0 {
+				t.Fatal("expected LLM calls")
+			}
+			for i, call := range provider.Calls() {
+				if call.Temperature == nil || *call.Temperature != 0.1 || call.MaxTokens != 64 {
+					t.Errorf("call %d: expected run sampling, got temperature=%v max_tokens=%d", i, call.Temperature, call.MaxTokens)
+				}
+			}
+		})
+	}
+}
+
+func TestRunOptions_SeedUnsupportedWarning(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := logger.ContextWithLogger(context.Background(), logger.FromSlog(slog.New(slog.NewTextHandler(&buf, nil))))
+
+	for _, supported := range []bool{false, true} {
+		buf.Reset()
+		provider := mock.NewLLMProvider("mock").AddResponse("ok")
+		if supported {
+			provider.WithSeedSupport()
+		}
+		if _, err := NewBaseAgent(WithLLM(provider)).Invoke(ctx, Input{Query: "hi"}, WithRunSeed(7)); err != nil {
+			t.Fatal(err)
+		}
+		if warned := strings.Contains(buf.String(), "run seed is ignored"); warned == supported {
+			t.Errorf("supported=%v: unexpected warning state, log: %s", supported, buf.String())
+		}
+	}
+}
@@ -6,30 +6,54 @@ import (
 	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/hooks"
 	"github.com/hexagon-codes/hexagon/internal/util"
+	"github.com/hexagon-codes/hexagon/llm/limiter"
 	agentruntime "github.com/hexagon-codes/hexagon/runtime"
 )
 
+// completeLLM 在限流器许可下直接调用 LLM
+// 限流器来自 context 或全局设置（见 llm/limiter），未配置时不限流。
+// context 中有预算计数器时，调用前检查预算，Token 使用计入计数器；
+// 当前运行设置了采样参数（见 WithRunTemperature）时应用到请求。
+func completeLLM(ctx context.Context, provider llm.Provider, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	tracker := budgetFromContext(ctx)
+	if err := tracker.check(); err != nil {
+		return nil, err
+	}
+	resp, err := limiter.Complete(ctx, provider, sampledRequest(req, activeSampling(ctx)))
+	if resp != nil {
+		tracker.addTokens(resp.Usage)
+	}
+	return resp, core.ClassifyError(err)
+}
+
 func runCompletionWithRuntime(ctx context.Context, provider llm.Provider, runID string, messages []llm.Message, sink agentruntime.EventSink) (*llm.CompletionResponse, error) {
 	if provider == nil {
-		return nil, fmt.Errorf("LLM provider not configured")
+		return nil, fmt.Errorf("LLM provider %w", core.ErrNotConfigured)
 	}
 	if runID == "" {
 		runID = util.GenerateID("run")
 	}
 
+	var middleware []agentruntime.Middleware
+	if tracker := budgetFromContext(ctx); tracker != nil {
+		middleware = (&budgetRun{tracker: tracker}).middleware()
+	}
 	runner := agentruntime.NewRunner(agentruntime.Config{
 		ProviderSelector: agentruntime.StaticProviderSelector{
 			Provider: provider,
 			Name:     provider.Name(),
 		},
+		Middleware:      middleware,
 		DefaultMaxTurns: 1,
 	})
 	result, err := runner.RunWithSink(ctx, agentruntime.Request{
 		ID:         runID,
 		Messages:   append([]llm.Message(nil), messages...),
 		Limits:     agentruntime.Limits{MaxTurns: 1},
+		Sampling:   activeSampling(ctx),
 		StreamMode: agentruntime.StreamModeEvents,
 	}, sink)
 	if err != nil {
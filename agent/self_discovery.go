@@ -10,7 +10,6 @@ import (
 	"github.com/hexagon-codes/ai-core/llm"
 	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/hooks"
-	"github.com/hexagon-codes/hexagon/internal/util"
 	"github.com/hexagon-codes/hexagon/stream"
 )
 
@@ -207,11 +206,31 @@ func NewSelfDiscovery(opts []Option, sdOpts ...SelfDiscoveryOption) *SelfDiscove
 //  4. EXECUTE: 使用结构执行推理
 func (a *SelfDiscoveryAgent) Run(ctx context.Context, input Input) (Output, error) {
 	if a.config.LLM == nil {
-		return Output{}, fmt.Errorf("LLM provider not configured")
+		return Output{}, fmt.Errorf("LLM provider %w", core.ErrNotConfigured)
 	}
 
-	// 生成运行 ID
-	runID := util.GenerateID("run")
+	ctx, done, err := a.beginRun(ctx)
+	if err != nil {
+		return Output{}, err
+	}
+	defer done()
+
+	ctx, _ = takeRunSampling(ctx, a.config.LLM)
+	ctx, budget, cancel := a.beginBudget(ctx)
+	defer cancel()
+	output, err := a.run(ctx, input)
+	return output, budget.err(ctx, err)
+}
+
+// run 执行一次运行，预算计数由 Run 开启
+func (a *SelfDiscoveryAgent) run(ctx context.Context, input Input) (Output, error) {
+	cached, cacheRun, hit := a.cacheLookup(ctx, input, nil)
+	if hit {
+		return cached, nil
+	}
+
+	// 分配运行 ID
+	ctx, runID := a.startRun(ctx)
 	startTime := time.Now()
 
 	// 获取钩子管理器
@@ -220,9 +239,10 @@ func (a *SelfDiscoveryAgent) Run(ctx context.Context, input Input) (Output, erro
 	// 触发运行开始钩子
 	if hookManager != nil {
 		if err := hookManager.TriggerRunStart(ctx, &hooks.RunStartEvent{
-			RunID:   runID,
-			AgentID: a.ID(),
-			Input:   input,
+			RunID:       runID,
+			ParentRunID: ParentRunIDFromContext(ctx),
+			AgentID:     a.ID(),
+			Input:       input,
 		}); err != nil {
 			return Output{}, fmt.Errorf("run start hook failed: %w", err)
 		}
@@ -271,13 +291,30 @@ func (a *SelfDiscoveryAgent) Run(ctx context.Context, input Input) (Output, erro
 		},
 	}
 
+	if a.config.OutputParser != nil {
+		systemPrompt, err := a.systemPrompt(ctx, input)
+		if err != nil {
+			return Output{}, err
+		}
+		if output, err = a.parseOutput(ctx, input, systemPrompt, output); err != nil {
+			return Output{}, err
+		}
+	}
+
+	output, err = a.guardOutput(ctx, output)
+	if err != nil {
+		return Output{}, err
+	}
+	a.cacheStore(ctx, cacheRun, input, output)
+
 	// 触发运行结束钩子
 	if hookManager != nil {
 		hookManager.TriggerRunEnd(ctx, &hooks.RunEndEvent{
-			RunID:    runID,
-			AgentID:  a.ID(),
-			Output:   output,
-			Duration: time.Since(startTime).Milliseconds(),
+			RunID:       runID,
+			ParentRunID: ParentRunIDFromContext(ctx),
+			AgentID:     a.ID(),
+			Output:      output,
+			Duration:    time.Since(startTime).Milliseconds(),
 		})
 	}
 
@@ -312,7 +349,7 @@ func (a *SelfDiscoveryAgent) selectModules(ctx context.Context, input Input) ([]
 
 只返回 JSON，不要其他内容。`, input.Query, modulesDesc.String(), a.maxModules)
 
-	resp, err := a.config.LLM.Complete(ctx, llm.CompletionRequest{
+	resp, err := completeLLM(ctx, a.config.LLM, llm.CompletionRequest{
 		Messages: []llm.Message{
 			{Role: llm.RoleUser, Content: prompt},
 		},
@@ -398,7 +435,7 @@ func (a *SelfDiscoveryAgent) adaptModules(ctx context.Context, input Input, modu
 
 只返回 JSON，不要其他内容。`, input.Query, modulesInfo.String())
 
-	resp, err := a.config.LLM.Complete(ctx, llm.CompletionRequest{
+	resp, err := completeLLM(ctx, a.config.LLM, llm.CompletionRequest{
 		Messages: []llm.Message{
 			{Role: llm.RoleUser, Content: prompt},
 		},
@@ -473,7 +510,7 @@ func (a *SelfDiscoveryAgent) implementStructure(ctx context.Context, input Input
 
 返回一个结构化的推理计划（纯文本，不需要 JSON）。`, input.Query, modulesInfo.String())
 
-	resp, err := a.config.LLM.Complete(ctx, llm.CompletionRequest{
+	resp, err := completeLLM(ctx, a.config.LLM, llm.CompletionRequest{
 		Messages: []llm.Message{
 			{Role: llm.RoleUser, Content: prompt},
 		},
@@ -487,6 +524,11 @@ func (a *SelfDiscoveryAgent) implementStructure(ctx context.Context, input Input
 
 // executeReasoning EXECUTE 阶段 - 使用推理结构执行推理
 func (a *SelfDiscoveryAgent) executeReasoning(ctx context.Context, input Input, reasoningStructure string) (string, llm.Usage, error) {
+	systemPrompt, err := a.systemPrompt(ctx, input)
+	if err != nil {
+		return "", llm.Usage{}, err
+	}
+
 	prompt := fmt.Sprintf(`请使用以下推理结构来解决任务。
 
 任务: %s
@@ -497,10 +539,10 @@ func (a *SelfDiscoveryAgent) executeReasoning(ctx context.Context, input Input,
 请按照推理结构逐步思考，然后给出最终答案。`, input.Query, reasoningStructure)
 
 	messages := []llm.Message{}
-	if a.config.SystemPrompt != "" {
+	if systemPrompt != "" {
 		messages = append(messages, llm.Message{
 			Role:    llm.RoleSystem,
-			Content: a.config.SystemPrompt,
+			Content: systemPrompt,
 		})
 	}
 	messages = append(messages, llm.Message{
@@ -508,7 +550,7 @@ func (a *SelfDiscoveryAgent) executeReasoning(ctx context.Context, input Input,
 		Content: prompt,
 	})
 
-	resp, err := a.config.LLM.Complete(ctx, llm.CompletionRequest{
+	resp, err := completeLLM(ctx, a.config.LLM, llm.CompletionRequest{
 		Messages: messages,
 	})
 	if err != nil {
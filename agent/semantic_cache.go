@@ -0,0 +1,318 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync/atomic"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// MetadataSemanticCache 命中语义缓存时，Output.Metadata 中记录命中信息的键
+// 值为 *SemanticCacheHit
+const MetadataSemanticCache = "semantic_cache"
+
+// 语义缓存文档的元数据键
+const (
+	semanticCacheScopeKey   = "semantic_cache_scope"
+	semanticCacheOutputKey  = "semantic_cache_output"
+	semanticCacheExpiresKey = "semantic_cache_expires_at"
+)
+
+// SemanticCache 基于向量相似度的回复缓存
+//
+// 将查询向量化后连同回复写入 vector.Store；新查询与最近邻的相似度达到阈值时
+// 直接返回缓存的回复，适合 FAQ 类近似重复的查询。
+// 相似度取自 Store 搜索结果的 Score，要求 Store 返回余弦相似度（越大越相似）。
+//
+// 一个 SemanticCache 可以被多个 Agent 共享，缓存按 Agent 名称（或 WithCacheScope）隔离，
+// 持久化的 Store 在进程重启后仍能命中。
+type SemanticCache struct {
+	embedder  vector.Embedder
+	store     vector.Store
+	ttl       time.Duration
+	threshold float64
+	now       func() time.Time
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// SemanticCacheOption SemanticCache 配置选项
+type SemanticCacheOption func(*SemanticCache)
+
+// WithSemanticCacheTTL 设置缓存有效期，<= 0 表示不过期
+func WithSemanticCacheTTL(ttl time.Duration) SemanticCacheOption {
+	return func(c *SemanticCache) {
+		c.ttl = ttl
+	}
+}
+
+// WithSemanticCacheThreshold 设置默认相似度阈值（0-1），默认 0.95
+func WithSemanticCacheThreshold(threshold float64) SemanticCacheOption {
+	return func(c *SemanticCache) {
+		c.threshold = threshold
+	}
+}
+
+// NewSemanticCache 创建语义缓存
+func NewSemanticCache(embedder vector.Embedder, store vector.Store, opts ...SemanticCacheOption) *SemanticCache {
+	c := &SemanticCache{
+		embedder:  embedder,
+		store:     store,
+		ttl:       24 * time.Hour,
+		threshold: 0.95,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SemanticCacheHit 缓存命中信息
+type SemanticCacheHit struct {
+	// Query 命中的缓存查询
+	Query string `json:"query"`
+
+	// Similarity 与当前查询的相似度
+	Similarity float64 `json:"similarity"`
+}
+
+// SemanticCacheStats 缓存统计
+type SemanticCacheStats struct {
+	// Hits 命中次数
+	Hits int64 `json:"hits"`
+
+	// Misses 未命中次数
+	Misses int64 `json:"misses"`
+
+	// HitRate 命中率
+	HitRate float64 `json:"hit_rate"`
+}
+
+// Stats 返回缓存统计
+func (c *SemanticCache) Stats() SemanticCacheStats {
+	hits, misses := c.hits.Load(), c.misses.Load()
+	stats := SemanticCacheStats{Hits: hits, Misses: misses}
+	if total := hits + misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	return stats
+}
+
+// Lookup 查找与 query 语义相近的缓存回复
+// scope 用于隔离不同调用方的缓存；threshold <= 0 时使用默认阈值
+func (c *SemanticCache) Lookup(ctx context.Context, scope, query string, threshold float64) (Output, bool, error) {
+	embedding, err := c.embedder.EmbedOne(ctx, query)
+	if err != nil {
+		return Output{}, false, fmt.Errorf("semantic cache: embed query: %w", err)
+	}
+	return c.lookup(ctx, scope, embedding, threshold)
+}
+
+// Store 缓存 query 的回复
+func (c *SemanticCache) Store(ctx context.Context, scope, query string, output Output) error {
+	embedding, err := c.embedder.EmbedOne(ctx, query)
+	if err != nil {
+		return fmt.Errorf("semantic cache: embed query: %w", err)
+	}
+	return c.put(ctx, scope, query, embedding, output)
+}
+
+// lookup 按查询向量查找缓存，过期条目会被删除
+func (c *SemanticCache) lookup(ctx context.Context, scope string, embedding []float32, threshold float64) (Output, bool, error) {
+	if threshold <= 0 {
+		threshold = c.threshold
+	}
+
+	docs, err := c.store.Search(ctx, embedding, 5,
+		vector.WithFilter(map[string]any{semanticCacheScopeKey: scope}),
+		vector.WithMinScore(float32(threshold)),
+	)
+	if err != nil {
+		return Output{}, false, fmt.Errorf("semantic cache: search: %w", err)
+	}
+
+	now := c.now()
+	var expired []string
+	defer func() {
+		if len(expired) > 0 {
+			_ = c.store.Delete(context.WithoutCancel(ctx), expired)
+		}
+	}()
+
+	for _, doc := range docs {
+		if float64(doc.Score) < threshold {
+			continue
+		}
+		if exp := expiresAt(doc.Metadata); exp > 0 && now.UnixNano() > exp {
+			expired = append(expired, doc.ID)
+			continue
+		}
+		raw, _ := doc.Metadata[semanticCacheOutputKey].(string)
+		var output Output
+		if err := json.Unmarshal([]byte(raw), &output); err != nil {
+			continue
+		}
+		if output.Metadata == nil {
+			output.Metadata = make(map[string]any)
+		}
+		output.Metadata[MetadataSemanticCache] = &SemanticCacheHit{
+			Query:      doc.Content,
+			Similarity: float64(doc.Score),
+		}
+		c.hits.Add(1)
+		return output, true, nil
+	}
+
+	c.misses.Add(1)
+	return Output{}, false, nil
+}
+
+// put 写入缓存，同一 scope 下相同查询会覆盖旧值
+func (c *SemanticCache) put(ctx context.Context, scope, query string, embedding []float32, output Output) error {
+	// 命中信息和用量不属于回复本身
+	output.Usage = llm.Usage{}
+	if _, ok := output.Metadata[MetadataSemanticCache]; ok {
+		output.Metadata = maps.Clone(output.Metadata)
+		delete(output.Metadata, MetadataSemanticCache)
+	}
+	raw, err := json.Marshal(output)
+	if err != nil {
+		return fmt.Errorf("semantic cache: encode output: %w", err)
+	}
+
+	metadata := map[string]any{
+		semanticCacheScopeKey:  scope,
+		semanticCacheOutputKey: string(raw),
+	}
+	if c.ttl > 0 {
+		metadata[semanticCacheExpiresKey] = c.now().Add(c.ttl).UnixNano()
+	}
+
+	sum := sha256.Sum256([]byte(scope + "\x00" + query))
+	if err := c.store.Add(ctx, []vector.Document{{
+		ID:        "semcache-" + hex.EncodeToString(sum[:16]),
+		Content:   query,
+		Embedding: embedding,
+		Metadata:  metadata,
+	}}); err != nil {
+		return fmt.Errorf("semantic cache: store: %w", err)
+	}
+	return nil
+}
+
+// expiresAt 读取过期时间（UnixNano），0 表示不过期
+// 持久化的 Store 可能将整数反序列化为 float64
+func expiresAt(metadata map[string]any) int64 {
+	switch v := metadata[semanticCacheExpiresKey].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
+
+// WithSemanticCache 为 Agent 启用语义缓存
+//
+// 运行前查找语义相近的历史查询，相似度达到 threshold（<= 0 时使用缓存的默认阈值）
+// 时直接返回缓存的回复；未命中时正常运行并缓存成功的回复。
+//
+// 以下运行不使用缓存，因为相同问题的答案可能不同：
+//   - 带 Input.Context 的运行
+//   - 读取历史且历史非空的运行（Agent 的 Memory、ContextWithMemory 或 ThreadID 对应的线程）
+//
+// 配置了 WithToolPolicy 时，缓存按授权主体隔离，不同主体不会共享回复。
+func WithSemanticCache(cache *SemanticCache, threshold float64) Option {
+	return func(c *Config) {
+		c.SemanticCache = cache
+		c.SemanticCacheThreshold = threshold
+	}
+}
+
+// WithCacheScope 设置语义缓存的隔离范围，默认为 Agent 名称
+// 同名但行为不同的 Agent 共享一个 SemanticCache 时应设置不同的范围
+func WithCacheScope(scope string) Option {
+	return func(c *Config) {
+		c.CacheScope = scope
+	}
+}
+
+// semanticCacheRun 单次运行的缓存状态
+type semanticCacheRun struct {
+	scope     string
+	embedding []float32
+}
+
+// cacheScope 返回本次运行的缓存范围：WithCacheScope 或 Agent 名称，
+// 配置了工具策略时附加授权主体的哈希
+func (a *BaseAgent) cacheScope(ctx context.Context) string {
+	scope := a.config.CacheScope
+	if scope == "" {
+		scope = a.Name()
+	}
+	if policy := a.config.ToolPolicy; policy != nil {
+		sum := sha256.Sum256([]byte(policy.subject(ctx)))
+		scope += "/" + hex.EncodeToString(sum[:8])
+	}
+	return scope
+}
+
+// hasHistory 报告 mem 中是否有历史记录，查询失败时视为有
+func hasHistory(ctx context.Context, mem memory.Memory) bool {
+	if mem == nil {
+		return false
+	}
+	entries, err := mem.Search(ctx, memory.SearchQuery{Limit: 1})
+	return err != nil || len(entries) > 0
+}
+
+// cacheLookup 运行前查找语义缓存
+// history 为本次运行读取的历史记忆（不读取历史的 Agent 传 nil），非空时不使用缓存。
+// 缓存故障只记录日志，不影响运行；返回的 run 为 nil 表示本次运行不写缓存
+func (a *BaseAgent) cacheLookup(ctx context.Context, input Input, history memory.Memory) (Output, *semanticCacheRun, bool) {
+	cache := a.config.SemanticCache
+	if cache == nil || len(input.Context) > 0 || input.Query == "" || hasHistory(ctx, history) {
+		return Output{}, nil, false
+	}
+
+	embedding, err := cache.embedder.EmbedOne(ctx, input.Query)
+	if err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "semantic cache embed failed",
+			logger.String("agent_name", a.Name()), logger.Err(err))
+		return Output{}, nil, false
+	}
+
+	scope := a.cacheScope(ctx)
+	output, hit, err := cache.lookup(ctx, scope, embedding, a.config.SemanticCacheThreshold)
+	if err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "semantic cache lookup failed",
+			logger.String("agent_name", a.Name()), logger.Err(err))
+	}
+	if hit {
+		return output, nil, true
+	}
+	return Output{}, &semanticCacheRun{scope: scope, embedding: embedding}, false
+}
+
+// cacheStore 运行成功后写入语义缓存
+func (a *BaseAgent) cacheStore(ctx context.Context, run *semanticCacheRun, input Input, output Output) {
+	if run == nil {
+		return
+	}
+	if err := a.config.SemanticCache.put(ctx, run.scope, input.Query, run.embedding, output); err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "semantic cache store failed",
+			logger.String("agent_name", a.Name()), logger.Err(err))
+	}
+}
@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/hexagon/security/rbac"
+	"github.com/hexagon-codes/hexagon/store/vector"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// bagOfWordsEmbedder 按词哈希生成向量，词集合相同的查询向量相同
+func bagOfWordsEmbedder() vector.Embedder {
+	return vector.NewEmbedderFunc(32, func(_ context.Context, texts []string) ([][]float32, error) {
+		out := make([][]float32, len(texts))
+		for i, text := range texts {
+			vec := make([]float32, 32)
+			for _, word := range strings.Fields(strings.ToLower(strings.Trim(text, "?!. "))) {
+				h := fnv.New32a()
+				h.Write([]byte(word))
+				vec[h.Sum32()%32]++
+			}
+			out[i] = vec
+		}
+		return out, nil
+	})
+}
+
+func TestReActAgentSemanticCache(t *testing.T) {
+	cache := NewSemanticCache(bagOfWordsEmbedder(), vector.NewMemoryStore(32))
+
+	mockLLM := mock.NewLLMProvider("cache")
+	mockLLM.AddResponse("30 days")
+	mockLLM.AddResponse("weekdays 9-5")
+
+	a := NewReAct(WithLLM(mockLLM), WithSemanticCache(cache, 0.9))
+	// 每次运行使用独立的空记忆，模拟无状态的 FAQ 请求
+	ctx := ContextWithMemory(context.Background(), memory.NewBuffer(10))
+
+	first, err := a.Run(ctx, Input{Query: "What is the refund policy?"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if _, ok := first.Metadata[MetadataSemanticCache]; ok {
+		t.Error("first run should not be a cache hit")
+	}
+
+	ctx = ContextWithMemory(context.Background(), memory.NewBuffer(10))
+	second, err := a.Run(ctx, Input{Query: "what is the refund policy"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if second.Content != "30 days" {
+		t.Errorf("expected cached content, got %q", second.Content)
+	}
+	hit, ok := second.Metadata[MetadataSemanticCache].(*SemanticCacheHit)
+	if !ok || hit.Query != "What is the refund policy?" {
+		t.Errorf("expected cache hit metadata, got %+v", second.Metadata)
+	}
+	if second.Usage.TotalTokens != 0 {
+		t.Errorf("cache hit should report no usage, got %+v", second.Usage)
+	}
+
+	ctx = ContextWithMemory(context.Background(), memory.NewBuffer(10))
+	third, err := a.Run(ctx, Input{Query: "When are you open?"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if third.Content != "weekdays 9-5" {
+		t.Errorf("expected fresh response, got %q", third.Content)
+	}
+
+	if mockLLM.CallCount() != 2 {
+		t.Errorf("expected 2 LLM calls, got %d", mockLLM.CallCount())
+	}
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSemanticCacheTTLAndScope(t *testing.T) {
+	store := vector.NewMemoryStore(32)
+	cache := NewSemanticCache(bagOfWordsEmbedder(), store, WithSemanticCacheTTL(time.Minute))
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if err := cache.Store(ctx, "a", "hello world", Output{Content: "hi"}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	if _, ok, _ := cache.Lookup(ctx, "b", "hello world", 0); ok {
+		t.Error("lookup in another scope should miss")
+	}
+	out, ok, err := cache.Lookup(ctx, "a", "Hello world!", 0)
+	if err != nil || !ok || out.Content != "hi" {
+		t.Fatalf("expected hit, got %+v ok=%v err=%v", out, ok, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := cache.Lookup(ctx, "a", "hello world", 0); ok {
+		t.Error("expired entry should miss")
+	}
+	if n, _ := store.Count(ctx); n != 0 {
+		t.Errorf("expired entry should be deleted, %d left", n)
+	}
+}
+
+func TestReActAgentSemanticCache_Isolation(t *testing.T) {
+	cache := NewSemanticCache(bagOfWordsEmbedder(), vector.NewMemoryStore(32))
+	freshCtx := func() context.Context {
+		return ContextWithMemory(context.Background(), memory.NewBuffer(10))
+	}
+
+	// 按名称隔离：新实例（如进程重启后）仍能命中
+	first := NewReAct(WithName("faq"), WithLLM(mock.FixedProvider("30 days")), WithSemanticCache(cache, 0.9))
+	if _, err := first.Run(freshCtx(), Input{Query: "refund policy"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	restartedLLM := mock.FixedProvider("fresh")
+	restarted := NewReAct(WithName("faq"), WithLLM(restartedLLM), WithSemanticCache(cache, 0.9))
+	out, err := restarted.Run(freshCtx(), Input{Query: "refund policy"})
+	if err != nil || out.Content != "30 days" || restartedLLM.CallCount() != 0 {
+		t.Fatalf("expected hit across instances, got %q (err=%v)", out.Content, err)
+	}
+
+	// 有历史时答案依赖上下文，不使用缓存
+	history := memory.NewBuffer(10)
+	_ = history.Save(context.Background(), memory.NewEntry("user", "I bought it 40 days ago"))
+	out, err = restarted.Run(ContextWithMemory(context.Background(), history), Input{Query: "refund policy"})
+	if err != nil || out.Content != "fresh" {
+		t.Errorf("expected cache bypass with history, got %q (err=%v)", out.Content, err)
+	}
+
+	// 配置工具策略时按授权主体隔离
+	r := rbac.NewRBAC()
+	subject := func(ctx context.Context) string { s, _ := ctx.Value(subjectKey{}).(string); return s }
+	guarded := NewReAct(WithName("guarded"), WithLLM(mock.FixedProvider("answer")),
+		WithSemanticCache(cache, 0.9), WithToolPolicy(r, subject))
+	adminCtx := context.WithValue(freshCtx(), subjectKey{}, "admin")
+	if _, err := guarded.Run(adminCtx, Input{Query: "salary report"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	guestCtx := context.WithValue(freshCtx(), subjectKey{}, "guest")
+	out, _ = guarded.Run(guestCtx, Input{Query: "salary report"})
+	if _, ok := out.Metadata[MetadataSemanticCache]; ok {
+		t.Error("cached answer must not be shared across subjects")
+	}
+}
+
+// subjectKey 测试用授权主体的 context key
+type subjectKey struct{}
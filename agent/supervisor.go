@@ -101,7 +101,7 @@ func NewSupervisor(name string, manager Agent, workers []Agent, opts ...Supervis
 //  4. 继续让 manager 决定下一步，直到 manager 不再调用工具
 func (s *SupervisorAgent) Run(ctx context.Context, input Input) (Output, error) {
 	if s.manager.LLM() == nil {
-		return Output{}, fmt.Errorf("SupervisorAgent %q: manager LLM not configured", s.config.Name)
+		return Output{}, fmt.Errorf("SupervisorAgent %q: manager LLM %w", s.config.Name, core.ErrNotConfigured)
 	}
 
 	// 将所有 worker 包装为工具
@@ -143,7 +143,7 @@ func (s *SupervisorAgent) Run(ctx context.Context, input Input) (Output, error)
 		}
 
 		// 调用 manager LLM
-		resp, err := s.manager.LLM().Complete(ctx, llm.CompletionRequest{
+		resp, err := completeLLM(ctx, s.manager.LLM(), llm.CompletionRequest{
 			Messages: messages,
 			Tools:    toolDefs,
 		})
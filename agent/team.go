@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
 	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/internal/util"
+	"github.com/hexagon-codes/hexagon/llm/prompt"
 	"github.com/hexagon-codes/hexagon/stream"
 )
 
@@ -31,6 +33,10 @@ const (
 	// TeamModeRoundRobin 轮询模式
 	// Agent 轮流执行，直到达到目标
 	TeamModeRoundRobin
+
+	// TeamModeDebate 辩论模式
+	// 角色不对称：正反方辩论后由裁判决定，或批评者反复推动生成者改进直到认可
+	TeamModeDebate
 )
 
 // String 返回模式名称
@@ -44,6 +50,8 @@ func (m TeamMode) String() string {
 		return "collaborative"
 	case TeamModeRoundRobin:
 		return "round_robin"
+	case TeamModeDebate:
+		return "debate"
 	default:
 		return "unknown"
 	}
@@ -72,9 +80,18 @@ type Team struct {
 	// Manager 管理者 Agent（仅用于 Hierarchical 模式）
 	manager Agent
 
+	// managerPrompt Manager 规划阶段的提示词模板，为 nil 时使用内置提示词
+	managerPrompt prompt.Renderer
+
 	// MaxRounds 最大轮次（用于 RoundRobin 和 Collaborative 模式）
 	maxRounds int
 
+	// maxConcurrency Hierarchical 模式下并行执行子任务的最大数量
+	maxConcurrency int
+
+	// debate Debate 模式配置
+	debate debateConfig
+
 	// GlobalState 全局状态
 	globalState GlobalState
 
@@ -85,8 +102,14 @@ type Team struct {
 	// Verbose 详细输出
 	verbose bool
 
-	// mu 保护 agents 切片的并发访问
+	// lastTranscript 最近一次运行的消息记录（受 mu 保护）
+	lastTranscript *TeamTranscript
+
+	// mu 保护 agents 切片和 lastTranscript 的并发访问
 	mu sync.RWMutex
+
+	// lifecycle 跟踪进行中的团队运行
+	lifecycle runTracker
 }
 
 // TeamOption 团队配置选项
@@ -95,11 +118,12 @@ type TeamOption func(*Team)
 // NewTeam 创建团队
 func NewTeam(name string, opts ...TeamOption) *Team {
 	t := &Team{
-		id:          util.GenerateID("team"),
-		name:        name,
-		mode:        TeamModeSequential,
-		maxRounds:   10,
-		globalState: NewGlobalState(),
+		id:             util.GenerateID("team"),
+		name:           name,
+		mode:           TeamModeSequential,
+		maxRounds:      10,
+		maxConcurrency: 4,
+		globalState:    NewGlobalState(),
 	}
 
 	for _, opt := range opts {
@@ -148,6 +172,32 @@ func WithManager(manager Agent) TeamOption {
 	}
 }
 
+// WithManagerPrompt 设置 Manager 规划阶段的提示词模板（Hierarchical 模式）
+//
+// 模板可使用以下变量，以及 Input.Context 中的变量：
+//   - task: 团队任务
+//   - members: 成员列表（[]TeamMember），包含名称、描述、头衔、目标和专长
+//   - roster: 格式化后的成员列表文本
+//   - format: 分配计划的 JSON 输出格式说明
+//
+// 渲染结果未提及全部成员名称时自动追加 roster，未包含 "assignments" 时自动追加 format，
+// 确保 Manager 知道可分配的成员和需要输出的格式。Manager 的输出无法解析为有效的
+// 分配计划时，团队运行返回 ErrInvalidManagerPlan。
+//
+// 示例：
+//
+//	tpl := prompt.MustNew("manager", `You lead a research team. Prefer at most 3 sub-tasks.
+//	Task: {{.task}}
+//	{{range .members}}- {{.Name}}: {{join .Expertise ", "}}
+//	{{end}}`)
+//	team := agent.NewTeam("research", agent.WithAgents(researcher, writer),
+//	    agent.WithManager(manager), agent.WithManagerPrompt(tpl))
+func WithManagerPrompt(tpl prompt.Renderer) TeamOption {
+	return func(t *Team) {
+		t.managerPrompt = tpl
+	}
+}
+
 // WithMaxRounds 设置最大轮次
 func WithMaxRounds(rounds int) TeamOption {
 	return func(t *Team) {
@@ -155,6 +205,14 @@ func WithMaxRounds(rounds int) TeamOption {
 	}
 }
 
+// WithTeamMaxConcurrency 设置 Hierarchical 模式下并行执行子任务的最大数量，默认 4
+// n <= 0 表示不限制
+func WithTeamMaxConcurrency(n int) TeamOption {
+	return func(t *Team) {
+		t.maxConcurrency = n
+	}
+}
+
 // WithGlobalState 设置全局状态
 func WithGlobalState(state GlobalState) TeamOption {
 	return func(t *Team) {
@@ -247,22 +305,66 @@ func (t *Team) RemoveAgent(agentID string) {
 
 // Run 执行团队任务
 func (t *Team) Run(ctx context.Context, input Input) (Output, error) {
+	ctx, done, err := t.lifecycle.begin(ctx)
+	if err != nil {
+		return Output{}, err
+	}
+	defer done()
+
+	// 成员 Agent 的运行都以团队运行为父运行
+	ctx, _ = enterRun(ctx)
+
+	rec := newTranscriptRecorder(t, input)
+	output, err := t.runMode(ctx, input, rec)
+	transcript := rec.finish(err)
+
+	t.mu.Lock()
+	t.lastTranscript = transcript
+	t.mu.Unlock()
+
+	if err != nil {
+		return Output{}, err
+	}
+	// 复制元数据，避免修改成员输出中的 map
+	metadata := make(map[string]any, len(output.Metadata)+1)
+	for k, v := range output.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataTeamTranscript] = transcript
+	output.Metadata = metadata
+	return output, nil
+}
+
+// runMode 按工作模式执行，消息记录到 rec
+func (t *Team) runMode(ctx context.Context, input Input, rec *transcriptRecorder) (Output, error) {
 	switch t.mode {
 	case TeamModeSequential:
-		return t.runSequential(ctx, input)
+		return t.runSequential(ctx, input, rec)
 	case TeamModeHierarchical:
-		return t.runHierarchical(ctx, input)
+		return t.runHierarchical(ctx, input, rec)
 	case TeamModeCollaborative:
-		return t.runCollaborative(ctx, input)
+		return t.runCollaborative(ctx, input, rec)
 	case TeamModeRoundRobin:
-		return t.runRoundRobin(ctx, input)
+		return t.runRoundRobin(ctx, input, rec)
+	case TeamModeDebate:
+		return t.runDebate(ctx, input, rec)
 	default:
 		return Output{}, fmt.Errorf("unknown team mode: %d", t.mode)
 	}
 }
 
+// Close 关闭团队
+//
+// 拒绝新的运行，取消进行中的团队运行并等待其返回（受 ctx 限制）。
+// 成员 Agent 与 Manager 由调用方注入，不会被关闭，需要时请分别调用其 Close。
+// 可重复调用。
+func (t *Team) Close(ctx context.Context) error {
+	_, err := t.lifecycle.shutdown(ctx)
+	return err
+}
+
 // runSequential 顺序执行
-func (t *Team) runSequential(ctx context.Context, input Input) (Output, error) {
+func (t *Team) runSequential(ctx context.Context, input Input, rec *transcriptRecorder) (Output, error) {
 	// 获取 agents 的快照
 	t.mu.RLock()
 	agents := make([]Agent, len(t.agents))
@@ -275,15 +377,22 @@ func (t *Team) runSequential(ctx context.Context, input Input) (Output, error) {
 
 	currentInput := input
 	var lastOutput Output
+	rec.send(TranscriptUser, agents[0].Name(), 0, input.Query)
 
-	for _, agent := range agents {
+	for i, agent := range agents {
 		select {
 		case <-ctx.Done():
 			return Output{}, ctx.Err()
 		default:
 		}
 
+		start := time.Now()
 		output, err := agent.Run(ctx, currentInput)
+		to := TranscriptUser
+		if i+1 < len(agents) {
+			to = agents[i+1].Name()
+		}
+		rec.reply(agent.Name(), to, 0, output, err, time.Since(start))
 		if err != nil {
 			return Output{}, fmt.Errorf("agent %s failed: %w", agent.Name(), err)
 		}
@@ -300,79 +409,11 @@ func (t *Team) runSequential(ctx context.Context, input Input) (Output, error) {
 	return lastOutput, nil
 }
 
-// runHierarchical 层级执行
-//
-// 由 Manager Agent 协调和分配任务给其他 Agent。
-// 线程安全：在执行前获取 agents 的快照
-func (t *Team) runHierarchical(ctx context.Context, input Input) (Output, error) {
-	if t.manager == nil {
-		return Output{}, fmt.Errorf("hierarchical mode requires a manager")
-	}
-
-	// 获取 agents 的快照（线程安全）
-	t.mu.RLock()
-	agents := make([]Agent, len(t.agents))
-	copy(agents, t.agents)
-	t.mu.RUnlock()
-
-	if len(agents) == 0 {
-		return Output{}, fmt.Errorf("team has no agents")
-	}
-
-	// Manager 分析任务并决定如何分配
-	// 这里简化实现：Manager 决定执行顺序
-	managerOutput, err := t.manager.Run(ctx, Input{
-		Query: fmt.Sprintf("As team manager, analyze this task and coordinate team: %s\nTeam members: %s",
-			input.Query, t.getAgentNamesFromSlice(agents)),
-		Context: input.Context,
-	})
-	if err != nil {
-		return Output{}, fmt.Errorf("manager failed: %w", err)
-	}
-
-	// 让所有 Agent 处理任务
-	results := make([]string, 0, len(agents))
-	var agentErrors []string
-	for _, agent := range agents {
-		select {
-		case <-ctx.Done():
-			return Output{}, ctx.Err()
-		default:
-		}
-
-		output, err := agent.Run(ctx, Input{
-			Query:   input.Query,
-			Context: map[string]any{"manager_guidance": managerOutput.Content},
-		})
-		if err != nil {
-			// 记录失败的 Agent 及错误信息，继续执行其他 Agent
-			agentErrors = append(agentErrors, fmt.Sprintf("[%s]: %v", agent.Name(), err))
-			continue
-		}
-		results = append(results, fmt.Sprintf("[%s]: %s", agent.Name(), output.Content))
-	}
-
-	// 如果所有 Agent 都失败了，返回错误
-	if len(results) == 0 && len(agentErrors) > 0 {
-		return Output{}, fmt.Errorf("all agents failed: %s", strings.Join(agentErrors, "; "))
-	}
-
-	// Manager 汇总结果
-	summaryOutput, err := t.manager.Run(ctx, Input{
-		Query: fmt.Sprintf("Summarize team results:\n%s", results),
-	})
-	if err != nil {
-		return Output{Content: fmt.Sprintf("Team results: %v", results)}, nil
-	}
-
-	return summaryOutput, nil
-}
-
 // runCollaborative 协作执行
 //
 // 所有 Agent 并行工作，通过消息传递协作。
 // 线程安全：在执行前获取 agents 的快照
-func (t *Team) runCollaborative(ctx context.Context, input Input) (Output, error) {
+func (t *Team) runCollaborative(ctx context.Context, input Input, rec *transcriptRecorder) (Output, error) {
 	// 获取 agents 的快照（线程安全）
 	t.mu.RLock()
 	agents := make([]Agent, len(t.agents))
@@ -392,12 +433,15 @@ func (t *Team) runCollaborative(ctx context.Context, input Input) (Output, error
 
 	results := make(chan result, len(agents))
 	var wg sync.WaitGroup
+	rec.send(TranscriptUser, TranscriptTeam, 0, input.Query)
 
 	for _, agent := range agents {
 		wg.Add(1)
 		go func(a Agent) {
 			defer wg.Done()
+			start := time.Now()
 			output, err := a.Run(ctx, input)
+			rec.reply(a.Name(), TranscriptUser, 0, output, err, time.Since(start))
 			results <- result{agent: a, output: output, err: err}
 		}(agent)
 	}
@@ -449,7 +493,7 @@ func (t *Team) runCollaborative(ctx context.Context, input Input) (Output, error
 //
 // Agent 轮流执行，直到达到目标或达到最大轮次。
 // 线程安全：在执行前获取 agents 的快照
-func (t *Team) runRoundRobin(ctx context.Context, input Input) (Output, error) {
+func (t *Team) runRoundRobin(ctx context.Context, input Input, rec *transcriptRecorder) (Output, error) {
 	// 获取 agents 的快照（线程安全）
 	t.mu.RLock()
 	agents := make([]Agent, len(t.agents))
@@ -463,26 +507,40 @@ func (t *Team) runRoundRobin(ctx context.Context, input Input) (Output, error) {
 	currentInput := input
 	var lastOutput Output
 
+	rec.send(TranscriptUser, agents[0].Name(), 1, input.Query)
+
 	for round := 0; round < t.maxRounds; round++ {
-		for _, agent := range agents {
+		for i, agent := range agents {
 			select {
 			case <-ctx.Done():
 				return Output{}, ctx.Err()
 			default:
 			}
 
+			start := time.Now()
 			output, err := agent.Run(ctx, currentInput)
+			d := time.Since(start)
+
+			// 检查是否完成（简化：检查输出中是否包含完成标记）
+			done := false
+			if err == nil && output.Metadata != nil {
+				done, _ = output.Metadata["done"].(bool)
+			}
+
+			// 接收方为轮转中的下一个成员，结束时为调用方
+			to := agents[(i+1)%len(agents)].Name()
+			if done || (round == t.maxRounds-1 && i == len(agents)-1) {
+				to = TranscriptUser
+			}
+			rec.reply(agent.Name(), to, round+1, output, err, d)
+
 			if err != nil {
 				continue
 			}
 
 			lastOutput = output
-
-			// 检查是否完成（简化：检查输出中是否包含完成标记）
-			if output.Metadata != nil {
-				if done, ok := output.Metadata["done"].(bool); ok && done {
-					return output, nil
-				}
+			if done {
+				return output, nil
 			}
 
 			// 准备下一轮输入
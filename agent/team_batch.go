@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BatchOption RunBatch 选项
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	concurrency     int
+	continueOnError bool
+}
+
+// WithConcurrency 设置同时执行的最大运行数
+// 默认使用团队的 WithTeamMaxConcurrency（默认 4）
+func WithConcurrency(n int) BatchOption {
+	return func(c *batchConfig) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithContinueOnError 单个输入失败时继续执行其余输入
+// 默认第一个失败即取消尚未完成的运行
+func WithContinueOnError() BatchOption {
+	return func(c *batchConfig) {
+		c.continueOnError = true
+	}
+}
+
+// BatchItemError 批量执行中单个输入的错误
+type BatchItemError struct {
+	// Index 输入在 inputs 中的下标
+	Index int
+
+	// Err 失败原因
+	Err error
+}
+
+// BatchError 批量执行的汇总错误，Errors 按下标升序
+type BatchError struct {
+	// Errors 失败的输入
+	Errors []BatchItemError
+
+	// Total 输入总数
+	Total int
+}
+
+func (e *BatchError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d of %d batch items failed", len(e.Errors), e.Total)
+	for i, item := range e.Errors {
+		if i == 3 {
+			fmt.Fprintf(&sb, "; ... (%d more)", len(e.Errors)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "; item %d: %v", item.Index, item.Err)
+	}
+	return sb.String()
+}
+
+// Unwrap 返回所有失败原因，支持 errors.Is / errors.As
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, item := range e.Errors {
+		errs[i] = item.Err
+	}
+	return errs
+}
+
+// RunBatch 以有界并发对多个输入执行团队任务
+//
+// outputs 与 inputs 一一对应。默认遇到第一个失败即取消其余运行，返回 nil 和该错误；
+// 使用 WithContinueOnError 时执行全部输入，失败项的输出为零值，
+// 并返回汇总所有失败的 *BatchError。
+//
+// 各次运行共享团队成员 Agent（及其 Memory），LLM 调用仍受全局 / context 限流器约束。
+//
+// 使用示例：
+//
+//	outputs, err := team.RunBatch(ctx, inputs, agent.WithConcurrency(8), agent.WithContinueOnError())
+//	var batchErr *agent.BatchError
+//	if errors.As(err, &batchErr) {
+//	    for _, item := range batchErr.Errors {
+//	        log.Printf("input %d failed: %v", item.Index, item.Err)
+//	    }
+//	}
+func (t *Team) RunBatch(ctx context.Context, inputs []Input, opts ...BatchOption) ([]Output, error) {
+	cfg := &batchConfig{concurrency: t.maxConcurrency}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return runBatch(ctx, inputs, cfg, t.Run)
+}
+
+// runBatch 使用固定数量的 worker 执行 run，结果按下标写回
+func runBatch(ctx context.Context, inputs []Input, cfg *batchConfig, run func(context.Context, Input) (Output, error)) ([]Output, error) {
+	outputs := make([]Output, len(inputs))
+	if len(inputs) == 0 {
+		return outputs, nil
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	workers := len(inputs)
+	if cfg.concurrency > 0 {
+		workers = min(workers, cfg.concurrency)
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []BatchItemError
+		wg   sync.WaitGroup
+	)
+	// finished 标记已执行完毕（成功或失败）的输入，每个下标只由一个 worker 写入
+	finished := make([]bool, len(inputs))
+	indexes := make(chan int)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				output, err := run(ctx, inputs[i])
+				finished[i] = true
+				if err == nil {
+					outputs[i] = output
+					continue
+				}
+				mu.Lock()
+				errs = append(errs, BatchItemError{Index: i, Err: err})
+				mu.Unlock()
+				if !cfg.continueOnError {
+					cancel(fmt.Errorf("batch item %d failed: %w", i, err))
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range inputs {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	// 取消后未分派的输入计为失败
+	for i, ok := range finished {
+		if !ok {
+			errs = append(errs, BatchItemError{Index: i, Err: context.Cause(ctx)})
+		}
+	}
+	if len(errs) == 0 {
+		return outputs, nil
+	}
+	if !cfg.continueOnError {
+		return nil, context.Cause(ctx)
+	}
+	slices.SortFunc(errs, func(a, b BatchItemError) int { return a.Index - b.Index })
+	return outputs, &BatchError{Errors: errs, Total: len(inputs)}
+}
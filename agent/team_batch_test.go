@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBatchTeam 创建一个回显查询（大写）的单成员团队，查询包含 "fail" 时失败
+func newBatchTeam(inflight, peak *atomic.Int32) *Team {
+	echo := newMockAgent("echo", func(ctx context.Context, input Input) (Output, error) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		select {
+		case <-time.After(5 * time.Millisecond):
+		case <-ctx.Done():
+			return Output{}, ctx.Err()
+		}
+
+		if strings.Contains(input.Query, "fail") {
+			return Output{}, errors.New("boom")
+		}
+		return Output{Content: strings.ToUpper(input.Query)}, nil
+	})
+	return NewTeam("batch", WithAgents(echo))
+}
+
+func TestTeamRunBatch_OrderAndConcurrency(t *testing.T) {
+	var inflight, peak atomic.Int32
+	team := newBatchTeam(&inflight, &peak)
+
+	inputs := make([]Input, 12)
+	for i := range inputs {
+		inputs[i] = Input{Query: fmt.Sprintf("item-%d", i)}
+	}
+	outputs, err := team.RunBatch(context.Background(), inputs, WithConcurrency(3))
+	if err != nil {
+		t.Fatalf("RunBatch failed: %v", err)
+	}
+	for i, out := range outputs {
+		if want := fmt.Sprintf("ITEM-%d", i); out.Content != want {
+			t.Errorf("output %d: expected %q, got %q", i, want, out.Content)
+		}
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("expected peak concurrency in [2, 3], got %d", p)
+	}
+}
+
+func TestTeamRunBatch_ContinueOnError(t *testing.T) {
+	var inflight, peak atomic.Int32
+	team := newBatchTeam(&inflight, &peak)
+
+	inputs := []Input{{Query: "a"}, {Query: "fail-1"}, {Query: "b"}, {Query: "fail-3"}}
+	outputs, err := team.RunBatch(context.Background(), inputs, WithContinueOnError())
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected *BatchError, got %v", err)
+	}
+	if batchErr.Total != 4 || len(batchErr.Errors) != 2 || batchErr.Errors[0].Index != 1 || batchErr.Errors[1].Index != 3 {
+		t.Errorf("unexpected batch error: %+v", batchErr)
+	}
+	if outputs[0].Content != "A" || outputs[2].Content != "B" || outputs[1].Content != "" {
+		t.Errorf("unexpected outputs: %+v", outputs)
+	}
+}
+
+func TestTeamRunBatch_FailFast(t *testing.T) {
+	var inflight, peak atomic.Int32
+	team := newBatchTeam(&inflight, &peak)
+
+	inputs := []Input{{Query: "fail-0"}}
+	for i := range 20 {
+		inputs = append(inputs, Input{Query: fmt.Sprintf("item-%d", i)})
+	}
+	outputs, err := team.RunBatch(context.Background(), inputs, WithConcurrency(1))
+	if err == nil || !strings.Contains(err.Error(), "batch item 0 failed") {
+		t.Fatalf("expected first item error, got %v", err)
+	}
+	if outputs != nil {
+		t.Errorf("expected nil outputs on fail-fast, got %d", len(outputs))
+	}
+}
+
+func TestTeamRunBatch_Empty(t *testing.T) {
+	var inflight, peak atomic.Int32
+	outputs, err := newBatchTeam(&inflight, &peak).RunBatch(context.Background(), nil)
+	if err != nil || len(outputs) != 0 {
+		t.Errorf("expected empty result, got %v %v", outputs, err)
+	}
+}
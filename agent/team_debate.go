@@ -0,0 +1,278 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// DebateStyle 辩论模式的风格
+type DebateStyle int
+
+const (
+	// DebateStyleAdversarial 对抗式辩论：正反方轮流陈述，裁判做出最终决定
+	DebateStyleAdversarial DebateStyle = iota
+
+	// DebateStyleCritique 生成-批评循环：批评者反复审阅并推动生成者改进，直到认可
+	DebateStyleCritique
+)
+
+// DebateRole 辩论中的角色
+type DebateRole string
+
+const (
+	DebateRoleProponent DebateRole = "proponent" // 正方
+	DebateRoleOpponent  DebateRole = "opponent"  // 反方
+	DebateRoleJudge     DebateRole = "judge"     // 裁判
+	DebateRoleGenerator DebateRole = "generator" // 生成者
+	DebateRoleCritic    DebateRole = "critic"    // 批评者
+)
+
+// DebateApproved 批评者认可生成结果时在回复中单独一行给出的标记
+// 只有去除首尾空白后与标记完全相同的行才视为认可，"NOT APPROVED" 等不算
+const DebateApproved = "APPROVED"
+
+// Debate 模式输出元数据的键
+const (
+	// MetadataDebateTranscript 完整发言记录，值为 []DebateTurn
+	MetadataDebateTranscript = "transcript"
+
+	// MetadataDebateRounds 实际进行的轮数
+	MetadataDebateRounds = "rounds"
+
+	// MetadataDebateApproved 批评者是否认可最终结果（仅 DebateStyleCritique）
+	MetadataDebateApproved = "approved"
+)
+
+// DebateTurn 一次发言
+type DebateTurn struct {
+	// Round 轮次，从 1 开始；裁判的发言轮次为最后一轮
+	Round int `json:"round"`
+
+	// Role 角色
+	Role DebateRole `json:"role"`
+
+	// Agent 发言的 Agent 名称
+	Agent string `json:"agent"`
+
+	// Content 发言内容
+	Content string `json:"content"`
+}
+
+// DebateTermination 辩论终止条件
+// 每轮结束后以当前发言记录调用，返回 true 时提前结束
+type DebateTermination func(transcript []DebateTurn) bool
+
+// debateConfig 辩论模式配置
+type debateConfig struct {
+	style     DebateStyle
+	rounds    int
+	first     Agent // 正方或生成者
+	second    Agent // 反方或批评者
+	judge     Agent
+	terminate DebateTermination
+}
+
+// WithDebate 设置对抗式辩论（Debate 模式）
+// 正反方每轮各发言一次，结束后由裁判根据完整记录给出决定
+func WithDebate(proponent, opponent, judge Agent) TeamOption {
+	return func(t *Team) {
+		t.mode = TeamModeDebate
+		t.debate.style = DebateStyleAdversarial
+		t.debate.first = proponent
+		t.debate.second = opponent
+		t.debate.judge = judge
+	}
+}
+
+// WithCritique 设置生成-批评循环（Debate 模式）
+// 批评者回复中有一行为 DebateApproved 时视为认可，循环结束
+func WithCritique(generator, critic Agent) TeamOption {
+	return func(t *Team) {
+		t.mode = TeamModeDebate
+		t.debate.style = DebateStyleCritique
+		t.debate.first = generator
+		t.debate.second = critic
+		t.debate.judge = nil
+	}
+}
+
+// WithDebateRounds 设置 Debate 模式的最大轮数，默认 3
+func WithDebateRounds(rounds int) TeamOption {
+	return func(t *Team) {
+		t.debate.rounds = rounds
+	}
+}
+
+// WithDebateTermination 设置 Debate 模式的提前终止条件
+func WithDebateTermination(fn DebateTermination) TeamOption {
+	return func(t *Team) {
+		t.debate.terminate = fn
+	}
+}
+
+// runDebate 辩论执行
+func (t *Team) runDebate(ctx context.Context, input Input, rec *transcriptRecorder) (Output, error) {
+	cfg := t.debate
+	if cfg.first == nil || cfg.second == nil {
+		return Output{}, fmt.Errorf("debate mode requires two participants")
+	}
+	if cfg.style == DebateStyleAdversarial && cfg.judge == nil {
+		return Output{}, fmt.Errorf("debate mode requires a judge")
+	}
+	if cfg.rounds <= 0 {
+		cfg.rounds = 3
+	}
+
+	run := &debateRun{topic: input.Query, context: input.Context, rec: rec}
+	rec.send(TranscriptUser, cfg.first.Name(), 1, input.Query)
+	var output Output
+	var err error
+	if cfg.style == DebateStyleCritique {
+		output, err = run.critique(ctx, cfg)
+	} else {
+		output, err = run.adversarial(ctx, cfg)
+	}
+	if err != nil {
+		return Output{}, err
+	}
+
+	output.Usage = run.usage
+	output.ToolCalls = run.toolCalls
+	if output.Metadata == nil {
+		output.Metadata = make(map[string]any)
+	}
+	output.Metadata["mode"] = TeamModeDebate.String()
+	output.Metadata[MetadataDebateTranscript] = run.transcript
+	output.Metadata[MetadataDebateRounds] = run.rounds
+	return output, nil
+}
+
+// debateRun 单次辩论的状态
+type debateRun struct {
+	topic      string
+	context    map[string]any
+	rounds     int
+	transcript []DebateTurn
+	usage      llm.Usage
+	toolCalls  []ToolCallRecord
+	rec        *transcriptRecorder
+}
+
+// adversarial 对抗式辩论
+func (r *debateRun) adversarial(ctx context.Context, cfg debateConfig) (Output, error) {
+	for round := 1; round <= cfg.rounds; round++ {
+		r.rounds = round
+		if _, err := r.speak(ctx, cfg.first, DebateRoleProponent, cfg.second.Name(),
+			"You are the proponent in a debate. Argue in favor of the position and rebut the opponent's latest points."); err != nil {
+			return Output{}, err
+		}
+		if _, err := r.speak(ctx, cfg.second, DebateRoleOpponent, cfg.first.Name(),
+			"You are the opponent in a debate. Argue against the position and rebut the proponent's latest points."); err != nil {
+			return Output{}, err
+		}
+		if cfg.terminate != nil && cfg.terminate(r.transcript) {
+			break
+		}
+	}
+
+	decision, err := r.speak(ctx, cfg.judge, DebateRoleJudge, TranscriptUser,
+		"You are the judge of this debate. Weigh both sides and give your final decision with a brief justification.")
+	if err != nil {
+		return Output{}, err
+	}
+	return Output{Content: decision.Content, Metadata: decision.Metadata}, nil
+}
+
+// critique 生成-批评循环，返回最后一版生成结果
+func (r *debateRun) critique(ctx context.Context, cfg debateConfig) (Output, error) {
+	var artifact Output
+	approved := false
+	for round := 1; round <= cfg.rounds; round++ {
+		r.rounds = round
+		instruction := "Produce your best response to the task."
+		if round > 1 {
+			instruction = "Revise your previous response to address the critic's feedback. Output only the revised response."
+		}
+		out, err := r.speak(ctx, cfg.first, DebateRoleGenerator, cfg.second.Name(), instruction)
+		if err != nil {
+			return Output{}, err
+		}
+		artifact = out
+
+		review, err := r.speak(ctx, cfg.second, DebateRoleCritic, cfg.first.Name(), fmt.Sprintf(
+			"You are the critic. Review the generator's latest response. If it fully satisfies the task, reply with %s on a line by itself; otherwise give specific, actionable feedback.",
+			DebateApproved))
+		if err != nil {
+			return Output{}, err
+		}
+		if isApproval(review.Content) {
+			approved = true
+			break
+		}
+		if cfg.terminate != nil && cfg.terminate(r.transcript) {
+			break
+		}
+	}
+
+	output := Output{Content: artifact.Content, Metadata: artifact.Metadata}
+	if output.Metadata == nil {
+		output.Metadata = make(map[string]any)
+	}
+	output.Metadata[MetadataDebateApproved] = approved
+	return output, nil
+}
+
+// isApproval 判断批评者回复中是否有一行恰为 DebateApproved
+func isApproval(content string) bool {
+	for line := range strings.Lines(content) {
+		if strings.TrimSpace(line) == DebateApproved {
+			return true
+		}
+	}
+	return false
+}
+
+// speak 让 agent 以指定角色基于当前记录发言，并追加到记录中
+// to 为团队消息记录中的接收方，即需要回应这次发言的一方
+func (r *debateRun) speak(ctx context.Context, agent Agent, role DebateRole, to, instruction string) (Output, error) {
+	if err := ctx.Err(); err != nil {
+		return Output{}, err
+	}
+
+	start := time.Now()
+	out, err := agent.Run(ctx, Input{
+		Query:   r.prompt(instruction),
+		Context: r.context,
+	})
+	r.rec.reply(agent.Name(), to, r.rounds, out, err, time.Since(start))
+	if err != nil {
+		return Output{}, fmt.Errorf("%s %s failed: %w", role, agent.Name(), err)
+	}
+
+	r.transcript = append(r.transcript, DebateTurn{
+		Round:   r.rounds,
+		Role:    role,
+		Agent:   agent.Name(),
+		Content: out.Content,
+	})
+	r.usage = mergeUsage(r.usage, out.Usage)
+	r.toolCalls = append(r.toolCalls, out.ToolCalls...)
+	return out, nil
+}
+
+// prompt 构建包含主题和发言记录的提示
+func (r *debateRun) prompt(instruction string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n\nTask: %s\n", instruction, r.topic)
+	if len(r.transcript) > 0 {
+		sb.WriteString("\nTranscript so far:\n")
+		for _, turn := range r.transcript {
+			fmt.Fprintf(&sb, "\n[Round %d] %s (%s):\n%s\n", turn.Round, turn.Role, turn.Agent, turn.Content)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,378 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/llm/parser"
+)
+
+// Hierarchical 模式输出元数据的键
+const (
+	// MetadataTeamAssignments 分配计划，值为 []TeamAssignment
+	MetadataTeamAssignments = "assignments"
+
+	// MetadataTeamAssignmentResults 各子任务的执行结果，值为 []TeamAssignmentResult
+	MetadataTeamAssignmentResults = "assignment_results"
+)
+
+// Manager 提示词模板的变量名，见 WithManagerPrompt
+const (
+	// ManagerPromptVarTask 团队任务
+	ManagerPromptVarTask = "task"
+
+	// ManagerPromptVarMembers 成员列表，值为 []TeamMember
+	ManagerPromptVarMembers = "members"
+
+	// ManagerPromptVarRoster 格式化后的成员列表文本
+	ManagerPromptVarRoster = "roster"
+
+	// ManagerPromptVarFormat 分配计划的输出格式说明
+	ManagerPromptVarFormat = "format"
+)
+
+// ErrInvalidManagerPlan Manager 的输出无法解析为有效的分配计划
+var ErrInvalidManagerPlan = errors.New("agent: invalid manager plan")
+
+// managerPlanFormat 分配计划的输出格式说明
+const managerPlanFormat = `Respond with JSON only: {"assignments": [{"agent": "<member name>", "task": "<sub-task>"}]}`
+
+// TeamMember Manager 提示词中的成员信息，来自成员的 Name、Description 和 Role
+type TeamMember struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Goal        string   `json:"goal,omitempty"`
+	Expertise   []string `json:"expertise,omitempty"`
+}
+
+// String 返回单行的成员描述，如 "researcher (Senior Researcher): Finds facts. Expertise: search, papers"
+func (m TeamMember) String() string {
+	var sb strings.Builder
+	sb.WriteString(m.Name)
+	if m.Title != "" {
+		fmt.Fprintf(&sb, " (%s)", m.Title)
+	}
+	var details []string
+	if m.Description != "" {
+		details = append(details, m.Description)
+	}
+	if m.Goal != "" {
+		details = append(details, "Goal: "+m.Goal)
+	}
+	if len(m.Expertise) > 0 {
+		details = append(details, "Expertise: "+strings.Join(m.Expertise, ", "))
+	}
+	if len(details) > 0 {
+		sb.WriteString(": ")
+		sb.WriteString(strings.Join(details, ". "))
+	}
+	return sb.String()
+}
+
+// teamMembers 收集成员信息
+func teamMembers(agents []Agent) []TeamMember {
+	members := make([]TeamMember, len(agents))
+	for i, a := range agents {
+		role := a.Role()
+		members[i] = TeamMember{
+			Name:        a.Name(),
+			Description: a.Description(),
+			Title:       role.Title,
+			Goal:        role.Goal,
+			Expertise:   role.Expertise,
+		}
+	}
+	return members
+}
+
+// formatRoster 格式化成员列表，每行一个成员
+func formatRoster(members []TeamMember) string {
+	var sb strings.Builder
+	for _, m := range members {
+		fmt.Fprintf(&sb, "- %s\n", m)
+	}
+	return sb.String()
+}
+
+// TeamAssignment Manager 分配给成员的子任务
+type TeamAssignment struct {
+	// Agent 成员名称
+	Agent string `json:"agent"`
+
+	// Task 子任务描述
+	Task string `json:"task"`
+}
+
+// TeamAssignmentResult 子任务执行结果
+type TeamAssignmentResult struct {
+	TeamAssignment
+
+	// Content 成员的回复，失败时为空
+	Content string `json:"content,omitempty"`
+
+	// Error 失败原因
+	Error string `json:"error,omitempty"`
+
+	// Usage 成员的 Token 使用
+	Usage llm.Usage `json:"usage"`
+
+	// Duration 执行耗时
+	Duration time.Duration `json:"duration"`
+}
+
+// teamPlan Manager 输出的分配计划
+type teamPlan struct {
+	Assignments []TeamAssignment `json:"assignments"`
+}
+
+// runHierarchical 层级执行
+//
+// 分三个阶段：
+//  1. 规划：Manager 将任务拆分为子任务并分配给成员，输出 JSON 分配计划
+//  2. 分派：子任务按 maxConcurrency 并行执行，结果按计划顺序收集
+//  3. 汇总：Manager 基于各成员结果生成最终回复
+//
+// Manager 未给出有效计划时返回 ErrInvalidManagerPlan，不执行任何子任务。
+// 分配计划和各子任务结果记录在输出元数据中。
+// 线程安全：在执行前获取 agents 的快照
+func (t *Team) runHierarchical(ctx context.Context, input Input, rec *transcriptRecorder) (Output, error) {
+	if t.manager == nil {
+		return Output{}, fmt.Errorf("hierarchical mode requires a manager")
+	}
+
+	// 获取 agents 的快照（线程安全）
+	t.mu.RLock()
+	agents := make([]Agent, len(t.agents))
+	copy(agents, t.agents)
+	t.mu.RUnlock()
+
+	if len(agents) == 0 {
+		return Output{}, fmt.Errorf("team has no agents")
+	}
+
+	// 阶段 1: Manager 规划
+	planQuery, err := t.buildPlanQuery(input, agents)
+	if err != nil {
+		return Output{}, err
+	}
+	rec.send(TranscriptUser, t.manager.Name(), 0, input.Query)
+	start := time.Now()
+	planOutput, err := t.manager.Run(ctx, Input{
+		Query:   planQuery,
+		Context: input.Context,
+	})
+	rec.reply(t.manager.Name(), TranscriptTeam, 0, planOutput, err, time.Since(start))
+	if err != nil {
+		return Output{}, fmt.Errorf("manager failed: %w", err)
+	}
+	totalUsage := planOutput.Usage
+
+	byName := make(map[string]Agent, len(agents))
+	for _, a := range agents {
+		byName[strings.ToLower(a.Name())] = a
+	}
+	assignments, err := parseAssignments(ctx, planOutput.Content, byName)
+	if err != nil {
+		return Output{}, err
+	}
+
+	// 阶段 2: 并行分派
+	results, toolCalls := t.dispatchAssignments(ctx, input, planOutput.Content, assignments, byName, rec)
+	if err := ctx.Err(); err != nil {
+		return Output{}, err
+	}
+
+	var agentErrors []string
+	succeeded := 0
+	for _, r := range results {
+		totalUsage = mergeUsage(totalUsage, r.Usage)
+		if r.Error != "" {
+			agentErrors = append(agentErrors, fmt.Sprintf("[%s]: %s", r.Agent, r.Error))
+			continue
+		}
+		succeeded++
+	}
+
+	// 如果所有 Agent 都失败了，返回错误
+	if succeeded == 0 {
+		return Output{}, fmt.Errorf("all agents failed: %s", strings.Join(agentErrors, "; "))
+	}
+
+	// 阶段 3: Manager 汇总
+	start = time.Now()
+	output, err := t.manager.Run(ctx, Input{
+		Query: buildSynthesisQuery(input.Query, results),
+	})
+	rec.reply(t.manager.Name(), TranscriptUser, 0, output, err, time.Since(start))
+	if err != nil {
+		// 汇总失败不影响主流程，直接拼接成员结果
+		output = Output{Content: formatAssignmentResults(results)}
+	}
+
+	output.Usage = mergeUsage(totalUsage, output.Usage)
+	output.ToolCalls = append(toolCalls, output.ToolCalls...)
+	if output.Metadata == nil {
+		output.Metadata = make(map[string]any)
+	}
+	output.Metadata["mode"] = TeamModeHierarchical.String()
+	output.Metadata[MetadataTeamAssignments] = assignments
+	output.Metadata[MetadataTeamAssignmentResults] = results
+
+	return output, nil
+}
+
+// dispatchAssignments 并行执行子任务，结果与 assignments 一一对应
+func (t *Team) dispatchAssignments(ctx context.Context, input Input, guidance string, assignments []TeamAssignment, byName map[string]Agent, rec *transcriptRecorder) ([]TeamAssignmentResult, []ToolCallRecord) {
+	results := make([]TeamAssignmentResult, len(assignments))
+	toolCalls := make([][]ToolCallRecord, len(assignments))
+
+	var semaphore chan struct{}
+	if t.maxConcurrency > 0 {
+		semaphore = make(chan struct{}, t.maxConcurrency)
+	}
+
+	var wg sync.WaitGroup
+	for i, assignment := range assignments {
+		results[i].TeamAssignment = assignment
+		agent := byName[strings.ToLower(assignment.Agent)]
+		rec.send(t.manager.Name(), agent.Name(), 0, assignment.Task)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if semaphore != nil {
+				select {
+				case semaphore <- struct{}{}:
+					defer func() { <-semaphore }()
+				case <-ctx.Done():
+					results[i].Error = ctx.Err().Error()
+					return
+				}
+			}
+
+			start := time.Now()
+			output, err := agent.Run(ctx, Input{
+				Query: assignment.Task,
+				Context: map[string]any{
+					"team_task":        input.Query,
+					"manager_guidance": guidance,
+				},
+			})
+			results[i].Duration = time.Since(start)
+			results[i].Usage = output.Usage
+			rec.reply(agent.Name(), t.manager.Name(), 0, output, err, results[i].Duration)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Content = output.Content
+			toolCalls[i] = output.ToolCalls
+		}()
+	}
+	wg.Wait()
+
+	var all []ToolCallRecord
+	for _, calls := range toolCalls {
+		all = append(all, calls...)
+	}
+	return results, all
+}
+
+// buildPlanQuery 构建 Manager 规划提示
+// 配置了 WithManagerPrompt 时渲染模板，否则使用内置提示词
+func (t *Team) buildPlanQuery(input Input, agents []Agent) (string, error) {
+	members := teamMembers(agents)
+	roster := formatRoster(members)
+
+	if t.managerPrompt == nil {
+		var sb strings.Builder
+		sb.WriteString("As team manager, break down the task into independent sub-tasks and assign each to a team member.\n\n")
+		fmt.Fprintf(&sb, "Task: %s\n\nTeam members:\n%s", input.Query, roster)
+		sb.WriteString("\nSub-tasks run in parallel, so each must be self-contained. ")
+		sb.WriteString("A member may receive several sub-tasks; members without a suitable sub-task may be left out.\n")
+		sb.WriteString(managerPlanFormat)
+		return sb.String(), nil
+	}
+
+	vars := make(map[string]any, len(input.Context)+4)
+	maps.Copy(vars, input.Context)
+	vars[ManagerPromptVarTask] = input.Query
+	vars[ManagerPromptVarMembers] = members
+	vars[ManagerPromptVarRoster] = roster
+	vars[ManagerPromptVarFormat] = managerPlanFormat
+
+	text, err := t.managerPrompt.Render(vars)
+	if err != nil {
+		return "", fmt.Errorf("render manager prompt: %w", err)
+	}
+
+	// 模板未列出全部成员或未说明输出格式时补充，避免 Manager 分配给不存在的成员或无法解析
+	for _, m := range members {
+		if !strings.Contains(text, m.Name) {
+			text += "\n\nTeam members:\n" + roster
+			break
+		}
+	}
+	if !strings.Contains(text, `"assignments"`) {
+		text += "\n\n" + managerPlanFormat
+	}
+	return text, nil
+}
+
+// parseAssignments 解析 Manager 的分配计划
+// 跳过未知成员和空任务；输出不是有效 JSON 或没有可执行的子任务时返回 ErrInvalidManagerPlan
+func parseAssignments(ctx context.Context, content string, byName map[string]Agent) ([]TeamAssignment, error) {
+	plan, err := parser.NewJSONParser[teamPlan]().Parse(ctx, content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManagerPlan, err)
+	}
+
+	assignments := make([]TeamAssignment, 0, len(plan.Assignments))
+	var skipped []string
+	for _, a := range plan.Assignments {
+		agent, ok := byName[strings.ToLower(strings.TrimSpace(a.Agent))]
+		task := strings.TrimSpace(a.Task)
+		if !ok || task == "" {
+			skipped = append(skipped, fmt.Sprintf("%q", a.Agent))
+			continue
+		}
+		assignments = append(assignments, TeamAssignment{Agent: agent.Name(), Task: task})
+	}
+	if len(assignments) == 0 {
+		if len(skipped) > 0 {
+			return nil, fmt.Errorf("%w: no assignment names a known member with a task (got %s)", ErrInvalidManagerPlan, strings.Join(skipped, ", "))
+		}
+		return nil, fmt.Errorf("%w: no assignments", ErrInvalidManagerPlan)
+	}
+	return assignments, nil
+}
+
+// buildSynthesisQuery 构建 Manager 汇总提示
+func buildSynthesisQuery(task string, results []TeamAssignmentResult) string {
+	return fmt.Sprintf("As team manager, synthesize the final answer to the task from your team's results.\n\nTask: %s\n\nResults:\n%s",
+		task, formatAssignmentResults(results))
+}
+
+// formatAssignmentResults 格式化子任务结果
+func formatAssignmentResults(results []TeamAssignmentResult) string {
+	var sb strings.Builder
+	for i, r := range results {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "[%s] %s\n", r.Agent, r.Task)
+		if r.Error != "" {
+			fmt.Fprintf(&sb, "(failed: %s)", r.Error)
+		} else {
+			sb.WriteString(r.Content)
+		}
+	}
+	return sb.String()
+}
@@ -1,7 +1,19 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/llm/prompt"
+	"github.com/hexagon-codes/hexagon/testing/mock"
 )
 
 func TestTeamCreation(t *testing.T) {
@@ -45,6 +57,7 @@ func TestTeamModeString(t *testing.T) {
 		{TeamModeHierarchical, "hierarchical"},
 		{TeamModeCollaborative, "collaborative"},
 		{TeamModeRoundRobin, "round_robin"},
+		{TeamModeDebate, "debate"},
 		{TeamMode(99), "unknown"},
 	}
 
@@ -107,3 +120,436 @@ func TestTeamInputOutputSchema(t *testing.T) {
 		t.Error("expected non-nil output schema")
 	}
 }
+
+func TestTeamHierarchicalParallelAssignments(t *testing.T) {
+	managerLLM := mock.NewLLMProvider("manager")
+	managerLLM.AddResponse("Plan:\n```json\n" +
+		`{"assignments": [{"agent": "Researcher", "task": "find facts"}, {"agent": "writer", "task": "draft intro"}, {"agent": "ghost", "task": "ignored"}]}` +
+		"\n```")
+	managerLLM.AddResponse("final answer")
+
+	// 两个子任务都进入执行后才返回，串行执行会超时失败
+	var mu sync.Mutex
+	active := 0
+	ready := make(chan struct{})
+	respond := func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		mu.Lock()
+		active++
+		if active == 2 {
+			close(ready)
+		}
+		mu.Unlock()
+		select {
+		case <-ready:
+		case <-time.After(time.Second):
+			return nil, errors.New("sub-tasks did not run in parallel")
+		}
+		return &llm.CompletionResponse{
+			Content: "done: " + req.Messages[len(req.Messages)-1].Content,
+			Usage:   llm.Usage{TotalTokens: 10},
+		}, nil
+	}
+
+	team := NewTeam("hierarchical-team",
+		WithAgents(
+			NewBaseAgent(WithName("researcher"), WithLLM(mock.NewLLMProvider("researcher").WithResponseFn(respond))),
+			NewBaseAgent(WithName("writer"), WithLLM(mock.NewLLMProvider("writer").WithResponseFn(respond))),
+		),
+		WithManager(NewBaseAgent(WithName("manager"), WithLLM(managerLLM))),
+		WithTeamMaxConcurrency(2),
+	)
+
+	out, err := team.Run(context.Background(), Input{Query: "write a report"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if out.Content != "final answer" {
+		t.Errorf("expected synthesized answer, got %q", out.Content)
+	}
+
+	assignments, _ := out.Metadata[MetadataTeamAssignments].([]TeamAssignment)
+	if len(assignments) != 2 || assignments[0].Agent != "researcher" || assignments[1].Task != "draft intro" {
+		t.Fatalf("unexpected assignments: %+v", assignments)
+	}
+	results, _ := out.Metadata[MetadataTeamAssignmentResults].([]TeamAssignmentResult)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Error != "" || r.Content != "done: "+r.Task {
+			t.Errorf("unexpected result: %+v", r)
+		}
+	}
+	if out.Usage.TotalTokens < 20 {
+		t.Errorf("expected member usage to be aggregated, got %+v", out.Usage)
+	}
+
+	synthesis := managerLLM.LastCall().Messages
+	if prompt := synthesis[len(synthesis)-1].Content; !strings.Contains(prompt, "done: find facts") {
+		t.Errorf("expected member results in synthesis prompt, got %q", prompt)
+	}
+}
+
+func TestTeamHierarchicalInvalidPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  string
+	}{
+		{"not json", "everyone should help", "invalid manager plan"},
+		{"unknown members", `{"assignments": [{"agent": "ghost", "task": "haunt"}]}`, `"ghost"`},
+		{"empty plan", `{"assignments": []}`, "no assignments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managerLLM := mock.NewLLMProvider("manager")
+			managerLLM.AddResponse(tt.response)
+			workerLLM := mock.NewLLMProvider("worker")
+
+			team := NewTeam("hierarchical-team",
+				WithAgents(
+					NewBaseAgent(WithName("w1"), WithLLM(workerLLM)),
+					NewBaseAgent(WithName("w2"), WithLLM(workerLLM)),
+				),
+				WithManager(NewBaseAgent(WithName("manager"), WithLLM(managerLLM))),
+			)
+
+			_, err := team.Run(context.Background(), Input{Query: "task"})
+			if !errors.Is(err, ErrInvalidManagerPlan) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected ErrInvalidManagerPlan containing %q, got %v", tt.wantErr, err)
+			}
+			if workerLLM.CallCount() != 0 {
+				t.Errorf("expected no member to run on an invalid plan, got %d calls", workerLLM.CallCount())
+			}
+		})
+	}
+}
+
+func TestTeamManagerPrompt(t *testing.T) {
+	managerLLM := mock.NewLLMProvider("manager")
+	managerLLM.AddResponse(`{"assignments": [{"agent": "researcher", "task": "find facts"}]}`)
+	managerLLM.AddResponse("final answer")
+
+	researcher := NewBaseAgent(
+		WithName("researcher"),
+		WithDescription("Finds facts"),
+		WithRole(Role{Title: "Senior Researcher", Expertise: []string{"search", "papers"}}),
+		WithLLM(mock.NewLLMProvider("researcher").AddResponse("facts")),
+	)
+	writer := NewBaseAgent(WithName("writer"), WithLLM(mock.NewLLMProvider("writer")))
+
+	tpl := prompt.MustNew("manager", "Team lead for {{.audience}}. Task: {{.task}}\n"+
+		"{{range .members}}* {{.Name}} knows {{join .Expertise \"/\"}}\n{{end}}")
+	team := NewTeam("team",
+		WithAgents(researcher, writer),
+		WithManager(NewBaseAgent(WithName("manager"), WithLLM(managerLLM))),
+		WithManagerPrompt(tpl),
+	)
+
+	_, err := team.Run(context.Background(), Input{Query: "write a report", Context: map[string]any{"audience": "students"}})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	plan := managerLLM.Calls()[0].Messages
+	got := plan[len(plan)-1].Content
+	for _, want := range []string{
+		"Team lead for students. Task: write a report",
+		"* researcher knows search/papers",
+		`{"assignments"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected manager prompt to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Team members:") {
+		t.Errorf("roster should not be appended when the template lists every member, got:\n%s", got)
+	}
+}
+
+func TestTeamMember_String(t *testing.T) {
+	m := TeamMember{Name: "researcher", Title: "Senior Researcher", Description: "Finds facts", Expertise: []string{"search", "papers"}}
+	want := "researcher (Senior Researcher): Finds facts. Expertise: search, papers"
+	if got := m.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestTeamDebateAdversarial(t *testing.T) {
+	proLLM := mock.NewLLMProvider("pro").AddResponse("pro 1").AddResponse("pro 2")
+	conLLM := mock.NewLLMProvider("con").AddResponse("con 1").AddResponse("con 2")
+	judgeLLM := mock.NewLLMProvider("judge").AddResponse("proponent wins")
+
+	team := NewTeam("debate",
+		WithDebate(
+			NewBaseAgent(WithName("pro"), WithLLM(proLLM)),
+			NewBaseAgent(WithName("con"), WithLLM(conLLM)),
+			NewBaseAgent(WithName("judge"), WithLLM(judgeLLM)),
+		),
+		WithDebateRounds(2),
+	)
+	if team.Mode() != TeamModeDebate {
+		t.Fatalf("expected Debate mode, got %v", team.Mode())
+	}
+
+	out, err := team.Run(context.Background(), Input{Query: "tabs are better than spaces"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if out.Content != "proponent wins" {
+		t.Errorf("expected judge decision, got %q", out.Content)
+	}
+
+	transcript, _ := out.Metadata[MetadataDebateTranscript].([]DebateTurn)
+	roles := make([]DebateRole, len(transcript))
+	for i, turn := range transcript {
+		roles[i] = turn.Role
+	}
+	want := []DebateRole{DebateRoleProponent, DebateRoleOpponent, DebateRoleProponent, DebateRoleOpponent, DebateRoleJudge}
+	if fmt.Sprint(roles) != fmt.Sprint(want) {
+		t.Fatalf("expected roles %v, got %v", want, roles)
+	}
+
+	judgeCall := judgeLLM.LastCall().Messages
+	if prompt := judgeCall[len(judgeCall)-1].Content; !strings.Contains(prompt, "con 2") {
+		t.Errorf("judge should see the full transcript, got %q", prompt)
+	}
+}
+
+func TestTeamDebateCritique(t *testing.T) {
+	genLLM := mock.NewLLMProvider("gen").AddResponse("draft").AddResponse("better draft")
+	criticLLM := mock.NewLLMProvider("critic").AddResponse("needs examples").AddResponse("APPROVED")
+
+	team := NewTeam("critique",
+		WithCritique(
+			NewBaseAgent(WithName("writer"), WithLLM(genLLM)),
+			NewBaseAgent(WithName("critic"), WithLLM(criticLLM)),
+		),
+		WithDebateRounds(5),
+	)
+
+	out, err := team.Run(context.Background(), Input{Query: "write a tagline"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if out.Content != "better draft" {
+		t.Errorf("expected revised artifact, got %q", out.Content)
+	}
+	if approved, _ := out.Metadata[MetadataDebateApproved].(bool); !approved {
+		t.Error("expected critic approval")
+	}
+	if rounds, _ := out.Metadata[MetadataDebateRounds].(int); rounds != 2 {
+		t.Errorf("expected 2 rounds, got %d", rounds)
+	}
+	revision := genLLM.LastCall().Messages
+	if prompt := revision[len(revision)-1].Content; !strings.Contains(prompt, "needs examples") {
+		t.Errorf("generator should see critic feedback, got %q", prompt)
+	}
+}
+
+func TestTeamDebateCritique_NotApproved(t *testing.T) {
+	genLLM := mock.NewLLMProvider("gen").AddResponse("draft").AddResponse("better draft")
+	criticLLM := mock.NewLLMProvider("critic").AddResponse("NOT APPROVED: too vague").AddResponse("Looks good.\nAPPROVED\n")
+
+	team := NewTeam("critique",
+		WithCritique(
+			NewBaseAgent(WithName("writer"), WithLLM(genLLM)),
+			NewBaseAgent(WithName("critic"), WithLLM(criticLLM)),
+		),
+		WithDebateRounds(5),
+	)
+
+	out, err := team.Run(context.Background(), Input{Query: "write a tagline"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if rounds, _ := out.Metadata[MetadataDebateRounds].(int); rounds != 2 || out.Content != "better draft" {
+		t.Errorf("\"NOT APPROVED\" should not end the loop: rounds=%d content=%q", rounds, out.Content)
+	}
+	if approved, _ := out.Metadata[MetadataDebateApproved].(bool); !approved {
+		t.Error("expected approval on its own line to count")
+	}
+}
+
+func TestTeamDebateTermination(t *testing.T) {
+	proLLM := mock.NewLLMProvider("pro").AddResponse("I concede")
+	conLLM := mock.NewLLMProvider("con").AddResponse("agreed")
+	judgeLLM := mock.NewLLMProvider("judge").AddResponse("settled")
+
+	team := NewTeam("debate",
+		WithDebate(
+			NewBaseAgent(WithName("pro"), WithLLM(proLLM)),
+			NewBaseAgent(WithName("con"), WithLLM(conLLM)),
+			NewBaseAgent(WithName("judge"), WithLLM(judgeLLM)),
+		),
+		WithDebateTermination(func(transcript []DebateTurn) bool {
+			return strings.Contains(transcript[len(transcript)-2].Content, "concede")
+		}),
+	)
+
+	out, err := team.Run(context.Background(), Input{Query: "topic"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if rounds, _ := out.Metadata[MetadataDebateRounds].(int); rounds != 1 {
+		t.Errorf("expected early termination after 1 round, got %d", rounds)
+	}
+}
+
+// transcriptFlow 将消息记录转换为 "from->to:content" 列表
+func transcriptFlow(tr *TeamTranscript) []string {
+	flow := make([]string, len(tr.Messages))
+	for i, m := range tr.Messages {
+		flow[i] = fmt.Sprintf("%s->%s:%s", m.From, m.To, m.Content)
+	}
+	return flow
+}
+
+func TestTeamTranscript_Sequential(t *testing.T) {
+	team := NewTeam("pipeline",
+		WithAgents(
+			NewBaseAgent(WithName("researcher"), WithLLM(mock.NewLLMProvider("researcher").AddResponse("facts"))),
+			NewBaseAgent(WithName("writer"), WithLLM(mock.NewLLMProvider("writer").AddResponse("article"))),
+		),
+	)
+	if team.LastTranscript() != nil {
+		t.Fatal("expected no transcript before the first run")
+	}
+
+	out, err := team.Run(context.Background(), Input{Query: "write about Go"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	tr, _ := out.Metadata[MetadataTeamTranscript].(*TeamTranscript)
+	if tr == nil || tr != team.LastTranscript() {
+		t.Fatalf("expected transcript in metadata and LastTranscript, got %v", tr)
+	}
+	want := []string{"user->researcher:write about Go", "researcher->writer:facts", "writer->user:article"}
+	if got := transcriptFlow(tr); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected flow %v, got %v", want, got)
+	}
+	for i, m := range tr.Messages {
+		if m.Seq != i+1 {
+			t.Errorf("message %d: expected seq %d, got %d", i, i+1, m.Seq)
+		}
+	}
+
+	data, err := json.Marshal(tr)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded TeamTranscript
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Team != "pipeline" || decoded.Mode != TeamModeSequential.String() || len(decoded.Messages) != 3 {
+		t.Errorf("unexpected decoded transcript: %+v", decoded)
+	}
+}
+
+func TestTeamTranscript_Failure(t *testing.T) {
+	team := NewTeam("pipeline",
+		WithAgents(
+			NewBaseAgent(WithName("researcher"), WithLLM(mock.NewLLMProvider("researcher").AddResponse("facts"))),
+			NewBaseAgent(WithName("writer"), WithLLM(mock.NewLLMProvider("writer"))),
+		),
+	)
+
+	if _, err := team.Run(context.Background(), Input{Query: "write about Go"}); err == nil {
+		t.Fatal("expected writer to fail")
+	}
+	tr := team.LastTranscript()
+	if tr == nil || tr.Error == "" {
+		t.Fatalf("expected failed run to be recorded, got %+v", tr)
+	}
+	last := tr.Messages[len(tr.Messages)-1]
+	if last.From != "writer" || last.Error == "" || last.Content != "" {
+		t.Errorf("expected the failing writer message last, got %+v", last)
+	}
+}
+
+func TestTeamTranscript_Modes(t *testing.T) {
+	t.Run("round robin", func(t *testing.T) {
+		team := NewTeam("rr",
+			WithMode(TeamModeRoundRobin),
+			WithMaxRounds(2),
+			WithAgents(
+				NewBaseAgent(WithName("a"), WithLLM(mock.NewLLMProvider("a").AddResponse("a1").AddResponse("a2"))),
+				NewBaseAgent(WithName("b"), WithLLM(mock.NewLLMProvider("b").AddResponse("b1").AddResponse("b2"))),
+			),
+		)
+		if _, err := team.Run(context.Background(), Input{Query: "go"}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		tr := team.LastTranscript()
+		want := []string{"user->a:go", "a->b:a1", "b->a:b1", "a->b:a2", "b->user:b2"}
+		if got := transcriptFlow(tr); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("expected flow %v, got %v", want, got)
+		}
+		if tr.Messages[3].Round != 2 {
+			t.Errorf("expected second round, got %d", tr.Messages[3].Round)
+		}
+	})
+
+	t.Run("hierarchical", func(t *testing.T) {
+		managerLLM := mock.NewLLMProvider("manager").
+			AddResponse(`{"assignments": [{"agent": "w1", "task": "part one"}]}`).
+			AddResponse("final")
+		team := NewTeam("h",
+			WithAgents(NewBaseAgent(WithName("w1"), WithLLM(mock.NewLLMProvider("w1").AddResponse("one done")))),
+			WithManager(NewBaseAgent(WithName("boss"), WithLLM(managerLLM))),
+		)
+		if _, err := team.Run(context.Background(), Input{Query: "task"}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		got := transcriptFlow(team.LastTranscript())
+		want := []string{
+			"user->boss:task",
+			`boss->team:{"assignments": [{"agent": "w1", "task": "part one"}]}`,
+			"boss->w1:part one",
+			"w1->boss:one done",
+			"boss->user:final",
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("expected flow %v, got %v", want, got)
+		}
+	})
+
+	t.Run("collaborative", func(t *testing.T) {
+		team := NewTeam("c",
+			WithMode(TeamModeCollaborative),
+			WithAgents(
+				NewBaseAgent(WithName("a"), WithLLM(mock.NewLLMProvider("a").AddResponse("from a"))),
+				NewBaseAgent(WithName("b"), WithLLM(mock.NewLLMProvider("b").AddResponse("from b"))),
+			),
+		)
+		if _, err := team.Run(context.Background(), Input{Query: "brainstorm"}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		got := transcriptFlow(team.LastTranscript())
+		if len(got) != 3 || got[0] != "user->team:brainstorm" {
+			t.Fatalf("unexpected flow %v", got)
+		}
+		replies := []string{got[1], got[2]}
+		sort.Strings(replies)
+		if fmt.Sprint(replies) != fmt.Sprint([]string{"a->user:from a", "b->user:from b"}) {
+			t.Errorf("unexpected replies %v", replies)
+		}
+	})
+
+	t.Run("debate", func(t *testing.T) {
+		team := NewTeam("d",
+			WithCritique(
+				NewBaseAgent(WithName("writer"), WithLLM(mock.NewLLMProvider("writer").AddResponse("draft"))),
+				NewBaseAgent(WithName("critic"), WithLLM(mock.NewLLMProvider("critic").AddResponse("APPROVED"))),
+			),
+		)
+		if _, err := team.Run(context.Background(), Input{Query: "tagline"}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		want := []string{"user->writer:tagline", "writer->critic:draft", "critic->writer:APPROVED"}
+		if got := transcriptFlow(team.LastTranscript()); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("expected flow %v, got %v", want, got)
+		}
+	})
+}
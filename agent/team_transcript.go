@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// ============== Team 消息记录 ==============
+
+// MetadataTeamTranscript 团队输出元数据中的消息记录，值为 *TeamTranscript
+const MetadataTeamTranscript = "team_transcript"
+
+// 消息记录中的非成员参与方
+const (
+	// TranscriptUser 团队的调用方：发出任务并接收最终结果
+	TranscriptUser = "user"
+
+	// TranscriptTeam 全体成员，用于广播的消息（如协作模式的任务、层级模式的分配计划）
+	TranscriptTeam = "team"
+)
+
+// TeamMessage 团队运行中的一条消息：谁对谁说了什么
+type TeamMessage struct {
+	// Seq 消息序号，从 1 开始，按记录先后递增
+	Seq int `json:"seq"`
+
+	// Round 轮次，从 1 开始；不分轮次的模式为 0
+	Round int `json:"round,omitempty"`
+
+	// From 发送方：TranscriptUser 或成员（Manager）名称
+	From string `json:"from"`
+
+	// To 接收方：TranscriptUser、TranscriptTeam 或成员（Manager）名称
+	To string `json:"to"`
+
+	// Content 消息内容
+	Content string `json:"content"`
+
+	// ToolCalls 发送方生成这条消息时的工具调用
+	ToolCalls []ToolCallRecord `json:"tool_calls,omitempty"`
+
+	// Usage 发送方生成这条消息时的 Token 使用
+	Usage llm.Usage `json:"usage,omitzero"`
+
+	// Error 发送方执行失败的原因，此时 Content 为空
+	Error string `json:"error,omitempty"`
+
+	// Duration 发送方生成这条消息的耗时，转发的任务为 0
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Timestamp 记录时间
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TeamTranscript 一次团队运行的完整消息记录
+//
+// 记录按实际消息流生成：顺序模式为 user → A → B → user 的接力，
+// 协作模式为向 team 广播任务后各成员回复 user，轮询模式按轮次接力，
+// 层级模式包含 Manager 的计划、分配、成员回复和汇总，Debate 模式为各方的发言。
+// 可直接 JSON 序列化，用于调试、审计或作为微调数据。
+type TeamTranscript struct {
+	// Team 团队名称
+	Team string `json:"team"`
+
+	// Mode 工作模式
+	Mode string `json:"mode"`
+
+	// Task 调用方的任务
+	Task string `json:"task"`
+
+	// Messages 按记录先后排列的消息
+	Messages []TeamMessage `json:"messages"`
+
+	// Error 运行失败的原因
+	Error string `json:"error,omitempty"`
+
+	// StartedAt 运行开始时间
+	StartedAt time.Time `json:"started_at"`
+
+	// Duration 运行总耗时
+	Duration time.Duration `json:"duration"`
+}
+
+// LastTranscript 返回最近一次结束的 Run 的消息记录，尚未运行时返回 nil
+//
+// 运行失败时记录同样保留，可用于排查在哪个成员处中断。
+// 并发运行时返回最后结束的一次；需要与具体运行对应时请读取输出元数据的 MetadataTeamTranscript。
+func (t *Team) LastTranscript() *TeamTranscript {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.lastTranscript
+}
+
+// transcriptRecorder 记录单次团队运行的消息，线程安全
+// nil *transcriptRecorder 的方法均为空操作
+type transcriptRecorder struct {
+	mu         sync.Mutex
+	transcript *TeamTranscript
+}
+
+// newTranscriptRecorder 开始记录团队运行
+func newTranscriptRecorder(t *Team, input Input) *transcriptRecorder {
+	return &transcriptRecorder{transcript: &TeamTranscript{
+		Team:      t.name,
+		Mode:      t.mode.String(),
+		Task:      input.Query,
+		Messages:  []TeamMessage{},
+		StartedAt: time.Now(),
+	}}
+}
+
+// send 记录转发的消息（任务、分配），不对应成员的一次执行
+func (r *transcriptRecorder) send(from, to string, round int, content string) {
+	r.add(TeamMessage{From: from, To: to, Round: round, Content: content})
+}
+
+// reply 记录成员一次执行产生的消息，err 非 nil 时记录失败原因
+func (r *transcriptRecorder) reply(from, to string, round int, output Output, err error, d time.Duration) {
+	msg := TeamMessage{
+		From:      from,
+		To:        to,
+		Round:     round,
+		Content:   output.Content,
+		ToolCalls: output.ToolCalls,
+		Usage:     output.Usage,
+		Duration:  d,
+	}
+	if err != nil {
+		msg.Content = ""
+		msg.Error = err.Error()
+	}
+	r.add(msg)
+}
+
+func (r *transcriptRecorder) add(msg TeamMessage) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msg.Seq = len(r.transcript.Messages) + 1
+	msg.Timestamp = time.Now()
+	r.transcript.Messages = append(r.transcript.Messages, msg)
+}
+
+// finish 结束记录并返回消息记录，之后不应再记录
+func (r *transcriptRecorder) finish(err error) *TeamTranscript {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transcript.Duration = time.Since(r.transcript.StartedAt)
+	if err != nil {
+		r.transcript.Error = err.Error()
+	}
+	return r.transcript
+}
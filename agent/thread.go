@@ -0,0 +1,354 @@
+// thread.go 提供会话线程持久化，让多会话聊天机器人在进程重启后保留对话历史
+//
+// 支持两种存储后端：
+//   - MemoryThreadStore: 内存存储（适合开发和测试）
+//   - FileThreadStore: 文件存储（每个线程一个 JSON 文件，适合单机生产环境）
+//
+// 使用示例：
+//
+//	store, _ := agent.NewFileThreadStore("./threads")
+//	// 仅 ReActAgent 支持线程存储（见 WithThreadStore）
+//	a := agent.NewReAct(agent.WithLLM(provider), agent.WithThreadStore(store))
+//
+//	// 同一 ThreadID 的调用自动加载并追加历史
+//	a.Run(ctx, agent.Input{Query: "我叫小明", ThreadID: "user-42"})
+//	a.Run(ctx, agent.Input{Query: "我叫什么？", ThreadID: "user-42"})
+//
+//	// 删除线程（如用户要求删除个人数据）
+//	store.Delete(ctx, "user-42")
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/hexagon/hooks"
+)
+
+// ErrThreadNotFound 会话线程未找到
+var ErrThreadNotFound = errors.New("agent: 会话线程未找到")
+
+// Thread 会话线程，保存一个会话的完整消息历史
+type Thread struct {
+	// ID 线程标识（通常为会话 ID 或用户 ID）
+	ID string `json:"id"`
+
+	// Messages 按时间顺序的消息
+	Messages []ConvMessage `json:"messages,omitempty"`
+
+	// CreatedAt 创建时间
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt 最后追加消息的时间
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ThreadStore 会话线程存储接口
+type ThreadStore interface {
+	// Load 加载线程，不存在时返回 ErrThreadNotFound
+	Load(ctx context.Context, id string) (*Thread, error)
+
+	// Append 向线程追加消息，线程不存在时自动创建
+	Append(ctx context.Context, id string, messages ...ConvMessage) error
+
+	// List 列出所有线程，按最后更新时间倒序
+	List(ctx context.Context) ([]*Thread, error)
+
+	// Delete 删除线程及其全部消息，不存在时返回 ErrThreadNotFound
+	Delete(ctx context.Context, id string) error
+}
+
+// WithThreadStore 设置会话线程存储
+//
+// 设置后，Input.ThreadID 非空的运行会从存储加载该线程的历史作为上下文
+// （替代 Agent 的 Memory），成功后将本轮的用户输入和回复追加到线程。
+//
+// 历史通过 ContextWithMemory 注入，因此仅对 SupportsContextMemory 为 true 的 Agent 生效
+// （目前为 ReActAgent）；其他 Agent 忽略该选项和 Input.ThreadID，既不加载也不保存线程。
+func WithThreadStore(store ThreadStore) Option {
+	return func(c *Config) {
+		c.ThreadStore = store
+	}
+}
+
+// ============== 内存存储 ==============
+
+// MemoryThreadStore 内存线程存储
+//
+// 适合开发和测试。数据不持久化，进程退出后丢失。
+// 线程安全。
+type MemoryThreadStore struct {
+	threads map[string]*Thread
+	mu      sync.RWMutex
+}
+
+// NewMemoryThreadStore 创建内存线程存储
+func NewMemoryThreadStore() *MemoryThreadStore {
+	return &MemoryThreadStore{
+		threads: make(map[string]*Thread),
+	}
+}
+
+func (s *MemoryThreadStore) Load(_ context.Context, id string) (*Thread, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, exists := s.threads[id]
+	if !exists {
+		return nil, ErrThreadNotFound
+	}
+	return t.clone(), nil
+}
+
+func (s *MemoryThreadStore) Append(_ context.Context, id string, messages ...ConvMessage) error {
+	if id == "" {
+		return fmt.Errorf("会话线程 ID 不能为空")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, exists := s.threads[id]
+	if !exists {
+		t = &Thread{ID: id, CreatedAt: time.Now()}
+		s.threads[id] = t
+	}
+	t.append(messages)
+	return nil
+}
+
+func (s *MemoryThreadStore) List(_ context.Context) ([]*Thread, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*Thread, 0, len(s.threads))
+	for _, t := range s.threads {
+		result = append(result, t.clone())
+	}
+	sortThreads(result)
+	return result, nil
+}
+
+func (s *MemoryThreadStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.threads[id]; !exists {
+		return ErrThreadNotFound
+	}
+	delete(s.threads, id)
+	return nil
+}
+
+// ============== 文件存储 ==============
+
+// FileThreadStore 文件线程存储
+//
+// 将每个线程保存为独立 JSON 文件，文件名为 {id}.json。
+// 删除线程即删除对应文件。单进程内线程安全。
+type FileThreadStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileThreadStore 创建文件线程存储
+//
+// dir 为存储目录，不存在时自动创建。
+func NewFileThreadStore(dir string) (*FileThreadStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建会话目录失败: %w", err)
+	}
+	return &FileThreadStore{dir: dir}, nil
+}
+
+func (s *FileThreadStore) Load(_ context.Context, id string) (*Thread, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(id)
+}
+
+func (s *FileThreadStore) Append(_ context.Context, id string, messages ...ConvMessage) error {
+	if id == "" {
+		return fmt.Errorf("会话线程 ID 不能为空")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.read(id)
+	if errors.Is(err, ErrThreadNotFound) {
+		t = &Thread{ID: id, CreatedAt: time.Now()}
+	} else if err != nil {
+		return err
+	}
+	t.append(messages)
+	return s.write(t)
+}
+
+func (s *FileThreadStore) List(_ context.Context) ([]*Thread, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取会话目录失败: %w", err)
+	}
+
+	var result []*Thread
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		t, err := s.read(id)
+		if err != nil {
+			continue
+		}
+		result = append(result, t)
+	}
+	sortThreads(result)
+	return result, nil
+}
+
+func (s *FileThreadStore) Delete(_ context.Context, id string) error {
+	path, err := safeJSONPath(s.dir, "会话", id)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return ErrThreadNotFound
+		}
+		return fmt.Errorf("删除会话文件失败: %w", err)
+	}
+	return nil
+}
+
+// read 读取线程文件（调用方须持有 s.mu）
+func (s *FileThreadStore) read(id string) (*Thread, error) {
+	path, err := safeJSONPath(s.dir, "会话", id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrThreadNotFound
+		}
+		return nil, fmt.Errorf("读取会话文件失败: %w", err)
+	}
+
+	var t Thread
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("反序列化会话失败: %w", err)
+	}
+	return &t, nil
+}
+
+// write 原子写入线程文件（调用方须持有 s.mu）
+func (s *FileThreadStore) write(t *Thread) error {
+	path, err := safeJSONPath(s.dir, "会话", t.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化会话失败: %w", err)
+	}
+
+	// 先写临时文件再重命名，避免进程中断留下损坏的历史
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("写入会话文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("写入会话文件失败: %w", err)
+	}
+	return nil
+}
+
+// ============== 辅助方法 ==============
+
+// append 追加消息并更新时间戳
+func (t *Thread) append(messages []ConvMessage) {
+	now := time.Now()
+	for _, m := range messages {
+		if m.Timestamp.IsZero() {
+			m.Timestamp = now
+		}
+		t.Messages = append(t.Messages, m)
+	}
+	t.UpdatedAt = now
+}
+
+// clone 深拷贝线程，防止外部修改影响已保存的数据
+func (t *Thread) clone() *Thread {
+	c := *t
+	c.Messages = slices.Clone(t.Messages)
+	return &c
+}
+
+// sortThreads 按最后更新时间倒序排序
+func sortThreads(threads []*Thread) {
+	slices.SortFunc(threads, func(a, b *Thread) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+}
+
+// beginThread 加载 Input.ThreadID 对应的历史，注入为本次运行的记忆
+// 未配置 ThreadStore 或 ThreadID 为空时原样返回 ctx
+func (a *BaseAgent) beginThread(ctx context.Context, input Input) (context.Context, error) {
+	store := a.config.ThreadStore
+	if store == nil || input.ThreadID == "" {
+		return ctx, nil
+	}
+
+	t, err := store.Load(ctx, input.ThreadID)
+	if errors.Is(err, ErrThreadNotFound) {
+		t = &Thread{ID: input.ThreadID}
+	} else if err != nil {
+		return ctx, fmt.Errorf("load thread %s: %w", input.ThreadID, err)
+	}
+
+	history := memory.NewBuffer(len(t.Messages) + 3)
+	for _, m := range t.Messages {
+		entry := memory.NewEntry(m.Role, m.Content)
+		if err := history.Save(ctx, entry); err != nil {
+			return ctx, fmt.Errorf("load thread %s: %w", input.ThreadID, err)
+		}
+	}
+	return ContextWithMemory(ctx, history), nil
+}
+
+// saveThread 将本轮对话追加到线程，失败时通过错误钩子报告
+func (a *BaseAgent) saveThread(ctx context.Context, runID string, input Input, output Output) {
+	store := a.config.ThreadStore
+	if store == nil || input.ThreadID == "" {
+		return
+	}
+	err := store.Append(ctx, input.ThreadID,
+		ConvMessage{Role: "user", Content: input.Query},
+		ConvMessage{Role: "assistant", Content: output.Content},
+	)
+	if err == nil {
+		return
+	}
+	if hookManager := hooks.ManagerFromContext(ctx); hookManager != nil {
+		hookManager.TriggerError(ctx, &hooks.ErrorEvent{
+			RunID:   runID,
+			AgentID: a.ID(),
+			Error:   fmt.Errorf("save thread %s: %w", input.ThreadID, err),
+			Phase:   "thread_save",
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func testThreadStore(t *testing.T, store ThreadStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := store.Load(ctx, "missing"); !errors.Is(err, ErrThreadNotFound) {
+		t.Fatalf("expected ErrThreadNotFound, got %v", err)
+	}
+
+	if err := store.Append(ctx, "a", ConvMessage{Role: "user", Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append(ctx, "b", ConvMessage{Role: "user", Content: "yo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append(ctx, "a", ConvMessage{Role: "assistant", Content: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	thread, err := store.Load(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thread.Messages) != 2 || thread.Messages[1].Content != "hello" || thread.Messages[1].Timestamp.IsZero() {
+		t.Errorf("unexpected messages: %+v", thread.Messages)
+	}
+
+	threads, err := store.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 2 || threads[0].ID != "a" {
+		t.Errorf("expected most recently updated thread first, got %+v", threads)
+	}
+
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, "a"); !errors.Is(err, ErrThreadNotFound) {
+		t.Errorf("expected ErrThreadNotFound on second delete, got %v", err)
+	}
+	if _, err := store.Load(ctx, "a"); !errors.Is(err, ErrThreadNotFound) {
+		t.Errorf("deleted thread should not load, got %v", err)
+	}
+}
+
+func TestMemoryThreadStore(t *testing.T) {
+	testThreadStore(t, NewMemoryThreadStore())
+}
+
+func TestFileThreadStore(t *testing.T) {
+	store, err := NewFileThreadStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testThreadStore(t, store)
+
+	if err := store.Append(context.Background(), "../escape", ConvMessage{Role: "user", Content: "x"}); err == nil {
+		t.Error("expected path traversal to be rejected")
+	}
+}
+
+func TestWithThreadStore_PersistsAcrossAgents(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store, err := NewFileThreadStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := NewReAct(WithLLM(mock.NewLLMProvider("mock").AddResponse("nice to meet you")), WithThreadStore(store))
+	if _, err := first.Run(ctx, Input{Query: "my name is Ada", ThreadID: "t1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟进程重启：新的存储实例和新的 Agent
+	store, err = NewFileThreadStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := mock.NewLLMProvider("mock").AddResponse("Ada").AddResponse("who?")
+	second := NewReAct(WithLLM(provider), WithThreadStore(store))
+	if _, err := second.Run(ctx, Input{Query: "what is my name?", ThreadID: "t1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var history []string
+	for _, m := range provider.LastCall().Messages[1:] {
+		history = append(history, string(m.Role)+":"+m.Content)
+	}
+	want := "user:my name is Ada|assistant:nice to meet you|user:what is my name?"
+	if got := strings.Join(history, "|"); got != want {
+		t.Errorf("expected history %q, got %q", want, got)
+	}
+
+	// 其他线程互不影响
+	if _, err := second.Run(ctx, Input{Query: "hello", ThreadID: "t2"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(provider.LastCall().Messages); n != 2 {
+		t.Errorf("expected new thread to start empty, got %d messages", n)
+	}
+
+	thread, err := store.Load(ctx, "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thread.Messages) != 4 {
+		t.Errorf("expected 4 persisted messages, got %d", len(thread.Messages))
+	}
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"context"
+
+	agentruntime "github.com/hexagon-codes/hexagon/runtime"
+)
+
+// TokenHandler 接收 LLM 流式生成的文本增量，返回错误时中止本次运行
+type TokenHandler func(content string) error
+
+// ContextWithTokenHandler 在 context 中设置本次运行的 Token 回调
+//
+// 设置后 Agent 以流式方式调用 LLM，每收到一段文本增量调用一次 fn。
+// 增量包括调用工具前的中间回复，最终结果以 Run 的返回值为准；命中缓存时不回调。
+// 增量无法经过输出解析和守卫，因此 Agent 配置了输出守卫（WithOutputGuards）或
+// 输出解析器（WithOutputParser）时不回调，调用方应使用 Run 返回的结果。
+// 目前仅 ReActAgent 支持（见 SupportsContextMemory）。
+func ContextWithTokenHandler(ctx context.Context, fn TokenHandler) context.Context {
+	return context.WithValue(ctx, tokenHandlerKey{}, fn)
+}
+
+// tokenHandlerKey Token 回调的 context key
+type tokenHandlerKey struct{}
+
+// tokenHandlerFromContext 返回 context 中的 Token 回调，未设置时返回 nil
+func tokenHandlerFromContext(ctx context.Context) TokenHandler {
+	fn, _ := ctx.Value(tokenHandlerKey{}).(TokenHandler)
+	return fn
+}
+
+// tokenHandler 返回本次运行的 Token 回调
+// 配置了输出守卫或输出解析器时返回 nil，避免未经处理的内容（如需脱敏的 PII）流向调用方
+func (a *BaseAgent) tokenHandler(ctx context.Context) TokenHandler {
+	if a.config.OutputParser != nil {
+		return nil
+	}
+	if g := a.config.OutputGuard; g != nil && g.Enabled() {
+		return nil
+	}
+	return tokenHandlerFromContext(ctx)
+}
+
+// tokenSink 将 LLM 文本增量转发给 fn，其余事件交给 next
+func tokenSink(fn TokenHandler, next agentruntime.EventSink) agentruntime.EventSink {
+	return agentruntime.EventSinkFunc(func(ctx context.Context, event agentruntime.Event) error {
+		if event.Type == agentruntime.EventLLMChunk && event.Chunk != nil && event.Chunk.Content != "" {
+			if err := fn(event.Chunk.Content); err != nil {
+				return err
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		return next.Emit(ctx, event)
+	})
+}
@@ -0,0 +1,245 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/tool"
+	hexatool "github.com/hexagon-codes/hexagon/tool"
+)
+
+const (
+	// defaultToolCacheMaxEntries 工具结果缓存的默认容量
+	defaultToolCacheMaxEntries = 1000
+
+	// defaultToolCacheTTL 工具结果缓存的默认有效期
+	defaultToolCacheTTL = 5 * time.Minute
+)
+
+// ToolCache 工具结果缓存
+//
+// 按工具名称和参数（规范化 JSON 的 SHA-256）缓存成功的执行结果，
+// 相同参数再次调用时直接返回缓存结果，不再执行工具，适合单位换算、静态查询等确定性工具。
+// 执行失败的结果不缓存；有副作用的工具（发送邮件、写入数据库）或结果依赖调用者的工具
+// 应通过 tool.WithCacheable(t, false) 或实现 tool.Cacheable 退出缓存。
+//
+// 缓存按 scope 隔离：Agent 使用 Agent 名称（或 WithCacheScope）、工具策略的授权主体
+// 和会话线程 ID，不同用户或会话不会共享结果。条目默认 5 分钟后过期。
+//
+// 缓存保存在内存中，结果按原值返回，不经序列化。可在多个 Agent 间共享。
+type ToolCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]toolCacheEntry
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type toolCacheEntry struct {
+	result    tool.Result
+	createdAt time.Time
+}
+
+// ToolCacheOption ToolCache 配置选项
+type ToolCacheOption func(*ToolCache)
+
+// WithToolCacheTTL 设置缓存有效期，默认 5 分钟，<= 0 时不过期
+func WithToolCacheTTL(ttl time.Duration) ToolCacheOption {
+	return func(c *ToolCache) {
+		c.ttl = ttl
+	}
+}
+
+// WithToolCacheMaxEntries 设置最大缓存条目数，默认 1000
+// 超出时先清除过期条目，仍不足时淘汰最早写入的条目
+func WithToolCacheMaxEntries(n int) ToolCacheOption {
+	return func(c *ToolCache) {
+		if n > 0 {
+			c.maxEntries = n
+		}
+	}
+}
+
+// NewToolCache 创建工具结果缓存
+func NewToolCache(opts ...ToolCacheOption) *ToolCache {
+	c := &ToolCache{
+		ttl:        defaultToolCacheTTL,
+		maxEntries: defaultToolCacheMaxEntries,
+		entries:    make(map[string]toolCacheEntry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ToolCacheStats 工具结果缓存统计
+type ToolCacheStats struct {
+	// Hits 命中次数
+	Hits int64 `json:"hits"`
+
+	// Misses 未命中次数
+	Misses int64 `json:"misses"`
+
+	// HitRate 命中率
+	HitRate float64 `json:"hit_rate"`
+
+	// Entries 当前缓存条目数
+	Entries int `json:"entries"`
+}
+
+// Stats 返回缓存统计
+func (c *ToolCache) Stats() ToolCacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	hits, misses := c.hits.Load(), c.misses.Load()
+	stats := ToolCacheStats{Hits: hits, Misses: misses, Entries: entries}
+	if total := hits + misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	return stats
+}
+
+// Clear 清空缓存
+func (c *ToolCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// Execute 执行工具，可缓存的工具先在 scope 内查找缓存
+// scope 隔离不同调用方的结果，为空时在所有调用方间共享
+func (c *ToolCache) Execute(ctx context.Context, scope string, t tool.Tool, args map[string]any) (tool.Result, error) {
+	if !hexatool.IsCacheable(t) {
+		return t.Execute(ctx, args)
+	}
+	key, err := toolCacheKey(scope, t.Name(), args)
+	if err != nil {
+		return t.Execute(ctx, args)
+	}
+	if result, ok := c.lookup(key); ok {
+		return result, nil
+	}
+
+	result, err := t.Execute(ctx, args)
+	if err == nil && result.Success {
+		c.put(key, result)
+	}
+	return result, err
+}
+
+// toolCacheKey 计算工具调用的缓存键，map 序列化时键已排序
+func toolCacheKey(scope, name string, args map[string]any) (string, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return scope + "\x00" + name + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// lookup 查找未过期的缓存结果
+func (c *ToolCache) lookup(key string) (tool.Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok && c.expired(entry, time.Now()) {
+		delete(c.entries, key)
+		ok = false
+	}
+	if !ok {
+		c.misses.Add(1)
+		return tool.Result{}, false
+	}
+	c.hits.Add(1)
+	return entry.result, true
+}
+
+// put 写入结果，容量不足时淘汰旧条目
+func (c *ToolCache) put(key string, result tool.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = toolCacheEntry{result: result, createdAt: now}
+}
+
+// evict 清除过期条目，仍然已满时淘汰最早写入的条目，调用方持有锁
+func (c *ToolCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for k, entry := range c.entries {
+		if c.expired(entry, now) {
+			delete(c.entries, k)
+			continue
+		}
+		if oldestKey == "" || entry.createdAt.Before(oldest) {
+			oldestKey, oldest = k, entry.createdAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		delete(c.entries, oldestKey)
+	}
+}
+
+func (c *ToolCache) expired(entry toolCacheEntry, now time.Time) bool {
+	return c.ttl > 0 && now.Sub(entry.createdAt) >= c.ttl
+}
+
+// WithToolCache 为 Agent 启用工具结果缓存
+//
+// ReActAgent 和 PlanExecuteAgent 执行工具时按工具名称和参数查找缓存，
+// 命中时不执行工具，工具钩子照常触发。c 为 nil 时使用默认配置的缓存。
+// 缓存按 Agent、授权主体和会话线程隔离（见 ToolCache）。
+//
+// 示例：
+//
+//	toolCache := agent.NewToolCache(agent.WithToolCacheTTL(10 * time.Minute))
+//	a := agent.NewReAct(
+//	    agent.WithLLM(provider),
+//	    agent.WithTools(converter, tool.WithCacheable(sendEmail, false)),
+//	    agent.WithToolCache(toolCache),
+//	)
+//	fmt.Println(toolCache.Stats().HitRate)
+func WithToolCache(c *ToolCache) Option {
+	return func(cfg *Config) {
+		if c == nil {
+			c = NewToolCache()
+		}
+		cfg.ToolCache = c
+	}
+}
+
+// toolCacheScope 返回本次运行的工具缓存范围：语义缓存的范围（Agent 名称与授权主体）加会话线程 ID
+func (a *BaseAgent) toolCacheScope(ctx context.Context, threadID string) string {
+	if a.config.ToolCache == nil {
+		return ""
+	}
+	scope := a.cacheScope(ctx)
+	if threadID != "" {
+		scope += "#" + threadID
+	}
+	return scope
+}
+
+// executeTool 执行工具，配置了工具结果缓存时经过缓存
+func executeTool(ctx context.Context, c *ToolCache, scope string, t tool.Tool, args map[string]any) (tool.Result, error) {
+	if c == nil {
+		return t.Execute(ctx, args)
+	}
+	return c.Execute(ctx, scope, t, args)
+}
@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+	hexatool "github.com/hexagon-codes/hexagon/tool"
+)
+
+func TestReActAgentToolCache(t *testing.T) {
+	mockLLM := mock.NewLLMProvider("tool-cache")
+	mockLLM.AddToolCallResponse([]llm.ToolCall{
+		{ID: "call_1", Type: "function", Name: "convert", Arguments: `{"value": 1, "unit": "km"}`},
+		{ID: "call_2", Type: "function", Name: "send_email", Arguments: `{"to": "boss"}`},
+	})
+	mockLLM.AddToolCallResponse([]llm.ToolCall{
+		// 参数顺序不同，规范化后命中
+		{ID: "call_3", Type: "function", Name: "convert", Arguments: `{"unit": "km", "value": 1}`},
+		{ID: "call_4", Type: "function", Name: "send_email", Arguments: `{"to": "boss"}`},
+	})
+	mockLLM.AddResponse("done")
+
+	convert := mock.FixedTool("convert", "1000 m")
+	email := mock.FixedTool("send_email", "sent")
+	toolCache := NewToolCache()
+	a := NewReAct(
+		WithLLM(mockLLM),
+		WithTools(convert, hexatool.WithCacheable(email, false)),
+		WithToolCache(toolCache),
+	)
+
+	output, err := a.Run(context.Background(), Input{Query: "convert and email"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if convert.CallCount() != 1 {
+		t.Errorf("expected cached tool to run once, got %d", convert.CallCount())
+	}
+	if email.CallCount() != 2 {
+		t.Errorf("expected non-cacheable tool to run every time, got %d", email.CallCount())
+	}
+	if len(output.ToolCalls) != 4 || output.ToolCalls[2].Result.Output != "1000 m" {
+		t.Errorf("expected cached result in tool call records, got %+v", output.ToolCalls)
+	}
+	if stats := toolCache.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestToolCache_TTLAndEviction(t *testing.T) {
+	ctx := context.Background()
+	lookup := mock.FixedTool("lookup", "v")
+
+	c := NewToolCache(WithToolCacheTTL(time.Millisecond))
+	c.Execute(ctx, "", lookup, map[string]any{"k": 1})
+	time.Sleep(5 * time.Millisecond)
+	c.Execute(ctx, "", lookup, map[string]any{"k": 1})
+	if lookup.CallCount() != 2 {
+		t.Errorf("expected expired entry to be re-executed, got %d calls", lookup.CallCount())
+	}
+
+	c = NewToolCache(WithToolCacheMaxEntries(2))
+	for i := range 3 {
+		c.Execute(ctx, "", lookup, map[string]any{"k": i})
+	}
+	if n := c.Stats().Entries; n != 2 {
+		t.Errorf("expected 2 entries after eviction, got %d", n)
+	}
+
+	// 失败结果不缓存
+	failing := mock.NewTool("failing").AddErrorResult(errors.New("boom"))
+	c.Execute(ctx, "", failing, nil)
+	c.Execute(ctx, "", failing, nil)
+	if failing.CallCount() != 2 {
+		t.Errorf("expected failed result not to be cached, got %d calls", failing.CallCount())
+	}
+
+	c.Clear()
+	if n := c.Stats().Entries; n != 0 {
+		t.Errorf("expected empty cache after Clear, got %d", n)
+	}
+}
+
+func TestToolCache_WrappedOptOutAndScope(t *testing.T) {
+	ctx := context.Background()
+
+	// 被其他中间件再次包装后，退出缓存的标记仍然有效
+	email := mock.FixedTool("send_email", "sent")
+	wrapped := hexatool.WithTimeout(hexatool.WithRetry(hexatool.WithCacheable(email, false), 1, time.Millisecond), time.Second)
+	if hexatool.IsCacheable(wrapped) {
+		t.Fatal("expected wrapped opt-out to be visible")
+	}
+	c := NewToolCache()
+	c.Execute(ctx, "", wrapped, map[string]any{"to": "boss"})
+	c.Execute(ctx, "", wrapped, map[string]any{"to": "boss"})
+	if email.CallCount() != 2 {
+		t.Errorf("expected wrapped non-cacheable tool to run every time, got %d", email.CallCount())
+	}
+
+	// 不同范围（用户、会话）不共享结果
+	whoami := mock.FixedTool("whoami", "alice")
+	c.Execute(ctx, "agent/alice", whoami, nil)
+	c.Execute(ctx, "agent/bob", whoami, nil)
+	c.Execute(ctx, "agent/alice", whoami, nil)
+	if whoami.CallCount() != 2 {
+		t.Errorf("expected one execution per scope, got %d", whoami.CallCount())
+	}
+}
+
+func TestReActAgentToolCache_ThreadScope(t *testing.T) {
+	lookup := mock.FixedTool("lookup", "v")
+	toolCache := NewToolCache()
+	newAgent := func() *ReActAgent {
+		mockLLM := mock.NewLLMProvider("tool-cache-thread")
+		mockLLM.AddToolCallResponse([]llm.ToolCall{{ID: "call_1", Name: "lookup", Arguments: `{}`}})
+		mockLLM.AddResponse("done")
+		return NewReAct(WithName("assistant"), WithLLM(mockLLM), WithTools(lookup),
+			WithToolCache(toolCache), WithThreadStore(NewMemoryThreadStore()))
+	}
+
+	for _, thread := range []string{"t1", "t2", "t1"} {
+		if _, err := newAgent().Run(context.Background(), Input{Query: "look it up", ThreadID: thread}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+	if lookup.CallCount() != 2 {
+		t.Errorf("expected one execution per thread, got %d", lookup.CallCount())
+	}
+}
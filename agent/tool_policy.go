@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/ai-core/tool"
+	"github.com/hexagon-codes/hexagon/security/rbac"
+)
+
+// ToolPolicy 基于 RBAC 的工具访问策略
+//
+// 每个工具对应资源 "tool:<工具名>"、操作 "execute"。
+// 运行开始时未授权的工具不会提供给模型，工具调用前再次授权检查。
+type ToolPolicy struct {
+	rbac    *rbac.RBAC
+	subject func(ctx context.Context) string
+}
+
+// NewToolPolicy 创建工具访问策略
+// subjectFromCtx 从 context 中取出授权主体（用户 ID），为 nil 时使用 rbac.UserFromContext
+func NewToolPolicy(r *rbac.RBAC, subjectFromCtx func(ctx context.Context) string) *ToolPolicy {
+	if subjectFromCtx == nil {
+		subjectFromCtx = func(ctx context.Context) string {
+			if user := rbac.UserFromContext(ctx); user != nil {
+				return user.ID
+			}
+			return ""
+		}
+	}
+	return &ToolPolicy{rbac: r, subject: subjectFromCtx}
+}
+
+// WithToolPolicy 按调用者角色限制 Agent 可调用的工具
+//
+// 无法从 context 中取得主体时拒绝所有工具。
+func WithToolPolicy(r *rbac.RBAC, subjectFromCtx func(ctx context.Context) string) Option {
+	return func(c *Config) {
+		c.ToolPolicy = NewToolPolicy(r, subjectFromCtx)
+	}
+}
+
+// ToolResource 返回工具对应的 RBAC 资源名
+func ToolResource(name string) string {
+	return "tool:" + name
+}
+
+// Allow 检查当前主体是否可以执行指定工具
+func (p *ToolPolicy) Allow(ctx context.Context, toolName string) bool {
+	subject := p.subject(ctx)
+	if subject == "" {
+		return false
+	}
+	return p.rbac.Authorize(rbac.AccessRequest{
+		Subject:  subject,
+		Resource: ToolResource(toolName),
+		Action:   "execute",
+	}).Allowed
+}
+
+// Filter 返回当前主体可以执行的工具
+func (p *ToolPolicy) Filter(ctx context.Context, tools []tool.Tool) []tool.Tool {
+	allowed := make([]tool.Tool, 0, len(tools))
+	for _, t := range tools {
+		if p.Allow(ctx, t.Name()) {
+			allowed = append(allowed, t)
+		}
+	}
+	return allowed
+}
+
+// allowedTools 返回本次运行可用的工具
+func (a *BaseAgent) allowedTools(ctx context.Context) []tool.Tool {
+	if a.config.ToolPolicy == nil {
+		return a.config.Tools
+	}
+	return a.config.ToolPolicy.Filter(ctx, a.config.Tools)
+}
+
+// toolAllowed 检查工具调用是否被允许
+func (a *BaseAgent) toolAllowed(ctx context.Context, name string) bool {
+	return a.config.ToolPolicy == nil || a.config.ToolPolicy.Allow(ctx, name)
+}
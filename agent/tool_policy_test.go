@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/tool"
+	"github.com/hexagon-codes/hexagon/security/rbac"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func newToolPolicyRBAC(t *testing.T) *rbac.RBAC {
+	t.Helper()
+	ctx := context.Background()
+	r := rbac.NewRBAC()
+	if err := r.AddRole(ctx, &rbac.Role{
+		Name:        "searcher",
+		Permissions: []rbac.Permission{{Resource: ToolResource("search"), Action: "execute"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddUser(ctx, &rbac.User{ID: "guest-1", Roles: []string{"searcher"}}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestReActAgentToolPolicy(t *testing.T) {
+	r := newToolPolicyRBAC(t)
+	ctx := rbac.ContextWithUser(context.Background(), &rbac.User{ID: "guest-1"})
+
+	mockLLM := mock.NewLLMProvider("policy")
+	mockLLM.AddToolCallResponse([]llm.ToolCall{
+		{ID: "call_1", Type: "function", Name: "send_email", Arguments: `{}`},
+	})
+	mockLLM.AddResponse("done")
+
+	emailSent := false
+	email := mock.NewTool("send_email", mock.WithToolExecuteFn(func(ctx context.Context, args map[string]any) (tool.Result, error) {
+		emailSent = true
+		return tool.NewResult("sent"), nil
+	}))
+
+	agent := NewReAct(
+		WithLLM(mockLLM),
+		WithTools(mock.FixedTool("search", "ok"), email),
+		WithToolPolicy(r, nil),
+	)
+	output, err := agent.Run(ctx, Input{Query: "email my boss"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	offered := mockLLM.Calls()[0].Tools
+	if len(offered) != 1 || offered[0].Function.Name != "search" {
+		t.Errorf("expected only search to be offered, got %+v", offered)
+	}
+	if emailSent {
+		t.Error("forbidden tool should not be executed")
+	}
+	if len(output.ToolCalls) != 1 || output.ToolCalls[0].Result.Success {
+		t.Errorf("expected failed tool call record, got %+v", output.ToolCalls)
+	}
+}
+
+func TestToolPolicyNoSubject(t *testing.T) {
+	policy := NewToolPolicy(newToolPolicyRBAC(t), nil)
+	if policy.Allow(context.Background(), "search") {
+		t.Error("expected deny without subject")
+	}
+
+	custom := NewToolPolicy(newToolPolicyRBAC(t), func(context.Context) string { return "guest-1" })
+	if !custom.Allow(context.Background(), "search") || custom.Allow(context.Background(), "send_email") {
+		t.Error("unexpected custom subject decisions")
+	}
+}
@@ -0,0 +1,118 @@
+// Package compose 提供 Hexagon 框架的编排能力
+//
+// 本文件实现各编排抽象到 core.Runnable 的适配器：
+//   - FromAgent: Agent → Runnable[agent.Input, agent.Output]
+//   - FromTeam: Team → Runnable[agent.Input, agent.Output]
+//   - FromChain: Chain[I, O] → Runnable[I, O]
+//   - FromGraph: Graph[S] → Runnable[S, S]
+//
+// 适配后可以统一套用 core.WithRetry、core.WithFallback、core.WithCircuitBreaker，
+// 并通过 Pipe、Then 与其他 Runnable 组合。
+// 适配器的 Invoke 遵循 core.WithTimeout 选项：设置后以超时 context 执行。
+//
+// 适配器位于 compose 而不是 core，因为 agent、graph 等包依赖 core。
+//
+// 使用示例：
+//
+//	researcher := compose.FromAgent(researchAgent)
+//	resilient := core.WithFallback(core.WithRetry(researcher), compose.FromAgent(backupAgent))
+//	out, err := resilient.Invoke(ctx, agent.Input{Query: "..."}, core.WithTimeout(30000))
+package compose
+
+import (
+	"context"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/orchestration/chain"
+	"github.com/hexagon-codes/hexagon/orchestration/graph"
+	"github.com/hexagon-codes/hexagon/stream"
+)
+
+// FromAgent 将 Agent 适配为 Runnable
+//
+// 输入输出沿用 agent.Input / agent.Output：Input.Query 为用户问题，
+// Output.Content 为最终回答，Output.ToolCalls 等字段保留执行细节，不丢失信息。
+func FromAgent(a agent.Agent) core.Runnable[agent.Input, agent.Output] {
+	return &timeoutRunnable[agent.Input, agent.Output]{Runnable: a}
+}
+
+// FromTeam 将 Team 适配为 Runnable
+//
+// 输入输出与 FromAgent 相同，团队可以和单个 Agent 互相替换（如作为彼此的降级）。
+func FromTeam(t *agent.Team) core.Runnable[agent.Input, agent.Output] {
+	return &timeoutRunnable[agent.Input, agent.Output]{Runnable: t}
+}
+
+// FromChain 将 Chain 适配为 Runnable
+//
+// 输入输出为链声明的类型参数 I / O，链内部步骤之间仍以 any 传递。
+func FromChain[I, O any](c *chain.Chain[I, O]) core.Runnable[I, O] {
+	return &timeoutRunnable[I, O]{Runnable: c}
+}
+
+// FromGraph 将图适配为 Runnable
+//
+// 输入为初始状态，输出为执行结束时的状态，类型均为图的状态类型 S；
+// 需要从状态中提取字段时可以用 Pipe 接一个 Lambda。
+// opts 在每次执行时传给 Run / Stream。
+// Stream 在每个节点完成后发送当时的状态，最后一个元素即最终状态；
+// 节点失败时流以该错误结束。
+func FromGraph[S graph.State](g *graph.Graph[S], opts ...graph.RunOption) core.Runnable[S, S] {
+	r := core.NewRunnable(g.Name, "graph "+g.Name, func(ctx context.Context, input S, copts ...core.Option) (S, error) {
+		ctx, cancel := withOptionTimeout(ctx, copts)
+		defer cancel()
+		return g.Run(ctx, input, opts...)
+	})
+	return r.WithStream(func(ctx context.Context, input S, _ ...core.Option) (*stream.StreamReader[S], error) {
+		ctx, cancel := context.WithCancel(ctx)
+		events, err := g.Stream(ctx, input, opts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		reader, writer := stream.Pipe[S](10)
+		go func() {
+			defer func() {
+				// 提前结束时取消执行并排空事件，避免图的执行 goroutine 阻塞
+				cancel()
+				for range events {
+				}
+			}()
+			for event := range events {
+				switch event.Type {
+				case graph.EventTypeNodeEnd:
+					if writer.Send(event.State) != nil {
+						return
+					}
+				case graph.EventTypeError:
+					writer.CloseWithError(event.Error)
+					return
+				}
+			}
+			writer.Close()
+		}()
+		return reader, nil
+	})
+}
+
+// timeoutRunnable 透传 Runnable，Invoke 遵循 core.WithTimeout 选项
+type timeoutRunnable[I, O any] struct {
+	core.Runnable[I, O]
+}
+
+func (r *timeoutRunnable[I, O]) Invoke(ctx context.Context, input I, opts ...core.Option) (O, error) {
+	ctx, cancel := withOptionTimeout(ctx, opts)
+	defer cancel()
+	return r.Runnable.Invoke(ctx, input, opts...)
+}
+
+// withOptionTimeout 按 core.WithTimeout 选项（毫秒）派生超时 context
+func withOptionTimeout(ctx context.Context, opts []core.Option) (context.Context, context.CancelFunc) {
+	if o := core.ApplyOptions(opts...); o.Timeout > 0 {
+		return context.WithTimeout(ctx, time.Duration(o.Timeout)*time.Millisecond)
+	}
+	return ctx, func() {}
+}
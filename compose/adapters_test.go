@@ -0,0 +1,129 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/orchestration/chain"
+	"github.com/hexagon-codes/hexagon/orchestration/graph"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// counterState 测试用图状态
+type counterState struct {
+	Count int
+	Path  string
+}
+
+func (s counterState) Clone() graph.State { return s }
+
+// buildCounterGraph 构建依次执行 a、b 两个节点的图，fail 不为 nil 时 b 返回该错误
+func buildCounterGraph(t *testing.T, fail error) *graph.Graph[counterState] {
+	t.Helper()
+	step := func(name string, err error) graph.NodeHandler[counterState] {
+		return func(ctx context.Context, s counterState) (counterState, error) {
+			s.Count++
+			s.Path += name
+			return s, err
+		}
+	}
+	g, err := graph.NewGraph[counterState]("counter").
+		AddNode("a", step("a", nil)).
+		AddNode("b", step("b", fail)).
+		AddEdge(graph.START, "a").
+		AddEdge("a", "b").
+		AddEdge("b", graph.END).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// TestFromAgent 测试 Agent 适配后可套用降级
+func TestFromAgent(t *testing.T) {
+	primary := agent.NewReAct(agent.WithLLM(mock.NewLLMProvider("primary").AddErrorResponse(errors.New("down"))))
+	backup := agent.NewReAct(agent.WithLLM(mock.NewLLMProvider("backup").AddResponse("from backup")))
+
+	r := core.WithFallback(FromAgent(primary), FromAgent(backup))
+	out, err := r.Invoke(context.Background(), agent.Input{Query: "hi"})
+	if err != nil || out.Content != "from backup" {
+		t.Fatalf("Invoke = %q, %v", out.Content, err)
+	}
+}
+
+// TestFromTeam 测试 Team 适配
+func TestFromTeam(t *testing.T) {
+	member := agent.NewReAct(agent.WithLLM(mock.NewLLMProvider("m").AddResponse("team answer")))
+	team := agent.NewTeam("team", agent.WithAgents(member))
+
+	out, err := Pipe(FromTeam(team), Lambda(func(o agent.Output) string { return o.Content })).
+		Invoke(context.Background(), agent.Input{Query: "hi"})
+	if err != nil || out != "team answer" {
+		t.Fatalf("Invoke = %q, %v", out, err)
+	}
+}
+
+// TestFromChain 测试 Chain 适配及 WithTimeout 选项
+func TestFromChain(t *testing.T) {
+	c := chain.NewChain[string, string]("upper").
+		PipeFunc("upper", func(ctx context.Context, input any) (any, error) {
+			return strings.ToUpper(input.(string)), nil
+		}).
+		MustBuild()
+
+	out, err := FromChain(c).Invoke(context.Background(), "abc")
+	if err != nil || out != "ABC" {
+		t.Fatalf("Invoke = %q, %v", out, err)
+	}
+
+	slow := chain.NewChain[string, string]("slow").
+		PipeFunc("wait", func(ctx context.Context, input any) (any, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return input, nil
+			}
+		}).
+		MustBuild()
+	if _, err := FromChain(slow).Invoke(context.Background(), "x", core.WithTimeout(10)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("期望超时，得到 %v", err)
+	}
+}
+
+// TestFromGraph 测试图适配的 Invoke 和 Stream
+func TestFromGraph(t *testing.T) {
+	r := FromGraph(buildCounterGraph(t, nil))
+	if r.Name() != "counter" {
+		t.Errorf("Name = %q", r.Name())
+	}
+
+	out, err := r.Invoke(context.Background(), counterState{})
+	if err != nil || out.Count != 2 || out.Path != "ab" {
+		t.Fatalf("Invoke = %+v, %v", out, err)
+	}
+
+	sr, err := r.Stream(context.Background(), counterState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	states, err := sr.Collect(context.Background())
+	if err != nil || len(states) != 2 || states[0].Path != "a" || states[1].Path != "ab" {
+		t.Errorf("Stream = %+v, %v", states, err)
+	}
+
+	boom := errors.New("boom")
+	sr, err = FromGraph(buildCounterGraph(t, boom)).Stream(context.Background(), counterState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sr.Collect(context.Background()); !errors.Is(err, boom) {
+		t.Errorf("期望节点错误，得到 %v", err)
+	}
+}
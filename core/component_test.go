@@ -2053,6 +2053,14 @@ func TestValidator_TypeInteger(t *testing.T) {
 	if err := v.Validate(s, "hello"); err == nil {
 		t.Error("期望字符串不通过整数验证")
 	}
+
+	// JSON 解码得到的 float64
+	if err := v.Validate(s, float64(42)); err != nil {
+		t.Fatalf("期望整数值的 float64 通过验证，但得到: %v", err)
+	}
+	if err := v.Validate(s, 4.2); err == nil {
+		t.Error("期望小数不通过整数验证")
+	}
 }
 
 // TestValidator_TypeNumber 测试数字类型验证
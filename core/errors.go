@@ -0,0 +1,158 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// 通用错误类别
+//
+// 各层（Agent、LLM、RAG、图编排）返回的错误用 %w 包装这些哨兵错误，
+// 调用方用 errors.Is 判断类别，而不是匹配错误字符串：
+//
+//	if errors.Is(err, core.ErrRateLimited) {
+//	    // 稍后重试
+//	}
+//
+// 各包已有的同类哨兵错误（如 loader.ErrRateLimited）与这里是同一个值。
+var (
+	// ErrRateLimited 被限流（如 HTTP 429）
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrInvalidInput 输入无效（缺少必填参数、格式错误、HTTP 400 等）
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrProviderUnavailable 服务暂时不可用（HTTP 5xx、网络错误等）
+	ErrProviderUnavailable = errors.New("provider unavailable")
+
+	// ErrContextTooLong 输入超出模型的上下文长度
+	ErrContextTooLong = errors.New("context too long")
+
+	// ErrUnauthorized 认证或授权失败（如 HTTP 401、403）
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrNotFound 资源不存在
+	ErrNotFound = errors.New("not found")
+
+	// ErrNotConfigured 缺少必需的组件配置（如未设置 LLM Provider、向量存储）
+	ErrNotConfigured = errors.New("not configured")
+)
+
+// categories 参与分类的错误类别
+var categories = []error{
+	ErrRateLimited,
+	ErrInvalidInput,
+	ErrProviderUnavailable,
+	ErrContextTooLong,
+	ErrUnauthorized,
+	ErrNotFound,
+	ErrNotConfigured,
+}
+
+// ClassifyError 为外部错误补充错误类别
+//
+// 用于第三方 SDK 或 HTTP 调用返回的错误：它们通常只在消息中携带状态码，
+// 这里按状态码和常见的错误描述识别类别，返回同时匹配原错误和类别的错误，消息不变。
+// err 为 nil、已属于某个类别、是 context 取消或超时、或无法识别时原样返回。
+func ClassifyError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	for _, category := range categories {
+		if errors.Is(err, category) {
+			return err
+		}
+	}
+	if category := classify(err); category != nil {
+		return &classifiedError{err: err, category: category}
+	}
+	return err
+}
+
+// NewCategoryError 创建属于某个类别的哨兵错误，消息为 msg
+//
+// 用于各包保留自己的哨兵错误，同时能被 errors.Is 识别为通用类别：
+//
+//	var ErrAuthFailed = core.NewCategoryError("authentication failed", core.ErrUnauthorized)
+//	errors.Is(ErrAuthFailed, core.ErrUnauthorized) // true
+func NewCategoryError(msg string, category error) error {
+	return &classifiedError{err: errors.New(msg), category: category}
+}
+
+// IsRetryable 判断错误是否为暂时性的，可作为重试和降级的判断条件
+//
+// 限流和服务不可用（含外部错误经 ClassifyError 识别出的）视为可重试，
+// 输入无效、上下文过长、认证失败等重试也不会成功的错误以及 context 取消不可重试。
+//
+// 示例：
+//
+//	cfg := core.DefaultRetryConfig()
+//	cfg.RetryOn = core.IsRetryable
+//	r := core.WithRetry(runnable, cfg)
+func IsRetryable(err error) bool {
+	err = ClassifyError(err)
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrProviderUnavailable)
+}
+
+// classifiedError 附带类别的错误
+type classifiedError struct {
+	err      error
+	category error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+
+func (e *classifiedError) Unwrap() []error { return []error{e.err, e.category} }
+
+// classify 按错误消息和类型识别类别
+func classify(err error) error {
+	msg := strings.ToLower(err.Error())
+	switch {
+	// 上下文过长通常也是 400，需要先于 ErrInvalidInput 判断
+	case containsAny(msg, "context_length_exceeded", "maximum context length", "context window",
+		"prompt is too long", "too many tokens"):
+		return ErrContextTooLong
+	case hasStatus(msg, http.StatusTooManyRequests) || containsAny(msg, "rate limit", "rate_limit"):
+		return ErrRateLimited
+	case hasStatus(msg, http.StatusUnauthorized, http.StatusForbidden) ||
+		containsAny(msg, "invalid api key", "invalid_api_key", "authentication"):
+		return ErrUnauthorized
+	case hasStatus(msg, http.StatusBadRequest, http.StatusUnprocessableEntity):
+		return ErrInvalidInput
+	case hasStatus(msg, http.StatusNotFound):
+		return ErrNotFound
+	case hasStatus(msg, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout) ||
+		containsAny(msg, "overloaded", "service unavailable", "connection refused", "connection reset"):
+		return ErrProviderUnavailable
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrProviderUnavailable
+	}
+	return nil
+}
+
+// hasStatus 判断消息中是否包含 HTTP 状态行（如 "429 too many requests"）
+func hasStatus(msg string, codes ...int) bool {
+	for _, code := range codes {
+		if strings.Contains(msg, strings.ToLower(fmt.Sprintf("%d %s", code, http.StatusText(code)))) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
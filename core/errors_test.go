@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{errors.New("openai api error: 429 Too Many Requests, body: {}"), ErrRateLimited},
+		{errors.New("anthropic api error: 400 Bad Request, body: prompt is too long"), ErrContextTooLong},
+		{errors.New(`openai api error: 400 Bad Request, body: {"code":"context_length_exceeded"}`), ErrContextTooLong},
+		{errors.New("openai api error: 400 Bad Request, body: {}"), ErrInvalidInput},
+		{errors.New("openai api error: 401 Unauthorized"), ErrUnauthorized},
+		{errors.New("anthropic api error: 503 Service Unavailable"), ErrProviderUnavailable},
+		{fmt.Errorf("request failed: %w", &net.OpError{Op: "dial", Err: errors.New("boom")}), ErrProviderUnavailable},
+	}
+	for _, tt := range tests {
+		got := ClassifyError(tt.err)
+		if !errors.Is(got, tt.want) || !errors.Is(got, tt.err) {
+			t.Errorf("ClassifyError(%q) should match %v and the original error", tt.err, tt.want)
+		}
+		if got.Error() != tt.err.Error() {
+			t.Errorf("ClassifyError changed message: %q", got.Error())
+		}
+	}
+
+	// 无法识别、已分类和 context 错误原样返回
+	for _, err := range []error{
+		nil,
+		errors.New("something odd"),
+		fmt.Errorf("wrapped: %w", ErrNotFound),
+		fmt.Errorf("call: %w", context.DeadlineExceeded),
+	} {
+		if got := ClassifyError(err); got != err {
+			t.Errorf("ClassifyError(%v) = %v, want unchanged", err, got)
+		}
+	}
+}
+
+func TestNewCategoryError(t *testing.T) {
+	errAuth := NewCategoryError("authentication failed", ErrUnauthorized)
+	err := fmt.Errorf("fetch: %w", errAuth)
+	if !errors.Is(err, errAuth) || !errors.Is(err, ErrUnauthorized) {
+		t.Error("category error should match itself and its category")
+	}
+	if errAuth.Error() != "authentication failed" {
+		t.Errorf("Error() = %q", errAuth.Error())
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	if !IsRetryable(errors.New("api error: 429 Too Many Requests")) || !IsRetryable(ErrProviderUnavailable) {
+		t.Error("rate limit and unavailable errors should be retryable")
+	}
+	if IsRetryable(ErrContextTooLong) || IsRetryable(context.Canceled) || IsRetryable(nil) {
+		t.Error("permanent errors should not be retryable")
+	}
+}
@@ -450,6 +450,20 @@ const (
 	CircuitHalfOpen
 )
 
+// String 返回状态名称：closed、open 或 half-open
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
 // CircuitBreakerConfig 熔断器配置
 type CircuitBreakerConfig struct {
 	// FailureThreshold 失败阈值
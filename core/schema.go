@@ -16,6 +16,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -520,7 +521,9 @@ func (v *Validator) validateType(schemaType string, val reflect.Value, path stri
 	case "string":
 		valid = val.Kind() == reflect.String
 	case "integer":
-		valid = val.Kind() >= reflect.Int && val.Kind() <= reflect.Uint64
+		// JSON 解码得到的数字为 float64，整数值也视为合法
+		valid = val.Kind() >= reflect.Int && val.Kind() <= reflect.Uint64 ||
+			(val.Kind() == reflect.Float32 || val.Kind() == reflect.Float64) && val.Float() == math.Trunc(val.Float())
 	case "number":
 		valid = val.Kind() >= reflect.Int && val.Kind() <= reflect.Float64
 	case "boolean":
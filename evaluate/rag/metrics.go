@@ -28,6 +28,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // ============== 错误定义 ==============
@@ -40,7 +42,7 @@ var (
 	ErrNoLLMProvider = errors.New("no LLM provider")
 
 	// ErrInvalidInput 无效输入
-	ErrInvalidInput = errors.New("invalid evaluation input")
+	ErrInvalidInput = core.NewCategoryError("invalid evaluation input", core.ErrInvalidInput)
 )
 
 // ============== 评估输入输出 ==============
@@ -0,0 +1,159 @@
+// Package events 提供轻量的泛型事件总线
+//
+// hooks 包面向 Agent 执行，本包用于发布订阅任意领域事件，例如在一次运行中
+// 发布"预算已消耗 80%"。编排引擎（图、工作流、Agent 网络）通过 Lifecycle
+// 事件接入同一条总线，调用方可以用一个订阅者统一观察所有引擎的生命周期。
+//
+// 主要类型：
+//   - Bus[T]: 类型安全的事件总线，并发分发，处理器 panic 互不影响
+//   - Lifecycle: 编排引擎的通用生命周期事件
+//
+// 使用示例：
+//
+//	bus := events.NewBus[BudgetAlert]()
+//	unsubscribe := bus.Subscribe(func(ctx context.Context, alert BudgetAlert) {
+//	    log.Printf("budget %.0f%% consumed", alert.Ratio*100)
+//	})
+//	defer unsubscribe()
+//
+//	bus.Publish(ctx, BudgetAlert{Ratio: 0.8})
+package events
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/hexagon-codes/hexagon/observe/logger"
+)
+
+// Handler 事件处理器
+type Handler[T any] func(ctx context.Context, event T)
+
+// PanicHandler 处理器 panic 时的回调，recovered 为 recover() 的返回值
+type PanicHandler func(ctx context.Context, recovered any, stack []byte)
+
+// Option 事件总线配置选项
+type Option func(*options)
+
+type options struct {
+	onPanic PanicHandler
+}
+
+// WithPanicHandler 设置处理器 panic 时的回调
+// 默认通过框架 Logger 记录错误日志
+func WithPanicHandler(fn PanicHandler) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.onPanic = fn
+		}
+	}
+}
+
+// Bus 类型安全的事件总线
+//
+// Publish 并发调用所有订阅者并等待全部返回，同一订阅者收到的事件保持发布顺序。
+// 某个处理器 panic 时被恢复并交给 PanicHandler，不影响其他处理器和发布方。
+// nil *Bus 的所有方法均为空操作，可作为可选依赖直接使用。
+// 线程安全。
+type Bus[T any] struct {
+	mu       sync.RWMutex
+	handlers map[uint64]Handler[T]
+	nextID   uint64
+	onPanic  PanicHandler
+}
+
+// NewBus 创建事件总线
+func NewBus[T any](opts ...Option) *Bus[T] {
+	o := options{onPanic: logPanic}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Bus[T]{
+		handlers: make(map[uint64]Handler[T]),
+		onPanic:  o.onPanic,
+	}
+}
+
+// Subscribe 订阅事件，返回取消订阅函数（可重复调用）
+func (b *Bus[T]) Subscribe(handler Handler[T]) (unsubscribe func()) {
+	if b == nil || handler == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}
+}
+
+// Publish 发布事件，并发调用所有订阅者并等待全部返回
+//
+// 调用处理器时不持有总线的锁，处理器中可以安全地再次发布或取消订阅；
+// 处理器应尽快返回，耗时操作应自行转入后台。
+func (b *Bus[T]) Publish(ctx context.Context, event T) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler[T], 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	switch len(handlers) {
+	case 0:
+		return
+	case 1:
+		b.dispatch(ctx, handlers[0], event)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, h := range handlers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.dispatch(ctx, h, event)
+		}()
+	}
+	wg.Wait()
+}
+
+// Len 返回当前订阅者数量
+func (b *Bus[T]) Len() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.handlers)
+}
+
+// dispatch 调用单个处理器并恢复 panic
+func (b *Bus[T]) dispatch(ctx context.Context, h Handler[T], event T) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.onPanic(ctx, r, debug.Stack())
+		}
+	}()
+	h(ctx, event)
+}
+
+// logPanic 默认的 panic 回调，记录错误日志
+func logPanic(ctx context.Context, recovered any, stack []byte) {
+	logger.FromContext(ctx).ErrorContext(ctx, "event handler panicked",
+		"panic", fmt.Sprint(recovered),
+		"stack", string(stack),
+	)
+}
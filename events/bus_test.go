@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestBus_PublishSubscribe 测试所有订阅者收到事件，取消订阅后不再收到
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := NewBus[int]()
+	var sum, count atomic.Int64
+
+	unsubscribe := bus.Subscribe(func(ctx context.Context, v int) { sum.Add(int64(v)) })
+	bus.Subscribe(func(ctx context.Context, v int) { count.Add(1) })
+	if bus.Len() != 2 {
+		t.Fatalf("Len = %d, 期望 2", bus.Len())
+	}
+
+	bus.Publish(context.Background(), 5)
+	if sum.Load() != 5 || count.Load() != 1 {
+		t.Errorf("sum = %d, count = %d", sum.Load(), count.Load())
+	}
+
+	unsubscribe()
+	unsubscribe()
+	bus.Publish(context.Background(), 7)
+	if sum.Load() != 5 || count.Load() != 2 {
+		t.Errorf("取消订阅后 sum = %d, count = %d", sum.Load(), count.Load())
+	}
+}
+
+// TestBus_PanicIsolation 测试处理器 panic 不影响其他处理器和发布方
+func TestBus_PanicIsolation(t *testing.T) {
+	var mu sync.Mutex
+	var recovered []any
+	bus := NewBus[string](WithPanicHandler(func(ctx context.Context, r any, stack []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		recovered = append(recovered, r)
+	}))
+
+	var delivered atomic.Int64
+	bus.Subscribe(func(ctx context.Context, s string) { panic("bad handler") })
+	for range 3 {
+		bus.Subscribe(func(ctx context.Context, s string) { delivered.Add(1) })
+	}
+
+	bus.Publish(context.Background(), "event")
+	if delivered.Load() != 3 {
+		t.Errorf("delivered = %d, 期望 3", delivered.Load())
+	}
+	if len(recovered) != 1 || recovered[0] != "bad handler" {
+		t.Errorf("recovered = %v", recovered)
+	}
+}
+
+// TestBus_Reentrant 测试处理器中发布和取消订阅不会死锁
+func TestBus_Reentrant(t *testing.T) {
+	bus := NewBus[int]()
+	var got []int
+	var unsubscribe func()
+	unsubscribe = bus.Subscribe(func(ctx context.Context, v int) {
+		got = append(got, v)
+		if v < 3 {
+			bus.Publish(ctx, v+1)
+		} else {
+			unsubscribe()
+		}
+	})
+
+	bus.Publish(context.Background(), 1)
+	if len(got) != 3 || bus.Len() != 0 {
+		t.Errorf("got = %v, Len = %d", got, bus.Len())
+	}
+}
+
+// TestBus_Nil 测试 nil 总线的方法为空操作
+func TestBus_Nil(t *testing.T) {
+	var bus *Bus[int]
+	bus.Subscribe(func(ctx context.Context, v int) {})()
+	bus.Publish(context.Background(), 1)
+	if bus.Len() != 0 {
+		t.Errorf("Len = %d", bus.Len())
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+import "time"
+
+// ============== 编排引擎生命周期事件 ==============
+
+// 生命周期事件的来源
+const (
+	// SourceGraph 图编排引擎（orchestration/graph）
+	SourceGraph = "graph"
+
+	// SourceWorkflow 工作流执行器（orchestration/workflow）
+	SourceWorkflow = "workflow"
+
+	// SourceNetwork 多 Agent 网络（agent.AgentNetwork）
+	SourceNetwork = "network"
+)
+
+// LifecycleType 生命周期事件类型
+type LifecycleType string
+
+const (
+	// RunStarted 运行开始（图运行、工作流执行、网络启动）
+	RunStarted LifecycleType = "run.started"
+
+	// RunCompleted 运行成功结束（网络停止也使用该类型）
+	RunCompleted LifecycleType = "run.completed"
+
+	// RunFailed 运行失败
+	RunFailed LifecycleType = "run.failed"
+
+	// RunCancelled 运行被取消
+	RunCancelled LifecycleType = "run.cancelled"
+
+	// RunPaused 运行暂停
+	RunPaused LifecycleType = "run.paused"
+
+	// RunResumed 运行恢复
+	RunResumed LifecycleType = "run.resumed"
+
+	// StepStarted 步骤开始（图节点、工作流步骤）
+	StepStarted LifecycleType = "step.started"
+
+	// StepCompleted 步骤完成
+	StepCompleted LifecycleType = "step.completed"
+
+	// StepFailed 步骤失败
+	StepFailed LifecycleType = "step.failed"
+
+	// StepSkipped 步骤跳过
+	StepSkipped LifecycleType = "step.skipped"
+
+	// StepRetrying 步骤重试
+	StepRetrying LifecycleType = "step.retrying"
+
+	// MemberJoined 成员加入（Agent 注册到网络）
+	MemberJoined LifecycleType = "member.joined"
+
+	// MemberLeft 成员离开（Agent 从网络注销）
+	MemberLeft LifecycleType = "member.left"
+
+	// MemberOffline 成员心跳超时，被标记为离线
+	MemberOffline LifecycleType = "member.offline"
+)
+
+// Lifecycle 编排引擎的生命周期事件
+//
+// 图、工作流和 Agent 网络通过各自的 WithEventBus 选项发布到同一条总线：
+//
+//	bus := events.NewBus[events.Lifecycle]()
+//	bus.Subscribe(func(ctx context.Context, e events.Lifecycle) {
+//	    log.Printf("[%s] %s %s %s", e.Source, e.Name, e.Type, e.Target)
+//	})
+//
+//	g.Run(ctx, state, graph.WithEventBus(bus))
+//	executor := workflow.NewExecutor(workflow.WithEventBus(bus))
+//	network := agent.NewAgentNetwork("team", agent.WithNetworkEventBus(bus))
+type Lifecycle struct {
+	// Source 事件来源：SourceGraph、SourceWorkflow 或 SourceNetwork
+	Source string `json:"source"`
+
+	// Type 事件类型
+	Type LifecycleType `json:"type"`
+
+	// Name 图、工作流或网络的名称
+	Name string `json:"name,omitempty"`
+
+	// RunID 图的运行 ID、工作流的执行 ID 或网络 ID
+	RunID string `json:"run_id,omitempty"`
+
+	// Target 事件涉及的节点 ID、步骤 ID 或 Agent ID，运行级事件为空
+	Target string `json:"target,omitempty"`
+
+	// Duration 步骤耗时，仅图节点的完成和失败事件设置
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Err 失败原因
+	Err error `json:"-"`
+
+	// Timestamp 事件时间
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -48,8 +48,11 @@ func runSequentialWorkflow(ctx context.Context) {
 		}).
 		AddFunc("transform", "转换数据", func(ctx context.Context, input workflow.StepInput) (*workflow.StepOutput, error) {
 			fmt.Println("  [2/3] 转换数据...")
-			// PreviousOutputs 中的值保持原始类型（[]string）
-			items := input.PreviousOutputs["extract"].([]string)
+			// PreviousOutputs 中的值保持原始类型（[]string），类型不符时返回 ok=false 而非 panic
+			items, ok := workflow.PreviousAs[[]string](input, "extract")
+			if !ok {
+				return nil, fmt.Errorf("extract 步骤输出类型不符")
+			}
 			var result []string
 			for _, s := range items {
 				result = append(result, strings.ToUpper(strings.TrimSpace(s)))
@@ -58,7 +61,10 @@ func runSequentialWorkflow(ctx context.Context) {
 		}).
 		AddFunc("load", "加载数据", func(ctx context.Context, input workflow.StepInput) (*workflow.StepOutput, error) {
 			fmt.Println("  [3/3] 加载数据...")
-			items := input.PreviousOutputs["transform"].([]string)
+			items, ok := input.PreviousSlice("transform")
+			if !ok {
+				return nil, fmt.Errorf("transform 步骤输出类型不符")
+			}
 			return &workflow.StepOutput{Data: fmt.Sprintf("成功加载 %d 条记录", len(items))}, nil
 		}).
 		Build()
@@ -78,25 +84,24 @@ func runSequentialWorkflow(ctx context.Context) {
 func runParallelWorkflow(ctx context.Context) {
 	wf, err := workflow.New("parallel-analysis").
 		AddFunc("prepare", "准备数据", func(ctx context.Context, input workflow.StepInput) (*workflow.StepOutput, error) {
-			fmt.Println("  [准备] 准备数据...")
 			return &workflow.StepOutput{Data: input.Data}, nil
 		}).
 		ParallelFuncs("analyze", "并行分析", map[string]workflow.StepFunc{
 			"sentiment": func(ctx context.Context, input workflow.StepInput) (*workflow.StepOutput, error) {
-				fmt.Println("  [并行] 情感分析...")
 				time.Sleep(50 * time.Millisecond)
 				return &workflow.StepOutput{Data: "positive"}, nil
 			},
 			"keywords": func(ctx context.Context, input workflow.StepInput) (*workflow.StepOutput, error) {
-				fmt.Println("  [并行] 关键词提取...")
 				time.Sleep(50 * time.Millisecond)
 				return &workflow.StepOutput{Data: []string{"AI", "Agent", "Go"}}, nil
 			},
 		}).
 		AddFunc("merge", "合并结果", func(ctx context.Context, input workflow.StepInput) (*workflow.StepOutput, error) {
-			fmt.Println("  [合并] 合并分析结果...")
 			// 并行步骤的输出存储在父步骤 ID 下，值为 map[string]any
-			analyzeResult := input.PreviousOutputs["analyze"].(map[string]any)
+			analyzeResult, ok := input.PreviousMap("analyze")
+			if !ok {
+				return nil, fmt.Errorf("analyze 步骤输出类型不符")
+			}
 			return &workflow.StepOutput{
 				Data: fmt.Sprintf("情感=%v, 关键词=%v",
 					analyzeResult["sentiment"],
@@ -108,12 +113,29 @@ func runParallelWorkflow(ctx context.Context) {
 		log.Fatalf("构建工作流失败: %v", err)
 	}
 
+	// 通过事件流在外部渲染进度，步骤函数中无需打印
 	executor := workflow.NewExecutor()
-	result, err := executor.Run(ctx, wf, workflow.WorkflowInput{Data: "Hexagon is powerful"})
+	events, err := executor.Stream(ctx, wf, workflow.WorkflowInput{Data: "Hexagon is powerful"})
 	if err != nil {
 		log.Fatalf("执行失败: %v", err)
 	}
-	fmt.Printf("  结果: %v\n", result.Data)
+	for event := range events {
+		switch event.Type {
+		case workflow.EventStepStarted:
+			if event.BranchID != "" {
+				fmt.Printf("  [%s/%s] 开始\n", event.StepID, event.BranchID)
+			} else {
+				fmt.Printf("  [%s] 开始\n", event.StepID)
+			}
+		case workflow.EventStepFailed:
+			fmt.Printf("  [%s] 失败: %s\n", event.StepID, event.Error)
+		case workflow.EventWorkflowCompleted:
+			output := event.Data.(*workflow.WorkflowOutput)
+			fmt.Printf("  结果: %v\n", output.Data)
+		case workflow.EventWorkflowFailed:
+			log.Fatalf("执行失败: %s", event.Error)
+		}
+	}
 }
 
 // runConditionalWorkflow 演示审核流程: 大额走经理审批，小额自动通过
@@ -133,7 +155,7 @@ func runConditionalWorkflow(ctx context.Context) {
 		}).
 		Conditional("route", "路由审批",
 			func(ctx context.Context, input workflow.StepInput) (string, error) {
-				if input.Variables["level"] == "high" {
+				if level, _ := input.VarString("level"); level == "high" {
 					return "true", nil
 				}
 				return "false", nil
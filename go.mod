@@ -9,6 +9,7 @@ require (
 	github.com/redis/go-redis/v9 v9.18.0
 	golang.org/x/net v0.53.0
 	golang.org/x/sync v0.20.0
+	golang.org/x/text v0.36.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
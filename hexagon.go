@@ -26,6 +26,7 @@ package hexagon
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"os"
 	"runtime/debug"
 	"strings"
@@ -36,6 +37,9 @@ import (
 	"github.com/hexagon-codes/ai-core/memory"
 	"github.com/hexagon-codes/ai-core/tool"
 	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/llm/limiter"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+	"github.com/hexagon-codes/hexagon/rag"
 )
 
 // Version is the current version of the Hexagon framework.
@@ -125,6 +129,41 @@ func SetDefaultProvider(p llm.Provider) {
 	defaultProvider = p
 }
 
+// SetGlobalLLMLimiter 设置进程级 LLM 调用限流（并发安全）
+//
+// 所有 Agent 的 LLM 调用都会在发起前获取许可，调用阻塞等待时遵循 context 取消。
+// rps <= 0 表示不限速，concurrency <= 0 表示不限并发；两者都 <= 0 时取消限流。
+//
+// 示例：
+//
+//	// 每秒最多 5 个请求、最多 3 个并发调用
+//	hexagon.SetGlobalLLMLimiter(5, 3)
+func SetGlobalLLMLimiter(rps float64, concurrency int) {
+	if rps <= 0 && concurrency <= 0 {
+		limiter.SetGlobal(nil)
+		return
+	}
+	limiter.SetGlobal(limiter.New(rps, concurrency))
+}
+
+// SetLogger 设置框架内部日志使用的 *slog.Logger（并发安全）
+//
+// 框架在 Agent 运行、工具调用、RAG 检索等边界输出结构化日志，
+// 并携带 run_id、agent_id、tool_name、duration 等关联字段。
+// 默认不输出任何日志；传入 nil 恢复为静默。
+// 如需按请求定制，可使用 logger.ContextWithLogger 将 Logger 放入 context。
+//
+// 示例：
+//
+//	hexagon.SetLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		logger.SetFramework(nil)
+		return
+	}
+	logger.SetFramework(logger.FromSlog(l))
+}
+
 // QuickStartOption 是 QuickStart 的配置选项
 type QuickStartOption func(*quickStartConfig)
 
@@ -268,3 +307,65 @@ func Run(ctx context.Context, input Input, opts ...QuickStartOption) (Output, er
 func NewTool[I, O any](name, description string, fn func(context.Context, I) (O, error)) *tool.FuncTool[I, O] {
 	return tool.NewFunc(name, description, fn)
 }
+
+// RAGSearchTool 将 RAG 引擎（或任意检索器）包装为知识库检索工具
+//
+// Agent 可以自行决定何时检索、改写查询并多次检索，而不是在调用前固定检索一次。
+// 工具参数为 query（必填）、top_k 和 filter，结果带 [n] 编号和来源便于引用。
+// 选项参见 rag.NewSearchTool。
+//
+// 示例：
+//
+//	engine := rag.NewEngine(rag.WithStore(store), rag.WithEngineEmbedder(embedder))
+//	agent := hexagon.QuickStart(
+//	    hexagon.WithTools(hexagon.RAGSearchTool(engine)),
+//	)
+func RAGSearchTool(r rag.Retriever, opts ...rag.SearchToolOption) tool.Tool {
+	return rag.NewSearchTool(r, opts...)
+}
+
+// DelegateTool 将子 Agent 包装为委派工具
+//
+// 模型调用工具时运行子 Agent 并将其输出作为工具结果，控制权仍在父 Agent，
+// 用于在一次运行内分解层级任务。委派深度默认最多 3 层，
+// 选项参见 agent.WithMaxDelegationDepth 和 agent.WithDelegationBudget。
+//
+// 示例：
+//
+//	researcher := agent.NewReAct(agent.WithName("researcher"), agent.WithTools(search))
+//	lead := hexagon.QuickStart(
+//	    hexagon.WithTools(hexagon.DelegateTool(researcher, "research", "Research a topic and summarize findings")),
+//	)
+func DelegateTool(subAgent Agent, name, description string, opts ...agent.DelegateOption) tool.Tool {
+	return agent.DelegateTool(subAgent, name, description, opts...)
+}
+
+// ============== 辩论与批评团队 ==============
+
+// TeamModeDebate 辩论模式：正反方辩论后由裁判决定，或生成者与批评者循环改进
+const TeamModeDebate = agent.TeamModeDebate
+
+// DebateApproved 批评者认可生成结果时在回复中单独一行给出的标记
+const DebateApproved = agent.DebateApproved
+
+// 辩论团队选项
+//
+// 示例：
+//
+//	team := agent.NewTeam("review",
+//	    hexagon.WithCritique(writer, reviewer),
+//	    hexagon.WithDebateRounds(5),
+//	)
+var (
+	// WithDebate 设置对抗式辩论：正反方每轮各发言一次，结束后由裁判给出决定
+	WithDebate = agent.WithDebate
+
+	// WithCritique 设置生成-批评循环，批评者回复 DebateApproved 时结束
+	WithCritique = agent.WithCritique
+
+	// WithDebateRounds 设置最大轮数，默认 3
+	WithDebateRounds = agent.WithDebateRounds
+
+	// WithDebateTermination 设置提前终止条件
+	WithDebateTermination = agent.WithDebateTermination
+)
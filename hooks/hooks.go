@@ -19,6 +19,7 @@
 //   - LLMHook: LLM 调用钩子
 //   - RetrieverHook: 检索钩子
 //   - Manager: 钩子管理器，统一管理和触发钩子
+//   - StreamAggregator: 按 RunID 将 LLM 流式分块重组为完整内容
 //
 // 使用示例：
 //
@@ -65,11 +66,17 @@ const (
 	TimingRunStreamStart // 流式执行开始
 	TimingRunStreamEnd   // 流式执行结束
 
+	// LLM 降级时机
+	TimingLLMFallback // LLM 调用失败并切换到备用 Provider
+
+	// LLM 熔断时机
+	TimingLLMCircuit // Provider 熔断器状态变化
+
 	// 便捷组合
 	TimingRunAll       = TimingRunStart | TimingRunEnd | TimingRunError
 	TimingRunStreamAll = TimingRunStreamStart | TimingRunStreamEnd
 	TimingToolAll      = TimingToolStart | TimingToolEnd
-	TimingLLMAll       = TimingLLMStart | TimingLLMEnd | TimingLLMStream
+	TimingLLMAll       = TimingLLMStart | TimingLLMEnd | TimingLLMStream | TimingLLMFallback | TimingLLMCircuit
 	TimingRetrieverAll = TimingRetrieverStart | TimingRetrieverEnd
 	TimingAll          = TimingRunAll | TimingRunStreamAll | TimingToolAll | TimingLLMAll | TimingRetrieverAll
 )
@@ -101,6 +108,8 @@ func (t Timing) String() string {
 		{TimingRetrieverEnd, "retriever_end"},
 		{TimingRunStreamStart, "run_stream_start"},
 		{TimingRunStreamEnd, "run_stream_end"},
+		{TimingLLMFallback, "llm_fallback"},
+		{TimingLLMCircuit, "llm_circuit"},
 	}
 	for _, tt := range timings {
 		if t.Has(tt.t) {
@@ -176,19 +185,23 @@ type RetrieverHook interface {
 
 // RunStartEvent Agent 开始执行事件
 type RunStartEvent struct {
-	RunID    string         `json:"run_id"`
-	AgentID  string         `json:"agent_id"`
-	Input    any            `json:"input"`
-	Metadata map[string]any `json:"metadata,omitempty"`
+	RunID string `json:"run_id"`
+	// ParentRunID 外层运行的 ID（团队、Swarm 中的成员运行），顶层运行为空
+	ParentRunID string         `json:"parent_run_id,omitempty"`
+	AgentID     string         `json:"agent_id"`
+	Input       any            `json:"input"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
 // RunEndEvent Agent 执行完成事件
 type RunEndEvent struct {
-	RunID    string         `json:"run_id"`
-	AgentID  string         `json:"agent_id"`
-	Output   any            `json:"output"`
-	Duration int64          `json:"duration_ms"`
-	Metadata map[string]any `json:"metadata,omitempty"`
+	RunID string `json:"run_id"`
+	// ParentRunID 外层运行的 ID，顶层运行为空
+	ParentRunID string         `json:"parent_run_id,omitempty"`
+	AgentID     string         `json:"agent_id"`
+	Output      any            `json:"output"`
+	Duration    int64          `json:"duration_ms"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
 // ErrorEvent 错误事件
@@ -228,6 +241,7 @@ type LLMStartEvent struct {
 	Provider    string         `json:"provider"`
 	Messages    []any          `json:"messages"`
 	Temperature float64        `json:"temperature,omitempty"`
+	Seed        *int64         `json:"seed,omitempty"`
 	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
@@ -253,6 +267,32 @@ type LLMStreamEvent struct {
 	ChunkIndex int    `json:"chunk_index"`
 }
 
+// LLMFallbackEvent LLM Provider 降级事件
+type LLMFallbackEvent struct {
+	RunID   string `json:"run_id"`
+	AgentID string `json:"agent_id"`
+	// From 调用失败的 Provider
+	From string `json:"from"`
+	// To 接下来尝试的 Provider
+	To string `json:"to"`
+	// Error From 的失败原因
+	Error    error          `json:"error,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// LLMCircuitEvent LLM Provider 熔断器状态变化事件
+type LLMCircuitEvent struct {
+	// Provider Provider 名称
+	Provider string `json:"provider"`
+	// From 原状态：closed、open 或 half-open
+	From string `json:"from"`
+	// To 新状态
+	To string `json:"to"`
+	// Error 触发状态变化的调用错误，恢复时为 nil
+	Error    error          `json:"error,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
 // RetrieverStartEvent 检索开始事件
 type RetrieverStartEvent struct {
 	RunID    string         `json:"run_id"`
@@ -340,6 +380,24 @@ type StreamHook interface {
 	OnStreamEnd(ctx context.Context, event *RunStreamEndEvent) error
 }
 
+// LLMFallbackHook LLM 降级钩子（可选接口）
+//
+// LLMHook 可以额外实现此接口来接收 Provider 降级事件，用于跟踪 Provider 健康状况。
+// 与 StreamHook 相同，不需要单独注册，复用 llmHooks 列表。
+type LLMFallbackHook interface {
+	// OnLLMFallback LLM 调用失败并切换到下一个 Provider
+	OnLLMFallback(ctx context.Context, event *LLMFallbackEvent) error
+}
+
+// LLMCircuitHook LLM 熔断钩子（可选接口）
+//
+// LLMHook 可以额外实现此接口来接收 Provider 熔断器的状态变化，
+// 如 Provider 被熔断（open）或恢复（closed）。复用 llmHooks 列表。
+type LLMCircuitHook interface {
+	// OnLLMCircuit Provider 熔断器状态变化
+	OnLLMCircuit(ctx context.Context, event *LLMCircuitEvent) error
+}
+
 // ============== HookManager ==============
 
 // Manager 钩子管理器
@@ -348,6 +406,7 @@ type Manager struct {
 	toolHooks      []ToolHook
 	llmHooks       []LLMHook
 	retrieverHooks []RetrieverHook
+	onHookError    func(hookName string, err error)
 	mu             sync.RWMutex
 }
 
@@ -389,6 +448,29 @@ func (m *Manager) RegisterRetrieverHook(hook RetrieverHook) {
 	m.retrieverHooks = append(m.retrieverHooks, hook)
 }
 
+// OnHookError 设置钩子错误回调
+//
+// 任一钩子返回错误时调用，参数为钩子名称和错误，随后 Trigger 方法照常返回该错误。
+// 框架内很多 Trigger 调用忽略返回值（如运行结束、LLM 事件），
+// 设置此回调可以让这些失败可被观测（记录日志、上报指标），而不是静默丢失。
+// 回调在触发钩子的 goroutine 中同步执行，应尽快返回。传入 nil 取消回调。
+func (m *Manager) OnHookError(fn func(hookName string, err error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onHookError = fn
+}
+
+// hookFailed 将钩子错误报告给 OnHookError 回调并原样返回
+func (m *Manager) hookFailed(hook Hook, err error) error {
+	m.mu.RLock()
+	fn := m.onHookError
+	m.mu.RUnlock()
+	if fn != nil {
+		fn(hook.Name(), err)
+	}
+	return err
+}
+
 // checkTiming 检查 Hook 是否关心指定时机
 // 如果 Hook 实现了 TimingChecker 接口，检查其声明的时机
 // 否则默认关心所有时机
@@ -416,7 +498,7 @@ func (m *Manager) TriggerRunStart(ctx context.Context, event *RunStartEvent) err
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingRunStart) {
 			if err := hook.OnStart(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -440,7 +522,7 @@ func (m *Manager) TriggerRunEnd(ctx context.Context, event *RunEndEvent) error {
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingRunEnd) {
 			if err := hook.OnEnd(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -464,7 +546,7 @@ func (m *Manager) TriggerError(ctx context.Context, event *ErrorEvent) error {
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingRunError) {
 			if err := hook.OnError(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -488,7 +570,7 @@ func (m *Manager) TriggerToolStart(ctx context.Context, event *ToolStartEvent) e
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingToolStart) {
 			if err := hook.OnToolStart(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -512,7 +594,7 @@ func (m *Manager) TriggerToolEnd(ctx context.Context, event *ToolEndEvent) error
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingToolEnd) {
 			if err := hook.OnToolEnd(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -536,7 +618,7 @@ func (m *Manager) TriggerLLMStart(ctx context.Context, event *LLMStartEvent) err
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingLLMStart) {
 			if err := hook.OnLLMStart(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -560,7 +642,7 @@ func (m *Manager) TriggerLLMEnd(ctx context.Context, event *LLMEndEvent) error {
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingLLMEnd) {
 			if err := hook.OnLLMEnd(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -584,6 +666,64 @@ func (m *Manager) TriggerLLMStream(ctx context.Context, event *LLMStreamEvent) e
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingLLMStream) {
 			if err := hook.OnLLMStream(ctx, event); err != nil {
+				return m.hookFailed(hook, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TriggerLLMFallback 触发 LLM 降级事件
+//
+// 遍历已注册的 llmHooks，调用实现了 LLMFallbackHook 接口的钩子。
+//
+// 线程安全：在迭代前创建钩子列表的副本，避免并发修改问题。
+// TimingChecker：只调用关心 TimingLLMFallback 时机的 Hook。
+func (m *Manager) TriggerLLMFallback(ctx context.Context, event *LLMFallbackEvent) error {
+	m.mu.RLock()
+	if len(m.llmHooks) == 0 {
+		m.mu.RUnlock()
+		return nil
+	}
+	hooks := make([]LLMHook, len(m.llmHooks))
+	copy(hooks, m.llmHooks)
+	m.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if !hook.Enabled() || !checkTiming(hook, TimingLLMFallback) {
+			continue
+		}
+		if fh, ok := hook.(LLMFallbackHook); ok {
+			if err := fh.OnLLMFallback(ctx, event); err != nil {
+				return m.hookFailed(hook, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TriggerLLMCircuit 触发 LLM 熔断器状态变化事件
+//
+// 遍历已注册的 llmHooks，调用实现了 LLMCircuitHook 接口的钩子。
+//
+// 线程安全：在迭代前创建钩子列表的副本，避免并发修改问题。
+// TimingChecker：只调用关心 TimingLLMCircuit 时机的 Hook。
+func (m *Manager) TriggerLLMCircuit(ctx context.Context, event *LLMCircuitEvent) error {
+	m.mu.RLock()
+	if len(m.llmHooks) == 0 {
+		m.mu.RUnlock()
+		return nil
+	}
+	hooks := make([]LLMHook, len(m.llmHooks))
+	copy(hooks, m.llmHooks)
+	m.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if !hook.Enabled() || !checkTiming(hook, TimingLLMCircuit) {
+			continue
+		}
+		if ch, ok := hook.(LLMCircuitHook); ok {
+			if err := ch.OnLLMCircuit(ctx, event); err != nil {
 				return err
 			}
 		}
@@ -608,7 +748,7 @@ func (m *Manager) TriggerRetrieverStart(ctx context.Context, event *RetrieverSta
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingRetrieverStart) {
 			if err := hook.OnRetrieverStart(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -632,7 +772,7 @@ func (m *Manager) TriggerRetrieverEnd(ctx context.Context, event *RetrieverEndEv
 	for _, hook := range hooks {
 		if hook.Enabled() && checkTiming(hook, TimingRetrieverEnd) {
 			if err := hook.OnRetrieverEnd(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -662,7 +802,7 @@ func (m *Manager) TriggerStreamStart(ctx context.Context, event *RunStreamStartE
 		}
 		if sh, ok := hook.(StreamHook); ok {
 			if err := sh.OnStreamStart(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
@@ -692,7 +832,7 @@ func (m *Manager) TriggerStreamEnd(ctx context.Context, event *RunStreamEndEvent
 		}
 		if sh, ok := hook.(StreamHook); ok {
 			if err := sh.OnStreamEnd(ctx, event); err != nil {
-				return err
+				return m.hookFailed(hook, err)
 			}
 		}
 	}
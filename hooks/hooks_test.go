@@ -165,6 +165,45 @@ func TestHookManager_ErrorEvent(t *testing.T) {
 	}
 }
 
+// failingToolHook 总是返回错误的工具钩子
+type failingToolHook struct {
+	mockToolHook
+	err error
+}
+
+func (h *failingToolHook) OnToolEnd(ctx context.Context, event *ToolEndEvent) error {
+	return h.err
+}
+
+func TestHookManager_OnHookError(t *testing.T) {
+	manager := NewManager()
+	hookErr := errors.New("exporter down")
+	manager.RegisterToolHook(&failingToolHook{mockToolHook: mockToolHook{name: "exporter", enabled: true}, err: hookErr})
+
+	var gotName string
+	var gotErr error
+	manager.OnHookError(func(hookName string, err error) {
+		gotName, gotErr = hookName, err
+	})
+
+	err := manager.TriggerToolEnd(context.Background(), &ToolEndEvent{ToolName: "search"})
+	if !errors.Is(err, hookErr) {
+		t.Errorf("expected hook error to be returned, got %v", err)
+	}
+	if gotName != "exporter" || !errors.Is(gotErr, hookErr) {
+		t.Errorf("expected error sink to receive exporter error, got %q %v", gotName, gotErr)
+	}
+
+	// 成功的钩子不触发回调
+	gotName = ""
+	if err := manager.TriggerToolStart(context.Background(), &ToolStartEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	if gotName != "" {
+		t.Errorf("expected no callback for successful hooks, got %q", gotName)
+	}
+}
+
 func TestHookManager_Context(t *testing.T) {
 	manager := NewManager()
 	ctx := context.Background()
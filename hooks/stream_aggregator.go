@@ -0,0 +1,222 @@
+package hooks
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ============== StreamAggregator 流式输出聚合 ==============
+
+// AggregatedStream 聚合后的一次 LLM 流式输出
+type AggregatedStream struct {
+	// RunID 运行 ID
+	RunID string `json:"run_id"`
+
+	// Model 模型名称（取自流式事件）
+	Model string `json:"model,omitempty"`
+
+	// Content 按 ChunkIndex 顺序拼接的内容，缺失的分块被跳过
+	Content string `json:"content"`
+
+	// Chunks 收到的不重复分块数
+	Chunks int `json:"chunks"`
+
+	// Missing 缺失的分块序号（0 到已收到的最大序号之间）
+	Missing []int `json:"missing,omitempty"`
+
+	// Duplicates 重复收到的分块数，重复分块只保留第一次的内容
+	Duplicates int `json:"duplicates,omitempty"`
+
+	// Done 是否已收到 LLM 调用完成事件
+	Done bool `json:"done"`
+}
+
+// Complete 流式输出是否已结束且没有缺失的分块
+func (s AggregatedStream) Complete() bool {
+	return s.Done && len(s.Missing) == 0
+}
+
+// StreamAggregator 按 RunID 将 LLMStreamEvent 重组为完整内容
+//
+// 分块按 ChunkIndex 排序，不依赖到达顺序，重复的分块被忽略，缺失的分块在 Missing 中列出。
+// 同一运行的新一次 LLM 调用（LLMStartEvent）会清空之前的分块。
+// 作为 LLMHook 注册到 Manager，也可以直接调用 Add 喂入事件：
+//
+//	agg := hooks.NewStreamAggregator(hooks.WithStreamComplete(func(ctx context.Context, s hooks.AggregatedStream) {
+//	    if !s.Complete() {
+//	        log.Printf("run %s missing chunks %v", s.RunID, s.Missing)
+//	    }
+//	    save(s.RunID, s.Content)
+//	}))
+//	manager.RegisterLLMHook(agg)
+type StreamAggregator struct {
+	onComplete func(ctx context.Context, stream AggregatedStream)
+
+	mu      sync.Mutex
+	streams map[string]*streamBuffer
+}
+
+// streamBuffer 单个运行的分块缓冲
+type streamBuffer struct {
+	model      string
+	chunks     map[int]string
+	maxIndex   int
+	duplicates int
+	done       bool
+}
+
+// StreamAggregatorOption StreamAggregator 配置选项
+type StreamAggregatorOption func(*StreamAggregator)
+
+// WithStreamComplete 设置 LLM 调用完成时的回调
+//
+// 收到 LLMEndEvent 时以聚合结果调用 fn，随后释放该运行的分块。
+// 未设置时分块保留到调用 Delete 为止。
+func WithStreamComplete(fn func(ctx context.Context, stream AggregatedStream)) StreamAggregatorOption {
+	return func(a *StreamAggregator) {
+		a.onComplete = fn
+	}
+}
+
+// NewStreamAggregator 创建流式输出聚合器
+func NewStreamAggregator(opts ...StreamAggregatorOption) *StreamAggregator {
+	a := &StreamAggregator{
+		streams: make(map[string]*streamBuffer),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Add 添加一个流式分块，ChunkIndex 为负数的事件被忽略
+func (a *StreamAggregator) Add(event *LLMStreamEvent) {
+	if event == nil || event.ChunkIndex < 0 {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	buf := a.buffer(event.RunID)
+	if event.Model != "" {
+		buf.model = event.Model
+	}
+	if _, ok := buf.chunks[event.ChunkIndex]; ok {
+		buf.duplicates++
+		return
+	}
+	buf.chunks[event.ChunkIndex] = event.Content
+	buf.maxIndex = max(buf.maxIndex, event.ChunkIndex)
+}
+
+// Result 返回运行当前的聚合结果，没有收到过该运行的事件时返回 false
+func (a *StreamAggregator) Result(runID string) (AggregatedStream, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	buf, ok := a.streams[runID]
+	if !ok {
+		return AggregatedStream{}, false
+	}
+	return buf.result(runID), true
+}
+
+// Delete 释放运行的分块
+func (a *StreamAggregator) Delete(runID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.streams, runID)
+}
+
+// buffer 返回运行的分块缓冲，不存在时创建，调用方持有锁
+func (a *StreamAggregator) buffer(runID string) *streamBuffer {
+	buf, ok := a.streams[runID]
+	if !ok {
+		buf = &streamBuffer{chunks: make(map[int]string), maxIndex: -1}
+		a.streams[runID] = buf
+	}
+	return buf
+}
+
+// result 生成聚合结果
+func (b *streamBuffer) result(runID string) AggregatedStream {
+	indexes := make([]int, 0, len(b.chunks))
+	for i := range b.chunks {
+		indexes = append(indexes, i)
+	}
+	slices.Sort(indexes)
+
+	var sb strings.Builder
+	var missing []int
+	next := 0
+	for _, i := range indexes {
+		for ; next < i; next++ {
+			missing = append(missing, next)
+		}
+		sb.WriteString(b.chunks[i])
+		next = i + 1
+	}
+
+	return AggregatedStream{
+		RunID:      runID,
+		Model:      b.model,
+		Content:    sb.String(),
+		Chunks:     len(b.chunks),
+		Missing:    missing,
+		Duplicates: b.duplicates,
+		Done:       b.done,
+	}
+}
+
+// Name 返回钩子名称
+func (a *StreamAggregator) Name() string { return "stream-aggregator" }
+
+// Enabled 返回钩子是否启用
+func (a *StreamAggregator) Enabled() bool { return true }
+
+// Timings 只关心 LLM 调用相关的时机
+func (a *StreamAggregator) Timings() Timing {
+	return TimingLLMStart | TimingLLMEnd | TimingLLMStream
+}
+
+// OnLLMStart 新的 LLM 调用开始，清空运行之前的分块
+func (a *StreamAggregator) OnLLMStart(_ context.Context, event *LLMStartEvent) error {
+	a.Delete(event.RunID)
+	return nil
+}
+
+// OnLLMStream 收集流式分块
+func (a *StreamAggregator) OnLLMStream(_ context.Context, event *LLMStreamEvent) error {
+	a.Add(event)
+	return nil
+}
+
+// OnLLMEnd 标记运行的流式输出结束，设置了完成回调时调用并释放分块
+func (a *StreamAggregator) OnLLMEnd(ctx context.Context, event *LLMEndEvent) error {
+	a.mu.Lock()
+	buf, ok := a.streams[event.RunID]
+	if !ok {
+		a.mu.Unlock()
+		return nil
+	}
+	buf.done = true
+	result := buf.result(event.RunID)
+	if a.onComplete != nil {
+		delete(a.streams, event.RunID)
+	}
+	a.mu.Unlock()
+
+	if a.onComplete != nil {
+		a.onComplete(ctx, result)
+	}
+	return nil
+}
+
+// 确保实现了接口
+var (
+	_ LLMHook       = (*StreamAggregator)(nil)
+	_ TimingChecker = (*StreamAggregator)(nil)
+)
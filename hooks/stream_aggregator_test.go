@@ -0,0 +1,69 @@
+package hooks
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestStreamAggregator_OutOfOrder(t *testing.T) {
+	agg := NewStreamAggregator()
+	for _, e := range []*LLMStreamEvent{
+		{RunID: "run-1", Model: "gpt", Content: "lo", ChunkIndex: 1},
+		{RunID: "run-1", Content: "Hel", ChunkIndex: 0},
+		{RunID: "run-1", Content: "XX", ChunkIndex: 1},
+		{RunID: "run-1", Content: "!", ChunkIndex: 4},
+		{RunID: "run-2", Content: "other", ChunkIndex: 0},
+	} {
+		agg.Add(e)
+	}
+
+	got, ok := agg.Result("run-1")
+	if !ok {
+		t.Fatal("expected result for run-1")
+	}
+	if got.Content != "Hello!" {
+		t.Errorf("Content = %q, want %q", got.Content, "Hello!")
+	}
+	if !slices.Equal(got.Missing, []int{2, 3}) {
+		t.Errorf("Missing = %v, want [2 3]", got.Missing)
+	}
+	if got.Duplicates != 1 || got.Chunks != 3 || got.Model != "gpt" {
+		t.Errorf("unexpected result: %+v", got)
+	}
+	if got.Complete() {
+		t.Error("stream with gaps should not be complete")
+	}
+	if _, ok := agg.Result("run-3"); ok {
+		t.Error("unknown run should have no result")
+	}
+}
+
+func TestStreamAggregator_Hook(t *testing.T) {
+	var completed []AggregatedStream
+	agg := NewStreamAggregator(WithStreamComplete(func(_ context.Context, s AggregatedStream) {
+		completed = append(completed, s)
+	}))
+
+	m := NewManager()
+	m.RegisterLLMHook(agg)
+	ctx := context.Background()
+
+	// 第一次调用的残留分块在新的 LLM 调用开始时被清空
+	m.TriggerLLMStream(ctx, &LLMStreamEvent{RunID: "run-1", Content: "stale", ChunkIndex: 0})
+	m.TriggerLLMStart(ctx, &LLMStartEvent{RunID: "run-1"})
+	for i, c := range []string{"a", "b", "c"} {
+		m.TriggerLLMStream(ctx, &LLMStreamEvent{RunID: "run-1", Content: c, ChunkIndex: i})
+	}
+	m.TriggerLLMEnd(ctx, &LLMEndEvent{RunID: "run-1"})
+
+	if len(completed) != 1 {
+		t.Fatalf("expected 1 completed stream, got %d", len(completed))
+	}
+	if got := completed[0]; got.Content != "abc" || !got.Complete() {
+		t.Errorf("unexpected completed stream: %+v", got)
+	}
+	if _, ok := agg.Result("run-1"); ok {
+		t.Error("completed stream should be released")
+	}
+}
@@ -3,6 +3,7 @@
 // 本包封装了 toolkit/util/poolx，提供：
 //   - 全局协程池管理
 //   - 并发任务执行
+//   - 有界并发处理（Run / Stream），结果保持输入顺序
 //   - 对象池复用
 //
 // 使用示例：
@@ -0,0 +1,188 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ============== 有界并发执行 ==============
+
+// ItemError 单个元素处理失败的错误
+type ItemError struct {
+	// Index 元素在输入中的下标
+	Index int
+
+	// Err 处理函数返回的错误
+	Err error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// RunResult Stream 输出的单个元素结果
+type RunResult[R any] struct {
+	// Index 元素在输入中的下标
+	Index int
+
+	// Value 处理结果，失败时为零值
+	Value R
+
+	// Err 处理失败时的错误
+	Err error
+}
+
+// Run 以最多 concurrency 个协程并发处理 items，结果按输入顺序返回
+//
+// concurrency <= 0 时使用 WithMaxConcurrency 的设置，仍未设置则使用 CPU 核心数。
+// 默认任一元素失败即取消其余元素，返回该元素的 *ItemError；
+// WithStopOnError(false) 时处理全部元素，失败元素的结果为零值，
+// 返回的错误由所有 *ItemError 经 errors.Join 组合。
+// ctx 被取消时停止分发并等待已开始的元素结束，返回取消原因。
+// worker 中的 panic 被恢复并作为该元素的错误返回。
+//
+// 示例：
+//
+//	summaries, err := pool.Run(ctx, docs, 4, func(ctx context.Context, doc Document) (string, error) {
+//	    return summarize(ctx, doc)
+//	})
+func Run[T, R any](ctx context.Context, items []T, concurrency int, worker func(context.Context, T) (R, error), opts ...BatchOption) ([]R, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	config := runConfig(concurrency, opts)
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	run(ctx, items, config, worker, func(i int, result R, err error) {
+		results[i], errs[i] = result, err
+	})
+
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+	if config.StopOnError {
+		if i, err := firstError(errs); err != nil {
+			return nil, &ItemError{Index: i, Err: err}
+		}
+		return results, nil
+	}
+
+	var itemErrs []error
+	for i, err := range errs {
+		if err != nil {
+			itemErrs = append(itemErrs, &ItemError{Index: i, Err: err})
+		}
+	}
+	return results, errors.Join(itemErrs...)
+}
+
+// Stream 与 Run 相同的并发语义，按完成顺序逐个输出结果
+//
+// 所有已开始的元素结束后关闭通道。默认任一元素失败后不再分发新元素，
+// 已开始的元素仍会输出结果（通常是取消错误）。
+// 调用方应读完通道或取消 ctx，ctx 取消后未送出的结果被丢弃。
+func Stream[T, R any](ctx context.Context, items []T, concurrency int, worker func(context.Context, T) (R, error), opts ...BatchOption) <-chan RunResult[R] {
+	config := runConfig(concurrency, opts)
+	ch := make(chan RunResult[R])
+
+	go func() {
+		defer close(ch)
+		var mu sync.Mutex
+		run(ctx, items, config, worker, func(i int, result R, err error) {
+			// 串行送出，避免 ctx 取消后多个协程同时阻塞
+			mu.Lock()
+			defer mu.Unlock()
+			select {
+			case ch <- RunResult[R]{Index: i, Value: result, Err: err}:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return ch
+}
+
+// runConfig 合并并发数参数和选项
+func runConfig(concurrency int, opts []BatchOption) *BatchConfig {
+	config := DefaultBatchConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	if concurrency > 0 {
+		config.MaxConcurrency = concurrency
+	}
+	if config.MaxConcurrency <= 0 {
+		config.MaxConcurrency = runtime.NumCPU()
+	}
+	return config
+}
+
+// run 分发元素并在每个元素结束时调用 done，所有已开始的元素结束后返回
+func run[T, R any](ctx context.Context, items []T, config *BatchConfig, worker func(context.Context, T) (R, error), done func(i int, result R, err error)) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, config.MaxConcurrency)
+	var wg sync.WaitGroup
+
+dispatch:
+	for i, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := safeCall(ctx, item, worker)
+			if err != nil && config.StopOnError {
+				cancel()
+			}
+			done(i, result, err)
+		}()
+	}
+	wg.Wait()
+}
+
+// safeCall 调用 worker 并将 panic 转为错误
+func safeCall[T, R any](ctx context.Context, item T, worker func(context.Context, T) (R, error)) (result R, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return worker(ctx, item)
+}
+
+// firstError 返回首个失败元素的错误，优先于其他元素失败后被取消产生的错误
+func firstError(errs []error) (int, error) {
+	first := -1
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			return i, err
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	if first < 0 {
+		return 0, nil
+	}
+	return first, errs[first]
+}
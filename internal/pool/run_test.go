@@ -0,0 +1,99 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var running, peak atomic.Int32
+	items := []int{5, 4, 3, 2, 1}
+	results, err := Run(context.Background(), items, 2, func(ctx context.Context, n int) (int, error) {
+		cur := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); cur > p && !peak.CompareAndSwap(p, cur); p = peak.Load() {
+		}
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n * 10, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{50, 40, 30, 20, 10}; !slices.Equal(results, want) {
+		t.Errorf("results = %v, want %v", results, want)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("expected at most 2 concurrent workers, got %d", p)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	errBad := errors.New("bad")
+	worker := func(ctx context.Context, n int) (int, error) {
+		if n%2 == 1 {
+			return 0, errBad
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+		return n, nil
+	}
+
+	// 默认遇错即停
+	_, err := Run(context.Background(), []int{0, 1, 2}, 1, worker)
+	var itemErr *ItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != 1 || !errors.Is(err, errBad) {
+		t.Errorf("expected item 1 error, got %v", err)
+	}
+
+	// 继续处理其余元素
+	results, err := Run(context.Background(), []int{0, 1, 2, 3}, 2, worker, WithStopOnError(false))
+	if !slices.Equal(results, []int{0, 0, 2, 0}) {
+		t.Errorf("results = %v", results)
+	}
+	if !errors.Is(err, errBad) || err.Error() != "item 1: bad\nitem 3: bad" {
+		t.Errorf("unexpected joined error: %v", err)
+	}
+
+	// panic 转为元素错误
+	_, err = Run(context.Background(), []int{0}, 1, func(ctx context.Context, n int) (int, error) {
+		panic("boom")
+	})
+	if err == nil || err.Error() != "item 0: panic: boom" {
+		t.Errorf("expected panic error, got %v", err)
+	}
+
+	// 调用方取消
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, []int{0, 2}, 1, worker); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	ch := Stream(context.Background(), []int{30, 1, 10}, 3, func(ctx context.Context, n int) (int, error) {
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n, nil
+	})
+
+	var order []int
+	for r := range ch {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		if r.Value != []int{30, 1, 10}[r.Index] {
+			t.Errorf("result %d has value %d", r.Index, r.Value)
+		}
+		order = append(order, r.Index)
+	}
+	if !slices.Equal(order, []int{1, 2, 0}) {
+		t.Errorf("expected completion order [1 2 0], got %v", order)
+	}
+}
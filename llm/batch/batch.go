@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/toolkit/util/rate"
 	"github.com/hexagon-codes/toolkit/util/retry"
 )
@@ -40,13 +41,13 @@ var (
 	ErrQueueFull = errors.New("request queue is full")
 
 	// ErrRateLimited 被限流
-	ErrRateLimited = errors.New("rate limited")
+	ErrRateLimited = core.ErrRateLimited
 
 	// ErrTimeout 超时
 	ErrTimeout = errors.New("timeout")
 
 	// ErrProviderNotSet 未设置 Provider
-	ErrProviderNotSet = errors.New("provider not set")
+	ErrProviderNotSet = core.NewCategoryError("provider not set", core.ErrNotConfigured)
 )
 
 // ============== 请求和响应 ==============
@@ -484,7 +485,7 @@ func isRetryableError(err error) bool {
 	}
 	// 可重试的错误类型
 	errStr := err.Error()
-	return errors.Is(err, ErrRateLimited) ||
+	return core.IsRetryable(err) ||
 		errors.Is(err, ErrTimeout) ||
 		containsAny(errStr, "timeout", "rate limit", "429", "503", "temporarily")
 }
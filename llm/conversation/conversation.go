@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/llm/tokenizer"
 )
 
 // ============== 对话管理器 ==============
@@ -38,6 +39,7 @@ type Manager struct {
 	maxTokens    int // Token 预算上限（0 表示不限制）
 	maxTurns     int // 最大轮次（0 表示不限制）
 	maxMessages  int // 最大存储消息数（0 表示不限制，防止内存无限增长）
+	counter      tokenizer.Counter
 	mu           sync.RWMutex
 }
 
@@ -86,6 +88,14 @@ func WithMaxMessages(n int) Option {
 	}
 }
 
+// WithTokenCounter 设置 Token 计数器
+// 默认使用 tokenizer.Default()
+func WithTokenCounter(counter tokenizer.Counter) Option {
+	return func(m *Manager) {
+		m.counter = counter
+	}
+}
+
 // WithSystemPrompt 设置系统提示词
 func WithSystemPrompt(prompt string) Option {
 	return func(m *Manager) {
@@ -168,13 +178,14 @@ func (m *Manager) buildMessages(tokenBudget int) []llm.Message {
 	var result []llm.Message
 
 	// 始终包含系统提示词
+	counter := tokenizer.OrDefault(m.counter)
 	usedTokens := 0
 	if m.systemPrompt != "" {
 		result = append(result, llm.Message{
 			Role:    llm.RoleSystem,
 			Content: m.systemPrompt,
 		})
-		usedTokens += estimateTokens(m.systemPrompt)
+		usedTokens += counter.Count(m.systemPrompt)
 	}
 
 	// 应用轮次限制：找到最近 maxTurns 个 user 消息的起始位置
@@ -200,7 +211,7 @@ func (m *Manager) buildMessages(tokenBudget int) []llm.Message {
 	if tokenBudget > 0 {
 		var selected []TimedMessage
 		for i := len(msgs) - 1; i >= 0; i-- {
-			tokens := estimateTokens(msgs[i].Content)
+			tokens := counter.Count(msgs[i].Content)
 			if usedTokens+tokens > tokenBudget {
 				break
 			}
@@ -319,22 +330,3 @@ func (m *Manager) Summarize(ctx context.Context, provider llm.Provider, keepRece
 
 	return nil
 }
-
-// estimateTokens 估算文本的 Token 数
-//
-// 使用 rune 级别估算：英文约 4 字符/token，中文约 1-2 字符/token。
-// 按 rune 计数后除以 2，比 len(text)/4 对中文更准确：
-//   - 纯英文 "hello world"(11 runes) → 5 tokens (实际~3)，略高估
-//   - 纯中文 "你好世界"(4 runes) → 2 tokens (实际~4)，略低估
-//   - 混合文本取折中
-func estimateTokens(text string) int {
-	runeCount := 0
-	for range text {
-		runeCount++
-	}
-	n := runeCount / 2
-	if n == 0 && runeCount > 0 {
-		n = 1
-	}
-	return n
-}
@@ -0,0 +1,205 @@
+// Package limiter 提供进程级 LLM 调用限流
+//
+// 多个 Agent 并发运行时（例如 Team 并行模式），各自的 LLM 调用会叠加并超出
+// 提供商的速率限制。本包提供一个令牌桶 + 并发槽位的组合限流器，
+// 所有经过框架的 LLM 调用（runtime.Runner、各 Agent 的直接调用以及通过 Complete
+// 发起的辅助调用，如 RAG 查询改写和 LLM 守卫）在发起前都会获取许可，调用结束后释放。
+//
+// 限流器的选择顺序：
+//  1. context 中通过 WithContext 注入的限流器
+//  2. 通过 SetGlobal 设置的全局限流器
+//  3. 都没有时不限流
+//
+// 使用示例：
+//
+//	// 全进程限制为每秒 5 个请求、最多 3 个并发
+//	limiter.SetGlobal(limiter.New(5, 3))
+//
+//	// 或仅对某次调用链生效
+//	ctx = limiter.WithContext(ctx, limiter.New(1, 1))
+package limiter
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// Limiter LLM 调用限流器
+// 组合了令牌桶（限制速率）和信号量（限制并发），并发安全
+type Limiter struct {
+	// 令牌桶
+	rate     float64 // 每秒补充的令牌数，<= 0 表示不限速
+	burst    float64 // 桶容量
+	tokens   float64
+	lastFill time.Time
+	mu       sync.Mutex
+
+	// 并发槽位，nil 表示不限并发
+	slots chan struct{}
+}
+
+// Option 限流器选项
+type Option func(*Limiter)
+
+// WithBurst 设置令牌桶容量（允许的突发请求数）
+// 默认值: max(1, ceil(rps))
+func WithBurst(burst int) Option {
+	return func(l *Limiter) {
+		if burst > 0 {
+			l.burst = float64(burst)
+			l.tokens = l.burst
+		}
+	}
+}
+
+// New 创建限流器
+//
+// 参数：
+//   - rps: 每秒允许的请求数，<= 0 表示不限速
+//   - concurrency: 最大并发调用数，<= 0 表示不限并发
+func New(rps float64, concurrency int, opts ...Option) *Limiter {
+	l := &Limiter{
+		rate:     rps,
+		burst:    math.Max(1, math.Ceil(rps)),
+		lastFill: time.Now(),
+	}
+	l.tokens = l.burst
+	if concurrency > 0 {
+		l.slots = make(chan struct{}, concurrency)
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Acquire 获取一次调用许可，阻塞直到获得许可或 ctx 取消
+// 成功时返回的 release 必须在调用结束后执行，用于归还并发槽位
+func (l *Limiter) Acquire(ctx context.Context) (release func(), err error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release = func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if err := l.wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// wait 从令牌桶中取出一个令牌
+func (l *Limiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve 尝试取出令牌，成功返回 0，否则返回需要等待的时间
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.lastFill).Seconds()*l.rate)
+	l.lastFill = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// InFlight 返回当前正在进行的调用数
+func (l *Limiter) InFlight() int {
+	if l.slots == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// ============== 全局与 context 限流器 ==============
+
+var (
+	globalLimiter *Limiter
+	globalMu      sync.RWMutex
+)
+
+// SetGlobal 设置全局限流器，传入 nil 取消全局限流
+func SetGlobal(l *Limiter) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalLimiter = l
+}
+
+// Global 返回全局限流器，未设置时返回 nil
+func Global() *Limiter {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return globalLimiter
+}
+
+type contextKey struct{}
+
+// WithContext 将限流器注入 context，优先级高于全局限流器
+func WithContext(ctx context.Context, l *Limiter) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 返回当前生效的限流器
+// 依次查找 context 和全局设置，都没有时返回 nil
+func FromContext(ctx context.Context) *Limiter {
+	if l, ok := ctx.Value(contextKey{}).(*Limiter); ok && l != nil {
+		return l
+	}
+	return Global()
+}
+
+// Acquire 使用当前生效的限流器获取调用许可
+// 没有配置限流器时立即返回
+func Acquire(ctx context.Context) (release func(), err error) {
+	l := FromContext(ctx)
+	if l == nil {
+		return func() {}, nil
+	}
+	return l.Acquire(ctx)
+}
+
+// Complete 在当前生效的限流器许可下调用 provider.Complete
+// 框架中不经过 runtime.Runner 的 LLM 调用都应通过它发起
+func Complete(ctx context.Context, provider llm.Provider, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	release, err := Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return provider.Complete(ctx, req)
+}
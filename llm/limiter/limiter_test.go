@@ -0,0 +1,114 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Concurrency(t *testing.T) {
+	l := New(0, 2)
+
+	var current, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.Acquire(context.Background())
+			if err != nil {
+				t.Errorf("Acquire failed: %v", err)
+				return
+			}
+			defer release()
+
+			n := atomic.AddInt32(&current, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&current, -1)
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("expected at most 2 concurrent calls, got %d", peak)
+	}
+	if l.InFlight() != 0 {
+		t.Errorf("expected 0 in-flight calls, got %d", l.InFlight())
+	}
+}
+
+func TestLimiter_Rate(t *testing.T) {
+	l := New(50, 0, WithBurst(1))
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+		release()
+	}
+
+	// 第一个令牌立即可用，其余 5 个每个约 20ms
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected rate limiting to delay calls, elapsed %v", elapsed)
+	}
+}
+
+func TestLimiter_ContextCancel(t *testing.T) {
+	l := New(0, 1)
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	slow := New(0.1, 0, WithBurst(1))
+	r, _ := slow.Acquire(context.Background())
+	r()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+	if _, err := slow.Acquire(ctx2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded while waiting for token, got %v", err)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	defer SetGlobal(nil)
+
+	if FromContext(context.Background()) != nil {
+		t.Error("expected no limiter by default")
+	}
+	release, err := Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire without limiter failed: %v", err)
+	}
+	release()
+
+	global := New(10, 1)
+	SetGlobal(global)
+	if FromContext(context.Background()) != global {
+		t.Error("expected global limiter")
+	}
+
+	scoped := New(1, 1)
+	ctx := WithContext(context.Background(), scoped)
+	if FromContext(ctx) != scoped {
+		t.Error("expected context limiter to take precedence")
+	}
+}
@@ -7,6 +7,7 @@
 //   - List 解析器：解析列表输出
 //   - 结构化解析器：解析到指定结构体
 //   - 组合解析器：链式组合多个解析器
+//   - 流式部分对象：从 token 流逐步解析出越来越完整的结构体
 //
 // 设计借鉴：
 //   - LangChain: OutputParser
@@ -382,16 +383,40 @@ func (p *ListParser) WithMaxItems(max int) *ListParser {
 	return p
 }
 
+// listMarker 列表项前缀：- * + • 项目符号，或 1. 1) (1) 形式的编号
+var listMarker = regexp.MustCompile(`^(?:[-*+•]\s+|\(?\d+[.)]\s*)`)
+
 // Parse 解析列表
+//
+// 支持项目符号列表和编号列表，自动去除前缀。
+// 输出中有带前缀的列表项时，首个列表项之前和最后一个列表项之后的
+// 说明性文字（如 "Here are the items:"）会被忽略。
 func (p *ListParser) Parse(ctx context.Context, output string) ([]string, error) {
 	if output == "" {
 		return nil, ErrEmptyOutput
 	}
 
 	items := strings.Split(output, p.Separator)
-	result := make([]string, 0, len(items))
 
-	for _, item := range items {
+	// 确定列表项范围，忽略前后说明文字
+	first, last := 0, len(items)-1
+	if p.Separator == "\n" {
+		marked := -1
+		for i, item := range items {
+			if listMarker.MatchString(strings.TrimSpace(item)) {
+				if marked < 0 {
+					first = i
+				}
+				marked = i
+			}
+		}
+		if marked >= 0 {
+			last = marked
+		}
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items[first : last+1] {
 		if p.TrimItems {
 			item = strings.TrimSpace(item)
 		}
@@ -399,9 +424,7 @@ func (p *ListParser) Parse(ctx context.Context, output string) ([]string, error)
 			continue
 		}
 		// 移除常见的列表前缀
-		item = strings.TrimPrefix(item, "- ")
-		item = strings.TrimPrefix(item, "* ")
-		item = regexp.MustCompile(`^\d+\.\s*`).ReplaceAllString(item, "")
+		item = listMarker.ReplaceAllString(item, "")
 		result = append(result, item)
 	}
 
@@ -682,6 +705,36 @@ func (p *RetryParser[T]) GetTypeName() string {
 	return p.parser.GetTypeName()
 }
 
+// ============== 类型擦除 ==============
+
+// AnyParser 类型擦除的解析器
+// 用于在非泛型代码（如 Agent 配置）中保存任意 Parser[T]
+type AnyParser interface {
+	// ParseAny 解析输出
+	ParseAny(ctx context.Context, output string) (any, error)
+
+	// GetFormatInstructions 获取格式说明
+	GetFormatInstructions() string
+
+	// GetTypeName 获取类型名称
+	GetTypeName() string
+}
+
+// Any 将 Parser[T] 包装为 AnyParser
+func Any[T any](p Parser[T]) AnyParser {
+	return anyParser[T]{p}
+}
+
+// anyParser Parser[T] 的类型擦除包装
+type anyParser[T any] struct {
+	Parser[T]
+}
+
+// ParseAny 解析输出
+func (p anyParser[T]) ParseAny(ctx context.Context, output string) (any, error) {
+	return p.Parse(ctx, output)
+}
+
 // ============== 工具函数 ==============
 
 // extractJSON 从文本中提取 JSON
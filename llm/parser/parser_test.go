@@ -1753,3 +1753,31 @@ func TestJSONParser_StrictMode_ValidInput(t *testing.T) {
 		t.Errorf("Name 期望 Alice，得到 %s", result.Name)
 	}
 }
+
+func TestListParser_Preamble(t *testing.T) {
+	// 测试忽略列表前后的说明文字和多种编号格式
+	p := NewListParser()
+	input := "Here are the fruits:\n\n1) apple\n(2) banana\n• cherry\n+ durian\n\nLet me know if you need more."
+	result, err := p.Parse(context.Background(), input)
+	if err != nil {
+		t.Fatalf("列表解析失败: %v", err)
+	}
+	expected := []string{"apple", "banana", "cherry", "durian"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Errorf("期望 %v，得到 %v", expected, result)
+	}
+}
+
+func TestAnyParser(t *testing.T) {
+	p := Any[[]string](NewListParser())
+	v, err := p.ParseAny(context.Background(), "- a\n- b")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if items, ok := v.([]string); !ok || len(items) != 2 {
+		t.Errorf("期望 []string{a, b}，得到 %#v", v)
+	}
+	if p.GetTypeName() != "List" {
+		t.Errorf("GetTypeName 期望 List，得到 %s", p.GetTypeName())
+	}
+}
@@ -0,0 +1,248 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/stream"
+)
+
+// ============== 流式部分对象解析 ==============
+
+// Partial 流式结构化输出中的一个对象快照
+type Partial[T any] struct {
+	// Value 当前已能解析出的对象，尚未生成的字段为零值
+	Value T
+
+	// Final 是否为流结束后通过 Schema 验证的最终对象
+	Final bool
+}
+
+// PartialOption 流式部分对象解析配置选项
+type PartialOption func(*partialConfig)
+
+type partialConfig struct {
+	schema *core.Schema
+}
+
+// WithPartialSchema 设置最终对象的验证 Schema，默认由目标类型生成
+func WithPartialSchema(schema *core.Schema) PartialOption {
+	return func(c *partialConfig) {
+		c.schema = schema
+	}
+}
+
+// StreamPartial 将 LLM 的 token 流解析为逐步完整的 T 类型对象
+//
+// 每收到一个分块，对已累积的不完整 JSON 做尽力补全（闭合字符串和括号，
+// 丢弃未完成的键、数字和字面量），内容有变化时发送一个 Final 为 false 的快照。
+// 源流结束后解析完整输出并按 Schema 验证，通过后发送 Final 为 true 的最终对象；
+// 解析或验证失败时以 ErrParseFailure / ErrValidationFailure 关闭流。
+//
+// 示例：
+//
+//	partials := parser.StreamPartial[Profile](tokens)
+//	defer partials.Close()
+//	for {
+//	    p, err := partials.Recv()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    render(p.Value)
+//	}
+func StreamPartial[T any](sr *stream.StreamReader[string], opts ...PartialOption) *stream.StreamReader[Partial[T]] {
+	cfg := &partialConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.schema == nil {
+		var zero T
+		cfg.schema = core.GenerateSchema(zero)
+	}
+
+	reader, writer := stream.Pipe[Partial[T]](1)
+	go func() {
+		defer sr.Close()
+
+		var buf strings.Builder
+		var last string
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			buf.WriteString(chunk)
+
+			repaired := repairPartialJSON(buf.String())
+			if repaired == "" || repaired == last {
+				continue
+			}
+			var value T
+			if err := json.Unmarshal([]byte(repaired), &value); err != nil {
+				continue
+			}
+			last = repaired
+			if err := writer.Send(Partial[T]{Value: value}); err != nil {
+				return
+			}
+		}
+
+		value, err := parseFinal[T](buf.String(), cfg.schema)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if err := writer.Send(Partial[T]{Value: value, Final: true}); err != nil {
+			return
+		}
+		writer.Close()
+	}()
+
+	return reader
+}
+
+// parseFinal 解析完整输出并按 Schema 验证
+func parseFinal[T any](output string, schema *core.Schema) (T, error) {
+	var result T
+
+	jsonStr := extractJSON(output)
+	if jsonStr == "" {
+		return result, fmt.Errorf("%w: no JSON found in output", ErrParseFailure)
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return result, fmt.Errorf("%w: %v", ErrParseFailure, err)
+	}
+	if err := core.NewValidator().ValidateJSON(schema, []byte(jsonStr)); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrValidationFailure, err)
+	}
+	return result, nil
+}
+
+// repairPartialJSON 将不完整的 JSON 补全为可解析的 JSON，无法补全时返回空字符串
+//
+// 从第一个 '{' 或 '[' 开始扫描，记录最后一个完整值之后的位置和当时的括号栈。
+// 末尾是未结束的字符串值时保留已生成的部分并闭合，其余未完成的内容被丢弃。
+func repairPartialJSON(s string) string {
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return ""
+	}
+	s = s[start:]
+
+	var (
+		stack     []byte // 未闭合的 '{' 和 '['
+		safeEnd   int    // s[:safeEnd] 以完整值或开括号结尾
+		safeStack []byte // safeEnd 处的括号栈
+		prev      byte   // 字符串之外上一个非空白字符
+		inString  bool
+		isKey     bool // 当前字符串是否为对象的键
+		escapeAt  = -1 // 当前字符串中最后一个反斜杠的位置
+		escaped   bool
+	)
+	markSafe := func(end int) {
+		safeEnd = end
+		safeStack = append(safeStack[:0], stack...)
+	}
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+				escapeAt = i
+			case ch == '"':
+				inString = false
+				prev = '"'
+				if !isKey {
+					markSafe(i + 1)
+				}
+			}
+			continue
+		}
+
+		switch ch {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '"':
+			inString = true
+			escapeAt = -1
+			isKey = len(stack) > 0 && stack[len(stack)-1] == '{' && (prev == '{' || prev == ',')
+		case '{', '[':
+			stack = append(stack, ch)
+			markSafe(i + 1)
+		case '}', ']':
+			if len(stack) == 0 {
+				return ""
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return s[:i+1]
+			}
+			markSafe(i + 1)
+		case ',', ':':
+		default:
+			// 数字或字面量，只有后面出现分隔符才认为完整
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t\n\r,:]}", rune(s[end])) {
+				end++
+			}
+			if end == len(s) {
+				i = end
+				continue
+			}
+			markSafe(end)
+			i = end - 1
+		}
+		prev = ch
+	}
+
+	if inString && !isKey {
+		partial := s
+		if escaped || (escapeAt >= 0 && escapeAt+1 < len(s) && s[escapeAt+1] == 'u' && len(s)-escapeAt < 6) {
+			partial = s[:escapeAt]
+		}
+		partial = trimIncompleteRune(partial)
+		return partial + `"` + closeBrackets(stack)
+	}
+	return s[:safeEnd] + closeBrackets(safeStack)
+}
+
+// closeBrackets 按栈的逆序生成闭合括号
+func closeBrackets(stack []byte) string {
+	var sb strings.Builder
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == '{' {
+			sb.WriteByte('}')
+		} else {
+			sb.WriteByte(']')
+		}
+	}
+	return sb.String()
+}
+
+// trimIncompleteRune 去掉末尾被截断的多字节 UTF-8 字符
+func trimIncompleteRune(s string) string {
+	for n := 0; n < utf8.UTFMax-1 && len(s) > 0; n++ {
+		r, size := utf8.DecodeLastRuneInString(s)
+		if r != utf8.RuneError || size != 1 {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/stream"
+)
+
+func TestRepairPartialJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"无 JSON", "thinking...", ""},
+		{"只有开括号", "```json\n{", "{}"},
+		{"未完成的键", `{"name": "Al", "ag`, `{"name": "Al"}`},
+		{"键后的冒号", `{"name": "Al", "age":`, `{"name": "Al"}`},
+		{"未结束的字符串值", `{"name": "Ali`, `{"name": "Ali"}`},
+		{"未结束的数字", `{"age": 4`, `{}`},
+		{"已结束的数字", `{"age": 42,`, `{"age": 42}`},
+		{"未完成的字面量", `{"ok": tr`, `{}`},
+		{"嵌套数组", `{"tags": ["a", "b`, `{"tags": ["a", "b"]}`},
+		{"未完成的转义", `{"text": "line\`, `{"text": "line"}`},
+		{"未完成的 Unicode 转义", `{"text": "caf\u00`, `{"text": "caf"}`},
+		{"完整 JSON 后的文本", `{"a": 1} done`, `{"a": 1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repairPartialJSON(tt.input); got != tt.want {
+				t.Errorf("repairPartialJSON(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+type profile struct {
+	Name string   `json:"name" validate:"required"`
+	Age  int      `json:"age" validate:"required,min=0"`
+	Tags []string `json:"tags"`
+}
+
+func TestStreamPartial(t *testing.T) {
+	chunks := []string{`{"na`, `me": "Al`, `ice", "age": 3`, `0, "tags": ["go`, `", "ai"]}`}
+	partials := StreamPartial[profile](stream.FromSlice(chunks))
+	defer partials.Close()
+
+	var got []Partial[profile]
+	for {
+		p, err := partials.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p)
+	}
+
+	var names []string
+	for _, p := range got[:len(got)-1] {
+		if p.Final {
+			t.Errorf("unexpected final partial before the end: %+v", p)
+		}
+		names = append(names, p.Value.Name)
+	}
+	if want := []string{"", "Al", "Alice", "Alice", "Alice"}; !slices.Equal(names, want) {
+		t.Errorf("partial names = %v, want %v", names, want)
+	}
+
+	final := got[len(got)-1]
+	if !final.Final || final.Value.Age != 30 || !slices.Equal(final.Value.Tags, []string{"go", "ai"}) {
+		t.Errorf("unexpected final object: %+v", final)
+	}
+}
+
+func TestStreamPartial_Invalid(t *testing.T) {
+	// 缺少必需字段时最终对象验证失败
+	partials := StreamPartial[profile](stream.FromSlice([]string{`{"name": "Bob"}`}))
+	defer partials.Close()
+
+	var err error
+	for err == nil {
+		_, err = partials.Recv()
+	}
+	if !errors.Is(err, ErrValidationFailure) {
+		t.Errorf("expected validation failure, got %v", err)
+	}
+
+	partials = StreamPartial[profile](stream.FromSlice([]string{"no json here"}))
+	defer partials.Close()
+	for err = nil; err == nil; {
+		_, err = partials.Recv()
+	}
+	if !errors.Is(err, ErrParseFailure) {
+		t.Errorf("expected parse failure, got %v", err)
+	}
+}
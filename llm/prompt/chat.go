@@ -0,0 +1,86 @@
+package prompt
+
+import (
+	"fmt"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// ChatTemplate 对话消息模板
+//
+// 按顺序渲染 system / user / assistant 消息，所有消息共享同一组片段、函数和默认值。
+//
+// 使用示例：
+//
+//	chat := prompt.NewChat(prompt.WithPartial("rules", "Be concise.")).
+//	    System("You are {{.role}}. {{template \"rules\" .}}").
+//	    User("{{.query}}")
+//	messages, err := chat.Render(vars)
+type ChatTemplate struct {
+	cfg      *config
+	messages []chatMessage
+	err      error
+}
+
+// chatMessage 单条消息模板
+type chatMessage struct {
+	role llm.Role
+	tpl  *Template
+}
+
+// NewChat 创建对话消息模板
+func NewChat(opts ...Option) *ChatTemplate {
+	return &ChatTemplate{cfg: newConfig(opts)}
+}
+
+// System 追加系统消息模板
+func (c *ChatTemplate) System(text string) *ChatTemplate {
+	return c.Message(llm.RoleSystem, text)
+}
+
+// User 追加用户消息模板
+func (c *ChatTemplate) User(text string) *ChatTemplate {
+	return c.Message(llm.RoleUser, text)
+}
+
+// Assistant 追加助手消息模板
+func (c *ChatTemplate) Assistant(text string) *ChatTemplate {
+	return c.Message(llm.RoleAssistant, text)
+}
+
+// Message 追加指定角色的消息模板
+// 解析错误会在 Err 和 Render 时返回
+func (c *ChatTemplate) Message(role llm.Role, text string) *ChatTemplate {
+	if c.err != nil {
+		return c
+	}
+	name := fmt.Sprintf("%s#%d", role, len(c.messages))
+	tpl, err := newTemplate(name, text, c.cfg)
+	if err != nil {
+		c.err = err
+		return c
+	}
+	c.messages = append(c.messages, chatMessage{role: role, tpl: tpl})
+	return c
+}
+
+// Err 返回构建过程中的解析错误
+func (c *ChatTemplate) Err() error {
+	return c.err
+}
+
+// Render 渲染消息列表
+func (c *ChatTemplate) Render(vars map[string]any) ([]llm.Message, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	messages := make([]llm.Message, 0, len(c.messages))
+	for _, m := range c.messages {
+		content, err := m.tpl.Render(vars)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, llm.Message{Role: m.role, Content: content})
+	}
+	return messages, nil
+}
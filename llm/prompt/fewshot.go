@@ -0,0 +1,139 @@
+package prompt
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// FewShot Few-shot 提示词
+//
+// 渲染结果依次为前缀、每个示例、后缀，以分隔符连接。
+// 示例模板以单个示例的字段为变量；前缀和后缀以 Render 的变量渲染，
+// 并可通过 {{.examples}} 引用已渲染的示例文本。
+//
+// 使用示例：
+//
+//	fs, err := prompt.NewFewShot("Q: {{.q}}\nA: {{.a}}",
+//	    []map[string]any{{"q": "2+2", "a": "4"}, {"q": "3*3", "a": "9"}},
+//	    prompt.WithFewShotPrefix("Answer like the examples:"),
+//	    prompt.WithFewShotSuffix("Q: {{.query}}\nA:"),
+//	)
+//	text, err := fs.Render(map[string]any{"query": "5-1"})
+type FewShot struct {
+	example   *Template
+	examples  []map[string]any
+	prefix    string
+	suffix    string
+	separator string
+	opts      []Option
+
+	prefixTpl *Template
+	suffixTpl *Template
+}
+
+// FewShotOption FewShot 配置选项
+type FewShotOption func(*FewShot)
+
+// WithFewShotPrefix 设置前缀模板
+func WithFewShotPrefix(text string) FewShotOption {
+	return func(f *FewShot) {
+		f.prefix = text
+	}
+}
+
+// WithFewShotSuffix 设置后缀模板
+func WithFewShotSuffix(text string) FewShotOption {
+	return func(f *FewShot) {
+		f.suffix = text
+	}
+}
+
+// WithFewShotSeparator 设置分隔符，默认 "\n\n"
+func WithFewShotSeparator(sep string) FewShotOption {
+	return func(f *FewShot) {
+		f.separator = sep
+	}
+}
+
+// WithFewShotOptions 设置解析前缀、后缀和示例模板时使用的模板选项
+func WithFewShotOptions(opts ...Option) FewShotOption {
+	return func(f *FewShot) {
+		f.opts = append(f.opts, opts...)
+	}
+}
+
+// NewFewShot 创建 Few-shot 提示词
+func NewFewShot(example string, examples []map[string]any, opts ...FewShotOption) (*FewShot, error) {
+	f := &FewShot{
+		examples:  examples,
+		separator: "\n\n",
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	cfg := newConfig(f.opts)
+	var err error
+	if f.example, err = newTemplate("example", example, cfg); err != nil {
+		return nil, err
+	}
+	if f.prefix != "" {
+		if f.prefixTpl, err = newTemplate("prefix", f.prefix, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if f.suffix != "" {
+		if f.suffixTpl, err = newTemplate("suffix", f.suffix, cfg); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// RenderExamples 只渲染示例部分
+func (f *FewShot) RenderExamples() (string, error) {
+	parts := make([]string, 0, len(f.examples))
+	for i, ex := range f.examples {
+		text, err := f.example.Render(ex)
+		if err != nil {
+			return "", fmt.Errorf("example %d: %w", i, err)
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, f.separator), nil
+}
+
+// Render 渲染完整的 Few-shot 提示词
+func (f *FewShot) Render(vars map[string]any) (string, error) {
+	examples, err := f.RenderExamples()
+	if err != nil {
+		return "", err
+	}
+
+	data := make(map[string]any, len(vars)+1)
+	maps.Copy(data, vars)
+	data["examples"] = examples
+
+	parts := make([]string, 0, 3)
+	if f.prefixTpl != nil {
+		prefix, err := f.prefixTpl.Render(data)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, prefix)
+	}
+	if examples != "" {
+		parts = append(parts, examples)
+	}
+	if f.suffixTpl != nil {
+		suffix, err := f.suffixTpl.Render(data)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, suffix)
+	}
+	return strings.Join(parts, f.separator), nil
+}
+
+var _ Renderer = (*FewShot)(nil)
@@ -0,0 +1,200 @@
+// Package prompt 提供基于 Go text/template 的提示词模板
+//
+// 与 llm/template 的 Jinja2 风格语法不同，本包直接使用 Go 模板语法，支持：
+//   - 命名变量：{{.query}}
+//   - 条件与循环：{{if .verbose}}...{{end}}、{{range .items}}...{{end}}
+//   - 可复用片段（partial）：{{template "rules" .}}
+//   - 对话消息模板：按 system / user / assistant 渲染消息列表
+//   - Few-shot 示例渲染
+//
+// 渲染时缺少变量会返回 ErrMissingVariable，而不是输出 "<no value>"。
+// 可选变量需通过 WithDefaults 提供默认值。
+//
+// 使用示例：
+//
+//	tpl, err := prompt.New("qa",
+//	    "You are {{.role}}.\n{{template \"rules\" .}}\nQuestion: {{.query}}",
+//	    prompt.WithPartial("rules", "Answer in {{.lang}}."),
+//	    prompt.WithDefaults(map[string]any{"lang": "English"}),
+//	)
+//	text, err := tpl.Render(map[string]any{"role": "a helpful assistant", "query": "..."})
+package prompt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// ErrMissingVariable 渲染时缺少变量
+var ErrMissingVariable = errors.New("prompt: missing variable")
+
+// Renderer 可渲染的提示词
+// Template 和 FewShot 均实现此接口
+type Renderer interface {
+	Render(vars map[string]any) (string, error)
+}
+
+// Template 提示词模板
+// 创建后只读，可在多个 goroutine 中并发渲染
+type Template struct {
+	name     string
+	text     string
+	defaults map[string]any
+	parsed   *template.Template
+}
+
+// Option 模板配置选项
+type Option func(*config)
+
+// config 模板配置
+type config struct {
+	partials map[string]string
+	funcs    template.FuncMap
+	defaults map[string]any
+}
+
+// WithPartial 注册可复用片段，模板中通过 {{template "name" .}} 引用
+func WithPartial(name, text string) Option {
+	return func(c *config) {
+		c.partials[name] = text
+	}
+}
+
+// WithPartials 批量注册可复用片段
+func WithPartials(partials map[string]string) Option {
+	return func(c *config) {
+		maps.Copy(c.partials, partials)
+	}
+}
+
+// WithFuncs 注册模板函数，同名时覆盖内置函数
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(c *config) {
+		maps.Copy(c.funcs, funcs)
+	}
+}
+
+// WithDefaults 设置变量默认值，渲染时未提供的变量使用默认值
+func WithDefaults(defaults map[string]any) Option {
+	return func(c *config) {
+		maps.Copy(c.defaults, defaults)
+	}
+}
+
+// newConfig 应用选项
+func newConfig(opts []Option) *config {
+	c := &config{
+		partials: make(map[string]string),
+		funcs:    defaultFuncs(),
+		defaults: make(map[string]any),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// New 创建并解析提示词模板
+func New(name, text string, opts ...Option) (*Template, error) {
+	return newTemplate(name, text, newConfig(opts))
+}
+
+// MustNew 创建提示词模板，解析失败时 panic
+// 适用于包级变量等模板内容固定的场景
+func MustNew(name, text string, opts ...Option) *Template {
+	t, err := New(name, text, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// newTemplate 按配置解析模板
+func newTemplate(name, text string, cfg *config) (*Template, error) {
+	parsed := template.New(name).Funcs(cfg.funcs).Option("missingkey=error")
+	for partial, body := range cfg.partials {
+		if _, err := parsed.New(partial).Parse(body); err != nil {
+			return nil, fmt.Errorf("prompt: parse partial %s: %w", partial, err)
+		}
+	}
+	if _, err := parsed.Parse(text); err != nil {
+		return nil, fmt.Errorf("prompt: parse template %s: %w", name, err)
+	}
+
+	return &Template{
+		name:     name,
+		text:     text,
+		defaults: cfg.defaults,
+		parsed:   parsed,
+	}, nil
+}
+
+// Name 返回模板名称
+func (t *Template) Name() string {
+	return t.name
+}
+
+// Text 返回模板原文
+func (t *Template) Text() string {
+	return t.text
+}
+
+// Render 使用变量渲染模板
+// 缺少变量时返回包装了 ErrMissingVariable 的错误
+func (t *Template) Render(vars map[string]any) (string, error) {
+	data := vars
+	if len(t.defaults) > 0 {
+		data = make(map[string]any, len(t.defaults)+len(vars))
+		maps.Copy(data, t.defaults)
+		maps.Copy(data, vars)
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	var sb strings.Builder
+	if err := t.parsed.Execute(&sb, data); err != nil {
+		if key, ok := missingKey(err); ok {
+			return "", fmt.Errorf("%w %q in template %s", ErrMissingVariable, key, t.name)
+		}
+		return "", fmt.Errorf("prompt: render template %s: %w", t.name, err)
+	}
+	return sb.String(), nil
+}
+
+// missingKeyPattern text/template 在 missingkey=error 时的错误信息
+var missingKeyPattern = regexp.MustCompile(`map has no entry for key "([^"]*)"`)
+
+// missingKey 从执行错误中提取缺失的变量名
+func missingKey(err error) (string, bool) {
+	m := missingKeyPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// defaultFuncs 内置模板函数
+func defaultFuncs() template.FuncMap {
+	return template.FuncMap{
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"trim":  strings.TrimSpace,
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"indent": func(spaces int, s string) string {
+			pad := strings.Repeat(" ", spaces)
+			return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+	}
+}
+
+var _ Renderer = (*Template)(nil)
@@ -0,0 +1,100 @@
+package prompt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestTemplateRender(t *testing.T) {
+	tpl, err := New("qa",
+		`{{template "intro" .}}{{if .verbose}} Explain step by step.{{end}} Q: {{.query}}`,
+		WithPartial("intro", "You are {{.role}}."),
+		WithDefaults(map[string]any{"verbose": false}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tpl.Render(map[string]any{"role": "a tutor", "query": "1+1?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "You are a tutor. Q: 1+1?"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	got, err = tpl.Render(map[string]any{"role": "a tutor", "query": "1+1?", "verbose": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "step by step") {
+		t.Errorf("expected conditional section, got %q", got)
+	}
+
+	_, err = tpl.Render(map[string]any{"query": "1+1?"})
+	if !errors.Is(err, ErrMissingVariable) || !strings.Contains(err.Error(), `"role"`) {
+		t.Errorf("expected missing variable role, got %v", err)
+	}
+
+	if _, err := New("bad", "{{.query"); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestChatTemplate(t *testing.T) {
+	chat := NewChat(WithPartial("rules", "Be brief.")).
+		System(`You are {{.role}}. {{template "rules"}}`).
+		User("{{.query}}").
+		Assistant("Sure.")
+
+	msgs, err := chat.Render(map[string]any{"role": "a bot", "query": "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(msgs))
+	}
+	if msgs[0].Role != llm.RoleSystem || msgs[0].Content != "You are a bot. Be brief." {
+		t.Errorf("unexpected system message: %+v", msgs[0])
+	}
+	if msgs[1].Role != llm.RoleUser || msgs[1].Content != "hello" {
+		t.Errorf("unexpected user message: %+v", msgs[1])
+	}
+
+	if _, err := chat.Render(map[string]any{"role": "a bot"}); !errors.Is(err, ErrMissingVariable) {
+		t.Errorf("expected ErrMissingVariable, got %v", err)
+	}
+
+	bad := NewChat().System("{{if}}").User("{{.query}}")
+	if bad.Err() == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestFewShot(t *testing.T) {
+	fs, err := NewFewShot("Q: {{.q}}\nA: {{.a}}",
+		[]map[string]any{{"q": "2+2", "a": "4"}, {"q": "3*3", "a": "9"}},
+		WithFewShotPrefix("Answer like the examples."),
+		WithFewShotSuffix("Q: {{.query}}\nA:"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := fs.Render(map[string]any{"query": "5-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Answer like the examples.\n\nQ: 2+2\nA: 4\n\nQ: 3*3\nA: 9\n\nQ: 5-1\nA:"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	fs, _ = NewFewShot("Q: {{.q}}\nA: {{.a}}", []map[string]any{{"q": "x"}})
+	if _, err := fs.Render(nil); !errors.Is(err, ErrMissingVariable) {
+		t.Errorf("expected ErrMissingVariable for incomplete example, got %v", err)
+	}
+}
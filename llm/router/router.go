@@ -15,7 +15,6 @@ package router
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -23,16 +22,17 @@ import (
 	"sync/atomic"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // ============== 错误定义 ==============
 
 var (
 	// ErrNoProvider 没有可用的 Provider
-	ErrNoProvider = errors.New("router: 没有可用的 LLM Provider")
+	ErrNoProvider = core.NewCategoryError("router: 没有可用的 LLM Provider", core.ErrProviderUnavailable)
 
 	// ErrAllFailed 所有 Provider 均失败
-	ErrAllFailed = errors.New("router: 所有 Provider 均失败")
+	ErrAllFailed = core.NewCategoryError("router: 所有 Provider 均失败", core.ErrProviderUnavailable)
 )
 
 // ============== 路由策略 ==============
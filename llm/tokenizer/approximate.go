@@ -0,0 +1,31 @@
+package tokenizer
+
+import (
+	"github.com/hexagon-codes/ai-core/llm"
+	coretok "github.com/hexagon-codes/ai-core/tokenizer"
+)
+
+// Approximate 近似 Token 计数器
+//
+// 按字符类型混合估算（英文约 4 字符/token，中文约 1.5 字/token，标点约 2 个/token），
+// 误差约 ±10%，无外部依赖，作为未注册模型的默认计数器。
+type Approximate struct {
+	counter *coretok.Counter
+}
+
+// NewApproximate 创建近似计数器
+func NewApproximate() *Approximate {
+	return &Approximate{counter: coretok.New("")}
+}
+
+// Count 估算文本的 Token 数
+func (a *Approximate) Count(text string) int {
+	return a.counter.Count(text)
+}
+
+// CountMessages 估算消息列表的 Token 数
+func (a *Approximate) CountMessages(messages []llm.Message) int {
+	return countMessages(a.Count, openAIFormat, messages)
+}
+
+var _ Counter = (*Approximate)(nil)
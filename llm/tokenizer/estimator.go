@@ -0,0 +1,133 @@
+package tokenizer
+
+import (
+	"math"
+	"unicode"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// tokenRatios 按字符类型的 token 比率
+// 不同的 BPE 编码对不同字符类型有不同的压缩率
+type tokenRatios struct {
+	// letter 英文等字母字符每个字符的 token 比率（如 "hello" → ~1.3 token）
+	letter float64
+	// cjk CJK 字符（中日韩）每个字符的 token 比率
+	cjk float64
+	// digit 数字每个字符的 token 比率
+	digit float64
+	// punctuation 标点符号每个字符的 token 比率
+	punctuation float64
+	// whitespace 空白字符每个字符的 token 比率
+	whitespace float64
+}
+
+// encodingRatios 各编码的 token 比率，基于实际 tiktoken 统计的近似值
+var encodingRatios = map[Encoding]tokenRatios{
+	CL100kBase: {
+		letter:      0.25, // 平均 4 字符 = 1 token
+		cjk:         0.5,  // 平均 2 字符 = 1 token（CJK 通常 1-2 字符/token）
+		digit:       0.33, // 平均 3 位数字 = 1 token
+		punctuation: 1.0,  // 每个标点约 1 token
+		whitespace:  0.25, // 空白通常与前后词合并
+	},
+	// 更大的词表带来更好的压缩率
+	O200kBase: {
+		letter:      0.22,
+		cjk:         0.45,
+		digit:       0.28,
+		punctuation: 0.9,
+		whitespace:  0.2,
+	},
+}
+
+// Estimator 按 tiktoken 编码特性估算的计数器
+//
+// 不进行 BPE 编码，按编码对各字符类型的压缩率估算，结果比 Approximate 更贴近 OpenAI 模型；
+// 需要精确计数时使用 Tiktoken。消息开销按 OpenAI Chat 格式计算。
+type Estimator struct {
+	model    string
+	encoding Encoding
+}
+
+// EstimatorOption Estimator 配置选项
+type EstimatorOption func(*Estimator)
+
+// WithEncoding 指定编码，覆盖按模型推断的编码
+func WithEncoding(encoding Encoding) EstimatorOption {
+	return func(e *Estimator) {
+		e.encoding = encoding
+	}
+}
+
+// NewEstimator 创建估算计数器
+// 根据模型名选择编码
+func NewEstimator(model string, opts ...EstimatorOption) *Estimator {
+	e := &Estimator{
+		model:    model,
+		encoding: EncodingForModel(model),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Model 返回模型名
+func (e *Estimator) Model() string {
+	return e.model
+}
+
+// Encoding 返回编码名称
+func (e *Estimator) Encoding() Encoding {
+	return e.encoding
+}
+
+// Count 估算文本的 Token 数
+func (e *Estimator) Count(text string) int {
+	if text == "" {
+		return 0
+	}
+	return e.estimate(text)
+}
+
+// CountMessages 按 OpenAI Chat 格式估算消息列表的 Token 数
+func (e *Estimator) CountMessages(messages []llm.Message) int {
+	return countMessages(e.Count, openAIFormat, messages)
+}
+
+// estimate 按字符类型差异化估算 token 数
+func (e *Estimator) estimate(text string) int {
+	ratios, ok := encodingRatios[e.encoding]
+	if !ok {
+		ratios = encodingRatios[CL100kBase]
+	}
+
+	var letters, cjk, digits, puncts, spaces int
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			spaces++
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			cjk++
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			puncts++
+		default:
+			letters++
+		}
+	}
+
+	total := float64(letters)*ratios.letter +
+		float64(cjk)*ratios.cjk +
+		float64(digits)*ratios.digit +
+		float64(puncts)*ratios.punctuation +
+		float64(spaces)*ratios.whitespace
+
+	// 非空文本至少 1 个 token
+	return max(int(math.Ceil(total)), 1)
+}
+
+var _ Counter = (*Estimator)(nil)
@@ -0,0 +1,74 @@
+package tokenizer
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// Encoding tiktoken 编码名称
+type Encoding string
+
+const (
+	// CL100kBase GPT-4、GPT-3.5-turbo、text-embedding-3 系列使用的编码
+	CL100kBase Encoding = "cl100k_base"
+
+	// O200kBase GPT-4o、GPT-4.1、o 系列使用的编码
+	O200kBase Encoding = "o200k_base"
+)
+
+// EncodingForModel 返回模型使用的 tiktoken 编码，未知模型返回 cl100k_base
+func EncodingForModel(model string) Encoding {
+	for _, prefix := range []string{"gpt-4o", "gpt-4.1", "gpt-5", "o1", "o3", "o4", "chatgpt-4o"} {
+		if strings.HasPrefix(model, prefix) {
+			return O200kBase
+		}
+	}
+	return CL100kBase
+}
+
+// EncodeFunc 将文本编码为 token ID 序列
+type EncodeFunc func(text string) []int
+
+// ErrNilEncoder 创建 Tiktoken 时未提供编码器
+var ErrNilEncoder = errors.New("tokenizer: tiktoken encoder is nil")
+
+// Tiktoken 基于 OpenAI tiktoken BPE 编码器的精确计数器
+//
+// 本包不内置 BPE 词表，编码器由调用方提供（如 tiktoken-go）；
+// 没有编码器时使用 Estimator 按编码特性估算。消息开销按 OpenAI Chat 格式计算。
+type Tiktoken struct {
+	encoding Encoding
+	encode   EncodeFunc
+}
+
+// NewTiktoken 创建 tiktoken 计数器
+//
+// encode 需与 encoding 一致且并发安全，为 nil 时返回 ErrNilEncoder。
+func NewTiktoken(encoding Encoding, encode EncodeFunc) (*Tiktoken, error) {
+	if encode == nil {
+		return nil, ErrNilEncoder
+	}
+	return &Tiktoken{encoding: encoding, encode: encode}, nil
+}
+
+// Encoding 返回编码名称
+func (t *Tiktoken) Encoding() Encoding {
+	return t.encoding
+}
+
+// Count 计算文本的 Token 数
+func (t *Tiktoken) Count(text string) int {
+	if text == "" {
+		return 0
+	}
+	return len(t.encode(text))
+}
+
+// CountMessages 按 OpenAI Chat 格式计算消息列表的 Token 数
+func (t *Tiktoken) CountMessages(messages []llm.Message) int {
+	return countMessages(t.Count, openAIFormat, messages)
+}
+
+var _ Counter = (*Tiktoken)(nil)
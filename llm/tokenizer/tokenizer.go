@@ -0,0 +1,162 @@
+// Package tokenizer 提供框架统一的 Token 计数抽象
+//
+// 记忆裁剪、文档分块、成本预估等都需要计算 Token 数，本包提供统一的 Counter 接口，
+// 避免各模块各自估算导致结果不一致：
+//   - Approximate: 默认的近似计数器，无外部依赖，适用于任意模型
+//   - Estimator: 按 OpenAI tiktoken 编码（cl100k_base / o200k_base）的特性估算，
+//     OpenAI 模型的默认计数器
+//   - Tiktoken: 接入真实 BPE 编码器（如 tiktoken-go）的精确计数器
+//
+// 计数器按模型选择，ForModel 按模型名前缀查找已注册的计数器，
+// 未匹配时返回默认计数器。
+//
+// 使用示例：
+//
+//	counter := tokenizer.ForModel("gpt-4o")
+//	n := counter.CountMessages(messages)
+//
+//	// 接入 tiktoken-go 获得精确计数
+//	enc, _ := tiktoken.GetEncoding("o200k_base")
+//	counter, _ := tokenizer.NewTiktoken(tokenizer.O200kBase,
+//	    func(s string) []int { return enc.Encode(s, nil, nil) })
+//	tokenizer.Register("gpt-4o", counter)
+package tokenizer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// Counter Token 计数器
+// 实现需并发安全
+type Counter interface {
+	// Count 计算文本的 Token 数
+	Count(text string) int
+
+	// CountMessages 计算消息列表的 Token 数，包含角色和消息格式的开销
+	CountMessages(messages []llm.Message) int
+}
+
+// ============== 计数器注册表 ==============
+
+var (
+	mu         sync.RWMutex
+	defaultCtr Counter = NewApproximate()
+	registry           = map[string]Counter{
+		"gpt-4o":                 NewEstimator("gpt-4o"),
+		"gpt-4.1":                NewEstimator("gpt-4.1"),
+		"gpt-4":                  NewEstimator("gpt-4"),
+		"gpt-3.5-turbo":          NewEstimator("gpt-3.5-turbo"),
+		"o1":                     NewEstimator("o1"),
+		"o3":                     NewEstimator("o3"),
+		"o4":                     NewEstimator("o4"),
+		"text-embedding-":        NewEstimator("text-embedding-3-small"),
+		"text-embedding-ada-002": NewEstimator("text-embedding-ada-002"),
+	}
+)
+
+// Default 返回默认计数器
+func Default() Counter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultCtr
+}
+
+// SetDefault 设置默认计数器，传入 nil 恢复为 Approximate
+func SetDefault(c Counter) {
+	mu.Lock()
+	defer mu.Unlock()
+	if c == nil {
+		c = NewApproximate()
+	}
+	defaultCtr = c
+}
+
+// Register 为模型名前缀注册计数器
+// 传入 nil 取消注册；查找时使用最长匹配前缀
+func Register(modelPrefix string, c Counter) {
+	mu.Lock()
+	defer mu.Unlock()
+	if c == nil {
+		delete(registry, modelPrefix)
+		return
+	}
+	registry[modelPrefix] = c
+}
+
+// ForModel 返回模型对应的计数器
+// 按最长前缀匹配已注册的计数器，未匹配（或模型名为空）时返回默认计数器
+func ForModel(model string) Counter {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if model != "" {
+		prefixes := make([]string, 0, len(registry))
+		for prefix := range registry {
+			if strings.HasPrefix(model, prefix) {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+		if len(prefixes) > 0 {
+			sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+			return registry[prefixes[0]]
+		}
+	}
+	return defaultCtr
+}
+
+// OrDefault 返回 c，c 为 nil 时返回默认计数器
+// 供接受可选计数器的组件使用
+func OrDefault(c Counter) Counter {
+	if c != nil {
+		return c
+	}
+	return Default()
+}
+
+// ============== 消息计数 ==============
+
+// messageFormat 消息格式开销
+type messageFormat struct {
+	// perMessage 每条消息的固定开销
+	perMessage int
+	// perName 消息带 Name 时的额外开销
+	perName int
+	// replyPriming 回复前导开销
+	replyPriming int
+}
+
+// openAIFormat OpenAI Chat 格式开销
+// 参考 OpenAI Cookbook "How to count tokens with tiktoken"
+var openAIFormat = messageFormat{perMessage: 3, perName: 1, replyPriming: 3}
+
+// countMessages 按消息格式累加消息 Token 数
+// 多模态消息计算文本部分，助手消息计算工具调用的名称和参数
+func countMessages(count func(string) int, format messageFormat, messages []llm.Message) int {
+	if len(messages) == 0 {
+		return 0
+	}
+
+	total := format.replyPriming
+	for _, msg := range messages {
+		total += format.perMessage
+		total += count(string(msg.Role))
+		if len(msg.MultiContent) > 0 {
+			for _, part := range msg.MultiContent {
+				total += count(part.Text)
+			}
+		} else {
+			total += count(msg.Content)
+		}
+		if msg.Name != "" {
+			total += count(msg.Name) + format.perName
+		}
+		for _, tc := range msg.ToolCalls {
+			total += count(tc.Name) + count(tc.Arguments)
+		}
+	}
+	return total
+}
@@ -0,0 +1,89 @@
+package tokenizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestForModel(t *testing.T) {
+	tests := []struct {
+		model    string
+		encoding Encoding
+	}{
+		{"gpt-4", CL100kBase},
+		{"gpt-4-turbo", CL100kBase},
+		{"gpt-4o-mini", O200kBase},
+		{"o3-mini", O200kBase},
+	}
+	for _, tt := range tests {
+		c, ok := ForModel(tt.model).(*Estimator)
+		if !ok {
+			t.Fatalf("%s: expected Estimator counter, got %T", tt.model, ForModel(tt.model))
+		}
+		if c.Encoding() != tt.encoding {
+			t.Errorf("%s: expected %s, got %s", tt.model, tt.encoding, c.Encoding())
+		}
+	}
+
+	if _, ok := ForModel("deepseek-chat").(*Approximate); !ok {
+		t.Errorf("expected default counter for unknown model")
+	}
+
+	custom, err := NewTiktoken(CL100kBase, func(s string) []int { return make([]int, len(s)) })
+	if err != nil {
+		t.Fatalf("NewTiktoken failed: %v", err)
+	}
+	Register("deepseek", custom)
+	defer Register("deepseek", nil)
+	if ForModel("deepseek-chat") != Counter(custom) {
+		t.Errorf("expected registered counter")
+	}
+	if n := custom.Count("hello"); n != 5 {
+		t.Errorf("expected encoder count 5, got %d", n)
+	}
+}
+
+func TestNewTiktoken_RequiresEncoder(t *testing.T) {
+	if _, err := NewTiktoken(O200kBase, nil); !errors.Is(err, ErrNilEncoder) {
+		t.Errorf("expected ErrNilEncoder, got %v", err)
+	}
+}
+
+func TestCountMessages(t *testing.T) {
+	c, err := NewTiktoken(CL100kBase, func(s string) []int { return make([]int, len(s)) })
+	if err != nil {
+		t.Fatalf("NewTiktoken failed: %v", err)
+	}
+
+	if n := c.CountMessages(nil); n != 0 {
+		t.Errorf("expected 0 for no messages, got %d", n)
+	}
+
+	msgs := []llm.Message{
+		{Role: llm.RoleUser, Content: "hi", Name: "bob"},
+		{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCallRef{{Name: "calc", Arguments: "{}"}}},
+	}
+	// 前导 3 + 每条消息 3
+	// user: 4 + 2 + (3+1); assistant: 9 + 4 + 2
+	want := 3 + (3 + 4 + 2 + 3 + 1) + (3 + 9 + 4 + 2)
+	if n := c.CountMessages(msgs); n != want {
+		t.Errorf("expected %d, got %d", want, n)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	for _, c := range []Counter{NewApproximate(), NewEstimator("gpt-4"), NewEstimator("gpt-4o")} {
+		if n := c.Count(""); n != 0 {
+			t.Errorf("%T: expected 0 for empty text, got %d", c, n)
+		}
+		if n := c.Count("a"); n < 1 {
+			t.Errorf("%T: expected at least 1 token, got %d", c, n)
+		}
+		en := c.Count("The quick brown fox jumps over the lazy dog")
+		if en < 5 || en > 20 {
+			t.Errorf("%T: unexpected english estimate %d", c, en)
+		}
+	}
+}
@@ -9,6 +9,7 @@
 // 使用示例：
 //
 //	mem := NewWindowMemory(10) // 保留最近 10 条
+//	mem := NewWindowMemory(50, WithMaxTokens(4000)) // 同时限制总 Token 数
 //	mem := NewSummaryMemory(llmProvider, "model", 20) // 超过 20 条时摘要
 //	mem := NewVectorMemory(embedder, vectorStore, 100)
 package memory
@@ -22,30 +23,56 @@ import (
 
 	"github.com/hexagon-codes/ai-core/llm"
 	coremem "github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/hexagon/llm/tokenizer"
 )
 
 // ============== WindowMemory ==============
 
 // WindowMemory 滑动窗口记忆
 // 只保留最近 N 条对话记录，超出时自动移除最旧的
+// 设置 WithMaxTokens 后还按总 Token 数裁剪
 type WindowMemory struct {
 	mu         sync.RWMutex
 	entries    []coremem.Entry
 	index      map[string]int // ID -> entries 索引，加速 Get/Delete 查找
 	windowSize int
+	maxTokens  int
+	counter    tokenizer.Counter
+}
+
+// WindowOption WindowMemory 配置选项
+type WindowOption func(*WindowMemory)
+
+// WithMaxTokens 设置窗口的最大总 Token 数
+// 超出时移除最旧的条目，最新一条始终保留；<= 0 表示不限制
+func WithMaxTokens(n int) WindowOption {
+	return func(m *WindowMemory) {
+		m.maxTokens = n
+	}
+}
+
+// WithTokenCounter 设置 Token 计数器，默认使用 tokenizer.Default()
+func WithTokenCounter(counter tokenizer.Counter) WindowOption {
+	return func(m *WindowMemory) {
+		m.counter = counter
+	}
 }
 
 // NewWindowMemory 创建滑动窗口记忆
 // windowSize: 窗口大小（保留的最大条目数）
-func NewWindowMemory(windowSize int) *WindowMemory {
+func NewWindowMemory(windowSize int, opts ...WindowOption) *WindowMemory {
 	if windowSize <= 0 {
 		windowSize = 10
 	}
-	return &WindowMemory{
+	m := &WindowMemory{
 		entries:    make([]coremem.Entry, 0, windowSize),
 		index:      make(map[string]int, windowSize),
 		windowSize: windowSize,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 func (m *WindowMemory) Save(_ context.Context, entry coremem.Entry) error {
@@ -59,9 +86,7 @@ func (m *WindowMemory) Save(_ context.Context, entry coremem.Entry) error {
 	m.entries = append(m.entries, entry)
 
 	// 滑动窗口：超出时移除最旧的
-	if len(m.entries) > m.windowSize {
-		m.entries = m.entries[len(m.entries)-m.windowSize:]
-	}
+	m.trim()
 
 	// 重建索引
 	m.rebuildIndex()
@@ -82,9 +107,7 @@ func (m *WindowMemory) SaveBatch(_ context.Context, entries []coremem.Entry) err
 	}
 
 	// 滑动窗口：批量添加后统一裁剪
-	if len(m.entries) > m.windowSize {
-		m.entries = m.entries[len(m.entries)-m.windowSize:]
-	}
+	m.trim()
 
 	// 重建索引（仅一次）
 	m.rebuildIndex()
@@ -128,6 +151,28 @@ func (m *WindowMemory) Delete(_ context.Context, id string) error {
 	return nil
 }
 
+// trim 按条目数和 Token 数裁剪最旧的条目（调用方需持有写锁）
+func (m *WindowMemory) trim() {
+	if len(m.entries) > m.windowSize {
+		m.entries = m.entries[len(m.entries)-m.windowSize:]
+	}
+	if m.maxTokens <= 0 {
+		return
+	}
+
+	// 从最新往前累加，保留不超过预算的后缀
+	counter := tokenizer.OrDefault(m.counter)
+	total, start := 0, len(m.entries)
+	for start > 0 {
+		total += counter.Count(m.entries[start-1].Content)
+		if total > m.maxTokens && start < len(m.entries) {
+			break
+		}
+		start--
+	}
+	m.entries = m.entries[start:]
+}
+
 // rebuildIndex 重建 ID 到索引的映射（调用方需持有写锁）
 func (m *WindowMemory) rebuildIndex() {
 	m.index = make(map[string]int, len(m.entries))
@@ -147,7 +192,13 @@ func (m *WindowMemory) Clear(_ context.Context) error {
 func (m *WindowMemory) Stats() coremem.MemoryStats {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return coremem.MemoryStats{EntryCount: len(m.entries)}
+
+	counter := tokenizer.OrDefault(m.counter)
+	tokens := 0
+	for _, e := range m.entries {
+		tokens += counter.Count(e.Content)
+	}
+	return coremem.MemoryStats{EntryCount: len(m.entries), TokenCount: tokens}
 }
 
 var _ coremem.Memory = (*WindowMemory)(nil)
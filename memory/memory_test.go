@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	coremem "github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/hexagon/llm/tokenizer"
+)
+
+// runeCounter 按字符数计数，便于断言
+type runeCounter struct{ *tokenizer.Approximate }
+
+func (runeCounter) Count(text string) int { return len([]rune(text)) }
+
+func TestWindowMemory_MaxTokens(t *testing.T) {
+	ctx := context.Background()
+	mem := NewWindowMemory(10, WithMaxTokens(10), WithTokenCounter(runeCounter{tokenizer.NewApproximate()}))
+
+	for _, content := range []string{"aaaa", "bbbb", "cccc"} {
+		if err := mem.Save(ctx, coremem.Entry{ID: content, Content: content}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	entries, _ := mem.Search(ctx, coremem.SearchQuery{})
+	if len(entries) != 2 || entries[0].ID != "bbbb" || entries[1].ID != "cccc" {
+		t.Fatalf("expected oldest entry trimmed, got %+v", entries)
+	}
+	if stats := mem.Stats(); stats.EntryCount != 2 || stats.TokenCount != 8 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// 单条超出预算时仍保留最新一条
+	if err := mem.SaveBatch(ctx, []coremem.Entry{{ID: "long", Content: "0123456789abc"}}); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	entries, _ = mem.Search(ctx, coremem.SearchQuery{})
+	if len(entries) != 1 || entries[0].ID != "long" {
+		t.Errorf("expected only the latest entry, got %+v", entries)
+	}
+	if got, _ := mem.Get(ctx, "long"); got == nil {
+		t.Error("expected index rebuilt after trim")
+	}
+}
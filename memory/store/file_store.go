@@ -135,10 +135,16 @@ func (s *FileStore) Put(ctx context.Context, namespace []string, key string, val
 
 	filePath := s.itemPath(namespace, key)
 
-	// 如果已存在，保留原始创建时间
-	if existing, err := s.readItemUnlocked(filePath); err == nil && existing != nil {
+	// 如果已存在，保留原始创建时间；已过期的视为不存在
+	var version int64
+	if existing, err := s.readItemUnlocked(filePath); err == nil && existing != nil && !existing.isExpired() {
 		item.CreatedAt = existing.CreatedAt
+		version = existing.Version
 	}
+	if err := options.checkVersion(version); err != nil {
+		return err
+	}
+	item.Version = version + 1
 
 	return s.writeItemUnlocked(filePath, item)
 }
@@ -336,6 +342,7 @@ type fileItem struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
+	Version   int64          `json:"version"`
 }
 
 func (fi *fileItem) isExpired() bool {
@@ -351,6 +358,7 @@ func (fi *fileItem) toItem() *Item {
 		CreatedAt: fi.CreatedAt,
 		UpdatedAt: fi.UpdatedAt,
 		ExpiresAt: fi.ExpiresAt,
+		Version:   fi.Version,
 	}
 
 	if fi.Namespace != nil {
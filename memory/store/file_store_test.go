@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -528,3 +529,27 @@ func TestFileStore_ListOrderDesc(t *testing.T) {
 		}
 	}
 }
+
+func TestFileStore_CAS(t *testing.T) {
+	s := newTestFileStore(t)
+	ctx := context.Background()
+	ns := []string{"agents", "a1"}
+
+	if err := s.Put(ctx, ns, "state", map[string]any{"v": "a"}, WithCAS(0)); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if err := s.Put(ctx, ns, "state", map[string]any{"v": "b"}, WithCAS(1)); err != nil {
+		t.Fatalf("版本一致时写入失败: %v", err)
+	}
+	if err := s.Put(ctx, ns, "state", map[string]any{"v": "c"}, WithCAS(1)); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("版本过期时期望 ErrVersionConflict, 实际 %v", err)
+	}
+
+	item, err := s.Get(ctx, ns, "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Value["v"] != "b" || item.Version != 2 {
+		t.Errorf("期望版本 2 的值 b, 实际 %+v", item)
+	}
+}
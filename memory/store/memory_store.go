@@ -101,10 +101,16 @@ func (s *InMemoryStore) Put(_ context.Context, namespace []string, key string, v
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// 如果已存在，保留原始创建时间
-	if existing, ok := s.items[storeKey]; ok {
+	// 如果已存在，保留原始创建时间；已过期的视为不存在
+	var version int64
+	if existing, ok := s.items[storeKey]; ok && !existing.IsExpired() {
 		item.CreatedAt = existing.CreatedAt
+		version = existing.Version
 	}
+	if err := options.checkVersion(version); err != nil {
+		return err
+	}
+	item.Version = version + 1
 
 	s.items[storeKey] = item
 	return nil
@@ -333,6 +339,7 @@ func copyItem(item *Item) *Item {
 		CreatedAt: item.CreatedAt,
 		UpdatedAt: item.UpdatedAt,
 		ExpiresAt: item.ExpiresAt,
+		Version:   item.Version,
 	}
 
 	// 拷贝命名空间
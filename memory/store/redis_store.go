@@ -119,35 +119,56 @@ func (s *RedisStore) Put(ctx context.Context, namespace []string, key string, va
 
 	redisKey := s.dataKey(namespace, key)
 
-	// 如果已存在，保留原始创建时间
-	existing, err := s.getItem(ctx, redisKey)
-	if err == nil && existing != nil {
-		item.CreatedAt = existing.CreatedAt
-	}
+	// WATCH 数据键：读取版本后键被其他客户端修改时事务失败，
+	// 启用 WithCAS 时返回冲突，否则基于新版本重试
+	put := func(tx *redis.Tx) error {
+		// 如果已存在，保留原始创建时间
+		var version int64
+		existing, err := readRedisItem(ctx, tx, redisKey)
+		if err != nil && options.cas {
+			return err
+		}
+		if err == nil && existing != nil {
+			item.CreatedAt = existing.CreatedAt
+			version = existing.Version
+		}
+		if err := options.checkVersion(version); err != nil {
+			return err
+		}
+		item.Version = version + 1
 
-	data, err := json.Marshal(item)
-	if err != nil {
-		return fmt.Errorf("序列化记忆失败: %w", err)
-	}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("序列化记忆失败: %w", err)
+		}
 
-	// 使用 Pipeline 批量操作：写入数据 + 更新命名空间索引
-	// 注意：Redis Pipeline 不是事务，部分失败时可能导致数据不一致
-	// 这里使用 TxPipeline 确保原子性
-	pipe := s.client.TxPipeline()
-	if ttl > 0 {
-		pipe.Set(ctx, redisKey, data, ttl)
-	} else {
-		pipe.Set(ctx, redisKey, data, 0)
+		// 使用事务批量操作：写入数据 + 更新命名空间索引
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, redisKey, data, max(ttl, 0))
+			pipe.SAdd(ctx, s.nsIndexKey(namespace), key)
+			return nil
+		})
+		return err
 	}
-	pipe.SAdd(ctx, s.nsIndexKey(namespace), key)
 
-	if _, err := pipe.Exec(ctx); err != nil {
-		return fmt.Errorf("Redis Pipeline 执行失败: %w", err)
+	for range redisPutRetries {
+		err := s.client.Watch(ctx, put, redisKey)
+		if !errors.Is(err, redis.TxFailedErr) {
+			if err != nil && !errors.Is(err, ErrVersionConflict) {
+				return fmt.Errorf("Redis 事务执行失败: %w", err)
+			}
+			return err
+		}
+		if options.cas {
+			return fmt.Errorf("%w: %s 被并发修改", ErrVersionConflict, redisKey)
+		}
 	}
-
-	return nil
+	return fmt.Errorf("Redis 事务执行失败: 并发写入重试 %d 次后仍冲突", redisPutRetries)
 }
 
+// redisPutRetries 未启用 WithCAS 时 Put 遇到并发修改的最大尝试次数
+const redisPutRetries = 10
+
 // Get 获取一条记忆
 func (s *RedisStore) Get(ctx context.Context, namespace []string, key string) (*Item, error) {
 	if err := ctx.Err(); err != nil {
@@ -350,6 +371,7 @@ type redisItem struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
+	Version   int64          `json:"version"`
 }
 
 func (ri *redisItem) toItem() *Item {
@@ -358,6 +380,7 @@ func (ri *redisItem) toItem() *Item {
 		CreatedAt: ri.CreatedAt,
 		UpdatedAt: ri.UpdatedAt,
 		ExpiresAt: ri.ExpiresAt,
+		Version:   ri.Version,
 	}
 
 	if ri.Namespace != nil {
@@ -396,7 +419,12 @@ func (s *RedisStore) nsIndexKey(namespace []string) string {
 
 // getItem 从 Redis 获取单条记忆
 func (s *RedisStore) getItem(ctx context.Context, redisKey string) (*redisItem, error) {
-	data, err := s.client.Get(ctx, redisKey).Bytes()
+	return readRedisItem(ctx, s.client, redisKey)
+}
+
+// readRedisItem 通过客户端或事务读取单条记忆，不存在时返回 nil, nil
+func readRedisItem(ctx context.Context, c redis.Cmdable, redisKey string) (*redisItem, error) {
+	data, err := c.Get(ctx, redisKey).Bytes()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
@@ -5,6 +5,7 @@
 //   - JSON 文档存储：每条记忆是一个 JSON 文档
 //   - 语义检索：可选的向量搜索能力
 //   - TTL 过期：记忆自动过期清理
+//   - 乐观并发：每条记忆带版本号，WithCAS 防止并发写入丢失更新
 //
 // 使用示例：
 //
@@ -29,10 +30,15 @@ package store
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
 
+// ErrVersionConflict 使用 WithCAS 写入时记忆的当前版本与期望版本不一致
+var ErrVersionConflict = errors.New("memory store: version conflict")
+
 // MemoryStore 跨会话持久记忆存储接口
 //
 // 对标 LangGraph Memory Store，提供命名空间隔离的 KV 存储。
@@ -47,7 +53,8 @@ type MemoryStore interface {
 	// value: JSON 文档（任意 map 数据）
 	// opts: 可选配置（TTL、索引字段等）
 	//
-	// 如果 key 已存在，则覆盖更新
+	// 如果 key 已存在，则覆盖更新；使用 WithCAS 时仅在版本未变化时更新，
+	// 否则返回 ErrVersionConflict
 	Put(ctx Context, namespace []string, key string, value map[string]any, opts ...PutOption) error
 
 	// Get 获取一条记忆
@@ -99,6 +106,9 @@ type Item struct {
 
 	// ExpiresAt 过期时间（nil 表示永不过期）
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Version 版本号，创建时为 1，每次 Put 加 1，用于 WithCAS 乐观并发控制
+	Version int64 `json:"version"`
 }
 
 // IsExpired 检查记忆是否已过期
@@ -142,6 +152,10 @@ type PutOption func(*putOptions)
 type putOptions struct {
 	ttl         time.Duration
 	indexFields []string
+
+	// cas 为 true 时仅在当前版本等于 expectedVersion 时写入
+	cas             bool
+	expectedVersion int64
 }
 
 // WithTTL 设置记忆的过期时间
@@ -166,6 +180,35 @@ func WithIndex(fields ...string) PutOption {
 	}
 }
 
+// WithCAS 启用乐观并发控制：仅当记忆的当前版本等于 expectedVersion 时写入，
+// 否则返回 ErrVersionConflict 且不修改记忆
+//
+// expectedVersion 为 0 表示仅在记忆不存在（或已过期）时创建。
+// 默认的 Put 为最后写入者胜出，多个并行分支写同一个键时会丢失更新；
+// 使用 WithCAS 时冲突方可以重新读取、合并后重试。
+//
+// 示例：
+//
+//	for {
+//	    item, _ := store.Get(ctx, ns, key)
+//	    var version int64
+//	    value := map[string]any{}
+//	    if item != nil {
+//	        version, value = item.Version, item.Value
+//	    }
+//	    value["count"] = toInt(value["count"]) + 1 // 在最新值上合并
+//	    err := store.Put(ctx, ns, key, value, store.WithCAS(version))
+//	    if !errors.Is(err, store.ErrVersionConflict) {
+//	        return err
+//	    }
+//	}
+func WithCAS(expectedVersion int64) PutOption {
+	return func(o *putOptions) {
+		o.cas = true
+		o.expectedVersion = expectedVersion
+	}
+}
+
 // ListOption 是 List 操作的可选配置
 type ListOption func(*listOptions)
 
@@ -235,6 +278,14 @@ func applyPutOptions(opts []PutOption) *putOptions {
 	return o
 }
 
+// checkVersion 按 WithCAS 检查记忆的当前版本，记忆不存在时 current 为 0
+func (o *putOptions) checkVersion(current int64) error {
+	if o.cas && current != o.expectedVersion {
+		return fmt.Errorf("%w: expected version %d, current version %d", ErrVersionConflict, o.expectedVersion, current)
+	}
+	return nil
+}
+
 // applyListOptions 应用 List 选项
 func applyListOptions(opts []ListOption) *listOptions {
 	o := &listOptions{
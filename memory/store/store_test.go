@@ -2,7 +2,9 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -461,3 +463,100 @@ func TestItem_IsExpired(t *testing.T) {
 
 // 确保 fmt 被使用
 var _ = fmt.Sprintf
+
+// TestInMemoryStore_Version 测试版本号随写入递增
+func TestInMemoryStore_Version(t *testing.T) {
+	s := NewInMemoryStore()
+	defer s.Close()
+	ctx := context.Background()
+	ns := []string{"agents", "a1"}
+
+	for want := int64(1); want <= 3; want++ {
+		if err := s.Put(ctx, ns, "state", map[string]any{"step": want}); err != nil {
+			t.Fatal(err)
+		}
+		item, _ := s.Get(ctx, ns, "state")
+		if item.Version != want {
+			t.Fatalf("期望版本 %d, 实际 %d", want, item.Version)
+		}
+	}
+
+	// 删除后重新创建从 1 开始
+	if err := s.Delete(ctx, ns, "state"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, ns, "state", map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+	if item, _ := s.Get(ctx, ns, "state"); item.Version != 1 {
+		t.Errorf("重新创建后期望版本 1, 实际 %d", item.Version)
+	}
+}
+
+// TestInMemoryStore_CAS 测试 WithCAS 的版本检查
+func TestInMemoryStore_CAS(t *testing.T) {
+	s := NewInMemoryStore()
+	defer s.Close()
+	ctx := context.Background()
+	ns := []string{"agents", "a1"}
+
+	// 期望版本 0 表示仅创建
+	if err := s.Put(ctx, ns, "state", map[string]any{"v": "a"}, WithCAS(0)); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if err := s.Put(ctx, ns, "state", map[string]any{"v": "b"}, WithCAS(0)); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("已存在时期望 ErrVersionConflict, 实际 %v", err)
+	}
+
+	// 过期版本被拒绝且不修改记忆
+	if err := s.Put(ctx, ns, "state", map[string]any{"v": "b"}, WithCAS(1)); err != nil {
+		t.Fatalf("版本一致时写入失败: %v", err)
+	}
+	if err := s.Put(ctx, ns, "state", map[string]any{"v": "c"}, WithCAS(1)); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("版本过期时期望 ErrVersionConflict, 实际 %v", err)
+	}
+	item, _ := s.Get(ctx, ns, "state")
+	if item.Value["v"] != "b" || item.Version != 2 {
+		t.Errorf("冲突的写入不应修改记忆: %+v", item)
+	}
+}
+
+// TestInMemoryStore_CASConcurrent 测试并发读取-合并-重试不丢失更新
+func TestInMemoryStore_CASConcurrent(t *testing.T) {
+	s := NewInMemoryStore()
+	defer s.Close()
+	ctx := context.Background()
+	ns := []string{"graph", "run1"}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				item, _ := s.Get(ctx, ns, "branches")
+				var version int64
+				value := map[string]any{}
+				if item != nil {
+					version, value = item.Version, item.Value
+				}
+				value[fmt.Sprintf("b%d", i)] = true
+				err := s.Put(ctx, ns, "branches", value, WithCAS(version))
+				if err == nil {
+					return
+				}
+				if !errors.Is(err, ErrVersionConflict) {
+					t.Errorf("写入失败: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	item, _ := s.Get(ctx, ns, "branches")
+	if len(item.Value) != writers || item.Version != writers {
+		t.Errorf("期望 %d 个分支和版本 %d, 实际 %d 个分支和版本 %d", writers, writers, len(item.Value), item.Version)
+	}
+}
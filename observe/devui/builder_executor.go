@@ -93,19 +93,17 @@ func (t *nodeTracker) getResults() []NodeResult {
 // Execute 执行图定义
 //
 // 将 GraphDefinition 构建为 graph.Graph[graph.MapState] 并使用同步 Run() 执行。
-// 节点 handler 内部记录执行结果并发送 SSE 事件，避免 Stream() 的并发竞态。
+// 节点 handler 内部记录执行结果，避免 Stream() 的并发竞态；
+// 执行进度由收集器作为 graph.ExecutionObserver 发送 SSE 事件。
 func (e *BuilderExecutor) Execute(ctx context.Context, def *GraphDefinition, initialState map[string]any) (*ExecutionResult, error) {
 	runID := "run-" + idgen.ShortID()
 	startTime := time.Now()
 
-	// 发送图开始事件
-	e.collector.EmitGraphStart(runID, def.ID, def.Name, initialState)
-
 	// 创建结果跟踪器
 	tracker := &nodeTracker{}
 
 	// 构建图
-	g, err := e.buildGraph(def, runID, tracker)
+	g, err := e.buildGraph(def, tracker)
 	if err != nil {
 		e.collector.EmitError(runID, "builder", "构建图失败: "+err.Error(), "")
 		return &ExecutionResult{
@@ -128,11 +126,11 @@ func (e *BuilderExecutor) Execute(ctx context.Context, def *GraphDefinition, ini
 	state["__graph_name"] = def.Name
 
 	// 使用同步 Run() 执行，避免 Stream() 的状态并发问题
-	finalState, err := g.Run(ctx, state)
+	// 图开始、节点状态和图结束事件由收集器作为执行观察者发送
+	finalState, err := g.Run(ctx, state, graph.WithObserver(e.collector), graph.WithRunID(runID))
 	if err != nil {
 		totalDuration := time.Since(startTime).Milliseconds()
 		e.collector.EmitError(runID, "builder", "执行图失败: "+err.Error(), "")
-		e.collector.EmitGraphEnd(runID, def.ID, map[string]any{"error": err.Error()}, totalDuration)
 		return &ExecutionResult{
 			RunID:       runID,
 			GraphID:     def.ID,
@@ -146,9 +144,6 @@ func (e *BuilderExecutor) Execute(ctx context.Context, def *GraphDefinition, ini
 	totalDuration := time.Since(startTime).Milliseconds()
 	nodeResults := tracker.getResults()
 
-	// 发送图结束事件
-	e.collector.EmitGraphEnd(runID, def.ID, map[string]any{"node_count": len(nodeResults)}, totalDuration)
-
 	// 清理内部状态键
 	resultState := make(map[string]any)
 	for k, v := range finalState {
@@ -176,7 +171,7 @@ func (e *BuilderExecutor) Execute(ctx context.Context, def *GraphDefinition, ini
 //   - agent/tool/llm: MVP 使用 echo handler
 //   - condition: 根据 config.condition 做状态键判断
 //   - parallel: 透传状态（MVP）
-func (e *BuilderExecutor) buildGraph(def *GraphDefinition, runID string, tracker *nodeTracker) (*graph.Graph[graph.MapState], error) {
+func (e *BuilderExecutor) buildGraph(def *GraphDefinition, tracker *nodeTracker) (*graph.Graph[graph.MapState], error) {
 	builder := graph.NewGraph[graph.MapState](def.Name)
 
 	// 构建节点映射，方便边处理时查找
@@ -191,7 +186,7 @@ func (e *BuilderExecutor) buildGraph(def *GraphDefinition, runID string, tracker
 		if node.Type == "start" || node.Type == "end" {
 			continue
 		}
-		handler := e.createNodeHandler(node, runID, def.ID, tracker)
+		handler := e.createNodeHandler(node, tracker)
 		builder.AddNode(node.ID, handler)
 	}
 
@@ -244,9 +239,9 @@ func (e *BuilderExecutor) buildGraph(def *GraphDefinition, runID string, tracker
 
 // createNodeHandler 为节点创建处理函数
 //
-// handler 内部直接记录执行结果到 tracker 并发送 SSE 事件，
+// handler 内部直接记录执行结果到 tracker，
 // 避免通过 Stream channel 传递状态导致的并发竞态。
-func (e *BuilderExecutor) createNodeHandler(node GraphNodeDef, runID, graphID string, tracker *nodeTracker) graph.NodeHandler[graph.MapState] {
+func (e *BuilderExecutor) createNodeHandler(node GraphNodeDef, tracker *nodeTracker) graph.NodeHandler[graph.MapState] {
 	nodeID := node.ID
 	nodeName := node.Name
 	nodeType := node.Type
@@ -255,10 +250,6 @@ func (e *BuilderExecutor) createNodeHandler(node GraphNodeDef, runID, graphID st
 	return func(ctx context.Context, state graph.MapState) (graph.MapState, error) {
 		startTime := time.Now()
 
-		// 发送节点开始事件
-		e.collector.EmitGraphNode(runID, graphID, nodeID, nodeName,
-			map[string]any{"type": nodeType, "status": "started"}, 0)
-
 		output := map[string]any{
 			"node_id":   nodeID,
 			"node_name": nodeName,
@@ -348,10 +339,6 @@ func (e *BuilderExecutor) createNodeHandler(node GraphNodeDef, runID, graphID st
 			Output:     output,
 		})
 
-		// 发送节点完成事件
-		e.collector.EmitGraphNode(runID, graphID, nodeID, nodeName,
-			map[string]any{"type": nodeType, "status": "completed"}, durationMs)
-
 		return state, nil
 	}
 }
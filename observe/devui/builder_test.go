@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/hexagon-codes/hexagon/orchestration/graph"
 )
 
 // TestGraphStore_CRUD 测试 GraphStore 的完整增删改查
@@ -420,6 +424,58 @@ func TestBuilderExecutor_Execute(t *testing.T) {
 	}
 }
 
+// TestBuilderExecutor_GraphEvents 测试执行时发送拓扑和节点状态事件
+func TestBuilderExecutor_GraphEvents(t *testing.T) {
+	collector := NewCollector(100)
+	executor := NewBuilderExecutor(collector)
+
+	def := &GraphDefinition{
+		ID:   "events-graph",
+		Name: "事件图",
+		Nodes: []GraphNodeDef{
+			{ID: "s", Type: "start"},
+			{ID: "a1", Name: "Agent 1", Type: "agent"},
+			{ID: "e", Type: "end"},
+		},
+		Edges: []GraphEdgeDef{
+			{ID: "e1", Source: "s", Target: "a1"},
+			{ID: "e2", Source: "a1", Target: "e"},
+		},
+	}
+
+	result, err := executor.Execute(context.Background(), def, nil)
+	if err != nil || result.Status != "completed" {
+		t.Fatalf("执行失败: %v, %+v", err, result)
+	}
+
+	var statuses []string
+	var topology graph.Topology
+	var endStatus any
+	for _, e := range collector.Events().GetAll() {
+		if e.Data["run_id"] != result.RunID {
+			continue
+		}
+		switch e.Type {
+		case EventGraphStart:
+			topology, _ = e.Data["topology"].(graph.Topology)
+		case EventGraphNode:
+			statuses = append(statuses, fmt.Sprintf("%v:%v", e.Data["node_id"], e.Data["status"]))
+		case EventGraphEnd:
+			endStatus = e.Data["status"]
+		}
+	}
+
+	if topology.EntryPoint != "a1" || len(topology.Nodes) == 0 {
+		t.Errorf("topology = %+v", topology)
+	}
+	if got := strings.Join(statuses, ","); got != "a1:running,a1:done" {
+		t.Errorf("节点状态 = %s", got)
+	}
+	if endStatus != "completed" {
+		t.Errorf("graph.end status = %v", endStatus)
+	}
+}
+
 // TestBuilderExecutor_InvalidGraph 测试执行无效图
 func TestBuilderExecutor_InvalidGraph(t *testing.T) {
 	collector := NewCollector(100)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hexagon-codes/hexagon/hooks"
 	"github.com/hexagon-codes/hexagon/observe/tracer"
+	"github.com/hexagon-codes/hexagon/orchestration/graph"
 	"github.com/hexagon-codes/toolkit/util/idgen"
 )
 
@@ -15,6 +16,7 @@ import (
 //
 // 收集器实现了 hooks.RunHook、hooks.ToolHook、hooks.LLMHook、hooks.RetrieverHook 接口，
 // 用于收集 Agent 执行过程中的各种事件，并广播给 SSE 订阅者。
+// 同时实现 graph.ExecutionObserver，通过 graph.WithObserver 接入图执行的拓扑和节点状态。
 //
 // 特性：
 //   - 实现所有 Hook 接口，自动收集事件
@@ -36,6 +38,9 @@ type Collector struct {
 	// enabled 是否启用
 	enabled bool
 
+	// graphRuns 进行中的图运行开始时间，runID -> time.Time
+	graphRuns sync.Map
+
 	// stats 统计信息
 	stats struct {
 		totalEvents   atomic.Int64
@@ -399,6 +404,59 @@ func (c *Collector) EmitGraphEnd(runID, graphID string, state map[string]any, du
 	ReleaseEvent(e)
 }
 
+// ============================================================================
+// 实现 graph.ExecutionObserver 接口
+// ============================================================================
+
+// OnGraphStart 图运行开始，发送携带节点拓扑的图开始事件
+func (c *Collector) OnGraphStart(ctx context.Context, runID string, topology graph.Topology) {
+	c.graphRuns.Store(runID, time.Now())
+
+	e := c.createEvent(EventGraphStart, "", "", "", "")
+	e.Data["run_id"] = runID
+	e.Data["graph_id"] = topology.Name
+	e.Data["graph_name"] = topology.Name
+	e.Data["topology"] = topology
+
+	c.emit(e)
+	ReleaseEvent(e)
+}
+
+// OnNodeStatus 节点状态变化，发送图节点事件
+func (c *Collector) OnNodeStatus(ctx context.Context, runID string, update graph.NodeStatusUpdate) {
+	e := c.createEvent(EventGraphNode, "", "", "", "")
+	e.Data["run_id"] = runID
+	e.Data["node_id"] = update.Node
+	e.Data["node_name"] = update.Node
+	e.Data["status"] = string(update.Status)
+	if update.Status == graph.NodeStatusDone || update.Status == graph.NodeStatusFailed {
+		e.Data["duration_ms"] = update.Duration.Milliseconds()
+	}
+	if update.Error != nil {
+		e.Data["error"] = update.Error.Error()
+	}
+
+	c.emit(e)
+	ReleaseEvent(e)
+}
+
+// OnGraphEnd 图运行结束，发送图结束事件
+func (c *Collector) OnGraphEnd(ctx context.Context, runID string, err error) {
+	e := c.createEvent(EventGraphEnd, "", "", "", "")
+	e.Data["run_id"] = runID
+	e.Data["status"] = "completed"
+	if err != nil {
+		e.Data["status"] = "failed"
+		e.Data["error"] = err.Error()
+	}
+	if start, ok := c.graphRuns.LoadAndDelete(runID); ok {
+		e.Data["duration_ms"] = time.Since(start.(time.Time)).Milliseconds()
+	}
+
+	c.emit(e)
+	ReleaseEvent(e)
+}
+
 // EmitStateChange 发送状态变更事件
 func (c *Collector) EmitStateChange(agentID, key string, oldValue, newValue any) {
 	e := c.createEvent(EventStateChange, "", "", agentID, "")
@@ -435,4 +493,6 @@ var (
 	_ hooks.ToolHook      = (*Collector)(nil)
 	_ hooks.LLMHook       = (*Collector)(nil)
 	_ hooks.RetrieverHook = (*Collector)(nil)
+
+	_ graph.ExecutionObserver = (*Collector)(nil)
 )
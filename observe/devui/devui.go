@@ -14,9 +14,10 @@ import (
 // DevUI 开发调试界面服务器
 //
 // DevUI 提供了一个 Web 界面用于实时查看 Agent 执行过程，包括：
-//   - 实时事件流（SSE 推送）
+//   - 实时事件流（SSE / WebSocket 推送）
 //   - REST API 查询历史事件和指标
 //   - Span 追踪可视化
+//   - 图执行可视化（节点状态随事件实时更新）
 //   - 指标仪表板
 //
 // 使用示例：
@@ -29,6 +30,9 @@ import (
 //	    hexagon.WithHooks(ui.HookManager()),
 //	)
 //
+//	// 图执行接入：在图面板中查看节点拓扑和执行进度
+//	result, err := g.Run(ctx, state, graph.WithObserver(ui.Collector()))
+//
 //	go ui.Start()
 //	// 访问 http://localhost:8080
 type DevUI struct {
@@ -38,6 +42,8 @@ type DevUI struct {
 	options       *Options
 	graphStore    *GraphStore
 	replayManager *ReplayManager
+	player        *Player
+	playerMu      sync.Mutex
 	running       bool
 	mu            sync.Mutex
 	startTime     time.Time
@@ -51,6 +57,10 @@ type Options struct {
 	// EnableSSE 是否启用 SSE 事件推送，默认 true
 	EnableSSE bool
 
+	// EnableWebSocket 是否启用 WebSocket 事件推送（/ws），默认 false
+	// 适用于会缓冲 SSE 的代理环境，可与 SSE 同时启用
+	EnableWebSocket bool
+
 	// EnableMetrics 是否启用指标展示，默认 true
 	EnableMetrics bool
 
@@ -72,6 +82,10 @@ type Options struct {
 
 	// WriteTimeout HTTP 写入超时
 	WriteTimeout time.Duration
+
+	// ReplayDir 事件回放文件所在目录，默认当前目录
+	// /api/replay 只能读取该目录下的文件
+	ReplayDir string
 }
 
 // DefaultOptions 返回默认配置
@@ -85,6 +99,7 @@ func DefaultOptions() *Options {
 		CORSEnabled:   true,
 		ReadTimeout:   30 * time.Second,
 		WriteTimeout:  30 * time.Second,
+		ReplayDir:     ".",
 	}
 }
 
@@ -105,6 +120,14 @@ func WithSSE(enabled bool) Option {
 	}
 }
 
+// WithWebSocket 设置是否启用 WebSocket 事件推送
+// 启用后仪表板优先使用 WebSocket，连接失败时回退到 SSE
+func WithWebSocket(enabled bool) Option {
+	return func(o *Options) {
+		o.EnableWebSocket = enabled
+	}
+}
+
 // WithMetrics 设置是否启用指标
 func WithMetrics(enabled bool) Option {
 	return func(o *Options) {
@@ -154,6 +177,13 @@ func WithTimeouts(read, write time.Duration) Option {
 	}
 }
 
+// WithReplayDir 设置事件回放文件目录
+func WithReplayDir(dir string) Option {
+	return func(o *Options) {
+		o.ReplayDir = dir
+	}
+}
+
 // New 创建 DevUI 实例
 func New(opts ...Option) *DevUI {
 	options := DefaultOptions()
@@ -243,6 +273,8 @@ func (d *DevUI) Stop(ctx context.Context) error {
 	}
 	d.mu.Unlock()
 
+	d.StopPlayback()
+
 	if d.server != nil {
 		if err := d.server.Shutdown(ctx); err != nil {
 			return fmt.Errorf("devui: shutdown error: %w", err)
@@ -331,6 +363,8 @@ func (d *DevUI) setupRoutes() *http.ServeMux {
 	mux.HandleFunc(prefix+"/builder/node-types", corsMiddleware(bHandler.handleNodeTypes))
 
 	// Replay API（调试回放）
+	mux.HandleFunc(prefix+"/replay", corsMiddleware(handler.handleReplay))
+	mux.HandleFunc(prefix+"/replay/control", corsMiddleware(handler.handleReplayControl))
 	mux.HandleFunc(prefix+"/replay/sessions", corsMiddleware(rHandler.handleSessions))
 	mux.HandleFunc(prefix+"/replay/sessions/", corsMiddleware(rHandler.handleSession))
 
@@ -339,6 +373,11 @@ func (d *DevUI) setupRoutes() *http.ServeMux {
 		mux.HandleFunc("/events", corsMiddleware(handler.handleSSE))
 	}
 
+	// WebSocket 事件流
+	if d.options.EnableWebSocket {
+		mux.HandleFunc("/ws", handler.handleWebSocket)
+	}
+
 	// 健康检查
 	mux.HandleFunc("/health", corsMiddleware(handler.handleHealth))
 
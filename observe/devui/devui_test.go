@@ -3,6 +3,7 @@ package devui
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -284,6 +285,83 @@ func TestHandler(t *testing.T) {
 	})
 }
 
+// TestHandleEventsFilter 测试服务端过滤与分页
+func TestHandleEventsFilter(t *testing.T) {
+	ui := New(WithMaxEvents(100))
+	h := newHandler(ui)
+	collector := ui.Collector()
+	ctx := context.Background()
+
+	for i := range 5 {
+		runID := fmt.Sprintf("run-%d", i%2)
+		_ = collector.OnStart(ctx, &hooks.RunStartEvent{RunID: runID, AgentID: "agent", Input: "hello"})
+		_ = collector.OnToolStart(ctx, &hooks.ToolStartEvent{
+			RunID:    runID,
+			ToolName: fmt.Sprintf("weather-%d", i),
+			ToolID:   "tool",
+			Input:    map[string]any{"city": "Paris"},
+		})
+	}
+
+	get := func(t *testing.T, target string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		h.handleEvents(w, req)
+		var resp response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		data, _ := resp.Data.(map[string]any)
+		return w.Code, data
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		total int
+	}{
+		{"type", "type=tool.call", 5},
+		{"multi type", "type=tool.call,agent.start", 10},
+		{"run id", "runID=run-0", 6},
+		{"run id and type", "type=tool.call&run_id=run-1", 2},
+		{"query", "q=WEATHER-3", 1},
+		{"since future", "since=" + time.Now().Add(time.Hour).Format(time.RFC3339), 0},
+		{"since past", fmt.Sprintf("since=%d", time.Now().Add(-time.Hour).UnixMilli()), 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, data := get(t, "/api/events?"+tt.query)
+			if code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", code)
+			}
+			if total := int(data["total"].(float64)); total != tt.total {
+				t.Errorf("expected total %d, got %d", tt.total, total)
+			}
+		})
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		_, data := get(t, "/api/events?type=tool.call&limit=2&offset=4")
+		events := data["events"].([]any)
+		if len(events) != 1 || data["has_more"].(bool) {
+			t.Errorf("expected last page with 1 event, got %d (has_more=%v)", len(events), data["has_more"])
+		}
+		// 从新到旧排序，最后一页是最早的事件
+		first := events[0].(map[string]any)["data"].(map[string]any)
+		if first["tool_name"] != "weather-0" {
+			t.Errorf("expected oldest tool event, got %v", first["tool_name"])
+		}
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		code, _ := get(t, "/api/events?since=yesterday")
+		if code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", code)
+		}
+	})
+}
+
 // TestAllHooks 测试所有 Hook 接口
 func TestAllHooks(t *testing.T) {
 	collector := NewCollector(100)
@@ -4,7 +4,7 @@
 // LLM 调用、工具执行、RAG 检索等信息。
 //
 // 特性：
-//   - 实时事件流推送（SSE）
+//   - 实时事件流推送（SSE / WebSocket）
 //   - Span 追踪可视化
 //   - 指标仪表板
 //   - 零侵入集成（利用现有 Hooks + Tracer）
@@ -26,6 +26,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/orchestration/graph"
 	"github.com/hexagon-codes/toolkit/util/poolx"
 )
 
@@ -233,14 +234,25 @@ type RetrieverEndData struct {
 	Duration  int64  `json:"duration_ms"` // 毫秒
 }
 
+// GraphStartData 图开始事件数据
+type GraphStartData struct {
+	RunID     string          `json:"run_id"`
+	GraphID   string          `json:"graph_id"`
+	GraphName string          `json:"graph_name"`
+	State     map[string]any  `json:"state,omitempty"`
+	Topology  *graph.Topology `json:"topology,omitempty"` // 通过 graph.WithObserver 接入时提供
+}
+
 // GraphNodeData 图节点事件数据
 type GraphNodeData struct {
 	RunID    string         `json:"run_id"`
 	GraphID  string         `json:"graph_id"`
 	NodeID   string         `json:"node_id"`
 	NodeName string         `json:"node_name"`
+	Status   string         `json:"status,omitempty"` // pending, running, done, failed
 	State    map[string]any `json:"state,omitempty"`
 	Duration int64          `json:"duration_ms,omitempty"` // 毫秒
+	Error    string         `json:"error,omitempty"`
 }
 
 // ErrorData 错误事件数据
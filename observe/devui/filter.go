@@ -0,0 +1,128 @@
+package devui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventFilter 服务端事件过滤条件
+//
+// 用于 /api/events 查询以及 SSE / WebSocket 事件流，
+// 所有条件之间为 AND 关系，空条件表示不过滤。
+type EventFilter struct {
+	// Types 事件类型（任一匹配即可）
+	Types []EventType
+
+	// RunID 运行 ID（匹配 Data["run_id"]）
+	RunID string
+
+	// Since 仅包含该时间及之后的事件
+	Since time.Time
+
+	// Until 仅包含该时间之前的事件
+	Until time.Time
+
+	// Query 在序列化后的事件内容中做子串匹配（不区分大小写）
+	Query string
+}
+
+// parseEventFilter 从查询参数解析过滤条件
+//
+// 支持的参数：
+//   - type: 事件类型，多个值可用逗号分隔或重复传参
+//   - runID / run_id: 运行 ID
+//   - since / until: RFC3339 时间或 Unix 毫秒时间戳
+//   - q: 子串搜索
+func parseEventFilter(query url.Values) (EventFilter, error) {
+	var f EventFilter
+
+	for _, v := range query["type"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				f.Types = append(f.Types, EventType(t))
+			}
+		}
+	}
+
+	f.RunID = query.Get("runID")
+	if f.RunID == "" {
+		f.RunID = query.Get("run_id")
+	}
+
+	var err error
+	if f.Since, err = parseFilterTime(query.Get("since")); err != nil {
+		return f, fmt.Errorf("invalid since: %w", err)
+	}
+	if f.Until, err = parseFilterTime(query.Get("until")); err != nil {
+		return f, fmt.Errorf("invalid until: %w", err)
+	}
+
+	f.Query = strings.ToLower(strings.TrimSpace(query.Get("q")))
+	return f, nil
+}
+
+// parseFilterTime 解析 RFC3339 时间或 Unix 毫秒时间戳
+func parseFilterTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// IsEmpty 是否没有任何过滤条件
+func (f EventFilter) IsEmpty() bool {
+	return len(f.Types) == 0 && f.RunID == "" && f.Since.IsZero() && f.Until.IsZero() && f.Query == ""
+}
+
+// Match 判断事件是否满足过滤条件
+func (f EventFilter) Match(e *Event) bool {
+	if e == nil {
+		return false
+	}
+
+	if len(f.Types) > 0 {
+		matched := false
+		for _, t := range f.Types {
+			if e.Type == t {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if f.RunID != "" {
+		runID, _ := e.Data["run_id"].(string)
+		if runID != f.RunID {
+			return false
+		}
+	}
+
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Timestamp.Before(f.Until) {
+		return false
+	}
+
+	if f.Query != "" {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return false
+		}
+		if !strings.Contains(strings.ToLower(string(payload)), f.Query) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -68,7 +68,9 @@ func writeError(w http.ResponseWriter, status int, message string) {
 }
 
 // handleEvents 获取事件列表
-// GET /api/events?limit=100&offset=0&type=agent.start
+// GET /api/events?limit=100&offset=0&type=agent.start&runID=run-1&since=<ts>&until=<ts>&q=weather
+//
+// 事件按从新到旧排序，total 为过滤后的匹配总数
 func (h *handler) handleEvents(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -84,24 +86,44 @@ func (h *handler) handleEvents(w http.ResponseWriter, r *http.Request) {
 			limit = n
 		}
 	}
+	offset := 0
+	if o := query.Get("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n > 0 {
+			offset = n
+		}
+	}
 
-	// 获取最近的事件
-	events := h.devUI.collector.Events().GetRecent(limit)
+	// 解析过滤条件
+	filter, err := parseEventFilter(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	// 过滤事件类型
-	if typeFilter := query.Get("type"); typeFilter != "" {
-		filtered := make([]*Event, 0, len(events))
-		for _, e := range events {
-			if string(e.Type) == typeFilter {
-				filtered = append(filtered, e)
-			}
+	// 从新到旧遍历缓冲区并过滤
+	buffer := h.devUI.collector.Events()
+	all := buffer.GetRecent(buffer.Size())
+	matched := make([]*Event, 0, len(all))
+	for _, e := range all {
+		if filter.Match(e) {
+			matched = append(matched, e)
 		}
-		events = filtered
+	}
+
+	// 分页
+	total := len(matched)
+	events := []*Event{}
+	if offset < total {
+		end := min(offset+limit, total)
+		events = matched[offset:end]
 	}
 
 	writeSuccess(w, map[string]any{
-		"events": events,
-		"total":  len(events),
+		"events":   events,
+		"total":    total,
+		"offset":   offset,
+		"limit":    limit,
+		"has_more": offset+len(events) < total,
 	})
 }
 
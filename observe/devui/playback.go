@@ -0,0 +1,457 @@
+package devui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNoPlayback 当前没有进行中的回放
+var ErrNoPlayback = errors.New("devui: no playback in progress")
+
+// maxEventLineSize 事件文件单行最大字节数
+const maxEventLineSize = 4 * 1024 * 1024
+
+// WriteEvents 将事件以 JSON Lines 格式写入 w（每行一个事件）
+//
+// 生成的文件可通过 ReadEvents 读取，或经由 /api/replay 回放。
+func WriteEvents(w io.Writer, events []*Event) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if e == nil {
+			continue
+		}
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("devui: encode event %s: %w", e.ID, err)
+		}
+	}
+	return nil
+}
+
+// ReadEvents 读取 JSON Lines 格式的事件，按时间戳升序返回
+func ReadEvents(r io.Reader) ([]*Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLineSize)
+
+	var events []*Event
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("devui: line %d: %w", line, err)
+		}
+		events = append(events, &e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("devui: read events: %w", err)
+	}
+
+	// 稳定排序，保留同一时刻事件的原始顺序
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events, nil
+}
+
+// ExportEvents 将当前缓冲区中的事件写入 w（JSON Lines 格式）
+func (d *DevUI) ExportEvents(w io.Writer) error {
+	return WriteEvents(w, d.collector.Events().GetAll())
+}
+
+// PlaybackStatus 回放状态
+type PlaybackStatus struct {
+	// File 回放的文件
+	File string `json:"file,omitempty"`
+
+	// Position 下一个待发送事件的索引
+	Position int `json:"position"`
+
+	// Total 事件总数
+	Total int `json:"total"`
+
+	// Speed 回放速度倍率
+	Speed float64 `json:"speed"`
+
+	// Paused 是否已暂停
+	Paused bool `json:"paused"`
+
+	// Running 是否仍在回放（播放完毕或停止后为 false）
+	Running bool `json:"running"`
+}
+
+// Player 事件回放器
+//
+// 按原始事件间隔（除以 speed）依次重新发送事件，支持暂停、继续、跳转和调速。
+// 跳转后的第一个事件立即发送。
+type Player struct {
+	events []*Event
+	emit   func(*Event)
+	file   string
+
+	mu      sync.Mutex
+	speed   float64
+	pos     int
+	paused  bool
+	running bool
+	fresh   bool // 为 true 时下一个事件不等待间隔
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPlayer 创建回放器
+//
+// 参数：
+//   - events: 按时间升序排列的事件
+//   - speed: 速度倍率，<= 0 时按 1 处理
+//   - emit: 事件发送函数
+func NewPlayer(events []*Event, speed float64, emit func(*Event)) *Player {
+	if speed <= 0 {
+		speed = 1
+	}
+	return &Player{
+		events: events,
+		emit:   emit,
+		speed:  speed,
+		fresh:  true,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start 在后台开始回放
+func (p *Player) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	p.mu.Lock()
+	p.cancel = cancel
+	p.running = true
+	p.mu.Unlock()
+
+	go p.run(ctx)
+}
+
+// run 回放主循环
+func (p *Player) run(ctx context.Context) {
+	defer close(p.done)
+	defer func() {
+		p.mu.Lock()
+		p.running = false
+		p.mu.Unlock()
+	}()
+
+	for {
+		p.mu.Lock()
+		if p.pos >= len(p.events) {
+			p.mu.Unlock()
+			return
+		}
+		if p.paused {
+			p.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.wake:
+			}
+			continue
+		}
+
+		pos := p.pos
+		var delay time.Duration
+		if !p.fresh && pos > 0 {
+			gap := p.events[pos].Timestamp.Sub(p.events[pos-1].Timestamp)
+			delay = max(time.Duration(float64(gap)/p.speed), 0)
+		}
+		p.mu.Unlock()
+
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-p.wake:
+				// 状态变化（暂停/跳转/调速），重新计算
+				timer.Stop()
+				continue
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		p.mu.Lock()
+		if p.paused || p.pos != pos {
+			p.mu.Unlock()
+			continue
+		}
+		e := p.events[pos]
+		p.pos++
+		p.fresh = false
+		p.mu.Unlock()
+
+		p.emit(e)
+	}
+}
+
+// notify 唤醒回放循环
+func (p *Player) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pause 暂停回放
+func (p *Player) Pause() {
+	p.mu.Lock()
+	p.paused = true
+	p.mu.Unlock()
+	p.notify()
+}
+
+// Resume 继续回放
+func (p *Player) Resume() {
+	p.mu.Lock()
+	p.paused = false
+	p.fresh = true
+	p.mu.Unlock()
+	p.notify()
+}
+
+// Seek 跳转到指定事件索引
+// 回放已结束时跳转不会重新开始播放
+func (p *Player) Seek(position int) error {
+	p.mu.Lock()
+	if position < 0 || position > len(p.events) {
+		p.mu.Unlock()
+		return fmt.Errorf("devui: seek position %d out of range [0, %d]", position, len(p.events))
+	}
+	p.pos = position
+	p.fresh = true
+	p.mu.Unlock()
+	p.notify()
+	return nil
+}
+
+// SetSpeed 调整回放速度
+func (p *Player) SetSpeed(speed float64) error {
+	if speed <= 0 {
+		return fmt.Errorf("devui: invalid speed %v", speed)
+	}
+	p.mu.Lock()
+	p.speed = speed
+	p.mu.Unlock()
+	p.notify()
+	return nil
+}
+
+// Stop 停止回放并等待回放协程退出
+func (p *Player) Stop() {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-p.done
+}
+
+// Done 回放结束（播放完毕或停止）时关闭
+func (p *Player) Done() <-chan struct{} {
+	return p.done
+}
+
+// Status 返回回放状态
+func (p *Player) Status() PlaybackStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PlaybackStatus{
+		File:     p.file,
+		Position: p.pos,
+		Total:    len(p.events),
+		Speed:    p.speed,
+		Paused:   p.paused,
+		Running:  p.running,
+	}
+}
+
+// StartPlayback 从事件文件开始回放，替换正在进行的回放
+//
+// file 是相对于 Options.ReplayDir 的路径，不允许跳出该目录。
+// 回放的事件会写入事件缓冲区并推送给 SSE / WebSocket 订阅者，
+// 事件数据中带有 "replayed": true 标记。
+func (d *DevUI) StartPlayback(file string, speed float64) (*Player, error) {
+	if !filepath.IsLocal(file) {
+		return nil, fmt.Errorf("devui: invalid replay file: %s", file)
+	}
+
+	f, err := os.Open(filepath.Join(d.options.ReplayDir, file))
+	if err != nil {
+		return nil, fmt.Errorf("devui: open replay file: %w", err)
+	}
+	defer f.Close()
+
+	events, err := ReadEvents(f)
+	if err != nil {
+		return nil, err
+	}
+
+	player := NewPlayer(events, speed, d.emitReplayed)
+	player.file = file
+
+	d.playerMu.Lock()
+	old := d.player
+	d.player = player
+	d.playerMu.Unlock()
+
+	if old != nil {
+		old.Stop()
+	}
+	player.Start(context.Background())
+	return player, nil
+}
+
+// Playback 返回当前回放器，没有回放时返回 nil
+func (d *DevUI) Playback() *Player {
+	d.playerMu.Lock()
+	defer d.playerMu.Unlock()
+	return d.player
+}
+
+// StopPlayback 停止当前回放
+func (d *DevUI) StopPlayback() {
+	d.playerMu.Lock()
+	player := d.player
+	d.player = nil
+	d.playerMu.Unlock()
+
+	if player != nil {
+		player.Stop()
+	}
+}
+
+// emitReplayed 将回放事件注入收集器
+func (d *DevUI) emitReplayed(e *Event) {
+	clone := e.Clone()
+	clone.Data["replayed"] = true
+	d.collector.emit(clone)
+}
+
+// handleReplay 开始回放或查询回放状态
+// POST /api/replay?file=session.jsonl&speed=2 - 开始回放
+// GET  /api/replay                           - 查询回放状态
+func (h *handler) handleReplay(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet:
+		if query.Get("file") == "" {
+			player := h.devUI.Playback()
+			if player == nil {
+				writeError(w, http.StatusNotFound, ErrNoPlayback.Error())
+				return
+			}
+			writeSuccess(w, player.Status())
+			return
+		}
+		fallthrough
+
+	case http.MethodPost:
+		file := query.Get("file")
+		if file == "" {
+			writeError(w, http.StatusBadRequest, "file required")
+			return
+		}
+		speed := 1.0
+		if s := query.Get("speed"); s != "" {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v <= 0 {
+				writeError(w, http.StatusBadRequest, "invalid speed")
+				return
+			}
+			speed = v
+		}
+
+		player, err := h.devUI.StartPlayback(file, speed)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeSuccess(w, player.Status())
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleReplayControl 控制当前回放
+// POST /api/replay/control?action=pause
+// POST /api/replay/control?action=resume
+// POST /api/replay/control?action=seek&position=10
+// POST /api/replay/control?action=speed&speed=4
+// POST /api/replay/control?action=stop
+func (h *handler) handleReplayControl(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	player := h.devUI.Playback()
+	if player == nil {
+		writeError(w, http.StatusNotFound, ErrNoPlayback.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	switch action := query.Get("action"); action {
+	case "pause":
+		player.Pause()
+	case "resume":
+		player.Resume()
+	case "seek":
+		position, err := strconv.Atoi(query.Get("position"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid position")
+			return
+		}
+		if err := player.Seek(position); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	case "speed":
+		speed, err := strconv.ParseFloat(query.Get("speed"), 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid speed")
+			return
+		}
+		if err := player.SetSpeed(speed); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	case "stop":
+		h.devUI.StopPlayback()
+	default:
+		writeError(w, http.StatusBadRequest, "unknown action: "+action)
+		return
+	}
+
+	writeSuccess(w, player.Status())
+}
@@ -0,0 +1,192 @@
+package devui
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func makeEvents(n int, gap time.Duration) []*Event {
+	base := time.Now().Add(-time.Hour)
+	events := make([]*Event, n)
+	for i := range events {
+		events[i] = &Event{
+			ID:        "evt-" + string(rune('a'+i)),
+			Type:      EventToolCall,
+			Timestamp: base.Add(time.Duration(i) * gap),
+			Data:      map[string]any{"run_id": "run-1", "index": i},
+		}
+	}
+	return events
+}
+
+func TestWriteReadEvents(t *testing.T) {
+	events := makeEvents(3, time.Second)
+
+	// 乱序写入，读取后应按时间排序
+	var buf bytes.Buffer
+	if err := WriteEvents(&buf, []*Event{events[2], events[0], events[1]}); err != nil {
+		t.Fatalf("WriteEvents failed: %v", err)
+	}
+
+	got, err := ReadEvents(&buf)
+	if err != nil {
+		t.Fatalf("ReadEvents failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(got))
+	}
+	for i, e := range got {
+		if e.ID != events[i].ID {
+			t.Errorf("event %d: expected %s, got %s", i, events[i].ID, e.ID)
+		}
+	}
+
+	if _, err := ReadEvents(bytes.NewBufferString("{not json}\n")); err == nil {
+		t.Error("expected error for invalid line")
+	}
+}
+
+type recorder struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (r *recorder) emit(e *Event) {
+	r.mu.Lock()
+	r.ids = append(r.ids, e.ID)
+	r.mu.Unlock()
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.ids)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPlayerSpeed(t *testing.T) {
+	// 原始间隔 1s，1000 倍速下约 1ms
+	rec := &recorder{}
+	p := NewPlayer(makeEvents(5, time.Second), 1000, rec.emit)
+	p.Start(t.Context())
+
+	select {
+	case <-p.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("playback did not finish")
+	}
+	if rec.count() != 5 {
+		t.Errorf("expected 5 events, got %d", rec.count())
+	}
+	if st := p.Status(); st.Running || st.Position != 5 {
+		t.Errorf("unexpected status: %+v", st)
+	}
+}
+
+func TestPlayerPauseSeek(t *testing.T) {
+	// 原始间隔 1h，正常速度下只会立即发送第一个事件
+	rec := &recorder{}
+	p := NewPlayer(makeEvents(4, time.Hour), 1, rec.emit)
+	p.Start(t.Context())
+	defer p.Stop()
+
+	waitFor(t, func() bool { return rec.count() == 1 })
+
+	p.Pause()
+	if err := p.Seek(3); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if rec.count() != 1 {
+		t.Fatalf("expected no events while paused, got %d", rec.count())
+	}
+
+	// 继续后跳转位置的事件立即发送
+	p.Resume()
+	waitFor(t, func() bool { return rec.count() == 2 })
+	<-p.Done()
+
+	rec.mu.Lock()
+	last := rec.ids[1]
+	rec.mu.Unlock()
+	if last != "evt-d" {
+		t.Errorf("expected seek target evt-d, got %s", last)
+	}
+
+	if err := p.Seek(10); err == nil {
+		t.Error("expected out of range error")
+	}
+	if err := p.SetSpeed(0); err == nil {
+		t.Error("expected invalid speed error")
+	}
+}
+
+func TestReplayHandler(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	if err := WriteEvents(&buf, makeEvents(3, time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "session.jsonl"), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := New(WithReplayDir(dir))
+	defer ui.StopPlayback()
+	mux := ui.setupRoutes()
+
+	do := func(method, target string) int {
+		req := httptest.NewRequest(method, target, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := do(http.MethodGet, "/api/replay"); code != http.StatusNotFound {
+		t.Errorf("expected 404 without playback, got %d", code)
+	}
+	if code := do(http.MethodPost, "/api/replay?file=../secret.jsonl"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for path escaping replay dir, got %d", code)
+	}
+	if code := do(http.MethodPost, "/api/replay?file=session.jsonl&speed=10"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+
+	<-ui.Playback().Done()
+	events := ui.Collector().Events().GetAll()
+	if len(events) != 3 {
+		t.Fatalf("expected 3 replayed events, got %d", len(events))
+	}
+	if events[0].Data["replayed"] != true {
+		t.Error("expected replayed marker")
+	}
+
+	if code := do(http.MethodPost, "/api/replay/control?action=seek&position=0"); code != http.StatusOK {
+		t.Errorf("expected 200 for seek, got %d", code)
+	}
+	if code := do(http.MethodPost, "/api/replay/control?action=rewind"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown action, got %d", code)
+	}
+	if code := do(http.MethodPost, "/api/replay/control?action=stop"); code != http.StatusOK {
+		t.Errorf("expected 200 for stop, got %d", code)
+	}
+	if ui.Playback() != nil {
+		t.Error("expected playback to be cleared after stop")
+	}
+}
@@ -15,7 +15,9 @@ import (
 var sseBufferPool = poolx.NewBufferPool(1024)
 
 // handleSSE 处理 SSE 事件流
-// GET /events
+// GET /events?type=tool.call&runID=run-1&q=weather
+//
+// 支持与 /api/events 相同的过滤参数（type/runID/since/until/q），仅推送匹配的事件。
 //
 // SSE 事件格式：
 //
@@ -32,6 +34,13 @@ func (h *handler) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 解析过滤条件，仅推送匹配的事件
+	filter, err := parseEventFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// 设置 SSE 响应头
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -68,6 +77,10 @@ func (h *handler) handleSSE(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+			if !filter.Match(event) {
+				continue
+			}
+
 			// 发送事件
 			if err := h.sendSSEEvent(w, string(event.Type), event); err != nil {
 				return
@@ -1,7 +1,7 @@
 /**
  * Hexagon Dev UI
  *
- * 实时调试界面，通过 SSE 接收事件并展示
+ * 实时调试界面，通过 WebSocket 或 SSE 接收事件并展示
  */
 
 // ============================================================================
@@ -12,9 +12,14 @@ const state = {
     events: [],
     selectedEvent: null,
     eventSource: null,
+    socket: null,
+    wsUnavailable: false,  // WebSocket 握手失败后回退到 SSE
     connected: false,
     paused: false,
     streamContent: {},  // LLM 流式内容聚合
+    graphRuns: {},      // 图运行：run_id -> { name, topology, statuses, path, status }
+    graphRunOrder: [],  // 图运行 ID（从新到旧）
+    selectedRunId: null,
     metrics: {
         totalEvents: 0,
         agentRuns: 0,
@@ -61,6 +66,12 @@ const elements = {
     streamModal: document.getElementById('streamModal'),
     streamContent: document.getElementById('streamContent'),
     closeStreamModal: document.getElementById('closeStreamModal'),
+    // 图执行面板
+    graphPanel: document.getElementById('graphPanel'),
+    graphTitle: document.getElementById('graphTitle'),
+    graphRunStatus: document.getElementById('graphRunStatus'),
+    graphRunSelect: document.getElementById('graphRunSelect'),
+    graphView: document.getElementById('graphView'),
     // 指标
     metricTotalEvents: document.getElementById('metricTotalEvents'),
     metricAgentRuns: document.getElementById('metricAgentRuns'),
@@ -72,10 +83,76 @@ const elements = {
 };
 
 // ============================================================================
-// SSE 连接
+// 事件连接（优先 WebSocket，不可用时回退 SSE）
 // ============================================================================
 
 function connect() {
+    if (state.wsUnavailable || !('WebSocket' in window)) {
+        connectSSE();
+        return;
+    }
+    connectWebSocket();
+}
+
+function connectWebSocket() {
+    if (state.socket) {
+        state.socket.close();
+    }
+
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const url = `${protocol}//${window.location.host}/ws`;
+    let opened = false;
+
+    console.log('Connecting to WebSocket:', url);
+
+    const socket = new WebSocket(url);
+    state.socket = socket;
+
+    socket.onopen = () => {
+        opened = true;
+        console.log('WebSocket connected');
+        state.connected = true;
+        updateConnectionStatus('connected', '已连接');
+    };
+
+    socket.onmessage = (msg) => {
+        let type = '';
+        try {
+            type = JSON.parse(msg.data).type;
+        } catch (err) {
+            console.error('Failed to parse message:', err, msg.data);
+            return;
+        }
+        if (type === 'connected' || type === 'heartbeat') {
+            return;
+        }
+        handleEvent({ type: type, data: msg.data });
+    };
+
+    socket.onclose = () => {
+        if (state.socket !== socket) return;
+        state.socket = null;
+        state.connected = false;
+
+        if (!opened) {
+            // 服务器未启用 WebSocket，回退到 SSE
+            console.log('WebSocket unavailable, falling back to SSE');
+            state.wsUnavailable = true;
+            connect();
+            return;
+        }
+
+        updateConnectionStatus('disconnected', '已断开');
+        setTimeout(() => {
+            if (!state.connected) {
+                console.log('Attempting to reconnect...');
+                connect();
+            }
+        }, 3000);
+    };
+}
+
+function connectSSE() {
     if (state.eventSource) {
         state.eventSource.close();
     }
@@ -103,7 +180,7 @@ function connect() {
         setTimeout(() => {
             if (!state.connected) {
                 console.log('Attempting to reconnect...');
-                connect();
+                connectSSE();
             }
         }, 3000);
     };
@@ -141,6 +218,11 @@ function handleEvent(e) {
             }
         }
 
+        // 更新图执行面板
+        if (event.type.startsWith('graph.')) {
+            updateGraphRun(event);
+        }
+
         // 添加到事件列表
         state.events.unshift(event);
 
@@ -233,6 +315,12 @@ function getEventTitle(event) {
             return `检索: ${data.query?.substring(0, 30)}...`;
         case 'retriever.end':
             return `找到 ${data.doc_count} 个文档`;
+        case 'graph.start':
+            return `图: ${data.graph_name || data.graph_id || data.run_id}`;
+        case 'graph.node':
+            return `${data.node_name || data.node_id}: ${GRAPH_STATUS_LABEL[data.status] || data.status || ''}`;
+        case 'graph.end':
+            return data.status === 'failed' ? `图失败: ${data.error || ''}` : `图完成 (${data.duration_ms ?? '-'}ms)`;
         case 'error':
             return data.message?.substring(0, 50) || '错误';
         default:
@@ -396,6 +484,10 @@ elements.clearBtn.addEventListener('click', () => {
     state.events = [];
     state.selectedEvent = null;
     state.streamContent = {};
+    state.graphRuns = {};
+    state.graphRunOrder = [];
+    state.selectedRunId = null;
+    elements.graphPanel.hidden = true;
     state.metrics = {
         totalEvents: 0,
         agentRuns: 0,
@@ -419,6 +511,245 @@ elements.closeStreamModal.addEventListener('click', () => {
     elements.streamModal.classList.remove('active');
 });
 
+elements.graphRunSelect.addEventListener('change', () => {
+    state.selectedRunId = elements.graphRunSelect.value;
+    renderGraph();
+});
+
+// ============================================================================
+// 图执行可视化
+// ============================================================================
+
+const GRAPH_STATUS_LABEL = {
+    pending: '等待',
+    running: '执行中',
+    done: '完成',
+    failed: '失败'
+};
+
+const GRAPH_RUN_STATUS_LABEL = {
+    running: '运行中',
+    completed: '已完成',
+    failed: '失败'
+};
+
+const GRAPH_LAYOUT = {
+    nodeWidth: 120,
+    nodeHeight: 32,
+    gapX: 40,
+    gapY: 56,
+    padding: 24
+};
+
+// 根据 graph.* 事件更新图运行状态
+// 只有带拓扑的 graph.start（通过 graph.WithObserver 接入）才会显示在面板中
+function updateGraphRun(event) {
+    const data = event.data || {};
+    const runId = data.run_id;
+    if (!runId) return;
+
+    if (event.type === 'graph.start') {
+        if (!data.topology) return;
+        const statuses = {};
+        (data.topology.nodes || []).forEach(n => {
+            statuses[n.id] = n.kind === 'start' ? 'done' : 'pending';
+        });
+        state.graphRuns[runId] = {
+            name: data.graph_name || data.topology.name || runId,
+            topology: data.topology,
+            statuses: statuses,
+            path: ['__start__'],
+            status: 'running',
+            startedAt: event.timestamp
+        };
+        state.graphRunOrder.unshift(runId);
+        // 只保留最近 20 次运行
+        state.graphRunOrder.splice(20).forEach(id => delete state.graphRuns[id]);
+        state.selectedRunId = runId;
+        renderGraphRunSelect();
+        renderGraph();
+        return;
+    }
+
+    const run = state.graphRuns[runId];
+    if (!run) return;
+
+    if (event.type === 'graph.node' && data.node_id) {
+        run.statuses[data.node_id] = data.status;
+        if (data.status === 'running') {
+            run.path.push(data.node_id);
+        }
+    } else if (event.type === 'graph.end') {
+        run.status = data.status || 'completed';
+        run.error = data.error;
+        if (run.status === 'completed') {
+            run.statuses['__end__'] = 'done';
+            run.path.push('__end__');
+        }
+        renderGraphRunSelect();
+    }
+
+    if (runId === state.selectedRunId) {
+        renderGraph();
+    }
+}
+
+function renderGraphRunSelect() {
+    elements.graphRunSelect.innerHTML = state.graphRunOrder.map(id => {
+        const run = state.graphRuns[id];
+        const label = `${run.name} · ${formatTime(run.startedAt)} · ${GRAPH_RUN_STATUS_LABEL[run.status] || run.status}`;
+        return `<option value="${escapeHtml(id)}" ${id === state.selectedRunId ? 'selected' : ''}>${escapeHtml(label)}</option>`;
+    }).join('');
+}
+
+// 计算节点布局：从开始节点按 BFS 分层，自上而下排列
+function layoutGraph(topology) {
+    const nodes = topology.nodes || [];
+    const edges = topology.edges || [];
+    const level = {};
+    const outgoing = {};
+    edges.forEach(e => {
+        (outgoing[e.from] = outgoing[e.from] || []).push(e.to);
+    });
+
+    const queue = ['__start__'];
+    level['__start__'] = 0;
+    while (queue.length > 0) {
+        const id = queue.shift();
+        (outgoing[id] || []).forEach(to => {
+            if (level[to] === undefined) {
+                level[to] = level[id] + 1;
+                queue.push(to);
+            }
+        });
+    }
+
+    // 结束节点和不可达节点放在最后一层
+    let maxLevel = 0;
+    Object.values(level).forEach(l => { maxLevel = Math.max(maxLevel, l); });
+    nodes.forEach(n => {
+        if (n.kind === 'end' || level[n.id] === undefined) {
+            level[n.id] = maxLevel + 1;
+        }
+    });
+
+    const layers = [];
+    nodes.forEach(n => {
+        (layers[level[n.id]] = layers[level[n.id]] || []).push(n);
+    });
+
+    const { nodeWidth, nodeHeight, gapX, gapY, padding } = GRAPH_LAYOUT;
+    const widest = Math.max(1, ...layers.map(l => (l || []).length));
+    const width = widest * nodeWidth + (widest - 1) * gapX + padding * 2;
+    const positions = {};
+    layers.forEach((layer, i) => {
+        if (!layer) return;
+        const layerWidth = layer.length * nodeWidth + (layer.length - 1) * gapX;
+        const startX = (width - layerWidth) / 2;
+        layer.forEach((n, j) => {
+            positions[n.id] = {
+                x: startX + j * (nodeWidth + gapX) + nodeWidth / 2,
+                y: padding + i * (nodeHeight + gapY) + nodeHeight / 2,
+                level: i
+            };
+        });
+    });
+
+    return {
+        positions: positions,
+        width: width,
+        height: layers.length * (nodeHeight + gapY) - gapY + padding * 2
+    };
+}
+
+function renderGraph() {
+    const run = state.graphRuns[state.selectedRunId];
+    if (!run) {
+        elements.graphPanel.hidden = true;
+        return;
+    }
+    elements.graphPanel.hidden = false;
+    elements.graphTitle.textContent = `📊 ${run.name}`;
+    elements.graphRunStatus.className = `graph-run-status ${run.status}`;
+    elements.graphRunStatus.textContent = run.error
+        ? `${GRAPH_RUN_STATUS_LABEL[run.status]}: ${run.error}`
+        : GRAPH_RUN_STATUS_LABEL[run.status] || run.status;
+
+    const { nodeWidth, nodeHeight } = GRAPH_LAYOUT;
+    const layout = layoutGraph(run.topology);
+    const pos = layout.positions;
+
+    // 实际经过的边
+    const taken = new Set();
+    for (let i = 1; i < run.path.length; i++) {
+        taken.add(`${run.path[i - 1]}->${run.path[i]}`);
+    }
+
+    let svg = `<svg width="${layout.width}" height="${layout.height}" xmlns="http://www.w3.org/2000/svg">
+        <defs>
+            <marker id="graphArrow" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="6" markerHeight="6" orient="auto-start-reverse">
+                <path d="M 0 0 L 10 5 L 0 10 z" fill="#6e7681"></path>
+            </marker>
+        </defs>`;
+
+    (run.topology.edges || []).forEach(e => {
+        const from = pos[e.from];
+        const to = pos[e.to];
+        if (!from || !to) return;
+
+        const classes = ['graph-edge'];
+        if (e.conditional) classes.push('conditional');
+        if (taken.has(`${e.from}->${e.to}`)) classes.push('taken');
+
+        let d;
+        let labelX;
+        let labelY;
+        if (to.level > from.level) {
+            const x1 = from.x;
+            const y1 = from.y + nodeHeight / 2;
+            const x2 = to.x;
+            const y2 = to.y - nodeHeight / 2;
+            const midY = (y1 + y2) / 2;
+            d = `M ${x1} ${y1} C ${x1} ${midY}, ${x2} ${midY}, ${x2} ${y2}`;
+            labelX = (x1 + x2) / 2;
+            labelY = midY - 4;
+        } else {
+            // 回边（循环）：从右侧绕回
+            const x1 = from.x + nodeWidth / 2;
+            const x2 = to.x + nodeWidth / 2;
+            const bend = Math.max(x1, x2) + 40;
+            d = `M ${x1} ${from.y} C ${bend} ${from.y}, ${bend} ${to.y}, ${x2} ${to.y}`;
+            labelX = bend - 10;
+            labelY = (from.y + to.y) / 2;
+        }
+        svg += `<path class="${classes.join(' ')}" d="${d}" marker-end="url(#graphArrow)"></path>`;
+        if (e.label) {
+            svg += `<text class="graph-edge-label" x="${labelX}" y="${labelY}">${escapeHtml(e.label)}</text>`;
+        }
+    });
+
+    (run.topology.nodes || []).forEach(n => {
+        const p = pos[n.id];
+        if (!p) return;
+        const status = run.statuses[n.id] || 'pending';
+        let shape;
+        if (n.kind === 'start' || n.kind === 'end') {
+            shape = `<circle cx="${p.x}" cy="${p.y}" r="${nodeHeight / 2}"></circle>`;
+        } else {
+            shape = `<rect x="${p.x - nodeWidth / 2}" y="${p.y - nodeHeight / 2}" width="${nodeWidth}" height="${nodeHeight}" rx="6"></rect>`;
+        }
+        const label = n.label.length > 14 ? n.label.substring(0, 13) + '…' : n.label;
+        svg += `<g class="graph-node ${status}">
+            <title>${escapeHtml(n.id)} (${GRAPH_STATUS_LABEL[status] || status})</title>
+            ${shape}
+            <text x="${p.x}" y="${p.y}">${escapeHtml(n.kind === 'node' ? label : label.substring(0, 2))}</text>
+        </g>`;
+    });
+
+    svg += '</svg>';
+    elements.graphView.innerHTML = svg;
+}
+
 // ============================================================================
 // 工具函数
 // ============================================================================
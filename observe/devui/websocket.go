@@ -0,0 +1,123 @@
+package devui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// wsWriteTimeout WebSocket 单条消息写超时
+// 防止慢客户端长期占用写协程
+const wsWriteTimeout = 10 * time.Second
+
+// handleWebSocket 处理 WebSocket 事件流
+// GET /ws?type=tool.call&runID=run-1&q=weather
+//
+// 过滤参数与 /api/events 相同，仅推送匹配的事件。
+// 每条消息是一个 JSON 文本帧，内容与 SSE data 字段完全一致：
+//
+//	{"id":"evt-1","type":"agent.start","data":{...}}
+//
+// 连接建立后首先发送 {"type":"connected",...}，之后每 30 秒发送一次
+// {"type":"heartbeat"}。客户端断开时读协程退出并取消订阅，不会泄漏协程。
+func (h *handler) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	server := websocket.Server{
+		Handshake: h.wsHandshake,
+		Handler:   h.serveWebSocket,
+	}
+	server.ServeHTTP(w, r)
+}
+
+// wsHandshake 校验 WebSocket 握手
+// 启用 CORS 时接受任意来源，否则要求同源
+func (h *handler) wsHandshake(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" || h.devUI.options.CORSEnabled {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if u.Host != r.Host {
+		return fmt.Errorf("devui: cross-origin websocket rejected: %s", origin)
+	}
+	return nil
+}
+
+// serveWebSocket 推送事件直到客户端断开或服务器关闭
+func (h *handler) serveWebSocket(ws *websocket.Conn) {
+	defer ws.Close()
+
+	// http.Server 的读写超时会延续到被劫持的连接上，长连接需要清除
+	_ = ws.SetDeadline(time.Time{})
+
+	ctx, cancel := context.WithCancel(ws.Request().Context())
+	defer cancel()
+
+	filter, err := parseEventFilter(ws.Request().URL.Query())
+	if err != nil {
+		_ = h.sendWSMessage(ws, map[string]any{"type": "error", "error": err.Error()})
+		return
+	}
+
+	eventCh, unsubscribe := h.devUI.collector.Subscribe()
+	defer unsubscribe()
+
+	// 读协程：仅用于感知客户端断开（客户端消息被忽略）
+	go func() {
+		defer cancel()
+		var discard string
+		for {
+			if err := websocket.Message.Receive(ws, &discard); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := h.sendWSMessage(ws, map[string]any{
+		"type":    "connected",
+		"message": "Connected to Hexagon Dev UI",
+		"time":    time.Now().Format(time.RFC3339),
+	}); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-eventCh:
+			if !ok {
+				return
+			}
+			if !filter.Match(event) {
+				continue
+			}
+			if err := h.sendWSMessage(ws, event); err != nil {
+				return
+			}
+
+		case <-heartbeat.C:
+			if err := h.sendWSMessage(ws, map[string]any{"type": "heartbeat"}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendWSMessage 发送 JSON 文本帧
+func (h *handler) sendWSMessage(ws *websocket.Conn, v any) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(ws, v)
+}
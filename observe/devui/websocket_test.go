@@ -0,0 +1,100 @@
+package devui
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/hooks"
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocketStream(t *testing.T) {
+	ui := New(WithWebSocket(true))
+	srv := httptest.NewServer(ui.setupRoutes())
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	ws, err := websocket.Dial(wsURL, "", srv.URL)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var hello map[string]any
+	if err := websocket.JSON.Receive(ws, &hello); err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if hello["type"] != "connected" {
+		t.Fatalf("expected connected message, got %v", hello)
+	}
+
+	_ = ui.Collector().OnStart(context.Background(), &hooks.RunStartEvent{
+		RunID:   "run-1",
+		AgentID: "agent-1",
+		Input:   "hello",
+	})
+
+	var event Event
+	if err := websocket.JSON.Receive(ws, &event); err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if event.Type != EventAgentStart || event.Data["run_id"] != "run-1" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	// 客户端断开后服务器应取消订阅
+	ws.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for ui.Collector().SubscriberCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber was not released after disconnect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebSocketFilter(t *testing.T) {
+	ui := New(WithWebSocket(true))
+	srv := httptest.NewServer(ui.setupRoutes())
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws?type=agent.start&runID=run-2"
+	ws, err := websocket.Dial(wsURL, "", srv.URL)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer ws.Close()
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var hello map[string]any
+	if err := websocket.JSON.Receive(ws, &hello); err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+
+	ctx := context.Background()
+	_ = ui.Collector().OnStart(ctx, &hooks.RunStartEvent{RunID: "run-1", AgentID: "agent-1"})
+	_ = ui.Collector().OnToolStart(ctx, &hooks.ToolStartEvent{RunID: "run-2", ToolName: "search"})
+	_ = ui.Collector().OnStart(ctx, &hooks.RunStartEvent{RunID: "run-2", AgentID: "agent-2"})
+
+	var event Event
+	if err := websocket.JSON.Receive(ws, &event); err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if event.Type != EventAgentStart || event.Data["run_id"] != "run-2" {
+		t.Errorf("expected only the matching event, got %+v", event)
+	}
+}
+
+func TestWebSocketDisabledByDefault(t *testing.T) {
+	ui := New()
+	srv := httptest.NewServer(ui.setupRoutes())
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	if _, err := websocket.Dial(wsURL, "", srv.URL); err == nil {
+		t.Error("expected dial to fail when websocket is disabled")
+	}
+}
@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// ============== slog 适配 ==============
+
+// SlogLogger 基于标准库 *slog.Logger 的 Logger 实现
+//
+// 用于将框架日志接入调用方已有的 slog 处理链，
+// *Context 方法会自动附加 context 中的 run_id、agent_id 等关联字段。
+type SlogLogger struct {
+	inner *slog.Logger
+}
+
+// 确保实现了 Logger 接口
+var _ Logger = (*SlogLogger)(nil)
+
+// FromSlog 将 *slog.Logger 包装为 Logger
+// l 为 nil 时返回丢弃所有日志的 Logger
+func FromSlog(l *slog.Logger) *SlogLogger {
+	if l == nil {
+		l = slog.New(slog.DiscardHandler)
+	}
+	return &SlogLogger{inner: l}
+}
+
+// Nop 返回丢弃所有日志的 Logger
+func Nop() Logger {
+	return FromSlog(nil)
+}
+
+// Debug 记录调试日志
+func (l *SlogLogger) Debug(msg string, args ...any) {
+	l.inner.Debug(msg, args...)
+}
+
+// Info 记录信息日志
+func (l *SlogLogger) Info(msg string, args ...any) {
+	l.inner.Info(msg, args...)
+}
+
+// Warn 记录警告日志
+func (l *SlogLogger) Warn(msg string, args ...any) {
+	l.inner.Warn(msg, args...)
+}
+
+// Error 记录错误日志
+func (l *SlogLogger) Error(msg string, args ...any) {
+	l.inner.Error(msg, args...)
+}
+
+// DebugContext 记录带 context 的调试日志
+func (l *SlogLogger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.inner.DebugContext(ctx, msg, appendContextAttrs(ctx, args)...)
+}
+
+// InfoContext 记录带 context 的信息日志
+func (l *SlogLogger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.inner.InfoContext(ctx, msg, appendContextAttrs(ctx, args)...)
+}
+
+// WarnContext 记录带 context 的警告日志
+func (l *SlogLogger) WarnContext(ctx context.Context, msg string, args ...any) {
+	l.inner.WarnContext(ctx, msg, appendContextAttrs(ctx, args)...)
+}
+
+// ErrorContext 记录带 context 的错误日志
+func (l *SlogLogger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	l.inner.ErrorContext(ctx, msg, appendContextAttrs(ctx, args)...)
+}
+
+// With 创建带有额外字段的子 Logger
+func (l *SlogLogger) With(args ...any) Logger {
+	return &SlogLogger{inner: l.inner.With(args...)}
+}
+
+// WithContext 从 context 中提取上下文信息创建子 Logger
+func (l *SlogLogger) WithContext(ctx context.Context) Logger {
+	attrs := extractContextAttrs(ctx)
+	if len(attrs) == 0 {
+		return l
+	}
+	return l.With(attrs...)
+}
+
+// SetLevel 对 slog 适配器无效
+// 日志级别由调用方的 slog.Handler 控制
+func (l *SlogLogger) SetLevel(string) {}
+
+// Slog 返回底层的 *slog.Logger
+func (l *SlogLogger) Slog() *slog.Logger {
+	return l.inner
+}
+
+// ============== 框架内部 Logger ==============
+
+// loggerHolder 包装 Logger 以便存入 atomic.Pointer
+type loggerHolder struct {
+	l Logger
+}
+
+// framework 框架内部使用的 Logger，默认丢弃所有日志
+var framework atomic.Pointer[loggerHolder]
+
+func init() {
+	framework.Store(&loggerHolder{l: Nop()})
+}
+
+// SetFramework 设置框架内部使用的 Logger（并发安全）
+//
+// 框架在 Agent 运行、工具调用、RAG 检索等边界输出结构化日志，
+// 统一携带 run_id、agent_id、tool_name、duration 等字段。
+// 传入 nil 恢复为默认的静默 Logger。
+func SetFramework(l Logger) {
+	if l == nil {
+		l = Nop()
+	}
+	framework.Store(&loggerHolder{l: l})
+}
+
+// Framework 返回框架内部使用的 Logger
+func Framework() Logger {
+	return framework.Load().l
+}
+
+type loggerKey struct{}
+
+// ContextWithLogger 将 Logger 添加到 context
+// 框架优先使用 context 中的 Logger，便于按请求定制日志输出
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext 返回 context 中的 Logger，不存在时返回框架 Logger
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(Logger); ok && l != nil {
+			return l
+		}
+	}
+	return Framework()
+}
+
+// ============== 通用属性 ==============
+
+// RunID 创建 run_id 属性
+func RunID(id string) slog.Attr {
+	return slog.String("run_id", id)
+}
+
+// Elapsed 创建 duration 属性
+func Elapsed(d time.Duration) slog.Attr {
+	return slog.Duration("duration", d)
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestFromSlogContextAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	l := FromSlog(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := ContextWithRunID(context.Background(), "run-1")
+	ctx = ContextWithAgentID(ctx, "agent-1")
+	l.InfoContext(ctx, "hello", ToolName("search"))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid log output %q: %v", buf.String(), err)
+	}
+	for key, want := range map[string]string{"msg": "hello", "run_id": "run-1", "agent_id": "agent-1", "tool_name": "search"} {
+		if record[key] != want {
+			t.Errorf("expected %s=%q, got %v", key, want, record[key])
+		}
+	}
+}
+
+func TestFrameworkLogger(t *testing.T) {
+	t.Cleanup(func() { SetFramework(nil) })
+
+	// 默认静默
+	if _, ok := Framework().(*SlogLogger); !ok {
+		t.Fatalf("expected default framework logger to be a nop SlogLogger, got %T", Framework())
+	}
+
+	var global, scoped bytes.Buffer
+	SetFramework(FromSlog(slog.New(slog.NewTextHandler(&global, nil))))
+
+	FromContext(context.Background()).Info("global")
+	if !bytes.Contains(global.Bytes(), []byte("global")) {
+		t.Errorf("expected framework logger output, got %q", global.String())
+	}
+
+	// context 中的 Logger 优先
+	ctx := ContextWithLogger(context.Background(), FromSlog(slog.New(slog.NewTextHandler(&scoped, nil))))
+	FromContext(ctx).Info("scoped")
+	if !bytes.Contains(scoped.Bytes(), []byte("scoped")) || bytes.Contains(global.Bytes(), []byte("scoped")) {
+		t.Errorf("expected context logger to take precedence")
+	}
+
+	SetFramework(nil)
+	FromContext(context.Background()).Info("dropped")
+	if bytes.Contains(global.Bytes(), []byte("dropped")) {
+		t.Error("expected logs to be discarded after reset")
+	}
+}
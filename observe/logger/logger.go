@@ -1,7 +1,8 @@
 // Package logger 提供 Hexagon AI Agent 框架的日志工具
 //
 // Logger 封装了 toolkit/util/logger，并添加了 Agent 相关的上下文字段支持。
-// 支持自动从 context 中提取 agent_id, session_id, trace_id, span_id 等字段。
+// 也可通过 FromSlog 接入标准库 *slog.Logger；框架内部日志见 SetFramework。
+// 支持自动从 context 中提取 run_id, agent_id, session_id, trace_id, span_id 等字段。
 package logger
 
 import (
@@ -103,22 +104,22 @@ func (l *AgentLogger) Error(msg string, args ...any) {
 
 // DebugContext 记录带 context 的调试日志
 func (l *AgentLogger) DebugContext(ctx context.Context, msg string, args ...any) {
-	l.inner.DebugContext(ctx, msg, l.appendContextAttrs(ctx, args)...)
+	l.inner.DebugContext(ctx, msg, appendContextAttrs(ctx, args)...)
 }
 
 // InfoContext 记录带 context 的信息日志
 func (l *AgentLogger) InfoContext(ctx context.Context, msg string, args ...any) {
-	l.inner.InfoContext(ctx, msg, l.appendContextAttrs(ctx, args)...)
+	l.inner.InfoContext(ctx, msg, appendContextAttrs(ctx, args)...)
 }
 
 // WarnContext 记录带 context 的警告日志
 func (l *AgentLogger) WarnContext(ctx context.Context, msg string, args ...any) {
-	l.inner.WarnContext(ctx, msg, l.appendContextAttrs(ctx, args)...)
+	l.inner.WarnContext(ctx, msg, appendContextAttrs(ctx, args)...)
 }
 
 // ErrorContext 记录带 context 的错误日志
 func (l *AgentLogger) ErrorContext(ctx context.Context, msg string, args ...any) {
-	l.inner.ErrorContext(ctx, msg, l.appendContextAttrs(ctx, args)...)
+	l.inner.ErrorContext(ctx, msg, appendContextAttrs(ctx, args)...)
 }
 
 // With 创建带有额外字段的子 Logger
@@ -128,7 +129,7 @@ func (l *AgentLogger) With(args ...any) Logger {
 
 // WithContext 从 context 中提取上下文信息创建子 Logger
 func (l *AgentLogger) WithContext(ctx context.Context) Logger {
-	attrs := l.extractContextAttrs(ctx)
+	attrs := extractContextAttrs(ctx)
 	if len(attrs) == 0 {
 		return l
 	}
@@ -151,9 +152,23 @@ func (l *AgentLogger) Close() error {
 }
 
 // extractContextAttrs 从 context 中提取 Agent 相关的属性
-func (l *AgentLogger) extractContextAttrs(ctx context.Context) []any {
+func extractContextAttrs(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+
 	var attrs []any
 
+	// 提取 Run ID
+	if runID := RunIDFromContext(ctx); runID != "" {
+		attrs = append(attrs, "run_id", runID)
+	}
+
+	// 提取父 Run ID（嵌套运行，如团队成员、Swarm 交接）
+	if parentRunID := ParentRunIDFromContext(ctx); parentRunID != "" {
+		attrs = append(attrs, "parent_run_id", parentRunID)
+	}
+
 	// 提取 Agent ID
 	if agentID := AgentIDFromContext(ctx); agentID != "" {
 		attrs = append(attrs, "agent_id", agentID)
@@ -183,8 +198,8 @@ func (l *AgentLogger) extractContextAttrs(ctx context.Context) []any {
 }
 
 // appendContextAttrs 将 context 属性附加到参数中
-func (l *AgentLogger) appendContextAttrs(ctx context.Context, args []any) []any {
-	attrs := l.extractContextAttrs(ctx)
+func appendContextAttrs(ctx context.Context, args []any) []any {
+	attrs := extractContextAttrs(ctx)
 	if len(attrs) == 0 {
 		return args
 	}
@@ -194,13 +209,25 @@ func (l *AgentLogger) appendContextAttrs(ctx context.Context, args []any) []any
 // ============== Context keys ==============
 
 type (
-	agentIDKey   struct{}
-	sessionIDKey struct{}
-	traceIDKey   struct{}
-	spanIDKey    struct{}
-	componentKey struct{}
+	runIDKey       struct{}
+	parentRunIDKey struct{}
+	agentIDKey     struct{}
+	sessionIDKey   struct{}
+	traceIDKey     struct{}
+	spanIDKey      struct{}
+	componentKey   struct{}
 )
 
+// ContextWithRunID 将 Run ID 添加到 context
+func ContextWithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+// ContextWithParentRunID 将父 Run ID 添加到 context
+func ContextWithParentRunID(ctx context.Context, parentRunID string) context.Context {
+	return context.WithValue(ctx, parentRunIDKey{}, parentRunID)
+}
+
 // ContextWithAgentID 将 Agent ID 添加到 context
 func ContextWithAgentID(ctx context.Context, agentID string) context.Context {
 	return context.WithValue(ctx, agentIDKey{}, agentID)
@@ -226,6 +253,22 @@ func ContextWithComponent(ctx context.Context, component string) context.Context
 	return context.WithValue(ctx, componentKey{}, component)
 }
 
+// RunIDFromContext 从 context 中获取 Run ID
+func RunIDFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(runIDKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// ParentRunIDFromContext 从 context 中获取父 Run ID
+func ParentRunIDFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(parentRunIDKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
 // AgentIDFromContext 从 context 中获取 Agent ID
 func AgentIDFromContext(ctx context.Context) string {
 	if v, ok := ctx.Value(agentIDKey{}).(string); ok {
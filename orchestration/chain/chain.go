@@ -16,9 +16,12 @@ package chain
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/internal/pool"
 	"github.com/hexagon-codes/hexagon/stream"
 )
 
@@ -95,7 +98,115 @@ func (b *ChainBuilder[I, O]) PipeFunc(name string, fn func(ctx context.Context,
 	return b
 }
 
+// Tap 添加副作用步骤
+//
+// fn 用于日志、指标、事件等副作用，原始输入会原样传给下一步；
+// fn 返回错误时链中止。Tap 步骤与普通步骤一样会被中间件包装。
+func (b *ChainBuilder[I, O]) Tap(name string, fn func(ctx context.Context, input any) error) *ChainBuilder[I, O] {
+	if b.err != nil {
+		return b
+	}
+	if fn == nil {
+		b.err = fmt.Errorf("tap %s: function cannot be nil", name)
+		return b
+	}
+
+	b.chain.steps = append(b.chain.steps, step{
+		name: name,
+		handler: func(ctx context.Context, input any) (any, error) {
+			if err := fn(ctx, input); err != nil {
+				return nil, err
+			}
+			return input, nil
+		},
+	})
+	return b
+}
+
+// Fork 添加并行分支步骤
+//
+// 各分支以当前数据为输入并发执行，结果以 map[string]any（分支名 -> 输出）作为下一步的输入，
+// 通常紧跟 Join 合并。任一分支失败（包括 panic）时取消其余分支并返回该错误。
+// 中间件包装整个 Fork 步骤，而不是单个分支。branches 在调用时被复制，之后修改不影响链。
+func (b *ChainBuilder[I, O]) Fork(branches map[string]StepFunc) *ChainBuilder[I, O] {
+	if b.err != nil {
+		return b
+	}
+	if len(branches) == 0 {
+		b.err = fmt.Errorf("fork must have at least one branch")
+		return b
+	}
+
+	names := make([]string, 0, len(branches))
+	for name, fn := range branches {
+		if fn == nil {
+			b.err = fmt.Errorf("fork branch %s: function cannot be nil", name)
+			return b
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fns := make([]StepFunc, len(names))
+	for i, name := range names {
+		fns[i] = branches[name]
+	}
+
+	b.chain.steps = append(b.chain.steps, step{
+		name: "fork",
+		handler: func(ctx context.Context, input any) (any, error) {
+			return runBranches(ctx, names, fns, input)
+		},
+	})
+	return b
+}
+
+// Join 添加合并步骤
+// 将 Fork 产生的分支结果合并为下一步的输入
+func (b *ChainBuilder[I, O]) Join(merge func(ctx context.Context, results map[string]any) (any, error)) *ChainBuilder[I, O] {
+	if b.err != nil {
+		return b
+	}
+	if merge == nil {
+		b.err = fmt.Errorf("join: merge function cannot be nil")
+		return b
+	}
+
+	b.chain.steps = append(b.chain.steps, step{
+		name: "join",
+		handler: func(ctx context.Context, input any) (any, error) {
+			results, ok := input.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("join input type mismatch: expected map[string]any, got %T", input)
+			}
+			return merge(ctx, results)
+		},
+	})
+	return b
+}
+
+// runBranches 并发执行分支，任一失败时取消其余分支
+// names 与 fns 一一对应，分支中的 panic 由 pool.Run 恢复为错误
+func runBranches(ctx context.Context, names []string, fns []StepFunc, input any) (map[string]any, error) {
+	outputs, err := pool.Run(ctx, fns, len(fns), func(ctx context.Context, fn StepFunc) (any, error) {
+		return fn(ctx, input)
+	})
+	if err != nil {
+		var itemErr *pool.ItemError
+		if errors.As(err, &itemErr) {
+			return nil, fmt.Errorf("branch %s: %w", names[itemErr.Index], itemErr.Err)
+		}
+		return nil, err
+	}
+
+	results := make(map[string]any, len(names))
+	for i, name := range names {
+		results[name] = outputs[i]
+	}
+	return results, nil
+}
+
 // Use 添加中间件
+// 中间件按添加顺序由外到内包装每个步骤（包括 Tap 步骤），先添加的最先执行
 func (b *ChainBuilder[I, O]) Use(middleware ...Middleware) *ChainBuilder[I, O] {
 	if b.err != nil {
 		return b
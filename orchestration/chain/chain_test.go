@@ -3,6 +3,8 @@ package chain
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -282,6 +284,165 @@ func TestChainWithMiddleware(t *testing.T) {
 	}
 }
 
+func TestChainTap(t *testing.T) {
+	var logs []string
+
+	middleware := func(next StepFunc) StepFunc {
+		return func(ctx context.Context, input any) (any, error) {
+			logs = append(logs, "before")
+			return next(ctx, input)
+		}
+	}
+
+	chain, err := NewChain[string, string]("tap-chain").
+		Use(middleware).
+		Tap("log", func(ctx context.Context, input any) error {
+			logs = append(logs, "tap:"+input.(string))
+			return nil
+		}).
+		PipeFunc("upper", func(ctx context.Context, input any) (any, error) {
+			return input.(string) + "!", nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	result, err := chain.Invoke(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if result != "hello!" {
+		t.Errorf("expected 'hello!', got '%s'", result)
+	}
+
+	expected := []string{"before", "tap:hello", "before"}
+	if len(logs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, logs)
+	}
+	for i := range logs {
+		if logs[i] != expected[i] {
+			t.Errorf("log[%d]: expected '%s', got '%s'", i, expected[i], logs[i])
+		}
+	}
+}
+
+func TestChainTapError(t *testing.T) {
+	called := false
+	tapErr := errors.New("tap failed")
+
+	chain, _ := NewChain[string, string]("tap-error").
+		Tap("fail", func(ctx context.Context, input any) error {
+			return tapErr
+		}).
+		PipeFunc("next", func(ctx context.Context, input any) (any, error) {
+			called = true
+			return input, nil
+		}).
+		Build()
+
+	_, err := chain.Invoke(context.Background(), "test")
+	if !errors.Is(err, tapErr) {
+		t.Errorf("expected tap error, got %v", err)
+	}
+	if called {
+		t.Error("expected chain to stop after tap error")
+	}
+
+	if _, err := NewChain[string, string]("nil-tap").Tap("nil", nil).Build(); err == nil {
+		t.Error("expected error for nil tap function")
+	}
+}
+
+func TestChainForkJoin(t *testing.T) {
+	chain, err := NewChain[string, string]("fork-chain").
+		Fork(map[string]StepFunc{
+			"upper": func(ctx context.Context, input any) (any, error) {
+				return strings.ToUpper(input.(string)), nil
+			},
+			"length": func(ctx context.Context, input any) (any, error) {
+				return len(input.(string)), nil
+			},
+		}).
+		Join(func(ctx context.Context, results map[string]any) (any, error) {
+			return fmt.Sprintf("%s:%d", results["upper"], results["length"]), nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	result, err := chain.Invoke(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if result != "ABC:3" {
+		t.Errorf("expected 'ABC:3', got '%s'", result)
+	}
+
+	// Fork 复制分支，之后修改 map 不影响已构建的链
+	branches := map[string]StepFunc{
+		"echo": func(ctx context.Context, input any) (any, error) { return input, nil },
+	}
+	forked := NewChain[string, map[string]any]("fork-copy").Fork(branches).MustBuild()
+	branches["late"] = func(ctx context.Context, input any) (any, error) { return "late", nil }
+	delete(branches, "echo")
+	out, err := forked.Invoke(context.Background(), "x")
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if len(out) != 1 || out["echo"] != "x" {
+		t.Errorf("expected branches copied at Fork, got %v", out)
+	}
+}
+
+func TestChainForkError(t *testing.T) {
+	branchErr := errors.New("branch failed")
+
+	chain, _ := NewChain[string, string]("fork-error").
+		Fork(map[string]StepFunc{
+			"fail": func(ctx context.Context, input any) (any, error) {
+				return nil, branchErr
+			},
+			"slow": func(ctx context.Context, input any) (any, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		}).
+		Join(func(ctx context.Context, results map[string]any) (any, error) {
+			return "joined", nil
+		}).
+		Build()
+
+	_, err := chain.Invoke(context.Background(), "test")
+	if !errors.Is(err, branchErr) {
+		t.Errorf("expected branch error, got %v", err)
+	}
+
+	// 分支 panic 作为错误返回
+	panicky, _ := NewChain[string, string]("fork-panic").
+		Fork(map[string]StepFunc{
+			"boom": func(ctx context.Context, input any) (any, error) { panic("boom") },
+		}).
+		Build()
+	if _, err := panicky.Invoke(context.Background(), "test"); err == nil || !strings.Contains(err.Error(), "branch boom") {
+		t.Errorf("expected recovered panic error, got %v", err)
+	}
+
+	if _, err := NewChain[string, string]("empty-fork").Fork(nil).Build(); err == nil {
+		t.Error("expected error for empty fork")
+	}
+
+	joinOnly, _ := NewChain[string, string]("join-only").
+		Join(func(ctx context.Context, results map[string]any) (any, error) {
+			return "joined", nil
+		}).
+		Build()
+	if _, err := joinOnly.Invoke(context.Background(), "test"); err == nil {
+		t.Error("expected error for join without map input")
+	}
+}
+
 func TestLoggingMiddleware(t *testing.T) {
 	var loggedName string
 	var loggedInput, loggedOutput any
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/stream"
+)
+
+// AgentNode 创建运行 Agent 的节点
+//
+// mapIn 从图状态构造 Agent 输入，mapOut 将 Agent 输出写回图状态，二者均不能为 nil。
+// 通过 StreamRun 执行时，Agent 以流式方式运行，每个输出块作为
+// StreamModeMessages 模式的 EventMessage 事件转发到图的事件流（Data 为 agent.Output）。
+// Agent 出错时返回节点错误，状态保持不变。
+//
+// 使用示例：
+//
+//	g := NewGraph[MyState]("agents").
+//	    AddNodeWithBuilder(AgentNode("research", researcher,
+//	        func(s MyState) agent.Input { return agent.Input{Query: s.Question} },
+//	        func(s MyState, out agent.Output) MyState { s.Notes = out.Content; return s },
+//	    )).
+//	    ...
+func AgentNode[S State](name string, a agent.Agent, mapIn func(S) agent.Input, mapOut func(S, agent.Output) S) *Node[S] {
+	node := &Node[S]{
+		Name:     name,
+		Type:     NodeTypeAgent,
+		Metadata: make(map[string]any),
+		Handler: func(ctx context.Context, state S) (S, error) {
+			if a == nil || mapIn == nil || mapOut == nil {
+				return state, fmt.Errorf("agent node %s: agent, mapIn and mapOut are required", name)
+			}
+
+			output, err := runAgentNode(ctx, name, a, mapIn(state))
+			if err != nil {
+				return state, fmt.Errorf("agent node %s: %w", name, err)
+			}
+			return mapOut(state, output), nil
+		},
+	}
+	if a != nil {
+		node.Metadata["agent_id"] = a.ID()
+	}
+	return node
+}
+
+// runAgentNode 运行 Agent，context 中有 StreamChannel 时转发流式输出
+func runAgentNode(ctx context.Context, name string, a agent.Agent, input agent.Input) (agent.Output, error) {
+	ch, ok := ctx.Value(streamChannelKey{}).(*StreamChannel)
+	if !ok || ch == nil {
+		return a.Invoke(ctx, input)
+	}
+
+	reader, err := a.Stream(ctx, input)
+	if err != nil {
+		return agent.Output{}, err
+	}
+	defer reader.Close()
+
+	var chunks []agent.Output
+	for {
+		chunk, err := reader.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return agent.Output{}, err
+		}
+		chunks = append(chunks, chunk)
+		ch.Emit(StreamModeEvent{
+			Mode: StreamModeMessages,
+			Type: EventMessage,
+			Node: name,
+			Data: chunk,
+		})
+	}
+	return stream.ConcatItems(chunks)
+}
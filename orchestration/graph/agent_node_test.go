@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func TestAgentNode(t *testing.T) {
+	llm := mock.NewLLMProvider("mock").AddResponse("draft ready")
+	writer := agent.NewBaseAgent(agent.WithName("writer"), agent.WithLLM(llm))
+
+	g := NewGraph[TestState]("agents").
+		AddNodeWithBuilder(AgentNode("write", writer,
+			func(s TestState) agent.Input { return agent.Input{Query: s.Path} },
+			func(s TestState, out agent.Output) TestState {
+				s.Data = map[string]string{"draft": out.Content}
+				return s
+			},
+		)).
+		AddEdge(START, "write").
+		AddEdge("write", END).
+		MustBuild()
+
+	result, err := g.Run(context.Background(), TestState{Path: "write a haiku"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Data["draft"] != "draft ready" {
+		t.Errorf("expected agent output in state, got %v", result.Data)
+	}
+	if got := llm.LastCall().Messages; got[len(got)-1].Content != "write a haiku" {
+		t.Errorf("expected mapped input, got %q", got[len(got)-1].Content)
+	}
+}
+
+func TestAgentNodeStreamsMessages(t *testing.T) {
+	llm := mock.NewLLMProvider("mock").AddResponse("hello")
+	a := agent.NewBaseAgent(agent.WithName("greeter"), agent.WithLLM(llm))
+
+	g := NewGraph[TestState]("agents").
+		AddNodeWithBuilder(AgentNode("greet", a,
+			func(s TestState) agent.Input { return agent.Input{Query: "hi"} },
+			func(s TestState, out agent.Output) TestState { s.Path = out.Content; return s },
+		)).
+		AddEdge(START, "greet").
+		AddEdge("greet", END).
+		MustBuild()
+
+	ch, err := g.StreamRun(context.Background(), TestState{}, WithStreamMode(StreamModeMessages))
+	if err != nil {
+		t.Fatalf("StreamRun failed: %v", err)
+	}
+
+	var messages []StreamModeEvent
+	for event := range ch.Events() {
+		if event.Type == EventMessage {
+			messages = append(messages, event)
+		}
+	}
+	if len(messages) != 1 || messages[0].Node != "greet" {
+		t.Fatalf("expected 1 message event from greet, got %+v", messages)
+	}
+	if out, ok := messages[0].Data.(agent.Output); !ok || out.Content != "hello" {
+		t.Errorf("expected agent output as event data, got %+v", messages[0].Data)
+	}
+}
+
+func TestAgentNodeError(t *testing.T) {
+	llm := mock.NewLLMProvider("mock").AddErrorResponse(errors.New("provider down"))
+	a := agent.NewBaseAgent(agent.WithName("broken"), agent.WithLLM(llm))
+
+	g := NewGraph[TestState]("agents").
+		AddNodeWithBuilder(AgentNode("broken", a,
+			func(s TestState) agent.Input { return agent.Input{Query: "q"} },
+			func(s TestState, out agent.Output) TestState { return s },
+		)).
+		AddEdge(START, "broken").
+		AddEdge("broken", END).
+		MustBuild()
+
+	if _, err := g.Run(context.Background(), TestState{}); err == nil {
+		t.Fatal("expected agent error to propagate as node error")
+	}
+}
@@ -14,6 +14,9 @@
 //	    AddNode("step_a", handlerA).
 //	    AddNode("step_b", handlerB).
 //	    AddBarrier("join", mergeFunc, "step_a", "step_b").
+//	    AddEdge(START, "step_a").
+//	    AddEdge(START, "step_b").
+//	    AddEdge("join", END).
 //	    Build()
 //
 //	// 方式 2: MapReduce 模式
@@ -24,8 +27,12 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/interrupt"
 )
 
 // NodeTypeBarrier 屏障节点类型
@@ -35,49 +42,108 @@ const NodeTypeBarrier NodeType = 100
 // 接收原始状态和所有上游分支的输出状态，返回合并后的状态
 type BarrierMerger[S State] func(original S, branchOutputs map[string]S) S
 
+// BarrierHandler 屏障处理函数
+//
+// original 为扇出前的状态，branches 为各上游节点（键为节点名）完成时的分支状态，
+// 返回合并后的状态，作为屏障之后节点的输入。
+type BarrierHandler[S State] func(ctx context.Context, original S, branches map[string]S) (S, error)
+
 // BarrierNode 创建屏障/延迟节点
 // 等待所有指定的上游分支完成后，使用 merger 合并所有分支结果
 //
 // 参数:
 //   - name: 节点名称
-//   - merger: 状态合并函数
+//   - merger: 状态合并函数，为 nil 时沿用扇出前的状态
 //   - waitFor: 需要等待的上游节点名称列表
 func BarrierNode[S State](name string, merger BarrierMerger[S], waitFor ...string) *Node[S] {
+	return barrierNode(name, func(_ context.Context, original S, branches map[string]S) (S, error) {
+		if merger == nil {
+			return original, nil
+		}
+		return merger(original, branches), nil
+	}, waitFor)
+}
+
+// barrierNode 创建屏障节点，处理函数由执行器在所有上游完成后调用
+func barrierNode[S State](name string, handler BarrierHandler[S], waitFor []string) *Node[S] {
 	return &Node[S]{
 		Name: name,
 		Type: NodeTypeBarrier,
 		Handler: func(ctx context.Context, state S) (S, error) {
 			// 由执行器处理实际等待逻辑，此处直接返回
-			// 如果 merger 为 nil，直接返回原始状态
 			return state, nil
 		},
 		Metadata: map[string]any{
 			"__barrier_wait_for": waitFor,
-			"__barrier_merger":   merger,
+			"__barrier_handler":  handler,
 		},
 	}
 }
 
 // AddBarrier 在图构建器中添加屏障节点
-// 等待所有指定上游节点完成后执行合并
+// 等待所有指定上游节点完成后执行合并，参见 AddBarrierNode
 func (b *GraphBuilder[S]) AddBarrier(name string, merger BarrierMerger[S], waitFor ...string) *GraphBuilder[S] {
 	if b.err != nil {
 		return b
 	}
+	if len(waitFor) == 0 {
+		b.err = fmt.Errorf("barrier %q 至少需要一个上游节点", name)
+		return b
+	}
+	return b.addBarrier(BarrierNode(name, merger, waitFor...), waitFor)
+}
 
+// AddBarrierNode 添加等待多个上游分支的屏障节点
+//
+// 节点有多条普通出边时，Run 和 Stream 将每条出边作为独立分支并行执行（各自持有状态副本），
+// 分支沿边执行到屏障节点为止；所有 waitFor 中的上游节点完成后，fn 以扇出前的状态和
+// 各上游的分支状态只调用一次，其返回值作为屏障之后的状态。自动添加 waitFor 到屏障的边。
+//
+// 用于菱形图（扇出到多个不同节点，在屏障处汇合）：
+//
+//	g, err := NewGraph[MyState]("review").
+//	    AddNode("lint", lint).
+//	    AddNode("security", securityScan).
+//	    AddBarrierNode("report", mergeReports, "lint", "security").
+//	    AddEdge(START, "lint").
+//	    AddEdge(START, "security").
+//	    AddEdge("report", END).
+//	    Build()
+//
+// 限制：所有分支必须汇合到同一个屏障，分支内不能再次扇出；
+// 分支失败时取消其他分支，不保存 WithCheckpointOnError 检查点。
+func (b *GraphBuilder[S]) AddBarrierNode(name string, fn BarrierHandler[S], waitFor ...string) *GraphBuilder[S] {
+	if b.err != nil {
+		return b
+	}
 	if len(waitFor) == 0 {
 		b.err = fmt.Errorf("barrier %q 至少需要一个上游节点", name)
 		return b
 	}
+	if fn == nil {
+		b.err = fmt.Errorf("%w: barrier %q handler is nil", core.ErrInvalidInput, name)
+		return b
+	}
+	return b.addBarrier(barrierNode(name, fn, waitFor), waitFor)
+}
 
-	node := BarrierNode(name, merger, waitFor...)
-	b.graph.Nodes[name] = node
+// addBarrier 添加屏障节点及上游到屏障的边
+func (b *GraphBuilder[S]) addBarrier(node *Node[S], waitFor []string) *GraphBuilder[S] {
+	if node.Name == START || node.Name == END {
+		b.err = fmt.Errorf("cannot use reserved node name: %s", node.Name)
+		return b
+	}
+	if _, exists := b.graph.Nodes[node.Name]; exists {
+		b.err = fmt.Errorf("node %s already exists", node.Name)
+		return b
+	}
+	b.graph.Nodes[node.Name] = node
 
 	// 自动添加所有上游节点到此屏障的边
 	for _, from := range waitFor {
 		b.graph.Edges = append(b.graph.Edges, &Edge{
 			From: from,
-			To:   name,
+			To:   node.Name,
 			Type: EdgeTypeNormal,
 		})
 	}
@@ -85,6 +151,174 @@ func (b *GraphBuilder[S]) AddBarrier(name string, merger BarrierMerger[S], waitF
 	return b
 }
 
+// barrierSpec 编译后的屏障定义
+type barrierSpec[S State] struct {
+	waitFor []string
+	handler BarrierHandler[S]
+}
+
+// compileBarriers 收集图中的屏障节点
+func (g *Graph[S]) compileBarriers() {
+	g.barriers = nil
+	for name, node := range g.Nodes {
+		if node.Type != NodeTypeBarrier {
+			continue
+		}
+		waitFor, _ := node.Metadata["__barrier_wait_for"].([]string)
+		handler, _ := node.Metadata["__barrier_handler"].(BarrierHandler[S])
+		if handler == nil {
+			continue
+		}
+		if g.barriers == nil {
+			g.barriers = make(map[string]*barrierSpec[S])
+		}
+		g.barriers[name] = &barrierSpec[S]{waitFor: waitFor, handler: handler}
+	}
+}
+
+// fanOutTargets 返回节点扇出的目标，图中没有屏障或只有一条出边时返回 nil
+func (g *Graph[S]) fanOutTargets(node string) []string {
+	if len(g.barriers) == 0 || len(g.conditionalEdges[node]) > 0 {
+		return nil
+	}
+	if targets := g.adjacency[node]; len(targets) > 1 {
+		return targets
+	}
+	return nil
+}
+
+// branchResult 扇出分支的执行结果
+type branchResult[S State] struct {
+	target  string
+	barrier string
+	pred    string
+	state   S
+	err     error
+}
+
+// fanOut 并行执行 from 的所有出边分支，在屏障处合并
+// emit 不为 nil 时为分支中的每个节点发送开始和完成事件（可能被并发调用）
+// 返回屏障节点名称和合并后的状态
+func (e *graphExecutor[S]) fanOut(ctx context.Context, from string, targets []string, emit func(StreamEvent[S])) (string, S, error) {
+	original := e.state
+	branchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan branchResult[S], len(targets))
+	for _, target := range targets {
+		go func() {
+			r := e.runBranch(branchCtx, target, original.Clone().(S), emit)
+			if r.err != nil {
+				cancel()
+			}
+			results <- r
+		}()
+	}
+
+	branches := make(map[string]S, len(targets))
+	barrier := ""
+	var errs []error
+	for range targets {
+		r := <-results
+		switch {
+		case r.err != nil:
+			errs = append(errs, fmt.Errorf("branch %s failed: %w", r.target, r.err))
+		case barrier != "" && r.barrier != barrier:
+			errs = append(errs, fmt.Errorf("%w: branches from %s converge at different barriers %s and %s",
+				core.ErrInvalidInput, from, barrier, r.barrier))
+		default:
+			barrier = r.barrier
+			branches[r.pred] = r.state
+		}
+	}
+	if len(errs) > 0 {
+		// 被取消的分支只返回 context 错误，优先报告首个真实错误
+		for _, err := range errs {
+			if !errors.Is(err, context.Canceled) {
+				return "", original, err
+			}
+		}
+		return "", original, errs[0]
+	}
+
+	spec := e.graph.barriers[barrier]
+	for _, upstream := range spec.waitFor {
+		if _, ok := branches[upstream]; !ok {
+			return "", original, fmt.Errorf("%w: barrier %s: upstream %s is not reachable from %s",
+				core.ErrInvalidInput, barrier, upstream, from)
+		}
+	}
+
+	if emit != nil {
+		emit(StreamEvent[S]{Type: EventTypeNodeStart, NodeName: barrier, State: original})
+	}
+	start := e.observer.nodeRunning(ctx, barrier)
+	merged, err := spec.handler(ctx, original, branches)
+	e.observer.nodeFinished(ctx, barrier, start, err)
+	if err != nil {
+		return "", original, fmt.Errorf("node %s failed: %w", barrier, err)
+	}
+	if emit != nil {
+		emit(StreamEvent[S]{Type: EventTypeNodeEnd, NodeName: barrier, State: merged})
+	}
+	return barrier, merged, nil
+}
+
+// runBranch 从 target 开始沿边执行，直到到达屏障节点
+func (e *graphExecutor[S]) runBranch(ctx context.Context, target string, state S, emit func(StreamEvent[S])) branchResult[S] {
+	r := branchResult[S]{target: target}
+	pred, current := "", target
+	for {
+		if ctx.Err() != nil {
+			r.err = ctxError(ctx, e.config)
+			return r
+		}
+		if _, ok := e.graph.barriers[current]; ok {
+			r.barrier, r.pred, r.state = current, pred, state
+			if pred == "" {
+				r.err = fmt.Errorf("%w: barrier %s is a direct fan-out target", core.ErrInvalidInput, current)
+			}
+			return r
+		}
+		if current == END {
+			r.err = fmt.Errorf("%w: branch reached END before a barrier", core.ErrInvalidInput)
+			return r
+		}
+		node, ok := e.graph.Nodes[current]
+		if !ok {
+			r.err = fmt.Errorf("node %s %w", current, core.ErrNotFound)
+			return r
+		}
+
+		if emit != nil {
+			emit(StreamEvent[S]{Type: EventTypeNodeStart, NodeName: current, State: state})
+		}
+		nodeCtx := interrupt.AppendAddressSegment(ctx, interrupt.SegmentNode, current, "")
+		start := e.observer.nodeRunning(ctx, current)
+		newState, err := executeNodeWithRetry(nodeCtx, e.config, current, node, state, nil)
+		e.observer.nodeFinished(ctx, current, start, err)
+		if err != nil {
+			r.err = fmt.Errorf("node %s failed: %w", current, err)
+			return r
+		}
+		state = newState
+		if emit != nil {
+			emit(StreamEvent[S]{Type: EventTypeNodeEnd, NodeName: current, State: state})
+		}
+
+		if e.graph.fanOutTargets(current) != nil {
+			r.err = fmt.Errorf("%w: nested fan-out at node %s is not supported", core.ErrInvalidInput, current)
+			return r
+		}
+		next, err := e.nextNode(current, state)
+		if err != nil {
+			r.err = err
+			return r
+		}
+		pred, current = current, next
+	}
+}
+
 // ============== MapReduce 模式 ==============
 
 // SplitFunc 数据分片函数
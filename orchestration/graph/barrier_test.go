@@ -2,8 +2,12 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // TestBarrierNode 测试屏障节点
@@ -518,3 +522,144 @@ func TestAddBarrier_EdgesCreated(t *testing.T) {
 		t.Error("expected join node to exist")
 	}
 }
+
+// TestAddBarrierNode_Diamond 测试扇出到多个节点后在屏障汇合
+func TestAddBarrierNode_Diamond(t *testing.T) {
+	ctx := context.Background()
+	var barrierCalls atomic.Int32
+
+	set := func(key string) NodeHandler[MapState] {
+		return func(_ context.Context, s MapState) (MapState, error) {
+			s.Set(key, true)
+			return s, nil
+		}
+	}
+	g, err := NewGraph[MapState]("diamond").
+		AddNode("prepare", set("prepared")).
+		AddNode("lint", set("lint")).
+		AddNode("security", set("security")).
+		AddNode("security_report", set("security_report")).
+		AddBarrierNode("report", func(_ context.Context, original MapState, branches map[string]MapState) (MapState, error) {
+			barrierCalls.Add(1)
+			if _, ok := original.Get("lint"); ok {
+				t.Error("original state should not contain branch updates")
+			}
+			merged := original.Clone().(MapState)
+			for upstream, s := range branches {
+				for k, v := range s {
+					merged[k] = v
+				}
+				merged.Set("from_"+upstream, true)
+			}
+			return merged, nil
+		}, "lint", "security_report").
+		AddNode("publish", set("published")).
+		AddEdge(START, "prepare").
+		AddEdge("prepare", "lint").
+		AddEdge("prepare", "security").
+		AddEdge("security", "security_report").
+		AddEdge("report", "publish").
+		AddEdge("publish", END).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := g.Run(ctx, MapState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"prepared", "lint", "security", "security_report", "from_lint", "from_security_report", "published"} {
+		if _, ok := result.Get(key); !ok {
+			t.Errorf("expected %s in result, got %v", key, result)
+		}
+	}
+	if n := barrierCalls.Load(); n != 1 {
+		t.Errorf("expected barrier to run exactly once, got %d", n)
+	}
+
+	// Stream 为分支节点和屏障发送事件
+	events, err := g.Stream(ctx, MapState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ended := map[string]bool{}
+	for evt := range events {
+		if evt.Type == EventTypeError {
+			t.Fatalf("stream error: %v", evt.Error)
+		}
+		if evt.Type == EventTypeNodeEnd {
+			ended[evt.NodeName] = true
+		}
+	}
+	for _, name := range []string{"lint", "security_report", "report", "publish"} {
+		if !ended[name] {
+			t.Errorf("expected node end event for %s, got %v", name, ended)
+		}
+	}
+}
+
+// TestAddBarrier_FanOutFromStart 测试从 START 扇出并用合并函数汇合
+func TestAddBarrier_FanOutFromStart(t *testing.T) {
+	merger := func(original MapState, branchOutputs map[string]MapState) MapState {
+		original.Set("branches", len(branchOutputs))
+		return original
+	}
+	g, err := NewGraph[MapState]("start-fan-out").
+		AddNode("step_a", func(_ context.Context, s MapState) (MapState, error) { return s, nil }).
+		AddNode("step_b", func(_ context.Context, s MapState) (MapState, error) { return s, nil }).
+		AddBarrier("join", merger, "step_a", "step_b").
+		AddEdge(START, "step_a").
+		AddEdge(START, "step_b").
+		AddEdge("join", END).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := g.Run(context.Background(), MapState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := result.Get("branches"); n != 2 {
+		t.Errorf("expected 2 branches merged, got %v", n)
+	}
+}
+
+// TestAddBarrierNode_Errors 测试分支失败和上游缺失
+func TestAddBarrierNode_Errors(t *testing.T) {
+	errBoom := errors.New("boom")
+	passthrough := func(_ context.Context, s MapState) (MapState, error) { return s, nil }
+	merge := func(_ context.Context, original MapState, _ map[string]MapState) (MapState, error) {
+		return original, nil
+	}
+
+	g := NewGraph[MapState]("failing").
+		AddNode("a", passthrough).
+		AddNode("b", func(_ context.Context, s MapState) (MapState, error) { return s, errBoom }).
+		AddBarrierNode("join", merge, "a", "b").
+		AddEdge(START, "a").
+		AddEdge(START, "b").
+		AddEdge("join", END).
+		MustBuild()
+	if _, err := g.Run(context.Background(), MapState{}); !errors.Is(err, errBoom) {
+		t.Errorf("expected branch error, got %v", err)
+	}
+
+	// c 不在扇出的分支上，屏障无法满足
+	g = NewGraph[MapState]("unreachable").
+		AddNode("a", passthrough).
+		AddNode("b", passthrough).
+		AddNode("c", passthrough).
+		AddBarrierNode("join", merge, "a", "b", "c").
+		AddEdge(START, "a").
+		AddEdge(START, "b").
+		AddEdge("join", END).
+		MustBuild()
+	if _, err := g.Run(context.Background(), MapState{}); !errors.Is(err, core.ErrInvalidInput) {
+		t.Errorf("expected unreachable upstream error, got %v", err)
+	}
+
+	if _, err := NewGraph[MapState]("nil").AddBarrierNode("join", nil, "a").Build(); err == nil {
+		t.Error("expected error for nil barrier handler")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/internal/util"
 )
 
@@ -97,10 +98,10 @@ func (s *MemoryCheckpointSaver) Save(ctx context.Context, checkpoint *Checkpoint
 		return err
 	}
 	if checkpoint == nil {
-		return fmt.Errorf("checkpoint is nil")
+		return fmt.Errorf("%w: checkpoint is nil", core.ErrInvalidInput)
 	}
 	if checkpoint.ThreadID == "" {
-		return fmt.Errorf("checkpoint thread_id is required")
+		return fmt.Errorf("%w: checkpoint thread_id is required", core.ErrInvalidInput)
 	}
 
 	s.mu.Lock()
@@ -147,14 +148,14 @@ func (s *MemoryCheckpointSaver) Load(ctx context.Context, threadID string) (*Che
 
 	ids, ok := s.threads[threadID]
 	if !ok || len(ids) == 0 {
-		return nil, fmt.Errorf("no checkpoint found for thread %s", threadID)
+		return nil, fmt.Errorf("no checkpoint found for thread %s: %w", threadID, core.ErrNotFound)
 	}
 
 	// 返回最新的检查点
 	latestID := ids[len(ids)-1]
 	cp, ok := s.checkpoints[latestID]
 	if !ok {
-		return nil, fmt.Errorf("checkpoint %s not found", latestID)
+		return nil, fmt.Errorf("checkpoint %s %w", latestID, core.ErrNotFound)
 	}
 
 	return cloneCheckpoint(cp), nil
@@ -171,7 +172,7 @@ func (s *MemoryCheckpointSaver) LoadByID(ctx context.Context, id string) (*Check
 
 	cp, ok := s.checkpoints[id]
 	if !ok {
-		return nil, fmt.Errorf("checkpoint %s not found", id)
+		return nil, fmt.Errorf("checkpoint %s %w", id, core.ErrNotFound)
 	}
 
 	return cloneCheckpoint(cp), nil
@@ -341,3 +342,46 @@ func generateCheckpointID() string {
 
 // 确保实现了接口
 var _ CheckpointSaver = (*MemoryCheckpointSaver)(nil)
+
+// saveErrorCheckpoint 在节点失败时保存最后一次成功的状态（WithCheckpointOnError）
+// 未启用或未配置保存器时返回 nil, nil
+func saveErrorCheckpoint[S State](ctx context.Context, g *Graph[S], config *runConfig, nodeName string, state S, cause error) (*Checkpoint, error) {
+	if !config.checkpointOnError {
+		return nil, nil
+	}
+	saver := g.Checkpointer
+	threadID := ""
+	if tc := config.threadConfig; tc != nil {
+		if tc.CheckpointSaver != nil {
+			saver = tc.CheckpointSaver
+		}
+		threadID = tc.ThreadID
+	}
+	if saver == nil {
+		return nil, nil
+	}
+	if threadID == "" {
+		threadID = util.GenerateID("thread")
+	}
+
+	stateData, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("marshal state: %w", err)
+	}
+	cp := &Checkpoint{
+		ThreadID:     threadID,
+		GraphName:    g.Name,
+		CurrentNode:  nodeName,
+		State:        stateData,
+		PendingNodes: []string{nodeName},
+		Metadata: map[string]any{
+			"status": "failed",
+			"error":  cause.Error(),
+		},
+	}
+	// 运行可能因超时或取消而失败，保存不受原 context 取消影响
+	if err := saver.Save(context.WithoutCancel(ctx), cp); err != nil {
+		return nil, fmt.Errorf("save checkpoint: %w", err)
+	}
+	return cp, nil
+}
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/internal/util"
 )
 
@@ -222,10 +223,10 @@ func (s *MemoryEnhancedCheckpointSaver) SaveEnhanced(ctx context.Context, checkp
 		return err
 	}
 	if checkpoint == nil {
-		return fmt.Errorf("checkpoint is nil")
+		return fmt.Errorf("%w: checkpoint is nil", core.ErrInvalidInput)
 	}
 	if checkpoint.ThreadID == "" {
-		return fmt.Errorf("checkpoint thread_id is required")
+		return fmt.Errorf("%w: checkpoint thread_id is required", core.ErrInvalidInput)
 	}
 
 	s.mu.Lock()
@@ -286,13 +287,13 @@ func (s *MemoryEnhancedCheckpointSaver) LoadEnhanced(ctx context.Context, thread
 
 	ids, ok := s.threads[threadID]
 	if !ok || len(ids) == 0 {
-		return nil, fmt.Errorf("no checkpoint found for thread %s", threadID)
+		return nil, fmt.Errorf("no checkpoint found for thread %s: %w", threadID, core.ErrNotFound)
 	}
 
 	latestID := ids[len(ids)-1]
 	cp, ok := s.enhanced[latestID]
 	if !ok {
-		return nil, fmt.Errorf("checkpoint %s not found", latestID)
+		return nil, fmt.Errorf("checkpoint %s %w", latestID, core.ErrNotFound)
 	}
 
 	return cloneEnhancedCheckpoint(cp), nil
@@ -304,7 +305,7 @@ func (s *MemoryEnhancedCheckpointSaver) LoadEnhancedByID(ctx context.Context, id
 		return nil, err
 	}
 	if id == "" {
-		return nil, fmt.Errorf("checkpoint id is required")
+		return nil, fmt.Errorf("%w: checkpoint id is required", core.ErrInvalidInput)
 	}
 
 	s.mu.RLock()
@@ -312,7 +313,7 @@ func (s *MemoryEnhancedCheckpointSaver) LoadEnhancedByID(ctx context.Context, id
 
 	cp, ok := s.enhanced[id]
 	if !ok {
-		return nil, fmt.Errorf("checkpoint %s not found", id)
+		return nil, fmt.Errorf("checkpoint %s %w", id, core.ErrNotFound)
 	}
 
 	return cloneEnhancedCheckpoint(cp), nil
@@ -382,7 +383,7 @@ func (s *MemoryEnhancedCheckpointSaver) GetHistory(ctx context.Context, checkpoi
 		return nil, err
 	}
 	if checkpointID == "" {
-		return nil, fmt.Errorf("checkpoint id is required")
+		return nil, fmt.Errorf("%w: checkpoint id is required", core.ErrInvalidInput)
 	}
 
 	s.mu.RLock()
@@ -428,10 +429,10 @@ func (s *MemoryEnhancedCheckpointSaver) CreateBranch(ctx context.Context, checkp
 		return nil, err
 	}
 	if checkpointID == "" {
-		return nil, fmt.Errorf("checkpoint id is required")
+		return nil, fmt.Errorf("%w: checkpoint id is required", core.ErrInvalidInput)
 	}
 	if branchName == "" {
-		return nil, fmt.Errorf("branch name is required")
+		return nil, fmt.Errorf("%w: branch name is required", core.ErrInvalidInput)
 	}
 
 	s.mu.Lock()
@@ -440,7 +441,7 @@ func (s *MemoryEnhancedCheckpointSaver) CreateBranch(ctx context.Context, checkp
 	// 获取源检查点
 	source, ok := s.enhanced[checkpointID]
 	if !ok {
-		return nil, fmt.Errorf("checkpoint %s not found", checkpointID)
+		return nil, fmt.Errorf("checkpoint %s %w", checkpointID, core.ErrNotFound)
 	}
 
 	// 创建分支
@@ -503,10 +504,10 @@ func (s *MemoryEnhancedCheckpointSaver) MergeBranch(ctx context.Context, sourceB
 		return nil, err
 	}
 	if sourceBranchID == "" {
-		return nil, fmt.Errorf("source branch id is required")
+		return nil, fmt.Errorf("%w: source branch id is required", core.ErrInvalidInput)
 	}
 	if targetBranchID == "" {
-		return nil, fmt.Errorf("target branch id is required")
+		return nil, fmt.Errorf("%w: target branch id is required", core.ErrInvalidInput)
 	}
 
 	s.mu.Lock()
@@ -514,12 +515,12 @@ func (s *MemoryEnhancedCheckpointSaver) MergeBranch(ctx context.Context, sourceB
 
 	sourceBranch, ok := s.branches[sourceBranchID]
 	if !ok {
-		return nil, fmt.Errorf("source branch %s not found", sourceBranchID)
+		return nil, fmt.Errorf("source branch %s %w", sourceBranchID, core.ErrNotFound)
 	}
 
 	targetBranch, ok := s.branches[targetBranchID]
 	if !ok {
-		return nil, fmt.Errorf("target branch %s not found", targetBranchID)
+		return nil, fmt.Errorf("target branch %s %w", targetBranchID, core.ErrNotFound)
 	}
 	if sourceBranch.LatestCheckpointID == "" {
 		return nil, fmt.Errorf("source branch %s has no latest checkpoint", sourceBranchID)
@@ -530,12 +531,12 @@ func (s *MemoryEnhancedCheckpointSaver) MergeBranch(ctx context.Context, sourceB
 
 	sourceCP, ok := s.enhanced[sourceBranch.LatestCheckpointID]
 	if !ok {
-		return nil, fmt.Errorf("source checkpoint not found")
+		return nil, fmt.Errorf("source checkpoint %w", core.ErrNotFound)
 	}
 
 	targetCP, ok := s.enhanced[targetBranch.LatestCheckpointID]
 	if !ok {
-		return nil, fmt.Errorf("target checkpoint not found")
+		return nil, fmt.Errorf("target checkpoint %w", core.ErrNotFound)
 	}
 
 	// 根据策略合并
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // 确保 FileCheckpointSaver 实现了 CheckpointSaver 接口
@@ -74,10 +76,10 @@ func (s *FileCheckpointSaver) Save(ctx context.Context, checkpoint *Checkpoint)
 		return err
 	}
 	if checkpoint == nil {
-		return fmt.Errorf("checkpoint is nil")
+		return fmt.Errorf("%w: checkpoint is nil", core.ErrInvalidInput)
 	}
 	if checkpoint.ThreadID == "" {
-		return fmt.Errorf("thread_id is required")
+		return fmt.Errorf("%w: thread_id is required", core.ErrInvalidInput)
 	}
 
 	s.mu.Lock()
@@ -173,10 +175,10 @@ func (s *FileCheckpointSaver) Load(ctx context.Context, threadID string) (*Check
 	// 加载索引
 	idx, err := s.loadIndex(threadID)
 	if err != nil {
-		return nil, fmt.Errorf("no checkpoint found for thread %s", threadID)
+		return nil, fmt.Errorf("no checkpoint found for thread %s: %w", threadID, core.ErrNotFound)
 	}
 	if len(idx.CheckpointIDs) == 0 {
-		return nil, fmt.Errorf("no checkpoint found for thread %s", threadID)
+		return nil, fmt.Errorf("no checkpoint found for thread %s: %w", threadID, core.ErrNotFound)
 	}
 
 	// 返回最新的检查点（列表最后一个）
@@ -200,7 +202,7 @@ func (s *FileCheckpointSaver) LoadByID(ctx context.Context, id string) (*Checkpo
 		return nil, err
 	}
 	if id == "" {
-		return nil, fmt.Errorf("checkpoint id is required")
+		return nil, fmt.Errorf("%w: checkpoint id is required", core.ErrInvalidInput)
 	}
 
 	s.mu.RLock()
@@ -210,7 +212,7 @@ func (s *FileCheckpointSaver) LoadByID(ctx context.Context, id string) (*Checkpo
 	threadsDir := filepath.Join(s.baseDir, "threads")
 	entries, err := os.ReadDir(threadsDir)
 	if err != nil {
-		return nil, fmt.Errorf("checkpoint %s not found", id)
+		return nil, fmt.Errorf("checkpoint %s %w", id, core.ErrNotFound)
 	}
 
 	for _, entry := range entries {
@@ -224,7 +226,7 @@ func (s *FileCheckpointSaver) LoadByID(ctx context.Context, id string) (*Checkpo
 		}
 	}
 
-	return nil, fmt.Errorf("checkpoint %s not found", id)
+	return nil, fmt.Errorf("checkpoint %s %w", id, core.ErrNotFound)
 }
 
 // List 列出线程的所有检查点
@@ -280,7 +282,7 @@ func (s *FileCheckpointSaver) Delete(ctx context.Context, id string) error {
 		return err
 	}
 	if id == "" {
-		return fmt.Errorf("checkpoint id is required")
+		return fmt.Errorf("%w: checkpoint id is required", core.ErrInvalidInput)
 	}
 
 	s.mu.Lock()
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -76,10 +77,10 @@ func (s *RedisCheckpointSaver) Save(ctx context.Context, checkpoint *Checkpoint)
 		return errors.New("redis client is nil")
 	}
 	if checkpoint == nil {
-		return errors.New("checkpoint is nil")
+		return fmt.Errorf("%w: checkpoint is nil", core.ErrInvalidInput)
 	}
 	if checkpoint.ThreadID == "" {
-		return errors.New("checkpoint thread_id is required")
+		return fmt.Errorf("%w: checkpoint thread_id is required", core.ErrInvalidInput)
 	}
 
 	if checkpoint.ID == "" {
@@ -143,7 +144,7 @@ func (s *RedisCheckpointSaver) Load(ctx context.Context, threadID string) (*Chec
 		return nil, fmt.Errorf("get latest checkpoint id: %w", err)
 	}
 	if len(ids) == 0 {
-		return nil, fmt.Errorf("no checkpoint found for thread %s", threadID)
+		return nil, fmt.Errorf("no checkpoint found for thread %s: %w", threadID, core.ErrNotFound)
 	}
 
 	return s.LoadByID(ctx, ids[0])
@@ -158,13 +159,13 @@ func (s *RedisCheckpointSaver) LoadByID(ctx context.Context, id string) (*Checkp
 		return nil, errors.New("redis client is nil")
 	}
 	if id == "" {
-		return nil, fmt.Errorf("checkpoint id is required")
+		return nil, fmt.Errorf("%w: checkpoint id is required", core.ErrInvalidInput)
 	}
 
 	data, err := s.client.Get(ctx, checkpointKey(id)).Bytes()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, fmt.Errorf("checkpoint %s not found", id)
+			return nil, fmt.Errorf("checkpoint %s %w", id, core.ErrNotFound)
 		}
 		return nil, fmt.Errorf("get checkpoint from redis: %w", err)
 	}
@@ -289,7 +290,7 @@ func (s *RedisCheckpointSaver) Delete(ctx context.Context, id string) error {
 		return errors.New("redis client is nil")
 	}
 	if id == "" {
-		return fmt.Errorf("checkpoint id is required")
+		return fmt.Errorf("%w: checkpoint id is required", core.ErrInvalidInput)
 	}
 
 	// 先获取检查点以获取 threadID
@@ -9,8 +9,11 @@ package graph
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // CheckpointRunner 检查点恢复执行器
@@ -36,10 +39,12 @@ type CheckpointRunnerConfig struct {
 	SaveOnError bool
 	// SaveOnInterrupt 中断时是否保存检查点
 	SaveOnInterrupt bool
-	// MaxRetries 最大重试次数
+	// MaxRetries 节点未配置 WithNodeRetry（或 RetryPolicy）时的最大重试次数
 	MaxRetries int
-	// RetryDelay 重试延迟
+	// RetryDelay MaxRetries 生效时的固定重试间隔
 	RetryDelay time.Duration
+	// NodeTimeout 每个节点的默认超时时间，0 表示不限制，Node.Timeout 优先于此值
+	NodeTimeout time.Duration
 }
 
 // DefaultCheckpointRunnerConfig 默认配置
@@ -227,6 +232,9 @@ func (r *CheckpointRunner[S]) executeFromCheckpoint(ctx context.Context, state S
 			if r.config.SaveOnError {
 				r.saveErrorCheckpoint(ctx, state, currentNode, pendingNodes, err)
 			}
+			if errors.Is(err, ErrNodeTimeout) {
+				return state, err
+			}
 			return state, fmt.Errorf("execute node %s: %w", currentNode, err)
 		}
 
@@ -268,31 +276,23 @@ func (r *CheckpointRunner[S]) executeFromCheckpoint(ctx context.Context, state S
 }
 
 // executeNode 执行单个节点
+// 重试遵循节点的 WithNodeRetry 配置，未配置时按 MaxRetries 和 RetryDelay 重试
 func (r *CheckpointRunner[S]) executeNode(ctx context.Context, nodeName string, state S) (S, error) {
 	node, ok := r.graph.Nodes[nodeName]
 	if !ok {
-		return state, fmt.Errorf("node %s not found", nodeName)
+		return state, fmt.Errorf("node %s %w", nodeName, core.ErrNotFound)
 	}
 
-	// 支持重试
-	var lastErr error
-	for i := 0; i <= r.config.MaxRetries; i++ {
-		newState, err := node.Handler(ctx, state)
-		if err == nil {
-			return newState, nil
-		}
-		lastErr = err
-
-		if i < r.config.MaxRetries {
-			select {
-			case <-ctx.Done():
-				return state, ctx.Err()
-			case <-time.After(r.config.RetryDelay):
-			}
+	retry := node.retryConfig()
+	if retry == nil && r.config.MaxRetries > 0 {
+		retry = &core.RetryConfig{
+			MaxRetries:   r.config.MaxRetries,
+			InitialDelay: r.config.RetryDelay,
 		}
 	}
-
-	return state, lastErr
+	// 每次尝试单独计算超时
+	runCfg := &runConfig{nodeTimeout: r.config.NodeTimeout}
+	return executeNodeWithPolicy(ctx, runCfg, nodeName, node, state, retry, nil)
 }
 
 // getSuccessors 获取后继节点
@@ -18,9 +18,13 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/events"
 	"github.com/hexagon-codes/hexagon/interrupt"
 )
 
@@ -52,6 +56,12 @@ type Graph[S State] struct {
 
 	// conditionalEdges 条件边映射
 	conditionalEdges map[string][]conditionalEdge[S]
+
+	// reducers 增量更新节点的字段合并函数
+	reducers map[string]StateReducer
+
+	// barriers 屏障节点（编译后生成）
+	barriers map[string]*barrierSpec[S]
 }
 
 // conditionalEdge 条件边内部表示
@@ -81,7 +91,11 @@ func NewGraph[S State](name string) *GraphBuilder[S] {
 }
 
 // AddNode 添加节点
-func (b *GraphBuilder[S]) AddNode(name string, handler NodeHandler[S]) *GraphBuilder[S] {
+//
+// 可通过 opts 配置节点行为，例如：
+//
+//	AddNode("call_llm", callLLM, WithNodeRetry(core.RetryConfig{MaxRetries: 2, InitialDelay: time.Second}))
+func (b *GraphBuilder[S]) AddNode(name string, handler NodeHandler[S], opts ...NodeOption) *GraphBuilder[S] {
 	if b.err != nil {
 		return b
 	}
@@ -96,11 +110,17 @@ func (b *GraphBuilder[S]) AddNode(name string, handler NodeHandler[S]) *GraphBui
 		return b
 	}
 
+	options := &nodeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	b.graph.Nodes[name] = &Node[S]{
 		Name:     name,
 		Type:     NodeTypeNormal,
 		Handler:  handler,
 		Metadata: make(map[string]any),
+		Retry:    options.retry,
 	}
 	return b
 }
@@ -243,9 +263,13 @@ func (g *Graph[S]) compile() error {
 	if err := g.validate(); err != nil {
 		return err
 	}
+	if err := g.bindUpdates(); err != nil {
+		return err
+	}
+	g.compileBarriers()
 
-	// 设置入口点
-	if g.EntryPoint == "" {
+	// 设置入口点，START 扇出到多个分支时从 START 开始执行
+	if g.EntryPoint == "" && g.fanOutTargets(START) == nil {
 		// 从 START 节点的边推断入口点
 		if targets, ok := g.adjacency[START]; ok && len(targets) > 0 {
 			g.EntryPoint = targets[0]
@@ -304,16 +328,28 @@ func (g *Graph[S]) Run(ctx context.Context, initialState S, opts ...RunOption) (
 		config:  config,
 	}
 
-	return executor.run(ctx)
+	ctx, cancel := config.withGraphTimeout(ctx)
+	defer cancel()
+
+	executor.observer = newRunObserver(ctx, g, config)
+	state, err := executor.run(ctx)
+	executor.observer.graphEnd(ctx, err)
+	return state, err
 }
 
 // RunOption 运行选项
 type RunOption func(*runConfig)
 
 type runConfig struct {
-	threadConfig *ThreadConfig
-	interrupt    []string
-	debug        bool
+	threadConfig      *ThreadConfig
+	interrupt         []string
+	debug             bool
+	graphTimeout      time.Duration
+	nodeTimeout       time.Duration
+	checkpointOnError bool
+	observer          ExecutionObserver
+	bus               *events.Bus[events.Lifecycle]
+	runID             string
 }
 
 // WithThread 设置线程配置
@@ -337,6 +373,19 @@ func WithDebug(debug bool) RunOption {
 	}
 }
 
+// WithCheckpointOnError 节点失败（含超时、取消）时自动保存检查点
+//
+// 检查点保存失败节点之前最后一次成功的状态，CurrentNode 为失败的节点，
+// 便于检查或从该节点重新执行。保存器优先使用 WithThread 的 CheckpointSaver，
+// 其次为图的 Checkpointer；两者都未配置时不保存。
+// 未通过 WithThread 指定线程 ID 时自动生成。
+// Stream 的错误事件 Metadata 中记录 checkpoint_id 和 thread_id。
+func WithCheckpointOnError() RunOption {
+	return func(c *runConfig) {
+		c.checkpointOnError = true
+	}
+}
+
 // graphExecutor 图执行器
 type graphExecutor[S State] struct {
 	graph   *Graph[S]
@@ -344,6 +393,9 @@ type graphExecutor[S State] struct {
 	visited map[string]bool
 	config  *runConfig
 	mu      sync.Mutex
+
+	// observer 执行观察者通知
+	observer *runObserver
 }
 
 // run 执行图
@@ -356,7 +408,7 @@ func (e *graphExecutor[S]) run(ctx context.Context) (S, error) {
 	for {
 		select {
 		case <-ctx.Done():
-			return e.state, ctx.Err()
+			return e.state, ctxError(ctx, e.config)
 		default:
 		}
 
@@ -375,24 +427,46 @@ func (e *graphExecutor[S]) run(ctx context.Context) (S, error) {
 		// 获取节点
 		node, ok := e.graph.Nodes[currentNode]
 		if !ok {
-			return e.state, fmt.Errorf("node %s not found", currentNode)
+			return e.state, fmt.Errorf("node %s %w", currentNode, core.ErrNotFound)
 		}
 
 		// 注入层级地址段
 		nodeCtx := interrupt.AppendAddressSegment(ctx, interrupt.SegmentNode, currentNode, "")
 
 		// 执行节点
-		newState, err := node.Handler(nodeCtx, e.state)
+		start := e.observer.nodeRunning(ctx, currentNode)
+		newState, err := executeNodeWithRetry(nodeCtx, e.config, currentNode, node, e.state, nil)
+		e.observer.nodeFinished(ctx, currentNode, start, err)
 		if err != nil {
 			// 捕获 InterruptSignal，透传给调用方
 			if signal, ok := interrupt.IsInterruptSignal(err); ok {
 				return e.state, signal
 			}
-			return e.state, fmt.Errorf("node %s failed: %w", currentNode, err)
+			if !errors.Is(err, ErrNodeTimeout) && !errors.Is(err, ErrGraphTimeout) {
+				err = fmt.Errorf("node %s failed: %w", currentNode, err)
+			}
+			if _, cpErr := saveErrorCheckpoint(ctx, e.graph, e.config, currentNode, e.state, err); cpErr != nil {
+				err = errors.Join(err, cpErr)
+			}
+			return e.state, err
 		}
 		e.state = newState
 		e.visited[currentNode] = true
 
+		// 多条出边汇合到屏障时并行执行各分支，从屏障继续
+		if targets := e.graph.fanOutTargets(currentNode); targets != nil {
+			barrier, merged, err := e.fanOut(ctx, currentNode, targets, nil)
+			if err != nil {
+				if signal, ok := interrupt.IsInterruptSignal(err); ok {
+					return e.state, signal
+				}
+				return e.state, err
+			}
+			e.state = merged
+			e.visited[barrier] = true
+			currentNode = barrier
+		}
+
 		// 确定下一个节点
 		nextNode, err := e.getNextNode(currentNode)
 		if err != nil {
@@ -407,10 +481,15 @@ func (e *graphExecutor[S]) run(ctx context.Context) (S, error) {
 
 // getNextNode 获取下一个节点
 func (e *graphExecutor[S]) getNextNode(currentNode string) (string, error) {
+	return e.nextNode(currentNode, e.state)
+}
+
+// nextNode 按 state 获取 currentNode 的下一个节点
+func (e *graphExecutor[S]) nextNode(currentNode string, state S) (string, error) {
 	// 先检查条件边
 	if condEdges, ok := e.graph.conditionalEdges[currentNode]; ok && len(condEdges) > 0 {
 		for _, ce := range condEdges {
-			label := ce.router(e.state)
+			label := ce.router(state)
 			if ce.edges == nil {
 				// 动态路由（如 Command 节点）：router 返回值直接作为目标节点名
 				return label, nil
@@ -453,6 +532,13 @@ func (g *Graph[S]) Stream(ctx context.Context, initialState S, opts ...RunOption
 			opt(config)
 		}
 
+		ctx, cancel := config.withGraphTimeout(ctx)
+		defer cancel()
+
+		observer := newRunObserver(ctx, g, config)
+		var runErr error
+		defer func() { observer.graphEnd(ctx, runErr) }()
+
 		state := initialState
 		currentNode := g.EntryPoint
 		if currentNode == "" {
@@ -469,14 +555,33 @@ func (g *Graph[S]) Stream(ctx context.Context, initialState S, opts ...RunOption
 			}
 		}
 
+		// sendError 发送携带最后一次成功状态的错误事件
+		// resumable 为 true 时按 WithCheckpointOnError 保存检查点，nodeName 为恢复时重新执行的节点
+		sendError := func(nodeName string, err error, resumable bool) {
+			runErr = err
+			evt := StreamEvent[S]{
+				Type:     EventTypeError,
+				NodeName: nodeName,
+				State:    state,
+				Error:    err,
+			}
+			if resumable {
+				cp, cpErr := saveErrorCheckpoint(ctx, g, config, nodeName, state, err)
+				switch {
+				case cpErr != nil:
+					evt.Metadata = map[string]any{"checkpoint_error": cpErr}
+				case cp != nil:
+					evt.Metadata = map[string]any{"checkpoint_id": cp.ID, "thread_id": cp.ThreadID}
+				}
+			}
+			sendEvent(evt)
+		}
+
 		for {
 			// 检查 context 是否已取消
 			select {
 			case <-ctx.Done():
-				sendEvent(StreamEvent[S]{
-					Type:  EventTypeError,
-					Error: ctx.Err(),
-				})
+				sendError(currentNode, ctxError(ctx, config), currentNode != END)
 				return
 			default:
 			}
@@ -491,10 +596,7 @@ func (g *Graph[S]) Stream(ctx context.Context, initialState S, opts ...RunOption
 
 			node, ok := g.Nodes[currentNode]
 			if !ok {
-				sendEvent(StreamEvent[S]{
-					Type:  EventTypeError,
-					Error: fmt.Errorf("node %s not found", currentNode),
-				})
+				sendError("", fmt.Errorf("node %s %w", currentNode, core.ErrNotFound), false)
 				return
 			}
 
@@ -508,22 +610,26 @@ func (g *Graph[S]) Stream(ctx context.Context, initialState S, opts ...RunOption
 			}
 
 			// 执行节点（handler 应该自己处理 context 取消）
-			newState, err := node.Handler(ctx, state)
-			if err != nil {
+			nodeName := currentNode
+			start := observer.nodeRunning(ctx, currentNode)
+			newState, err := executeNodeWithRetry(ctx, config, currentNode, node, state, func(attempt int, err error, delay time.Duration) {
 				sendEvent(StreamEvent[S]{
-					Type:     EventTypeError,
-					NodeName: currentNode,
+					Type:     EventTypeNodeRetry,
+					NodeName: nodeName,
+					State:    state,
 					Error:    err,
+					Metadata: map[string]any{"attempt": attempt, "delay": delay},
 				})
+			})
+			observer.nodeFinished(ctx, currentNode, start, err)
+			if err != nil {
+				sendError(currentNode, err, true)
 				return
 			}
 
 			// 节点执行后再次检查 context
 			if ctx.Err() != nil {
-				sendEvent(StreamEvent[S]{
-					Type:  EventTypeError,
-					Error: ctx.Err(),
-				})
+				sendError(currentNode, ctxError(ctx, config), true)
 				return
 			}
 
@@ -538,14 +644,26 @@ func (g *Graph[S]) Stream(ctx context.Context, initialState S, opts ...RunOption
 				return
 			}
 
+			executor := &graphExecutor[S]{graph: g, state: state, config: config, observer: observer}
+
+			// 多条出边汇合到屏障时并行执行各分支，从屏障继续
+			if targets := g.fanOutTargets(currentNode); targets != nil {
+				barrier, merged, err := executor.fanOut(ctx, currentNode, targets, func(evt StreamEvent[S]) {
+					sendEvent(evt)
+				})
+				if err != nil {
+					sendError("", err, false)
+					return
+				}
+				state = merged
+				executor.state = merged
+				currentNode = barrier
+			}
+
 			// 获取下一个节点
-			executor := &graphExecutor[S]{graph: g, state: state, config: config}
 			nextNode, err := executor.getNextNode(currentNode)
 			if err != nil {
-				sendEvent(StreamEvent[S]{
-					Type:  EventTypeError,
-					Error: err,
-				})
+				sendError("", err, false)
 				return
 			}
 
@@ -565,9 +683,10 @@ type StreamEvent[S State] struct {
 	NodeName string
 
 	// State 当前状态
+	// EventTypeError 事件中为出错前最后一次成功的状态，可用于检查或保存检查点
 	State S
 
-	// Error 错误（仅用于 EventTypeError）
+	// Error 错误（用于 EventTypeError 和 EventTypeNodeRetry）
 	Error error
 
 	// Metadata 元数据
@@ -586,6 +705,8 @@ const (
 	EventTypeError
 	// EventTypeEnd 图执行结束
 	EventTypeEnd
+	// EventTypeNodeRetry 节点失败后即将重试，Metadata 包含 attempt 和 delay
+	EventTypeNodeRetry
 )
 
 // String 返回事件类型的字符串表示
@@ -599,6 +720,8 @@ func (t EventType) String() string {
 		return "error"
 	case EventTypeEnd:
 		return "end"
+	case EventTypeNodeRetry:
+		return "node_retry"
 	default:
 		return "unknown"
 	}
@@ -271,6 +271,79 @@ func TestGraphStream(t *testing.T) {
 	}
 }
 
+func TestGraphStreamErrorCarriesLastGoodState(t *testing.T) {
+	errBoom := errors.New("boom")
+	saver := NewMemoryCheckpointSaver()
+	g, err := NewGraph[TestState]("test-graph").
+		AddNode("step1", func(ctx context.Context, s TestState) (TestState, error) {
+			s.Counter++
+			s.Path = "step1"
+			return s, nil
+		}).
+		AddNode("step2", func(ctx context.Context, s TestState) (TestState, error) {
+			s.Counter = 100
+			return s, errBoom
+		}).
+		AddEdge(START, "step1").
+		AddEdge("step1", "step2").
+		AddEdge("step2", END).
+		WithCheckpointer(saver).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := g.Stream(context.Background(), TestState{}, WithThread(&ThreadConfig{ThreadID: "t1"}), WithCheckpointOnError())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var errEvent *StreamEvent[TestState]
+	for event := range events {
+		if event.Type == EventTypeError {
+			errEvent = &event
+		}
+	}
+	if errEvent == nil {
+		t.Fatal("expected error event")
+	}
+	if !errors.Is(errEvent.Error, errBoom) || errEvent.NodeName != "step2" {
+		t.Errorf("unexpected error event: %+v", errEvent)
+	}
+	if errEvent.State.Counter != 1 || errEvent.State.Path != "step1" {
+		t.Errorf("expected last good state from step1, got %+v", errEvent.State)
+	}
+
+	cp, err := saver.Load(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("expected checkpoint to be saved: %v", err)
+	}
+	if cp.ID != errEvent.Metadata["checkpoint_id"] || cp.CurrentNode != "step2" {
+		t.Errorf("unexpected checkpoint: %+v, metadata %v", cp, errEvent.Metadata)
+	}
+}
+
+func TestGraphRunCheckpointOnErrorWithoutSaver(t *testing.T) {
+	g, err := NewGraph[TestState]("test-graph").
+		AddNode("step1", func(ctx context.Context, s TestState) (TestState, error) {
+			return s, errors.New("boom")
+		}).
+		AddEdge(START, "step1").
+		AddEdge("step1", END).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 未配置保存器时只返回原错误
+	state, err := g.Run(context.Background(), TestState{Counter: 7}, WithCheckpointOnError())
+	if err == nil || err.Error() != "node step1 failed: boom" {
+		t.Errorf("expected original node error, got %v", err)
+	}
+	if state.Counter != 7 {
+		t.Errorf("expected last good state, got %+v", state)
+	}
+}
+
 func TestGraphValidationMissingNode(t *testing.T) {
 	_, err := NewGraph[TestState]("test-graph").
 		AddNode("step1", func(ctx context.Context, s TestState) (TestState, error) {
@@ -352,6 +425,7 @@ func TestEventTypeString(t *testing.T) {
 		{EventTypeNodeEnd, "node_end"},
 		{EventTypeError, "error"},
 		{EventTypeEnd, "end"},
+		{EventTypeNodeRetry, "node_retry"},
 		{EventType(99), "unknown"},
 	}
 
@@ -3,9 +3,12 @@ package graph
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // InterruptType 中断类型
@@ -583,7 +586,7 @@ func (h *HumanInTheLoop[S]) RunWithInterrupt(ctx context.Context, threadID strin
 		// 获取节点
 		node, ok := h.graph.Nodes[currentNode]
 		if !ok {
-			return state, nil, fmt.Errorf("node %s not found", currentNode)
+			return state, nil, fmt.Errorf("node %s %w", currentNode, core.ErrNotFound)
 		}
 
 		// 检查节点是否需要中断
@@ -618,9 +621,12 @@ func (h *HumanInTheLoop[S]) RunWithInterrupt(ctx context.Context, threadID strin
 			}
 		}
 
-		// 执行节点
-		newState, err := node.Handler(ctx, state)
+		// 执行节点（超时仅遵循 Node.Timeout）
+		newState, err := executeNodeWithRetry(ctx, &runConfig{}, currentNode, node, state, nil)
 		if err != nil {
+			if errors.Is(err, ErrNodeTimeout) {
+				return state, nil, err
+			}
 			return state, nil, fmt.Errorf("node %s failed: %w", currentNode, err)
 		}
 		state = newState
@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hexagon-codes/hexagon/internal/pool"
+)
+
+// ============== Map 节点 ==============
+
+// MapErrorPolicy 单个元素处理失败时的策略
+type MapErrorPolicy int
+
+const (
+	// MapFailFast 任一元素失败时取消其余元素，节点以该错误失败（默认）
+	MapFailFast MapErrorPolicy = iota
+
+	// MapSkipFailed 跳过失败的元素，collect 只收到成功元素的结果（保持原顺序）
+	MapSkipFailed
+)
+
+// defaultMapConcurrency Map 节点默认的最大并发数
+const defaultMapConcurrency = 10
+
+// MapOption Map 节点配置选项
+type MapOption func(*mapConfig)
+
+type mapConfig struct {
+	concurrency int
+	policy      MapErrorPolicy
+	onError     func(index int, err error)
+}
+
+// WithMapConcurrency 设置同时处理的最大元素数，默认 10
+func WithMapConcurrency(n int) MapOption {
+	return func(c *mapConfig) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithMapErrorPolicy 设置元素处理失败时的策略，默认 MapFailFast
+func WithMapErrorPolicy(policy MapErrorPolicy) MapOption {
+	return func(c *mapConfig) {
+		c.policy = policy
+	}
+}
+
+// WithMapOnError 设置元素处理失败时的回调，可用于记录 MapSkipFailed 跳过的元素
+// 回调可能被并发调用
+func WithMapOnError(fn func(index int, err error)) MapOption {
+	return func(c *mapConfig) {
+		c.onError = fn
+	}
+}
+
+// MapNode 创建对状态中集合逐个元素处理的节点
+//
+// extract 从状态中取出待处理的元素，process 以有限并发处理每个元素，
+// collect 将按元素顺序排列的结果写回状态。process 只接收元素，不能访问或修改状态。
+// 元素由 pool.Run 并发处理，process 中的 panic 被恢复并视为该元素失败。
+//
+// 示例：
+//
+//	node := graph.MapNode("analyze_docs",
+//	    func(s DocState) []rag.Document { return s.Retrieved },
+//	    func(ctx context.Context, doc rag.Document) (Summary, error) { return summarize(ctx, doc) },
+//	    func(s DocState, summaries []Summary) DocState { s.Summaries = summaries; return s },
+//	    graph.WithMapConcurrency(4),
+//	    graph.WithMapErrorPolicy(graph.MapSkipFailed),
+//	)
+func MapNode[S State, Item, Result any](
+	name string,
+	extract func(S) []Item,
+	process func(ctx context.Context, item Item) (Result, error),
+	collect func(S, []Result) S,
+	opts ...MapOption,
+) *Node[S] {
+	cfg := &mapConfig{concurrency: defaultMapConcurrency}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	handler := func(ctx context.Context, state S) (S, error) {
+		items := extract(state)
+		if len(items) == 0 {
+			return collect(state, nil), nil
+		}
+
+		// 按下标分发，便于回调和跳过时定位元素
+		indices := make([]int, len(items))
+		for i := range indices {
+			indices[i] = i
+		}
+		failed := make([]bool, len(items))
+		results, err := pool.Run(ctx, indices, cfg.concurrency, func(ctx context.Context, i int) (Result, error) {
+			result, err := process(ctx, items[i])
+			if err != nil {
+				failed[i] = true
+				if cfg.onError != nil {
+					cfg.onError(i, err)
+				}
+			}
+			return result, err
+		}, pool.WithStopOnError(cfg.policy == MapFailFast))
+
+		// 调用方取消或超时
+		if ctx.Err() != nil {
+			return state, context.Cause(ctx)
+		}
+
+		if cfg.policy == MapFailFast {
+			var itemErr *pool.ItemError
+			if errors.As(err, &itemErr) {
+				return state, fmt.Errorf("map node %s: item %d failed: %w", name, itemErr.Index, itemErr.Err)
+			}
+			return collect(state, results), nil
+		}
+
+		// worker 中的 panic 由 pool 恢复为错误，未经过上面的标记
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				var itemErr *pool.ItemError
+				if errors.As(e, &itemErr) && !failed[itemErr.Index] {
+					failed[itemErr.Index] = true
+					if cfg.onError != nil {
+						cfg.onError(itemErr.Index, itemErr.Err)
+					}
+				}
+			}
+		}
+
+		collected := make([]Result, 0, len(items))
+		for i, result := range results {
+			if !failed[i] {
+				collected = append(collected, result)
+			}
+		}
+		return collect(state, collected), nil
+	}
+
+	return &Node[S]{
+		Name:    name,
+		Type:    NodeTypeParallel,
+		Handler: handler,
+		Metadata: map[string]any{
+			"map_concurrency": cfg.concurrency,
+			"map_policy":      cfg.policy,
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type docState struct {
+	Docs      []string
+	Summaries []string
+}
+
+func (s docState) Clone() State {
+	return docState{Docs: slices.Clone(s.Docs), Summaries: slices.Clone(s.Summaries)}
+}
+
+func TestMapNode(t *testing.T) {
+	var running, peak atomic.Int32
+	node := MapNode("summarize",
+		func(s docState) []string { return s.Docs },
+		func(ctx context.Context, doc string) (string, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return strings.ToUpper(doc), nil
+		},
+		func(s docState, summaries []string) docState {
+			s.Summaries = summaries
+			return s
+		},
+		WithMapConcurrency(2),
+	)
+
+	g := NewGraph[docState]("map").
+		AddNodeWithBuilder(node).
+		AddEdge(START, "summarize").
+		AddEdge("summarize", END).
+		MustBuild()
+
+	result, err := g.Run(context.Background(), docState{Docs: []string{"a", "b", "c", "d", "e"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"A", "B", "C", "D", "E"}; !slices.Equal(result.Summaries, want) {
+		t.Errorf("Summaries = %v, want %v", result.Summaries, want)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("expected at most 2 concurrent items, got %d", p)
+	}
+}
+
+func TestMapNode_ErrorPolicy(t *testing.T) {
+	errBad := errors.New("bad doc")
+	process := func(ctx context.Context, doc string) (string, error) {
+		if doc == "bad" {
+			return "", errBad
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+		return doc + "!", nil
+	}
+	collect := func(s docState, summaries []string) docState {
+		s.Summaries = summaries
+		return s
+	}
+	state := docState{Docs: []string{"a", "bad", "c"}}
+
+	failFast := MapNode("fail_fast", func(s docState) []string { return s.Docs }, process, collect)
+	_, err := failFast.Handler(context.Background(), state)
+	if !errors.Is(err, errBad) || !strings.Contains(err.Error(), "item 1") {
+		t.Errorf("expected item 1 error, got %v", err)
+	}
+
+	var skipped []int
+	skip := MapNode("skip", func(s docState) []string { return s.Docs }, process, collect,
+		WithMapErrorPolicy(MapSkipFailed),
+		WithMapConcurrency(1),
+		WithMapOnError(func(index int, err error) { skipped = append(skipped, index) }),
+	)
+	result, err := skip.Handler(context.Background(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a!", "c!"}; !slices.Equal(result.Summaries, want) {
+		t.Errorf("Summaries = %v, want %v", result.Summaries, want)
+	}
+	if !slices.Equal(skipped, []int{1}) {
+		t.Errorf("expected item 1 to be reported, got %v", skipped)
+	}
+
+	// 元素处理 panic 时按失败处理
+	skipped = nil
+	panicky := MapNode("panic", func(s docState) []string { return s.Docs },
+		func(ctx context.Context, doc string) (string, error) {
+			if doc == "bad" {
+				panic("boom")
+			}
+			return doc + "!", nil
+		}, collect,
+		WithMapErrorPolicy(MapSkipFailed),
+		WithMapOnError(func(index int, err error) { skipped = append(skipped, index) }),
+	)
+	result, err = panicky.Handler(context.Background(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a!", "c!"}; !slices.Equal(result.Summaries, want) || !slices.Equal(skipped, []int{1}) {
+		t.Errorf("Summaries = %v, skipped = %v", result.Summaries, skipped)
+	}
+
+	// 调用方取消时节点返回取消原因
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := skip.Handler(ctx, state); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package graph
 import (
 	"context"
 	"fmt"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // NodeType 节点类型
@@ -29,6 +31,10 @@ const (
 	NodeTypeFallback
 	// NodeTypeLoop 循环节点
 	NodeTypeLoop
+	// NodeTypeAgent Agent 节点
+	NodeTypeAgent
+	// NodeTypeRetriever 检索节点
+	NodeTypeRetriever
 )
 
 // Node 图节点
@@ -42,12 +48,18 @@ type Node[S State] struct {
 	// Handler 节点处理函数
 	Handler NodeHandler[S]
 
+	// Updates 返回部分状态更新的处理函数，Handler 为空时由图在构建时生成 Handler
+	Updates []UpdateHandler[S]
+
 	// Metadata 节点元数据
 	Metadata map[string]any
 
 	// RetryPolicy 重试策略
 	RetryPolicy *RetryPolicy
 
+	// Retry 节点失败重试配置（WithNodeRetry），优先于 RetryPolicy
+	Retry *core.RetryConfig
+
 	// Timeout 超时时间（毫秒）
 	Timeout int64
 }
@@ -137,6 +149,7 @@ func (b *NodeBuilder[S]) WithRetry(policy *RetryPolicy) *NodeBuilder[S] {
 }
 
 // WithTimeout 设置超时时间（毫秒）
+// 优先于运行选项 WithNodeTimeout 设置的默认节点超时
 func (b *NodeBuilder[S]) WithTimeout(ms int64) *NodeBuilder[S] {
 	b.node.Timeout = ms
 	return b
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/interrupt"
+)
+
+// NodeOption AddNode 的节点选项
+type NodeOption func(*nodeOptions)
+
+// nodeOptions 节点选项集合
+type nodeOptions struct {
+	retry *core.RetryConfig
+}
+
+// WithNodeRetry 节点失败时按 config 退避重试，重试耗尽后图才以该错误终止
+//
+// config.RetryOn 为 nil 时重试所有错误；中断信号和整图超时/取消不会重试。
+// 每次重试前调用 config.OnRetry，Stream 会发出 EventTypeNodeRetry 事件，
+// StreamRun 的 StreamModeDebug 会发出 EventNodeRetry 事件。
+// Run、Stream、StreamRun、CheckpointRunner、HumanInTheLoop 和 Pregel 执行器均遵循该配置。
+// 与节点超时同时使用时，超时作用于每一次尝试。
+func WithNodeRetry(config core.RetryConfig) NodeOption {
+	return func(o *nodeOptions) {
+		o.retry = &config
+	}
+}
+
+// retryConfig 返回节点的重试配置
+// 优先使用 WithNodeRetry 设置的配置，其次转换 RetryPolicy（RetryNode / NodeBuilder.WithRetry）
+func (n *Node[S]) retryConfig() *core.RetryConfig {
+	if n.Retry != nil {
+		return n.Retry
+	}
+	if p := n.RetryPolicy; p != nil {
+		return &core.RetryConfig{
+			MaxRetries:   p.MaxRetries,
+			InitialDelay: time.Duration(p.InitialDelay) * time.Millisecond,
+			MaxDelay:     time.Duration(p.MaxDelay) * time.Millisecond,
+			Multiplier:   p.Multiplier,
+			RetryOn:      p.RetryOn,
+		}
+	}
+	return nil
+}
+
+// retryFunc 节点重试通知，attempt 为即将进行的重试序号（从 1 开始）
+type retryFunc func(attempt int, err error, delay time.Duration)
+
+// executeNodeWithRetry 执行节点，失败时按节点重试配置退避重试
+func executeNodeWithRetry[S State](ctx context.Context, config *runConfig, name string, node *Node[S], state S, onRetry retryFunc) (S, error) {
+	return executeNodeWithPolicy(ctx, config, name, node, state, node.retryConfig(), onRetry)
+}
+
+// executeNodeWithPolicy 执行节点，失败时按 retry 退避重试，retry 为 nil 时不重试
+func executeNodeWithPolicy[S State](ctx context.Context, config *runConfig, name string, node *Node[S], state S, retry *core.RetryConfig, onRetry retryFunc) (S, error) {
+	if retry == nil {
+		return executeNode(ctx, config, name, node, state)
+	}
+
+	delay := retry.InitialDelay
+	for attempt := 0; ; attempt++ {
+		newState, err := executeNode(ctx, config, name, node, state)
+		if err == nil {
+			return newState, nil
+		}
+		if attempt >= retry.MaxRetries || !shouldRetryNode(ctx, retry, err) {
+			return newState, err
+		}
+
+		wait := jitterDelay(delay, retry.Jitter)
+		if retry.OnRetry != nil {
+			retry.OnRetry(attempt, err)
+		}
+		if onRetry != nil {
+			onRetry(attempt+1, err, wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return state, ctxError(ctx, config)
+		case <-time.After(wait):
+		}
+
+		if retry.Multiplier > 0 {
+			delay = time.Duration(float64(delay) * retry.Multiplier)
+		}
+		if retry.MaxDelay > 0 && delay > retry.MaxDelay {
+			delay = retry.MaxDelay
+		}
+	}
+}
+
+// shouldRetryNode 判断节点错误是否应重试
+func shouldRetryNode(ctx context.Context, retry *core.RetryConfig, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if _, ok := interrupt.IsInterruptSignal(err); ok {
+		return false
+	}
+	return retry.RetryOn == nil || retry.RetryOn(err)
+}
+
+// jitterDelay 在 delay 上叠加 ±jitter 比例的随机抖动
+func jitterDelay(delay time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 || delay <= 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 + jitter*(2*rand.Float64()-1)))
+}
@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
+)
+
+var errTransient = errors.New("transient")
+
+// flakyNode 前 failures 次调用返回 errTransient
+func flakyNode(failures int, calls *int) NodeHandler[TestState] {
+	return func(ctx context.Context, s TestState) (TestState, error) {
+		*calls++
+		if *calls <= failures {
+			return s, errTransient
+		}
+		s.Counter++
+		return s, nil
+	}
+}
+
+func TestGraphNodeRetry(t *testing.T) {
+	calls := 0
+	var retried []int
+	g := NewGraph[TestState]("retry").
+		AddNode("flaky", flakyNode(2, &calls), WithNodeRetry(core.RetryConfig{
+			MaxRetries:   3,
+			InitialDelay: time.Millisecond,
+			Multiplier:   2,
+			OnRetry:      func(attempt int, err error) { retried = append(retried, attempt) },
+		})).
+		AddEdge(START, "flaky").
+		AddEdge("flaky", END).
+		MustBuild()
+
+	state, err := g.Run(context.Background(), TestState{})
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls != 3 || state.Counter != 1 {
+		t.Errorf("expected 3 calls and counter 1, got %d/%d", calls, state.Counter)
+	}
+	if len(retried) != 2 {
+		t.Errorf("expected OnRetry twice, got %v", retried)
+	}
+}
+
+func TestGraphNodeRetryExhausted(t *testing.T) {
+	calls := 0
+	g := NewGraph[TestState]("retry-exhausted").
+		AddNode("flaky", flakyNode(10, &calls), WithNodeRetry(core.RetryConfig{MaxRetries: 2})).
+		AddEdge(START, "flaky").
+		AddEdge("flaky", END).
+		MustBuild()
+
+	_, err := g.Run(context.Background(), TestState{})
+	if !errors.Is(err, errTransient) {
+		t.Fatalf("expected transient error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 1 attempt + 2 retries, got %d", calls)
+	}
+}
+
+func TestGraphNodeRetryOn(t *testing.T) {
+	calls := 0
+	g := NewGraph[TestState]("retry-on").
+		AddNode("flaky", flakyNode(10, &calls), WithNodeRetry(core.RetryConfig{
+			MaxRetries: 5,
+			RetryOn:    func(err error) bool { return !errors.Is(err, errTransient) },
+		})).
+		AddEdge(START, "flaky").
+		AddEdge("flaky", END).
+		MustBuild()
+
+	if _, err := g.Run(context.Background(), TestState{}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("expected no retries for filtered error, got %d calls", calls)
+	}
+}
+
+func TestGraphNodeRetryPolicy(t *testing.T) {
+	calls := 0
+	g := NewGraph[TestState]("retry-policy").
+		AddNodeWithBuilder(RetryNode("flaky", flakyNode(1, &calls), &RetryPolicy{MaxRetries: 1, InitialDelay: 1})).
+		AddEdge(START, "flaky").
+		AddEdge("flaky", END).
+		MustBuild()
+
+	if _, err := g.Run(context.Background(), TestState{}); err != nil {
+		t.Fatalf("expected RetryNode policy to be honored, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestGraphStreamNodeRetryEvents(t *testing.T) {
+	calls := 0
+	g := NewGraph[TestState]("retry-stream").
+		AddNode("flaky", flakyNode(2, &calls), WithNodeRetry(core.RetryConfig{MaxRetries: 3})).
+		AddEdge(START, "flaky").
+		AddEdge("flaky", END).
+		MustBuild()
+
+	events, err := g.Stream(context.Background(), TestState{})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	var attempts []int
+	var last StreamEvent[TestState]
+	for evt := range events {
+		if evt.Type == EventTypeNodeRetry {
+			if evt.NodeName != "flaky" || !errors.Is(evt.Error, errTransient) {
+				t.Errorf("unexpected retry event %+v", evt)
+			}
+			attempts = append(attempts, evt.Metadata["attempt"].(int))
+		}
+		last = evt
+	}
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("expected retry events for attempts 1 and 2, got %v", attempts)
+	}
+	if last.Type != EventTypeEnd || last.State.Counter != 1 {
+		t.Errorf("expected successful end, got %+v", last)
+	}
+}
+
+func TestExecutorsNodeRetry(t *testing.T) {
+	runs := map[string]func(g *Graph[TestState]) error{
+		"checkpoint runner": func(g *Graph[TestState]) error {
+			config := DefaultCheckpointRunnerConfig()
+			config.MaxRetries = 0
+			_, err := NewCheckpointRunner(g, NewMemoryEnhancedCheckpointSaver(), config).Run(context.Background(), "thread", TestState{})
+			return err
+		},
+		"human in the loop": func(g *Graph[TestState]) error {
+			_, _, err := NewHumanInTheLoop(g, NewMemoryInterruptHandler(), NewMemoryCheckpointSaver()).
+				RunWithInterrupt(context.Background(), "thread", TestState{})
+			return err
+		},
+		"pregel": func(g *Graph[TestState]) error {
+			_, _, err := g.RunPregelMode(context.Background(), TestState{})
+			return err
+		},
+		"pregel sequential": func(g *Graph[TestState]) error {
+			_, _, err := g.RunPregelMode(context.Background(), TestState{}, WithParallelExecution(false))
+			return err
+		},
+		"stream run": func(g *Graph[TestState]) error {
+			ch, err := g.StreamRun(context.Background(), TestState{}, WithStreamMode(StreamModeDebug))
+			if err != nil {
+				return err
+			}
+			retries := 0
+			for evt := range ch.Events() {
+				switch evt.Type {
+				case EventNodeRetry:
+					retries++
+				case EventNodeError:
+					return fmt.Errorf("node error: %v", evt.Data)
+				}
+			}
+			if retries != 1 {
+				return fmt.Errorf("expected 1 retry event, got %d", retries)
+			}
+			return nil
+		},
+	}
+	for name, run := range runs {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			g := NewGraph[TestState]("executor-retry").
+				AddNode("flaky", flakyNode(1, &calls), WithNodeRetry(core.RetryConfig{MaxRetries: 2, InitialDelay: time.Millisecond})).
+				AddEdge(START, "flaky").
+				AddEdge("flaky", END).
+				MustBuild()
+			if err := run(g); err != nil {
+				t.Fatalf("expected success after retry, got %v", err)
+			}
+			if calls != 2 {
+				t.Errorf("expected 2 calls, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestCheckpointRunnerMaxRetriesPolicy(t *testing.T) {
+	calls := 0
+	g := NewGraph[TestState]("checkpoint-retry").
+		AddNode("flaky", flakyNode(10, &calls)).
+		AddEdge(START, "flaky").
+		AddEdge("flaky", END).
+		MustBuild()
+
+	config := DefaultCheckpointRunnerConfig()
+	config.MaxRetries = 2
+	config.RetryDelay = time.Millisecond
+	_, err := NewCheckpointRunner(g, NewMemoryEnhancedCheckpointSaver(), config).Run(context.Background(), "thread", TestState{})
+	if !errors.Is(err, errTransient) || calls != 3 {
+		t.Fatalf("expected 3 attempts then errTransient, got %d calls, err %v", calls, err)
+	}
+}
@@ -0,0 +1,268 @@
+// Package graph 提供 Hexagon AI Agent 框架的图编排引擎
+//
+// observer.go 实现执行观察者，用于调试界面等实时展示执行进度：
+//   - Topology: 图的节点拓扑（附带 Mermaid / DOT 导出）
+//   - ExecutionObserver: 运行开始、节点状态变化、运行结束的回调
+//   - WithEventBus: 将同样的生命周期发布为 events.Lifecycle 事件
+package graph
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/events"
+	"github.com/hexagon-codes/hexagon/internal/util"
+)
+
+// NodeStatus 节点执行状态
+type NodeStatus string
+
+const (
+	// NodeStatusPending 尚未执行
+	NodeStatusPending NodeStatus = "pending"
+
+	// NodeStatusRunning 正在执行
+	NodeStatusRunning NodeStatus = "running"
+
+	// NodeStatusDone 执行完成
+	NodeStatusDone NodeStatus = "done"
+
+	// NodeStatusFailed 执行失败
+	NodeStatusFailed NodeStatus = "failed"
+)
+
+// Topology 图的节点拓扑
+type Topology struct {
+	// Name 图名称
+	Name string `json:"name"`
+
+	// EntryPoint 入口节点
+	EntryPoint string `json:"entry_point"`
+
+	// Nodes 节点列表（按 ID 排序，包含 START 和 END）
+	Nodes []TopologyNode `json:"nodes"`
+
+	// Edges 边列表，包含条件边的所有可能目标
+	Edges []TopologyEdge `json:"edges"`
+
+	// Mermaid Mermaid 格式导出
+	Mermaid string `json:"mermaid"`
+
+	// DOT Graphviz DOT 格式导出
+	DOT string `json:"dot"`
+}
+
+// TopologyNode 拓扑中的节点
+type TopologyNode struct {
+	// ID 节点 ID（即 AddNode 时的名称）
+	ID string `json:"id"`
+
+	// Label 显示名称
+	Label string `json:"label"`
+
+	// Kind 节点种类：start、end 或 node
+	Kind string `json:"kind"`
+}
+
+// TopologyEdge 拓扑中的边
+type TopologyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Label 条件边的路由标签
+	Label string `json:"label,omitempty"`
+
+	// Conditional 是否为条件边
+	Conditional bool `json:"conditional,omitempty"`
+}
+
+// Topology 返回图的节点拓扑
+// 入口点不是通过 START 的边设置时，补充一条 START 到入口点的边
+func (g *Graph[S]) Topology() Topology {
+	t := Topology{
+		Name:       g.Name,
+		EntryPoint: g.EntryPoint,
+		Mermaid:    g.Export(FormatMermaid),
+		DOT:        g.Export(FormatDOT),
+	}
+
+	for id, node := range g.Nodes {
+		tn := TopologyNode{ID: id, Label: id, Kind: "node"}
+		switch id {
+		case START:
+			tn.Label, tn.Kind = "开始", "start"
+		case END:
+			tn.Label, tn.Kind = "结束", "end"
+		default:
+			if node.Name != "" {
+				tn.Label = node.Name
+			}
+		}
+		t.Nodes = append(t.Nodes, tn)
+	}
+	sort.Slice(t.Nodes, func(i, j int) bool { return t.Nodes[i].ID < t.Nodes[j].ID })
+
+	hasEntryEdge := false
+	for _, edge := range g.Edges {
+		t.Edges = append(t.Edges, TopologyEdge{From: edge.From, To: edge.To})
+		if edge.From == START && edge.To == g.EntryPoint {
+			hasEntryEdge = true
+		}
+	}
+	if g.EntryPoint != "" && !hasEntryEdge {
+		t.Edges = append([]TopologyEdge{{From: START, To: g.EntryPoint}}, t.Edges...)
+	}
+
+	froms := make([]string, 0, len(g.conditionalEdges))
+	for from := range g.conditionalEdges {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+	for _, from := range froms {
+		for _, cond := range g.conditionalEdges[from] {
+			labels := make([]string, 0, len(cond.edges))
+			for label := range cond.edges {
+				labels = append(labels, label)
+			}
+			sort.Strings(labels)
+			for _, label := range labels {
+				t.Edges = append(t.Edges, TopologyEdge{
+					From: from, To: cond.edges[label], Label: label, Conditional: true,
+				})
+			}
+		}
+	}
+	return t
+}
+
+// NodeStatusUpdate 节点状态变化
+type NodeStatusUpdate struct {
+	// Node 节点 ID
+	Node string
+
+	// Status 新状态
+	Status NodeStatus
+
+	// Duration 节点执行耗时，仅 done 和 failed 时有效
+	Duration time.Duration
+
+	// Error 失败原因，仅 failed 时有效
+	Error error
+}
+
+// ExecutionObserver 图执行观察者
+//
+// 回调在执行 goroutine 中同步调用，实现应尽快返回，不应阻塞执行。
+// 屏障（AddBarrierNode）的并行分支中 OnNodeStatus 可能被并发调用。
+type ExecutionObserver interface {
+	// OnGraphStart 运行开始，topology 为本次运行的图拓扑
+	OnGraphStart(ctx context.Context, runID string, topology Topology)
+
+	// OnNodeStatus 节点状态变化
+	OnNodeStatus(ctx context.Context, runID string, update NodeStatusUpdate)
+
+	// OnGraphEnd 运行结束，err 为 nil 表示成功
+	OnGraphEnd(ctx context.Context, runID string, err error)
+}
+
+// WithObserver 设置执行观察者，Run 和 Stream 在运行开始、节点状态变化和运行结束时回调
+func WithObserver(observer ExecutionObserver) RunOption {
+	return func(c *runConfig) {
+		c.observer = observer
+	}
+}
+
+// WithRunID 设置运行 ID，传给执行观察者；未设置时自动生成
+func WithRunID(runID string) RunOption {
+	return func(c *runConfig) {
+		c.runID = runID
+	}
+}
+
+// WithEventBus 将运行的生命周期事件发布到事件总线
+//
+// 运行开始和结束发布 RunStarted / RunCompleted / RunFailed，节点状态变化发布
+// StepStarted / StepCompleted / StepFailed，Target 为节点 ID。可与 WithObserver 同时使用。
+func WithEventBus(bus *events.Bus[events.Lifecycle]) RunOption {
+	return func(c *runConfig) {
+		c.bus = bus
+	}
+}
+
+// runObserver 单次运行的观察者通知，为 nil 或未设置观察者和事件总线时所有方法为空操作
+type runObserver struct {
+	observer  ExecutionObserver
+	bus       *events.Bus[events.Lifecycle]
+	graphName string
+	runID     string
+}
+
+// newRunObserver 创建运行观察者并通知运行开始
+func newRunObserver[S State](ctx context.Context, g *Graph[S], config *runConfig) *runObserver {
+	o := &runObserver{observer: config.observer, bus: config.bus, graphName: g.Name, runID: config.runID}
+	if o.observer == nil && o.bus == nil {
+		return o
+	}
+	if o.runID == "" {
+		o.runID = util.GenerateID("run")
+	}
+	if o.observer != nil {
+		o.observer.OnGraphStart(ctx, o.runID, g.Topology())
+	}
+	o.publish(ctx, events.Lifecycle{Type: events.RunStarted})
+	return o
+}
+
+// nodeRunning 通知节点开始执行，返回开始时间
+func (o *runObserver) nodeRunning(ctx context.Context, node string) time.Time {
+	if o != nil && o.observer != nil {
+		o.observer.OnNodeStatus(ctx, o.runID, NodeStatusUpdate{Node: node, Status: NodeStatusRunning})
+	}
+	o.publish(ctx, events.Lifecycle{Type: events.StepStarted, Target: node})
+	return time.Now()
+}
+
+// nodeFinished 通知节点执行结束，err 不为 nil 时为失败
+func (o *runObserver) nodeFinished(ctx context.Context, node string, start time.Time, err error) {
+	if o == nil || (o.observer == nil && o.bus == nil) {
+		return
+	}
+	update := NodeStatusUpdate{Node: node, Status: NodeStatusDone, Duration: time.Since(start)}
+	if err != nil {
+		update.Status, update.Error = NodeStatusFailed, err
+	}
+	if o.observer != nil {
+		o.observer.OnNodeStatus(ctx, o.runID, update)
+	}
+
+	event := events.Lifecycle{Type: events.StepCompleted, Target: node, Duration: update.Duration, Err: err}
+	if err != nil {
+		event.Type = events.StepFailed
+	}
+	o.publish(ctx, event)
+}
+
+// graphEnd 通知运行结束
+func (o *runObserver) graphEnd(ctx context.Context, err error) {
+	if o != nil && o.observer != nil {
+		o.observer.OnGraphEnd(ctx, o.runID, err)
+	}
+	event := events.Lifecycle{Type: events.RunCompleted, Err: err}
+	if err != nil {
+		event.Type = events.RunFailed
+	}
+	o.publish(ctx, event)
+}
+
+// publish 补全来源、图名称和运行 ID 后发布到事件总线
+func (o *runObserver) publish(ctx context.Context, event events.Lifecycle) {
+	if o == nil || o.bus == nil {
+		return
+	}
+	event.Source = events.SourceGraph
+	event.Name = o.graphName
+	event.RunID = o.runID
+	event.Timestamp = time.Now()
+	o.bus.Publish(ctx, event)
+}
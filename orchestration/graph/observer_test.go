@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/events"
+)
+
+// recordingObserver 记录观察者回调
+type recordingObserver struct {
+	mu       sync.Mutex
+	runID    string
+	topology Topology
+	updates  []string
+	endErr   error
+	ended    bool
+}
+
+func (o *recordingObserver) OnGraphStart(ctx context.Context, runID string, topology Topology) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.runID, o.topology = runID, topology
+}
+
+func (o *recordingObserver) OnNodeStatus(ctx context.Context, runID string, update NodeStatusUpdate) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.updates = append(o.updates, fmt.Sprintf("%s:%s", update.Node, update.Status))
+}
+
+func (o *recordingObserver) OnGraphEnd(ctx context.Context, runID string, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.endErr, o.ended = err, true
+}
+
+// TestTopology 测试拓扑包含节点、普通边和条件边
+func TestTopology(t *testing.T) {
+	g := buildConditionalGraph(t)
+	topo := g.Topology()
+
+	if topo.Name != "conditional-viz" || topo.EntryPoint != "check" {
+		t.Errorf("Name/EntryPoint = %q/%q", topo.Name, topo.EntryPoint)
+	}
+	ids := make(map[string]string)
+	for _, n := range topo.Nodes {
+		ids[n.ID] = n.Kind
+	}
+	if len(ids) != 5 || ids[START] != "start" || ids[END] != "end" || ids["check"] != "node" {
+		t.Errorf("Nodes = %+v", topo.Nodes)
+	}
+
+	var conditional int
+	for _, e := range topo.Edges {
+		if e.Conditional {
+			conditional++
+			if e.From != "check" || (e.Label == "yes" && e.To != "path_a") {
+				t.Errorf("条件边 = %+v", e)
+			}
+		}
+	}
+	if conditional != 2 {
+		t.Errorf("条件边数量 = %d, 期望 2", conditional)
+	}
+	if topo.Mermaid == "" || topo.DOT == "" {
+		t.Error("应包含 Mermaid 和 DOT 导出")
+	}
+}
+
+// TestObserver_Run 测试 Run 的观察者回调
+func TestObserver_Run(t *testing.T) {
+	g := buildSimpleGraph(t)
+	obs := &recordingObserver{}
+
+	if _, err := g.Run(context.Background(), TestState{}, WithObserver(obs), WithRunID("run-1")); err != nil {
+		t.Fatalf("Run 失败: %v", err)
+	}
+	if obs.runID != "run-1" || len(obs.topology.Nodes) != 4 {
+		t.Errorf("runID = %q, topology = %+v", obs.runID, obs.topology)
+	}
+	want := "[A:running A:done B:running B:done]"
+	if got := fmt.Sprint(obs.updates); got != want {
+		t.Errorf("updates = %s, 期望 %s", got, want)
+	}
+	if !obs.ended || obs.endErr != nil {
+		t.Errorf("ended = %v, endErr = %v", obs.ended, obs.endErr)
+	}
+}
+
+// TestObserver_Failure 测试节点失败时的回调（Run 与 Stream）
+func TestObserver_Failure(t *testing.T) {
+	boom := errors.New("boom")
+	g, err := NewGraph[TestState]("failing").
+		AddNode("ok", func(ctx context.Context, s TestState) (TestState, error) { return s, nil }).
+		AddNode("bad", func(ctx context.Context, s TestState) (TestState, error) { return s, boom }).
+		AddEdge(START, "ok").
+		AddEdge("ok", "bad").
+		AddEdge("bad", END).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs := &recordingObserver{}
+	if _, err := g.Run(context.Background(), TestState{}, WithObserver(obs)); !errors.Is(err, boom) {
+		t.Fatalf("Run err = %v", err)
+	}
+	if obs.runID == "" {
+		t.Error("未设置 WithRunID 时应自动生成运行 ID")
+	}
+	want := "[ok:running ok:done bad:running bad:failed]"
+	if got := fmt.Sprint(obs.updates); got != want {
+		t.Errorf("Run updates = %s, 期望 %s", got, want)
+	}
+	if !errors.Is(obs.endErr, boom) {
+		t.Errorf("Run endErr = %v", obs.endErr)
+	}
+
+	obs = &recordingObserver{}
+	events, err := g.Stream(context.Background(), TestState{}, WithObserver(obs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range events {
+	}
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if got := fmt.Sprint(obs.updates); got != want {
+		t.Errorf("Stream updates = %s, 期望 %s", got, want)
+	}
+	if !obs.ended || !errors.Is(obs.endErr, boom) {
+		t.Errorf("Stream ended = %v, endErr = %v", obs.ended, obs.endErr)
+	}
+}
+
+// TestWithEventBus 测试运行生命周期发布到事件总线
+func TestWithEventBus(t *testing.T) {
+	g := buildSimpleGraph(t)
+	bus := events.NewBus[events.Lifecycle]()
+
+	var mu sync.Mutex
+	var got []string
+	bus.Subscribe(func(ctx context.Context, e events.Lifecycle) {
+		mu.Lock()
+		defer mu.Unlock()
+		if e.Source != events.SourceGraph || e.RunID != "run-1" || e.Name != g.Name {
+			t.Errorf("event = %+v", e)
+		}
+		got = append(got, fmt.Sprintf("%s:%s", e.Target, e.Type))
+	})
+
+	if _, err := g.Run(context.Background(), TestState{}, WithEventBus(bus), WithRunID("run-1")); err != nil {
+		t.Fatalf("Run 失败: %v", err)
+	}
+	want := "[:run.started A:step.started A:step.completed B:step.started B:step.completed :run.completed]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("events = %v, 期望 %s", got, want)
+	}
+}
@@ -17,9 +17,13 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // ============== Pregel 触发模式 ==============
@@ -94,6 +98,10 @@ type PregelConfig struct {
 
 	// Debug 调试模式
 	Debug bool
+
+	// NodeTimeout 每个节点的默认超时时间，0 表示不限制
+	// 节点自身的 Node.Timeout 优先于此值
+	NodeTimeout time.Duration
 }
 
 // DefaultPregelConfig 返回默认的 Pregel 配置
@@ -147,6 +155,13 @@ func WithPregelDebug(debug bool) PregelOption {
 	}
 }
 
+// WithPregelNodeTimeout 设置每个节点的默认超时时间
+func WithPregelNodeTimeout(d time.Duration) PregelOption {
+	return func(c *PregelConfig) {
+		c.NodeTimeout = d
+	}
+}
+
 // PregelExecutorOption PregelExecutor 配置选项
 type PregelExecutorOption[S State] func(*PregelExecutor[S])
 
@@ -355,14 +370,14 @@ func (pe *PregelExecutor[S]) executeNodesParallel(ctx context.Context, nodes []s
 
 			node, ok := pe.graph.Nodes[name]
 			if !ok {
-				errCh <- fmt.Errorf("node %s not found", name)
+				errCh <- fmt.Errorf("node %s %w", name, core.ErrNotFound)
 				return
 			}
 
 			// 执行节点（使用基础状态的副本）
-			newState, err := node.Handler(ctx, baseState)
+			newState, err := executeNodeWithRetry(ctx, pe.runConfig(), name, node, baseState, nil)
 			if err != nil {
-				errCh <- fmt.Errorf("node %s failed: %w", name, err)
+				errCh <- pregelNodeError(name, err)
 				return
 			}
 
@@ -424,13 +439,13 @@ func (pe *PregelExecutor[S]) executeNodesSequential(ctx context.Context, nodes [
 
 		node, ok := pe.graph.Nodes[nodeName]
 		if !ok {
-			return fmt.Errorf("node %s not found", nodeName)
+			return fmt.Errorf("node %s %w", nodeName, core.ErrNotFound)
 		}
 
 		// 执行节点
-		newState, err := node.Handler(ctx, pe.state)
+		newState, err := executeNodeWithRetry(ctx, pe.runConfig(), nodeName, node, pe.state, nil)
 		if err != nil {
-			return fmt.Errorf("node %s failed: %w", nodeName, err)
+			return pregelNodeError(nodeName, err)
 		}
 
 		pe.mu.Lock()
@@ -443,6 +458,19 @@ func (pe *PregelExecutor[S]) executeNodesSequential(ctx context.Context, nodes [
 	return nil
 }
 
+// runConfig 返回执行单个节点所用的运行配置
+func (pe *PregelExecutor[S]) runConfig() *runConfig {
+	return &runConfig{nodeTimeout: pe.config.NodeTimeout}
+}
+
+// pregelNodeError 包装节点错误，超时错误已包含节点名，原样返回
+func pregelNodeError(name string, err error) error {
+	if errors.Is(err, ErrNodeTimeout) {
+		return err
+	}
+	return fmt.Errorf("node %s failed: %w", name, err)
+}
+
 // activateSuccessors 激活后继节点
 //
 // 安全说明：为避免死锁，此方法先在锁内获取状态副本，
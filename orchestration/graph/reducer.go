@@ -0,0 +1,222 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/hexagon-codes/hexagon/core"
+)
+
+// ============== 增量状态更新 ==============
+//
+// 默认情况下节点返回完整状态，并行分支各自持有 State.Clone() 的副本，
+// 状态较大时深拷贝开销明显。增量更新模式下节点只返回变更的字段（StateUpdate），
+// 并行分支共享同一个只读状态，图按字段注册的 StateReducer 将更新合并回状态，不再复制整个状态：
+//
+//	g, err := NewGraph[MapState]("analysis").
+//	    WithStateReducer("findings", ReduceAppend).
+//	    AddUpdateNode("load", loadHandler).
+//	    AddNodeWithBuilder(ParallelUpdateNode[MapState]("analyze", statsHandler, trendHandler)).
+//	    AddEdge(START, "load").
+//	    AddEdge("load", "analyze").
+//	    AddEdge("analyze", END).
+//	    Build()
+//
+// 状态类型需实现 FieldState（MapState 已实现）。
+
+// StateUpdate 节点返回的部分状态更新，键为字段名
+type StateUpdate map[string]any
+
+// UpdateHandler 返回部分状态更新的节点处理函数
+//
+// state 在并行分支间共享，处理函数只能读取，不能修改。
+type UpdateHandler[S State] func(ctx context.Context, state S) (StateUpdate, error)
+
+// FieldState 支持按字段读写的状态，增量更新模式需要状态实现此接口
+type FieldState interface {
+	State
+
+	// Get 获取字段值
+	Get(key string) (any, bool)
+
+	// Set 设置字段值
+	Set(key string, value any)
+}
+
+// StateReducer 字段合并函数，将更新值合并到字段的当前值
+// 字段不存在时 current 为 nil
+type StateReducer func(current, update any) any
+
+// ReduceLastWrite 覆盖式合并：更新值直接替换当前值，未注册 reducer 的字段使用此方式
+func ReduceLastWrite(current, update any) any {
+	return update
+}
+
+// ReduceAppend 追加式合并：将更新值追加到当前切片
+//
+// 更新值为切片时追加其全部元素，否则作为单个元素追加；
+// 当前值为空时直接使用更新值，元素类型不兼容时退化为覆盖。
+func ReduceAppend(current, update any) any {
+	if current == nil {
+		return update
+	}
+	if update == nil {
+		return current
+	}
+
+	cv, uv := reflect.ValueOf(current), reflect.ValueOf(update)
+	if cv.Kind() != reflect.Slice {
+		return update
+	}
+	elem := cv.Type().Elem()
+	if uv.Kind() != reflect.Slice {
+		if !uv.Type().AssignableTo(elem) {
+			return update
+		}
+		return reflect.Append(cv, uv).Interface()
+	}
+	if uv.Type().AssignableTo(cv.Type()) {
+		return reflect.AppendSlice(cv, uv).Interface()
+	}
+
+	out := reflect.MakeSlice(cv.Type(), cv.Len(), cv.Len()+uv.Len())
+	reflect.Copy(out, cv)
+	for i := range uv.Len() {
+		item := uv.Index(i)
+		if item.Kind() == reflect.Interface && !item.IsNil() {
+			item = item.Elem()
+		}
+		if !item.IsValid() || !item.Type().AssignableTo(elem) {
+			return update
+		}
+		out = reflect.Append(out, item)
+	}
+	return out.Interface()
+}
+
+// WithStateReducer 注册字段的合并函数，作用于增量更新节点（AddUpdateNode、ParallelUpdateNode）
+//
+// 未注册的字段使用 ReduceLastWrite。返回完整状态的普通节点不受影响。
+func (b *GraphBuilder[S]) WithStateReducer(field string, reducer StateReducer) *GraphBuilder[S] {
+	if b.err != nil {
+		return b
+	}
+	if reducer == nil {
+		b.err = fmt.Errorf("%w: reducer for field %s is nil", core.ErrInvalidInput, field)
+		return b
+	}
+	if b.graph.reducers == nil {
+		b.graph.reducers = make(map[string]StateReducer)
+	}
+	b.graph.reducers[field] = reducer
+	return b
+}
+
+// AddUpdateNode 添加返回部分状态更新的节点
+//
+// 节点的更新按 WithStateReducer 注册的合并函数原地应用到状态上，不复制整个状态。
+func (b *GraphBuilder[S]) AddUpdateNode(name string, handler UpdateHandler[S], opts ...NodeOption) *GraphBuilder[S] {
+	if b.err != nil {
+		return b
+	}
+	if handler == nil {
+		b.err = fmt.Errorf("%w: handler for node %s is nil", core.ErrInvalidInput, name)
+		return b
+	}
+	b.AddNode(name, nil, opts...)
+	if node, ok := b.graph.Nodes[name]; ok && b.err == nil {
+		node.Updates = []UpdateHandler[S]{handler}
+	}
+	return b
+}
+
+// ParallelUpdateNode 创建并行执行的增量更新节点
+//
+// 所有处理函数共享同一个只读状态并行执行，无需像 ParallelNode 那样为每个分支复制状态；
+// 全部成功后按处理函数的顺序将更新合并到状态，同一字段的多个更新按注册的 reducer 依次合并。
+// 节点需通过 AddNodeWithBuilder 加入图，合并函数在 Build 时绑定。
+func ParallelUpdateNode[S State](name string, handlers ...UpdateHandler[S]) *Node[S] {
+	return &Node[S]{
+		Name:    name,
+		Type:    NodeTypeParallel,
+		Updates: handlers,
+		Metadata: map[string]any{
+			"handler_count": len(handlers),
+		},
+	}
+}
+
+// bindUpdates 为增量更新节点生成处理函数
+func (g *Graph[S]) bindUpdates() error {
+	for name, node := range g.Nodes {
+		if node.Handler != nil || node.Updates == nil {
+			continue
+		}
+		var zero S
+		if _, ok := any(zero).(FieldState); !ok {
+			return fmt.Errorf("%w: node %s returns state updates but state type %T does not implement FieldState",
+				core.ErrInvalidInput, name, zero)
+		}
+		node.Handler = g.updateHandler(node.Updates)
+	}
+	return nil
+}
+
+// updateHandler 执行更新处理函数并按字段合并结果
+func (g *Graph[S]) updateHandler(handlers []UpdateHandler[S]) NodeHandler[S] {
+	return func(ctx context.Context, state S) (S, error) {
+		updates := make([]StateUpdate, len(handlers))
+		if len(handlers) == 1 {
+			update, err := handlers[0](ctx, state)
+			if err != nil {
+				return state, err
+			}
+			updates[0] = update
+		} else {
+			errs := make([]error, len(handlers))
+			done := make(chan struct{}, len(handlers))
+			for i, h := range handlers {
+				go func() {
+					updates[i], errs[i] = h(ctx, state)
+					done <- struct{}{}
+				}()
+			}
+			for range handlers {
+				<-done
+			}
+			for i, err := range errs {
+				if err != nil {
+					return state, fmt.Errorf("parallel execution failed at handler %d: %w", i, err)
+				}
+			}
+		}
+
+		fs := any(state).(FieldState)
+		for _, update := range updates {
+			g.applyUpdate(fs, update)
+		}
+		return state, nil
+	}
+}
+
+// applyUpdate 将一个更新合并到状态，字段按名称顺序处理
+func (g *Graph[S]) applyUpdate(fs FieldState, update StateUpdate) {
+	keys := make([]string, 0, len(update))
+	for k := range update {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		reducer, ok := g.reducers[k]
+		if !ok {
+			reducer = ReduceLastWrite
+		}
+		current, _ := fs.Get(k)
+		fs.Set(k, reducer(current, update[k]))
+	}
+}
+
+var _ FieldState = MapState(nil)
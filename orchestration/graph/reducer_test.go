@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestReduceAppend(t *testing.T) {
+	tests := []struct {
+		name    string
+		current any
+		update  any
+		want    any
+	}{
+		{"当前值为空", nil, []string{"a"}, []string{"a"}},
+		{"同类型切片", []string{"a"}, []string{"b", "c"}, []string{"a", "b", "c"}},
+		{"单个元素", []string{"a"}, "b", []string{"a", "b"}},
+		{"[]any 转换", []string{"a"}, []any{"b"}, []string{"a", "b"}},
+		{"追加到 []any", []any{1}, []int{2, 3}, []any{1, 2, 3}},
+		{"类型不兼容时覆盖", []string{"a"}, []int{1}, []int{1}},
+		{"当前值不是切片", 1, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReduceAppend(tt.current, tt.update); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReduceAppend(%v, %v) = %v, want %v", tt.current, tt.update, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_UpdateNodes(t *testing.T) {
+	g, err := NewGraph[MapState]("updates").
+		WithStateReducer("findings", ReduceAppend).
+		AddUpdateNode("load", func(ctx context.Context, s MapState) (StateUpdate, error) {
+			return StateUpdate{"rows": 3, "findings": []string{"loaded"}}, nil
+		}).
+		AddNodeWithBuilder(ParallelUpdateNode[MapState]("analyze",
+			func(ctx context.Context, s MapState) (StateUpdate, error) {
+				return StateUpdate{"findings": fmt.Sprintf("stats over %v rows", s["rows"]), "status": "stats"}, nil
+			},
+			func(ctx context.Context, s MapState) (StateUpdate, error) {
+				return StateUpdate{"findings": []string{"trend up"}, "status": "trend"}, nil
+			},
+		)).
+		AddEdge(START, "load").
+		AddEdge("load", "analyze").
+		AddEdge("analyze", END).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := g.Run(context.Background(), MapState{"findings": []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"loaded", "stats over 3 rows", "trend up"}
+	if got := result["findings"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+	// 未注册 reducer 的字段按处理函数顺序覆盖
+	if result["status"] != "trend" {
+		t.Errorf("status = %v, want trend", result["status"])
+	}
+}
+
+func TestGraph_UpdateNodeErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	g := NewGraph[MapState]("failing").
+		AddNodeWithBuilder(ParallelUpdateNode[MapState]("analyze",
+			func(ctx context.Context, s MapState) (StateUpdate, error) {
+				return StateUpdate{"a": 1}, nil
+			},
+			func(ctx context.Context, s MapState) (StateUpdate, error) {
+				return nil, errBoom
+			},
+		)).
+		AddEdge(START, "analyze").
+		AddEdge("analyze", END).
+		MustBuild()
+
+	state := MapState{}
+	if _, err := g.Run(context.Background(), state); !errors.Is(err, errBoom) {
+		t.Errorf("expected handler error, got %v", err)
+	}
+	if len(state) != 0 {
+		t.Errorf("failed node should not apply partial updates, got %v", state)
+	}
+
+	// 状态类型不支持按字段更新时构建失败
+	_, err := NewGraph[TestState]("unsupported").
+		AddUpdateNode("n", func(ctx context.Context, s TestState) (StateUpdate, error) { return nil, nil }).
+		AddEdge(START, "n").
+		AddEdge("n", END).
+		Build()
+	if err == nil {
+		t.Error("expected build error for state without FieldState")
+	}
+
+	if _, err := NewGraph[MapState]("nil").WithStateReducer("a", nil).Build(); err == nil {
+		t.Error("expected error for nil reducer")
+	}
+}
+
+// largeState 构造含大量原始数据的状态
+func largeState() MapState {
+	rows := make([]any, 10000)
+	for i := range rows {
+		rows[i] = map[string]any{"id": i, "value": float64(i) * 1.5, "label": "row"}
+	}
+	return MapState{"raw_data": rows, "findings": []any{}}
+}
+
+// BenchmarkParallel_Clone 并行分支各自复制完整状态
+func BenchmarkParallel_Clone(b *testing.B) {
+	handler := func(ctx context.Context, s MapState) (MapState, error) {
+		s["findings"] = append(s["findings"].([]any), len(s["raw_data"].([]any)))
+		return s, nil
+	}
+	node := ParallelNodeWithMerger("analyze", func(original MapState, outputs []MapState) MapState {
+		for _, out := range outputs {
+			original["findings"] = append(original["findings"].([]any), out["findings"].([]any)...)
+		}
+		return original
+	}, handler, handler, handler, handler)
+
+	state := largeState()
+	b.ReportAllocs()
+	for b.Loop() {
+		state["findings"] = []any{}
+		if _, err := node.Handler(context.Background(), state); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParallel_Reducer 并行分支共享状态，只合并增量更新
+func BenchmarkParallel_Reducer(b *testing.B) {
+	handler := func(ctx context.Context, s MapState) (StateUpdate, error) {
+		return StateUpdate{"findings": len(s["raw_data"].([]any))}, nil
+	}
+	g := NewGraph[MapState]("analyze").
+		WithStateReducer("findings", ReduceAppend).
+		AddNodeWithBuilder(ParallelUpdateNode("analyze", handler, handler, handler, handler)).
+		AddEdge(START, "analyze").
+		AddEdge("analyze", END).
+		MustBuild()
+	node := g.Nodes["analyze"]
+
+	state := largeState()
+	b.ReportAllocs()
+	for b.Loop() {
+		state["findings"] = []any{}
+		if _, err := node.Handler(context.Background(), state); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
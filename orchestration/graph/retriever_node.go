@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// RetrieverNode 创建检索节点
+//
+// queryFn 从图状态构造检索查询，mergeFn 将检索到的文档写回图状态，二者均不能为 nil。
+// 检索前后触发 RetrieverStart / RetrieverEnd 钩子（context 中需有 hooks.Manager），
+// 事件元数据中 node 为节点名称。检索出错时返回节点错误，状态保持不变。
+//
+// 使用示例（检索 → 增强 → 生成）：
+//
+//	g := NewGraph[RAGState]("rag").
+//	    AddNodeWithBuilder(RetrieverNode("retrieve", retriever,
+//	        func(s RAGState) string { return s.Question },
+//	        func(s RAGState, docs []rag.Document) RAGState { s.Docs = docs; return s },
+//	        rag.WithTopK(5),
+//	    )).
+//	    AddNode("generate", generate).
+//	    ...
+func RetrieverNode[S State](name string, retriever rag.Retriever, queryFn func(S) string, mergeFn func(S, []rag.Document) S, opts ...rag.RetrieveOption) *Node[S] {
+	return &Node[S]{
+		Name: name,
+		Type: NodeTypeRetriever,
+		Handler: func(ctx context.Context, state S) (S, error) {
+			if retriever == nil || queryFn == nil || mergeFn == nil {
+				return state, fmt.Errorf("retriever node %s: retriever, queryFn and mergeFn are required", name)
+			}
+
+			docs, err := rag.RetrieveWithHooks(ctx, retriever, queryFn(state),
+				map[string]any{"node": name}, opts...)
+			if err != nil {
+				return state, fmt.Errorf("retriever node %s: %w", name, err)
+			}
+			return mergeFn(state, docs), nil
+		},
+		Metadata: make(map[string]any),
+	}
+}
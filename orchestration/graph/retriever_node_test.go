@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// recordingRetrieverHook 记录检索事件的钩子
+type recordingRetrieverHook struct {
+	mu     sync.Mutex
+	starts []*hooks.RetrieverStartEvent
+	ends   []*hooks.RetrieverEndEvent
+}
+
+func (h *recordingRetrieverHook) Name() string  { return "recording" }
+func (h *recordingRetrieverHook) Enabled() bool { return true }
+
+func (h *recordingRetrieverHook) OnRetrieverStart(ctx context.Context, e *hooks.RetrieverStartEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.starts = append(h.starts, e)
+	return nil
+}
+
+func (h *recordingRetrieverHook) OnRetrieverEnd(ctx context.Context, e *hooks.RetrieverEndEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ends = append(h.ends, e)
+	return nil
+}
+
+func TestRetrieverNode(t *testing.T) {
+	retriever := mock.NewRetriever(mock.WithDocuments([]rag.Document{
+		{ID: "1", Content: "hexagon graphs"},
+		{ID: "2", Content: "hexagon workflows"},
+	}))
+
+	g := NewGraph[TestState]("rag").
+		AddNodeWithBuilder(RetrieverNode("retrieve", retriever,
+			func(s TestState) string { return s.Path },
+			func(s TestState, docs []rag.Document) TestState {
+				s.Data = map[string]string{}
+				for _, d := range docs {
+					s.Data[d.ID] = d.Content
+				}
+				return s
+			},
+			rag.WithTopK(1),
+		)).
+		AddEdge(START, "retrieve").
+		AddEdge("retrieve", END).
+		MustBuild()
+
+	hook := &recordingRetrieverHook{}
+	manager := hooks.NewManager()
+	manager.RegisterRetrieverHook(hook)
+	ctx := hooks.ContextWithManager(context.Background(), manager)
+
+	result, err := g.Run(ctx, TestState{Path: "hexagon"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Data) != 1 || result.Data["1"] != "hexagon graphs" {
+		t.Errorf("expected top-1 document in state, got %v", result.Data)
+	}
+	if calls := retriever.RetrieveCalls(); len(calls) != 1 || calls[0] != "hexagon" {
+		t.Errorf("expected query from state, got %v", calls)
+	}
+
+	if len(hook.starts) != 1 || len(hook.ends) != 1 {
+		t.Fatalf("expected one start and one end event, got %d/%d", len(hook.starts), len(hook.ends))
+	}
+	start, end := hook.starts[0], hook.ends[0]
+	if start.Query != "hexagon" || start.TopK != 1 || start.Metadata["node"] != "retrieve" {
+		t.Errorf("unexpected start event: %+v", start)
+	}
+	if end.QueryID != start.QueryID || end.DocCount != 1 || end.Error != nil {
+		t.Errorf("unexpected end event: %+v", end)
+	}
+}
+
+func TestRetrieverNodeError(t *testing.T) {
+	errBackend := errors.New("backend down")
+	retriever := mock.NewRetriever(mock.WithRetrieveFn(
+		func(ctx context.Context, query string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
+			return nil, errBackend
+		}))
+
+	g := NewGraph[TestState]("rag").
+		AddNodeWithBuilder(RetrieverNode("retrieve", retriever,
+			func(s TestState) string { return s.Path },
+			func(s TestState, docs []rag.Document) TestState { return s },
+		)).
+		AddEdge(START, "retrieve").
+		AddEdge("retrieve", END).
+		MustBuild()
+
+	hook := &recordingRetrieverHook{}
+	manager := hooks.NewManager()
+	manager.RegisterRetrieverHook(hook)
+	ctx := hooks.ContextWithManager(context.Background(), manager)
+
+	if _, err := g.Run(ctx, TestState{Path: "q"}); !errors.Is(err, errBackend) {
+		t.Fatalf("expected retriever error, got %v", err)
+	}
+	if len(hook.ends) != 1 || !errors.Is(hook.ends[0].Error, errBackend) {
+		t.Errorf("expected end event with error, got %+v", hook.ends)
+	}
+}
@@ -83,6 +83,9 @@ const (
 	// EventNodeError 节点执行错误
 	EventNodeError StreamModeEventType = "node_error"
 
+	// EventNodeRetry 节点失败后即将重试（见 WithNodeRetry）
+	EventNodeRetry StreamModeEventType = "node_retry"
+
 	// EventStateUpdate 状态更新
 	EventStateUpdate StreamModeEventType = "state_update"
 
@@ -172,9 +175,10 @@ func (sc *StreamChannel) Close() {
 type StreamRunOption func(*streamRunConfig)
 
 type streamRunConfig struct {
-	modes      []StreamMode
-	bufferSize int
-	filter     func(StreamModeEvent) bool
+	modes       []StreamMode
+	bufferSize  int
+	filter      func(StreamModeEvent) bool
+	nodeTimeout time.Duration
 }
 
 // WithStreamMode 设置流式模式
@@ -198,6 +202,13 @@ func WithStreamFilter(filter func(StreamModeEvent) bool) StreamRunOption {
 	}
 }
 
+// WithStreamNodeTimeout 设置每个节点的默认超时时间，Node.Timeout 优先于此值
+func WithStreamNodeTimeout(d time.Duration) StreamRunOption {
+	return func(c *streamRunConfig) {
+		c.nodeTimeout = d
+	}
+}
+
 // StreamRun 以流式模式执行图
 // 返回 StreamChannel 用于读取执行过程中的事件
 func (g *Graph[S]) StreamRun(ctx context.Context, state S, opts ...StreamRunOption) (*StreamChannel, error) {
@@ -214,7 +225,10 @@ func (g *Graph[S]) StreamRun(ctx context.Context, state S, opts ...StreamRunOpti
 		modeSet[m] = true
 	}
 
+	runCfg := &runConfig{nodeTimeout: cfg.nodeTimeout}
 	ch := NewStreamChannel(cfg.bufferSize)
+	// 节点可通过 EmitCustomEvent 等向事件流写入事件
+	ctx = WithStreamChannel(ctx, ch)
 
 	go func() {
 		defer ch.Close()
@@ -267,7 +281,21 @@ func (g *Graph[S]) StreamRun(ctx context.Context, state S, opts ...StreamRunOpti
 			}
 
 			// 执行节点
-			newState, err := node.Handler(ctx, currentState)
+			nodeName := current
+			newState, err := executeNodeWithRetry(ctx, runCfg, current, node, currentState, func(attempt int, err error, delay time.Duration) {
+				if modeSet[StreamModeDebug] {
+					ch.Emit(StreamModeEvent{
+						Mode: StreamModeDebug,
+						Type: EventNodeRetry,
+						Node: nodeName,
+						Data: map[string]any{
+							"attempt": attempt,
+							"delay":   delay.String(),
+							"error":   err.Error(),
+						},
+					})
+				}
+			})
 			duration := time.Since(startTime)
 
 			if err != nil {
@@ -12,6 +12,8 @@ import (
 	"context"
 	"fmt"
 	"sync"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // ============== 子图节点 ==============
@@ -144,7 +146,7 @@ func (g *DynamicGraph[S]) RemoveNodeDynamic(name string) error {
 	}
 
 	if _, exists := g.Nodes[name]; !exists {
-		return fmt.Errorf("node %s not found", name)
+		return fmt.Errorf("node %s %w", name, core.ErrNotFound)
 	}
 
 	delete(g.Nodes, name)
@@ -252,7 +254,7 @@ func (g *DynamicGraph[S]) ReplaceNodeHandler(name string, handler NodeHandler[S]
 
 	node, exists := g.Nodes[name]
 	if !exists {
-		return fmt.Errorf("node %s not found", name)
+		return fmt.Errorf("node %s %w", name, core.ErrNotFound)
 	}
 
 	node.Handler = handler
@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrGraphTimeout 图执行超过 WithGraphTimeout 设置的总时长
+	ErrGraphTimeout = errors.New("graph timeout")
+
+	// ErrNodeTimeout 节点执行超过单节点超时
+	ErrNodeTimeout = errors.New("node timeout")
+)
+
+// WithGraphTimeout 设置整个图执行的超时时间
+// 超时后正在执行的节点会收到取消信号，Run 返回包装 ErrGraphTimeout 的错误
+func WithGraphTimeout(d time.Duration) RunOption {
+	return func(c *runConfig) {
+		c.graphTimeout = d
+	}
+}
+
+// WithNodeTimeout 设置每个节点的默认超时时间
+// 节点自身的 Node.Timeout（NodeBuilder.WithTimeout）优先于此值；
+// 超时的节点 context 会被取消，Run 返回包含节点名并包装 ErrNodeTimeout 的错误。
+//
+// 其他执行器同样遵循 Node.Timeout，默认节点超时分别通过
+// WithStreamNodeTimeout（StreamRun）、CheckpointRunnerConfig.NodeTimeout 和
+// WithPregelNodeTimeout 设置；HumanInTheLoop 没有运行选项，仅遵循 Node.Timeout
+func WithNodeTimeout(d time.Duration) RunOption {
+	return func(c *runConfig) {
+		c.nodeTimeout = d
+	}
+}
+
+// nodeOutcome 节点异步执行结果
+type nodeOutcome[S State] struct {
+	state S
+	err   error
+}
+
+// withGraphTimeout 为整个运行附加超时，未设置时原样返回
+func (c *runConfig) withGraphTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.graphTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, c.graphTimeout, ErrGraphTimeout)
+}
+
+// effectiveTimeout 返回节点的有效超时时间，0 表示不限制
+func effectiveTimeout[S State](config *runConfig, node *Node[S]) time.Duration {
+	if node.Timeout > 0 {
+		return time.Duration(node.Timeout) * time.Millisecond
+	}
+	return config.nodeTimeout
+}
+
+// executeNode 在超时约束下执行节点
+//
+// 未设置任何超时时直接同步调用 handler；否则在独立 goroutine 中执行，
+// 超时即取消其 context 并立即返回，不等待忽略取消信号的 handler 结束。
+// 异步执行时 handler 拿到的是 state.Clone() 的副本，超时后仍在运行的 handler
+// 不会改动超时时返回给调用方的 state。
+func executeNode[S State](ctx context.Context, config *runConfig, name string, node *Node[S], state S) (S, error) {
+	timeout := effectiveTimeout(config, node)
+	if timeout <= 0 && config.graphTimeout <= 0 {
+		return node.Handler(ctx, state)
+	}
+
+	var (
+		nodeCtx context.Context
+		cancel  context.CancelFunc
+	)
+	if timeout > 0 {
+		nodeCtx, cancel = context.WithTimeoutCause(ctx, timeout, ErrNodeTimeout)
+	} else {
+		nodeCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	input := state.Clone().(S)
+	done := make(chan nodeOutcome[S], 1)
+	go func() {
+		s, err := node.Handler(nodeCtx, input)
+		done <- nodeOutcome[S]{state: s, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		if out.err != nil && nodeCtx.Err() != nil {
+			if err := timeoutError(nodeCtx, config, name, timeout); err != nil {
+				return state, err
+			}
+		}
+		return out.state, out.err
+	case <-nodeCtx.Done():
+		if err := timeoutError(nodeCtx, config, name, timeout); err != nil {
+			return state, err
+		}
+		return state, ctx.Err()
+	}
+}
+
+// timeoutError 根据 context 取消原因构造超时错误，非超时取消返回 nil
+func timeoutError(ctx context.Context, config *runConfig, name string, nodeTimeout time.Duration) error {
+	cause := context.Cause(ctx)
+	switch {
+	case errors.Is(cause, ErrNodeTimeout):
+		return fmt.Errorf("node %s timed out after %s: %w", name, nodeTimeout, ErrNodeTimeout)
+	case errors.Is(cause, ErrGraphTimeout):
+		return fmt.Errorf("graph timed out after %s at node %s: %w", config.graphTimeout, name, ErrGraphTimeout)
+	}
+	return nil
+}
+
+// ctxError 返回 context 结束的错误，图超时时包装 ErrGraphTimeout
+func ctxError(ctx context.Context, config *runConfig) error {
+	if errors.Is(context.Cause(ctx), ErrGraphTimeout) {
+		return fmt.Errorf("graph timed out after %s: %w", config.graphTimeout, ErrGraphTimeout)
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,227 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingNode 阻塞直到 context 取消，并通过 cancelled 通知
+func blockingNode(cancelled chan<- struct{}) NodeHandler[TestState] {
+	return func(ctx context.Context, s TestState) (TestState, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return s, ctx.Err()
+	}
+}
+
+func TestGraphNodeTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	g := NewGraph[TestState]("timeout").
+		AddNode("fast", func(ctx context.Context, s TestState) (TestState, error) {
+			s.Counter++
+			return s, nil
+		}).
+		AddNode("slow", blockingNode(cancelled)).
+		AddEdge(START, "fast").
+		AddEdge("fast", "slow").
+		AddEdge("slow", END).
+		MustBuild()
+
+	state, err := g.Run(context.Background(), TestState{}, WithNodeTimeout(20*time.Millisecond))
+	if !errors.Is(err, ErrNodeTimeout) {
+		t.Fatalf("expected ErrNodeTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "node slow") {
+		t.Errorf("expected error to name the node, got %v", err)
+	}
+	if state.Counter != 1 {
+		t.Errorf("expected state from completed nodes, got %d", state.Counter)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("timed-out node context was not cancelled")
+	}
+}
+
+func TestGraphNodeTimeoutOverride(t *testing.T) {
+	slow := func(ctx context.Context, s TestState) (TestState, error) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			s.Counter++
+			return s, nil
+		case <-ctx.Done():
+			return s, ctx.Err()
+		}
+	}
+	g := NewGraph[TestState]("override").
+		AddNodeWithBuilder(NewNode("patient", slow).WithTimeout(time.Second.Milliseconds()).Build()).
+		AddEdge(START, "patient").
+		AddEdge("patient", END).
+		MustBuild()
+
+	state, err := g.Run(context.Background(), TestState{}, WithNodeTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("expected per-node timeout to override default, got %v", err)
+	}
+	if state.Counter != 1 {
+		t.Errorf("expected node to complete, got %d", state.Counter)
+	}
+}
+
+func TestGraphNodeTimeoutIsolatesState(t *testing.T) {
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	g := NewGraph[MapState]("isolate").
+		AddNode("stubborn", func(ctx context.Context, s MapState) (MapState, error) {
+			// 忽略取消信号，超时后仍写入状态
+			<-release
+			s["late"] = true
+			close(finished)
+			return s, nil
+		}).
+		AddEdge(START, "stubborn").
+		AddEdge("stubborn", END).
+		MustBuild()
+
+	input := MapState{"seed": 1}
+	state, err := g.Run(context.Background(), input, WithNodeTimeout(20*time.Millisecond))
+	if !errors.Is(err, ErrNodeTimeout) {
+		t.Fatalf("expected ErrNodeTimeout, got %v", err)
+	}
+	close(release)
+	<-finished
+
+	if _, ok := state["late"]; ok {
+		t.Error("abandoned handler mutated the returned state")
+	}
+	if _, ok := input["late"]; ok {
+		t.Error("abandoned handler mutated the caller's state")
+	}
+}
+
+func TestGraphTimeout(t *testing.T) {
+	step := func(ctx context.Context, s TestState) (TestState, error) {
+		time.Sleep(15 * time.Millisecond)
+		s.Counter++
+		return s, nil
+	}
+	// 忽略取消信号的节点也不会阻塞 Run 返回
+	stuck := func(ctx context.Context, s TestState) (TestState, error) {
+		time.Sleep(time.Second)
+		return s, nil
+	}
+	g := NewGraph[TestState]("graph-timeout").
+		AddNode("a", step).
+		AddNode("b", stuck).
+		AddEdge(START, "a").
+		AddEdge("a", "b").
+		AddEdge("b", END).
+		MustBuild()
+
+	start := time.Now()
+	_, err := g.Run(context.Background(), TestState{}, WithGraphTimeout(40*time.Millisecond))
+	if !errors.Is(err, ErrGraphTimeout) {
+		t.Fatalf("expected ErrGraphTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "node b") {
+		t.Errorf("expected error to name the running node, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run did not return promptly: %v", elapsed)
+	}
+}
+
+func TestGraphStreamNodeTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	g := NewGraph[TestState]("stream-timeout").
+		AddNode("slow", blockingNode(cancelled)).
+		AddEdge(START, "slow").
+		AddEdge("slow", END).
+		MustBuild()
+
+	events, err := g.Stream(context.Background(), TestState{}, WithNodeTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	var last StreamEvent[TestState]
+	for evt := range events {
+		last = evt
+	}
+	if last.Type != EventTypeError || !errors.Is(last.Error, ErrNodeTimeout) {
+		t.Fatalf("expected node timeout error event, got %+v", last)
+	}
+}
+
+func TestExecutorsNodeTimeout(t *testing.T) {
+	newGraph := func(cancelled chan struct{}) *Graph[TestState] {
+		return NewGraph[TestState]("executor-timeout").
+			AddNode("slow", blockingNode(cancelled)).
+			AddEdge(START, "slow").
+			AddEdge("slow", END).
+			MustBuild()
+	}
+	runs := map[string]func(g *Graph[TestState]) error{
+		"checkpoint runner": func(g *Graph[TestState]) error {
+			config := DefaultCheckpointRunnerConfig()
+			config.MaxRetries = 0
+			config.NodeTimeout = 10 * time.Millisecond
+			_, err := NewCheckpointRunner(g, NewMemoryEnhancedCheckpointSaver(), config).Run(context.Background(), "thread", TestState{})
+			return err
+		},
+		"pregel": func(g *Graph[TestState]) error {
+			_, _, err := g.RunPregelMode(context.Background(), TestState{}, WithPregelNodeTimeout(10*time.Millisecond))
+			return err
+		},
+		"pregel sequential": func(g *Graph[TestState]) error {
+			_, _, err := g.RunPregelMode(context.Background(), TestState{},
+				WithPregelNodeTimeout(10*time.Millisecond), WithParallelExecution(false))
+			return err
+		},
+		"stream run": func(g *Graph[TestState]) error {
+			ch, err := g.StreamRun(context.Background(), TestState{},
+				WithStreamMode(StreamModeDebug), WithStreamNodeTimeout(10*time.Millisecond))
+			if err != nil {
+				return err
+			}
+			var nodeErr error
+			for evt := range ch.Events() {
+				if evt.Type == EventNodeError {
+					nodeErr = errors.New(evt.Data.(map[string]any)["error"].(string))
+				}
+			}
+			return nodeErr
+		},
+	}
+	for name, run := range runs {
+		t.Run(name, func(t *testing.T) {
+			cancelled := make(chan struct{})
+			err := run(newGraph(cancelled))
+			if err == nil || !strings.Contains(err.Error(), "node slow timed out") {
+				t.Fatalf("expected node timeout, got %v", err)
+			}
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Error("timed-out node context was not cancelled")
+			}
+		})
+	}
+}
+
+func TestHumanInTheLoopNodeTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	g := NewGraph[TestState]("hitl-timeout").
+		AddNodeWithBuilder(NewNode("slow", blockingNode(cancelled)).WithTimeout(10).Build()).
+		AddEdge(START, "slow").
+		AddEdge("slow", END).
+		MustBuild()
+
+	hitl := NewHumanInTheLoop(g, NewMemoryInterruptHandler(), NewMemoryCheckpointSaver())
+	if _, _, err := hitl.RunWithInterrupt(context.Background(), "thread", TestState{}); !errors.Is(err, ErrNodeTimeout) {
+		t.Fatalf("expected ErrNodeTimeout, got %v", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/rag"
 )
 
 // WorkflowBuilder 工作流构建器
@@ -158,6 +160,11 @@ func (b *WorkflowBuilder) SubWorkflow(id, name string, workflow *Workflow, runne
 	return b.Add(NewSubWorkflowStep(id, name, workflow, runner))
 }
 
+// Retrieve 检索文档，输出 []rag.Document
+func (b *WorkflowBuilder) Retrieve(id, name string, retriever rag.Retriever, queryFn func(StepInput) string, opts ...rag.RetrieveOption) *WorkflowBuilder {
+	return b.Add(NewRetrieverStep(id, name, retriever, queryFn, opts...))
+}
+
 // Build 构建工作流
 func (b *WorkflowBuilder) Build() (*Workflow, error) {
 	if b.err != nil {
@@ -171,6 +178,10 @@ func (b *WorkflowBuilder) Build() (*Workflow, error) {
 		}
 	}
 
+	if err := b.workflow.validateCompensations(); err != nil {
+		return nil, err
+	}
+
 	return b.workflow, nil
 }
 
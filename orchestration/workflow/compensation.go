@@ -0,0 +1,219 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============== 补偿（Saga） ==============
+//
+// 工作流在某个步骤失败时，按完成顺序的逆序调用已完成步骤注册的补偿函数，
+// 撤销它们产生的副作用（如已扣减的库存、已发出的预订）。
+// 补偿只在工作流失败时执行，取消（包括超时）不会触发补偿。
+
+// CompensateFunc 补偿函数
+//
+// input.Data 为被补偿步骤的输出，Variables 和 PreviousOutputs 为工作流失败时的上下文。
+type CompensateFunc func(ctx context.Context, input StepInput) error
+
+// ErrorHandler 工作流失败处理函数
+// 在补偿执行完毕后调用，err 中包含失败原因和补偿结果
+type ErrorHandler func(ctx context.Context, err *WorkflowError)
+
+// CompensationResult 补偿执行结果
+type CompensationResult struct {
+	// StepID 被补偿的步骤 ID
+	StepID string `json:"step_id"`
+
+	// Error 补偿失败时的错误信息
+	Error string `json:"error,omitempty"`
+
+	// Duration 补偿耗时
+	Duration time.Duration `json:"duration,omitempty"`
+
+	err error
+}
+
+// Succeeded 补偿是否成功
+func (r CompensationResult) Succeeded() bool {
+	return r.Error == ""
+}
+
+// Err 返回补偿失败的原始错误
+func (r CompensationResult) Err() error {
+	return r.err
+}
+
+// WorkflowError 工作流失败错误
+//
+// 可通过 errors.As 从 Executor.Run 返回的错误中取出。
+type WorkflowError struct {
+	// StepID 导致失败的步骤 ID
+	StepID string
+
+	// Err 失败原因
+	Err error
+
+	// Compensations 按执行顺序排列的补偿结果
+	Compensations []CompensationResult
+}
+
+// Error 实现 error 接口
+func (e *WorkflowError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Err.Error())
+	if len(e.Compensations) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("; compensations: ")
+	for i, r := range e.Compensations {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if r.Succeeded() {
+			fmt.Fprintf(&sb, "%s ok", r.StepID)
+		} else {
+			fmt.Fprintf(&sb, "%s failed: %s", r.StepID, r.Error)
+		}
+	}
+	return sb.String()
+}
+
+// Unwrap 返回失败原因
+func (e *WorkflowError) Unwrap() error {
+	return e.Err
+}
+
+// CompensationFailed 是否有补偿失败
+func (e *WorkflowError) CompensationFailed() bool {
+	for _, r := range e.Compensations {
+		if !r.Succeeded() {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCompensation 为步骤注册补偿函数
+//
+// 工作流失败时，已成功完成且注册了补偿的步骤按完成顺序的逆序执行补偿。
+// stepID 必须是工作流的顶层步骤，并行、条件等组合步骤按整体补偿。
+func (b *WorkflowBuilder) WithCompensation(stepID string, fn CompensateFunc) *WorkflowBuilder {
+	if b.err != nil {
+		return b
+	}
+	if fn == nil {
+		b.err = fmt.Errorf("compensation for step %s cannot be nil", stepID)
+		return b
+	}
+	if b.workflow.Compensations == nil {
+		b.workflow.Compensations = make(map[string]CompensateFunc)
+	}
+	b.workflow.Compensations[stepID] = fn
+	return b
+}
+
+// OnError 注册工作流失败处理函数
+// 任意原因导致工作流失败时，在补偿完成后按注册顺序调用
+func (b *WorkflowBuilder) OnError(handler ErrorHandler) *WorkflowBuilder {
+	if b.err != nil {
+		return b
+	}
+	if handler == nil {
+		b.err = fmt.Errorf("error handler cannot be nil")
+		return b
+	}
+	b.workflow.ErrorHandlers = append(b.workflow.ErrorHandlers, handler)
+	return b
+}
+
+// validateCompensations 检查补偿函数是否都对应顶层步骤
+func (wf *Workflow) validateCompensations() error {
+	if len(wf.Compensations) == 0 {
+		return nil
+	}
+	ids := make(map[string]bool, len(wf.Steps))
+	for _, step := range wf.Steps {
+		ids[step.ID()] = true
+	}
+	for stepID := range wf.Compensations {
+		if !ids[stepID] {
+			return fmt.Errorf("compensation registered for unknown step %s", stepID)
+		}
+	}
+	return nil
+}
+
+// compensate 逆序执行已完成步骤的补偿
+// 使用不随工作流取消的 context，确保补偿在超时后仍能执行
+func (e *Executor) compensate(ctx context.Context, state *executionState, stepInput StepInput) []CompensationResult {
+	wf := state.workflow
+	if len(wf.Compensations) == 0 {
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	completed := state.execution.Context.CompletedSteps
+
+	var results []CompensationResult
+	for i := len(completed) - 1; i >= 0; i-- {
+		stepID := completed[i]
+		fn, ok := wf.Compensations[stepID]
+		if !ok {
+			continue
+		}
+
+		input := stepInput
+		input.Data = stepInput.PreviousOutputs[stepID]
+
+		start := time.Now()
+		err := runCompensation(ctx, fn, input)
+		result := CompensationResult{
+			StepID:   stepID,
+			Duration: time.Since(start),
+			err:      err,
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// runCompensation 执行补偿函数，将 panic 转为错误
+func runCompensation(ctx context.Context, fn CompensateFunc, input StepInput) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("compensation panic: %v", r)
+		}
+	}()
+	return fn(ctx, input)
+}
+
+// fail 执行补偿和失败处理，并将工作流标记为失败
+func (e *Executor) fail(ctx context.Context, state *executionState, stepInput StepInput, stepID string, cause error) {
+	wfErr := &WorkflowError{
+		StepID: stepID,
+		Err:    cause,
+	}
+	wfErr.Compensations = e.compensate(ctx, state, stepInput)
+
+	state.mu.Lock()
+	state.err = wfErr
+	state.execution.Compensations = wfErr.Compensations
+	state.mu.Unlock()
+
+	handlerCtx := context.WithoutCancel(ctx)
+	for _, handler := range state.workflow.ErrorHandlers {
+		handler(handlerCtx, wfErr)
+	}
+	if e.hooks != nil && e.hooks.OnError != nil {
+		e.hooks.OnError(handlerCtx, state.workflow, wfErr)
+	}
+
+	e.setExecutionStatus(state, StatusFailed, wfErr.Error())
+}
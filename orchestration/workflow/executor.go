@@ -3,9 +3,12 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/events"
 )
 
 // Executor 工作流执行器
@@ -22,6 +25,9 @@ type Executor struct {
 	// 钩子
 	hooks *WorkflowHooks
 
+	// 生命周期事件总线
+	bus *events.Bus[events.Lifecycle]
+
 	// 配置
 	config ExecutorConfig
 
@@ -57,6 +63,9 @@ type executionState struct {
 	pauseCh   chan struct{}
 	resumeCh  chan struct{}
 	doneCh    chan struct{}
+	err       *WorkflowError
+	output    *WorkflowOutput
+	stream    *eventStream
 	mu        sync.Mutex
 }
 
@@ -78,6 +87,14 @@ func WithHooks(hooks *WorkflowHooks) ExecutorOption {
 	}
 }
 
+// WithEventBus 将执行事件转换为 events.Lifecycle 发布到事件总线
+// RunID 为执行 ID，步骤事件的 Target 为步骤 ID
+func WithEventBus(bus *events.Bus[events.Lifecycle]) ExecutorOption {
+	return func(e *Executor) {
+		e.bus = bus
+	}
+}
+
 // WithExecutorConfig 设置配置
 func WithExecutorConfig(config ExecutorConfig) ExecutorOption {
 	return func(e *Executor) {
@@ -114,6 +131,56 @@ func (e *Executor) emitEvent(event *WorkflowEvent) {
 	for _, handler := range handlers {
 		handler(event)
 	}
+
+	// 转发到该执行的事件流（Stream）
+	stateVal, ok := e.executions.Load(event.ExecutionID)
+	if ok {
+		if stream := stateVal.(*executionState).stream; stream != nil {
+			stream.send(event)
+		}
+	}
+
+	// 发布到生命周期事件总线
+	if e.bus != nil {
+		lifecycle := lifecycleEvent(event)
+		if ok {
+			lifecycle.Name = stateVal.(*executionState).workflow.Name
+		}
+		e.bus.Publish(context.Background(), lifecycle)
+	}
+}
+
+// lifecycleTypes 工作流事件类型到生命周期事件类型的映射
+var lifecycleTypes = map[WorkflowEventType]events.LifecycleType{
+	EventWorkflowStarted:   events.RunStarted,
+	EventWorkflowCompleted: events.RunCompleted,
+	EventWorkflowFailed:    events.RunFailed,
+	EventWorkflowPaused:    events.RunPaused,
+	EventWorkflowResumed:   events.RunResumed,
+	EventWorkflowCancelled: events.RunCancelled,
+	EventStepStarted:       events.StepStarted,
+	EventStepCompleted:     events.StepCompleted,
+	EventStepFailed:        events.StepFailed,
+	EventStepSkipped:       events.StepSkipped,
+	EventStepRetrying:      events.StepRetrying,
+}
+
+// lifecycleEvent 将工作流事件转换为生命周期事件
+func lifecycleEvent(event *WorkflowEvent) events.Lifecycle {
+	lifecycle := events.Lifecycle{
+		Source:    events.SourceWorkflow,
+		Type:      lifecycleTypes[event.Type],
+		RunID:     event.ExecutionID,
+		Target:    event.StepID,
+		Timestamp: event.Timestamp,
+	}
+	if lifecycle.Type == "" {
+		lifecycle.Type = events.LifecycleType(event.Type)
+	}
+	if event.Error != "" {
+		lifecycle.Err = errors.New(event.Error)
+	}
+	return lifecycle
 }
 
 // Run 同步运行工作流
@@ -134,6 +201,9 @@ func (e *Executor) Run(ctx context.Context, wf *Workflow, input WorkflowInput) (
 	}
 
 	if execution.Status == StatusFailed {
+		if wfErr := e.failure(executionID); wfErr != nil {
+			return nil, fmt.Errorf("workflow failed: %w", wfErr)
+		}
 		return nil, fmt.Errorf("workflow failed: %s", execution.Error)
 	}
 
@@ -166,6 +236,11 @@ func (e *Executor) Run(ctx context.Context, wf *Workflow, input WorkflowInput) (
 
 // RunAsync 异步运行工作流
 func (e *Executor) RunAsync(ctx context.Context, wf *Workflow, input WorkflowInput) (string, error) {
+	return e.runAsync(ctx, wf, input, nil)
+}
+
+// runAsync 异步运行工作流，stream 不为 nil 时执行事件同时写入事件流
+func (e *Executor) runAsync(ctx context.Context, wf *Workflow, input WorkflowInput, stream *eventStream) (string, error) {
 	// 创建执行实例
 	execution := NewExecution(wf)
 	execution.StartedAt = time.Now()
@@ -194,6 +269,7 @@ func (e *Executor) RunAsync(ctx context.Context, wf *Workflow, input WorkflowInp
 		pauseCh:   make(chan struct{}),
 		resumeCh:  make(chan struct{}),
 		doneCh:    make(chan struct{}),
+		stream:    stream,
 	}
 
 	e.executions.Store(execution.ID, state)
@@ -232,6 +308,10 @@ func (e *Executor) executeWorkflow(ctx context.Context, state *executionState, i
 
 	wf := state.workflow
 	execution := state.execution
+	ctx = withStepEvents(ctx, func(event *WorkflowEvent) {
+		event.ExecutionID = execution.ID
+		e.emitEvent(event)
+	})
 
 	// 准备步骤输入
 	stepInput := StepInput{
@@ -264,7 +344,7 @@ func (e *Executor) executeWorkflow(ctx context.Context, state *executionState, i
 		if baseStep, ok := step.(*BaseStep); ok {
 			for _, dep := range baseStep.Dependencies() {
 				if _, completed := execution.StepResults[dep]; !completed {
-					e.setExecutionStatus(state, StatusFailed, fmt.Sprintf("dependency %s not completed", dep))
+					e.fail(ctx, state, stepInput, step.ID(), fmt.Errorf("dependency %s not completed", dep))
 					return
 				}
 			}
@@ -273,6 +353,27 @@ func (e *Executor) executeWorkflow(ctx context.Context, state *executionState, i
 		// 执行步骤
 		execution.Context.CurrentStepID = step.ID()
 
+		// 满足跳过条件：记录跳过标记，数据原样传给下一步
+		if shouldSkip(step, stepInput) {
+			now := time.Now()
+			execution.StepResults[step.ID()] = &StepResult{
+				StepID:      step.ID(),
+				Status:      StatusSkipped,
+				Output:      Skipped,
+				StartedAt:   now,
+				CompletedAt: &now,
+			}
+			stepInput.PreviousOutputs[step.ID()] = Skipped
+			e.emitEvent(&WorkflowEvent{
+				Type:        EventStepSkipped,
+				ExecutionID: execution.ID,
+				StepID:      step.ID(),
+				Status:      StatusSkipped,
+				Timestamp:   now,
+			})
+			continue
+		}
+
 		// 触发步骤开始钩子
 		if e.hooks != nil && e.hooks.OnStepStart != nil {
 			e.hooks.OnStepStart(ctx, step, stepInput.Data)
@@ -317,7 +418,7 @@ func (e *Executor) executeWorkflow(ctx context.Context, state *executionState, i
 				Timestamp:   time.Now(),
 			})
 
-			e.setExecutionStatus(state, StatusFailed, fmt.Sprintf("step %s failed: %s", step.ID(), err.Error()))
+			e.fail(ctx, state, stepInput, step.ID(), fmt.Errorf("step %s failed: %w", step.ID(), err))
 			return
 		}
 
@@ -361,6 +462,14 @@ func (e *Executor) executeWorkflow(ctx context.Context, state *executionState, i
 	// 工作流完成
 	outputData, _ := json.Marshal(stepInput.Data)
 	execution.Output = outputData
+	output := &WorkflowOutput{
+		Data:        stepInput.Data,
+		Variables:   stepInput.Variables,
+		StepOutputs: stepInput.PreviousOutputs,
+	}
+	state.mu.Lock()
+	state.output = output
+	state.mu.Unlock()
 	e.setExecutionStatus(state, StatusCompleted, "")
 
 	// 触发完成钩子
@@ -413,16 +522,33 @@ func (e *Executor) setExecutionStatus(state *executionState, status WorkflowStat
 	}
 
 	if eventType != "" {
-		e.emitEvent(&WorkflowEvent{
+		event := &WorkflowEvent{
 			Type:        eventType,
 			ExecutionID: execution.ID,
 			Status:      status,
 			Error:       errMsg,
 			Timestamp:   now,
-		})
+		}
+		// 完成事件携带工作流输出
+		if status == StatusCompleted && state.output != nil {
+			event.Data = state.output
+		}
+		e.emitEvent(event)
 	}
 }
 
+// failure 返回执行失败的详细错误
+func (e *Executor) failure(executionID string) *WorkflowError {
+	stateVal, ok := e.executions.Load(executionID)
+	if !ok {
+		return nil
+	}
+	state := stateVal.(*executionState)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.err
+}
+
 // Pause 暂停执行
 func (e *Executor) Pause(ctx context.Context, executionID string) error {
 	stateVal, ok := e.executions.Load(executionID)
@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// ============== RetrieverStep ==============
+
+// RetrieverStep 检索步骤
+//
+// 根据步骤输入构造查询并检索文档，输出 Data 为 []rag.Document。
+// 检索前后触发 RetrieverStart / RetrieverEnd 钩子（context 中需有 hooks.Manager），
+// 事件元数据中 step 为步骤 ID。
+type RetrieverStep struct {
+	id        string
+	name      string
+	retriever rag.Retriever
+	queryFn   func(StepInput) string
+	opts      []rag.RetrieveOption
+}
+
+// NewRetrieverStep 创建检索步骤
+// queryFn 为 nil 时使用字符串类型的 input.Data 作为查询
+func NewRetrieverStep(id, name string, retriever rag.Retriever, queryFn func(StepInput) string, opts ...rag.RetrieveOption) *RetrieverStep {
+	return &RetrieverStep{
+		id:        id,
+		name:      name,
+		retriever: retriever,
+		queryFn:   queryFn,
+		opts:      opts,
+	}
+}
+
+// ID 返回步骤 ID
+func (s *RetrieverStep) ID() string {
+	return s.id
+}
+
+// Name 返回步骤名称
+func (s *RetrieverStep) Name() string {
+	return s.name
+}
+
+// Type 返回步骤类型
+func (s *RetrieverStep) Type() StepType {
+	return StepTypeRetriever
+}
+
+// Execute 执行检索
+func (s *RetrieverStep) Execute(ctx context.Context, input StepInput) (*StepOutput, error) {
+	var query string
+	if s.queryFn != nil {
+		query = s.queryFn(input)
+	} else {
+		q, ok := input.Data.(string)
+		if !ok {
+			return nil, fmt.Errorf("retriever step %s: input data is %T, want string query", s.id, input.Data)
+		}
+		query = q
+	}
+
+	docs, err := rag.RetrieveWithHooks(ctx, s.retriever, query, map[string]any{"step": s.id}, s.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve: %w", err)
+	}
+	return &StepOutput{Data: docs}, nil
+}
+
+// Validate 验证步骤配置
+func (s *RetrieverStep) Validate() error {
+	if s.id == "" {
+		return fmt.Errorf("retriever step id cannot be empty")
+	}
+	if s.retriever == nil {
+		return fmt.Errorf("retriever step %s: retriever cannot be nil", s.id)
+	}
+	return nil
+}
@@ -23,6 +23,8 @@ const (
 	StepTypeSubWorkflow StepType = "sub_workflow"
 	// StepTypeWait 等待步骤
 	StepTypeWait StepType = "wait"
+	// StepTypeRetriever 检索步骤
+	StepTypeRetriever StepType = "retriever"
 )
 
 // Step 步骤接口
@@ -43,6 +45,34 @@ type Step interface {
 	Validate() error
 }
 
+// Skippable 可按条件跳过的步骤
+// 执行器和并行步骤在执行前检查，ShouldSkip 返回 true 时跳过该步骤
+type Skippable interface {
+	ShouldSkip(input StepInput) bool
+}
+
+// Skipped 被跳过步骤在 PreviousOutputs 中的输出值
+var Skipped = skippedOutput{}
+
+// skippedOutput 跳过标记
+type skippedOutput struct{}
+
+// String 实现 fmt.Stringer
+func (skippedOutput) String() string {
+	return "<skipped>"
+}
+
+// MarshalJSON 序列化为 "<skipped>"，便于持久化后识别
+func (skippedOutput) MarshalJSON() ([]byte, error) {
+	return []byte(`"<skipped>"`), nil
+}
+
+// shouldSkip 步骤是否满足跳过条件
+func shouldSkip(step Step, input StepInput) bool {
+	sk, ok := step.(Skippable)
+	return ok && sk.ShouldSkip(input)
+}
+
 // StepInput 步骤输入
 type StepInput struct {
 	// Data 输入数据
@@ -88,6 +118,7 @@ type BaseStep struct {
 	timeout      time.Duration
 	dependencies []string
 	metadata     map[string]any
+	skipIf       func(StepInput) bool
 }
 
 // BaseStepOption 基础步骤选项
@@ -131,6 +162,17 @@ func WithStepMetadata(key string, value any) BaseStepOption {
 	}
 }
 
+// WithSkipIf 设置跳过条件
+//
+// 条件成立时步骤不执行也不算失败：执行器将其 PreviousOutputs 条目设为 Skipped，
+// Data 原样传给下一步，并发送 step_skipped 事件。下游步骤可通过 StepInput.WasSkipped 判断。
+// 被跳过的步骤不计入已完成步骤，工作流失败时不会被补偿。
+func WithSkipIf(cond func(StepInput) bool) BaseStepOption {
+	return func(s *BaseStep) {
+		s.skipIf = cond
+	}
+}
+
 // NewStep 创建基础步骤
 func NewStep(id, name string, fn StepFunc, opts ...BaseStepOption) *BaseStep {
 	s := &BaseStep{
@@ -160,8 +202,18 @@ func (s *BaseStep) Type() StepType {
 	return StepTypeNormal
 }
 
+// ShouldSkip 是否应跳过步骤
+func (s *BaseStep) ShouldSkip(input StepInput) bool {
+	return s.skipIf != nil && s.skipIf(input)
+}
+
 // Execute 执行步骤
+// 满足跳过条件时不执行，输入数据原样输出
 func (s *BaseStep) Execute(ctx context.Context, input StepInput) (*StepOutput, error) {
+	if s.ShouldSkip(input) {
+		return &StepOutput{Data: input.Data}, nil
+	}
+
 	// 应用超时
 	if s.timeout > 0 {
 		var cancel context.CancelFunc
@@ -330,9 +382,42 @@ func (s *ParallelStep) Execute(ctx context.Context, input StepInput) (*StepOutpu
 				return
 			}
 
+			if shouldSkip(step, input) {
+				results <- result{stepID: step.ID(), output: &StepOutput{Data: Skipped}}
+				emitStepEvent(ctx, &WorkflowEvent{
+					Type:     EventStepSkipped,
+					StepID:   s.id,
+					BranchID: step.ID(),
+					Status:   StatusSkipped,
+				})
+				return
+			}
+
+			emitStepEvent(ctx, &WorkflowEvent{
+				Type:     EventStepStarted,
+				StepID:   s.id,
+				BranchID: step.ID(),
+				Status:   StatusRunning,
+			})
+
 			output, err := step.Execute(ctx, input)
 			results <- result{stepID: step.ID(), output: output, err: err}
 
+			event := &WorkflowEvent{
+				Type:     EventStepCompleted,
+				StepID:   s.id,
+				BranchID: step.ID(),
+				Status:   StatusCompleted,
+			}
+			if err != nil {
+				event.Type = EventStepFailed
+				event.Status = StatusFailed
+				event.Error = err.Error()
+			} else if output != nil {
+				event.Data = output
+			}
+			emitStepEvent(ctx, event)
+
 			if err != nil && s.failFast {
 				cancel()
 			}
@@ -0,0 +1,250 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ============== StepInput 类型安全访问 ==============
+//
+// 以下方法在值不存在或类型不匹配时返回 ok=false，而不是像直接类型断言那样 panic，
+// 上游步骤输出类型变化时下游步骤可以优雅处理。
+
+// Previous 获取前置步骤的输出
+func (in StepInput) Previous(stepID string) (any, bool) {
+	v, ok := in.PreviousOutputs[stepID]
+	return v, ok
+}
+
+// WasSkipped 前置步骤是否因跳过条件被跳过
+func (in StepInput) WasSkipped(stepID string) bool {
+	v, ok := in.Previous(stepID)
+	return ok && v == Skipped
+}
+
+// PreviousString 获取前置步骤的字符串输出
+func (in StepInput) PreviousString(stepID string) (string, bool) {
+	return PreviousAs[string](in, stepID)
+}
+
+// PreviousSlice 获取前置步骤的切片输出
+// 任意元素类型的切片（如 []string）都会转换为 []any
+func (in StepInput) PreviousSlice(stepID string) ([]any, bool) {
+	v, ok := in.Previous(stepID)
+	if !ok {
+		return nil, false
+	}
+	return toAnySlice(v)
+}
+
+// PreviousMap 获取前置步骤的 map[string]any 输出
+func (in StepInput) PreviousMap(stepID string) (map[string]any, bool) {
+	return PreviousAs[map[string]any](in, stepID)
+}
+
+// Var 获取上下文变量
+func (in StepInput) Var(key string) (any, bool) {
+	v, ok := in.Variables[key]
+	return v, ok
+}
+
+// VarString 获取字符串变量
+func (in StepInput) VarString(key string) (string, bool) {
+	return VarAs[string](in, key)
+}
+
+// VarInt 获取整数变量
+// 兼容各整数类型、无小数部分的浮点数（如 JSON 解码结果）和 json.Number
+func (in StepInput) VarInt(key string) (int, bool) {
+	v, ok := in.Var(key)
+	if !ok {
+		return 0, false
+	}
+	return toInt(v)
+}
+
+// VarFloat 获取浮点数变量
+// 兼容各整数和浮点类型以及 json.Number
+func (in StepInput) VarFloat(key string) (float64, bool) {
+	v, ok := in.Var(key)
+	if !ok {
+		return 0, false
+	}
+	return toFloat(v)
+}
+
+// VarBool 获取布尔变量
+func (in StepInput) VarBool(key string) (bool, bool) {
+	return VarAs[bool](in, key)
+}
+
+// BindPrevious 将前置步骤的输出绑定到 dst
+//
+// dst 必须是非 nil 指针。输出类型可直接赋值给 *dst 时直接赋值，
+// 否则经 JSON 往返转换（如 map[string]any 转为结构体）。
+// 步骤不存在、被跳过或结构不匹配时返回描述性错误。
+//
+// 使用示例：
+//
+//	var report struct {
+//	    Score float64  `json:"score"`
+//	    Tags  []string `json:"tags"`
+//	}
+//	if err := input.BindPrevious("analyze", &report); err != nil {
+//	    return nil, err
+//	}
+func (in StepInput) BindPrevious(stepID string, dst any) error {
+	v, ok := in.Previous(stepID)
+	if !ok {
+		return fmt.Errorf("bind previous: step %s has no output", stepID)
+	}
+	if v == Skipped {
+		return fmt.Errorf("bind previous: step %s was skipped", stepID)
+	}
+	if err := bindValue(v, dst); err != nil {
+		return fmt.Errorf("bind previous output of step %s: %w", stepID, err)
+	}
+	return nil
+}
+
+// BindData 将当前输入数据绑定到 dst，规则同 BindPrevious
+func (in StepInput) BindData(dst any) error {
+	if err := bindValue(in.Data, dst); err != nil {
+		return fmt.Errorf("bind input data: %w", err)
+	}
+	return nil
+}
+
+// PreviousAs 以指定类型获取前置步骤的输出
+//
+// 使用示例：
+//
+//	items, ok := workflow.PreviousAs[[]string](input, "extract")
+//	if !ok {
+//	    return nil, fmt.Errorf("extract 步骤输出类型不符")
+//	}
+func PreviousAs[T any](in StepInput, stepID string) (T, bool) {
+	v, ok := in.PreviousOutputs[stepID].(T)
+	return v, ok
+}
+
+// VarAs 以指定类型获取上下文变量
+func VarAs[T any](in StepInput, key string) (T, bool) {
+	v, ok := in.Variables[key].(T)
+	return v, ok
+}
+
+// bindValue 将 v 赋值或经 JSON 往返转换到 dst 指向的值
+func bindValue(v, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dst)
+	}
+	elem := rv.Elem()
+	if v == nil {
+		elem.SetZero()
+		return nil
+	}
+	if src := reflect.ValueOf(v); src.Type().AssignableTo(elem.Type()) {
+		elem.Set(src)
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot encode %T: %w", v, err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("cannot convert %T to %s: %w", v, elem.Type(), err)
+	}
+	return nil
+}
+
+// toAnySlice 将任意切片或数组转换为 []any
+func toAnySlice(v any) ([]any, bool) {
+	if s, ok := v.([]any); ok {
+		return s, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out, true
+}
+
+// toInt 将数值转换为 int，有小数部分或溢出时失败
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int8:
+		return int(n), true
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint8:
+		return int(n), true
+	case uint16:
+		return int(n), true
+	case uint32:
+		return int(n), true
+	case uint:
+		if n > math.MaxInt {
+			return 0, false
+		}
+		return int(n), true
+	case uint64:
+		if n > math.MaxInt {
+			return 0, false
+		}
+		return int(n), true
+	case float32:
+		return floatToInt(float64(n))
+	case float64:
+		return floatToInt(n)
+	case json.Number:
+		i, err := n.Int64()
+		if err != nil {
+			return 0, false
+		}
+		return int(i), true
+	}
+	return 0, false
+}
+
+// floatToInt 仅转换无小数部分且在 int 范围内的浮点数
+func floatToInt(f float64) (int, bool) {
+	if f != math.Trunc(f) || f > math.MaxInt || f < math.MinInt {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// toFloat 将数值转换为 float64
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+		return f, true
+	}
+	if i, ok := toInt(v); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Stream 流式运行工作流，返回执行事件
+//
+// 事件依次包括 workflow_started、各步骤的 step_started / step_completed / step_failed，
+// 以及最终的 workflow_completed（Data 为 *WorkflowOutput）、workflow_failed 或 workflow_cancelled。
+// 并行步骤的子步骤事件与外层步骤事件交错发送，StepID 为并行步骤 ID，BranchID 为子步骤 ID。
+//
+// 返回的 channel 在执行结束后关闭。
+// 调用者必须消费返回的 channel，否则执行会阻塞；不再需要时应取消传入的 context。
+func (e *Executor) Stream(ctx context.Context, wf *Workflow, input WorkflowInput) (<-chan *WorkflowEvent, error) {
+	stream := newEventStream(ctx)
+	executionID, err := e.runAsync(ctx, wf, input, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	stateVal, _ := e.executions.Load(executionID)
+	state := stateVal.(*executionState)
+	go func() {
+		// 执行退出后不再有事件写入
+		<-state.doneCh
+		stream.close()
+	}()
+
+	return stream.ch, nil
+}
+
+// eventStream 单次执行的事件流
+type eventStream struct {
+	ch     chan *WorkflowEvent
+	ctx    context.Context
+	mu     sync.Mutex
+	closed bool
+}
+
+// newEventStream 创建事件流，ctx 取消后丢弃后续事件
+func newEventStream(ctx context.Context) *eventStream {
+	return &eventStream{
+		ch:  make(chan *WorkflowEvent, 16),
+		ctx: ctx,
+	}
+}
+
+// send 发送事件，缓冲区满时等待消费
+func (s *eventStream) send(event *WorkflowEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- event:
+	case <-s.ctx.Done():
+	}
+}
+
+// close 关闭事件流
+func (s *eventStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// stepEventsKey 步骤事件回调的 context key
+type stepEventsKey struct{}
+
+// withStepEvents 在 context 中设置步骤事件回调，供组合步骤上报子步骤事件
+func withStepEvents(ctx context.Context, emit func(*WorkflowEvent)) context.Context {
+	return context.WithValue(ctx, stepEventsKey{}, emit)
+}
+
+// emitStepEvent 上报子步骤事件，context 中没有回调时忽略
+func emitStepEvent(ctx context.Context, event *WorkflowEvent) {
+	emit, ok := ctx.Value(stepEventsKey{}).(func(*WorkflowEvent))
+	if !ok {
+		return
+	}
+	event.Timestamp = time.Now()
+	emit(event)
+}
@@ -41,6 +41,8 @@ const (
 	StatusFailed WorkflowStatus = "failed"
 	// StatusCancelled 已取消
 	StatusCancelled WorkflowStatus = "cancelled"
+	// StatusSkipped 已跳过（仅用于步骤）
+	StatusSkipped WorkflowStatus = "skipped"
 )
 
 // Workflow 工作流定义
@@ -72,6 +74,12 @@ type Workflow struct {
 	// RetryPolicy 默认重试策略
 	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
 
+	// Compensations 步骤补偿函数（步骤 ID -> 补偿函数）
+	Compensations map[string]CompensateFunc `json:"-"`
+
+	// ErrorHandlers 工作流失败处理函数
+	ErrorHandlers []ErrorHandler `json:"-"`
+
 	// CreatedAt 创建时间
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -123,6 +131,9 @@ type WorkflowExecution struct {
 	// StepResults 步骤执行结果
 	StepResults map[string]*StepResult `json:"step_results"`
 
+	// Compensations 失败后执行的补偿结果
+	Compensations []CompensationResult `json:"compensations,omitempty"`
+
 	// StartedAt 开始时间
 	StartedAt time.Time `json:"started_at"`
 
@@ -274,6 +285,9 @@ type WorkflowEvent struct {
 	// StepID 步骤 ID（如果适用）
 	StepID string `json:"step_id,omitempty"`
 
+	// BranchID 并行分支 ID，仅并行步骤的子步骤事件设置，值为子步骤 ID
+	BranchID string `json:"branch_id,omitempty"`
+
 	// Status 状态
 	Status WorkflowStatus `json:"status"`
 
@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/events"
+	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/testing/mock"
 )
 
 func TestWorkflowBuilder(t *testing.T) {
@@ -1874,3 +1879,417 @@ func TestExecutor_Concurrent(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestStepInput_TypedAccess(t *testing.T) {
+	input := StepInput{
+		PreviousOutputs: map[string]any{
+			"extract": []string{"a", "b"},
+			"summary": "done",
+			"analyze": map[string]any{"score": 1},
+		},
+		Variables: map[string]any{
+			"level":   "high",
+			"count":   float64(3), // JSON 解码后的整数
+			"ratio":   json.Number("0.5"),
+			"enabled": true,
+		},
+	}
+
+	if s, ok := input.PreviousString("summary"); !ok || s != "done" {
+		t.Errorf("PreviousString: got %q, %v", s, ok)
+	}
+	if _, ok := input.PreviousString("extract"); ok {
+		t.Error("PreviousString should fail on type mismatch")
+	}
+	if items, ok := input.PreviousSlice("extract"); !ok || len(items) != 2 || items[1] != "b" {
+		t.Errorf("PreviousSlice: got %v, %v", items, ok)
+	}
+	if _, ok := input.PreviousSlice("summary"); ok {
+		t.Error("PreviousSlice should fail on non-slice")
+	}
+	if m, ok := input.PreviousMap("analyze"); !ok || m["score"] != 1 {
+		t.Errorf("PreviousMap: got %v, %v", m, ok)
+	}
+	if items, ok := PreviousAs[[]string](input, "extract"); !ok || items[0] != "a" {
+		t.Errorf("PreviousAs: got %v, %v", items, ok)
+	}
+	if _, ok := input.Previous("missing"); ok {
+		t.Error("Previous should fail on missing step")
+	}
+
+	if v, ok := input.VarString("level"); !ok || v != "high" {
+		t.Errorf("VarString: got %q, %v", v, ok)
+	}
+	if n, ok := input.VarInt("count"); !ok || n != 3 {
+		t.Errorf("VarInt: got %d, %v", n, ok)
+	}
+	if _, ok := input.VarInt("ratio"); ok {
+		t.Error("VarInt should fail on fractional number")
+	}
+	if f, ok := input.VarFloat("ratio"); !ok || f != 0.5 {
+		t.Errorf("VarFloat: got %v, %v", f, ok)
+	}
+	if b, ok := input.VarBool("enabled"); !ok || !b {
+		t.Errorf("VarBool: got %v, %v", b, ok)
+	}
+	if _, ok := input.VarBool("level"); ok {
+		t.Error("VarBool should fail on type mismatch")
+	}
+
+	// nil map 不应 panic
+	var empty StepInput
+	if _, ok := empty.VarString("x"); ok {
+		t.Error("expected ok=false on empty input")
+	}
+	if _, ok := empty.PreviousSlice("x"); ok {
+		t.Error("expected ok=false on empty input")
+	}
+}
+
+func TestStepInput_Bind(t *testing.T) {
+	type report struct {
+		Score float64  `json:"score"`
+		Tags  []string `json:"tags"`
+	}
+	input := StepInput{
+		Data: map[string]any{"score": 0.8, "tags": []any{"x"}},
+		PreviousOutputs: map[string]any{
+			"analyze": map[string]any{"score": 0.9, "tags": []string{"a", "b"}, "extra": true},
+			"direct":  report{Score: 1},
+			"summary": "done",
+			"skip":    Skipped,
+		},
+	}
+
+	var r report
+	if err := input.BindPrevious("analyze", &r); err != nil {
+		t.Fatalf("BindPrevious: %v", err)
+	}
+	if r.Score != 0.9 || len(r.Tags) != 2 || r.Tags[1] != "b" {
+		t.Errorf("BindPrevious: got %+v", r)
+	}
+	if err := input.BindPrevious("direct", &r); err != nil || r.Score != 1 || r.Tags != nil {
+		t.Errorf("BindPrevious direct: got %+v, %v", r, err)
+	}
+	if err := input.BindData(&r); err != nil || r.Score != 0.8 || r.Tags[0] != "x" {
+		t.Errorf("BindData: got %+v, %v", r, err)
+	}
+
+	err := input.BindPrevious("summary", &r)
+	if err == nil || !strings.Contains(err.Error(), "summary") {
+		t.Errorf("expected mismatch error naming the step, got %v", err)
+	}
+	if err := input.BindPrevious("missing", &r); err == nil {
+		t.Error("expected error on missing step")
+	}
+	if err := input.BindPrevious("skip", &r); err == nil || !strings.Contains(err.Error(), "skipped") {
+		t.Errorf("expected skipped error, got %v", err)
+	}
+	if err := input.BindPrevious("analyze", r); err == nil {
+		t.Error("expected error on non-pointer destination")
+	}
+}
+
+func TestExecutor_Compensation(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(s string) {
+		mu.Lock()
+		order = append(order, s)
+		mu.Unlock()
+	}
+	ok := func(id string) StepFunc {
+		return func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return &StepOutput{Data: id + "-out"}, nil
+		}
+	}
+
+	var handled *WorkflowError
+	wf, err := New("saga").
+		AddFunc("reserve", "Reserve", ok("reserve")).
+		AddFunc("charge", "Charge", ok("charge")).
+		AddFunc("notify", "Notify", ok("notify")).
+		AddFunc("ship", "Ship", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return nil, errors.New("out of stock")
+		}).
+		WithCompensation("reserve", func(ctx context.Context, input StepInput) error {
+			record("undo " + input.Data.(string))
+			return nil
+		}).
+		WithCompensation("charge", func(ctx context.Context, input StepInput) error {
+			record("undo " + input.Data.(string))
+			return errors.New("refund rejected")
+		}).
+		OnError(func(ctx context.Context, err *WorkflowError) {
+			record("on error")
+			handled = err
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	_, err = NewExecutor().Run(context.Background(), wf, WorkflowInput{})
+	if err == nil {
+		t.Fatal("expected workflow error")
+	}
+
+	want := []string{"undo charge-out", "undo reserve-out", "on error"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("expected order %v, got %v", want, order)
+	}
+
+	var wfErr *WorkflowError
+	if !errors.As(err, &wfErr) {
+		t.Fatalf("expected *WorkflowError, got %T", err)
+	}
+	if wfErr != handled {
+		t.Error("expected OnError to receive the same error")
+	}
+	if wfErr.StepID != "ship" {
+		t.Errorf("expected failed step ship, got %s", wfErr.StepID)
+	}
+	if len(wfErr.Compensations) != 2 || wfErr.Compensations[0].Succeeded() || !wfErr.Compensations[1].Succeeded() {
+		t.Errorf("unexpected compensation results: %+v", wfErr.Compensations)
+	}
+	if !wfErr.CompensationFailed() {
+		t.Error("expected CompensationFailed")
+	}
+	want2 := "step ship failed: out of stock; compensations: charge failed: refund rejected, reserve ok"
+	if wfErr.Error() != want2 {
+		t.Errorf("unexpected message: %s", wfErr.Error())
+	}
+}
+
+func TestBuilder_CompensationUnknownStep(t *testing.T) {
+	_, err := New("saga").
+		AddFunc("step1", "Step 1", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return nil, nil
+		}).
+		WithCompensation("missing", func(ctx context.Context, input StepInput) error { return nil }).
+		Build()
+	if err == nil {
+		t.Error("expected error for compensation on unknown step")
+	}
+}
+
+func TestExecutor_Stream(t *testing.T) {
+	executor := NewExecutor()
+	noop := func(ctx context.Context, input StepInput) (*StepOutput, error) {
+		return &StepOutput{Data: "ok"}, nil
+	}
+
+	wf := New("stream").
+		AddFunc("prepare", "Prepare", noop).
+		Parallel("fanout", "Fanout",
+			NewStep("a", "A", noop),
+			NewStep("b", "B", noop),
+		).
+		MustBuild()
+
+	events, err := executor.Stream(context.Background(), wf, WorkflowInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*WorkflowEvent
+	for event := range events {
+		got = append(got, event)
+	}
+	if len(got) == 0 || got[0].Type != EventWorkflowStarted {
+		t.Fatalf("expected workflow_started first, got %+v", got)
+	}
+
+	last := got[len(got)-1]
+	if last.Type != EventWorkflowCompleted {
+		t.Fatalf("expected workflow_completed last, got %s", last.Type)
+	}
+	if output, ok := last.Data.(*WorkflowOutput); !ok || output.StepOutputs["prepare"] != "ok" {
+		t.Errorf("expected workflow output in completed event, got %+v", last.Data)
+	}
+
+	branches := make(map[string][]WorkflowEventType)
+	for _, event := range got {
+		if event.BranchID != "" {
+			if event.StepID != "fanout" {
+				t.Errorf("branch event should carry parallel step ID, got %q", event.StepID)
+			}
+			branches[event.BranchID] = append(branches[event.BranchID], event.Type)
+		}
+	}
+	for _, id := range []string{"a", "b"} {
+		types := branches[id]
+		if len(types) != 2 || types[0] != EventStepStarted || types[1] != EventStepCompleted {
+			t.Errorf("unexpected events for branch %s: %v", id, types)
+		}
+	}
+}
+
+func TestExecutor_StreamFailure(t *testing.T) {
+	executor := NewExecutor()
+	wf := New("stream").
+		AddFunc("broken", "Broken", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return nil, errors.New("boom")
+		}).
+		MustBuild()
+
+	events, err := executor.Stream(context.Background(), wf, WorkflowInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []WorkflowEventType
+	for event := range events {
+		types = append(types, event.Type)
+	}
+	want := []WorkflowEventType{EventWorkflowStarted, EventStepStarted, EventStepFailed, EventWorkflowFailed}
+	if fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, types)
+	}
+}
+
+func TestWorkflow_SkipIf(t *testing.T) {
+	var sendCalled atomic.Bool
+	wf := New("skip").
+		AddFunc("prepare", "Prepare", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return &StepOutput{Data: "payload", Variables: map[string]any{"opted_out": true}}, nil
+		}).
+		AddFunc("send", "Send Email", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			sendCalled.Store(true)
+			return &StepOutput{Data: "sent"}, nil
+		}, WithSkipIf(func(input StepInput) bool {
+			optedOut, _ := input.VarBool("opted_out")
+			return optedOut
+		})).
+		AddFunc("report", "Report", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			if !input.WasSkipped("send") {
+				return nil, errors.New("expected send to be skipped")
+			}
+			return &StepOutput{Data: fmt.Sprintf("%v (email skipped)", input.Data)}, nil
+		}).
+		MustBuild()
+
+	executor := NewExecutor()
+	var skippedEvents atomic.Int32
+	executor.OnEvent(func(event *WorkflowEvent) {
+		if event.Type == EventStepSkipped && event.StepID == "send" {
+			skippedEvents.Add(1)
+		}
+	})
+
+	out, err := executor.Run(context.Background(), wf, WorkflowInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sendCalled.Load() {
+		t.Error("skipped step should not execute")
+	}
+	if out.Data != "payload (email skipped)" {
+		t.Errorf("expected data to pass through skipped step, got %v", out.Data)
+	}
+	if skippedEvents.Load() != 1 {
+		t.Errorf("expected 1 step_skipped event, got %d", skippedEvents.Load())
+	}
+}
+
+func TestParallelStep_SkipIf(t *testing.T) {
+	skip := func(StepInput) bool { return true }
+	ps := NewParallelStep("p", "P", []Step{
+		NewStep("a", "A", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return &StepOutput{Data: "a"}, nil
+		}),
+		NewStep("b", "B", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return nil, errors.New("should not run")
+		}, WithSkipIf(skip)),
+	})
+
+	out, err := ps.Execute(context.Background(), StepInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := out.Data.(map[string]any)
+	if outputs["a"] != "a" || outputs["b"] != Skipped {
+		t.Errorf("unexpected outputs: %v", outputs)
+	}
+}
+
+func TestWorkflow_Retrieve(t *testing.T) {
+	retriever := mock.NewRetriever(mock.WithDocuments([]rag.Document{
+		{ID: "1", Content: "alpha"},
+		{ID: "2", Content: "beta"},
+	}))
+
+	wf, err := New("rag").
+		Retrieve("retrieve", "检索", retriever, nil, rag.WithTopK(2)).
+		AddFunc("generate", "生成", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			docs, ok := PreviousAs[[]rag.Document](input, "retrieve")
+			if !ok {
+				return nil, fmt.Errorf("unexpected retrieve output %T", input.PreviousOutputs["retrieve"])
+			}
+			return &StepOutput{Data: fmt.Sprintf("%d docs", len(docs))}, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	result, err := NewExecutor().Run(context.Background(), wf, WorkflowInput{Data: "query"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Data != "2 docs" {
+		t.Errorf("expected 2 documents, got %v", result.Data)
+	}
+	if calls := retriever.RetrieveCalls(); len(calls) != 1 || calls[0] != "query" {
+		t.Errorf("expected input data as query, got %v", calls)
+	}
+
+	if _, err := NewExecutor().Run(context.Background(), wf, WorkflowInput{Data: 42}); err == nil {
+		t.Error("expected error for non-string query")
+	}
+}
+
+func TestExecutor_WithEventBus(t *testing.T) {
+	wf, _ := New("bus-workflow").
+		AddFunc("step1", "Step 1", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return &StepOutput{Data: "ok"}, nil
+		}).
+		AddFunc("step2", "Step 2", func(ctx context.Context, input StepInput) (*StepOutput, error) {
+			return nil, errors.New("boom")
+		}).
+		Build()
+
+	bus := events.NewBus[events.Lifecycle]()
+	var mu sync.Mutex
+	var got []events.Lifecycle
+	bus.Subscribe(func(ctx context.Context, e events.Lifecycle) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e)
+	})
+
+	executor := NewExecutor(WithEventBus(bus))
+	if _, err := executor.Run(context.Background(), wf, WorkflowInput{}); err == nil {
+		t.Fatal("expected workflow to fail")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) == 0 || got[0].Type != events.RunStarted || got[len(got)-1].Type != events.RunFailed {
+		t.Fatalf("unexpected events: %+v", got)
+	}
+	var stepFailed bool
+	for _, e := range got {
+		if e.Source != events.SourceWorkflow || e.Name != "bus-workflow" || e.RunID == "" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		if e.Type == events.StepFailed {
+			stepFailed = e.Target == "step2" && e.Err != nil
+		}
+	}
+	if !stepFailed {
+		t.Errorf("expected step.failed for step2, got %+v", got)
+	}
+}
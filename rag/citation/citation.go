@@ -7,6 +7,8 @@
 //   - 自动识别需要引用的内容
 //   - 在回答中添加引用标记 [1], [2] 等
 //   - 追踪引用与来源的对应关系
+//   - 标记无法对应到来源的引用（模型编造的编号）
+//   - ParentDocRetriever 的结果记录支撑引用的子块
 //   - 支持多种引用格式
 //
 // 使用示例：
@@ -30,6 +32,7 @@ import (
 
 	"github.com/hexagon-codes/ai-core/llm"
 	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/rag/retriever"
 )
 
 // CitationEngine 引用追踪引擎
@@ -82,6 +85,13 @@ type Citation struct {
 	// SourceURL 来源 URL（如果有）
 	SourceURL string `json:"source_url,omitempty"`
 
+	// ChunkID 支撑引用的子块 ID
+	// 来源为 ParentDocRetriever 返回的父文档时，记录得分最高的命中子块
+	ChunkID string `json:"chunk_id,omitempty"`
+
+	// ChunkContent 支撑引用的子块内容
+	ChunkContent string `json:"chunk_content,omitempty"`
+
 	// StartPosition 引用在回答中的起始位置
 	StartPosition int `json:"start_position,omitempty"`
 
@@ -103,6 +113,13 @@ type CitedResponse struct {
 	// Sources 来源文档列表
 	Sources []rag.Document `json:"sources"`
 
+	// References 引用编号 -> 来源文档，仅包含回答中实际引用的编号
+	References map[int]rag.Document `json:"references,omitempty"`
+
+	// Unresolved 无法对应到来源的引用（编号超出来源范围），SourceID 为空
+	// 通常说明模型编造了来源，界面上应提示而不是丢弃
+	Unresolved []Citation `json:"unresolved,omitempty"`
+
 	// Bibliography 参考文献列表（格式化）
 	Bibliography string `json:"bibliography,omitempty"`
 }
@@ -156,39 +173,56 @@ func New(retriever rag.Retriever, provider llm.Provider, opts ...Option) *Citati
 }
 
 // Query 执行带引用的查询
+//
+// 检索到的文档按顺序编号为 [1]..[n] 提供给模型，模型在回答中以 [n] 标注来源，
+// 返回的 References 为引用编号到来源文档的映射，Unresolved 为无法对应到来源的引用。
 func (e *CitationEngine) Query(ctx context.Context, query string) (*CitedResponse, error) {
-	response := &CitedResponse{
-		Citations: make([]Citation, 0),
-		Sources:   make([]rag.Document, 0),
-	}
-
 	// 1. 检索相关文档
 	docs, err := e.retriever.Retrieve(ctx, query, rag.WithTopK(e.topK))
 	if err != nil {
 		return nil, fmt.Errorf("检索失败: %w", err)
 	}
-	response.Sources = docs
 
 	// 2. 生成带引用的回答
-	citedContent, citations, err := e.generateWithCitations(ctx, query, docs)
+	citedContent, err := e.generateWithCitations(ctx, query, docs)
 	if err != nil {
 		return nil, fmt.Errorf("生成失败: %w", err)
 	}
 
-	response.Content = citedContent
-	response.Citations = citations
-
-	// 3. 提取原始内容（去除引用标记）
-	response.RawContent = e.stripCitations(citedContent)
+	// 3. 解析引用
+	return e.Resolve(citedContent, docs), nil
+}
 
-	// 4. 生成参考文献
-	response.Bibliography = e.generateBibliography(citations, docs)
+// Resolve 解析回答中的 [n] 引用标记，n 对应 docs 中的第 n 个文档
+//
+// 可用于解析其他途径生成的回答，如 Agent 使用 rag.NewSearchTool 检索后给出的回答。
+func (e *CitationEngine) Resolve(content string, docs []rag.Document) *CitedResponse {
+	citations, unresolved := e.resolveCitations(content, docs)
+
+	var references map[int]rag.Document
+	if len(citations) > 0 {
+		references = make(map[int]rag.Document, len(citations))
+		for _, c := range citations {
+			references[c.Index] = docs[c.Index-1]
+		}
+	}
 
-	return response, nil
+	if docs == nil {
+		docs = make([]rag.Document, 0)
+	}
+	return &CitedResponse{
+		Content:      content,
+		RawContent:   e.stripCitations(content),
+		Citations:    citations,
+		Sources:      docs,
+		References:   references,
+		Unresolved:   unresolved,
+		Bibliography: e.generateBibliography(citations, docs),
+	}
 }
 
 // generateWithCitations 生成带引用的回答
-func (e *CitationEngine) generateWithCitations(ctx context.Context, query string, docs []rag.Document) (string, []Citation, error) {
+func (e *CitationEngine) generateWithCitations(ctx context.Context, query string, docs []rag.Document) (string, error) {
 	// 构建带编号的来源信息
 	var sourcesBuilder strings.Builder
 	for i, doc := range docs {
@@ -202,7 +236,7 @@ func (e *CitationEngine) generateWithCitations(ctx context.Context, query string
 规则：
 1. 每个陈述或事实后面必须添加引用标记，如 [1], [2]
 2. 一个陈述可以有多个引用，如 [1][2]
-3. 只引用提供的来源，不要编造信息
+3. 只引用提供的来源编号 [1] 到 [%d]，不要编造信息或来源
 4. 如果来源无法回答问题，请说明
 
 来源：
@@ -210,7 +244,7 @@ func (e *CitationEngine) generateWithCitations(ctx context.Context, query string
 
 问题：%s
 
-请用中文回答，并在每个陈述后添加引用标记：`, sourcesBuilder.String(), query)
+请用中文回答，并在每个陈述后添加引用标记：`, len(docs), sourcesBuilder.String(), query)
 
 	resp, err := e.llm.Complete(ctx, llm.CompletionRequest{
 		Messages: []llm.Message{
@@ -218,18 +252,21 @@ func (e *CitationEngine) generateWithCitations(ctx context.Context, query string
 		},
 	})
 	if err != nil {
-		return "", nil, err
+		return "", err
 	}
-
-	// 解析引用
-	citations := e.parseCitations(resp.Content, docs)
-
-	return resp.Content, citations, nil
+	return resp.Content, nil
 }
 
 // parseCitations 解析回答中的引用
 func (e *CitationEngine) parseCitations(content string, docs []rag.Document) []Citation {
-	citations := make([]Citation, 0)
+	citations, _ := e.resolveCitations(content, docs)
+	return citations
+}
+
+// resolveCitations 解析回答中的引用，按编号首次出现的顺序返回
+// 编号超出来源范围的引用放入 unresolved
+func (e *CitationEngine) resolveCitations(content string, docs []rag.Document) (citations, unresolved []Citation) {
+	citations = make([]Citation, 0)
 	usedIndices := make(map[int]bool)
 
 	// 匹配引用标记 [数字]
@@ -246,33 +283,44 @@ func (e *CitationEngine) parseCitations(content string, docs []rag.Document) []C
 		var index int
 		fmt.Sscanf(indexStr, "%d", &index)
 
-		if index < 1 || index > len(docs) {
+		// 如果这个索引还没有被记录
+		if usedIndices[index] {
 			continue
 		}
+		usedIndices[index] = true
 
-		// 如果这个索引还没有被记录
-		if !usedIndices[index] {
-			usedIndices[index] = true
-			doc := docs[index-1]
-
-			citation := Citation{
-				Index:         index,
-				Marker:        e.formatMarker(index),
-				SourceID:      doc.ID,
-				SourceTitle:   e.extractTitle(doc),
-				SourceURL:     e.extractURL(doc),
-				StartPosition: match[0],
-				EndPosition:   match[1],
-			}
+		citation := Citation{
+			Index:         index,
+			Marker:        e.formatMarker(index),
+			StartPosition: match[0],
+			EndPosition:   match[1],
+		}
 
-			// 提取引用周围的文本作为被引用内容
-			citation.Text = e.extractCitedText(content, match[0])
+		// 提取引用周围的文本作为被引用内容
+		citation.Text = e.extractCitedText(content, match[0])
 
-			citations = append(citations, citation)
+		if index < 1 || index > len(docs) {
+			unresolved = append(unresolved, citation)
+			continue
 		}
+		e.attachSource(&citation, docs[index-1])
+		citations = append(citations, citation)
 	}
 
-	return citations
+	return citations, unresolved
+}
+
+// attachSource 将来源文档信息写入引用
+func (e *CitationEngine) attachSource(citation *Citation, doc rag.Document) {
+	citation.SourceID = doc.ID
+	citation.SourceTitle = e.extractTitle(doc)
+	citation.SourceURL = e.extractURL(doc)
+
+	// ParentDocRetriever 的结果记录了命中的子块（按分数降序），取最相关的一个
+	if children, ok := doc.Metadata[retriever.MetadataMatchedChildren].([]retriever.MatchedChild); ok && len(children) > 0 {
+		citation.ChunkID = children[0].ID
+		citation.ChunkContent = children[0].Content
+	}
 }
 
 // extractCitedText 提取引用标记前的文本
@@ -473,23 +521,29 @@ func (e *CitationEngine) parseStructuredResponse(content string, docs []rag.Docu
 		}, nil
 	}
 
-	// 构建引用列表
-	citations := make([]Citation, len(parsed.Citations))
-	for i, c := range parsed.Citations {
+	// 构建引用列表，来源编号超出范围的引用标记为无法解析
+	citations := make([]Citation, 0, len(parsed.Citations))
+	var unresolved []Citation
+	var references map[int]rag.Document
+	for _, c := range parsed.Citations {
+		citation := Citation{
+			Index:  c.Index,
+			Marker: e.formatMarker(c.Index),
+			Text:   c.Text,
+		}
+
 		sourceIndex := c.SourceIndex - 1
 		if sourceIndex < 0 || sourceIndex >= len(docs) {
-			sourceIndex = 0
+			unresolved = append(unresolved, citation)
+			continue
 		}
+		e.attachSource(&citation, docs[sourceIndex])
+		citations = append(citations, citation)
 
-		doc := docs[sourceIndex]
-		citations[i] = Citation{
-			Index:       c.Index,
-			Marker:      e.formatMarker(c.Index),
-			Text:        c.Text,
-			SourceID:    doc.ID,
-			SourceTitle: e.extractTitle(doc),
-			SourceURL:   e.extractURL(doc),
+		if references == nil {
+			references = make(map[int]rag.Document)
 		}
+		references[c.Index] = docs[sourceIndex]
 	}
 
 	return &CitedResponse{
@@ -497,6 +551,8 @@ func (e *CitationEngine) parseStructuredResponse(content string, docs []rag.Docu
 		RawContent:   e.stripCitations(parsed.Answer),
 		Citations:    citations,
 		Sources:      docs,
+		References:   references,
+		Unresolved:   unresolved,
 		Bibliography: e.generateBibliography(citations, docs),
 	}, nil
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/hexagon-codes/ai-core/llm"
 	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/rag/retriever"
 )
 
 // mockRetriever 模拟检索器
@@ -166,6 +167,45 @@ func TestCitationEngine_ParseCitations(t *testing.T) {
 	}
 }
 
+func TestCitationEngine_Resolve(t *testing.T) {
+	engine := New(&mockRetriever{}, &mockLLMProvider{})
+
+	docs := []rag.Document{
+		{ID: "doc1", Content: "Go 语言介绍", Source: "Go文档"},
+		{
+			ID:      "parent1",
+			Content: "完整的父文档内容",
+			Metadata: map[string]any{
+				retriever.MetadataMatchedChildren: []retriever.MatchedChild{
+					{ID: "parent1_chunk_2", ChunkIndex: 2, Score: 0.9, Content: "命中的子块"},
+					{ID: "parent1_chunk_0", ChunkIndex: 0, Score: 0.5, Content: "次要子块"},
+				},
+			},
+		},
+	}
+
+	resp := engine.Resolve("Go 是编程语言[1]。它支持并发[2]。它有 GC[3]。", docs)
+
+	if len(resp.Citations) != 2 {
+		t.Fatalf("expected 2 resolved citations, got %d", len(resp.Citations))
+	}
+	if resp.References[1].ID != "doc1" || resp.References[2].ID != "parent1" {
+		t.Errorf("unexpected references: %v", resp.References)
+	}
+
+	parent := resp.Citations[1]
+	if parent.SourceID != "parent1" || parent.ChunkID != "parent1_chunk_2" || parent.ChunkContent != "命中的子块" {
+		t.Errorf("expected parent citation with contributing chunk, got %+v", parent)
+	}
+
+	if len(resp.Unresolved) != 1 || resp.Unresolved[0].Index != 3 || resp.Unresolved[0].SourceID != "" {
+		t.Fatalf("expected citation [3] to be unresolved, got %+v", resp.Unresolved)
+	}
+	if _, ok := resp.References[3]; ok {
+		t.Error("unresolved citation should not appear in references")
+	}
+}
+
 func TestCitationEngine_FormatMarker(t *testing.T) {
 	tests := []struct {
 		style    CitationStyle
@@ -316,6 +356,25 @@ func TestCitationEngine_GenerateStructuredCitation(t *testing.T) {
 	}
 }
 
+func TestCitationEngine_GenerateStructuredCitation_Unresolved(t *testing.T) {
+	provider := &mockLLMProvider{
+		response: `{"answer": "Go 很快[1]。", "citations": [{"index": 1, "text": "Go 很快", "source_index": 4}]}`,
+	}
+	engine := New(&mockRetriever{}, provider)
+
+	docs := []rag.Document{{ID: "doc1", Content: "Go 语言介绍"}}
+	response, err := engine.GenerateStructuredCitation(context.Background(), "Go 快吗?", docs)
+	if err != nil {
+		t.Fatalf("GenerateStructuredCitation failed: %v", err)
+	}
+	if len(response.Citations) != 0 {
+		t.Errorf("expected no resolved citations, got %+v", response.Citations)
+	}
+	if len(response.Unresolved) != 1 || response.Unresolved[0].Index != 1 {
+		t.Errorf("expected out-of-range source to be flagged, got %+v", response.Unresolved)
+	}
+}
+
 func TestExtractJSON(t *testing.T) {
 	tests := []struct {
 		input    string
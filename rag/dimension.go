@@ -0,0 +1,108 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// ErrDimensionMismatch 嵌入器输出的向量维度与向量存储的维度不一致
+var ErrDimensionMismatch = core.NewCategoryError("embedding dimension mismatch", core.ErrInvalidInput)
+
+// dimensionProbe 探测嵌入维度时向量化的文本
+const dimensionProbe = "dimension probe"
+
+// dimensioned 声明了向量维度的组件
+// 内置的向量存储（Memory、Redis、pgvector、Milvus、Pinecone、FAISS）均实现了该接口
+type dimensioned interface {
+	Dimension() int
+}
+
+// EmbedderDimension 向量化一段探测文本，返回嵌入器实际输出的向量维度
+func EmbedderDimension(ctx context.Context, e Embedder) (int, error) {
+	embeddings, err := e.Embed(ctx, []string{dimensionProbe})
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe embedder dimension: %w", core.ClassifyError(err))
+	}
+	if len(embeddings) != 1 || len(embeddings[0]) == 0 {
+		return 0, fmt.Errorf("failed to probe embedder dimension: embedder returned no vector")
+	}
+	return len(embeddings[0]), nil
+}
+
+// StoreDimension 返回向量存储配置的维度，存储未声明维度时返回 0
+func StoreDimension(s vector.Store) int {
+	if d, ok := s.(dimensioned); ok {
+		return d.Dimension()
+	}
+	return 0
+}
+
+// CheckDimension 校验嵌入器与向量存储的维度一致
+//
+// 向量化一次探测文本得到嵌入器的实际维度，分别与嵌入器声明的 Dimension()
+// 和存储配置的维度比较（未声明的一方跳过）。维度不一致是最常见的 RAG 配置错误，
+// 不校验时要到写入或检索时才在存储内部报出难以理解的错误。
+func CheckDimension(ctx context.Context, e Embedder, s vector.Store) error {
+	actual, err := EmbedderDimension(ctx, e)
+	if err != nil {
+		return err
+	}
+	if declared := e.Dimension(); declared > 0 && declared != actual {
+		return fmt.Errorf("%w: embedder declares dim %d but produced %d", ErrDimensionMismatch, declared, actual)
+	}
+	if s == nil {
+		return nil
+	}
+	if storeDim := StoreDimension(s); storeDim > 0 && storeDim != actual {
+		return fmt.Errorf("%w: embedder dim %d != store dim %d", ErrDimensionMismatch, actual, storeDim)
+	}
+	return nil
+}
+
+// CheckEmbeddings 在写入或检索前检查向量维度与存储维度一致
+// 存储未声明维度时不检查
+func CheckEmbeddings(s vector.Store, embeddings ...[]float32) error {
+	storeDim := StoreDimension(s)
+	if storeDim <= 0 {
+		return nil
+	}
+	for _, emb := range embeddings {
+		if len(emb) != storeDim {
+			return fmt.Errorf("%w: embedder dim %d != store dim %d", ErrDimensionMismatch, len(emb), storeDim)
+		}
+	}
+	return nil
+}
+
+// Validate 校验引擎配置，建议在创建引擎后调用以尽早发现配置错误
+//
+// 检查向量存储和嵌入器均已配置，并向量化一次探测文本确认嵌入维度与存储维度一致，
+// 配置了 WithImageEmbedder 时同样检查图片嵌入器。
+// 未调用时，维度不一致会在首次索引或检索时以 ErrDimensionMismatch 报出。
+//
+// 示例：
+//
+//	engine := rag.NewEngine(rag.WithStore(store), rag.WithEngineEmbedder(embedder))
+//	if err := engine.Validate(ctx); err != nil {
+//	    log.Fatal(err) // embedding dimension mismatch: embedder dim 1536 != store dim 384
+//	}
+func (e *Engine) Validate(ctx context.Context) error {
+	if e.store == nil {
+		return ErrStoreRequired
+	}
+	if e.embedder == nil {
+		return ErrEmbedderRequired
+	}
+	if err := CheckDimension(ctx, e.embedder, e.store); err != nil {
+		return err
+	}
+	if e.imageEmbedder != nil {
+		if err := CheckDimension(ctx, e.imageEmbedder, e.store); err != nil {
+			return fmt.Errorf("image embedder: %w", err)
+		}
+	}
+	return nil
+}
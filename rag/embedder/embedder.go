@@ -4,6 +4,8 @@
 //   - OpenAIEmbedder: 使用 OpenAI Embedding API
 //   - CachedEmbedder: 带缓存的 Embedder 包装器（带 LRU 淘汰和防击穿）
 //   - BatchEmbedder: 批量处理的 Embedder 包装器
+//   - MockEmbedder: 基于文本哈希的确定性向量（用于测试）
+//   - BenchEmbedder: 可注入延迟和错误率的内存 Embedder（用于并发测试和基准测试）
 package embedder
 
 import (
@@ -11,8 +13,12 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/hexagon-codes/hexagon/store/vector"
 	"golang.org/x/sync/singleflight"
@@ -39,6 +45,9 @@ type OpenAIEmbedder struct {
 	model     string
 	dimension int
 	batchSize int
+
+	// observed 未声明维度时，从向量化结果中得到的维度
+	observed atomic.Int64
 }
 
 // OpenAIOption OpenAIEmbedder 选项
@@ -52,6 +61,7 @@ func WithModel(model string) OpenAIOption {
 }
 
 // WithDimension 设置向量维度
+// 默认值: 1536；设为 0 时不声明维度，由首次向量化的结果确定
 func WithDimension(dim int) OpenAIOption {
 	return func(e *OpenAIEmbedder) {
 		e.dimension = dim
@@ -117,6 +127,7 @@ func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32
 		allEmbeddings = append(allEmbeddings, embeddings...)
 	}
 
+	observeDimension(&e.observed, e.dimension, allEmbeddings)
 	return allEmbeddings, nil
 }
 
@@ -133,8 +144,12 @@ func (e *OpenAIEmbedder) EmbedOne(ctx context.Context, text string) ([]float32,
 }
 
 // Dimension 返回向量维度
+// 未声明维度时返回已向量化结果的维度，尚未向量化时返回 0
 func (e *OpenAIEmbedder) Dimension() int {
-	return e.dimension
+	if e.dimension > 0 {
+		return e.dimension
+	}
+	return int(e.observed.Load())
 }
 
 var _ vector.Embedder = (*OpenAIEmbedder)(nil)
@@ -308,6 +323,8 @@ var _ vector.Embedder = (*CachedEmbedder)(nil)
 // ============== MockEmbedder ==============
 
 // MockEmbedder 模拟 Embedder（用于测试）
+//
+// 相同文本总是生成相同向量；创建后无可变状态，可并发调用。
 type MockEmbedder struct {
 	dimension int
 	fixedVec  []float32
@@ -361,15 +378,180 @@ func (e *MockEmbedder) Dimension() int {
 
 var _ vector.Embedder = (*MockEmbedder)(nil)
 
+// ============== BenchEmbedder ==============
+
+// ErrInjectedFailure BenchEmbedder 按错误率注入的错误
+var ErrInjectedFailure = errors.New("injected embedding failure")
+
+// BenchEmbedder 基准测试用的内存 Embedder
+//
+// 向量与 MockEmbedder 相同（确定性），每次 Embed 调用模拟一次 API 往返延迟，
+// 可按比例注入错误，并统计调用次数。所有方法可并发调用。
+type BenchEmbedder struct {
+	mock      *MockEmbedder
+	latency   time.Duration
+	jitter    time.Duration
+	errorRate float64
+	err       error
+
+	mu  sync.Mutex
+	rng *rand.Rand
+
+	calls         atomic.Int64
+	embedOneCalls atomic.Int64
+	texts         atomic.Int64
+	failures      atomic.Int64
+}
+
+// BenchOption BenchEmbedder 选项
+type BenchOption func(*BenchEmbedder)
+
+// WithBenchJitter 设置延迟抖动，每次调用额外等待 [0, jitter) 的随机时长
+func WithBenchJitter(jitter time.Duration) BenchOption {
+	return func(e *BenchEmbedder) {
+		e.jitter = jitter
+	}
+}
+
+// WithBenchErrorRate 设置错误率（0-1），按该比例的 Embed 调用返回错误
+func WithBenchErrorRate(rate float64) BenchOption {
+	return func(e *BenchEmbedder) {
+		e.errorRate = min(max(rate, 0), 1)
+	}
+}
+
+// WithBenchError 设置注入的错误，默认为 ErrInjectedFailure
+func WithBenchError(err error) BenchOption {
+	return func(e *BenchEmbedder) {
+		e.err = err
+	}
+}
+
+// WithBenchSeed 设置随机种子，相同种子下抖动和错误注入序列可复现
+func WithBenchSeed(seed uint64) BenchOption {
+	return func(e *BenchEmbedder) {
+		e.rng = rand.New(rand.NewPCG(seed, seed))
+	}
+}
+
+// NewBenchEmbedder 创建基准测试 Embedder
+// latency 为每次 Embed 调用的模拟延迟，0 表示不等待
+func NewBenchEmbedder(dimension int, latency time.Duration, opts ...BenchOption) *BenchEmbedder {
+	e := &BenchEmbedder{
+		mock:    NewMockEmbedder(dimension),
+		latency: latency,
+		err:     ErrInjectedFailure,
+		rng:     rand.New(rand.NewPCG(1, 1)),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Embed 模拟延迟后生成确定性向量，context 取消时提前返回
+func (e *BenchEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls.Add(1)
+	e.texts.Add(int64(len(texts)))
+
+	delay, fail := e.roll()
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	} else if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if fail {
+		e.failures.Add(1)
+		return nil, e.err
+	}
+	return e.mock.Embed(ctx, texts)
+}
+
+// roll 抽取本次调用的延迟和是否注入错误
+func (e *BenchEmbedder) roll() (time.Duration, bool) {
+	if e.jitter <= 0 && e.errorRate <= 0 {
+		return e.latency, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delay := e.latency
+	if e.jitter > 0 {
+		delay += time.Duration(e.rng.Int64N(int64(e.jitter)))
+	}
+	return delay, e.errorRate > 0 && e.rng.Float64() < e.errorRate
+}
+
+// EmbedOne 嵌入单个文本
+func (e *BenchEmbedder) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	e.embedOneCalls.Add(1)
+	embeddings, err := e.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// Dimension 返回向量维度
+func (e *BenchEmbedder) Dimension() int {
+	return e.mock.Dimension()
+}
+
+// BenchStats BenchEmbedder 调用统计
+type BenchStats struct {
+	// Calls Embed 调用次数（包含 EmbedOne 发起的调用）
+	Calls int64
+
+	// EmbedOneCalls EmbedOne 调用次数
+	EmbedOneCalls int64
+
+	// Texts 请求嵌入的文本总数
+	Texts int64
+
+	// Failures 注入错误的次数
+	Failures int64
+}
+
+// Stats 返回调用统计
+func (e *BenchEmbedder) Stats() BenchStats {
+	return BenchStats{
+		Calls:         e.calls.Load(),
+		EmbedOneCalls: e.embedOneCalls.Load(),
+		Texts:         e.texts.Load(),
+		Failures:      e.failures.Load(),
+	}
+}
+
+// ResetStats 清零调用统计
+func (e *BenchEmbedder) ResetStats() {
+	e.calls.Store(0)
+	e.embedOneCalls.Store(0)
+	e.texts.Store(0)
+	e.failures.Store(0)
+}
+
+var _ vector.Embedder = (*BenchEmbedder)(nil)
+
 // ============== FuncEmbedder ==============
 
 // FuncEmbedder 函数式 Embedder
 type FuncEmbedder struct {
 	embedFn   func(ctx context.Context, texts []string) ([][]float32, error)
 	dimension int
+
+	// observed 未声明维度时，从向量化结果中得到的维度
+	observed atomic.Int64
 }
 
 // NewFuncEmbedder 创建函数式 Embedder
+// dimension 为 0 时不声明维度，由首次向量化的结果确定
 func NewFuncEmbedder(dimension int, fn func(ctx context.Context, texts []string) ([][]float32, error)) *FuncEmbedder {
 	return &FuncEmbedder{
 		embedFn:   fn,
@@ -379,7 +561,11 @@ func NewFuncEmbedder(dimension int, fn func(ctx context.Context, texts []string)
 
 // Embed 调用函数生成向量
 func (e *FuncEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
-	return e.embedFn(ctx, texts)
+	embeddings, err := e.embedFn(ctx, texts)
+	if err == nil {
+		observeDimension(&e.observed, e.dimension, embeddings)
+	}
+	return embeddings, err
 }
 
 // EmbedOne 嵌入单个文本
@@ -395,14 +581,26 @@ func (e *FuncEmbedder) EmbedOne(ctx context.Context, text string) ([]float32, er
 }
 
 // Dimension 返回向量维度
+// 未声明维度时返回已向量化结果的维度，尚未向量化时返回 0
 func (e *FuncEmbedder) Dimension() int {
-	return e.dimension
+	if e.dimension > 0 {
+		return e.dimension
+	}
+	return int(e.observed.Load())
 }
 
 var _ vector.Embedder = (*FuncEmbedder)(nil)
 
 // ============== 辅助函数 ==============
 
+// observeDimension 未声明维度时记录向量化结果的维度
+func observeDimension(observed *atomic.Int64, declared int, embeddings [][]float32) {
+	if declared > 0 || len(embeddings) == 0 || len(embeddings[0]) == 0 {
+		return
+	}
+	observed.Store(int64(len(embeddings[0])))
+}
+
 // hashText 计算文本的 MD5 哈希
 func hashText(text string) string {
 	hash := md5.Sum([]byte(text))
@@ -3,7 +3,9 @@ package embedder
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 )
 
 // mockEmbeddingProvider 模拟的嵌入提供者
@@ -372,3 +374,118 @@ func TestHashText(t *testing.T) {
 		t.Errorf("expected hash length 32, got %d", len(hash1))
 	}
 }
+
+func TestBenchEmbedderConcurrent(t *testing.T) {
+	embedder := NewBenchEmbedder(32, time.Millisecond)
+	want, _ := NewMockEmbedder(32).EmbedOne(context.Background(), "doc")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vec, err := embedder.EmbedOne(context.Background(), "doc")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			for j := range vec {
+				if vec[j] != want[j] {
+					t.Errorf("expected deterministic vector, got %v", vec)
+					return
+				}
+			}
+			if _, err := embedder.Embed(context.Background(), []string{"a", "b"}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := embedder.Stats()
+	if stats.Calls != 100 || stats.EmbedOneCalls != 50 || stats.Texts != 150 || stats.Failures != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	embedder.ResetStats()
+	if embedder.Stats() != (BenchStats{}) {
+		t.Errorf("expected zero stats after reset, got %+v", embedder.Stats())
+	}
+}
+
+func TestBenchEmbedderLatency(t *testing.T) {
+	embedder := NewBenchEmbedder(8, 20*time.Millisecond)
+
+	start := time.Now()
+	if _, err := embedder.EmbedOne(context.Background(), "x"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected at least 20ms latency, got %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	slow := NewBenchEmbedder(8, time.Second)
+	if _, err := slow.Embed(ctx, []string{"x"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestBenchEmbedderErrorRate(t *testing.T) {
+	embedder := NewBenchEmbedder(8, 0, WithBenchErrorRate(0.3), WithBenchSeed(42))
+
+	var failures int
+	for i := 0; i < 1000; i++ {
+		if _, err := embedder.EmbedOne(context.Background(), "x"); err != nil {
+			if !errors.Is(err, ErrInjectedFailure) {
+				t.Fatalf("expected ErrInjectedFailure, got %v", err)
+			}
+			failures++
+		}
+	}
+	if failures < 200 || failures > 400 {
+		t.Errorf("expected about 300 failures, got %d", failures)
+	}
+	if embedder.Stats().Failures != int64(failures) {
+		t.Errorf("expected Failures %d, got %d", failures, embedder.Stats().Failures)
+	}
+
+	custom := errors.New("quota exceeded")
+	always := NewBenchEmbedder(8, 0, WithBenchErrorRate(1), WithBenchError(custom))
+	if _, err := always.Embed(context.Background(), []string{"x"}); !errors.Is(err, custom) {
+		t.Errorf("expected custom error, got %v", err)
+	}
+}
+
+func BenchmarkBenchEmbedderParallel(b *testing.B) {
+	embedder := NewBenchEmbedder(256, 0)
+	texts := []string{"alpha", "beta", "gamma", "delta"}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := embedder.Embed(context.Background(), texts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestFuncEmbedderLazyDimension(t *testing.T) {
+	embedder := NewFuncEmbedder(0, func(ctx context.Context, texts []string) ([][]float32, error) {
+		result := make([][]float32, len(texts))
+		for i := range texts {
+			result[i] = make([]float32, 768)
+		}
+		return result, nil
+	})
+
+	if embedder.Dimension() != 0 {
+		t.Fatalf("expected undeclared dimension before embedding, got %d", embedder.Dimension())
+	}
+	if _, err := embedder.Embed(context.Background(), []string{"probe"}); err != nil {
+		t.Fatal(err)
+	}
+	if embedder.Dimension() != 768 {
+		t.Errorf("expected dimension 768 after embedding, got %d", embedder.Dimension())
+	}
+}
@@ -3,22 +3,52 @@ package rag
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/observe/logger"
 	"github.com/hexagon-codes/hexagon/store/vector"
 )
 
+var (
+	// ErrStoreRequired 未配置向量存储
+	ErrStoreRequired = core.NewCategoryError("store is required", core.ErrNotConfigured)
+
+	// ErrEmbedderRequired 未配置 Embedder
+	ErrEmbedderRequired = core.NewCategoryError("embedder is required", core.ErrNotConfigured)
+)
+
 // Engine RAG 引擎
 // 提供完整的 RAG 能力：文档加载、分割、索引、检索
 type Engine struct {
 	store    vector.Store
 	embedder Embedder
 	loader   Loader
+
+	// imageEmbedder 向量化图片文档的嵌入器，见 WithImageEmbedder
+	imageEmbedder MultimodalEmbedder
+
 	splitter Splitter
 	indexer  Indexer
 
+	// transformers 索引前依次执行的文档转换器
+	transformers []Transformer
+
+	// dedup 索引去重模式
+	dedup DedupMode
+
 	// 配置
 	topK     int
 	minScore float32
+
+	// fallback 检索失败时的降级模式
+	fallback FallbackMode
+
+	// lastResults FallbackLastResult 模式下各查询最近一次成功的结果
+	lastResults resultCache
+
+	// expiryFilter 是否在向量存储中按过期时间过滤
+	expiryFilter bool
 }
 
 // EngineOption Engine 配置选项
@@ -52,6 +82,31 @@ func WithEngineSplitter(splitter Splitter) EngineOption {
 	}
 }
 
+// WithTransformers 设置索引前执行的文档转换器
+// 转换器按顺序执行，可用于补充元数据、去重、提取标题等
+func WithTransformers(transformers ...Transformer) EngineOption {
+	return func(e *Engine) {
+		e.transformers = append(e.transformers, transformers...)
+	}
+}
+
+// WithDedup 设置索引去重模式
+//
+// 启用去重（DedupSkip / DedupUpdate）后以 Document.ContentHash 判断重复：
+//   - 文档元数据写入 content_hash
+//   - 存储 ID 由内容哈希生成，重复摄取同一内容（即使 ID 不同）会得到同一 ID；
+//     调用方指定的 ID 与之不同时保存在元数据 source_id 中
+//   - 内容哈希已存在的文档视为重复，按模式跳过或覆盖
+//
+// 内容变化的文档按新文档写入，旧版本需要调用方删除。
+//
+// 默认值: DedupAllow
+func WithDedup(mode DedupMode) EngineOption {
+	return func(e *Engine) {
+		e.dedup = mode
+	}
+}
+
 // WithEngineTopK 设置默认返回数量
 func WithEngineTopK(k int) EngineOption {
 	return func(e *Engine) {
@@ -69,8 +124,9 @@ func WithEngineMinScore(score float32) EngineOption {
 // NewEngine 创建 RAG 引擎
 func NewEngine(opts ...EngineOption) *Engine {
 	e := &Engine{
-		topK:     5,
-		minScore: 0.0,
+		topK:        5,
+		minScore:    0.0,
+		lastResults: resultCache{size: defaultFallbackCacheSize},
 	}
 	for _, opt := range opts {
 		opt(e)
@@ -79,55 +135,76 @@ func NewEngine(opts ...EngineOption) *Engine {
 }
 
 // Ingest 摄取文档
-// 从 loader 加载文档，分割后索引到向量存储
+// 从 loader 加载文档，分割后索引到向量存储；需要吞吐统计时使用 IngestWithStats
 func (e *Engine) Ingest(ctx context.Context) error {
-	if e.loader == nil {
-		return fmt.Errorf("loader is required for ingestion")
-	}
+	_, err := e.IngestWithStats(ctx)
+	return err
+}
+
+// IndexDocuments 索引文档列表
+func (e *Engine) IndexDocuments(ctx context.Context, docs []Document, opts ...IndexOption) error {
+	_, err := e.IndexWithResult(ctx, docs, opts...)
+	return err
+}
+
+// IndexWithResult 索引文档列表并返回新增/跳过/更新的数量
+// 未启用去重时所有文档计为新增
+func (e *Engine) IndexWithResult(ctx context.Context, docs []Document, opts ...IndexOption) (*IndexResult, error) {
 	if e.store == nil {
-		return fmt.Errorf("store is required for ingestion")
+		return nil, ErrStoreRequired
 	}
 	if e.embedder == nil {
-		return fmt.Errorf("embedder is required for ingestion")
+		return nil, ErrEmbedderRequired
 	}
-
-	// 1. 加载文档
-	docs, err := e.loader.Load(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to load documents: %w", err)
+	cfg := &indexConfig{}
+	for _, opt := range opts {
+		opt(cfg)
 	}
-
-	// 2. 分割文档
-	if e.splitter != nil {
-		docs, err = e.splitter.Split(ctx, docs)
-		if err != nil {
-			return fmt.Errorf("failed to split documents: %w", err)
-		}
+	if cfg.stats != nil {
+		start := time.Now()
+		defer func() { cfg.stats.Duration += time.Since(start) }()
+		cfg.stats.Documents += len(docs)
 	}
-
-	// 3. 索引文档
-	return e.IndexDocuments(ctx, docs)
+	return e.indexBatch(ctx, applyTTL(docs, opts), cfg.stats)
 }
 
-// IndexDocuments 索引文档列表
-func (e *Engine) IndexDocuments(ctx context.Context, docs []Document) error {
-	if e.store == nil {
-		return fmt.Errorf("store is required")
+// indexBatch 对一批文档执行转换、去重、向量化并写入存储
+// stats 非 nil 时累加各阶段耗时（不含 Documents 和 Duration，由调用方统计）
+func (e *Engine) indexBatch(ctx context.Context, docs []Document, stats *IndexStats) (*IndexResult, error) {
+	// 执行转换器
+	transformStart := time.Now()
+	for _, t := range e.transformers {
+		var err error
+		docs, err = t.Transform(ctx, docs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform documents with %s: %w", t.Name(), err)
+		}
 	}
-	if e.embedder == nil {
-		return fmt.Errorf("embedder is required")
+	if stats != nil {
+		stats.SplitDuration += time.Since(transformStart)
 	}
 
-	// 提取文本
-	texts := make([]string, len(docs))
-	for i, doc := range docs {
-		texts[i] = doc.Content
+	result := &IndexResult{}
+	if e.dedup == DedupAllow {
+		result.Added = len(docs)
+	} else {
+		var err error
+		docs, err = e.dedupDocuments(ctx, docs, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(docs) == 0 {
+		return result, nil
 	}
 
-	// 生成向量
-	embeddings, err := e.embedder.Embed(ctx, texts)
+	// 生成向量，文本和图片分别交给对应的嵌入器
+	embeddings, err := e.embedDocuments(ctx, docs, stats)
 	if err != nil {
-		return fmt.Errorf("failed to embed documents: %w", err)
+		return nil, err
+	}
+	if err := CheckEmbeddings(e.store, embeddings...); err != nil {
+		return nil, err
 	}
 
 	// 转换并存储
@@ -137,23 +214,77 @@ func (e *Engine) IndexDocuments(ctx context.Context, docs []Document) error {
 			ID:        doc.ID,
 			Content:   doc.Content,
 			Embedding: embeddings[i],
-			Metadata:  doc.Metadata,
+			Metadata:  modalityMetadata(doc),
 			CreatedAt: doc.CreatedAt,
 		}
+		e.setVectorExpiry(&vectorDocs[i], doc)
 	}
 
-	return e.store.Add(ctx, vectorDocs)
+	storeStart := time.Now()
+	err = e.store.Add(ctx, vectorDocs)
+	if stats != nil {
+		stats.StoreDuration += time.Since(storeStart)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stats != nil {
+		stats.Chunks += len(vectorDocs)
+	}
+	return result, nil
 }
 
-// Retrieve 检索相关文档
-func (e *Engine) Retrieve(ctx context.Context, query string, opts ...RetrieveOption) ([]Document, error) {
-	if e.store == nil {
-		return nil, fmt.Errorf("store is required")
-	}
-	if e.embedder == nil {
-		return nil, fmt.Errorf("embedder is required")
+// dedupDocuments 按内容哈希过滤重复文档，返回需要写入的文档
+func (e *Engine) dedupDocuments(ctx context.Context, docs []Document, result *IndexResult) ([]Document, error) {
+	seen := make(map[string]bool, len(docs))
+	out := make([]Document, 0, len(docs))
+
+	for _, doc := range docs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// 同一批次内的重复内容只保留第一次出现
+		hash := doc.ContentHash()
+		if seen[hash] {
+			result.Skipped++
+			continue
+		}
+		seen[hash] = true
+
+		metadata := make(map[string]any, len(doc.Metadata)+2)
+		for k, v := range doc.Metadata {
+			metadata[k] = v
+		}
+		metadata["content_hash"] = hash
+		id := "doc_" + hash[:16]
+		if doc.ID != "" && doc.ID != id {
+			metadata["source_id"] = doc.ID
+		}
+		doc.ID = id
+		doc.Metadata = metadata
+
+		// 不同存储对不存在的文档返回 nil 或错误，均视为不存在
+		existing, err := e.store.Get(ctx, doc.ID)
+		if err != nil || existing == nil {
+			result.Added++
+			out = append(out, doc)
+			continue
+		}
+		if e.dedup == DedupSkip {
+			result.Skipped++
+			continue
+		}
+		result.Updated++
+		out = append(out, doc)
 	}
 
+	return out, nil
+}
+
+// Retrieve 检索相关文档
+// 配置了 WithRAGFallback 时，检索失败按降级模式返回结果而不是错误
+func (e *Engine) Retrieve(ctx context.Context, query string, opts ...RetrieveOption) ([]Document, error) {
 	cfg := &RetrieveConfig{
 		TopK:     e.topK,
 		MinScore: e.minScore,
@@ -162,39 +293,90 @@ func (e *Engine) Retrieve(ctx context.Context, query string, opts ...RetrieveOpt
 		opt(cfg)
 	}
 
+	start := time.Now()
+	docs, err := e.retrieve(ctx, query, cfg)
+	if err != nil {
+		if degraded, ok := e.degrade(ctx, fallbackKey(query, cfg), query, err); ok {
+			return degraded, nil
+		}
+	} else if e.fallback == FallbackLastResult {
+		e.lastResults.put(fallbackKey(query, cfg), docs)
+	}
+
+	l := logger.FromContext(ctx)
+	if err != nil {
+		l.WarnContext(ctx, "rag retrieve failed", logger.Elapsed(time.Since(start)), logger.Err(err))
+	} else {
+		l.DebugContext(ctx, "rag retrieve finished",
+			logger.Int("results", len(docs)), logger.Elapsed(time.Since(start)))
+	}
+	return docs, err
+}
+
+// retrieve 执行向量检索
+func (e *Engine) retrieve(ctx context.Context, query string, cfg *RetrieveConfig) ([]Document, error) {
+	if e.store == nil {
+		return nil, ErrStoreRequired
+	}
+	if e.embedder == nil {
+		return nil, ErrEmbedderRequired
+	}
+
 	// 生成查询向量
 	embedding, err := e.embedder.Embed(ctx, []string{query})
 	if err != nil {
-		return nil, fmt.Errorf("failed to embed query: %w", err)
+		return nil, fmt.Errorf("failed to embed query: %w", core.ClassifyError(err))
 	}
 	if len(embedding) == 0 {
 		return nil, fmt.Errorf("no embedding returned for query")
 	}
+	if err := CheckEmbeddings(e.store, embedding[0]); err != nil {
+		return nil, err
+	}
+	return e.search(ctx, embedding[0], cfg)
+}
 
-	// 搜索
+// search 以查询向量搜索向量存储
+func (e *Engine) search(ctx context.Context, embedding []float32, cfg *RetrieveConfig) ([]Document, error) {
 	searchOpts := []vector.SearchOption{
 		vector.WithMinScore(cfg.MinScore),
 		vector.WithMetadata(true),
 	}
-	if cfg.Filter != nil {
+	now := time.Now()
+	if e.expiryFilter {
+		filter, err := vector.ParseFilter(cfg.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if filter == nil {
+			filter = vector.NotExpired(now)
+		} else {
+			filter = vector.And(filter, vector.NotExpired(now))
+		}
+		searchOpts = append(searchOpts, vector.WithFilterExpr(filter))
+	} else if cfg.Filter != nil {
 		searchOpts = append(searchOpts, vector.WithFilter(cfg.Filter))
 	}
 
-	vectorDocs, err := e.store.Search(ctx, embedding[0], cfg.TopK, searchOpts...)
+	vectorDocs, err := e.store.Search(ctx, embedding, cfg.TopK, searchOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search: %w", err)
+		return nil, fmt.Errorf("failed to search: %w", core.ClassifyError(err))
 	}
 
-	// 转换结果
-	docs := make([]Document, len(vectorDocs))
-	for i, vd := range vectorDocs {
-		docs[i] = Document{
+	// 转换结果，剔除存储未排除的过期文档
+	docs := make([]Document, 0, len(vectorDocs))
+	for _, vd := range vectorDocs {
+		if vector.IsExpired(vd, now) {
+			continue
+		}
+		docs = append(docs, Document{
 			ID:        vd.ID,
 			Content:   vd.Content,
 			Metadata:  vd.Metadata,
 			Score:     vd.Score,
 			CreatedAt: vd.CreatedAt,
-		}
+			ExpiresAt: vectorExpiry(vd),
+		})
 	}
 
 	return docs, nil
@@ -219,7 +401,7 @@ func (e *Engine) Query(ctx context.Context, query string, opts ...RetrieveOption
 // Delete 删除文档
 func (e *Engine) Delete(ctx context.Context, ids []string) error {
 	if e.store == nil {
-		return fmt.Errorf("store is required")
+		return ErrStoreRequired
 	}
 	return e.store.Delete(ctx, ids)
 }
@@ -227,7 +409,7 @@ func (e *Engine) Delete(ctx context.Context, ids []string) error {
 // Clear 清空所有文档
 func (e *Engine) Clear(ctx context.Context) error {
 	if e.store == nil {
-		return fmt.Errorf("store is required")
+		return ErrStoreRequired
 	}
 	return e.store.Clear(ctx)
 }
@@ -235,12 +417,13 @@ func (e *Engine) Clear(ctx context.Context) error {
 // Count 返回文档数量
 func (e *Engine) Count(ctx context.Context) (int, error) {
 	if e.store == nil {
-		return 0, fmt.Errorf("store is required")
+		return 0, ErrStoreRequired
 	}
 	return e.store.Count(ctx)
 }
 
 // Index 实现 Indexer 接口
+// 需要索引选项（如 WithTTL）时使用 IndexDocuments
 func (e *Engine) Index(ctx context.Context, docs []Document) error {
 	return e.IndexDocuments(ctx, docs)
 }
@@ -0,0 +1,232 @@
+// Package eval 提供检索器的离线评估工具
+//
+// 基于标注数据集（查询 + 相关文档 ID）计算经典信息检索指标：
+//   - Recall@K: 前 K 个结果覆盖了多少相关文档
+//   - Precision@K: 前 K 个结果中相关文档的占比
+//   - MRR: 第一个相关文档排名的倒数均值
+//   - NDCG@K: 考虑排名位置的归一化折损累计增益
+//
+// 适用于任意 rag.Retriever（包括集成检索器、重排序包装器等），
+// 报告包含逐查询明细，便于定位检索效果回退的具体查询。
+//
+// 使用示例：
+//
+//	report, err := eval.Evaluate(ctx, retriever, []eval.QueryWithRelevantIDs{
+//	    {Query: "What is Go?", RelevantIDs: []string{"doc-go"}},
+//	}, 5)
+//	fmt.Printf("recall@5=%.3f mrr=%.3f\n", report.RecallAtK, report.MRR)
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+var (
+	// ErrEmptyDataset 数据集为空
+	ErrEmptyDataset = errors.New("eval: dataset is empty")
+
+	// ErrInvalidK K 值无效
+	ErrInvalidK = errors.New("eval: k must be positive")
+)
+
+// QueryWithRelevantIDs 带标注的评估查询
+type QueryWithRelevantIDs struct {
+	// Query 查询文本
+	Query string `json:"query"`
+
+	// RelevantIDs 相关文档 ID 列表
+	RelevantIDs []string `json:"relevant_ids"`
+}
+
+// QueryResult 单个查询的评估结果
+type QueryResult struct {
+	// Query 查询文本
+	Query string `json:"query"`
+
+	// RelevantIDs 标注的相关文档 ID
+	RelevantIDs []string `json:"relevant_ids"`
+
+	// RetrievedIDs 检索返回的前 K 个文档 ID（按排名）
+	RetrievedIDs []string `json:"retrieved_ids"`
+
+	// Hits 前 K 个结果中命中的相关文档数
+	Hits int `json:"hits"`
+
+	// FirstRelevantRank 第一个相关文档的排名（从 1 开始，0 表示未命中）
+	FirstRelevantRank int `json:"first_relevant_rank"`
+
+	// RecallAtK 召回率
+	RecallAtK float64 `json:"recall_at_k"`
+
+	// PrecisionAtK 精确率
+	PrecisionAtK float64 `json:"precision_at_k"`
+
+	// ReciprocalRank 倒数排名
+	ReciprocalRank float64 `json:"reciprocal_rank"`
+
+	// NDCG 归一化折损累计增益
+	NDCG float64 `json:"ndcg"`
+}
+
+// EvalReport 评估报告
+type EvalReport struct {
+	// K 评估截断位置
+	K int `json:"k"`
+
+	// NumQueries 查询数量
+	NumQueries int `json:"num_queries"`
+
+	// RecallAtK 平均召回率
+	RecallAtK float64 `json:"recall_at_k"`
+
+	// PrecisionAtK 平均精确率
+	PrecisionAtK float64 `json:"precision_at_k"`
+
+	// MRR 平均倒数排名
+	MRR float64 `json:"mrr"`
+
+	// NDCG 平均 NDCG@K
+	NDCG float64 `json:"ndcg"`
+
+	// PerQuery 逐查询明细，顺序与数据集一致
+	PerQuery []QueryResult `json:"per_query"`
+
+	// Duration 评估耗时
+	Duration time.Duration `json:"duration"`
+}
+
+// String 返回报告摘要
+func (r *EvalReport) String() string {
+	return fmt.Sprintf("queries=%d k=%d recall@k=%.4f precision@k=%.4f mrr=%.4f ndcg@k=%.4f",
+		r.NumQueries, r.K, r.RecallAtK, r.PrecisionAtK, r.MRR, r.NDCG)
+}
+
+// Misses 返回前 K 个结果中没有任何相关文档的查询
+func (r *EvalReport) Misses() []QueryResult {
+	var misses []QueryResult
+	for _, q := range r.PerQuery {
+		if q.Hits == 0 {
+			misses = append(misses, q)
+		}
+	}
+	return misses
+}
+
+// Evaluate 在标注数据集上评估检索器
+//
+// 所有查询通过 rag.RetrieveBatch 执行，检索器实现了 rag.BatchRetriever 时
+// 会使用批量优化路径。每个查询请求 TopK=k，并只取前 k 个结果计算指标。
+func Evaluate(ctx context.Context, retriever rag.Retriever, dataset []QueryWithRelevantIDs, k int) (*EvalReport, error) {
+	if len(dataset) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	if k <= 0 {
+		return nil, ErrInvalidK
+	}
+
+	start := time.Now()
+
+	queries := make([]string, len(dataset))
+	for i, item := range dataset {
+		queries[i] = item.Query
+	}
+
+	results, err := rag.RetrieveBatch(ctx, retriever, queries, rag.WithTopK(k))
+	if err != nil {
+		return nil, fmt.Errorf("eval: retrieve failed: %w", err)
+	}
+	if len(results) != len(dataset) {
+		return nil, fmt.Errorf("eval: expected %d result sets, got %d", len(dataset), len(results))
+	}
+
+	report := &EvalReport{
+		K:          k,
+		NumQueries: len(dataset),
+		PerQuery:   make([]QueryResult, len(dataset)),
+	}
+
+	for i, item := range dataset {
+		qr := scoreQuery(item, results[i], k)
+		report.PerQuery[i] = qr
+		report.RecallAtK += qr.RecallAtK
+		report.PrecisionAtK += qr.PrecisionAtK
+		report.MRR += qr.ReciprocalRank
+		report.NDCG += qr.NDCG
+	}
+
+	n := float64(len(dataset))
+	report.RecallAtK /= n
+	report.PrecisionAtK /= n
+	report.MRR /= n
+	report.NDCG /= n
+	report.Duration = time.Since(start)
+
+	return report, nil
+}
+
+// scoreQuery 计算单个查询的指标
+// 重复返回的同一文档只计算一次
+func scoreQuery(item QueryWithRelevantIDs, docs []rag.Document, k int) QueryResult {
+	relevant := make(map[string]bool, len(item.RelevantIDs))
+	for _, id := range item.RelevantIDs {
+		relevant[id] = true
+	}
+
+	qr := QueryResult{
+		Query:        item.Query,
+		RelevantIDs:  item.RelevantIDs,
+		RetrievedIDs: make([]string, 0, k),
+	}
+
+	seen := make(map[string]bool, k)
+	var dcg float64
+	for _, doc := range docs {
+		if len(qr.RetrievedIDs) >= k {
+			break
+		}
+		if seen[doc.ID] {
+			continue
+		}
+		seen[doc.ID] = true
+		qr.RetrievedIDs = append(qr.RetrievedIDs, doc.ID)
+
+		rank := len(qr.RetrievedIDs)
+		if !relevant[doc.ID] {
+			continue
+		}
+		qr.Hits++
+		if qr.FirstRelevantRank == 0 {
+			qr.FirstRelevantRank = rank
+		}
+		dcg += 1 / math.Log2(float64(rank)+1)
+	}
+
+	if len(relevant) > 0 {
+		qr.RecallAtK = float64(qr.Hits) / float64(len(relevant))
+	}
+	qr.PrecisionAtK = float64(qr.Hits) / float64(k)
+	if qr.FirstRelevantRank > 0 {
+		qr.ReciprocalRank = 1 / float64(qr.FirstRelevantRank)
+	}
+
+	// 理想 DCG：所有相关文档排在最前
+	ideal := len(relevant)
+	if ideal > k {
+		ideal = k
+	}
+	var idcg float64
+	for rank := 1; rank <= ideal; rank++ {
+		idcg += 1 / math.Log2(float64(rank)+1)
+	}
+	if idcg > 0 {
+		qr.NDCG = dcg / idcg
+	}
+
+	return qr
+}
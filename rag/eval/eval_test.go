@@ -0,0 +1,105 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// fixedRetriever 按查询返回预设结果
+type fixedRetriever struct {
+	results map[string][]string
+}
+
+func (r *fixedRetriever) Retrieve(ctx context.Context, query string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
+	var docs []rag.Document
+	for _, id := range r.results[query] {
+		docs = append(docs, rag.Document{ID: id})
+	}
+	return docs, nil
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEvaluate(t *testing.T) {
+	r := &fixedRetriever{results: map[string][]string{
+		"q1": {"a", "b", "c"},
+		"q2": {"x", "y", "d"},
+		"q3": {"x", "y", "z"},
+	}}
+	dataset := []QueryWithRelevantIDs{
+		{Query: "q1", RelevantIDs: []string{"a", "c"}},
+		{Query: "q2", RelevantIDs: []string{"d"}},
+		{Query: "q3", RelevantIDs: []string{"e"}},
+	}
+
+	report, err := Evaluate(context.Background(), r, dataset, 3)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if report.NumQueries != 3 || len(report.PerQuery) != 3 {
+		t.Fatalf("unexpected report size: %+v", report)
+	}
+
+	q1 := report.PerQuery[0]
+	if q1.Hits != 2 || !approx(q1.RecallAtK, 1) || !approx(q1.PrecisionAtK, 2.0/3) || !approx(q1.ReciprocalRank, 1) {
+		t.Errorf("unexpected q1 result: %+v", q1)
+	}
+	wantNDCG := (1 + 1/math.Log2(4)) / (1 + 1/math.Log2(3))
+	if !approx(q1.NDCG, wantNDCG) {
+		t.Errorf("expected q1 ndcg %f, got %f", wantNDCG, q1.NDCG)
+	}
+
+	q2 := report.PerQuery[1]
+	if q2.FirstRelevantRank != 3 || !approx(q2.ReciprocalRank, 1.0/3) {
+		t.Errorf("unexpected q2 result: %+v", q2)
+	}
+
+	wantMRR := (1 + 1.0/3 + 0) / 3
+	if !approx(report.MRR, wantMRR) {
+		t.Errorf("expected mrr %f, got %f", wantMRR, report.MRR)
+	}
+	wantRecall := (1 + 1 + 0) / 3.0
+	if !approx(report.RecallAtK, wantRecall) {
+		t.Errorf("expected recall %f, got %f", wantRecall, report.RecallAtK)
+	}
+
+	misses := report.Misses()
+	if len(misses) != 1 || misses[0].Query != "q3" {
+		t.Errorf("expected q3 to be the only miss, got %+v", misses)
+	}
+}
+
+func TestEvaluate_TruncatesToK(t *testing.T) {
+	r := &fixedRetriever{results: map[string][]string{
+		"q": {"x", "x", "a", "b"},
+	}}
+	report, err := Evaluate(context.Background(), r, []QueryWithRelevantIDs{
+		{Query: "q", RelevantIDs: []string{"a", "b"}},
+	}, 2)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	q := report.PerQuery[0]
+	if len(q.RetrievedIDs) != 2 || q.RetrievedIDs[1] != "a" {
+		t.Errorf("expected deduplicated top-2 [x a], got %v", q.RetrievedIDs)
+	}
+	if !approx(q.RecallAtK, 0.5) || !approx(q.PrecisionAtK, 0.5) {
+		t.Errorf("unexpected metrics: %+v", q)
+	}
+}
+
+func TestEvaluate_InvalidInput(t *testing.T) {
+	r := &fixedRetriever{}
+	if _, err := Evaluate(context.Background(), r, nil, 5); !errors.Is(err, ErrEmptyDataset) {
+		t.Errorf("expected ErrEmptyDataset, got %v", err)
+	}
+	if _, err := Evaluate(context.Background(), r, []QueryWithRelevantIDs{{Query: "q"}}, 0); !errors.Is(err, ErrInvalidK) {
+		t.Errorf("expected ErrInvalidK, got %v", err)
+	}
+}
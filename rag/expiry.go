@@ -0,0 +1,85 @@
+package rag
+
+import (
+	"time"
+
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// IndexOption 索引选项
+type IndexOption func(*indexConfig)
+
+type indexConfig struct {
+	ttl   time.Duration
+	stats *IndexStats
+}
+
+// WithTTL 设置本次索引文档的存活时间
+//
+// 文档的 ExpiresAt 设为索引时间加 ttl，已设置 ExpiresAt 的文档保持不变。
+// 过期的文档不再出现在检索结果中，vector.FilterMemoryStore 会在下一次访问时清除它们。
+//
+// 示例：
+//
+//	engine.IndexDocuments(ctx, articles, rag.WithTTL(30*24*time.Hour))
+func WithTTL(ttl time.Duration) IndexOption {
+	return func(c *indexConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithExpiryFilter 在向量存储中按过期时间过滤
+//
+// 默认情况下过期文档在检索后被剔除，结果可能少于 TopK。
+// 启用后索引时为每个文档写入过期时间（无过期时间的文档写入一个足够远的时间），
+// 检索时附加 vector.NotExpired 条件，由存储在服务端排除过期文档。
+// 需要存储支持 Gt 过滤（如 chroma、pinecone、weaviate、pgvector、qdrant.FilterStore、vector.FilterMemoryStore），
+// 否则检索返回 vector.ErrUnsupportedFilter；启用前写入的文档没有过期时间，不会被检索到。
+func WithExpiryFilter() EngineOption {
+	return func(e *Engine) {
+		e.expiryFilter = true
+	}
+}
+
+// neverExpires WithExpiryFilter 下无过期时间的文档写入的过期时间
+var neverExpires = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+// applyTTL 为未设置过期时间的文档设置过期时间，返回新的切片
+func applyTTL(docs []Document, opts []IndexOption) []Document {
+	cfg := &indexConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.ttl <= 0 {
+		return docs
+	}
+
+	expiresAt := time.Now().Add(cfg.ttl)
+	out := make([]Document, len(docs))
+	for i, doc := range docs {
+		if doc.ExpiresAt.IsZero() {
+			doc.ExpiresAt = expiresAt
+		}
+		out[i] = doc
+	}
+	return out
+}
+
+// setVectorExpiry 将文档的过期时间写入向量文档元数据
+func (e *Engine) setVectorExpiry(vd *vector.Document, doc Document) {
+	switch {
+	case !doc.ExpiresAt.IsZero():
+		vector.SetExpiresAt(vd, doc.ExpiresAt)
+	case e.expiryFilter:
+		vector.SetExpiresAt(vd, neverExpires)
+	}
+}
+
+// vectorExpiry 读取向量文档的过期时间，未设置时返回零值
+func vectorExpiry(vd vector.Document) time.Time {
+	t, ok := vector.ExpiresAt(vd)
+	if !ok || !t.Before(neverExpires) {
+		return time.Time{}
+	}
+	return t
+}
@@ -6,6 +6,9 @@
 //   - 日期、作者
 //   - 实体信息
 //
+// ExtractorTransformer 可将任意提取器适配为 rag.Transformer，
+// 在索引前批量补充元数据。
+//
 // 参考 LlamaIndex 的 MetadataExtractor 设计
 package extractor
 
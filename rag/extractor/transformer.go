@@ -0,0 +1,155 @@
+package extractor
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// ============== 提取器转换器 ==============
+
+// ExtractorTransformer 将元数据提取器适配为 rag.Transformer
+// 提取结果合并到文档元数据中，不修改输入文档的元数据 map
+type ExtractorTransformer struct {
+	extractor MetadataExtractor
+
+	// overwrite 是否覆盖已存在的元数据键
+	overwrite bool
+}
+
+// ExtractorTransformerOption 配置选项
+type ExtractorTransformerOption func(*ExtractorTransformer)
+
+// WithOverwrite 设置是否覆盖文档已有的同名元数据
+// 默认值: false
+func WithOverwrite(overwrite bool) ExtractorTransformerOption {
+	return func(t *ExtractorTransformer) {
+		t.overwrite = overwrite
+	}
+}
+
+// NewExtractorTransformer 创建提取器转换器
+func NewExtractorTransformer(extractor MetadataExtractor, opts ...ExtractorTransformerOption) *ExtractorTransformer {
+	t := &ExtractorTransformer{
+		extractor: extractor,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// NewLanguageTransformer 创建语言检测转换器
+// 写入元数据 language（"zh" 或 "en"）
+func NewLanguageTransformer() *ExtractorTransformer {
+	return NewExtractorTransformer(NewLanguageExtractor())
+}
+
+// NewTitleTransformer 创建标题提取转换器
+// 写入元数据 title，已有标题的文档保持不变
+func NewTitleTransformer(opts ...TitleExtractorOption) *ExtractorTransformer {
+	return NewExtractorTransformer(NewTitleExtractor(opts...))
+}
+
+// Name 返回转换器名称
+func (t *ExtractorTransformer) Name() string {
+	return "extractor_transformer(" + t.extractor.Name() + ")"
+}
+
+// Transform 对每个文档执行提取并合并元数据
+func (t *ExtractorTransformer) Transform(ctx context.Context, docs []rag.Document) ([]rag.Document, error) {
+	result := make([]rag.Document, len(docs))
+	for i, doc := range docs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		extracted, err := t.extractor.Extract(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata := make(map[string]any, len(doc.Metadata)+len(extracted))
+		for k, v := range doc.Metadata {
+			metadata[k] = v
+		}
+		for k, v := range extracted {
+			if _, exists := metadata[k]; exists && !t.overwrite {
+				continue
+			}
+			metadata[k] = v
+		}
+
+		doc.Metadata = metadata
+		result[i] = doc
+	}
+	return result, nil
+}
+
+// ============== 语言提取器 ==============
+
+// LanguageExtractor 语言检测提取器
+// 基于字符统计的简单启发式，不依赖 LLM
+type LanguageExtractor struct{}
+
+// NewLanguageExtractor 创建语言检测提取器
+func NewLanguageExtractor() *LanguageExtractor {
+	return &LanguageExtractor{}
+}
+
+// Name 返回提取器名称
+func (e *LanguageExtractor) Name() string {
+	return "language_extractor"
+}
+
+// Extract 检测文档语言
+func (e *LanguageExtractor) Extract(ctx context.Context, doc rag.Document) (map[string]any, error) {
+	return map[string]any{"language": detectLanguage(doc.Content)}, nil
+}
+
+// ============== 去重转换器 ==============
+
+// DedupTransformer 按内容哈希去重的转换器
+// 为每个文档写入元数据 content_hash，并只保留同一内容的第一次出现
+type DedupTransformer struct{}
+
+// NewDedupTransformer 创建去重转换器
+func NewDedupTransformer() *DedupTransformer {
+	return &DedupTransformer{}
+}
+
+// Name 返回转换器名称
+func (t *DedupTransformer) Name() string {
+	return "dedup_transformer"
+}
+
+// Transform 去除内容重复的文档
+func (t *DedupTransformer) Transform(ctx context.Context, docs []rag.Document) ([]rag.Document, error) {
+	seen := make(map[string]bool, len(docs))
+	result := make([]rag.Document, 0, len(docs))
+	for _, doc := range docs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		hash := doc.ContentHash()
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		metadata := make(map[string]any, len(doc.Metadata)+1)
+		for k, v := range doc.Metadata {
+			metadata[k] = v
+		}
+		metadata["content_hash"] = hash
+		doc.Metadata = metadata
+		result = append(result, doc)
+	}
+	return result, nil
+}
+
+// 确保实现了接口
+var _ rag.Transformer = (*ExtractorTransformer)(nil)
+var _ rag.Transformer = (*DedupTransformer)(nil)
+var _ MetadataExtractor = (*LanguageExtractor)(nil)
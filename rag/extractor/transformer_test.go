@@ -0,0 +1,91 @@
+package extractor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+func TestExtractorTransformer(t *testing.T) {
+	tr := NewLanguageTransformer()
+	original := map[string]any{"source": "test"}
+	docs := []rag.Document{
+		{ID: "zh", Content: "这是一个中文文档", Metadata: original},
+		{ID: "en", Content: "This is an English document"},
+	}
+
+	result, err := tr.Transform(context.Background(), docs)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(result))
+	}
+	if result[0].Metadata["language"] != "zh" || result[1].Metadata["language"] != "en" {
+		t.Errorf("unexpected languages: %v, %v", result[0].Metadata["language"], result[1].Metadata["language"])
+	}
+	if result[0].Metadata["source"] != "test" {
+		t.Error("expected existing metadata to be preserved")
+	}
+	if _, ok := original["language"]; ok {
+		t.Error("input metadata map should not be modified")
+	}
+}
+
+func TestExtractorTransformer_Overwrite(t *testing.T) {
+	docs := []rag.Document{
+		{Content: "Go Programming Guide\n\nbody", Metadata: map[string]any{"language": "fr"}},
+	}
+
+	result, _ := NewLanguageTransformer().Transform(context.Background(), docs)
+	if result[0].Metadata["language"] != "fr" {
+		t.Errorf("expected existing key to be kept, got %v", result[0].Metadata["language"])
+	}
+
+	result, _ = NewExtractorTransformer(NewLanguageExtractor(), WithOverwrite(true)).Transform(context.Background(), docs)
+	if result[0].Metadata["language"] != "en" {
+		t.Errorf("expected key to be overwritten, got %v", result[0].Metadata["language"])
+	}
+
+	result, _ = NewTitleTransformer(WithTitleFromFirstLine(true)).Transform(context.Background(), docs)
+	if result[0].Metadata["title"] != "Go Programming Guide" {
+		t.Errorf("unexpected title: %v", result[0].Metadata["title"])
+	}
+}
+
+func TestDedupTransformer(t *testing.T) {
+	docs := []rag.Document{
+		{ID: "1", Content: "same"},
+		{ID: "2", Content: "different"},
+		{ID: "3", Content: "same"},
+	}
+
+	result, err := NewDedupTransformer().Transform(context.Background(), docs)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	if len(result) != 2 || result[0].ID != "1" || result[1].ID != "2" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result[0].Metadata["content_hash"] != docs[0].ContentHash() {
+		t.Error("expected content_hash metadata")
+	}
+}
+
+func TestCompositeTransformer(t *testing.T) {
+	composite := rag.NewCompositeTransformer(NewDedupTransformer(), NewLanguageTransformer())
+	result, err := composite.Transform(context.Background(), []rag.Document{
+		{Content: "hello world"},
+		{Content: "hello world"},
+	})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 document, got %d", len(result))
+	}
+	if result[0].Metadata["content_hash"] == nil || result[0].Metadata["language"] != "en" {
+		t.Errorf("expected metadata from both transformers, got %v", result[0].Metadata)
+	}
+}
@@ -0,0 +1,136 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/observe/logger"
+)
+
+// FallbackMode 检索失败时的降级模式
+type FallbackMode int
+
+const (
+	// FallbackNone 不降级，返回检索错误（默认）
+	FallbackNone FallbackMode = iota
+
+	// FallbackEmpty 返回空结果，调用方在没有检索上下文的情况下继续回答
+	FallbackEmpty
+
+	// FallbackLastResult 返回同一查询最近一次成功的结果，没有时返回空结果
+	FallbackLastResult
+)
+
+// String 返回降级模式名称
+func (m FallbackMode) String() string {
+	switch m {
+	case FallbackNone:
+		return "none"
+	case FallbackEmpty:
+		return "empty"
+	case FallbackLastResult:
+		return "last_result"
+	default:
+		return fmt.Sprintf("FallbackMode(%d)", int(m))
+	}
+}
+
+// defaultFallbackCacheSize FallbackLastResult 默认保留的查询数
+const defaultFallbackCacheSize = 256
+
+// WithRAGFallback 设置检索失败时的降级模式
+//
+// Embedder 或向量存储调用失败时，Retrieve 和 Query 按模式返回空结果或上次的结果，
+// 而不是返回错误，使 Agent 在部分故障期间仍能响应。
+// 降级时记录警告日志，并在 context 中有 hooks.Manager 时触发 Phase 为 "rag_retrieve" 的错误事件。
+// 调用方取消（context 取消或超时）以及未配置存储或 Embedder 时不降级。
+//
+// 默认值: FallbackNone
+func WithRAGFallback(mode FallbackMode) EngineOption {
+	return func(e *Engine) {
+		e.fallback = mode
+	}
+}
+
+// WithRAGFallbackCacheSize 设置 FallbackLastResult 保留的查询数，超出时淘汰最早的查询
+//
+// 默认值: 256
+func WithRAGFallbackCacheSize(size int) EngineOption {
+	return func(e *Engine) {
+		if size > 0 {
+			e.lastResults.size = size
+		}
+	}
+}
+
+// degrade 按降级模式处理检索错误，返回 ok=false 时应返回原错误
+func (e *Engine) degrade(ctx context.Context, key, query string, err error) ([]Document, bool) {
+	if e.fallback == FallbackNone || e.store == nil || e.embedder == nil ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, false
+	}
+
+	var docs []Document
+	if e.fallback == FallbackLastResult {
+		docs = e.lastResults.get(key)
+	}
+
+	logger.FromContext(ctx).WarnContext(ctx, "rag retrieve degraded",
+		logger.String("fallback", e.fallback.String()),
+		logger.Int("results", len(docs)),
+		logger.Err(err))
+	if manager := hooks.ManagerFromContext(ctx); manager != nil {
+		_ = manager.TriggerError(ctx, &hooks.ErrorEvent{
+			Error: err,
+			Phase: "rag_retrieve",
+			Metadata: map[string]any{
+				"query":    query,
+				"fallback": e.fallback.String(),
+				"results":  len(docs),
+			},
+		})
+	}
+	return docs, true
+}
+
+// fallbackKey 生成查询的缓存键，包含影响结果的检索配置
+func fallbackKey(query string, cfg *RetrieveConfig) string {
+	return fmt.Sprintf("%d|%g|%v|%s", cfg.TopK, cfg.MinScore, cfg.Filter, query)
+}
+
+// resultCache 按插入顺序淘汰的检索结果缓存
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string][]Document
+	order   []string
+}
+
+func (c *resultCache) get(key string) []Document {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	docs := c.entries[key]
+	if docs == nil {
+		return nil
+	}
+	return append([]Document(nil), docs...)
+}
+
+func (c *resultCache) put(key string, docs []Document) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string][]Document)
+	}
+	if _, ok := c.entries[key]; !ok {
+		if len(c.order) >= c.size {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = append([]Document(nil), docs...)
+}
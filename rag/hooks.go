@@ -0,0 +1,56 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/internal/util"
+)
+
+// RetrieveWithHooks 检索文档并触发检索钩子
+//
+// context 中有 hooks.Manager 时，检索前触发 RetrieverStart、检索后触发 RetrieverEnd，
+// 使检索步骤出现在可观测性数据中。metadata 会附加到两个事件上。
+// RetrieverStart 钩子返回错误时放弃检索；RetrieverEnd 钩子的错误被忽略。
+func RetrieveWithHooks(ctx context.Context, r Retriever, query string, metadata map[string]any, opts ...RetrieveOption) ([]Document, error) {
+	manager := hooks.ManagerFromContext(ctx)
+	if manager == nil {
+		return r.Retrieve(ctx, query, opts...)
+	}
+
+	cfg := &RetrieveConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	queryID := util.GenerateID("query")
+	if err := manager.TriggerRetrieverStart(ctx, &hooks.RetrieverStartEvent{
+		QueryID:  queryID,
+		Query:    query,
+		TopK:     cfg.TopK,
+		Metadata: metadata,
+	}); err != nil {
+		return nil, fmt.Errorf("retriever start hook: %w", err)
+	}
+
+	start := time.Now()
+	docs, err := r.Retrieve(ctx, query, opts...)
+
+	documents := make([]any, len(docs))
+	for i, doc := range docs {
+		documents[i] = doc
+	}
+	_ = manager.TriggerRetrieverEnd(ctx, &hooks.RetrieverEndEvent{
+		QueryID:   queryID,
+		Query:     query,
+		Documents: documents,
+		DocCount:  len(docs),
+		Duration:  time.Since(start).Milliseconds(),
+		Error:     err,
+		Metadata:  metadata,
+	})
+
+	return docs, err
+}
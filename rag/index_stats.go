@@ -0,0 +1,136 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IndexStats 索引吞吐统计
+//
+// 分阶段记录耗时，用于调整批大小等参数时定位瓶颈：
+// SplitDuration 高说明分割器或转换器是瓶颈，EmbedDuration 高应调整向量化批大小，
+// StoreDuration 高则是向量存储的写入吞吐不足。
+type IndexStats struct {
+	// Documents 输入的文档数（分割前）
+	Documents int `json:"documents"`
+
+	// Chunks 写入向量存储的文档块数（分割、转换和去重后）
+	Chunks int `json:"chunks"`
+
+	// EmbedBatches 调用嵌入器的次数
+	EmbedBatches int `json:"embed_batches"`
+
+	// LoadDuration 加载文档的耗时（仅 Ingest）
+	LoadDuration time.Duration `json:"load_duration"`
+
+	// SplitDuration 分割和执行转换器的耗时
+	SplitDuration time.Duration `json:"split_duration"`
+
+	// EmbedDuration 向量化的总耗时
+	EmbedDuration time.Duration `json:"embed_duration"`
+
+	// MaxEmbedLatency 单次向量化的最大耗时
+	MaxEmbedLatency time.Duration `json:"max_embed_latency"`
+
+	// StoreDuration 写入向量存储的总耗时
+	StoreDuration time.Duration `json:"store_duration"`
+
+	// Duration 索引的总耗时
+	Duration time.Duration `json:"duration"`
+}
+
+// DocsPerSecond 每秒索引的文档数
+func (s IndexStats) DocsPerSecond() float64 {
+	return perSecond(s.Documents, s.Duration)
+}
+
+// ChunksPerSecond 每秒写入的文档块数
+func (s IndexStats) ChunksPerSecond() float64 {
+	return perSecond(s.Chunks, s.Duration)
+}
+
+// AvgEmbedLatency 单次向量化的平均耗时
+func (s IndexStats) AvgEmbedLatency() time.Duration {
+	if s.EmbedBatches == 0 {
+		return 0
+	}
+	return s.EmbedDuration / time.Duration(s.EmbedBatches)
+}
+
+// String 返回便于日志输出的摘要
+func (s IndexStats) String() string {
+	return fmt.Sprintf("%d docs, %d chunks in %s (%.1f docs/s, %.1f chunks/s); split %s, embed %s (%d batches, avg %s, max %s), store %s",
+		s.Documents, s.Chunks, s.Duration, s.DocsPerSecond(), s.ChunksPerSecond(),
+		s.SplitDuration, s.EmbedDuration, s.EmbedBatches, s.AvgEmbedLatency(), s.MaxEmbedLatency, s.StoreDuration)
+}
+
+func perSecond(n int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
+
+// recordEmbed 记录一次向量化，stats 为 nil 时忽略
+func (s *IndexStats) recordEmbed(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.EmbedBatches++
+	s.EmbedDuration += d
+	s.MaxEmbedLatency = max(s.MaxEmbedLatency, d)
+}
+
+// WithIndexStats 将本次索引的吞吐统计累加到 stats
+//
+// 示例：
+//
+//	var stats rag.IndexStats
+//	err := engine.IndexDocuments(ctx, docs, rag.WithIndexStats(&stats))
+//	log.Println(stats.String())
+func WithIndexStats(stats *IndexStats) IndexOption {
+	return func(c *indexConfig) {
+		c.stats = stats
+	}
+}
+
+// IngestWithStats 摄取文档并返回各阶段的吞吐统计
+// 出错时返回已完成阶段的统计
+func (e *Engine) IngestWithStats(ctx context.Context) (*IndexStats, error) {
+	stats := &IndexStats{}
+	start := time.Now()
+	defer func() { stats.Duration = time.Since(start) }()
+
+	if e.loader == nil {
+		return stats, fmt.Errorf("loader is required for ingestion")
+	}
+	if e.store == nil {
+		return stats, fmt.Errorf("%w for ingestion", ErrStoreRequired)
+	}
+	if e.embedder == nil {
+		return stats, fmt.Errorf("%w for ingestion", ErrEmbedderRequired)
+	}
+
+	// 1. 加载文档
+	docs, err := e.loader.Load(ctx)
+	stats.LoadDuration = time.Since(start)
+	if err != nil {
+		return stats, fmt.Errorf("failed to load documents: %w", err)
+	}
+	stats.Documents = len(docs)
+
+	// 2. 分割文档（图片文档不分割）
+	if e.splitter != nil {
+		splitStart := time.Now()
+		docs, err = splitTextDocuments(ctx, e.splitter, docs)
+		stats.SplitDuration += time.Since(splitStart)
+		if err != nil {
+			return stats, fmt.Errorf("failed to split documents: %w", err)
+		}
+	}
+
+	// 3. 索引文档
+	_, err = e.indexBatch(ctx, docs, stats)
+	return stats, err
+}
@@ -0,0 +1,243 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IndexProgress 流式索引的进度事件
+//
+// 每个文档处理完成后发送一个事件；索引结束时发送最后一个 Summary 非空的事件，随后关闭 channel。
+type IndexProgress struct {
+	// ID 文档 ID（未指定 ID 的文档使用内容哈希生成）
+	ID string `json:"id,omitempty"`
+
+	// Err 索引失败原因，成功时为 nil
+	Err error `json:"-"`
+
+	// Processed 已处理（成功 + 失败）的文档数
+	Processed int `json:"processed"`
+
+	// Summary 索引汇总，仅在最后一个事件中设置
+	Summary *IndexSummary `json:"summary,omitempty"`
+}
+
+// IndexFailure 单个文档的索引失败记录
+type IndexFailure struct {
+	// ID 文档 ID
+	ID string `json:"id"`
+
+	// Err 失败原因
+	Err error `json:"-"`
+}
+
+// IndexSummary 流式索引的汇总结果
+type IndexSummary struct {
+	// IndexResult 新增/跳过/更新的数量（成功批次）
+	IndexResult
+
+	// Succeeded 成功索引（含因重复而跳过）的文档 ID，按处理顺序
+	Succeeded []string `json:"succeeded"`
+
+	// Failed 索引失败的文档
+	Failed []IndexFailure `json:"failed,omitempty"`
+
+	// Resumed 因已在 WithIndexResume 的汇总中成功而跳过的文档数
+	Resumed int `json:"resumed,omitempty"`
+
+	// Err 提前终止的原因（StopOnError 或 context 取消），正常结束时为 nil
+	Err error `json:"-"`
+
+	// Stats 吞吐统计，批次失败后的逐个重试也计入向量化和写入耗时
+	Stats IndexStats `json:"stats"`
+}
+
+// IndexStreamOption 流式索引选项
+type IndexStreamOption func(*indexStreamConfig)
+
+type indexStreamConfig struct {
+	batchSize   int
+	stopOnError bool
+	done        map[string]bool
+}
+
+// WithIndexBatchSize 设置每批向量化的文档数量
+// 批次失败时会逐个重试以定位出错文档
+// 默认值: 32
+func WithIndexBatchSize(n int) IndexStreamOption {
+	return func(c *indexStreamConfig) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// WithStopOnError 遇到第一个失败的文档即停止索引
+func WithStopOnError() IndexStreamOption {
+	return func(c *indexStreamConfig) {
+		c.stopOnError = true
+	}
+}
+
+// WithIndexResume 从上一次的汇总恢复：跳过 summary.Succeeded 中的文档
+// 用于中断或部分失败后重新执行同一批输入
+func WithIndexResume(summary *IndexSummary) IndexStreamOption {
+	return func(c *indexStreamConfig) {
+		if summary == nil {
+			return
+		}
+		for _, id := range summary.Succeeded {
+			c.done[id] = true
+		}
+	}
+}
+
+// IndexStream 流式索引文档，逐个报告进度
+//
+// 从 docs 读取文档直到其关闭，按批次向量化并写入存储。
+// 单个文档失败不会中止索引（除非使用 WithStopOnError），失败记录汇总在最后的 Summary 中。
+// 未指定 ID 的文档使用内容哈希生成确定性 ID，便于 WithIndexResume 恢复。
+//
+// 调用者必须消费返回的 channel 直到关闭；不再需要时应取消 ctx，
+// 取消后尽力发送最后的 Summary 事件。
+func (e *Engine) IndexStream(ctx context.Context, docs <-chan Document, opts ...IndexStreamOption) <-chan IndexProgress {
+	cfg := &indexStreamConfig{
+		batchSize: 32,
+		done:      make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	out := make(chan IndexProgress, cfg.batchSize)
+	go func() {
+		defer close(out)
+		s := &indexStream{engine: e, cfg: cfg, out: out, summary: &IndexSummary{}}
+		start := time.Now()
+		s.run(ctx, docs)
+		s.summary.Stats.Duration = time.Since(start)
+
+		final := IndexProgress{Processed: s.processed, Summary: s.summary}
+		if ctx.Err() == nil {
+			out <- final
+			return
+		}
+		select {
+		case out <- final:
+		default:
+		}
+	}()
+	return out
+}
+
+// indexStream 单次流式索引的状态
+type indexStream struct {
+	engine    *Engine
+	cfg       *indexStreamConfig
+	out       chan<- IndexProgress
+	summary   *IndexSummary
+	processed int
+}
+
+// run 读取文档并按批次索引
+func (s *indexStream) run(ctx context.Context, docs <-chan Document) {
+	if s.engine.store == nil {
+		s.summary.Err = ErrStoreRequired
+		return
+	}
+	if s.engine.embedder == nil {
+		s.summary.Err = ErrEmbedderRequired
+		return
+	}
+
+	batch := make([]Document, 0, s.cfg.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			s.summary.Err = ctx.Err()
+			return
+		case doc, ok := <-docs:
+			if !ok {
+				if len(batch) > 0 {
+					s.flush(ctx, batch)
+				}
+				return
+			}
+			if doc.ID == "" {
+				doc.ID = "doc_" + doc.ContentHash()[:16]
+			}
+			if s.cfg.done[doc.ID] {
+				s.summary.Resumed++
+				continue
+			}
+			batch = append(batch, doc)
+			s.summary.Stats.Documents++
+			if len(batch) < s.cfg.batchSize {
+				continue
+			}
+			if !s.flush(ctx, batch) {
+				return
+			}
+			batch = batch[:0]
+		}
+	}
+}
+
+// flush 索引一批文档，批次失败时逐个重试；返回 false 表示应停止
+func (s *indexStream) flush(ctx context.Context, batch []Document) bool {
+	result, err := s.engine.indexBatch(ctx, batch, &s.summary.Stats)
+	if err == nil {
+		// 批次已写入，即使 ctx 取消也记录全部文档，保证恢复时不会遗漏
+		s.addResult(result)
+		ok := true
+		for _, doc := range batch {
+			ok = s.report(ctx, doc.ID, nil) && ok
+		}
+		return ok
+	}
+
+	for _, doc := range batch {
+		if len(batch) > 1 {
+			result, err = s.engine.indexBatch(ctx, []Document{doc}, &s.summary.Stats)
+		}
+		if err == nil {
+			s.addResult(result)
+		}
+		if !s.report(ctx, doc.ID, err) {
+			return false
+		}
+	}
+	return true
+}
+
+// addResult 累加批次统计
+func (s *indexStream) addResult(result *IndexResult) {
+	s.summary.Added += result.Added
+	s.summary.Skipped += result.Skipped
+	s.summary.Updated += result.Updated
+}
+
+// report 记录并发送单个文档的结果；返回 false 表示应停止
+func (s *indexStream) report(ctx context.Context, id string, err error) bool {
+	s.processed++
+	if err != nil {
+		err = fmt.Errorf("index document %s: %w", id, err)
+		s.summary.Failed = append(s.summary.Failed, IndexFailure{ID: id, Err: err})
+	} else {
+		s.summary.Succeeded = append(s.summary.Succeeded, id)
+	}
+
+	select {
+	case <-ctx.Done():
+		s.summary.Err = ctx.Err()
+		return false
+	case s.out <- IndexProgress{ID: id, Err: err, Processed: s.processed}:
+	}
+
+	if err != nil && s.cfg.stopOnError {
+		s.summary.Err = err
+		return false
+	}
+	return true
+}
@@ -3,8 +3,9 @@
 // 实现 Property Graph 和 Text2Cypher 功能：
 //   - PropertyGraph: 属性图存储和查询
 //   - Entity/Relation: 实体和关系定义
+//   - Pattern/Fact: 边模式查询及其匹配的事实
 //   - Text2Cypher: 自然语言转 Cypher 查询
-//   - GraphRAG: 基于图的 RAG 检索
+//   - GraphRetriever: 基于图的 RAG 检索，实现 rag.Retriever，可与向量检索组合
 //
 // 对标 LlamaIndex 的 Property Graph Index + Text2Cypher。
 //
@@ -565,6 +566,9 @@ type GraphRetriever struct {
 	hops        int  // 子图深度
 	maxEntities int  // 最大返回实体数
 	includeRels bool // 是否在结果中包含关系信息
+
+	// extractor 从查询中提取实体，未设置时按查询中的词模糊搜索实体
+	extractor EntityExtractor
 }
 
 // GraphRetrieverOption 选项
@@ -591,6 +595,16 @@ func WithGraphIncludeRelations(include bool) GraphRetrieverOption {
 	}
 }
 
+// WithGraphEntityExtractor 设置从查询中提取实体的提取器
+//
+// 设置后按提取出的实体名称精确查找图中的实体作为遍历起点，
+// 不再按查询中的每个词模糊搜索，适合多词实体名或需要 NER / LLM 提取的场景。
+func WithGraphEntityExtractor(extractor EntityExtractor) GraphRetrieverOption {
+	return func(r *GraphRetriever) {
+		r.extractor = extractor
+	}
+}
+
 // NewGraphRetriever 创建图 RAG 检索器
 func NewGraphRetriever(graph *PropertyGraph, opts ...GraphRetrieverOption) *GraphRetriever {
 	r := &GraphRetriever{
@@ -606,26 +620,32 @@ func NewGraphRetriever(graph *PropertyGraph, opts ...GraphRetrieverOption) *Grap
 }
 
 // Retrieve 检索相关文档
-// 从用户查询中提取实体关键词，在图中搜索相关子图，转换为文档
+// 从用户查询中提取实体，在图中搜索相关子图，转换为文档。
+// 查询直接命中的实体分数为 1，遍历得到的邻居实体分数为 0.5；设置了 TopK 时按其截断。
 func (r *GraphRetriever) Retrieve(ctx context.Context, query string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
-	// 1. 从查询中提取关键词作为实体搜索条件
-	words := strings.Fields(query)
+	cfg := &rag.RetrieveConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// 1. 从查询中提取实体作为遍历起点
+	seeds, err := r.seedEntities(ctx, query)
+	if err != nil {
+		return nil, err
+	}
 
 	var allEntities []Entity
 	var allRelations []Relation
 	seen := make(map[string]bool)
+	matched := make(map[string]bool)
 
-	for _, word := range words {
-		entities, err := r.graph.Store().SearchEntities(ctx, word, "", r.maxEntities)
-		if err != nil {
-			continue
-		}
-
+	for _, entities := range seeds {
 		for _, e := range entities {
 			if seen[e.Name] {
 				continue
 			}
 			seen[e.Name] = true
+			matched[e.Name] = true
 			allEntities = append(allEntities, e)
 
 			// 获取子图
@@ -647,6 +667,9 @@ func (r *GraphRetriever) Retrieve(ctx context.Context, query string, opts ...rag
 	if len(allEntities) > r.maxEntities {
 		allEntities = allEntities[:r.maxEntities]
 	}
+	if cfg.TopK > 0 && len(allEntities) > cfg.TopK {
+		allEntities = allEntities[:cfg.TopK]
+	}
 
 	// 2. 将图数据转换为文档
 	var docs []rag.Document
@@ -673,9 +696,14 @@ func (r *GraphRetriever) Retrieve(ctx context.Context, query string, opts ...rag
 			}
 		}
 
+		score := float32(0.5)
+		if matched[e.Name] {
+			score = 1
+		}
 		docs = append(docs, rag.Document{
 			ID:      e.ID,
 			Content: content.String(),
+			Score:   score,
 			Metadata: map[string]any{
 				"entity_name": e.Name,
 				"entity_type": e.Type,
@@ -687,3 +715,33 @@ func (r *GraphRetriever) Retrieve(ctx context.Context, query string, opts ...rag
 
 	return docs, nil
 }
+
+// seedEntities 返回查询命中的实体，每个提取出的实体名或查询词对应一组
+func (r *GraphRetriever) seedEntities(ctx context.Context, query string) ([][]Entity, error) {
+	store := r.graph.Store()
+	if r.extractor == nil {
+		var seeds [][]Entity
+		for _, word := range strings.Fields(query) {
+			entities, err := store.SearchEntities(ctx, word, "", r.maxEntities)
+			if err != nil {
+				continue
+			}
+			seeds = append(seeds, entities)
+		}
+		return seeds, nil
+	}
+
+	names, err := r.extractor.ExtractEntities(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("提取查询实体失败: %w", err)
+	}
+	var seeds [][]Entity
+	for _, name := range names {
+		if e, _ := store.GetEntity(ctx, name); e != nil {
+			seeds = append(seeds, []Entity{*e})
+		}
+	}
+	return seeds, nil
+}
+
+var _ rag.Retriever = (*GraphRetriever)(nil)
@@ -0,0 +1,231 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// ============== 节点/边与模式查询 ==============
+
+// AddNode 添加节点（实体），同名节点已存在时覆盖
+func (pg *PropertyGraph) AddNode(ctx context.Context, name, nodeType string, properties map[string]any) error {
+	if name == "" {
+		return fmt.Errorf("节点名称不能为空")
+	}
+	return pg.store.AddEntity(ctx, Entity{Name: name, Type: nodeType, Properties: properties})
+}
+
+// AddEdge 添加从 from 指向 to 的边（关系）
+// 两端节点必须已通过 AddNode 或 AddEntity 添加，自动创建节点请使用 AddTriple
+func (pg *PropertyGraph) AddEdge(ctx context.Context, from, edgeType, to string, properties map[string]any) error {
+	for _, name := range []string{from, to} {
+		if e, err := pg.store.GetEntity(ctx, name); err != nil {
+			return err
+		} else if e == nil {
+			return fmt.Errorf("节点 %q 不存在", name)
+		}
+	}
+	return pg.store.AddRelation(ctx, Relation{From: from, To: to, Type: edgeType, Properties: properties})
+}
+
+// Pattern 边模式 (From:FromType)-[Relation]->(To:ToType)
+// 为空的字段匹配任意值
+type Pattern struct {
+	// From 起点节点名称
+	From string `json:"from,omitempty"`
+
+	// FromType 起点节点类型
+	FromType string `json:"from_type,omitempty"`
+
+	// Relation 关系类型
+	Relation string `json:"relation,omitempty"`
+
+	// To 终点节点名称
+	To string `json:"to,omitempty"`
+
+	// ToType 终点节点类型
+	ToType string `json:"to_type,omitempty"`
+
+	// Properties 边属性需要等于的值
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+// Fact 匹配模式的事实 (Subject)-[Relation]->(Object)
+type Fact struct {
+	Subject  Entity   `json:"subject"`
+	Relation Relation `json:"relation"`
+	Object   Entity   `json:"object"`
+}
+
+// String 返回 "Go -[created_by]-> Google" 形式的描述
+func (f Fact) String() string {
+	return fmt.Sprintf("%s -[%s]-> %s", f.Subject.Name, f.Relation.Type, f.Object.Name)
+}
+
+// Document 将事实转换为文档，便于与向量检索结果一起交给 LLM
+func (f Fact) Document() rag.Document {
+	var content strings.Builder
+	content.WriteString(f.String())
+	if len(f.Relation.Properties) > 0 {
+		keys := make([]string, 0, len(f.Relation.Properties))
+		for k := range f.Relation.Properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		content.WriteString("\n属性: ")
+		for _, k := range keys {
+			content.WriteString(fmt.Sprintf("%s=%v ", k, f.Relation.Properties[k]))
+		}
+	}
+
+	return rag.Document{
+		ID:      f.Relation.ID,
+		Content: strings.TrimSpace(content.String()),
+		Metadata: map[string]any{
+			"subject":  f.Subject.Name,
+			"relation": f.Relation.Type,
+			"object":   f.Object.Name,
+			"source":   "knowledge_graph",
+		},
+		Source: "knowledge_graph",
+	}
+}
+
+// Match 返回匹配模式的所有事实
+//
+// 设置了 From 或 To 时只遍历该节点的边，否则遍历所有节点的出边。
+// 基于 GraphStore 的通用接口实现，适用于任何后端。
+//
+// 示例：
+//
+//	// Google 创建了哪些语言？
+//	facts, err := graph.Match(ctx, knowledge.Pattern{Relation: "created_by", To: "Google", FromType: "Language"})
+func (pg *PropertyGraph) Match(ctx context.Context, p Pattern) ([]Fact, error) {
+	relations, err := pg.candidateRelations(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make(map[string]*Entity)
+	entity := func(name string) (*Entity, error) {
+		if e, ok := entities[name]; ok {
+			return e, nil
+		}
+		e, err := pg.store.GetEntity(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		entities[name] = e
+		return e, nil
+	}
+
+	var facts []Fact
+	for _, rel := range relations {
+		if !p.matchRelation(rel) {
+			continue
+		}
+		subject, err := entity(rel.From)
+		if err != nil {
+			return nil, err
+		}
+		object, err := entity(rel.To)
+		if err != nil {
+			return nil, err
+		}
+		if subject == nil || object == nil {
+			continue // 悬空的边
+		}
+		if (p.FromType != "" && subject.Type != p.FromType) || (p.ToType != "" && object.Type != p.ToType) {
+			continue
+		}
+		facts = append(facts, Fact{Subject: *subject, Relation: rel, Object: *object})
+	}
+	return facts, nil
+}
+
+// candidateRelations 返回可能匹配模式的边
+func (pg *PropertyGraph) candidateRelations(ctx context.Context, p Pattern) ([]Relation, error) {
+	switch {
+	case p.From != "":
+		return pg.store.GetRelations(ctx, p.From, "out")
+	case p.To != "":
+		return pg.store.GetRelations(ctx, p.To, "in")
+	}
+
+	all, err := pg.store.SearchEntities(ctx, "", p.FromType, 0)
+	if err != nil {
+		return nil, err
+	}
+	var relations []Relation
+	for _, e := range all {
+		rels, err := pg.store.GetRelations(ctx, e.Name, "out")
+		if err != nil {
+			return nil, err
+		}
+		relations = append(relations, rels...)
+	}
+	return relations, nil
+}
+
+// matchRelation 检查边的端点名称、类型和属性
+func (p Pattern) matchRelation(rel Relation) bool {
+	if (p.From != "" && rel.From != p.From) || (p.To != "" && rel.To != p.To) {
+		return false
+	}
+	if p.Relation != "" && rel.Type != p.Relation {
+		return false
+	}
+	for k, want := range p.Properties {
+		got, ok := rel.Properties[k]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// ============== 查询实体提取 ==============
+
+// EntityExtractor 从查询中提取实体名称
+// 可基于词典、NER 模型或 LLM 实现，见 WithGraphEntityExtractor
+type EntityExtractor interface {
+	ExtractEntities(ctx context.Context, query string) ([]string, error)
+}
+
+// EntityExtractorFunc 函数形式的实体提取器
+type EntityExtractorFunc func(ctx context.Context, query string) ([]string, error)
+
+// ExtractEntities 调用函数本身
+func (f EntityExtractorFunc) ExtractEntities(ctx context.Context, query string) ([]string, error) {
+	return f(ctx, query)
+}
+
+// NameMatchExtractor 返回按图中实体名称匹配的提取器
+// 查询中（不区分大小写）出现的实体名称即被提取，支持 "New York" 这样的多词名称
+func NameMatchExtractor(graph *PropertyGraph) EntityExtractor {
+	return EntityExtractorFunc(func(ctx context.Context, query string) ([]string, error) {
+		entities, err := graph.Store().SearchEntities(ctx, "", "", 0)
+		if err != nil {
+			return nil, err
+		}
+		queryLower := strings.ToLower(query)
+		var names []string
+		for _, e := range entities {
+			if e.Name != "" && strings.Contains(queryLower, strings.ToLower(e.Name)) {
+				names = append(names, e.Name)
+			}
+		}
+		// 长名称更具体，排在前面
+		sort.Slice(names, func(i, j int) bool {
+			if len(names[i]) != len(names[j]) {
+				return len(names[i]) > len(names[j])
+			}
+			return names[i] < names[j]
+		})
+		return names, nil
+	})
+}
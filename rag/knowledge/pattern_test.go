@@ -0,0 +1,143 @@
+package knowledge
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// newLanguageGraph 构建测试用的编程语言图谱
+func newLanguageGraph(t *testing.T) *PropertyGraph {
+	t.Helper()
+	ctx := context.Background()
+	graph := NewPropertyGraph(NewMemoryGraphStore())
+
+	nodes := []struct{ name, typ string }{
+		{"Go", "Language"}, {"Python", "Language"}, {"Google", "Company"},
+		{"Ken Thompson", "Person"}, {"Guido van Rossum", "Person"},
+	}
+	for _, n := range nodes {
+		if err := graph.AddNode(ctx, n.name, n.typ, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	edges := []struct {
+		from, typ, to string
+		props         map[string]any
+	}{
+		{"Go", "created_by", "Google", map[string]any{"year": 2009}},
+		{"Ken Thompson", "designed", "Go", nil},
+		{"Guido van Rossum", "designed", "Python", map[string]any{"year": 1991}},
+	}
+	for _, e := range edges {
+		if err := graph.AddEdge(ctx, e.from, e.typ, e.to, e.props); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return graph
+}
+
+func TestPropertyGraph_AddEdge_MissingNode(t *testing.T) {
+	graph := NewPropertyGraph(NewMemoryGraphStore())
+	ctx := context.Background()
+	_ = graph.AddNode(ctx, "Go", "Language", nil)
+
+	err := graph.AddEdge(ctx, "Go", "created_by", "Gooogle", nil)
+	if err == nil || !strings.Contains(err.Error(), "Gooogle") {
+		t.Errorf("端点不存在时应返回错误，实际 %v", err)
+	}
+	if err := graph.AddNode(ctx, "", "Language", nil); err == nil {
+		t.Error("空节点名称应返回错误")
+	}
+}
+
+func TestPropertyGraph_Match(t *testing.T) {
+	graph := newLanguageGraph(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		pattern Pattern
+		want    []string
+	}{
+		{"按起点", Pattern{From: "Go"}, []string{"Go -[created_by]-> Google"}},
+		{"按终点和关系", Pattern{Relation: "designed", To: "Python"}, []string{"Guido van Rossum -[designed]-> Python"}},
+		{"按起点类型", Pattern{FromType: "Person", Relation: "designed"}, []string{
+			"Guido van Rossum -[designed]-> Python", "Ken Thompson -[designed]-> Go",
+		}},
+		{"按边属性", Pattern{Properties: map[string]any{"year": 1991}}, []string{"Guido van Rossum -[designed]-> Python"}},
+		{"按终点类型", Pattern{ToType: "Company"}, []string{"Go -[created_by]-> Google"}},
+		{"无匹配", Pattern{From: "Go", Relation: "designed"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facts, err := graph.Match(ctx, tt.pattern)
+			if err != nil {
+				t.Fatalf("Match 失败: %v", err)
+			}
+			var got []string
+			for _, f := range facts {
+				got = append(got, f.String())
+			}
+			sort.Strings(got)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Match = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFact_Document(t *testing.T) {
+	graph := newLanguageGraph(t)
+	facts, _ := graph.Match(context.Background(), Pattern{From: "Go"})
+	if len(facts) != 1 {
+		t.Fatalf("期望 1 个事实，实际 %d", len(facts))
+	}
+
+	doc := facts[0].Document()
+	if doc.Content != "Go -[created_by]-> Google\n属性: year=2009" {
+		t.Errorf("Content = %q", doc.Content)
+	}
+	if doc.Metadata["relation"] != "created_by" || doc.Source != "knowledge_graph" {
+		t.Errorf("Metadata = %v, Source = %q", doc.Metadata, doc.Source)
+	}
+}
+
+func TestGraphRetriever_EntityExtractor(t *testing.T) {
+	graph := newLanguageGraph(t)
+	ctx := context.Background()
+
+	var r rag.Retriever = NewGraphRetriever(graph,
+		WithGraphHops(1),
+		WithGraphEntityExtractor(NameMatchExtractor(graph)),
+	)
+
+	// 多词实体名按整体匹配，不会因 "van" 等词误命中
+	docs, err := r.Retrieve(ctx, "What did Guido van Rossum design?")
+	if err != nil {
+		t.Fatalf("Retrieve 失败: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("期望返回 Guido 及其 1 跳邻居，实际 %d 个文档", len(docs))
+	}
+	if docs[0].Metadata["entity_name"] != "Guido van Rossum" || docs[0].Score != 1 {
+		t.Errorf("命中实体应排在第一位且分数为 1，实际 %v (%.1f)", docs[0].Metadata, docs[0].Score)
+	}
+	if docs[1].Metadata["entity_name"] != "Python" || docs[1].Score != 0.5 {
+		t.Errorf("邻居实体分数应为 0.5，实际 %v (%.1f)", docs[1].Metadata, docs[1].Score)
+	}
+	if !strings.Contains(docs[0].Content, "→ designed → Python") {
+		t.Errorf("Content 应包含关系，实际 %q", docs[0].Content)
+	}
+
+	docs, err = r.Retrieve(ctx, "Go and Python", rag.WithTopK(1))
+	if err != nil {
+		t.Fatalf("Retrieve 失败: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Errorf("TopK=1 时期望 1 个文档，实际 %d", len(docs))
+	}
+}
@@ -0,0 +1,325 @@
+package loader
+
+// 本文件实现按来源类型自动选择加载器：
+//   - AutoLoad: 根据 URL 协议、文件扩展名或嗅探的内容类型分派到对应加载器
+//   - AutoLoadReader: 从 io.Reader 加载，按名称扩展名或内容嗅探
+//   - RegisterLoader / RegisterContentType: 扩展名和 MIME 类型注册表
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// ErrUnsupportedFormat 没有与来源类型对应的加载器
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+// LoaderFactory 根据本地文件路径创建加载器
+type LoaderFactory func(path string) rag.Loader
+
+var (
+	autoMu sync.RWMutex
+
+	// autoLoaders 扩展名（小写，含点）-> 加载器工厂
+	autoLoaders = map[string]LoaderFactory{
+		".txt":      func(p string) rag.Loader { return NewTextLoader(p, WithEncodingDetection(true)) },
+		".text":     func(p string) rag.Loader { return NewTextLoader(p, WithEncodingDetection(true)) },
+		".log":      func(p string) rag.Loader { return NewTextLoader(p, WithEncodingDetection(true)) },
+		".md":       func(p string) rag.Loader { return NewMarkdownLoader(p) },
+		".markdown": func(p string) rag.Loader { return NewMarkdownLoader(p) },
+		".html":     func(p string) rag.Loader { return NewHTMLLoader(p) },
+		".htm":      func(p string) rag.Loader { return NewHTMLLoader(p) },
+		".pdf":      func(p string) rag.Loader { return NewPDFLoader(p) },
+		".docx":     func(p string) rag.Loader { return NewDOCXLoader(p) },
+		".xlsx":     func(p string) rag.Loader { return NewExcelLoader(p) },
+		".pptx":     func(p string) rag.Loader { return NewPPTXLoader(p) },
+		".csv":      func(p string) rag.Loader { return NewCSVLoader(p) },
+		".tsv":      func(p string) rag.Loader { return NewCSVLoader(p, WithCSVSeparator('\t')) },
+		".json":     func(p string) rag.Loader { return NewJSONLoader(p) },
+		".yaml":     func(p string) rag.Loader { return NewYAMLLoader(p) },
+		".yml":      func(p string) rag.Loader { return NewYAMLLoader(p) },
+		".xml":      func(p string) rag.Loader { return NewXMLLoader(p, "/*") },
+	}
+
+	// autoContentTypes MIME 类型 -> 扩展名
+	autoContentTypes = map[string]string{
+		"text/plain":                ".txt",
+		"text/markdown":             ".md",
+		"text/x-markdown":           ".md",
+		"text/html":                 ".html",
+		"application/xhtml+xml":     ".html",
+		"application/pdf":           ".pdf",
+		"text/csv":                  ".csv",
+		"text/tab-separated-values": ".tsv",
+		"application/json":          ".json",
+		"application/yaml":          ".yaml",
+		"application/x-yaml":        ".yaml",
+		"text/yaml":                 ".yaml",
+		"application/xml":           ".xml",
+		"text/xml":                  ".xml",
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+		"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+	}
+)
+
+// RegisterLoader 为文件扩展名注册加载器，已存在时覆盖
+// ext 不区分大小写，可省略前导点，如 "epub" 与 ".EPUB" 等价
+func RegisterLoader(ext string, factory LoaderFactory) {
+	autoMu.Lock()
+	defer autoMu.Unlock()
+	autoLoaders[normalizeExt(ext)] = factory
+}
+
+// RegisterContentType 将 MIME 类型映射到已注册的扩展名
+// 用于 URL 响应的 Content-Type 和内容嗅探，如 RegisterContentType("application/epub+zip", ".epub")
+func RegisterContentType(contentType, ext string) {
+	autoMu.Lock()
+	defer autoMu.Unlock()
+	autoContentTypes[strings.ToLower(contentType)] = normalizeExt(ext)
+}
+
+// LoaderForFile 返回本地文件对应的加载器
+// 优先按扩展名选择，扩展名未注册时嗅探文件内容；都无法识别时返回 ErrUnsupportedFormat
+func LoaderForFile(filePath string) (rag.Loader, error) {
+	ext, err := resolveExt(filePath, filePath, "")
+	if err != nil {
+		return nil, err
+	}
+	factory, _ := lookupLoader(ext)
+	return factory(filePath), nil
+}
+
+// AutoLoad 自动选择加载器加载来源
+//
+// source 可以是：
+//   - http/https URL：按响应的 Content-Type 选择，类型不明确时依次按 URL 路径扩展名和内容嗅探
+//   - file:// URL 或本地路径：按扩展名选择，扩展名未注册时嗅探内容
+//
+// 远程内容先写入临时文件再交给对应加载器，文档 Source 为原始 URL。
+// 无法识别类型时返回 ErrUnsupportedFormat。
+//
+// 使用示例：
+//
+//	docs, err := loader.AutoLoad(ctx, "https://example.com/report.pdf")
+func AutoLoad(ctx context.Context, source string) ([]rag.Document, error) {
+	u, err := url.Parse(source)
+	if err == nil {
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https":
+			return autoLoadURL(ctx, source, u)
+		case "file":
+			source = u.Path
+		}
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", source, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s is a directory, use NewDirectoryLoader", ErrUnsupportedFormat, source)
+	}
+
+	l, err := LoaderForFile(source)
+	if err != nil {
+		return nil, err
+	}
+	return l.Load(ctx)
+}
+
+// AutoLoadReader 从 Reader 加载，自动选择加载器
+// name 用于按扩展名判断类型并作为文档来源，可为空；扩展名未注册时嗅探内容
+func AutoLoadReader(ctx context.Context, r io.Reader, name string) ([]rag.Document, error) {
+	return autoLoadStream(ctx, r, name, name, "", nil)
+}
+
+// autoLoadURL 下载 URL 内容后按类型加载
+func autoLoadURL(ctx context.Context, source string, u *url.URL) ([]rag.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Hexagon-RAG/1.0")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d for URL %s", resp.StatusCode, source)
+	}
+
+	extra := map[string]any{"url": source}
+	return autoLoadStream(ctx, resp.Body, source, u.Path, resp.Header.Get("Content-Type"), extra)
+}
+
+// autoLoadStream 将内容写入临时文件，按类型选择加载器后将文档来源改回 source
+func autoLoadStream(ctx context.Context, r io.Reader, source, name, contentType string, extra map[string]any) ([]rag.Document, error) {
+	tmp, err := os.CreateTemp("", "hexagon-autoload-*"+filepath.Ext(name))
+	if err != nil {
+		return nil, fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", source, err)
+	}
+
+	ext, err := resolveExt(tmpPath, name, contentType)
+	if err != nil {
+		if source == "" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	factory, _ := lookupLoader(ext)
+	docs, err := factory(tmpPath).Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range docs {
+		docs[i].Source = strings.Replace(docs[i].Source, tmpPath, source, 1)
+		if docs[i].Metadata == nil {
+			docs[i].Metadata = make(map[string]any)
+		}
+		delete(docs[i].Metadata, "file_path")
+		if name != "" {
+			docs[i].Metadata["file_name"] = path.Base(name)
+		} else {
+			delete(docs[i].Metadata, "file_name")
+		}
+		for k, v := range extra {
+			docs[i].Metadata[k] = v
+		}
+	}
+	return docs, nil
+}
+
+// resolveExt 确定内容对应的已注册扩展名
+// 依次使用明确的 Content-Type、name 的扩展名和 filePath 的内容嗅探
+func resolveExt(filePath, name, contentType string) (string, error) {
+	mediaType := ""
+	if contentType != "" {
+		mediaType, _, _ = mime.ParseMediaType(contentType)
+	}
+	if ext, ok := extForContentType(mediaType); ok && !genericContentType(mediaType) {
+		return ext, nil
+	}
+	if ext := normalizeExt(path.Ext(filepath.ToSlash(name))); ext != "." {
+		if _, ok := lookupLoader(ext); ok {
+			return ext, nil
+		}
+	}
+	if ext, ok := extForContentType(mediaType); ok {
+		return ext, nil
+	}
+
+	sniffed, err := sniffContentType(filePath)
+	if err != nil {
+		return "", err
+	}
+	if ext, ok := extForContentType(sniffed); ok {
+		return ext, nil
+	}
+	if name == "" {
+		return "", fmt.Errorf("%w: content type %s", ErrUnsupportedFormat, sniffed)
+	}
+	return "", fmt.Errorf("%w: %s (content type %s)", ErrUnsupportedFormat, path.Base(name), sniffed)
+}
+
+// genericContentType 判断 MIME 类型是否不足以确定格式
+// 服务器常以 text/plain 返回 Markdown、CSV 等文本文件，此时优先按扩展名判断
+func genericContentType(mediaType string) bool {
+	return mediaType == "text/plain" || mediaType == "application/octet-stream"
+}
+
+// sniffContentType 嗅探文件内容的 MIME 类型
+// 在 http.DetectContentType 的基础上，按 ZIP 包内的目录区分 Office Open XML 格式
+func sniffContentType(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("read %s: %w", filePath, err)
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if mediaType != "application/zip" {
+		return mediaType, nil
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return mediaType, nil
+	}
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return mediaType, nil
+	}
+	for _, file := range zr.File {
+		switch {
+		case strings.HasPrefix(file.Name, "word/"):
+			return "application/vnd.openxmlformats-officedocument.wordprocessingml.document", nil
+		case strings.HasPrefix(file.Name, "xl/"):
+			return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", nil
+		case strings.HasPrefix(file.Name, "ppt/"):
+			return "application/vnd.openxmlformats-officedocument.presentationml.presentation", nil
+		}
+	}
+	return mediaType, nil
+}
+
+// extForContentType 查找 MIME 类型对应且已注册加载器的扩展名
+func extForContentType(mediaType string) (string, bool) {
+	if mediaType == "" {
+		return "", false
+	}
+	autoMu.RLock()
+	ext, ok := autoContentTypes[strings.ToLower(mediaType)]
+	autoMu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	_, ok = lookupLoader(ext)
+	return ext, ok
+}
+
+// lookupLoader 查找扩展名对应的加载器工厂
+func lookupLoader(ext string) (LoaderFactory, bool) {
+	autoMu.RLock()
+	defer autoMu.RUnlock()
+	factory, ok := autoLoaders[ext]
+	return factory, ok
+}
+
+// normalizeExt 规范化扩展名为小写并带前导点
+func normalizeExt(ext string) string {
+	return "." + strings.TrimPrefix(strings.ToLower(ext), ".")
+}
@@ -0,0 +1,135 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// TestAutoLoad_File 按扩展名和内容嗅探选择加载器
+func TestAutoLoad_File(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"readme.md":  "---\ntitle: Hi\n---\n# Heading\n\nbody",
+		"page":       "<!DOCTYPE html><html><head><title>T</title></head><body><p>hello html</p></body></html>",
+		"data.xml":   "<root><a>one</a><b>two</b></root>",
+		"notes.TXT":  "plain notes",
+		"image.bin":  "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+		"table.csv":  "text,name\nrow one,x\n",
+		"custom.foo": "custom format",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		contains string
+		loader   string
+	}{
+		{"readme.md", "Heading", "markdown"},
+		{"page", "hello html", "html"},
+		{"data.xml", "one", ""},
+		{"notes.TXT", "plain notes", "text"},
+		{"table.csv", "row one", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := AutoLoad(context.Background(), filepath.Join(dir, tt.name))
+			if err != nil {
+				t.Fatalf("AutoLoad 失败: %v", err)
+			}
+			if len(docs) == 0 || !strings.Contains(docs[0].Content, tt.contains) {
+				t.Fatalf("docs = %+v, 期望包含 %q", docs, tt.contains)
+			}
+			if tt.loader != "" && docs[0].Metadata["loader"] != tt.loader {
+				t.Errorf("loader = %v, 期望 %s", docs[0].Metadata["loader"], tt.loader)
+			}
+		})
+	}
+
+	if _, err := AutoLoad(context.Background(), filepath.Join(dir, "image.bin")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("不支持的类型 err = %v, 期望 ErrUnsupportedFormat", err)
+	}
+	if _, err := AutoLoad(context.Background(), dir); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("目录 err = %v, 期望 ErrUnsupportedFormat", err)
+	}
+
+	RegisterLoader("FOO", func(p string) rag.Loader { return NewStringLoader("from custom", p) })
+	defer func() {
+		autoMu.Lock()
+		delete(autoLoaders, ".foo")
+		autoMu.Unlock()
+	}()
+	docs, err := AutoLoad(context.Background(), "file://"+filepath.ToSlash(filepath.Join(dir, "custom.foo")))
+	if err != nil || len(docs) != 1 || docs[0].Content != "from custom" {
+		t.Errorf("自定义加载器 docs = %+v, err = %v", docs, err)
+	}
+}
+
+// TestAutoLoad_URL 按 Content-Type 和 URL 扩展名选择加载器
+func TestAutoLoad_URL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html><body><p>remote page</p></body></html>"))
+		case "/docs/guide.md":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("# Guide\n\nsteps"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	docs, err := AutoLoad(context.Background(), server.URL+"/article")
+	if err != nil {
+		t.Fatalf("AutoLoad 失败: %v", err)
+	}
+	if len(docs) != 1 || !strings.Contains(docs[0].Content, "remote page") {
+		t.Fatalf("docs = %+v", docs)
+	}
+	if docs[0].Source != server.URL+"/article" || docs[0].Metadata["url"] != server.URL+"/article" {
+		t.Errorf("Source = %q, url = %v", docs[0].Source, docs[0].Metadata["url"])
+	}
+	if _, ok := docs[0].Metadata["file_path"]; ok {
+		t.Error("不应暴露临时文件路径")
+	}
+
+	// text/plain 不明确时按 URL 扩展名
+	docs, err = AutoLoad(context.Background(), server.URL+"/docs/guide.md")
+	if err != nil || len(docs) != 1 || docs[0].Metadata["loader"] != "markdown" {
+		t.Errorf("guide.md docs = %+v, err = %v", docs, err)
+	}
+
+	if _, err := AutoLoad(context.Background(), server.URL+"/missing"); err == nil {
+		t.Error("404 应返回错误")
+	}
+}
+
+// TestAutoLoadReader 从 Reader 加载
+func TestAutoLoadReader(t *testing.T) {
+	docs, err := AutoLoadReader(context.Background(), strings.NewReader("text\nfirst\nsecond\n"), "upload.csv")
+	if err != nil {
+		t.Fatalf("AutoLoadReader 失败: %v", err)
+	}
+	if len(docs) != 2 || docs[0].Metadata["file_name"] != "upload.csv" {
+		t.Errorf("docs = %+v", docs)
+	}
+
+	// 无名称时嗅探内容
+	docs, err = AutoLoadReader(context.Background(), strings.NewReader("<?xml version=\"1.0\"?><r><x>sniffed</x></r>"), "")
+	if err != nil || len(docs) != 1 || !strings.Contains(docs[0].Content, "sniffed") {
+		t.Errorf("嗅探 XML docs = %+v, err = %v", docs, err)
+	}
+}
@@ -0,0 +1,151 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/hexagon-codes/toolkit/util/retry"
+)
+
+// ConnectorRetryConfig 连接器 HTTP 请求的重试配置
+//
+// 所有连接器的请求在遇到 429、5xx 和网络错误时按指数退避重试，
+// 响应带 Retry-After 时按其指定的时间等待。重试耗尽后才返回
+// ErrRateLimited（429）或 ErrConnectorFailed（5xx、网络错误）。
+type ConnectorRetryConfig struct {
+	// MaxAttempts 最大尝试次数（含首次请求），<= 1 表示不重试
+	MaxAttempts int
+
+	// InitialDelay 首次重试前的等待时间，之后每次翻倍
+	InitialDelay time.Duration
+
+	// MaxDelay 退避等待的上限，<= 0 表示不限制
+	MaxDelay time.Duration
+
+	// MaxRetryAfter Retry-After 超过该值时不再等待，直接返回 ErrRateLimited；<= 0 表示不限制
+	MaxRetryAfter time.Duration
+}
+
+// DefaultConnectorRetryConfig 默认连接器重试配置
+var DefaultConnectorRetryConfig = ConnectorRetryConfig{
+	MaxAttempts:   4,
+	InitialDelay:  time.Second,
+	MaxDelay:      30 * time.Second,
+	MaxRetryAfter: 2 * time.Minute,
+}
+
+// connectorRetry 返回配置的重试策略，未配置时使用默认值
+func connectorRetry(cfg *ConnectorRetryConfig) ConnectorRetryConfig {
+	if cfg == nil {
+		return DefaultConnectorRetryConfig
+	}
+	return *cfg
+}
+
+// connectorHTTPClient 连接器共用的 HTTP 客户端，负责限流和瞬时错误的重试
+type connectorHTTPClient struct {
+	client *http.Client
+	retry  ConnectorRetryConfig
+}
+
+// Do 发送请求，对 429、5xx 和网络错误退避重试
+//
+// 返回的响应状态码一定小于 500 且不是 429，其余状态码（如 401、404）由调用方处理。
+// 重试耗尽时 429 映射为 ErrRateLimited，其他失败映射为 ErrConnectorFailed；
+// context 取消时返回的错误同时匹配 ErrConnectorFailed 和 context 错误。
+func (c connectorHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	var (
+		resp     *http.Response
+		lastErr  error
+		attempts int
+	)
+	err := retry.DoWithContext(req.Context(), func() error {
+		attempts++
+		if attempts > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			req.Body = body
+		}
+
+		r, err := c.client.Do(req)
+		if err != nil {
+			lastErr = err
+			return err
+		}
+		if r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500 {
+			// 读完响应体以复用连接，Retry-After 仍可从响应头读取
+			_, _ = io.Copy(io.Discard, io.LimitReader(r.Body, 64<<10))
+			r.Body.Close()
+			lastErr = retry.NewHTTPError(r)
+			return lastErr
+		}
+		resp = r
+		return nil
+	},
+		retry.Attempts(max(c.retry.MaxAttempts, 1)),
+		retry.Delay(c.retry.InitialDelay),
+		// 退避上限和 Retry-After 上限分别由 delay 和 shouldRetry 控制
+		retry.MaxDelay(time.Duration(math.MaxInt64)),
+		retry.Multiplier(2),
+		retry.RetryIf(func(err error) bool { return c.shouldRetry(req, err) }),
+		retry.DelayType(c.delay),
+	)
+	if err == nil {
+		return resp, nil
+	}
+
+	if ctxErr := req.Context().Err(); ctxErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnectorFailed, ctxErr)
+	}
+	var httpErr *retry.HTTPError
+	switch {
+	case errors.As(lastErr, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: status 429 after %d attempts", ErrRateLimited, attempts)
+	case errors.As(lastErr, &httpErr):
+		return nil, fmt.Errorf("%w: status %d after %d attempts", ErrConnectorFailed, httpErr.StatusCode, attempts)
+	case lastErr != nil:
+		return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, lastErr)
+	}
+	return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, err)
+}
+
+// shouldRetry 判断失败的请求能否重试
+// 请求体无法重放或 Retry-After 超过上限时不重试
+func (c connectorHTTPClient) shouldRetry(req *http.Request, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+	var httpErr *retry.HTTPError
+	if !errors.As(err, &httpErr) {
+		// 网络错误
+		return true
+	}
+	if after := retry.GetRetryAfterFromError(err); c.retry.MaxRetryAfter > 0 && after > c.retry.MaxRetryAfter {
+		return false
+	}
+	return true
+}
+
+// delay 计算重试等待时间，优先使用 Retry-After
+func (c connectorHTTPClient) delay(n int, cfg *retry.Config) time.Duration {
+	if after := retry.GetRetryAfterFromError(cfg.LastError); after > 0 {
+		return after
+	}
+	if c.retry.InitialDelay <= 0 {
+		return 0
+	}
+	d := retry.ExponentialBackoff(n, cfg)
+	if c.retry.MaxDelay > 0 {
+		d = min(d, c.retry.MaxDelay)
+	}
+	return d
+}
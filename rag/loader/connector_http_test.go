@@ -0,0 +1,141 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastConnectorRetry 测试用重试配置，不等待
+var fastConnectorRetry = &ConnectorRetryConfig{MaxAttempts: 2}
+
+// TestConnectorHTTPClient_RetryThenSuccess 5xx 后重试成功
+func TestConnectorHTTPClient_RetryThenSuccess(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := connectorHTTPClient{client: server.Client(), retry: ConnectorRetryConfig{MaxAttempts: 3}}
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do 失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "ok" || calls.Load() != 3 {
+		t.Errorf("body = %q, calls = %d, 期望 ok / 3", body, calls.Load())
+	}
+}
+
+// TestConnectorHTTPClient_RateLimitExhausted 429 重试耗尽后返回 ErrRateLimited
+func TestConnectorHTTPClient_RateLimitExhausted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := connectorHTTPClient{client: server.Client(), retry: ConnectorRetryConfig{MaxAttempts: 3}}
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := c.Do(req)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, 期望 ErrRateLimited", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, 期望 3", calls.Load())
+	}
+}
+
+// TestConnectorHTTPClient_RetryAfter 按 Retry-After 等待，超过上限时不重试
+func TestConnectorHTTPClient_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := connectorHTTPClient{client: server.Client(), retry: ConnectorRetryConfig{MaxAttempts: 2, MaxRetryAfter: time.Minute}}
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	start := time.Now()
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do 失败: %v", err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("elapsed = %v, 期望按 Retry-After 等待约 1s", elapsed)
+	}
+
+	calls.Store(0)
+	c.retry.MaxRetryAfter = 500 * time.Millisecond
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Retry-After 超过上限时 err = %v, 期望 ErrRateLimited", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Retry-After 超过上限时 calls = %d, 期望 1", calls.Load())
+	}
+}
+
+// TestConnectorHTTPClient_ContextCanceled context 取消时停止重试
+func TestConnectorHTTPClient_ContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := connectorHTTPClient{client: server.Client(), retry: ConnectorRetryConfig{MaxAttempts: 10, InitialDelay: time.Second}}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := c.Do(req)
+	if !errors.Is(err, ErrConnectorFailed) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, 期望同时匹配 ErrConnectorFailed 和 DeadlineExceeded", err)
+	}
+}
+
+// TestWebAPIConnector_RetryReplaysBody 重试时重新发送请求体
+func TestWebAPIConnector_RetryReplaysBody(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"q":"x"}` {
+			t.Errorf("第 %d 次请求体 = %q", calls.Load()+1, body)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("done"))
+	}))
+	defer server.Close()
+
+	c := NewWebAPIConnector(&WebAPIConfig{URL: server.URL, Method: http.MethodPost, Body: `{"q":"x"}`, Retry: fastConnectorRetry})
+	docs, err := c.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load 失败: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Content != "done" || calls.Load() != 2 {
+		t.Errorf("docs = %v, calls = %d", docs, calls.Load())
+	}
+}
@@ -4,6 +4,7 @@
 //   - GitHub: 加载 GitHub 仓库、Issues、PR
 //   - Notion: 加载 Notion 页面和数据库
 //   - Slack: 加载 Slack 消息和频道
+//   - Confluence: 加载 Confluence 空间中的页面
 //   - Database: 加载 SQL 数据库内容
 //
 // 设计借鉴：
@@ -17,11 +18,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/core"
 	"github.com/hexagon-codes/hexagon/rag"
 )
 
@@ -35,13 +39,13 @@ var (
 	ErrConnectorFailed = errors.New("connector failed")
 
 	// ErrAuthFailed 认证失败
-	ErrAuthFailed = errors.New("authentication failed")
+	ErrAuthFailed = core.NewCategoryError("authentication failed", core.ErrUnauthorized)
 
 	// ErrRateLimited 被限流
-	ErrRateLimited = errors.New("rate limited")
+	ErrRateLimited = core.ErrRateLimited
 
 	// ErrNotFound 未找到
-	ErrNotFound = errors.New("not found")
+	ErrNotFound = core.ErrNotFound
 )
 
 // ============== 连接器接口 ==============
@@ -66,6 +70,7 @@ type GitHubConnector struct {
 	path     string
 	loadType GitHubLoadType
 	client   *http.Client
+	retry    ConnectorRetryConfig
 }
 
 // GitHubLoadType GitHub 加载类型
@@ -110,6 +115,9 @@ type GitHubConfig struct {
 
 	// MaxItems 最大项数
 	MaxItems int
+
+	// Retry 限流和瞬时错误的重试配置，为 nil 时使用 DefaultConnectorRetryConfig
+	Retry *ConnectorRetryConfig
 }
 
 // NewGitHubConnector 创建 GitHub 连接器
@@ -127,6 +135,7 @@ func NewGitHubConnector(config *GitHubConfig) *GitHubConnector {
 		path:     config.Path,
 		loadType: config.LoadType,
 		client:   &http.Client{Timeout: 30 * time.Second},
+		retry:    connectorRetry(config.Retry),
 	}
 }
 
@@ -347,9 +356,9 @@ func (gc *GitHubConnector) doRequest(ctx context.Context, url string) ([]byte, e
 		req.Header.Set("Authorization", "token "+gc.token)
 	}
 
-	resp, err := gc.client.Do(req)
+	resp, err := connectorHTTPClient{client: gc.client, retry: gc.retry}.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -359,9 +368,6 @@ func (gc *GitHubConnector) doRequest(ctx context.Context, url string) ([]byte, e
 	if resp.StatusCode == 404 {
 		return nil, ErrNotFound
 	}
-	if resp.StatusCode == 429 {
-		return nil, ErrRateLimited
-	}
 	if resp.StatusCode >= 400 {
 		return nil, fmt.Errorf("%w: status %d", ErrConnectorFailed, resp.StatusCode)
 	}
@@ -379,7 +385,7 @@ func (gc *GitHubConnector) fetchContent(ctx context.Context, url string) (string
 		req.Header.Set("Authorization", "token "+gc.token)
 	}
 
-	resp, err := gc.client.Do(req)
+	resp, err := connectorHTTPClient{client: gc.client, retry: gc.retry}.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -400,6 +406,7 @@ type NotionConnector struct {
 	token  string
 	pageID string
 	client *http.Client
+	retry  ConnectorRetryConfig
 }
 
 // NotionConfig Notion 连接器配置
@@ -412,6 +419,9 @@ type NotionConfig struct {
 
 	// DatabaseID 数据库 ID（可选）
 	DatabaseID string
+
+	// Retry 限流和瞬时错误的重试配置，为 nil 时使用 DefaultConnectorRetryConfig
+	Retry *ConnectorRetryConfig
 }
 
 // NewNotionConnector 创建 Notion 连接器
@@ -420,6 +430,7 @@ func NewNotionConnector(config *NotionConfig) *NotionConnector {
 		token:  config.Token,
 		pageID: config.PageID,
 		client: &http.Client{Timeout: 30 * time.Second},
+		retry:  connectorRetry(config.Retry),
 	}
 }
 
@@ -448,9 +459,9 @@ func (nc *NotionConnector) loadPage(ctx context.Context, pageID string) ([]*Docu
 	req.Header.Set("Authorization", "Bearer "+nc.token)
 	req.Header.Set("Notion-Version", "2022-06-28")
 
-	resp, err := nc.client.Do(req)
+	resp, err := connectorHTTPClient{client: nc.client, retry: nc.retry}.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -563,7 +574,12 @@ func (nc *NotionConnector) loadPage(ctx context.Context, pageID string) ([]*Docu
 type SlackConnector struct {
 	token     string
 	channelID string
+	limit     int // 最多加载的频道消息数，0 表示不限制
+	oldest    string
+	latest    string
+	replies   bool
 	client    *http.Client
+	retry     ConnectorRetryConfig
 }
 
 // SlackConfig Slack 连接器配置
@@ -574,17 +590,93 @@ type SlackConfig struct {
 	// ChannelID 频道 ID
 	ChannelID string
 
-	// Limit 消息数量限制
+	// Limit 最多加载的频道消息数（不含线程回复），<= 0 时使用默认值 100
+	// 需要加载全部消息时使用 WithSlackNoLimit
 	Limit int
+
+	// Retry 限流和瞬时错误的重试配置，为 nil 时使用 DefaultConnectorRetryConfig
+	Retry *ConnectorRetryConfig
+}
+
+// SlackConnectorOption SlackConnector 选项
+type SlackConnectorOption func(*SlackConnector)
+
+// WithSlackOldest 只加载时间戳晚于 ts 的消息（不含 ts 本身）
+// ts 为 Slack 消息时间戳，如 "1700000000.000100"；增量同步时传入上次同步的最新时间戳
+func WithSlackOldest(ts string) SlackConnectorOption {
+	return func(sc *SlackConnector) {
+		sc.oldest = ts
+	}
+}
+
+// WithSlackLatest 只加载时间戳早于 ts 的消息（不含 ts 本身）
+func WithSlackLatest(ts string) SlackConnectorOption {
+	return func(sc *SlackConnector) {
+		sc.latest = ts
+	}
+}
+
+// WithSlackNoLimit 不限制加载的频道消息数，翻页直到没有更多消息，忽略 SlackConfig.Limit
+func WithSlackNoLimit() SlackConnectorOption {
+	return func(sc *SlackConnector) {
+		sc.limit = 0
+	}
+}
+
+// WithSlackThreadReplies 同时加载线程回复
+// 回复作为独立文档紧跟在父消息之后，元数据 parent_id 和 thread_ts 指向父消息
+func WithSlackThreadReplies() SlackConnectorOption {
+	return func(sc *SlackConnector) {
+		sc.replies = true
+	}
+}
+
+const (
+	// slackPageSize 每次请求的消息数，Slack 建议不超过 200
+	slackPageSize = 200
+
+	// slackDefaultLimit 未设置 SlackConfig.Limit 时最多加载的频道消息数
+	slackDefaultLimit = 100
+)
+
+// slackMessage Slack 消息
+type slackMessage struct {
+	Type       string `json:"type"`
+	Subtype    string `json:"subtype"`
+	User       string `json:"user"`
+	Text       string `json:"text"`
+	TS         string `json:"ts"`
+	ThreadTS   string `json:"thread_ts"`
+	ReplyCount int    `json:"reply_count"`
+}
+
+// slackPage conversations.history / conversations.replies 的分页响应
+type slackPage struct {
+	OK               bool           `json:"ok"`
+	Error            string         `json:"error"`
+	Messages         []slackMessage `json:"messages"`
+	HasMore          bool           `json:"has_more"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
 }
 
 // NewSlackConnector 创建 Slack 连接器
-func NewSlackConnector(config *SlackConfig) *SlackConnector {
-	return &SlackConnector{
+func NewSlackConnector(config *SlackConfig, opts ...SlackConnectorOption) *SlackConnector {
+	sc := &SlackConnector{
 		token:     config.Token,
 		channelID: config.ChannelID,
+		limit:     config.Limit,
 		client:    &http.Client{Timeout: 30 * time.Second},
+		retry:     connectorRetry(config.Retry),
+	}
+	if sc.limit <= 0 {
+		sc.limit = slackDefaultLimit
+	}
+	for _, opt := range opts {
+		opt(sc)
 	}
+	return sc
 }
 
 // Name 返回连接器名称
@@ -593,63 +685,385 @@ func (sc *SlackConnector) Name() string {
 }
 
 // Load 加载 Slack 消息
+//
+// 按 response_metadata.next_cursor 翻页，直到没有更多消息或达到 Limit。
+// 启用 WithSlackThreadReplies 时，对有回复的消息调用 conversations.replies 加载整个线程。
 func (sc *SlackConnector) Load(ctx context.Context) ([]*Document, error) {
-	url := fmt.Sprintf("https://slack.com/api/conversations.history?channel=%s&limit=100",
-		sc.channelID)
+	var docs []*Document
+	count := 0
+	cursor := ""
+	for {
+		params := url.Values{"channel": {sc.channelID}}
+		pageSize := slackPageSize
+		if sc.limit > 0 {
+			pageSize = min(pageSize, sc.limit-count)
+		}
+		params.Set("limit", fmt.Sprint(pageSize))
+		if sc.oldest != "" {
+			params.Set("oldest", sc.oldest)
+		}
+		if sc.latest != "" {
+			params.Set("latest", sc.latest)
+		}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		page, err := sc.fetchPage(ctx, "conversations.history", params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, msg := range page.Messages {
+			if sc.limit > 0 && count >= sc.limit {
+				return docs, nil
+			}
+			count++
+			docs = append(docs, sc.messageDocument(msg, ""))
+
+			if sc.replies && msg.ReplyCount > 0 {
+				replies, err := sc.loadReplies(ctx, msg.TS)
+				if err != nil {
+					return nil, err
+				}
+				docs = append(docs, replies...)
+			}
+		}
+
+		cursor = page.ResponseMetadata.NextCursor
+		if !page.HasMore || cursor == "" || (sc.limit > 0 && count >= sc.limit) {
+			return docs, nil
+		}
+	}
+}
+
+// loadReplies 加载线程中的回复，不含父消息本身
+func (sc *SlackConnector) loadReplies(ctx context.Context, threadTS string) ([]*Document, error) {
+	var docs []*Document
+	cursor := ""
+	for {
+		params := url.Values{
+			"channel": {sc.channelID},
+			"ts":      {threadTS},
+			"limit":   {fmt.Sprint(slackPageSize)},
+		}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		page, err := sc.fetchPage(ctx, "conversations.replies", params)
+		if err != nil {
+			return nil, fmt.Errorf("load replies of %s: %w", threadTS, err)
+		}
+		for _, msg := range page.Messages {
+			if msg.TS == threadTS {
+				continue
+			}
+			docs = append(docs, sc.messageDocument(msg, threadTS))
+		}
+
+		cursor = page.ResponseMetadata.NextCursor
+		if !page.HasMore || cursor == "" {
+			return docs, nil
+		}
+	}
+}
+
+// fetchPage 调用 Slack Web API 的分页方法
+func (sc *SlackConnector) fetchPage(ctx context.Context, method string, params url.Values) (*slackPage, error) {
+	endpoint := "https://slack.com/api/" + method + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Authorization", "Bearer "+sc.token)
 
-	resp, err := sc.client.Do(req)
+	resp, err := connectorHTTPClient{client: sc.client, retry: sc.retry}.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result struct {
-		OK       bool `json:"ok"`
-		Messages []struct {
-			Type string `json:"type"`
-			User string `json:"user"`
-			Text string `json:"text"`
-			TS   string `json:"ts"`
-		} `json:"messages"`
-		Error string `json:"error"`
-	}
-
+	var page slackPage
 	body, _ := io.ReadAll(resp.Body)
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := json.Unmarshal(body, &page); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, err)
 	}
 
-	if !result.OK {
-		if result.Error == "invalid_auth" {
+	if !page.OK {
+		if page.Error == "invalid_auth" {
 			return nil, ErrAuthFailed
 		}
-		return nil, fmt.Errorf("%w: %s", ErrConnectorFailed, result.Error)
+		return nil, fmt.Errorf("%w: %s", ErrConnectorFailed, page.Error)
+	}
+	return &page, nil
+}
+
+// messageDocument 将消息转换为文档，parentTS 非空表示线程回复
+func (sc *SlackConnector) messageDocument(msg slackMessage, parentTS string) *Document {
+	metadata := map[string]any{
+		"source":     "slack",
+		"channel_id": sc.channelID,
+		"user":       msg.User,
+		"timestamp":  msg.TS,
+	}
+	if msg.Subtype != "" {
+		metadata["subtype"] = msg.Subtype
+	}
+	if msg.ReplyCount > 0 {
+		metadata["reply_count"] = msg.ReplyCount
+	}
+	if parentTS != "" {
+		metadata["parent_id"] = parentTS
+		metadata["thread_ts"] = parentTS
+	}
+	return &Document{
+		ID:       msg.TS,
+		Content:  msg.Text,
+		Metadata: metadata,
+	}
+}
+
+// ============== Confluence 连接器 ==============
+
+// ConfluenceConnector Confluence 数据连接器
+// 通过 REST API v2 分页加载空间中的所有页面，每个页面生成一个文档
+type ConfluenceConnector struct {
+	baseURL  string
+	spaceID  string
+	email    string
+	token    string
+	pageSize int
+	maxPages int
+	client   *http.Client
+	retry    ConnectorRetryConfig
+}
+
+// ConfluenceConfig Confluence 连接器配置
+type ConfluenceConfig struct {
+	// BaseURL 站点地址，如 https://your-domain.atlassian.net
+	BaseURL string
+
+	// SpaceID 空间 ID（数字 ID，不是空间 Key）
+	SpaceID string
+
+	// Email Atlassian 账号邮箱，与 Token 组成 Basic 认证（Confluence Cloud）
+	// 为空时 Token 作为 Bearer 个人访问令牌使用（Data Center）
+	Email string
+
+	// Token API Token 或个人访问令牌
+	Token string
+
+	// PageSize 每次请求的页面数量，默认 50
+	PageSize int
+
+	// MaxPages 最大页面数，0 表示不限制
+	MaxPages int
+
+	// Retry 限流和瞬时错误的重试配置，为 nil 时使用 DefaultConnectorRetryConfig
+	Retry *ConnectorRetryConfig
+}
+
+// NewConfluenceConnector 创建 Confluence 连接器
+func NewConfluenceConnector(config *ConfluenceConfig) *ConfluenceConnector {
+	pageSize := config.PageSize
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+
+	return &ConfluenceConnector{
+		baseURL:  strings.TrimRight(config.BaseURL, "/"),
+		spaceID:  config.SpaceID,
+		email:    config.Email,
+		token:    config.Token,
+		pageSize: pageSize,
+		maxPages: config.MaxPages,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		retry:    connectorRetry(config.Retry),
+	}
+}
+
+// Name 返回连接器名称
+func (cc *ConfluenceConnector) Name() string {
+	return "confluence"
+}
+
+// confluencePage Confluence v2 页面
+type confluencePage struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	ParentID  string `json:"parentId"`
+	AuthorID  string `json:"authorId"`
+	CreatedAt string `json:"createdAt"`
+	Version   struct {
+		Number    int    `json:"number"`
+		CreatedAt string `json:"createdAt"`
+		AuthorID  string `json:"authorId"`
+	} `json:"version"`
+	Body struct {
+		Storage struct {
+			Value string `json:"value"`
+		} `json:"storage"`
+	} `json:"body"`
+	Links struct {
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// Load 加载空间中的所有页面
+//
+// 页面正文（storage 格式）转换为纯文本，宏参数被丢弃，代码块等 CDATA 内容保留。
+// 元数据包含空间、标题、作者（Atlassian 账号 ID）和版本信息。
+func (cc *ConfluenceConnector) Load(ctx context.Context) ([]*Document, error) {
+	if cc.baseURL == "" || cc.spaceID == "" {
+		return nil, fmt.Errorf("%w: base URL and space ID are required", ErrConnectorFailed)
+	}
+
+	var space struct {
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	}
+	body, err := cc.doRequest(ctx, fmt.Sprintf("%s/wiki/api/v2/spaces/%s", cc.baseURL, url.PathEscape(cc.spaceID)))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &space); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, err)
 	}
 
 	var docs []*Document
-	for _, msg := range result.Messages {
-		docs = append(docs, &Document{
-			ID:      msg.TS,
-			Content: msg.Text,
-			Metadata: map[string]any{
-				"source":     "slack",
-				"channel_id": sc.channelID,
-				"user":       msg.User,
-				"timestamp":  msg.TS,
-			},
-		})
+	next := fmt.Sprintf("%s/wiki/api/v2/spaces/%s/pages?body-format=storage&limit=%d",
+		cc.baseURL, url.PathEscape(cc.spaceID), cc.pageSize)
+	for next != "" {
+		body, err := cc.doRequest(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Results []confluencePage `json:"results"`
+			Links   struct {
+				Next string `json:"next"`
+				Base string `json:"base"`
+			} `json:"_links"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, err)
+		}
+
+		for _, page := range result.Results {
+			docs = append(docs, cc.toDocument(page, space.Key, space.Name, result.Links.Base))
+			if cc.maxPages > 0 && len(docs) >= cc.maxPages {
+				return docs, nil
+			}
+		}
+
+		// next 为站点根路径下的相对地址，包含游标
+		next = ""
+		if result.Links.Next != "" {
+			next = cc.baseURL + result.Links.Next
+		}
 	}
 
 	return docs, nil
 }
 
+// toDocument 将页面转换为文档
+func (cc *ConfluenceConnector) toDocument(page confluencePage, spaceKey, spaceName, linkBase string) *Document {
+	if linkBase == "" {
+		linkBase = cc.baseURL + "/wiki"
+	}
+
+	metadata := map[string]any{
+		"source":     "confluence",
+		"type":       "page",
+		"space_id":   cc.spaceID,
+		"space_key":  spaceKey,
+		"space_name": spaceName,
+		"page_id":    page.ID,
+		"title":      page.Title,
+		"status":     page.Status,
+		"author":     page.AuthorID,
+		"version":    page.Version.Number,
+		"created_at": page.CreatedAt,
+		"updated_at": page.Version.CreatedAt,
+		"updated_by": page.Version.AuthorID,
+	}
+	if page.ParentID != "" {
+		metadata["parent_id"] = page.ParentID
+	}
+	if page.Links.WebUI != "" {
+		metadata["url"] = linkBase + page.Links.WebUI
+	}
+
+	return &Document{
+		ID:       "confluence-" + page.ID,
+		Content:  fmt.Sprintf("# %s\n\n%s", page.Title, confluenceStorageToText(page.Body.Storage.Value)),
+		Metadata: metadata,
+	}
+}
+
+func (cc *ConfluenceConnector) doRequest(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if cc.email != "" {
+		req.SetBasicAuth(cc.email, cc.token)
+	} else if cc.token != "" {
+		req.Header.Set("Authorization", "Bearer "+cc.token)
+	}
+
+	resp, err := connectorHTTPClient{client: cc.client, retry: cc.retry}.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, ErrAuthFailed
+	}
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%w: status %d", ErrConnectorFailed, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// confluenceStorageToText 将 Confluence storage 格式（XHTML + ac/ri 宏）转换为纯文本
+func confluenceStorageToText(storage string) string {
+	// 宏参数（如代码语言、目录深度）不是正文
+	storage = removeHTMLTag(storage, "ac:parameter")
+
+	// CDATA（代码块、纯文本宏）转义后保留，避免其中的尖括号被当作标签移除
+	var sb strings.Builder
+	for {
+		start := strings.Index(storage, "<![CDATA[")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(storage[start:], "]]>")
+		if end == -1 {
+			break
+		}
+		sb.WriteString(storage[:start])
+		sb.WriteString(" ")
+		sb.WriteString(html.EscapeString(storage[start+len("<![CDATA[") : start+end]))
+		sb.WriteString(" ")
+		storage = storage[start+end+len("]]>"):]
+	}
+	sb.WriteString(storage)
+
+	return html.UnescapeString(cleanWhitespace(stripHTMLTags(sb.String())))
+}
+
 // ============== SQL 数据库连接器 ==============
 
 // DatabaseConnector SQL 数据库连接器
@@ -770,6 +1184,7 @@ func (dc *DatabaseConnector) applyTemplate(template string, data map[string]any)
 // WebAPIConnector Web API 连接器
 type WebAPIConnector struct {
 	client   *http.Client
+	retry    ConnectorRetryConfig
 	url      string
 	method   string
 	headers  map[string]string
@@ -793,6 +1208,9 @@ type WebAPIConfig struct {
 
 	// JSONPath JSON 路径（提取数组）
 	JSONPath string
+
+	// Retry 限流和瞬时错误的重试配置，为 nil 时使用 DefaultConnectorRetryConfig
+	Retry *ConnectorRetryConfig
 }
 
 // NewWebAPIConnector 创建 Web API 连接器
@@ -804,6 +1222,7 @@ func NewWebAPIConnector(config *WebAPIConfig) *WebAPIConnector {
 
 	return &WebAPIConnector{
 		client:   &http.Client{Timeout: 30 * time.Second},
+		retry:    connectorRetry(config.Retry),
 		url:      config.URL,
 		method:   strings.ToUpper(method),
 		headers:  config.Headers,
@@ -833,9 +1252,9 @@ func (wc *WebAPIConnector) Load(ctx context.Context) ([]*Document, error) {
 		req.Header.Set(key, value)
 	}
 
-	resp, err := wc.client.Do(req)
+	resp, err := connectorHTTPClient{client: wc.client, retry: wc.retry}.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrConnectorFailed, err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -15,7 +15,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hexagon-codes/hexagon/internal/util"
@@ -25,37 +27,84 @@ import (
 // ============== TextLoader ==============
 
 // TextLoader 纯文本文件加载器
+//
+// 文件内容按 encoding 转码为 UTF-8，无法按该编码解码时返回错误，
+// 避免乱码内容进入下游的切分和向量化。实际使用的编码记录在元数据 encoding 中。
 type TextLoader struct {
 	path     string
 	encoding string
+
+	// encodingSet 是否通过 WithEncoding 显式指定了编码
+	encodingSet bool
+
+	// detectEncoding 未指定编码时探测编码
+	detectEncoding bool
+}
+
+// TextOption 文本加载器选项
+type TextOption func(*TextLoader)
+
+// WithEncoding 设置文件编码，如 "gb18030"、"latin1"、"utf-16le"
+// 支持的编码见 RegisterEncoding；默认 utf-8
+func WithEncoding(encoding string) TextOption {
+	return func(l *TextLoader) {
+		l.encoding = encoding
+		l.encodingSet = true
+	}
+}
+
+// WithEncodingDetection 未通过 WithEncoding 指定编码时，根据 BOM 和字节分布探测编码
+// 探测结果记录在元数据 encoding 中，encoding_detected 为 true
+func WithEncodingDetection(enabled bool) TextOption {
+	return func(l *TextLoader) {
+		l.detectEncoding = enabled
+	}
 }
 
 // NewTextLoader 创建文本加载器
-func NewTextLoader(path string) *TextLoader {
-	return &TextLoader{
+func NewTextLoader(path string, opts ...TextOption) *TextLoader {
+	l := &TextLoader{
 		path:     path,
 		encoding: "utf-8",
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // Load 加载文本文件
 func (l *TextLoader) Load(ctx context.Context) ([]rag.Document, error) {
-	content, err := os.ReadFile(l.path)
+	raw, err := os.ReadFile(l.path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", l.path, err)
 	}
 
+	encoding := l.encoding
+	detected := l.detectEncoding && !l.encodingSet
+	if detected {
+		encoding = detectEncoding(raw)
+	}
+	content, encoding, err := decodeText(raw, encoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode file %s: %w", l.path, err)
+	}
+
 	doc := rag.Document{
 		ID:      util.GenerateID("doc"),
-		Content: string(content),
+		Content: content,
 		Source:  l.path,
 		Metadata: map[string]any{
 			"loader":    "text",
 			"file_path": l.path,
 			"file_name": filepath.Base(l.path),
+			"encoding":  encoding,
 		},
 		CreatedAt: time.Now(),
 	}
+	if detected {
+		doc.Metadata["encoding_detected"] = true
+	}
 
 	return []rag.Document{doc}, nil
 }
@@ -161,11 +210,17 @@ var _ rag.Loader = (*MarkdownLoader)(nil)
 // ============== DirectoryLoader ==============
 
 // DirectoryLoader 目录批量加载器
+//
+// 支持多个包含模式、排除模式以及并发加载。
+// 无论并发度如何，输出文档都按文件路径的字典序排列。
 type DirectoryLoader struct {
-	path       string
-	pattern    string // glob 模式
-	recursive  bool
-	loaderFunc func(path string) rag.Loader
+	path            string
+	pattern         string   // glob 模式
+	patterns        []string // 多个包含模式（非空时覆盖 pattern）
+	excludePatterns []string
+	recursive       bool
+	concurrency     int
+	loaderFunc      func(path string) rag.Loader
 }
 
 // DirectoryOption 目录加载器选项
@@ -178,6 +233,23 @@ func WithPattern(pattern string) DirectoryOption {
 	}
 }
 
+// WithPatterns 设置多个文件匹配模式
+// 文件名匹配任一模式即被加载，设置后覆盖 WithPattern
+func WithPatterns(patterns ...string) DirectoryOption {
+	return func(l *DirectoryLoader) {
+		l.patterns = patterns
+	}
+}
+
+// WithExcludePatterns 设置排除模式
+// 模式同时匹配文件/目录名和相对于根目录的路径，匹配的目录会被整体跳过。
+// 例如 []string{"node_modules", ".git", "*.min.js", "vendor/*"}
+func WithExcludePatterns(patterns []string) DirectoryOption {
+	return func(l *DirectoryLoader) {
+		l.excludePatterns = patterns
+	}
+}
+
 // WithRecursive 设置是否递归
 func WithRecursive(recursive bool) DirectoryOption {
 	return func(l *DirectoryLoader) {
@@ -185,6 +257,16 @@ func WithRecursive(recursive bool) DirectoryOption {
 	}
 }
 
+// WithConcurrency 设置并发加载的文件数
+// 默认值: 1（串行加载）
+func WithConcurrency(n int) DirectoryOption {
+	return func(l *DirectoryLoader) {
+		if n > 0 {
+			l.concurrency = n
+		}
+	}
+}
+
 // WithLoaderFunc 设置自定义加载器工厂
 func WithLoaderFunc(fn func(path string) rag.Loader) DirectoryOption {
 	return func(l *DirectoryLoader) {
@@ -195,9 +277,10 @@ func WithLoaderFunc(fn func(path string) rag.Loader) DirectoryOption {
 // NewDirectoryLoader 创建目录加载器
 func NewDirectoryLoader(path string, opts ...DirectoryOption) *DirectoryLoader {
 	l := &DirectoryLoader{
-		path:      path,
-		pattern:   "*",
-		recursive: true,
+		path:        path,
+		pattern:     "*",
+		recursive:   true,
+		concurrency: 1,
 		loaderFunc: func(p string) rag.Loader {
 			ext := strings.ToLower(filepath.Ext(p))
 			switch ext {
@@ -216,8 +299,63 @@ func NewDirectoryLoader(path string, opts ...DirectoryOption) *DirectoryLoader {
 
 // Load 加载目录中的所有文件
 func (l *DirectoryLoader) Load(ctx context.Context) ([]rag.Document, error) {
+	files, err := l.collectFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每个文件的结果按下标存放，保证输出顺序与路径顺序一致
+	results := make([][]rag.Document, len(files))
+	sem := make(chan struct{}, l.concurrency)
+
+	var wg sync.WaitGroup
+	for i, path := range files {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			fileDocs, err := l.loaderFunc(path).Load(ctx)
+			if err != nil {
+				// 记录加载失败的文件（便于排查问题），继续处理其他文件
+				fmt.Fprintf(os.Stderr, "[WARN] hexagon/rag/loader: 加载文件 %s 失败: %v\n", path, err)
+				return
+			}
+			results[i] = fileDocs
+		}(i, path)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	var docs []rag.Document
+	for _, fileDocs := range results {
+		docs = append(docs, fileDocs...)
+	}
+	return docs, nil
+}
+
+// collectFiles 遍历目录，返回按路径排序的待加载文件
+func (l *DirectoryLoader) collectFiles(ctx context.Context) ([]string, error) {
+	includes := l.patterns
+	if len(includes) == 0 {
+		includes = []string{l.pattern}
+	}
 
+	var files []string
 	walkFn := func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -228,6 +366,13 @@ func (l *DirectoryLoader) Load(ctx context.Context) ([]rag.Document, error) {
 			return ctx.Err()
 		}
 
+		if path != l.path && l.excluded(path, info.Name()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// 跳过目录
 		if info.IsDir() {
 			if !l.recursive && path != l.path {
@@ -237,32 +382,46 @@ func (l *DirectoryLoader) Load(ctx context.Context) ([]rag.Document, error) {
 		}
 
 		// 匹配模式
-		matched, err := filepath.Match(l.pattern, filepath.Base(path))
-		if err != nil {
-			return err
-		}
-		if !matched {
-			return nil
-		}
-
-		// 加载文件
-		loader := l.loaderFunc(path)
-		fileDocs, err := loader.Load(ctx)
-		if err != nil {
-			// 记录加载失败的文件（便于排查问题），继续处理其他文件
-			fmt.Fprintf(os.Stderr, "[WARN] hexagon/rag/loader: 加载文件 %s 失败: %v\n", path, err)
-			return nil
+		for _, pattern := range includes {
+			matched, err := filepath.Match(pattern, info.Name())
+			if err != nil {
+				return err
+			}
+			if matched {
+				files = append(files, path)
+				return nil
+			}
 		}
-
-		docs = append(docs, fileDocs...)
 		return nil
 	}
 
 	if err := filepath.Walk(l.path, walkFn); err != nil {
 		return nil, fmt.Errorf("failed to walk directory %s: %w", l.path, err)
 	}
+	// filepath.Walk 只在每个目录内按文件名排序，"a/x" 会先于 "a-b" 被访问，
+	// 因此按完整路径重新排序
+	sort.Strings(files)
+	return files, nil
+}
 
-	return docs, nil
+// excluded 判断路径是否命中排除模式
+func (l *DirectoryLoader) excluded(path, name string) bool {
+	if len(l.excludePatterns) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(l.path, path)
+	if err != nil {
+		rel = path
+	}
+	for _, pattern := range l.excludePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(filepath.FromSlash(pattern), rel); ok {
+			return true
+		}
+	}
+	return false
 }
 
 // Name 返回加载器名称
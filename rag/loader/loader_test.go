@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -351,6 +352,78 @@ func TestDirectoryLoader_Load_ContextCancelled(t *testing.T) {
 	}
 }
 
+func TestDirectoryLoader_Load_ExcludeAndPatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(tmpDir, "node_modules", "pkg"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "docs"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "node_modules", "pkg", "readme.md"), []byte("dep"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "docs", "guide.md"), []byte("guide"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "docs", "notes.txt"), []byte("notes"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "app.js"), []byte("app"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "app.min.js"), []byte("min"), 0644)
+
+	l := NewDirectoryLoader(tmpDir,
+		WithPatterns("*.md", "*.txt", "*.js"),
+		WithExcludePatterns([]string{"node_modules", "*.min.js"}),
+	)
+	docs, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var got []string
+	for _, doc := range docs {
+		got = append(got, doc.Content)
+	}
+	want := []string{"app", "guide", "notes"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDirectoryLoader_Load_ConcurrentOrdered(t *testing.T) {
+	tmpDir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("file%02d.txt", i)
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(name), 0644)
+	}
+
+	serial, err := NewDirectoryLoader(tmpDir).Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	concurrent, err := NewDirectoryLoader(tmpDir, WithConcurrency(8)).Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(concurrent) != 20 || len(serial) != 20 {
+		t.Fatalf("expected 20 documents, got serial=%d concurrent=%d", len(serial), len(concurrent))
+	}
+	for i := range serial {
+		if serial[i].Content != concurrent[i].Content {
+			t.Errorf("position %d: expected %s, got %s", i, serial[i].Content, concurrent[i].Content)
+		}
+	}
+}
+
+func TestDirectoryLoader_Load_PathOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "a"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "a", "x.txt"), []byte("a/x"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "a-b.txt"), []byte("a-b"), 0644)
+
+	docs, err := NewDirectoryLoader(tmpDir).Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	// 按完整路径的字典序："a-b.txt" < "a/x.txt"
+	if len(docs) != 2 || docs[0].Content != "a-b" || docs[1].Content != "a/x" {
+		t.Errorf("expected path order [a-b a/x], got %+v", docs)
+	}
+}
+
 // ============== URLLoader 测试 ==============
 
 func TestNewURLLoader(t *testing.T) {
@@ -771,6 +844,123 @@ func TestTextLoader_DefaultEncoding(t *testing.T) {
 	}
 }
 
+// TestTextLoader_Encoding 验证按指定编码和探测结果转码
+func TestTextLoader_Encoding(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("创建临时文件失败: %v", err)
+		}
+		return path
+	}
+	load := func(t *testing.T, path string, opts ...TextOption) rag.Document {
+		t.Helper()
+		docs, err := NewTextLoader(path, opts...).Load(context.Background())
+		if err != nil {
+			t.Fatalf("Load 失败: %v", err)
+		}
+		return docs[0]
+	}
+
+	latin1 := write("latin1.txt", []byte("caf\xe9 na\xefve"))
+	cp1252 := write("cp1252.txt", []byte("\x93quoted\x94 \x80 5"))
+	utf16 := write("utf16.txt", []byte{0xFF, 0xFE, 'h', 0, 'i', 0, 0x2D, 0x4E})
+
+	t.Run("显式编码", func(t *testing.T) {
+		doc := load(t, latin1, WithEncoding("latin1"))
+		if doc.Content != "café naïve" || doc.Metadata["encoding"] != "iso-8859-1" {
+			t.Errorf("got %q (%v)", doc.Content, doc.Metadata["encoding"])
+		}
+		if _, ok := doc.Metadata["encoding_detected"]; ok {
+			t.Error("显式编码不应标记 encoding_detected")
+		}
+	})
+
+	t.Run("默认 utf-8 解码失败报错", func(t *testing.T) {
+		_, err := NewTextLoader(latin1).Load(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "invalid utf-8") {
+			t.Errorf("err = %v, want invalid utf-8 error", err)
+		}
+	})
+
+	t.Run("未知的编码", func(t *testing.T) {
+		_, err := NewTextLoader(latin1, WithEncoding("x-no-such-charset")).Load(context.Background())
+		if !errors.Is(err, ErrUnsupportedEncoding) {
+			t.Errorf("err = %v, want ErrUnsupportedEncoding", err)
+		}
+	})
+
+	t.Run("探测编码", func(t *testing.T) {
+		cases := []struct {
+			path, encoding, content string
+		}{
+			{latin1, "iso-8859-1", "café naïve"},
+			{cp1252, "windows-1252", "\u201cquoted\u201d € 5"},
+			{utf16, "utf-16", "hi中"},
+			{write("utf8.txt", []byte("\xef\xbb\xbf你好")), "utf-8", "你好"},
+		}
+		for _, c := range cases {
+			doc := load(t, c.path, WithEncodingDetection(true))
+			if doc.Content != c.content || doc.Metadata["encoding"] != c.encoding {
+				t.Errorf("%s: got %q (%v), want %q (%s)",
+					filepath.Base(c.path), doc.Content, doc.Metadata["encoding"], c.content, c.encoding)
+			}
+			if doc.Metadata["encoding_detected"] != true {
+				t.Errorf("%s: encoding_detected 未设置", filepath.Base(c.path))
+			}
+		}
+	})
+
+	t.Run("x/text 编码", func(t *testing.T) {
+		// "中文，你好" 的 GB18030 编码，"日本語" 的 Shift_JIS 编码
+		gb := write("gb18030.txt", []byte{0xD6, 0xD0, 0xCE, 0xC4, 0xA3, 0xAC, 0xC4, 0xE3, 0xBA, 0xC3})
+		sjis := write("sjis.txt", []byte{0x93, 0xFA, 0x96, 0x7B, 0x8C, 0xEA})
+
+		for _, enc := range []string{"gb18030", "GBK", "gb2312"} {
+			if doc := load(t, gb, WithEncoding(enc)); doc.Content != "中文，你好" || doc.Metadata["encoding"] != "gb18030" {
+				t.Errorf("%s: got %q (%v)", enc, doc.Content, doc.Metadata["encoding"])
+			}
+		}
+		if doc := load(t, gb, WithEncodingDetection(true)); doc.Content != "中文，你好" || doc.Metadata["encoding"] != "gb18030" {
+			t.Errorf("探测: got %q (%v)", doc.Content, doc.Metadata["encoding"])
+		}
+		if doc := load(t, sjis, WithEncoding("Shift_JIS")); doc.Content != "日本語" || doc.Metadata["encoding"] != "shift_jis" {
+			t.Errorf("shift_jis: got %q (%v)", doc.Content, doc.Metadata["encoding"])
+		}
+
+		// 无效字节序列返回错误而不是替换字符
+		bad := write("bad.txt", []byte{0xD6, 0xD0, 0x81})
+		if _, err := NewTextLoader(bad, WithEncoding("gb18030")).Load(context.Background()); err == nil {
+			t.Error("expected decode error for truncated gb18030 sequence")
+		}
+	})
+
+	t.Run("注册的编码参与探测", func(t *testing.T) {
+		// "中文" 的 GB18030 编码
+		gb := write("gb.txt", []byte{0xD6, 0xD0, 0xCE, 0xC4})
+		RegisterEncoding("gb18030", func(data []byte) (string, error) {
+			if !bytes.Equal(data, []byte{0xD6, 0xD0, 0xCE, 0xC4}) {
+				return "", fmt.Errorf("unexpected input")
+			}
+			return "中文", nil
+		})
+		defer func() {
+			encodingsMu.Lock()
+			delete(encodings, "gb18030")
+			encodingsMu.Unlock()
+		}()
+
+		doc := load(t, gb, WithEncodingDetection(true))
+		if doc.Content != "中文" || doc.Metadata["encoding"] != "gb18030" {
+			t.Errorf("got %q (%v)", doc.Content, doc.Metadata["encoding"])
+		}
+		if doc := load(t, gb, WithEncoding("GBK")); doc.Content != "中文" {
+			t.Errorf("别名 GBK: got %q", doc.Content)
+		}
+	})
+}
+
 // ============== MarkdownLoader 补充测试 ==============
 
 // TestMarkdownLoader_Load_BasicMarkdown 加载基础 Markdown 文件（无 front matter）
@@ -2387,6 +2577,108 @@ func TestSlackConnector_Name(t *testing.T) {
 	}
 }
 
+// TestConfluenceConnector_Load 分页加载空间页面
+func TestConfluenceConnector_Load(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/wiki/api/v2/spaces/42":
+			w.Write([]byte(`{"id":"42","key":"ENG","name":"Engineering"}`))
+		case r.URL.Path == "/wiki/api/v2/spaces/42/pages" && r.URL.Query().Get("cursor") == "":
+			if r.URL.Query().Get("body-format") != "storage" {
+				t.Errorf("body-format = %q", r.URL.Query().Get("body-format"))
+			}
+			w.Write([]byte(`{"results":[{"id":"1","title":"Intro","status":"current","authorId":"u1",
+				"version":{"number":3,"createdAt":"2024-01-02T00:00:00Z","authorId":"u2"},
+				"body":{"storage":{"value":"<p>Hello &amp; <strong>welcome</strong></p><ac:structured-macro ac:name=\"code\"><ac:parameter ac:name=\"language\">go</ac:parameter><ac:plain-text-body><![CDATA[if a < b {}]]></ac:plain-text-body></ac:structured-macro>"}},
+				"_links":{"webui":"/spaces/ENG/pages/1/Intro"}}],
+				"_links":{"next":"/wiki/api/v2/spaces/42/pages?cursor=abc","base":"http://` + r.Host + `/wiki"}}`))
+		case r.URL.Query().Get("cursor") == "abc":
+			w.Write([]byte(`{"results":[{"id":"2","title":"Second","version":{"number":1},"body":{"storage":{"value":"<p>Two</p>"}}}],"_links":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewConfluenceConnector(&ConfluenceConfig{
+		BaseURL: server.URL + "/",
+		SpaceID: "42",
+		Email:   "me@example.com",
+		Token:   "secret",
+	})
+	if got := c.Name(); got != "confluence" {
+		t.Errorf("Name() = %q, 期望 %q", got, "confluence")
+	}
+
+	docs, err := c.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load 失败: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("期望 2 个文档, 实际 %d", len(docs))
+	}
+
+	first := docs[0]
+	if first.ID != "confluence-1" {
+		t.Errorf("ID = %q", first.ID)
+	}
+	if want := "# Intro\n\nHello & welcome if a < b {}"; first.Content != want {
+		t.Errorf("Content = %q, 期望 %q", first.Content, want)
+	}
+	meta := first.Metadata
+	if meta["space_key"] != "ENG" || meta["space_name"] != "Engineering" || meta["title"] != "Intro" ||
+		meta["author"] != "u1" || meta["version"] != 3 || meta["updated_by"] != "u2" {
+		t.Errorf("元数据错误: %v", meta)
+	}
+	if meta["url"] != server.URL+"/wiki/spaces/ENG/pages/1/Intro" {
+		t.Errorf("url = %v", meta["url"])
+	}
+	if docs[1].Content != "# Second\n\nTwo" {
+		t.Errorf("第二页 Content = %q", docs[1].Content)
+	}
+
+	// MaxPages 限制页面数量
+	c.maxPages = 1
+	docs, err = c.Load(context.Background())
+	if err != nil || len(docs) != 1 {
+		t.Errorf("MaxPages=1: docs=%d, err=%v", len(docs), err)
+	}
+}
+
+// TestConfluenceConnector_Errors 错误映射
+func TestConfluenceConnector_Errors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrAuthFailed},
+		{http.StatusForbidden, ErrAuthFailed},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, ErrConnectorFailed},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		c := NewConfluenceConnector(&ConfluenceConfig{BaseURL: server.URL, SpaceID: "42", Token: "pat", Retry: fastConnectorRetry})
+		_, err := c.Load(context.Background())
+		server.Close()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: err = %v, 期望 %v", tt.status, err, tt.want)
+		}
+	}
+
+	if _, err := NewConfluenceConnector(&ConfluenceConfig{}).Load(context.Background()); !errors.Is(err, ErrConnectorFailed) {
+		t.Errorf("缺少配置时 err = %v", err)
+	}
+}
+
 // TestDatabaseConnector_Name 验证名称
 func TestDatabaseConnector_Name(t *testing.T) {
 	c := NewDatabaseConnector(&DatabaseConfig{})
@@ -2721,7 +3013,7 @@ func TestGitHubConnector_doRequest_Errors(t *testing.T) {
 			}))
 			defer server.Close()
 
-			gc := NewGitHubConnector(&GitHubConfig{Owner: "o", Repo: "r", Token: "tok"})
+			gc := NewGitHubConnector(&GitHubConfig{Owner: "o", Repo: "r", Token: "tok", Retry: fastConnectorRetry})
 			gc.client = &http.Client{Transport: &redirectTransport{server: server}}
 			_, err := gc.Load(context.Background())
 			if err == nil {
@@ -2833,7 +3125,7 @@ func TestNotionConnector_loadPage_ServerError(t *testing.T) {
 	}))
 	defer server.Close()
 
-	nc := NewNotionConnector(&NotionConfig{Token: "tok", PageID: "page-123"})
+	nc := NewNotionConnector(&NotionConfig{Token: "tok", PageID: "page-123", Retry: fastConnectorRetry})
 	nc.client = &http.Client{Transport: &redirectTransport{server: server}}
 	_, err := nc.Load(context.Background())
 	if err == nil {
@@ -2903,6 +3195,145 @@ func TestSlackConnector_Load_APIError(t *testing.T) {
 	}
 }
 
+// TestSlackConnector_Load_Pagination 测试游标翻页和时间过滤
+func TestSlackConnector_Load_Pagination(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("oldest") != "100.0" || q.Get("latest") != "300.0" {
+			t.Errorf("oldest/latest = %q/%q", q.Get("oldest"), q.Get("latest"))
+		}
+		cursors = append(cursors, q.Get("cursor"))
+		if q.Get("cursor") == "" {
+			json.NewEncoder(w).Encode(map[string]any{
+				"ok":                true,
+				"messages":          []map[string]any{{"text": "a", "ts": "250.0"}, {"text": "b", "ts": "200.0"}},
+				"has_more":          true,
+				"response_metadata": map[string]any{"next_cursor": "page2"},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"ok":       true,
+			"messages": []map[string]any{{"text": "c", "ts": "150.0"}},
+		})
+	}))
+	defer server.Close()
+
+	sc := NewSlackConnector(&SlackConfig{Token: "tok", ChannelID: "C123"},
+		WithSlackOldest("100.0"), WithSlackLatest("300.0"))
+	sc.client = &http.Client{Transport: &redirectTransport{server: server}}
+	docs, err := sc.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load 失败: %v", err)
+	}
+	if len(docs) != 3 || docs[2].Content != "c" {
+		t.Fatalf("期望 3 个消息, 实际 %d", len(docs))
+	}
+	if len(cursors) != 2 || cursors[1] != "page2" {
+		t.Errorf("cursors = %v", cursors)
+	}
+
+	// Limit 截断
+	cursors = nil
+	sc = NewSlackConnector(&SlackConfig{Token: "tok", ChannelID: "C123", Limit: 1},
+		WithSlackOldest("100.0"), WithSlackLatest("300.0"))
+	sc.client = &http.Client{Transport: &redirectTransport{server: server}}
+	docs, err = sc.Load(context.Background())
+	if err != nil || len(docs) != 1 || len(cursors) != 1 {
+		t.Errorf("Limit=1: docs = %d, requests = %d, err = %v", len(docs), len(cursors), err)
+	}
+}
+
+// TestSlackConnector_Limit 测试默认数量限制和不限制选项
+func TestSlackConnector_Limit(t *testing.T) {
+	var limits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		json.NewEncoder(w).Encode(map[string]any{"ok": true, "messages": []map[string]any{}})
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		name string
+		sc   *SlackConnector
+		want string
+	}{
+		{"默认", NewSlackConnector(&SlackConfig{Token: "tok", ChannelID: "C123"}), "100"},
+		{"Limit", NewSlackConnector(&SlackConfig{Token: "tok", ChannelID: "C123", Limit: 30}), "30"},
+		{"不限制", NewSlackConnector(&SlackConfig{Token: "tok", ChannelID: "C123", Limit: 30}, WithSlackNoLimit()), "200"},
+	} {
+		limits = nil
+		tt.sc.client = &http.Client{Transport: &redirectTransport{server: server}}
+		if _, err := tt.sc.Load(context.Background()); err != nil {
+			t.Fatalf("%s: Load 失败: %v", tt.name, err)
+		}
+		if len(limits) != 1 || limits[0] != tt.want {
+			t.Errorf("%s: limit = %v, want %s", tt.name, limits, tt.want)
+		}
+	}
+}
+
+// TestSlackConnector_Load_ThreadReplies 测试线程回复作为子文档
+func TestSlackConnector_Load_ThreadReplies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "conversations.history"):
+			json.NewEncoder(w).Encode(map[string]any{
+				"ok": true,
+				"messages": []map[string]any{
+					{"text": "question", "ts": "10.0", "thread_ts": "10.0", "reply_count": 2},
+					{"text": "plain", "ts": "5.0"},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "conversations.replies"):
+			if r.URL.Query().Get("ts") != "10.0" {
+				t.Errorf("replies ts = %q", r.URL.Query().Get("ts"))
+			}
+			if r.URL.Query().Get("cursor") == "" {
+				json.NewEncoder(w).Encode(map[string]any{
+					"ok":                true,
+					"messages":          []map[string]any{{"text": "question", "ts": "10.0", "thread_ts": "10.0"}, {"text": "answer1", "ts": "11.0", "thread_ts": "10.0"}},
+					"has_more":          true,
+					"response_metadata": map[string]any{"next_cursor": "r2"},
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"ok":       true,
+				"messages": []map[string]any{{"text": "answer2", "ts": "12.0", "thread_ts": "10.0"}},
+			})
+		}
+	}))
+	defer server.Close()
+
+	sc := NewSlackConnector(&SlackConfig{Token: "tok", ChannelID: "C123"}, WithSlackThreadReplies())
+	sc.client = &http.Client{Transport: &redirectTransport{server: server}}
+	docs, err := sc.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load 失败: %v", err)
+	}
+
+	var got []string
+	for _, doc := range docs {
+		got = append(got, doc.Content)
+	}
+	if strings.Join(got, ",") != "question,answer1,answer2,plain" {
+		t.Fatalf("文档顺序 = %v", got)
+	}
+	if docs[0].Metadata["reply_count"] != 2 {
+		t.Errorf("reply_count = %v", docs[0].Metadata["reply_count"])
+	}
+	for _, reply := range docs[1:3] {
+		if reply.Metadata["parent_id"] != "10.0" || reply.Metadata["thread_ts"] != "10.0" {
+			t.Errorf("回复元数据 = %v", reply.Metadata)
+		}
+	}
+	if _, ok := docs[3].Metadata["parent_id"]; ok {
+		t.Error("顶层消息不应有 parent_id")
+	}
+}
+
 // ============== DatabaseConnector applyTemplate 测试 ==============
 
 // TestDatabaseConnector_applyTemplate 测试模板替换
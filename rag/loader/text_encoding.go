@@ -0,0 +1,292 @@
+package loader
+
+// 本文件实现 TextLoader 的文本编码处理，包括：
+//   - 编码注册表（内置 UTF-8 / UTF-16 / Latin-1 / Windows-1252，可注册其他编码）
+//   - 未注册的编码按 WHATWG / IANA 名称使用 golang.org/x/text 解码（GB18030、GBK、Shift_JIS 等）
+//   - 基于 BOM 和字节分布的编码探测
+//   - 转码为 UTF-8 及解码失败检测
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// ErrUnsupportedEncoding 编码未注册
+var ErrUnsupportedEncoding = errors.New("unsupported text encoding")
+
+// DecodeFunc 将指定编码的字节转码为 UTF-8 字符串
+// 遇到该编码下无效的字节序列时应返回错误，而不是替换为乱码
+type DecodeFunc func(data []byte) (string, error)
+
+var (
+	encodingsMu sync.RWMutex
+	encodings   = map[string]DecodeFunc{
+		"utf-8":        decodeUTF8,
+		"utf-16":       decodeUTF16BOM,
+		"utf-16le":     decodeUTF16(false),
+		"utf-16be":     decodeUTF16(true),
+		"iso-8859-1":   decodeLatin1,
+		"windows-1252": decodeWindows1252,
+	}
+
+	// encodingAliases 编码别名 -> 规范名称
+	encodingAliases = map[string]string{
+		"utf8":     "utf-8",
+		"ascii":    "utf-8",
+		"us-ascii": "utf-8",
+		"utf16":    "utf-16",
+		"latin1":   "iso-8859-1",
+		"latin-1":  "iso-8859-1",
+		"cp1252":   "windows-1252",
+		"gbk":      "gb18030",
+		"gb2312":   "gb18030",
+	}
+)
+
+// RegisterEncoding 注册文本编码，已存在时覆盖
+//
+// 内置 utf-8、utf-16（按 BOM 判断字节序）、utf-16le、utf-16be、iso-8859-1 和 windows-1252；
+// 其他名称（如 gb18030、shift_jis、euc-kr、big5）按 WHATWG 和 IANA 编码名称
+// 由 golang.org/x/text 解码，无需注册。注册用于替换这些解码器或支持自定义编码。
+// gbk、gb2312 是 gb18030 的别名，注册 gb18030 同样作用于它们。
+func RegisterEncoding(name string, decode DecodeFunc) {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+	encodings[strings.ToLower(name)] = decode
+}
+
+// lookupEncoding 按名称或别名查找编码，返回规范名称
+// 未注册的名称依次按 WHATWG（htmlindex）和 IANA（ianaindex）名称查找 golang.org/x/text 的编码
+func lookupEncoding(name string) (string, DecodeFunc, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := encodingAliases[name]; ok {
+		name = canonical
+	}
+	encodingsMu.RLock()
+	decode, ok := encodings[name]
+	encodingsMu.RUnlock()
+	if ok {
+		return name, decode, true
+	}
+
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		enc, err = ianaindex.IANA.Encoding(name)
+	}
+	if err != nil || enc == nil {
+		return name, nil, false
+	}
+	if canonical, err := htmlindex.Name(enc); err == nil {
+		name = canonical
+	}
+	return name, decodeWith(enc), true
+}
+
+// decodeWith 返回使用 x/text 编码解码的函数
+// x/text 解码器将无效字节替换为 U+FFFD，出现替换字符时视为解码失败
+func decodeWith(enc encoding.Encoding) DecodeFunc {
+	return func(data []byte) (string, error) {
+		out, err := enc.NewDecoder().Bytes(data)
+		if err != nil {
+			return "", err
+		}
+		if i := bytes.IndexRune(out, utf8.RuneError); i >= 0 {
+			return "", fmt.Errorf("invalid byte sequence near decoded offset %d", i)
+		}
+		return string(out), nil
+	}
+}
+
+// decodeText 按指定编码将内容转码为 UTF-8
+func decodeText(data []byte, encoding string) (string, string, error) {
+	name, decode, ok := lookupEncoding(encoding)
+	if !ok {
+		return "", name, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+	}
+	content, err := decode(data)
+	if err != nil {
+		return "", name, fmt.Errorf("decode as %s: %w", name, err)
+	}
+	return content, name, nil
+}
+
+// detectEncoding 探测内容的编码
+//
+// 依次根据 BOM、UTF-8 有效性、UTF-16 的零字节分布判断；
+// 字节序列符合 GB18030 双字节或四字节结构且能解码时使用 gb18030，
+// 否则含 0x80-0x9F 字节且均为有效码位时按 windows-1252，其余按 iso-8859-1 处理。
+func detectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8"
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return "utf-16"
+	}
+
+	if enc, ok := detectUTF16(data); ok {
+		return enc
+	}
+	if utf8.Valid(data) {
+		return "utf-8"
+	}
+	if looksLikeGB18030(data) {
+		if _, decode, ok := lookupEncoding("gb18030"); ok {
+			if _, err := decode(data); err == nil {
+				return "gb18030"
+			}
+		}
+	}
+	for _, b := range data {
+		if b >= 0x80 && b <= 0x9F {
+			if _, err := decodeWindows1252(data); err == nil {
+				return "windows-1252"
+			}
+			break
+		}
+	}
+	return "iso-8859-1"
+}
+
+// detectUTF16 根据零字节分布判断无 BOM 的 UTF-16
+// 以 ASCII 为主的 UTF-16 文本中，高位字节几乎全为 0
+func detectUTF16(data []byte) (string, bool) {
+	if len(data) < 4 || len(data)%2 != 0 {
+		return "", false
+	}
+	var evenZeros, oddZeros int
+	for i := 0; i < len(data); i += 2 {
+		if data[i] == 0 {
+			evenZeros++
+		}
+		if data[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	pairs := len(data) / 2
+	switch {
+	case oddZeros*10 >= pairs*7 && evenZeros*10 < pairs:
+		return "utf-16le", true
+	case evenZeros*10 >= pairs*7 && oddZeros*10 < pairs:
+		return "utf-16be", true
+	}
+	return "", false
+}
+
+// looksLikeGB18030 检查非 ASCII 字节是否都构成合法的 GB18030 双字节或四字节序列
+func looksLikeGB18030(data []byte) bool {
+	multi := 0
+	for i := 0; i < len(data); {
+		b := data[i]
+		switch {
+		case b < 0x80:
+			i++
+			continue
+		case b == 0x80 || b == 0xFF || i+1 >= len(data):
+			return false
+		}
+		next := data[i+1]
+		switch {
+		case next >= 0x40 && next <= 0xFE && next != 0x7F:
+			i += 2
+		case next >= 0x30 && next <= 0x39 && i+3 < len(data) &&
+			data[i+2] >= 0x81 && data[i+2] <= 0xFE && data[i+3] >= 0x30 && data[i+3] <= 0x39:
+			i += 4
+		default:
+			return false
+		}
+		multi++
+	}
+	return multi > 0
+}
+
+// decodeUTF8 校验 UTF-8 并去除 BOM
+func decodeUTF8(data []byte) (string, error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("invalid utf-8 sequence at byte %d", invalidUTF8Offset(data))
+	}
+	return string(data), nil
+}
+
+// invalidUTF8Offset 返回第一个无效 UTF-8 序列的字节偏移
+func invalidUTF8Offset(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return i
+		}
+		i += size
+	}
+	return len(data)
+}
+
+// decodeUTF16BOM 按 BOM 判断字节序解码 UTF-16，无 BOM 时按大端处理
+func decodeUTF16BOM(data []byte) (string, error) {
+	if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+		return decodeUTF16(false)(data)
+	}
+	return decodeUTF16(true)(data)
+}
+
+// decodeUTF16 返回指定字节序的 UTF-16 解码函数，自动去除对应的 BOM
+func decodeUTF16(bigEndian bool) DecodeFunc {
+	return func(data []byte) (string, error) {
+		if len(data)%2 != 0 {
+			return "", fmt.Errorf("invalid utf-16: odd length %d", len(data))
+		}
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i < len(data); i += 2 {
+			if bigEndian {
+				units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+			} else {
+				units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+			}
+		}
+		if len(units) > 0 && units[0] == 0xFEFF {
+			units = units[1:]
+		}
+		for i := 0; i < len(units); i++ {
+			switch u := units[i]; {
+			case u >= 0xD800 && u < 0xDC00:
+				if i+1 >= len(units) || units[i+1] < 0xDC00 || units[i+1] > 0xDFFF {
+					return "", fmt.Errorf("invalid utf-16: unpaired surrogate at byte %d", i*2)
+				}
+				i++
+			case u >= 0xDC00 && u <= 0xDFFF:
+				return "", fmt.Errorf("invalid utf-16: unpaired surrogate at byte %d", i*2)
+			}
+		}
+		return string(utf16.Decode(units)), nil
+	}
+}
+
+// decodeLatin1 ISO-8859-1 每个字节即 Unicode 码位，总能解码
+func decodeLatin1(data []byte) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(data))
+	for _, b := range data {
+		sb.WriteRune(rune(b))
+	}
+	return sb.String(), nil
+}
+
+// decodeWindows1252 解码 Windows-1252，未定义的码位返回错误
+func decodeWindows1252(data []byte) (string, error) {
+	for i, b := range data {
+		if b >= 0x80 && b <= 0x9F {
+			if _, ok := winAnsiEncoding[b]; !ok {
+				return "", fmt.Errorf("invalid windows-1252 byte 0x%02X at byte %d", b, i)
+			}
+		}
+	}
+	return decodeWinAnsi(data), nil
+}
@@ -0,0 +1,481 @@
+// Package loader 提供 RAG 系统的文档加载器
+//
+// xml.go 实现 XML 文件加载器：
+//   - XMLLoader: 按元素路径（如 //item）将每个匹配元素转换为一个文档
+//
+// 使用示例：
+//
+//	loader := NewXMLLoader("feed.xml", "//item",
+//	    WithXMLContentPath("description"),
+//	    WithXMLMetadataPaths("title", "link", "enclosure/@url"),
+//	)
+//	docs, err := loader.Load(ctx)
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/internal/util"
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// ============== XMLLoader ==============
+
+// XMLLoader XML 文件加载器
+// 将每个匹配元素路径的元素转换为一个文档，适用于 RSS、Atom 和自定义数据导出
+//
+// 元素路径支持 XPath 的子集：
+//   - //item：任意层级的 item 元素
+//   - /rss/channel/item：从根元素开始的绝对路径
+//   - channel/item：任意层级下 channel 的 item 子元素（等同 //channel/item）
+//   - *：匹配任意元素名
+//
+// 带前缀的名称（如 atom:entry）通过 WithXMLNamespace 绑定命名空间 URI；
+// 不带前缀的名称匹配任意命名空间下的同名元素。
+//
+// 文件以 token 流方式解析，内存中只保留当前匹配的元素；
+// 匹配元素内部嵌套的匹配元素不会再单独生成文档。
+type XMLLoader struct {
+	// path 文件路径
+	path string
+
+	// elementPath 元素路径
+	elementPath string
+
+	// contentPath 内容路径（相对匹配元素，为空时使用元素的全部文本）
+	contentPath string
+
+	// metadataPaths 元数据路径（为空时提取元素属性和叶子子元素）
+	metadataPaths []string
+
+	// namespaces 前缀 -> 命名空间 URI
+	namespaces map[string]string
+}
+
+// XMLOption XML 加载器选项
+type XMLOption func(*XMLLoader)
+
+// WithXMLContentPath 设置内容路径，相对于匹配元素
+// 如 "description"、"content:encoded"、"body/text" 或 "@summary"（属性）
+// 默认使用匹配元素的全部文本
+func WithXMLContentPath(path string) XMLOption {
+	return func(l *XMLLoader) {
+		l.contentPath = path
+	}
+}
+
+// WithXMLMetadataPaths 设置元数据路径，相对于匹配元素，元数据键即路径本身
+// 路径末尾可以是属性（如 "enclosure/@url"、"@id"）；同名元素出现多次时值为 []string
+// 默认提取匹配元素的属性（键为 "@属性名"）和所有叶子子元素的文本（键为元素名）
+func WithXMLMetadataPaths(paths ...string) XMLOption {
+	return func(l *XMLLoader) {
+		l.metadataPaths = paths
+	}
+}
+
+// WithXMLNamespace 绑定路径中使用的命名空间前缀
+// 如 WithXMLNamespace("atom", "http://www.w3.org/2005/Atom") 后可使用 //atom:entry
+func WithXMLNamespace(prefix, uri string) XMLOption {
+	return func(l *XMLLoader) {
+		if l.namespaces == nil {
+			l.namespaces = make(map[string]string)
+		}
+		l.namespaces[prefix] = uri
+	}
+}
+
+// NewXMLLoader 创建 XML 加载器
+func NewXMLLoader(path, elementPath string, opts ...XMLOption) *XMLLoader {
+	l := &XMLLoader{
+		path:        path,
+		elementPath: elementPath,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Load 加载 XML 文件
+func (l *XMLLoader) Load(ctx context.Context) ([]rag.Document, error) {
+	var docs []rag.Document
+	err := l.each(ctx, func(doc rag.Document) error {
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Stream 流式加载 XML 文件，每解析出一个匹配元素即发送一个文档
+//
+// 文档 channel 关闭后，错误 channel 发送解析错误（如有）并关闭。
+// 文档 channel 可直接交给 rag.Engine.IndexStream，大文件无需整体加载到内存。
+func (l *XMLLoader) Stream(ctx context.Context) (<-chan rag.Document, <-chan error) {
+	out := make(chan rag.Document)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(out)
+		err := l.each(ctx, func(doc rag.Document) error {
+			select {
+			case out <- doc:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return out, errc
+}
+
+// each 解析文件，对每个匹配元素生成的文档调用 fn
+func (l *XMLLoader) each(ctx context.Context, fn func(rag.Document) error) error {
+	match, err := l.compile(l.elementPath)
+	if err != nil {
+		return err
+	}
+	contentPath, err := l.compileRelative(l.contentPath)
+	if err != nil {
+		return err
+	}
+	metadataPaths := make([]xmlRelPath, len(l.metadataPaths))
+	for i, p := range l.metadataPaths {
+		if metadataPaths[i], err = l.compileRelative(p); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return fmt.Errorf("无法读取 XML 文件 %s: %w", l.path, err)
+	}
+	defer f.Close()
+
+	dec := xml.NewDecoder(bufio.NewReader(f))
+	var stack []xml.Name
+	index := 0
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("解析 XML 文件 %s 失败: %w", l.path, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name)
+			if !match.matches(stack) {
+				continue
+			}
+			node, err := readXMLNode(dec, t)
+			if err != nil {
+				return fmt.Errorf("解析 XML 文件 %s 失败: %w", l.path, err)
+			}
+			stack = stack[:len(stack)-1]
+			if err := fn(l.toDocument(node, index, contentPath, metadataPaths)); err != nil {
+				return err
+			}
+			index++
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// toDocument 将匹配元素转换为文档
+func (l *XMLLoader) toDocument(node *xmlNode, index int, contentPath xmlRelPath, metadataPaths []xmlRelPath) rag.Document {
+	metadata := map[string]any{
+		"loader":    "xml",
+		"file_path": l.path,
+		"file_name": filepath.Base(l.path),
+		"element":   node.name.Local,
+		"index":     index,
+	}
+
+	var content string
+	if contentPath.raw == "" {
+		content = node.innerText()
+	} else if v := contentPath.values(node); len(v) > 0 {
+		content = strings.Join(v, "\n")
+	}
+
+	if len(metadataPaths) > 0 {
+		for _, p := range metadataPaths {
+			setXMLMetadata(metadata, p.raw, p.values(node))
+		}
+	} else {
+		for _, attr := range node.attrs {
+			metadata["@"+attr.Name.Local] = attr.Value
+		}
+		for _, child := range node.elements() {
+			if len(child.elements()) > 0 || (contentPath.raw != "" && contentPath.isChild(child)) {
+				continue
+			}
+			values, _ := metadata[child.name.Local].([]string)
+			metadata[child.name.Local] = append(values, child.innerText())
+		}
+		for key, v := range metadata {
+			if values, ok := v.([]string); ok {
+				setXMLMetadata(metadata, key, values)
+			}
+		}
+	}
+
+	return rag.Document{
+		ID:        util.GenerateID("doc"),
+		Content:   content,
+		Source:    fmt.Sprintf("%s#element=%d", l.path, index),
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}
+}
+
+// setXMLMetadata 单个值存为 string，多个值存为 []string，无值时不设置
+func setXMLMetadata(metadata map[string]any, key string, values []string) {
+	switch len(values) {
+	case 0:
+		delete(metadata, key)
+	case 1:
+		metadata[key] = values[0]
+	default:
+		metadata[key] = values
+	}
+}
+
+// Name 返回加载器名称
+func (l *XMLLoader) Name() string {
+	return "XMLLoader"
+}
+
+var _ rag.Loader = (*XMLLoader)(nil)
+
+// ============== 元素路径 ==============
+
+// xmlStep 路径中的一级元素名
+type xmlStep struct {
+	// space 命名空间 URI，为空时匹配任意命名空间
+	space string
+
+	// local 本地名称，"*" 匹配任意名称
+	local string
+}
+
+func (s xmlStep) matches(name xml.Name) bool {
+	return (s.local == "*" || s.local == name.Local) && (s.space == "" || s.space == name.Space)
+}
+
+// xmlPath 元素路径
+type xmlPath struct {
+	steps []xmlStep
+
+	// anywhere 为 true 时路径可以从任意层级开始
+	anywhere bool
+}
+
+// matches 检查当前元素栈是否匹配路径
+func (p xmlPath) matches(stack []xml.Name) bool {
+	if len(stack) < len(p.steps) || (!p.anywhere && len(stack) != len(p.steps)) {
+		return false
+	}
+	offset := len(stack) - len(p.steps)
+	for i, step := range p.steps {
+		if !step.matches(stack[offset+i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// compile 解析元素路径
+func (l *XMLLoader) compile(path string) (xmlPath, error) {
+	p := xmlPath{anywhere: true}
+	rest := path
+	switch {
+	case strings.HasPrefix(rest, "//"):
+		rest = rest[2:]
+	case strings.HasPrefix(rest, "/"):
+		rest = rest[1:]
+		p.anywhere = false
+	}
+	if rest == "" || strings.Contains(rest, "//") || strings.Contains(rest, "@") {
+		return xmlPath{}, fmt.Errorf("无效的 XML 元素路径: %q", path)
+	}
+	for _, part := range strings.Split(rest, "/") {
+		step, err := l.step(part)
+		if err != nil {
+			return xmlPath{}, fmt.Errorf("无效的 XML 元素路径 %q: %w", path, err)
+		}
+		p.steps = append(p.steps, step)
+	}
+	return p, nil
+}
+
+// step 解析单级元素名，带前缀时解析为命名空间 URI
+func (l *XMLLoader) step(part string) (xmlStep, error) {
+	if part == "" {
+		return xmlStep{}, fmt.Errorf("空的元素名")
+	}
+	prefix, local, ok := strings.Cut(part, ":")
+	if !ok {
+		return xmlStep{local: part}, nil
+	}
+	uri, bound := l.namespaces[prefix]
+	if !bound {
+		return xmlStep{}, fmt.Errorf("未绑定的命名空间前缀 %q", prefix)
+	}
+	return xmlStep{space: uri, local: local}, nil
+}
+
+// xmlRelPath 相对匹配元素的路径，末尾可以是属性
+type xmlRelPath struct {
+	raw   string
+	steps []xmlStep
+	attr  *xmlStep
+}
+
+// compileRelative 解析相对路径，空路径返回零值
+func (l *XMLLoader) compileRelative(path string) (xmlRelPath, error) {
+	p := xmlRelPath{raw: path}
+	if path == "" {
+		return p, nil
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if attr, ok := strings.CutPrefix(part, "@"); ok && i == len(parts)-1 {
+			step, err := l.step(attr)
+			if err != nil {
+				return xmlRelPath{}, fmt.Errorf("无效的 XML 路径 %q: %w", path, err)
+			}
+			p.attr = &step
+			continue
+		}
+		step, err := l.step(part)
+		if err != nil {
+			return xmlRelPath{}, fmt.Errorf("无效的 XML 路径 %q: %w", path, err)
+		}
+		p.steps = append(p.steps, step)
+	}
+	return p, nil
+}
+
+// isChild 检查元素是否为路径的第一级（用于默认元数据中排除内容元素）
+func (p xmlRelPath) isChild(node *xmlNode) bool {
+	return len(p.steps) > 0 && p.steps[0].matches(node.name)
+}
+
+// values 返回路径在元素中匹配到的所有文本或属性值
+func (p xmlRelPath) values(node *xmlNode) []string {
+	nodes := []*xmlNode{node}
+	for _, step := range p.steps {
+		var next []*xmlNode
+		for _, n := range nodes {
+			for _, child := range n.elements() {
+				if step.matches(child.name) {
+					next = append(next, child)
+				}
+			}
+		}
+		nodes = next
+	}
+
+	var values []string
+	for _, n := range nodes {
+		if p.attr == nil {
+			values = append(values, n.innerText())
+			continue
+		}
+		for _, attr := range n.attrs {
+			if p.attr.matches(attr.Name) {
+				values = append(values, attr.Value)
+			}
+		}
+	}
+	return values
+}
+
+// ============== 元素树 ==============
+
+// xmlNode 匹配元素的子树，按文档顺序保存文本和子元素
+type xmlNode struct {
+	name  xml.Name
+	attrs []xml.Attr
+
+	// items 文本（string）和子元素（*xmlNode）
+	items []any
+}
+
+// readXMLNode 读取 start 对应元素的完整子树
+func readXMLNode(dec *xml.Decoder, start xml.StartElement) (*xmlNode, error) {
+	node := &xmlNode{name: start.Name, attrs: start.Attr}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := readXMLNode(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			node.items = append(node.items, child)
+		case xml.CharData:
+			node.items = append(node.items, string(t))
+		case xml.EndElement:
+			return node, nil
+		}
+	}
+}
+
+// elements 返回子元素
+func (n *xmlNode) elements() []*xmlNode {
+	var children []*xmlNode
+	for _, item := range n.items {
+		if child, ok := item.(*xmlNode); ok {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// innerText 返回元素内的全部文本，相邻元素的文本以换行分隔
+func (n *xmlNode) innerText() string {
+	var buf bytes.Buffer
+	n.writeText(&buf)
+	return strings.TrimSpace(buf.String())
+}
+
+func (n *xmlNode) writeText(buf *bytes.Buffer) {
+	for _, item := range n.items {
+		switch v := item.(type) {
+		case string:
+			buf.WriteString(v)
+		case *xmlNode:
+			if b := buf.Bytes(); len(b) > 0 && b[len(b)-1] != '\n' && b[len(b)-1] != ' ' {
+				buf.WriteByte('\n')
+			}
+			v.writeText(buf)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+const testRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <title>Feed</title>
+    <item id="1">
+      <title>First</title>
+      <link>https://example.com/1</link>
+      <category>go</category>
+      <category>rag</category>
+      <description>Summary one</description>
+      <content:encoded><![CDATA[<p>Full text one</p>]]></content:encoded>
+      <enclosure url="https://example.com/1.mp3" type="audio/mpeg"/>
+    </item>
+    <item id="2">
+      <title>Second</title>
+      <description>Summary <b>two</b></description>
+    </item>
+  </channel>
+</rss>`
+
+func writeXMLFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("创建临时文件失败: %v", err)
+	}
+	return path
+}
+
+func TestXMLLoader_Load(t *testing.T) {
+	path := writeXMLFile(t, testRSS)
+	ctx := context.Background()
+
+	t.Run("内容和元数据路径", func(t *testing.T) {
+		docs, err := NewXMLLoader(path, "//item",
+			WithXMLNamespace("content", "http://purl.org/rss/1.0/modules/content/"),
+			WithXMLContentPath("content:encoded"),
+			WithXMLMetadataPaths("title", "category", "enclosure/@url", "@id"),
+		).Load(ctx)
+		if err != nil {
+			t.Fatalf("Load 失败: %v", err)
+		}
+		if len(docs) != 2 {
+			t.Fatalf("文档数量 = %d, want 2", len(docs))
+		}
+
+		first := docs[0]
+		if first.Content != "<p>Full text one</p>" {
+			t.Errorf("Content = %q", first.Content)
+		}
+		want := map[string]any{
+			"title":          "First",
+			"category":       []string{"go", "rag"},
+			"enclosure/@url": "https://example.com/1.mp3",
+			"@id":            "1",
+		}
+		for key, v := range want {
+			if !reflect.DeepEqual(first.Metadata[key], v) {
+				t.Errorf("Metadata[%q] = %v, want %v", key, first.Metadata[key], v)
+			}
+		}
+		if first.Metadata["loader"] != "xml" || first.Metadata["index"] != 0 {
+			t.Errorf("基础元数据错误: %v", first.Metadata)
+		}
+
+		// 第二项没有 content:encoded 和 enclosure
+		if docs[1].Content != "" {
+			t.Errorf("Content = %q, want empty", docs[1].Content)
+		}
+		if _, ok := docs[1].Metadata["enclosure/@url"]; ok {
+			t.Error("缺失的路径不应设置元数据")
+		}
+	})
+
+	t.Run("默认提取", func(t *testing.T) {
+		docs, err := NewXMLLoader(path, "/rss/channel/item", WithXMLContentPath("description")).Load(ctx)
+		if err != nil {
+			t.Fatalf("Load 失败: %v", err)
+		}
+		if len(docs) != 2 {
+			t.Fatalf("文档数量 = %d, want 2", len(docs))
+		}
+		if docs[1].Content != "Summary two" {
+			t.Errorf("Content = %q, want %q", docs[1].Content, "Summary two")
+		}
+		meta := docs[0].Metadata
+		if meta["@id"] != "1" || meta["title"] != "First" || meta["link"] != "https://example.com/1" {
+			t.Errorf("默认元数据错误: %v", meta)
+		}
+		if _, ok := meta["description"]; ok {
+			t.Error("内容元素不应出现在元数据中")
+		}
+		if !reflect.DeepEqual(meta["category"], []string{"go", "rag"}) {
+			t.Errorf("category = %v", meta["category"])
+		}
+	})
+
+	t.Run("全部文本", func(t *testing.T) {
+		docs, err := NewXMLLoader(path, "channel/item").Load(ctx)
+		if err != nil {
+			t.Fatalf("Load 失败: %v", err)
+		}
+		if !strings.Contains(docs[0].Content, "First") || !strings.Contains(docs[0].Content, "Summary one") {
+			t.Errorf("Content = %q", docs[0].Content)
+		}
+	})
+
+	t.Run("无效路径", func(t *testing.T) {
+		if _, err := NewXMLLoader(path, "//atom:entry").Load(ctx); err == nil {
+			t.Error("未绑定的命名空间前缀应该报错")
+		}
+		if _, err := NewXMLLoader(path, "").Load(ctx); err == nil {
+			t.Error("空路径应该报错")
+		}
+	})
+
+	t.Run("格式错误", func(t *testing.T) {
+		bad := writeXMLFile(t, "<rss><item><title>x</item></rss>")
+		if _, err := NewXMLLoader(bad, "//item").Load(ctx); err == nil {
+			t.Error("格式错误的 XML 应该报错")
+		}
+	})
+}
+
+func TestXMLLoader_Namespace(t *testing.T) {
+	path := writeXMLFile(t, `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:x="urn:other">
+  <entry><title>Atom</title></entry>
+  <x:entry><title>Other</title></x:entry>
+</feed>`)
+
+	docs, err := NewXMLLoader(path, "//atom:entry",
+		WithXMLNamespace("atom", "http://www.w3.org/2005/Atom"),
+	).Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load 失败: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Metadata["title"] != "Atom" {
+		t.Fatalf("docs = %v, want only the Atom entry", docs)
+	}
+
+	// 不带前缀时匹配任意命名空间
+	docs, err = NewXMLLoader(path, "//entry").Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load 失败: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Errorf("文档数量 = %d, want 2", len(docs))
+	}
+}
+
+func TestXMLLoader_Stream(t *testing.T) {
+	path := writeXMLFile(t, testRSS)
+	docCh, errCh := NewXMLLoader(path, "//item").Stream(context.Background())
+
+	var docs []rag.Document
+	for doc := range docCh {
+		docs = append(docs, doc)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("Stream 失败: %v", err)
+	}
+	if len(docs) != 2 || docs[1].Metadata["index"] != 1 {
+		t.Errorf("docs = %v", docs)
+	}
+}
@@ -0,0 +1,175 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
+)
+
+// ============== 多模态索引与检索 ==============
+
+const (
+	// MetadataModality 索引时写入的内容模态元数据键，图片文档的值为 ModalityImage
+	MetadataModality = "modality"
+
+	// ModalityImage 图片模态
+	ModalityImage = "image"
+)
+
+// ErrImageEmbedderRequired 索引或检索图片时未配置 MultimodalEmbedder
+var ErrImageEmbedderRequired = core.NewCategoryError("multimodal embedder is required for image content", core.ErrNotConfigured)
+
+// WithImageEmbedder 设置向量化图片的多模态嵌入器
+//
+// 索引时 Image 非空的文档交给该嵌入器，其余文档仍使用 WithEngineEmbedder 设置的嵌入器。
+// 两者的向量写入同一个存储，必须属于同一向量空间且维度一致，通常是同一个多模态模型；
+// 此时也可以只通过 WithEngineEmbedder 设置，引擎嵌入器实现了 MultimodalEmbedder 时自动用于图片。
+//
+// 示例：
+//
+//	engine := rag.NewEngine(
+//	    rag.WithStore(store),
+//	    rag.WithEngineEmbedder(clip), // clip 实现 rag.MultimodalEmbedder
+//	)
+//	err := engine.IndexDocuments(ctx, []rag.Document{
+//	    {ID: "arch", Content: "系统架构图", Image: png},
+//	})
+//	docs, err := engine.Retrieve(ctx, "architecture diagram")
+func WithImageEmbedder(embedder MultimodalEmbedder) EngineOption {
+	return func(e *Engine) {
+		e.imageEmbedder = embedder
+	}
+}
+
+// multimodalEmbedder 返回用于图片的嵌入器，未配置时返回 nil
+func (e *Engine) multimodalEmbedder() MultimodalEmbedder {
+	if e.imageEmbedder != nil {
+		return e.imageEmbedder
+	}
+	m, _ := e.embedder.(MultimodalEmbedder)
+	return m
+}
+
+// embedDocuments 按内容模态向量化文档，文本和图片分别批量调用对应的嵌入器
+// 返回的向量与 docs 一一对应
+func (e *Engine) embedDocuments(ctx context.Context, docs []Document, stats *IndexStats) ([][]float32, error) {
+	var texts []string
+	var images [][]byte
+	var textIdx, imageIdx []int
+	for i, doc := range docs {
+		if doc.IsImage() {
+			images = append(images, doc.Image)
+			imageIdx = append(imageIdx, i)
+		} else {
+			texts = append(texts, doc.Content)
+			textIdx = append(textIdx, i)
+		}
+	}
+
+	embeddings := make([][]float32, len(docs))
+	if len(texts) > 0 {
+		start := time.Now()
+		vecs, err := e.embedder.Embed(ctx, texts)
+		stats.recordEmbed(time.Since(start))
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed documents: %w", core.ClassifyError(err))
+		}
+		if err := scatterEmbeddings(embeddings, textIdx, vecs); err != nil {
+			return nil, err
+		}
+	}
+	if len(images) > 0 {
+		m := e.multimodalEmbedder()
+		if m == nil {
+			return nil, ErrImageEmbedderRequired
+		}
+		start := time.Now()
+		vecs, err := m.EmbedImages(ctx, images)
+		stats.recordEmbed(time.Since(start))
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed images: %w", core.ClassifyError(err))
+		}
+		if err := scatterEmbeddings(embeddings, imageIdx, vecs); err != nil {
+			return nil, err
+		}
+	}
+	return embeddings, nil
+}
+
+// scatterEmbeddings 将批量向量化的结果按原下标写回
+func scatterEmbeddings(dst [][]float32, idx []int, vecs [][]float32) error {
+	if len(vecs) != len(idx) {
+		return fmt.Errorf("embedder returned %d vectors for %d inputs", len(vecs), len(idx))
+	}
+	for i, j := range idx {
+		dst[j] = vecs[i]
+	}
+	return nil
+}
+
+// modalityMetadata 为图片文档补充模态元数据，不修改原元数据
+func modalityMetadata(doc Document) map[string]any {
+	if !doc.IsImage() {
+		return doc.Metadata
+	}
+	metadata := make(map[string]any, len(doc.Metadata)+1)
+	for k, v := range doc.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataModality] = ModalityImage
+	return metadata
+}
+
+// splitTextDocuments 只分割文本文档，图片文档原样保留
+func splitTextDocuments(ctx context.Context, splitter Splitter, docs []Document) ([]Document, error) {
+	var texts, images []Document
+	for _, doc := range docs {
+		if doc.IsImage() {
+			images = append(images, doc)
+		} else {
+			texts = append(texts, doc)
+		}
+	}
+	if len(images) == 0 {
+		return splitter.Split(ctx, docs)
+	}
+
+	split, err := splitter.Split(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	return append(split, images...), nil
+}
+
+// RetrieveImage 以图片为查询检索相关文档（以图搜图或以图搜文）
+// 与 Retrieve 不同，检索失败时不按 WithRAGFallback 降级
+func (e *Engine) RetrieveImage(ctx context.Context, image []byte, opts ...RetrieveOption) ([]Document, error) {
+	if e.store == nil {
+		return nil, ErrStoreRequired
+	}
+	m := e.multimodalEmbedder()
+	if m == nil {
+		return nil, ErrImageEmbedderRequired
+	}
+	cfg := &RetrieveConfig{
+		TopK:     e.topK,
+		MinScore: e.minScore,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	embedding, err := m.EmbedImages(ctx, [][]byte{image})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query image: %w", core.ClassifyError(err))
+	}
+	if len(embedding) == 0 {
+		return nil, fmt.Errorf("no embedding returned for query image")
+	}
+	if err := CheckEmbeddings(e.store, embedding[0]); err != nil {
+		return nil, err
+	}
+	return e.search(ctx, embedding[0], cfg)
+}
@@ -0,0 +1,87 @@
+package rag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hexagon-codes/hexagon/llm/tokenizer"
+)
+
+// ErrContextTooSmall Token 预算不足以容纳任何文档
+var ErrContextTooSmall = errors.New("rag: token budget too small for any document")
+
+// packSeparator 打包上下文中文档之间的分隔符
+const packSeparator = "\n\n"
+
+// PackContext 在 Token 预算内打包检索文档，用于构建 RAG 提示
+//
+// 按 Score 从高到低贪心选取文档，放不下的文档会被跳过，继续尝试后续（可能更短的）文档。
+// 每个文档格式化为带引用编号和来源的块：
+//
+//	[1] (source: docs/intro.md)
+//	文档内容
+//
+// 引用编号按打包顺序从 1 开始，与返回的文档子集一一对应。
+// 来源依次取 Source、Metadata["source"]、Metadata["title"]、ID。
+// counter 为 nil 时使用 tokenizer.Default()。
+// maxTokens <= 0 时返回错误；有文档但一个都放不下时返回 ErrContextTooSmall。
+func PackContext(docs []Document, maxTokens int, counter tokenizer.Counter) (string, []Document, error) {
+	if maxTokens <= 0 {
+		return "", nil, fmt.Errorf("rag: invalid token budget %d", maxTokens)
+	}
+	if len(docs) == 0 {
+		return "", nil, nil
+	}
+	counter = tokenizer.OrDefault(counter)
+
+	ranked := make([]Document, len(docs))
+	copy(ranked, docs)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+
+	sepTokens := counter.Count(packSeparator)
+	var sb strings.Builder
+	var packed []Document
+	used := 0
+	for _, doc := range ranked {
+		block := formatPackedDoc(len(packed)+1, doc)
+		cost := counter.Count(block)
+		if len(packed) > 0 {
+			cost += sepTokens
+		}
+		if used+cost > maxTokens {
+			continue
+		}
+
+		if len(packed) > 0 {
+			sb.WriteString(packSeparator)
+		}
+		sb.WriteString(block)
+		packed = append(packed, doc)
+		used += cost
+	}
+
+	if len(packed) == 0 {
+		return "", nil, fmt.Errorf("%w: budget %d tokens", ErrContextTooSmall, maxTokens)
+	}
+	return sb.String(), packed, nil
+}
+
+// formatPackedDoc 格式化带引用编号的文档块
+func formatPackedDoc(n int, doc Document) string {
+	return fmt.Sprintf("[%d] (source: %s)\n%s", n, docSource(doc), doc.Content)
+}
+
+// docSource 返回文档的引用来源：Source，其次 metadata 中的 source / title，最后为文档 ID
+func docSource(doc Document) string {
+	if doc.Source != "" {
+		return doc.Source
+	}
+	for _, key := range []string{"source", "title"} {
+		if s, ok := doc.Metadata[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return doc.ID
+}
@@ -10,7 +10,11 @@ package query
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/llm/limiter"
 )
 
 // LLMProvider 是 LLM 提供者接口（简化版）
@@ -18,6 +22,27 @@ type LLMProvider interface {
 	Complete(ctx context.Context, prompt string) (string, error)
 }
 
+// FromProvider 将 llm.Provider 适配为 LLMProvider
+// 提示词作为单条用户消息发送，使用 provider 的默认模型；调用经过 llm/limiter 限流
+func FromProvider(provider llm.Provider) LLMProvider {
+	return providerLLM{provider: provider}
+}
+
+// providerLLM 基于 llm.Provider 的 LLMProvider 实现
+type providerLLM struct {
+	provider llm.Provider
+}
+
+func (p providerLLM) Complete(ctx context.Context, prompt string) (string, error) {
+	resp, err := limiter.Complete(ctx, p.provider, llm.CompletionRequest{
+		Messages: []llm.Message{{Role: llm.RoleUser, Content: prompt}},
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
+
 // ============== QueryExpander ==============
 
 // QueryExpander 查询扩展器
@@ -242,9 +267,10 @@ More specific query:`, query)
 // 这对于复杂问题特别有用，可以从多个侧面检索相关信息。
 type MultiQueryGenerator struct {
 	llm            LLMProvider
-	numQueries     int  // 生成查询数量
-	includeSelf    bool // 是否包含原始查询
-	diversityBoost bool // 是否增强多样性
+	prompt         string // 自定义提示词模板
+	numQueries     int    // 生成查询数量
+	includeSelf    bool   // 是否包含原始查询
+	diversityBoost bool   // 是否增强多样性
 }
 
 // MultiQueryOption MultiQueryGenerator 选项
@@ -257,6 +283,14 @@ func WithNumQueries(num int) MultiQueryOption {
 	}
 }
 
+// WithMultiQueryPrompt 设置提示词模板
+// 模板中 %d 会被替换为生成数量，%s 会被替换为原始查询；LLM 应每行输出一个查询
+func WithMultiQueryPrompt(prompt string) MultiQueryOption {
+	return func(g *MultiQueryGenerator) {
+		g.prompt = prompt
+	}
+}
+
 // WithIncludeSelf 设置是否包含原始查询
 func WithIncludeSelf(include bool) MultiQueryOption {
 	return func(g *MultiQueryGenerator) {
@@ -286,10 +320,14 @@ func NewMultiQueryGenerator(llm LLMProvider, opts ...MultiQueryOption) *MultiQue
 }
 
 // Generate 生成多个查询
+// 解析时移除列表前缀（如 "1. "、"- "），忽略与原始查询或彼此重复（不区分大小写）的行
 func (g *MultiQueryGenerator) Generate(ctx context.Context, query string) ([]string, error) {
 	var prompt string
 
-	if g.diversityBoost {
+	switch {
+	case g.prompt != "":
+		prompt = fmt.Sprintf(g.prompt, g.numQueries, query)
+	case g.diversityBoost:
 		prompt = fmt.Sprintf(`Generate %d diverse search queries that approach the following question from different angles.
 Each query should capture a different aspect or perspective of the original question.
 Provide one query per line.
@@ -297,7 +335,7 @@ Provide one query per line.
 Original question: %s
 
 Diverse queries:`, g.numQueries, query)
-	} else {
+	default:
 		prompt = fmt.Sprintf(`Generate %d alternative search queries that would help answer the following question.
 Provide one query per line.
 
@@ -312,27 +350,24 @@ Alternative queries:`, g.numQueries, query)
 	}
 
 	// 解析响应
-	lines := strings.Split(strings.TrimSpace(resp), "\n")
 	var queries []string
-
 	if g.includeSelf {
 		queries = append(queries, query)
 	}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// 移除编号前缀
-		line = strings.TrimLeft(line, "0123456789.-*• ")
-		if line != "" && line != query {
-			queries = append(queries, line)
-			if len(queries) >= g.numQueries+(func() int {
-				if g.includeSelf {
-					return 1
-				}
-				return 0
-			}()) {
-				break
-			}
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(query)): true}
+	generated := 0
+	for _, line := range strings.Split(resp, "\n") {
+		line = trimListMarker(line)
+		key := strings.ToLower(line)
+		if line == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		queries = append(queries, line)
+		generated++
+		if generated >= g.numQueries {
+			break
 		}
 	}
 
@@ -344,6 +379,16 @@ Alternative queries:`, g.numQueries, query)
 	return queries, nil
 }
 
+// listMarker 匹配行首的列表前缀："1. "、"2) "、"3、"、"- "、"* "、"• "
+// 编号后须跟空白（"、" 除外），避免误删查询本身以数字开头的内容（如 "3D 打印"、"2024 年报"）
+var listMarker = regexp.MustCompile(`^(?:[-*•]\s+|\d{1,3}[.)]\s+|\d{1,3}、\s*)`)
+
+// trimListMarker 去除首尾空白和列表前缀
+func trimListMarker(line string) string {
+	line = strings.TrimSpace(line)
+	return strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+}
+
 // ============== HyDEGenerator ==============
 
 // HyDEGenerator 假设文档嵌入生成器
@@ -144,6 +144,32 @@ func TestMultiQueryGenerator_Generate(t *testing.T) {
 	}
 }
 
+func TestMultiQueryGenerator_ListMarkers(t *testing.T) {
+	mock := &MockLLMProvider{
+		response: "1. 3D 打印的原理\n2) 2024 年报摘要\n3、如何配置超时\n- 3D 打印的原理\n• ML\n",
+	}
+
+	generator := NewMultiQueryGenerator(mock,
+		WithNumQueries(5),
+		WithIncludeSelf(false),
+		WithMultiQueryPrompt("give %d variants of: %s"),
+	)
+	got, err := generator.Generate(context.Background(), "ml")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := []string{"3D 打印的原理", "2024 年报摘要", "如何配置超时"}
+	if len(got) != len(want) {
+		t.Fatalf("Generate() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Generate()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
 func TestHyDEGenerator_Generate(t *testing.T) {
 	mock := &MockLLMProvider{
 		response: "Machine learning is a subset of artificial intelligence that enables systems to learn from data.",
@@ -7,7 +7,9 @@
 //   - Document: 文档数据结构
 //   - Loader: 文档加载器（从文件、URL 等加载）
 //   - Splitter: 文档分割器（将长文档分割成小块）
+//   - Transformer: 文档转换器（在索引前补充元数据、去重等）
 //   - Embedder: 向量生成器（将文本转换为向量）
+//   - MultimodalEmbedder: 多模态向量生成器（将图片与文本映射到同一向量空间）
 //   - Indexer: 索引器（将文档向量化并存储）
 //   - Retriever: 检索器（根据查询检索相关文档）
 //   - Reranker: 重排序器（对检索结果重新排序）
@@ -24,6 +26,9 @@ package rag
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"time"
 )
 
@@ -32,9 +37,14 @@ type Document struct {
 	// ID 文档唯一标识
 	ID string `json:"id"`
 
-	// Content 文档内容
+	// Content 文档内容，图片文档可为图片的说明文字
 	Content string `json:"content"`
 
+	// Image 图片内容（PNG、JPEG 等编码后的原始字节）
+	// 非空时引擎使用 MultimodalEmbedder 向量化图片而不是 Content；
+	// 图片本身不写入向量存储，检索结果通过 ID、Source 或元数据定位原图
+	Image []byte `json:"image,omitempty"`
+
 	// Metadata 文档元数据
 	Metadata map[string]any `json:"metadata,omitempty"`
 
@@ -49,6 +59,26 @@ type Document struct {
 
 	// CreatedAt 创建时间
 	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// ExpiresAt 过期时间，零值表示永不过期
+	// 过期的文档不再出现在检索结果中，另见 WithTTL
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// ContentHash 返回文档内容的 SHA-256 十六进制摘要
+// 相同内容的文档具有相同的哈希，可用于去重；图片文档同时计入图片内容
+func (d Document) ContentHash() string {
+	h := sha256.New()
+	h.Write([]byte(d.Content))
+	if len(d.Image) > 0 {
+		h.Write(d.Image)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IsImage 判断是否为图片文档
+func (d Document) IsImage() bool {
+	return len(d.Image) > 0
 }
 
 // Loader 是文档加载器接口
@@ -87,6 +117,47 @@ type Indexer interface {
 	Count(ctx context.Context) (int, error)
 }
 
+// DedupMode 是索引时的去重模式
+// 文档以内容哈希（SHA-256）判断是否重复
+type DedupMode int
+
+const (
+	// DedupAllow 不去重，总是写入（默认）
+	DedupAllow DedupMode = iota
+
+	// DedupSkip 跳过内容哈希已存在的文档，不重复向量化
+	DedupSkip
+
+	// DedupUpdate 覆盖内容哈希已存在的文档（刷新元数据等）
+	DedupUpdate
+)
+
+// String 返回去重模式名称
+func (m DedupMode) String() string {
+	switch m {
+	case DedupAllow:
+		return "allow"
+	case DedupSkip:
+		return "skip"
+	case DedupUpdate:
+		return "update"
+	default:
+		return fmt.Sprintf("DedupMode(%d)", int(m))
+	}
+}
+
+// IndexResult 是索引操作的统计结果
+type IndexResult struct {
+	// Added 新增的文档数
+	Added int `json:"added"`
+
+	// Skipped 因重复而跳过的文档数
+	Skipped int `json:"skipped"`
+
+	// Updated 覆盖已有文档的数量
+	Updated int `json:"updated"`
+}
+
 // Retriever 是检索器接口
 // 负责根据查询检索相关文档
 type Retriever interface {
@@ -94,6 +165,37 @@ type Retriever interface {
 	Retrieve(ctx context.Context, query string, opts ...RetrieveOption) ([]Document, error)
 }
 
+// BatchRetriever 是支持批量检索的检索器接口
+// 实现方可以一次性向量化所有查询，减少离线评估等场景下的 Embed 调用次数
+type BatchRetriever interface {
+	Retriever
+
+	// RetrieveBatch 批量检索相关文档
+	// 返回结果与 queries 按下标一一对应，opts 对每个查询生效
+	RetrieveBatch(ctx context.Context, queries []string, opts ...RetrieveOption) ([][]Document, error)
+}
+
+// RetrieveBatch 使用任意检索器批量检索
+// 如果 r 实现了 BatchRetriever 则使用其优化实现，否则逐条调用 Retrieve
+func RetrieveBatch(ctx context.Context, r Retriever, queries []string, opts ...RetrieveOption) ([][]Document, error) {
+	if br, ok := r.(BatchRetriever); ok {
+		return br.RetrieveBatch(ctx, queries, opts...)
+	}
+
+	results := make([][]Document, len(queries))
+	for i, query := range queries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		docs, err := r.Retrieve(ctx, query, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("query %d: %w", i, err)
+		}
+		results[i] = docs
+	}
+	return results, nil
+}
+
 // RetrieveConfig 是检索配置
 type RetrieveConfig struct {
 	// TopK 返回的文档数量
@@ -139,6 +241,17 @@ type Embedder interface {
 	Dimension() int
 }
 
+// MultimodalEmbedder 是支持图片的向量生成器接口
+//
+// 文本和图片被映射到同一向量空间（如 CLIP 类模型），因此图片与文本可以写入同一个
+// 向量存储，用文本查询检索图片，或用图片检索相关文本。
+type MultimodalEmbedder interface {
+	Embedder
+
+	// EmbedImages 将图片（编码后的原始字节）转换为向量
+	EmbedImages(ctx context.Context, images [][]byte) ([][]float32, error)
+}
+
 // VectorStore 是向量存储接口
 type VectorStore interface {
 	// Add 添加向量
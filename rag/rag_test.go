@@ -1,8 +1,17 @@
 package rag
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/llm/tokenizer"
+	"github.com/hexagon-codes/hexagon/store/vector"
 )
 
 func TestDocument(t *testing.T) {
@@ -160,3 +169,751 @@ func TestPipeline(t *testing.T) {
 		t.Fatal("expected non-nil pipeline")
 	}
 }
+
+// echoRetriever 返回以查询为内容的单个文档
+type echoRetriever struct {
+	calls int
+}
+
+func (r *echoRetriever) Retrieve(ctx context.Context, query string, opts ...RetrieveOption) ([]Document, error) {
+	r.calls++
+	cfg := &RetrieveConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return []Document{{ID: query, Content: query, Score: float32(cfg.TopK)}}, nil
+}
+
+func TestRetrieveBatch_Fallback(t *testing.T) {
+	r := &echoRetriever{}
+	results, err := RetrieveBatch(context.Background(), r, []string{"a", "b", "c"}, WithTopK(3))
+	if err != nil {
+		t.Fatalf("RetrieveBatch failed: %v", err)
+	}
+	if r.calls != 3 {
+		t.Errorf("expected 3 Retrieve calls, got %d", r.calls)
+	}
+	for i, want := range []string{"a", "b", "c"} {
+		if len(results[i]) != 1 || results[i][0].ID != want {
+			t.Errorf("result %d: expected %s, got %v", i, want, results[i])
+		}
+		if results[i][0].Score != 3 {
+			t.Errorf("result %d: expected options to be applied", i)
+		}
+	}
+}
+
+// lengthEmbedder 以文本长度生成向量
+type lengthEmbedder struct{}
+
+func (e *lengthEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	result := make([][]float32, len(texts))
+	for i, text := range texts {
+		result[i] = []float32{float32(len(text)), 1}
+	}
+	return result, nil
+}
+
+func (e *lengthEmbedder) Dimension() int { return 2 }
+
+func TestEngine_IndexWithTransformers(t *testing.T) {
+	store := vector.NewMemoryStore(2)
+	upper := TransformerFunc(func(ctx context.Context, docs []Document) ([]Document, error) {
+		out := make([]Document, 0, len(docs))
+		for _, doc := range docs {
+			if doc.Content == "" {
+				continue
+			}
+			doc.Content = strings.ToUpper(doc.Content)
+			out = append(out, doc)
+		}
+		return out, nil
+	})
+
+	engine := NewEngine(
+		WithStore(store),
+		WithEngineEmbedder(&lengthEmbedder{}),
+		WithTransformers(upper),
+	)
+
+	ctx := context.Background()
+	err := engine.Index(ctx, []Document{
+		{ID: "a", Content: "hello"},
+		{ID: "b", Content: ""},
+	})
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	count, _ := engine.Count(ctx)
+	if count != 1 {
+		t.Errorf("expected 1 document after transform, got %d", count)
+	}
+	doc, _ := store.Get(ctx, "a")
+	if doc == nil || doc.Content != "HELLO" {
+		t.Errorf("expected transformed content, got %+v", doc)
+	}
+}
+
+func TestEngine_IndexWithDedup(t *testing.T) {
+	ctx := context.Background()
+	docs := []Document{
+		{ID: "a", Content: "alpha"},
+		{Content: "beta"},
+		{Content: "beta"},
+	}
+
+	tests := []struct {
+		mode   DedupMode
+		second IndexResult
+	}{
+		{DedupSkip, IndexResult{Skipped: 3}},
+		{DedupUpdate, IndexResult{Updated: 2, Skipped: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			store := vector.NewMemoryStore(2)
+			engine := NewEngine(
+				WithStore(store),
+				WithEngineEmbedder(&lengthEmbedder{}),
+				WithDedup(tt.mode),
+			)
+
+			first, err := engine.IndexWithResult(ctx, docs)
+			if err != nil {
+				t.Fatalf("IndexWithResult failed: %v", err)
+			}
+			if *first != (IndexResult{Added: 2, Skipped: 1}) {
+				t.Errorf("unexpected first result: %+v", *first)
+			}
+
+			second, err := engine.IndexWithResult(ctx, docs)
+			if err != nil {
+				t.Fatalf("IndexWithResult failed: %v", err)
+			}
+			if *second != tt.second {
+				t.Errorf("expected %+v, got %+v", tt.second, *second)
+			}
+
+			count, _ := engine.Count(ctx)
+			if count != 2 {
+				t.Errorf("expected 2 documents in store, got %d", count)
+			}
+		})
+	}
+}
+
+func TestEngine_IndexWithDedup_ContentHash(t *testing.T) {
+	ctx := context.Background()
+	store := vector.NewMemoryStore(2)
+	engine := NewEngine(
+		WithStore(store),
+		WithEngineEmbedder(&lengthEmbedder{}),
+		WithDedup(DedupSkip),
+	)
+
+	// 重复摄取时切块 ID 每次不同，按内容判断重复
+	if _, err := engine.IndexWithResult(ctx, []Document{{ID: "run1-0", Content: "alpha"}}); err != nil {
+		t.Fatalf("IndexWithResult failed: %v", err)
+	}
+	result, err := engine.IndexWithResult(ctx, []Document{{ID: "run2-0", Content: "alpha"}})
+	if err != nil {
+		t.Fatalf("IndexWithResult failed: %v", err)
+	}
+	if *result != (IndexResult{Skipped: 1}) {
+		t.Errorf("expected duplicate content skipped, got %+v", *result)
+	}
+	if count, _ := engine.Count(ctx); count != 1 {
+		t.Errorf("expected 1 document in store, got %d", count)
+	}
+
+	id := "doc_" + Document{Content: "alpha"}.ContentHash()[:16]
+	doc, _ := store.Get(ctx, id)
+	if doc == nil || doc.Metadata["source_id"] != "run1-0" {
+		t.Errorf("expected original ID in metadata, got %+v", doc)
+	}
+}
+
+func TestEngine_IndexWithoutDedup(t *testing.T) {
+	engine := NewEngine(
+		WithStore(vector.NewMemoryStore(2)),
+		WithEngineEmbedder(&lengthEmbedder{}),
+	)
+	result, err := engine.IndexWithResult(context.Background(), []Document{
+		{ID: "a", Content: "same"},
+		{ID: "b", Content: "same"},
+	})
+	if err != nil {
+		t.Fatalf("IndexWithResult failed: %v", err)
+	}
+	if result.Added != 2 {
+		t.Errorf("expected 2 added, got %+v", *result)
+	}
+}
+
+func TestEngine_IndexWithTTL(t *testing.T) {
+	for _, expiryFilter := range []bool{false, true} {
+		store := vector.NewFilterMemoryStore(2)
+		opts := []EngineOption{WithStore(store), WithEngineEmbedder(&lengthEmbedder{}), WithEngineTopK(10)}
+		if expiryFilter {
+			opts = append(opts, WithExpiryFilter())
+		}
+		engine := NewEngine(opts...)
+
+		ctx := context.Background()
+		err := engine.IndexDocuments(ctx, []Document{
+			{ID: "stale", Content: "old news", ExpiresAt: time.Now().Add(-time.Minute)},
+			{ID: "fresh", Content: "today news"},
+		}, WithTTL(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.Index(ctx, []Document{{ID: "forever", Content: "reference"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		docs, err := engine.Retrieve(ctx, "news")
+		if err != nil {
+			t.Fatalf("expiryFilter=%v: %v", expiryFilter, err)
+		}
+		expires := make(map[string]time.Time)
+		for _, doc := range docs {
+			expires[doc.ID] = doc.ExpiresAt
+		}
+		if _, ok := expires["stale"]; ok || len(docs) != 2 {
+			t.Errorf("expiryFilter=%v: expected stale doc excluded, got %v", expiryFilter, expires)
+		}
+		if until := time.Until(expires["fresh"]); until <= 0 || until > time.Hour {
+			t.Errorf("expiryFilter=%v: expected fresh doc to expire within an hour, got %v", expiryFilter, expires["fresh"])
+		}
+		if !expires["forever"].IsZero() {
+			t.Errorf("expiryFilter=%v: expected forever doc without expiry, got %v", expiryFilter, expires["forever"])
+		}
+		if n, _ := store.Count(ctx); n != 2 {
+			t.Errorf("expiryFilter=%v: expected memory store to reap stale doc, got %d docs", expiryFilter, n)
+		}
+	}
+}
+
+func TestPackContext(t *testing.T) {
+	docs := []Document{
+		{ID: "low", Content: "low relevance", Score: 0.1},
+		{ID: "long", Content: strings.Repeat("word ", 200), Score: 0.8, Source: "long.md"},
+		{ID: "top", Content: "most relevant", Score: 0.9, Metadata: map[string]any{"source": "top.md"}},
+	}
+
+	packed, used, err := PackContext(docs, 30, tokenizer.NewApproximate())
+	if err != nil {
+		t.Fatalf("PackContext failed: %v", err)
+	}
+	if len(used) != 2 || used[0].ID != "top" || used[1].ID != "low" {
+		t.Fatalf("expected top and low (long skipped), got %+v", used)
+	}
+	want := "[1] (source: top.md)\nmost relevant\n\n[2] (source: low)\nlow relevance"
+	if packed != want {
+		t.Errorf("unexpected packed context:\n%s", packed)
+	}
+	if n := tokenizer.NewApproximate().Count(packed); n > 30 {
+		t.Errorf("packed context exceeds budget: %d tokens", n)
+	}
+}
+
+func TestPackContextTooSmall(t *testing.T) {
+	docs := []Document{{ID: "1", Content: strings.Repeat("word ", 100)}}
+	if _, _, err := PackContext(docs, 5, nil); !errors.Is(err, ErrContextTooSmall) {
+		t.Errorf("expected ErrContextTooSmall, got %v", err)
+	}
+	if _, _, err := PackContext(docs, 0, nil); err == nil {
+		t.Error("expected error for non-positive budget")
+	}
+}
+
+// failingEmbedder 对包含 "bad" 的文本返回错误
+type failingEmbedder struct{ lengthEmbedder }
+
+func (e *failingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	for _, text := range texts {
+		if strings.Contains(text, "bad") {
+			return nil, errors.New("malformed document")
+		}
+	}
+	return e.lengthEmbedder.Embed(ctx, texts)
+}
+
+// switchEmbedder down 为 true 时返回错误
+type switchEmbedder struct {
+	lengthEmbedder
+	down atomic.Bool
+}
+
+func (e *switchEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if e.down.Load() {
+		return nil, errors.New("embedding service unavailable")
+	}
+	return e.lengthEmbedder.Embed(ctx, texts)
+}
+
+// errorRecorder 记录错误事件的运行钩子
+type errorRecorder struct {
+	events []*hooks.ErrorEvent
+}
+
+func (h *errorRecorder) Name() string                                        { return "error-recorder" }
+func (h *errorRecorder) Enabled() bool                                       { return true }
+func (h *errorRecorder) OnStart(context.Context, *hooks.RunStartEvent) error { return nil }
+func (h *errorRecorder) OnEnd(context.Context, *hooks.RunEndEvent) error     { return nil }
+func (h *errorRecorder) OnError(_ context.Context, e *hooks.ErrorEvent) error {
+	h.events = append(h.events, e)
+	return nil
+}
+
+func TestEngine_RetrieveFallback(t *testing.T) {
+	recorder := &errorRecorder{}
+	manager := hooks.NewManager()
+	manager.RegisterRunHook(recorder)
+	ctx := hooks.ContextWithManager(context.Background(), manager)
+
+	for _, tt := range []struct {
+		mode FallbackMode
+		want int
+	}{
+		{FallbackEmpty, 0},
+		{FallbackLastResult, 1},
+	} {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			embedder := &switchEmbedder{}
+			engine := NewEngine(
+				WithStore(vector.NewMemoryStore(2)),
+				WithEngineEmbedder(embedder),
+				WithRAGFallback(tt.mode),
+			)
+			if err := engine.Index(ctx, []Document{{ID: "a", Content: "hello"}}); err != nil {
+				t.Fatal(err)
+			}
+			if docs, err := engine.Retrieve(ctx, "hello"); err != nil || len(docs) != 1 {
+				t.Fatalf("expected 1 result, got %d %v", len(docs), err)
+			}
+
+			embedder.down.Store(true)
+			docs, err := engine.Retrieve(ctx, "hello")
+			if err != nil {
+				t.Fatalf("expected degraded result, got error %v", err)
+			}
+			if len(docs) != tt.want {
+				t.Errorf("expected %d degraded results, got %d", tt.want, len(docs))
+			}
+			// 未成功过的查询没有可用的上次结果
+			if docs, err := engine.Retrieve(ctx, "other"); err != nil || len(docs) != 0 {
+				t.Errorf("expected empty result for new query, got %d %v", len(docs), err)
+			}
+		})
+	}
+
+	if len(recorder.events) != 4 {
+		t.Fatalf("expected 4 error events, got %d", len(recorder.events))
+	}
+	if e := recorder.events[0]; e.Phase != "rag_retrieve" || e.Metadata["fallback"] != "empty" || e.Error == nil {
+		t.Errorf("unexpected error event: %+v", e)
+	}
+}
+
+func TestEngine_RetrieveFallback_Propagates(t *testing.T) {
+	embedder := &switchEmbedder{}
+	embedder.down.Store(true)
+
+	// 默认不降级
+	engine := NewEngine(WithStore(vector.NewMemoryStore(2)), WithEngineEmbedder(embedder))
+	if _, err := engine.Retrieve(context.Background(), "q"); err == nil {
+		t.Error("expected error without fallback")
+	}
+
+	// 调用方取消时不降级
+	engine = NewEngine(WithStore(vector.NewMemoryStore(2)), WithEngineEmbedder(embedder), WithRAGFallback(FallbackEmpty))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := engine.Retrieve(ctx, "q"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// 配置错误不降级
+	engine = NewEngine(WithRAGFallback(FallbackEmpty))
+	if _, err := engine.Retrieve(context.Background(), "q"); err == nil {
+		t.Error("expected error when store is missing")
+	}
+}
+
+// streamDocs 将文档写入已关闭的 channel
+func streamDocs(docs ...Document) <-chan Document {
+	ch := make(chan Document, len(docs))
+	for _, doc := range docs {
+		ch <- doc
+	}
+	close(ch)
+	return ch
+}
+
+// drainProgress 读取全部进度事件，返回逐文档事件和最终汇总
+func drainProgress(t *testing.T, ch <-chan IndexProgress) ([]IndexProgress, *IndexSummary) {
+	t.Helper()
+	var events []IndexProgress
+	var summary *IndexSummary
+	for p := range ch {
+		if p.Summary != nil {
+			summary = p.Summary
+			continue
+		}
+		events = append(events, p)
+	}
+	if summary == nil {
+		t.Fatal("expected final summary event")
+	}
+	return events, summary
+}
+
+func TestEngine_IndexStream(t *testing.T) {
+	ctx := context.Background()
+	store := vector.NewMemoryStore(2)
+	engine := NewEngine(WithStore(store), WithEngineEmbedder(&failingEmbedder{}))
+
+	docs := []Document{
+		{ID: "a", Content: "alpha"},
+		{ID: "b", Content: "bad pdf"},
+		{ID: "c", Content: "gamma"},
+		{Content: "delta"},
+	}
+	events, summary := drainProgress(t, engine.IndexStream(ctx, streamDocs(docs...), WithIndexBatchSize(3)))
+
+	if len(events) != 4 || events[3].Processed != 4 {
+		t.Fatalf("expected 4 progress events, got %+v", events)
+	}
+	if events[1].ID != "b" || events[1].Err == nil {
+		t.Errorf("expected failure for b, got %+v", events[1])
+	}
+	if summary.Err != nil || len(summary.Failed) != 1 || summary.Failed[0].ID != "b" {
+		t.Errorf("expected only b to fail, got %+v", summary)
+	}
+	if len(summary.Succeeded) != 3 || summary.Added != 3 {
+		t.Errorf("expected 3 succeeded, got %+v", summary)
+	}
+	if !strings.HasPrefix(summary.Succeeded[2], "doc_") {
+		t.Errorf("expected generated ID for doc without ID, got %q", summary.Succeeded[2])
+	}
+	if count, _ := engine.Count(ctx); count != 3 {
+		t.Errorf("expected 3 documents in store, got %d", count)
+	}
+	// 失败的批次 + 逐个重试 3 次 + 最后一批
+	if st := summary.Stats; st.Documents != 4 || st.Chunks != 3 || st.EmbedBatches != 5 || st.Duration <= 0 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+
+	// 恢复：已成功的文档被跳过，只重试失败的
+	docs[1].Content = "fixed pdf"
+	_, resumed := drainProgress(t, engine.IndexStream(ctx, streamDocs(docs...), WithIndexResume(summary)))
+	if resumed.Resumed != 3 || len(resumed.Succeeded) != 1 || resumed.Succeeded[0] != "b" {
+		t.Errorf("expected resume to only index b, got %+v", resumed)
+	}
+}
+
+func TestEngine_IndexStats(t *testing.T) {
+	ctx := context.Background()
+	dropEmpty := TransformerFunc(func(ctx context.Context, docs []Document) ([]Document, error) {
+		out := docs[:0:0]
+		for _, doc := range docs {
+			if doc.Content != "" {
+				out = append(out, doc)
+			}
+		}
+		return out, nil
+	})
+	engine := NewEngine(
+		WithStore(vector.NewMemoryStore(2)),
+		WithEngineEmbedder(&lengthEmbedder{}),
+		WithTransformers(dropEmpty),
+	)
+
+	var stats IndexStats
+	for range 2 {
+		err := engine.IndexDocuments(ctx, []Document{{Content: "a"}, {Content: ""}, {Content: "ccc"}}, WithIndexStats(&stats))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats.Documents != 6 || stats.Chunks != 4 || stats.EmbedBatches != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.Duration < stats.EmbedDuration+stats.StoreDuration || stats.MaxEmbedLatency > stats.EmbedDuration {
+		t.Errorf("inconsistent durations: %+v", stats)
+	}
+	if stats.DocsPerSecond() <= 0 || stats.AvgEmbedLatency() > stats.MaxEmbedLatency {
+		t.Errorf("unexpected derived metrics: %s", stats)
+	}
+}
+
+// slowEmbedder 每次调用有固定延迟，模拟远程嵌入服务
+type slowEmbedder struct{ lengthEmbedder }
+
+func (e *slowEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	time.Sleep(200 * time.Microsecond)
+	return e.lengthEmbedder.Embed(ctx, texts)
+}
+
+// BenchmarkEngine_IndexStream 比较不同批大小的索引吞吐
+func BenchmarkEngine_IndexStream(b *testing.B) {
+	docs := make([]Document, 256)
+	for i := range docs {
+		docs[i] = Document{ID: fmt.Sprintf("doc-%d", i), Content: strings.Repeat("x", i%64+1)}
+	}
+
+	for _, batchSize := range []int{1, 8, 32, 128} {
+		b.Run(fmt.Sprintf("batch=%d", batchSize), func(b *testing.B) {
+			var stats IndexStats
+			for b.Loop() {
+				engine := NewEngine(WithStore(vector.NewMemoryStore(2)), WithEngineEmbedder(&slowEmbedder{}))
+				for p := range engine.IndexStream(context.Background(), streamDocs(docs...), WithIndexBatchSize(batchSize)) {
+					if p.Summary != nil {
+						stats = p.Summary.Stats
+					}
+				}
+			}
+			b.ReportMetric(stats.DocsPerSecond(), "docs/s")
+			b.ReportMetric(float64(stats.AvgEmbedLatency().Microseconds()), "µs/embed")
+		})
+	}
+}
+
+func TestEngine_IndexStreamStopOnError(t *testing.T) {
+	engine := NewEngine(WithStore(vector.NewMemoryStore(2)), WithEngineEmbedder(&failingEmbedder{}))
+
+	docs := streamDocs(
+		Document{ID: "a", Content: "alpha"},
+		Document{ID: "b", Content: "bad"},
+		Document{ID: "c", Content: "gamma"},
+	)
+	events, summary := drainProgress(t, engine.IndexStream(context.Background(), docs, WithIndexBatchSize(1), WithStopOnError()))
+
+	if len(events) != 2 {
+		t.Fatalf("expected to stop after b, got %+v", events)
+	}
+	if summary.Err == nil || !strings.Contains(summary.Err.Error(), "b") {
+		t.Errorf("expected summary error naming b, got %v", summary.Err)
+	}
+}
+
+func TestEngine_IndexStreamNoStore(t *testing.T) {
+	_, summary := drainProgress(t, NewEngine().IndexStream(context.Background(), streamDocs()))
+	if summary.Err == nil {
+		t.Error("expected error without store")
+	}
+}
+
+// configRetriever 记录检索配置并返回固定文档
+type configRetriever struct {
+	query string
+	cfg   RetrieveConfig
+	docs  []Document
+	err   error
+}
+
+func (r *configRetriever) Retrieve(ctx context.Context, query string, opts ...RetrieveOption) ([]Document, error) {
+	r.query = query
+	r.cfg = RetrieveConfig{}
+	for _, opt := range opts {
+		opt(&r.cfg)
+	}
+	return r.docs, r.err
+}
+
+func TestSearchTool_FormatsCitations(t *testing.T) {
+	r := &configRetriever{docs: []Document{
+		{ID: "d1", Content: "Go was released in 2009.", Source: "history.md", Score: 0.91},
+		{ID: "d2", Content: "Goroutines are cheap.", Metadata: map[string]any{"title": "Concurrency"}, Score: 0.5},
+		{ID: "d3", Content: "No source."},
+	}}
+	st := NewSearchTool(r)
+
+	if st.Name() != "knowledge_search" || st.Schema() == nil {
+		t.Fatalf("unexpected tool definition: %s", st.Name())
+	}
+	result, err := st.Execute(context.Background(), map[string]any{"query": "go history"})
+	if err != nil || !result.Success {
+		t.Fatalf("execute failed: %v %+v", err, result)
+	}
+	out := result.Output.(string)
+	for _, want := range []string{
+		"[1] source: history.md (score: 0.91)\nGo was released in 2009.",
+		"[2] source: Concurrency",
+		"[3] source: d3",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if r.query != "go history" || r.cfg.TopK != 5 {
+		t.Errorf("unexpected retrieve call: %q %+v", r.query, r.cfg)
+	}
+}
+
+func TestSearchTool_TopKAndFilter(t *testing.T) {
+	r := &configRetriever{}
+	st := NewSearchTool(r,
+		WithSearchToolTopK(3, 10),
+		WithSearchToolFilter(map[string]any{"tenant": "acme"}),
+	)
+
+	result, _ := st.Execute(context.Background(), map[string]any{
+		"query":  "q",
+		"top_k":  float64(50),
+		"filter": map[string]any{"lang": "en", "tenant": "other"},
+	})
+	if !result.Success || result.Output != "No relevant documents found." {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if r.cfg.TopK != 10 {
+		t.Errorf("expected top_k clamped to 10, got %d", r.cfg.TopK)
+	}
+	if r.cfg.Filter["lang"] != "en" || r.cfg.Filter["tenant"] != "acme" {
+		t.Errorf("expected merged filter with fixed tenant, got %v", r.cfg.Filter)
+	}
+
+	_, _ = st.Execute(context.Background(), map[string]any{"query": "q"})
+	if r.cfg.TopK != 3 || r.cfg.Filter["tenant"] != "acme" || len(r.cfg.Filter) != 1 {
+		t.Errorf("expected defaults, got %+v", r.cfg)
+	}
+}
+
+func TestSearchTool_Errors(t *testing.T) {
+	st := NewSearchTool(&configRetriever{err: errors.New("store down")})
+
+	result, err := st.Execute(context.Background(), map[string]any{"query": "  "})
+	if err != nil || result.Success {
+		t.Errorf("expected error result for empty query, got %+v", result)
+	}
+	result, err = st.Execute(context.Background(), map[string]any{"query": "q"})
+	if err != nil || result.Success || !strings.Contains(result.Error, "store down") {
+		t.Errorf("expected retrieval error result, got %+v", result)
+	}
+}
+
+func TestEngine_DimensionCheck(t *testing.T) {
+	ctx := context.Background()
+
+	engine := NewEngine(WithStore(vector.NewMemoryStore(2)), WithEngineEmbedder(&lengthEmbedder{}))
+	if err := engine.Validate(ctx); err != nil {
+		t.Fatalf("expected matching dimensions to validate, got %v", err)
+	}
+
+	engine = NewEngine(WithStore(vector.NewMemoryStore(384)), WithEngineEmbedder(&lengthEmbedder{}))
+	err := engine.Validate(ctx)
+	if !errors.Is(err, ErrDimensionMismatch) || !strings.Contains(err.Error(), "embedder dim 2 != store dim 384") {
+		t.Fatalf("expected dimension mismatch from Validate, got %v", err)
+	}
+
+	// 未调用 Validate 时，首次索引和检索在写入存储前报出同样的错误
+	if err := engine.IndexDocuments(ctx, []Document{{ID: "a", Content: "hello"}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("expected dimension mismatch from IndexDocuments, got %v", err)
+	}
+	if _, err := engine.Retrieve(ctx, "hello"); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("expected dimension mismatch from Retrieve, got %v", err)
+	}
+}
+
+// misdeclaredEmbedder 声明的维度与实际输出不一致
+type misdeclaredEmbedder struct{ lengthEmbedder }
+
+func (e *misdeclaredEmbedder) Dimension() int { return 1536 }
+
+func TestCheckDimension_DeclaredMismatch(t *testing.T) {
+	err := CheckDimension(context.Background(), &misdeclaredEmbedder{}, nil)
+	if !errors.Is(err, ErrDimensionMismatch) || !strings.Contains(err.Error(), "declares dim 1536 but produced 2") {
+		t.Fatalf("expected declared dimension mismatch, got %v", err)
+	}
+}
+
+// imageEmbedder 将文本和图片映射到同一 2 维空间：文本为 [len, 1]，图片为 [len, 0]
+type imageEmbedder struct {
+	lengthEmbedder
+	imageCalls int
+}
+
+func (e *imageEmbedder) EmbedImages(ctx context.Context, images [][]byte) ([][]float32, error) {
+	e.imageCalls++
+	result := make([][]float32, len(images))
+	for i, img := range images {
+		result[i] = []float32{float32(len(img)), 0}
+	}
+	return result, nil
+}
+
+func TestEngine_IndexImages(t *testing.T) {
+	ctx := context.Background()
+	store := vector.NewMemoryStore(2)
+	embedder := &imageEmbedder{}
+	engine := NewEngine(WithStore(store), WithEngineEmbedder(&lengthEmbedder{}), WithImageEmbedder(embedder))
+
+	docs := []Document{
+		{ID: "text", Content: "hello"},
+		{ID: "diagram", Content: "architecture", Image: []byte{1, 2, 3}},
+	}
+	if err := engine.IndexDocuments(ctx, docs); err != nil {
+		t.Fatalf("IndexDocuments failed: %v", err)
+	}
+	if embedder.imageCalls != 1 {
+		t.Errorf("expected images embedded in one batch, got %d calls", embedder.imageCalls)
+	}
+
+	stored, err := store.Get(ctx, "diagram")
+	if err != nil || stored == nil {
+		t.Fatalf("expected image document stored, got %v", err)
+	}
+	if stored.Embedding[0] != 3 || stored.Embedding[1] != 0 {
+		t.Errorf("expected image embedding [3 0], got %v", stored.Embedding)
+	}
+	if stored.Metadata[MetadataModality] != ModalityImage {
+		t.Errorf("expected modality metadata, got %v", stored.Metadata)
+	}
+	if text, _ := store.Get(ctx, "text"); text.Metadata[MetadataModality] != nil {
+		t.Errorf("text document should not carry modality, got %v", text.Metadata)
+	}
+
+	results, err := engine.RetrieveImage(ctx, []byte{9, 9, 9}, WithTopK(1))
+	if err != nil {
+		t.Fatalf("RetrieveImage failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "diagram" {
+		t.Errorf("expected image query to match diagram, got %+v", results)
+	}
+}
+
+func TestEngine_IndexImages_NoMultimodalEmbedder(t *testing.T) {
+	ctx := context.Background()
+	engine := NewEngine(WithStore(vector.NewMemoryStore(2)), WithEngineEmbedder(&lengthEmbedder{}))
+
+	err := engine.IndexDocuments(ctx, []Document{{ID: "img", Image: []byte{1}}})
+	if !errors.Is(err, ErrImageEmbedderRequired) {
+		t.Fatalf("expected ErrImageEmbedderRequired, got %v", err)
+	}
+	if _, err := engine.RetrieveImage(ctx, []byte{1}); !errors.Is(err, ErrImageEmbedderRequired) {
+		t.Fatalf("expected ErrImageEmbedderRequired from RetrieveImage, got %v", err)
+	}
+
+	// 引擎嵌入器本身支持图片时无需单独配置
+	engine = NewEngine(WithStore(vector.NewMemoryStore(2)), WithEngineEmbedder(&imageEmbedder{}))
+	if err := engine.IndexDocuments(ctx, []Document{{ID: "img", Image: []byte{1}}}); err != nil {
+		t.Fatalf("expected multimodal engine embedder to index images, got %v", err)
+	}
+}
+
+func TestDocument_ContentHashImage(t *testing.T) {
+	a := Document{Content: "same", Image: []byte{1}}
+	b := Document{Content: "same", Image: []byte{2}}
+	if a.ContentHash() == b.ContentHash() {
+		t.Error("expected different images to hash differently")
+	}
+	if (Document{Content: "same"}).ContentHash() != (Document{Content: "same", Image: []byte{}}).ContentHash() {
+		t.Error("empty image should not change the hash")
+	}
+}
@@ -0,0 +1,322 @@
+package retriever
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// TokenizeFunc 分词函数，将文本切分为检索用的词项
+type TokenizeFunc func(text string) []string
+
+// BM25Retriever 基于倒排索引的 BM25 关键词检索器
+// 在进程内索引 Document.Content，提供向量检索缺少的精确词项匹配，
+// 可与 VectorRetriever 一起放入 HybridRetriever / MultiRetriever 组合使用
+//
+// 评分公式（Okapi BM25，IDF 采用 Lucene 的非负形式）：
+//
+//	score(D, Q) = Σ IDF(q) · tf(q, D) · (k1 + 1) / (tf(q, D) + k1 · (1 - b + b · |D| / avgdl))
+//	IDF(q)      = ln(1 + (N - df(q) + 0.5) / (df(q) + 0.5))
+//
+// 默认分词按 Unicode 字母和数字切分并转为小写；中文等 CJK 文本
+// 建议使用 WithBM25Tokenizer(splitter.SearchTokenize) 做 bigram 切分。
+// 所有方法并发安全。
+//
+// 使用示例：
+//
+//	bm25 := NewBM25Retriever(WithBM25Stopwords("the", "a", "of"))
+//	bm25.Index(ctx, docs)
+//	hybrid := NewHybridRetriever(vectorRetriever, bm25)
+type BM25Retriever struct {
+	mu sync.RWMutex
+
+	// docs 已索引文档，按 ID 存储
+	docs map[string]*bm25Doc
+
+	// postings 倒排索引：词项 → 文档 ID → 词频
+	postings map[string]map[string]int
+
+	// totalLen 所有文档的词项总数，用于计算平均文档长度
+	totalLen int
+
+	// tokenize 分词函数
+	tokenize TokenizeFunc
+
+	// stopwords 停用词（小写）
+	stopwords map[string]struct{}
+
+	// k1 词频饱和参数
+	k1 float64
+
+	// b 文档长度归一化参数
+	b float64
+
+	// topK 返回文档数量
+	topK int
+
+	// minScore 最小 BM25 分数
+	minScore float32
+}
+
+// bm25Doc 已索引的文档
+type bm25Doc struct {
+	doc    rag.Document
+	terms  map[string]int
+	length int
+}
+
+// BM25Option BM25Retriever 选项
+type BM25Option func(*BM25Retriever)
+
+// WithBM25Tokenizer 设置分词函数
+// 分词结果会在去除停用词前统一转为小写
+func WithBM25Tokenizer(fn TokenizeFunc) BM25Option {
+	return func(r *BM25Retriever) {
+		if fn != nil {
+			r.tokenize = fn
+		}
+	}
+}
+
+// WithBM25Stopwords 设置停用词，索引和查询时都会过滤
+func WithBM25Stopwords(words ...string) BM25Option {
+	return func(r *BM25Retriever) {
+		for _, w := range words {
+			r.stopwords[strings.ToLower(w)] = struct{}{}
+		}
+	}
+}
+
+// WithBM25Params 设置 BM25 参数
+// 默认值: k1 = 1.2, b = 0.75
+func WithBM25Params(k1, b float64) BM25Option {
+	return func(r *BM25Retriever) {
+		if k1 >= 0 {
+			r.k1 = k1
+		}
+		if b >= 0 && b <= 1 {
+			r.b = b
+		}
+	}
+}
+
+// WithBM25TopK 设置返回文档数量
+// 默认值: 5
+func WithBM25TopK(k int) BM25Option {
+	return func(r *BM25Retriever) {
+		if k > 0 {
+			r.topK = k
+		}
+	}
+}
+
+// WithBM25MinScore 设置最小 BM25 分数
+func WithBM25MinScore(score float32) BM25Option {
+	return func(r *BM25Retriever) {
+		r.minScore = score
+	}
+}
+
+// NewBM25Retriever 创建 BM25 检索器
+func NewBM25Retriever(opts ...BM25Option) *BM25Retriever {
+	r := &BM25Retriever{
+		docs:      make(map[string]*bm25Doc),
+		postings:  make(map[string]map[string]int),
+		tokenize:  wordTokenize,
+		stopwords: make(map[string]struct{}),
+		k1:        1.2,
+		b:         0.75,
+		topK:      5,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Index 索引文档
+// 已存在相同 ID 的文档会被替换；ID 为空时根据内容生成
+func (r *BM25Retriever) Index(ctx context.Context, docs []rag.Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, doc := range docs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if doc.ID == "" {
+			doc.ID = generateDocID(doc.Content)
+		}
+		r.remove(doc.ID)
+
+		terms := r.terms(doc.Content)
+		tf := make(map[string]int, len(terms))
+		for _, term := range terms {
+			tf[term]++
+		}
+		for term, n := range tf {
+			p, ok := r.postings[term]
+			if !ok {
+				p = make(map[string]int)
+				r.postings[term] = p
+			}
+			p[doc.ID] = n
+		}
+		r.docs[doc.ID] = &bm25Doc{doc: doc, terms: tf, length: len(terms)}
+		r.totalLen += len(terms)
+	}
+	return nil
+}
+
+// Delete 按 ID 删除文档，不存在的 ID 会被忽略
+func (r *BM25Retriever) Delete(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		r.remove(id)
+	}
+	return nil
+}
+
+// Clear 清空索引
+func (r *BM25Retriever) Clear(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.docs = make(map[string]*bm25Doc)
+	r.postings = make(map[string]map[string]int)
+	r.totalLen = 0
+	return nil
+}
+
+// Count 返回已索引的文档数量
+func (r *BM25Retriever) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.docs)
+}
+
+// Retrieve 检索相关文档
+// 只返回至少命中一个查询词项的文档，结果按 BM25 分数降序排列
+func (r *BM25Retriever) Retrieve(ctx context.Context, query string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
+	cfg := &rag.RetrieveConfig{
+		TopK:     r.topK,
+		MinScore: r.minScore,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.docs) == 0 {
+		return nil, nil
+	}
+
+	n := float64(len(r.docs))
+	avgDl := float64(r.totalLen) / n
+	if avgDl == 0 {
+		avgDl = 1
+	}
+
+	// 查询中重复的词项只计一次
+	seen := make(map[string]bool)
+	scores := make(map[string]float64)
+	for _, term := range r.terms(query) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		p := r.postings[term]
+		if len(p) == 0 {
+			continue
+		}
+		df := float64(len(p))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range p {
+			d := r.docs[id]
+			freq := float64(tf)
+			norm := r.k1 * (1 - r.b + r.b*float64(d.length)/avgDl)
+			scores[id] += idf * freq * (r.k1 + 1) / (freq + norm)
+		}
+	}
+
+	results := make([]rag.Document, 0, len(scores))
+	for id, score := range scores {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		d := r.docs[id]
+		if float32(score) < cfg.MinScore {
+			continue
+		}
+		if cfg.Filter != nil && !matchFilter(d.doc.Metadata, cfg.Filter) {
+			continue
+		}
+		doc := d.doc
+		doc.Score = float32(score)
+		results = append(results, doc)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if cfg.TopK > 0 && len(results) > cfg.TopK {
+		results = results[:cfg.TopK]
+	}
+	return results, nil
+}
+
+// remove 从索引中移除文档，调用方需持有写锁
+func (r *BM25Retriever) remove(id string) {
+	d, ok := r.docs[id]
+	if !ok {
+		return
+	}
+	for term := range d.terms {
+		p := r.postings[term]
+		delete(p, id)
+		if len(p) == 0 {
+			delete(r.postings, term)
+		}
+	}
+	r.totalLen -= d.length
+	delete(r.docs, id)
+}
+
+// terms 分词、转小写并过滤停用词
+func (r *BM25Retriever) terms(text string) []string {
+	tokens := r.tokenize(text)
+	terms := tokens[:0:0]
+	for _, t := range tokens {
+		t = strings.ToLower(t)
+		if t == "" {
+			continue
+		}
+		if _, stop := r.stopwords[t]; stop {
+			continue
+		}
+		terms = append(terms, t)
+	}
+	return terms
+}
+
+// wordTokenize 默认分词：按 Unicode 字母和数字切分
+func wordTokenize(text string) []string {
+	return strings.FieldsFunc(text, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
+	})
+}
+
+var _ rag.Retriever = (*BM25Retriever)(nil)
@@ -0,0 +1,135 @@
+package retriever
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/rag/splitter"
+)
+
+func bm25Docs() []rag.Document {
+	return []rag.Document{
+		{ID: "go", Content: "Go is a programming language with goroutines", Metadata: map[string]any{"lang": "en"}},
+		{ID: "rust", Content: "Rust is a systems programming language", Metadata: map[string]any{"lang": "en"}},
+		{ID: "cook", Content: "The art of cooking pasta", Metadata: map[string]any{"lang": "en"}},
+		{ID: "zh", Content: "Go 语言的并发模型基于 goroutine", Metadata: map[string]any{"lang": "zh"}},
+	}
+}
+
+func TestBM25Retriever_Basic(t *testing.T) {
+	ctx := context.Background()
+	r := NewBM25Retriever()
+	if err := r.Index(ctx, bm25Docs()); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	docs, err := r.Retrieve(ctx, "Goroutines!")
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].ID != "go" {
+		t.Fatalf("expected exact-term match on go, got %+v", docs)
+	}
+
+	// 罕见词 "systems" 的 IDF 高于常见词 "programming"
+	docs, _ = r.Retrieve(ctx, "programming systems")
+	if len(docs) != 2 || docs[0].ID != "rust" {
+		t.Fatalf("expected rust ranked first, got %+v", docs)
+	}
+	if docs[0].Score <= docs[1].Score {
+		t.Errorf("expected descending scores, got %v", docs)
+	}
+}
+
+func TestBM25Retriever_Score(t *testing.T) {
+	ctx := context.Background()
+	r := NewBM25Retriever()
+	r.Index(ctx, []rag.Document{
+		{ID: "a", Content: "apple apple banana"},
+		{ID: "b", Content: "banana cherry"},
+	})
+
+	docs, _ := r.Retrieve(ctx, "apple")
+	// N=2, df=1, |D|=3, avgdl=2.5, tf=2
+	idf := math.Log(1 + (2-1+0.5)/(1+0.5))
+	want := idf * 2 * 2.2 / (2 + 1.2*(1-0.75+0.75*3/2.5))
+	if len(docs) != 1 || math.Abs(float64(docs[0].Score)-want) > 1e-5 {
+		t.Fatalf("expected score %v, got %+v", want, docs)
+	}
+}
+
+func TestBM25Retriever_Config(t *testing.T) {
+	ctx := context.Background()
+	r := NewBM25Retriever(WithBM25TopK(1))
+	r.Index(ctx, bm25Docs())
+
+	docs, _ := r.Retrieve(ctx, "language")
+	if len(docs) != 1 {
+		t.Errorf("expected TopK 1, got %d", len(docs))
+	}
+
+	docs, _ = r.Retrieve(ctx, "language", rag.WithTopK(10))
+	if len(docs) != 2 {
+		t.Errorf("expected 2 docs with TopK override, got %d", len(docs))
+	}
+
+	docs, _ = r.Retrieve(ctx, "language", rag.WithTopK(10), rag.WithMinScore(100))
+	if len(docs) != 0 {
+		t.Errorf("expected MinScore to drop all docs, got %d", len(docs))
+	}
+
+	docs, _ = r.Retrieve(ctx, "go", rag.WithTopK(10), rag.WithFilter(map[string]any{"lang": "zh"}))
+	if len(docs) != 1 || docs[0].ID != "zh" {
+		t.Errorf("expected filter to keep zh only, got %+v", docs)
+	}
+}
+
+func TestBM25Retriever_IndexDelete(t *testing.T) {
+	ctx := context.Background()
+	r := NewBM25Retriever()
+	r.Index(ctx, bm25Docs())
+
+	// 重新索引相同 ID 替换旧内容
+	r.Index(ctx, []rag.Document{{ID: "cook", Content: "cooking rice"}})
+	if r.Count() != 4 {
+		t.Fatalf("expected 4 docs, got %d", r.Count())
+	}
+	if docs, _ := r.Retrieve(ctx, "pasta"); len(docs) != 0 {
+		t.Errorf("expected replaced content to be unindexed, got %+v", docs)
+	}
+	if docs, _ := r.Retrieve(ctx, "rice"); len(docs) != 1 {
+		t.Errorf("expected new content indexed, got %+v", docs)
+	}
+
+	r.Delete(ctx, []string{"go", "missing"})
+	if docs, _ := r.Retrieve(ctx, "goroutines"); len(docs) != 0 {
+		t.Errorf("expected deleted doc gone, got %+v", docs)
+	}
+	if r.Count() != 3 {
+		t.Errorf("expected 3 docs, got %d", r.Count())
+	}
+
+	r.Clear(ctx)
+	if docs, _ := r.Retrieve(ctx, "rust"); r.Count() != 0 || len(docs) != 0 {
+		t.Errorf("expected empty index after Clear")
+	}
+}
+
+func TestBM25Retriever_Tokenizer(t *testing.T) {
+	ctx := context.Background()
+
+	r := NewBM25Retriever(WithBM25Stopwords("The", "of"))
+	r.Index(ctx, bm25Docs())
+	if docs, _ := r.Retrieve(ctx, "the of"); len(docs) != 0 {
+		t.Errorf("expected stopwords-only query to match nothing, got %+v", docs)
+	}
+
+	r = NewBM25Retriever(WithBM25Tokenizer(splitter.SearchTokenize))
+	r.Index(ctx, bm25Docs())
+	docs, _ := r.Retrieve(ctx, "并发")
+	if len(docs) != 1 || docs[0].ID != "zh" {
+		t.Errorf("expected CJK bigram match, got %+v", docs)
+	}
+}
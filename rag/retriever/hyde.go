@@ -81,6 +81,9 @@ type HyDERetriever struct {
 
 	// temperature LLM 采样温度
 	temperature float64
+
+	// includeQuery 是否将原始查询与假设文档一起向量化参与检索
+	includeQuery bool
 }
 
 // HyDEOption HyDE 检索器选项
@@ -134,6 +137,15 @@ func WithHyDETemperature(temp float64) HyDEOption {
 	}
 }
 
+// WithHyDEIncludeQuery 设置是否将原始查询作为一个额外的"假设文档"参与检索
+// 对应 HyDE 论文中将查询向量与假设文档向量一起取平均的做法，
+// 可减轻 LLM 生成内容偏离问题时带来的漂移
+func WithHyDEIncludeQuery(include bool) HyDEOption {
+	return func(r *HyDERetriever) {
+		r.includeQuery = include
+	}
+}
+
 // defaultHyDEPrompt 默认假设文档生成提示词
 const defaultHyDEPrompt = `请根据以下问题，写出一段可能包含答案的文档内容。
 不要解释，直接输出文档内容。
@@ -182,6 +194,9 @@ func (r *HyDERetriever) Retrieve(ctx context.Context, query string, opts ...rag.
 		// 降级：LLM 调用失败时，直接用原始查询向量检索
 		return r.fallbackRetrieve(ctx, query, cfg)
 	}
+	if r.includeQuery {
+		hypotheticalDocs = append(hypotheticalDocs, query)
+	}
 
 	// 2. 根据合并策略检索
 	switch r.mergeStrategy {
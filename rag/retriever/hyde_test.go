@@ -365,6 +365,28 @@ func TestHyDERetriever_MultipleHypothetical(t *testing.T) {
 	}
 }
 
+// TestHyDERetriever_IncludeQuery 测试原始查询与假设文档一起参与向量平均
+func TestHyDERetriever_IncludeQuery(t *testing.T) {
+	llmProvider := mock.FixedProvider("假设文档")
+	embedder := mock.NewMockEmbedder(3)
+	store := mock.NewMockVectorStore(mock.WithSearchResults([]vector.Document{
+		{ID: "r1", Content: "结果", Score: 0.9},
+	}))
+
+	hyde := NewHyDERetriever(llmProvider, embedder, store, WithHyDEIncludeQuery(true))
+	if _, err := hyde.Retrieve(context.Background(), "Go 并发"); err != nil {
+		t.Fatalf("Retrieve 失败: %v", err)
+	}
+
+	calls := embedder.EmbedCalls()
+	if len(calls) != 2 || calls[1][0] != "Go 并发" {
+		t.Fatalf("期望依次向量化假设文档和原始查询，实际 %v", calls)
+	}
+	if store.SearchCallCount() != 1 {
+		t.Errorf("平均向量策略期望检索 1 次，实际 %d", store.SearchCallCount())
+	}
+}
+
 // ============== TestHyDERetriever_Options ==============
 
 // TestHyDERetriever_Options 测试各种选项函数是否正确设置
@@ -0,0 +1,60 @@
+package retriever
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/hexagon-codes/toolkit/util/idgen"
+)
+
+// IDGenerator 为未指定 ID 的文档生成 ID
+//
+// 父文档以 ID 为键存入 DocumentStore，ID 冲突会静默覆盖已有父文档，
+// 因此生成策略需要在确定性和冲突概率之间权衡：
+//
+//   - HashIDGenerator: 内容哈希截断，确定性（相同内容 → 相同 ID，可配合去重），
+//     n 字节时约在 2^(4n) 个文档时达到 50% 冲突概率（生日界）：
+//     8 字节约 40 亿，16 字节约 1.8×10^19
+//   - FullHashIDGenerator: 完整 SHA-256，确定性，冲突概率可忽略，ID 较长（68 字符）
+//   - UUIDIDGenerator: 随机 UUID v4，不冲突但非确定性，相同内容重复索引会产生多个父文档
+//   - SequenceIDGenerator: 进程内自增序号，紧凑易读，但跨进程或重启后会重复
+type IDGenerator func(content string) string
+
+// defaultIDHashBytes 默认哈希 ID 的字节数
+const defaultIDHashBytes = 16
+
+// HashIDGenerator 使用 SHA-256 前 n 字节（十六进制）生成 ID，格式 doc_<hex>
+// n 超出 [1, 32] 时取边界值
+func HashIDGenerator(n int) IDGenerator {
+	n = min(max(n, 1), sha256.Size)
+	return func(content string) string {
+		hash := sha256.Sum256([]byte(content))
+		return "doc_" + hex.EncodeToString(hash[:n])
+	}
+}
+
+// FullHashIDGenerator 使用完整 SHA-256 生成 ID
+func FullHashIDGenerator() IDGenerator {
+	return HashIDGenerator(sha256.Size)
+}
+
+// UUIDIDGenerator 使用随机 UUID 生成 ID，格式 doc_<uuid>
+func UUIDIDGenerator() IDGenerator {
+	return func(string) string {
+		return "doc_" + idgen.UUID()
+	}
+}
+
+// SequenceIDGenerator 使用自增序号生成 ID，格式 <prefix>_<n>，n 从 1 开始
+// 每次调用返回独立的计数器，并发安全
+func SequenceIDGenerator(prefix string) IDGenerator {
+	var seq atomic.Int64
+	return func(string) string {
+		return fmt.Sprintf("%s_%d", prefix, seq.Add(1))
+	}
+}
+
+// generateDocID 默认文档 ID：SHA-256 前 16 字节
+var generateDocID = HashIDGenerator(defaultIDHashBytes)
@@ -0,0 +1,275 @@
+package retriever
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/rag/query"
+	"github.com/hexagon-codes/hexagon/rag/reranker"
+)
+
+// MetadataMatchedQueries 检索结果中记录命中该文档的查询，值类型为 []string
+const MetadataMatchedQueries = "matched_queries"
+
+// QueryFusion 多查询结果的融合方式
+type QueryFusion int
+
+const (
+	// FusionMaxScore 去重后按文档在各查询中的最高分数排序
+	FusionMaxScore QueryFusion = iota
+
+	// FusionRRF 倒数排名融合：Score = Σ 1/(k + rank)，不依赖各查询分数的可比性
+	// 由 reranker.RRFReranker 计算，没有 ID 的文档不参与融合
+	FusionRRF
+)
+
+// defaultMultiQueryPrompt 默认查询改写提示词
+const defaultMultiQueryPrompt = `请为以下问题生成 %d 个不同表述的搜索查询，用于从知识库中检索相关文档。
+每个查询使用不同的措辞或角度，每行一个，不要编号，不要解释。
+
+问题：%s
+
+查询：`
+
+// MultiQueryRetriever 多查询检索器
+// 使用 LLM 将用户查询改写为多个不同表述，分别检索后合并去重，提升召回率
+//
+// 核心流程：
+//  1. 用户查询 → query.MultiQueryGenerator 生成 N 个改写查询（与原始查询一起检索）
+//  2. 所有查询并发调用底层检索器
+//  3. 按文档 ID 去重，按最高分数或 RRF 融合排序，返回 TopK
+//
+// LLM 调用失败时降级为仅用原始查询检索。
+// 参考 LangChain MultiQueryRetriever 设计
+//
+// 使用示例：
+//
+//	mq := NewMultiQueryRetriever(baseRetriever, llmProvider,
+//	    WithQueryCount(4),
+//	    WithQueryFusion(FusionRRF),
+//	)
+//	docs, err := mq.Retrieve(ctx, "如何配置超时？")
+type MultiQueryRetriever struct {
+	// base 底层检索器
+	base rag.Retriever
+
+	// llm 生成改写查询使用的 LLM，为 nil 时仅用原始查询检索
+	llm query.LLMProvider
+
+	// promptTemplate 改写提示词模板，%d 替换为生成数量，%s 替换为用户查询
+	promptTemplate string
+
+	// queryCount 生成的改写查询数量
+	queryCount int
+
+	// fusion 结果融合方式
+	fusion QueryFusion
+
+	// rrfK RRF 参数 k
+	rrfK float64
+
+	// topK 返回文档数量
+	topK int
+}
+
+// MultiQueryOption MultiQueryRetriever 选项
+type MultiQueryOption func(*MultiQueryRetriever)
+
+// WithQueryCount 设置生成的改写查询数量（不含原始查询）
+// 默认值: 3
+func WithQueryCount(n int) MultiQueryOption {
+	return func(r *MultiQueryRetriever) {
+		if n > 0 {
+			r.queryCount = n
+		}
+	}
+}
+
+// WithMultiQueryPrompt 设置改写提示词模板
+// 模板中 %d 会被替换为生成数量，%s 会被替换为用户查询；LLM 应每行输出一个查询
+func WithMultiQueryPrompt(prompt string) MultiQueryOption {
+	return func(r *MultiQueryRetriever) {
+		r.promptTemplate = prompt
+	}
+}
+
+// WithQueryFusion 设置结果融合方式
+// 默认值: FusionMaxScore
+func WithQueryFusion(fusion QueryFusion) MultiQueryOption {
+	return func(r *MultiQueryRetriever) {
+		r.fusion = fusion
+	}
+}
+
+// WithMultiQueryTopK 设置返回文档数量
+// 默认值: 5
+func WithMultiQueryTopK(k int) MultiQueryOption {
+	return func(r *MultiQueryRetriever) {
+		if k > 0 {
+			r.topK = k
+		}
+	}
+}
+
+// NewMultiQueryRetriever 创建多查询检索器
+//
+// 参数：
+//   - base: 底层检索器，每个查询都会调用一次
+//   - llmProvider: LLM 提供者，用于生成改写查询
+//   - opts: 可选配置
+func NewMultiQueryRetriever(base rag.Retriever, llmProvider llm.Provider, opts ...MultiQueryOption) *MultiQueryRetriever {
+	var generatorLLM query.LLMProvider
+	if llmProvider != nil {
+		generatorLLM = query.FromProvider(llmProvider)
+	}
+	r := &MultiQueryRetriever{
+		base:           base,
+		llm:            generatorLLM,
+		promptTemplate: defaultMultiQueryPrompt,
+		queryCount:     3,
+		fusion:         FusionMaxScore,
+		rrfK:           60,
+		topK:           5,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Retrieve 执行多查询检索
+// 检索选项原样传给底层检索器；单个查询检索失败时跳过，全部失败时返回错误
+func (r *MultiQueryRetriever) Retrieve(ctx context.Context, q string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
+	cfg := &rag.RetrieveConfig{
+		TopK: r.topK,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	queries := append([]string{q}, r.GenerateQueries(ctx, q)...)
+
+	// 并发检索所有查询
+	results := make([][]rag.Document, len(queries))
+	errs := make([]error, len(queries))
+	baseOpts := append(append([]rag.RetrieveOption(nil), opts...), rag.WithTopK(cfg.TopK))
+
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = r.base.Retrieve(ctx, q, baseOpts...)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(queries) {
+		return nil, fmt.Errorf("所有查询检索失败: %w", errors.Join(errs...))
+	}
+
+	docs := r.fuse(queries, results, cfg.TopK)
+	if len(docs) > cfg.TopK {
+		docs = docs[:cfg.TopK]
+	}
+	return docs, nil
+}
+
+// GenerateQueries 使用 LLM 生成改写查询（不含原始查询）
+// LLM 调用失败时返回空列表
+func (r *MultiQueryRetriever) GenerateQueries(ctx context.Context, q string) []string {
+	if r.llm == nil {
+		return nil
+	}
+
+	generator := query.NewMultiQueryGenerator(r.llm,
+		query.WithNumQueries(r.queryCount),
+		query.WithMultiQueryPrompt(r.promptTemplate),
+		query.WithIncludeSelf(false),
+	)
+	queries, err := generator.Generate(ctx, q)
+	if err != nil {
+		return nil
+	}
+	// 未生成有效查询时 Generate 返回原始查询
+	if len(queries) == 1 && queries[0] == q {
+		return nil
+	}
+	return queries
+}
+
+// fuse 融合各查询的结果，并记录命中每个文档的查询
+func (r *MultiQueryRetriever) fuse(queries []string, results [][]rag.Document, topK int) []rag.Document {
+	var merged []rag.Document
+	switch r.fusion {
+	case FusionRRF:
+		merged = reranker.NewRRFReranker(reranker.WithRRFK(r.rrfK), reranker.WithRRFTopK(topK)).FuseRankings(results...)
+	default:
+		merged = fuseMaxScore(results)
+	}
+
+	matched := make(map[string][]string)
+	for i, docs := range results {
+		for _, doc := range docs {
+			key := docKey(doc)
+			if qs := matched[key]; len(qs) == 0 || qs[len(qs)-1] != queries[i] {
+				matched[key] = append(qs, queries[i])
+			}
+		}
+	}
+	for i := range merged {
+		metadata := make(map[string]any, len(merged[i].Metadata)+1)
+		for k, v := range merged[i].Metadata {
+			metadata[k] = v
+		}
+		metadata[MetadataMatchedQueries] = matched[docKey(merged[i])]
+		merged[i].Metadata = metadata
+	}
+	return merged
+}
+
+// fuseMaxScore 按文档去重，保留各查询中分数最高的一份，按分数降序排列
+func fuseMaxScore(results [][]rag.Document) []rag.Document {
+	best := make(map[string]int)
+	var merged []rag.Document
+	for _, docs := range results {
+		for _, doc := range docs {
+			key := docKey(doc)
+			i, ok := best[key]
+			if !ok {
+				best[key] = len(merged)
+				merged = append(merged, doc)
+				continue
+			}
+			if doc.Score > merged[i].Score {
+				merged[i] = doc
+			}
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+	return merged
+}
+
+// docKey 文档去重键：优先使用 ID，没有 ID 时使用内容哈希
+func docKey(doc rag.Document) string {
+	if doc.ID != "" {
+		return doc.ID
+	}
+	return doc.ContentHash()
+}
+
+var _ rag.Retriever = (*MultiQueryRetriever)(nil)
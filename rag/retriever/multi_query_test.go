@@ -0,0 +1,146 @@
+package retriever
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// queryIndex 按查询返回预设结果的检索函数，并记录收到的查询
+type queryIndex struct {
+	mu      sync.Mutex
+	results map[string][]rag.Document
+	queries []string
+}
+
+func (q *queryIndex) retrieve(ctx context.Context, query string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queries = append(q.queries, query)
+	docs, ok := q.results[query]
+	if !ok {
+		return nil, errors.New("unknown query")
+	}
+	return docs, nil
+}
+
+func TestMultiQueryRetriever_Basic(t *testing.T) {
+	llmProvider := mock.FixedProvider("1. 超时如何设置\n2. 配置请求超时\n- 如何配置超时？\n")
+	index := &queryIndex{results: map[string][]rag.Document{
+		"如何配置超时？": {{ID: "a", Content: "A", Score: 0.7}, {ID: "b", Content: "B", Score: 0.5}},
+		"超时如何设置":  {{ID: "b", Content: "B", Score: 0.9}},
+		"配置请求超时":  {{ID: "c", Content: "C", Score: 0.6, Metadata: map[string]any{"source": "c.md"}}},
+	}}
+
+	r := NewMultiQueryRetriever(mock.NewRetriever(mock.WithRetrieveFn(index.retrieve)), llmProvider)
+	docs, err := r.Retrieve(context.Background(), "如何配置超时？")
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+
+	// 原始查询 + 2 个改写查询（重复的原始查询被去除）
+	if len(index.queries) != 3 {
+		t.Fatalf("expected 3 queries, got %v", index.queries)
+	}
+
+	var ids []string
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	if strings.Join(ids, ",") != "b,a,c" {
+		t.Fatalf("expected b,a,c ranked by max score, got %v", ids)
+	}
+	if docs[0].Score != 0.9 {
+		t.Errorf("expected max score 0.9, got %v", docs[0].Score)
+	}
+	matched, _ := docs[0].Metadata[MetadataMatchedQueries].([]string)
+	if len(matched) != 2 {
+		t.Errorf("expected doc b matched by 2 queries, got %v", matched)
+	}
+	if docs[2].Metadata["source"] != "c.md" {
+		t.Errorf("expected original metadata preserved, got %v", docs[2].Metadata)
+	}
+}
+
+func TestMultiQueryRetriever_RRF(t *testing.T) {
+	llmProvider := mock.FixedProvider("q2")
+	index := &queryIndex{results: map[string][]rag.Document{
+		"q1": {{ID: "a", Score: 0.99}, {ID: "b", Score: 0.5}},
+		"q2": {{ID: "b", Score: 0.4}, {ID: "c", Score: 0.3}},
+	}}
+
+	r := NewMultiQueryRetriever(mock.NewRetriever(mock.WithRetrieveFn(index.retrieve)), llmProvider,
+		WithQueryFusion(FusionRRF))
+	docs, err := r.Retrieve(context.Background(), "q1")
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	// b 出现在两个查询中，RRF 分数最高
+	if len(docs) != 3 || docs[0].ID != "b" {
+		t.Fatalf("expected b first under RRF, got %+v", docs)
+	}
+	if want := float32(1.0/62 + 1.0/61); docs[0].Score != want {
+		t.Errorf("expected RRF score %v, got %v", want, docs[0].Score)
+	}
+}
+
+func TestMultiQueryRetriever_PromptAndCount(t *testing.T) {
+	llmProvider := mock.FixedProvider("v1\nv2\nv3\nv4")
+	base := mock.NewRetriever(mock.WithDocuments([]rag.Document{{ID: "a", Score: 1}}))
+
+	r := NewMultiQueryRetriever(base, llmProvider,
+		WithQueryCount(2),
+		WithMultiQueryPrompt("give %d variants of: %s"),
+	)
+	if _, err := r.Retrieve(context.Background(), "topic"); err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+
+	if got := llmProvider.LastCall().Messages[0].Content; got != "give 2 variants of: topic" {
+		t.Errorf("unexpected prompt %q", got)
+	}
+	if len(base.RetrieveCalls()) != 3 {
+		t.Errorf("expected original + 2 variants, got %d calls", len(base.RetrieveCalls()))
+	}
+}
+
+func TestMultiQueryRetriever_LLMFailure(t *testing.T) {
+	index := &queryIndex{results: map[string][]rag.Document{
+		"q": {{ID: "a", Score: 0.8}},
+	}}
+	r := NewMultiQueryRetriever(mock.NewRetriever(mock.WithRetrieveFn(index.retrieve)),
+		mock.ErrorProvider(errors.New("llm down")))
+
+	docs, err := r.Retrieve(context.Background(), "q")
+	if err != nil {
+		t.Fatalf("expected fallback to original query, got %v", err)
+	}
+	if len(docs) != 1 || len(index.queries) != 1 {
+		t.Errorf("expected only original query, got queries %v docs %v", index.queries, docs)
+	}
+}
+
+func TestMultiQueryRetriever_Failures(t *testing.T) {
+	index := &queryIndex{results: map[string][]rag.Document{
+		"v1": {{ID: "a", Score: 0.8}},
+	}}
+	r := NewMultiQueryRetriever(mock.NewRetriever(mock.WithRetrieveFn(index.retrieve)),
+		mock.FixedProvider("v1\nv2"), WithMultiQueryTopK(1))
+
+	// 部分查询失败时返回成功查询的结果
+	docs, err := r.Retrieve(context.Background(), "q")
+	if err != nil || len(docs) != 1 || docs[0].ID != "a" {
+		t.Fatalf("expected partial results, got %v, %v", docs, err)
+	}
+
+	// 全部失败时返回错误
+	index.results = map[string][]rag.Document{}
+	if _, err := r.Retrieve(context.Background(), "q"); err == nil {
+		t.Error("expected error when all queries fail")
+	}
+}
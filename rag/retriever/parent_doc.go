@@ -3,9 +3,8 @@ package retriever
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
+	"reflect"
 	"sort"
 	"sync"
 	"time"
@@ -54,32 +53,82 @@ type ParentDocRetriever struct {
 	// parentTopK 返回父文档数量
 	parentTopK int
 
+	// childFetchMultiplier 子块检索倍数，实际检索 max(childTopK, TopK*倍数) 个子块
+	childFetchMultiplier int
+
+	// childRefetchLimit 父文档不足时二次检索的子块数量，0 表示不二次检索
+	childRefetchLimit int
+
 	// minScore 最小相关性分数
 	minScore float32
 
+	// dedup 索引去重模式
+	dedup rag.DedupMode
+
+	// idGenerator 父文档 ID 生成器（文档未指定 ID 时使用）
+	idGenerator IDGenerator
+
+	// inheritedMetadata 子块继承的父文档元数据键，nil 表示继承全部
+	inheritedMetadata []string
+
+	// childIDs 父文档 ID -> 子块 ID 列表（按 chunk_index 顺序）
+	childIDs map[string][]string
+
 	// mu 保护并发访问
 	mu sync.RWMutex
+
+	// reindexMu Reindex 持写锁，Index 持读锁：索引之间可以并发，但不与重建交错
+	reindexMu sync.RWMutex
 }
 
 // DocumentStore 简单的文档存储
-// 用于存储父文档
+// 用于存储父文档，可通过 WithIndex 为元数据字段建立二级索引
 type DocumentStore struct {
 	docs map[string]rag.Document
-	mu   sync.RWMutex
+
+	// indexes 元数据字段 -> 字段值 -> 文档 ID 集合
+	indexes map[string]map[any]map[string]struct{}
+
+	mu sync.RWMutex
+}
+
+// DocumentStoreOption DocumentStore 配置选项
+type DocumentStoreOption func(*DocumentStore)
+
+// WithIndex 为元数据字段建立二级索引，Query 按这些字段查找时无需扫描全部文档
+//
+// 只有可比较的元数据值（字符串、数字、布尔值等）会被索引
+func WithIndex(fields ...string) DocumentStoreOption {
+	return func(s *DocumentStore) {
+		for _, field := range fields {
+			if _, ok := s.indexes[field]; !ok {
+				s.indexes[field] = make(map[any]map[string]struct{})
+			}
+		}
+	}
 }
 
 // NewDocumentStore 创建文档存储
-func NewDocumentStore() *DocumentStore {
-	return &DocumentStore{
-		docs: make(map[string]rag.Document),
+func NewDocumentStore(opts ...DocumentStoreOption) *DocumentStore {
+	s := &DocumentStore{
+		docs:    make(map[string]rag.Document),
+		indexes: make(map[string]map[any]map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// Save 保存文档
+// Save 保存文档，覆盖同 ID 文档时同步更新索引
 func (s *DocumentStore) Save(doc rag.Document) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if old, ok := s.docs[doc.ID]; ok {
+		s.unindex(old)
+	}
 	s.docs[doc.ID] = doc
+	s.index(doc)
 }
 
 // Get 获取文档
@@ -94,14 +143,84 @@ func (s *DocumentStore) Get(id string) (rag.Document, bool) {
 func (s *DocumentStore) Delete(id string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	delete(s.docs, id)
+	if doc, ok := s.docs[id]; ok {
+		s.unindex(doc)
+		delete(s.docs, id)
+	}
 }
 
-// Clear 清空存储
+// Clear 清空存储，保留索引字段配置
 func (s *DocumentStore) Clear() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.docs = make(map[string]rag.Document)
+	for field := range s.indexes {
+		s.indexes[field] = make(map[any]map[string]struct{})
+	}
+}
+
+// Query 返回元数据字段等于 value 的文档，按 ID 升序排列
+//
+// 字段已通过 WithIndex 建立索引时直接查索引，否则扫描全部文档
+func (s *DocumentStore) Query(field string, value any) []rag.Document {
+	s.mu.RLock()
+	var docs []rag.Document
+	if index, ok := s.indexes[field]; ok {
+		if indexable(value) {
+			for id := range index[value] {
+				docs = append(docs, s.docs[id])
+			}
+		}
+	} else {
+		for _, doc := range s.docs {
+			if v, ok := doc.Metadata[field]; ok && indexable(v) && indexable(value) && v == value {
+				docs = append(docs, doc)
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].ID < docs[j].ID
+	})
+	return docs
+}
+
+// index 将文档加入索引，调用方持有写锁
+func (s *DocumentStore) index(doc rag.Document) {
+	for field, index := range s.indexes {
+		value, ok := doc.Metadata[field]
+		if !ok || !indexable(value) {
+			continue
+		}
+		ids, ok := index[value]
+		if !ok {
+			ids = make(map[string]struct{})
+			index[value] = ids
+		}
+		ids[doc.ID] = struct{}{}
+	}
+}
+
+// unindex 将文档移出索引，调用方持有写锁
+func (s *DocumentStore) unindex(doc rag.Document) {
+	for field, index := range s.indexes {
+		value, ok := doc.Metadata[field]
+		if !ok || !indexable(value) {
+			continue
+		}
+		if ids, ok := index[value]; ok {
+			delete(ids, doc.ID)
+			if len(ids) == 0 {
+				delete(index, value)
+			}
+		}
+	}
+}
+
+// indexable 值能否作为索引键（可比较且非 nil）
+func indexable(v any) bool {
+	return v != nil && reflect.ValueOf(v).Comparable()
 }
 
 // Count 返回文档数量
@@ -111,6 +230,41 @@ func (s *DocumentStore) Count() int {
 	return len(s.docs)
 }
 
+// List 返回所有文档，按 ID 升序排列
+func (s *DocumentStore) List() []rag.Document {
+	s.mu.RLock()
+	docs := make([]rag.Document, 0, len(s.docs))
+	for _, doc := range s.docs {
+		docs = append(docs, doc)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].ID < docs[j].ID
+	})
+	return docs
+}
+
+// MetadataMatchedChildren 检索结果中记录命中子块的元数据键
+// 值类型为 []MatchedChild，按分数降序排列
+const MetadataMatchedChildren = "matched_children"
+
+// MatchedChild 检索时命中的子块
+// 用于调试分块大小以及理解父文档的排序依据
+type MatchedChild struct {
+	// ID 子块 ID
+	ID string `json:"id"`
+
+	// ChunkIndex 子块在父文档中的序号，未知时为 -1
+	ChunkIndex int `json:"chunk_index"`
+
+	// Score 子块相似度分数
+	Score float32 `json:"score"`
+
+	// Content 子块内容
+	Content string `json:"content"`
+}
+
 // ParentDocOption ParentDocRetriever 配置选项
 type ParentDocOption func(*ParentDocRetriever)
 
@@ -141,6 +295,33 @@ func WithParentTopK(k int) ParentDocOption {
 	}
 }
 
+// WithChildFetchMultiplier 设置子块检索倍数
+//
+// 多个子块常属于同一父文档，固定的 childTopK 可能凑不齐 TopK 个不同父文档。
+// 设置后检索 max(childTopK, TopK*m) 个子块：m 越大召回越充分，
+// 但向量检索与结果聚合的开销也随之线性增加。
+// 默认值: 0（仅使用 childTopK）
+func WithChildFetchMultiplier(m int) ParentDocOption {
+	return func(r *ParentDocRetriever) {
+		if m > 0 {
+			r.childFetchMultiplier = m
+		}
+	}
+}
+
+// WithChildRefetch 设置父文档不足时的二次检索子块数量
+//
+// 首次检索去重后父文档少于 TopK，且向量存储返回了满额子块（说明可能还有更多）时，
+// 以 limit 个子块再检索一次。二次检索会增加一次向量检索的延迟，仅在 limit 大于首次检索数量时生效。
+// 默认值: 0（不二次检索）
+func WithChildRefetch(limit int) ParentDocOption {
+	return func(r *ParentDocRetriever) {
+		if limit > 0 {
+			r.childRefetchLimit = limit
+		}
+	}
+}
+
 // WithParentMinScore 设置最小相关性分数
 func WithParentMinScore(score float32) ParentDocOption {
 	return func(r *ParentDocRetriever) {
@@ -148,6 +329,41 @@ func WithParentMinScore(score float32) ParentDocOption {
 	}
 }
 
+// WithParentDedup 设置索引去重模式
+// 同 ID 且内容哈希一致的父文档视为重复，按模式跳过或重新索引。
+// 未指定 ID 的文档默认使用内容哈希生成 ID，因此相同内容总是映射到同一父文档。
+// 默认值: rag.DedupAllow
+func WithParentDedup(mode rag.DedupMode) ParentDocOption {
+	return func(r *ParentDocRetriever) {
+		r.dedup = mode
+	}
+}
+
+// WithIDGenerator 设置父文档 ID 生成器，仅用于未指定 ID 的文档
+// 各策略的冲突概率与取舍见 IDGenerator；去重依赖确定性 ID，
+// 使用 UUIDIDGenerator 或 SequenceIDGenerator 时相同内容会被重复索引。
+// 默认值: HashIDGenerator(16)
+func WithIDGenerator(gen IDGenerator) ParentDocOption {
+	return func(r *ParentDocRetriever) {
+		if gen != nil {
+			r.idGenerator = gen
+		}
+	}
+}
+
+// WithInheritedMetadata 设置子块从父文档继承的元数据键
+//
+// 继承的元数据随子块写入向量存储，使按元数据过滤的检索（如 vector.WithFilter）能在子块层面命中。
+// 子块自身（分割器生成）已有的键优先于父文档的值；parent_id 和 chunk_index 总是由检索器设置。
+// 不带参数调用表示不继承任何键。内置分割器会把父文档的全部元数据复制到子块，
+// 子块中与父文档同名且值相同、但不在 keys 中的键视为从父文档复制而来，同样被移除。
+// 默认值: 继承全部键
+func WithInheritedMetadata(keys ...string) ParentDocOption {
+	return func(r *ParentDocRetriever) {
+		r.inheritedMetadata = append([]string{}, keys...)
+	}
+}
+
 // WithParentStore 设置父文档存储（可用于持久化）
 func WithParentStore(store *DocumentStore) ParentDocOption {
 	return func(r *ParentDocRetriever) {
@@ -169,6 +385,8 @@ func NewParentDocRetriever(childStore vector.Store, embedder vector.Embedder, op
 		childTopK:   10,
 		parentTopK:  5,
 		minScore:    0.0,
+		idGenerator: generateDocID,
+		childIDs:    make(map[string][]string),
 	}
 
 	for _, opt := range opts {
@@ -182,98 +400,272 @@ func NewParentDocRetriever(childStore vector.Store, embedder vector.Embedder, op
 // 将原始文档保存为父文档，分割成子块后存入向量存储。
 // 仅在访问内存状态时短暂持锁，Embed 等耗时操作在锁外执行，避免阻塞 Retrieve。
 func (r *ParentDocRetriever) Index(ctx context.Context, docs []rag.Document) error {
+	_, err := r.IndexWithResult(ctx, docs)
+	return err
+}
+
+// IndexWithResult 索引文档并返回新增/跳过/更新的数量
+// 未启用去重时所有文档计为新增
+func (r *ParentDocRetriever) IndexWithResult(ctx context.Context, docs []rag.Document) (*rag.IndexResult, error) {
+	r.reindexMu.RLock()
+	defer r.reindexMu.RUnlock()
+
+	result := &rag.IndexResult{}
+	seen := make(map[string]bool, len(docs))
+	childStore, embedder := r.components()
+
 	for _, doc := range docs {
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return result, ctx.Err()
 		}
 
 		// 生成父文档 ID（如果没有）
 		if doc.ID == "" {
-			doc.ID = generateDocID(doc.Content)
+			doc.ID = r.idGenerator(doc.Content)
 		}
 		if doc.CreatedAt.IsZero() {
 			doc.CreatedAt = time.Now()
 		}
 
+		// 写入成功后才计入统计
+		isUpdate := false
+		if r.dedup != rag.DedupAllow {
+			hash := doc.ContentHash()
+			if seen[hash] {
+				result.Skipped++
+				continue
+			}
+			seen[hash] = true
+
+			r.mu.RLock()
+			existing, exists := r.parentStore.Get(doc.ID)
+			r.mu.RUnlock()
+
+			if exists && existing.ContentHash() == hash && r.dedup == rag.DedupSkip {
+				result.Skipped++
+				continue
+			}
+			isUpdate = exists
+		}
+
 		// 短暂持锁保存父文档
 		r.mu.Lock()
 		r.parentStore.Save(doc)
 		r.mu.Unlock()
 
-		// 分割成子块（在锁外执行）
-		var childDocs []rag.Document
-		if r.childSplitter != nil {
-			var err error
-			childDocs, err = r.childSplitter.Split(ctx, []rag.Document{doc})
-			if err != nil {
-				return fmt.Errorf("分割文档 %s 失败: %w", doc.ID, err)
-			}
-		} else {
-			// 没有分割器，直接使用原文档作为子文档
-			childDocs = []rag.Document{doc}
+		// 分割、向量化并存入子块（在锁外执行）
+		ids, err := r.indexChildren(ctx, childStore, embedder, doc)
+		if err != nil {
+			return result, err
 		}
 
-		// 为每个子文档设置 parent_id 元数据
-		for i := range childDocs {
-			if childDocs[i].ID == "" {
-				childDocs[i].ID = fmt.Sprintf("%s_chunk_%d", doc.ID, i)
+		// 记录子块 ID，并清理重新索引后不再存在的旧子块
+		current := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			current[id] = true
+		}
+		r.mu.Lock()
+		previous := r.childIDs[doc.ID]
+		r.childIDs[doc.ID] = ids
+		r.mu.Unlock()
+
+		var stale []string
+		for _, id := range previous {
+			if !current[id] {
+				stale = append(stale, id)
 			}
-			if childDocs[i].Metadata == nil {
-				childDocs[i].Metadata = make(map[string]any)
+		}
+		if len(stale) > 0 {
+			if err := childStore.Delete(ctx, stale); err != nil {
+				return result, fmt.Errorf("清理文档 %s 的旧子块失败: %w", doc.ID, err)
 			}
-			childDocs[i].Metadata["parent_id"] = doc.ID
-			childDocs[i].Metadata["chunk_index"] = i
 		}
 
-		// 向量化子文档（在锁外执行，此操作可能耗时数秒）
-		texts := make([]string, len(childDocs))
-		for i, cd := range childDocs {
-			texts[i] = cd.Content
+		if isUpdate {
+			result.Updated++
+		} else {
+			result.Added++
 		}
+	}
 
-		embeddings, err := r.embedder.Embed(ctx, texts)
+	return result, nil
+}
+
+// indexChildren 将父文档分割、向量化后存入 childStore，返回子块 ID（按 chunk_index 顺序）
+func (r *ParentDocRetriever) indexChildren(ctx context.Context, childStore vector.Store, embedder vector.Embedder, doc rag.Document) ([]string, error) {
+	// 分割成子块
+	var childDocs []rag.Document
+	if r.childSplitter != nil {
+		var err error
+		childDocs, err = r.childSplitter.Split(ctx, []rag.Document{doc})
 		if err != nil {
-			return fmt.Errorf("向量化文档 %s 的子块失败: %w", doc.ID, err)
+			return nil, fmt.Errorf("分割文档 %s 失败: %w", doc.ID, err)
 		}
+	} else {
+		// 没有分割器，直接使用原文档作为子文档
+		childDocs = []rag.Document{doc}
+	}
 
-		// 设置向量并转换为 vector.Document
-		vectorDocs := make([]vector.Document, len(childDocs))
-		for i := range childDocs {
-			if i < len(embeddings) {
-				childDocs[i].Embedding = embeddings[i]
-			}
-			vectorDocs[i] = ragDocToVectorDoc(childDocs[i])
+	// 继承父文档元数据并设置 parent_id
+	for i := range childDocs {
+		if childDocs[i].ID == "" {
+			childDocs[i].ID = fmt.Sprintf("%s_chunk_%d", doc.ID, i)
 		}
+		childDocs[i].Metadata = r.childMetadata(doc.Metadata, childDocs[i].Metadata)
+		childDocs[i].Metadata["parent_id"] = doc.ID
+		childDocs[i].Metadata["chunk_index"] = i
+	}
+
+	// 向量化子文档（此操作可能耗时数秒）
+	texts := make([]string, len(childDocs))
+	for i, cd := range childDocs {
+		texts[i] = cd.Content
+	}
 
-		// 存入向量存储（在锁外执行）
-		if err := r.childStore.Add(ctx, vectorDocs); err != nil {
-			return fmt.Errorf("存储文档 %s 的子块失败: %w", doc.ID, err)
+	embeddings, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("向量化文档 %s 的子块失败: %w", doc.ID, err)
+	}
+	if err := rag.CheckEmbeddings(childStore, embeddings...); err != nil {
+		return nil, fmt.Errorf("向量化文档 %s 的子块失败: %w", doc.ID, err)
+	}
+
+	// 设置向量并转换为 vector.Document
+	vectorDocs := make([]vector.Document, len(childDocs))
+	for i := range childDocs {
+		if i < len(embeddings) {
+			childDocs[i].Embedding = embeddings[i]
 		}
+		vectorDocs[i] = ragDocToVectorDoc(childDocs[i])
 	}
 
-	return nil
+	// 存入向量存储
+	if err := childStore.Add(ctx, vectorDocs); err != nil {
+		return nil, fmt.Errorf("存储文档 %s 的子块失败: %w", doc.ID, err)
+	}
+
+	ids := make([]string, len(childDocs))
+	for i, cd := range childDocs {
+		ids[i] = cd.ID
+	}
+	return ids, nil
 }
 
-// Retrieve 检索相关的父文档
-// 先检索子块，然后返回对应的父文档
-func (r *ParentDocRetriever) Retrieve(ctx context.Context, query string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
-	cfg := &rag.RetrieveConfig{
-		TopK:     r.parentTopK,
-		MinScore: r.minScore,
+// childMetadata 合并父文档元数据和子块自身元数据，返回新的 map
+// 子块已有的键优先；未设置 WithInheritedMetadata 时继承全部键，
+// 设置时子块中从父文档复制来的、不在继承列表中的键被移除
+func (r *ParentDocRetriever) childMetadata(parent, child map[string]any) map[string]any {
+	metadata := make(map[string]any, len(parent)+len(child)+2)
+	if r.inheritedMetadata == nil {
+		for k, v := range parent {
+			metadata[k] = v
+		}
+		for k, v := range child {
+			metadata[k] = v
+		}
+		return metadata
 	}
-	for _, opt := range opts {
-		opt(cfg)
+
+	inherited := make(map[string]bool, len(r.inheritedMetadata))
+	for _, k := range r.inheritedMetadata {
+		inherited[k] = true
+		if v, ok := parent[k]; ok {
+			metadata[k] = v
+		}
+	}
+	for k, v := range child {
+		if pv, ok := parent[k]; ok && !inherited[k] && reflect.DeepEqual(v, pv) {
+			continue
+		}
+		metadata[k] = v
 	}
+	return metadata
+}
 
+// Validate 校验子块存储与嵌入器的维度一致，建议在创建检索器后调用
+// 向量化一次探测文本，维度不一致时返回 rag.ErrDimensionMismatch
+func (r *ParentDocRetriever) Validate(ctx context.Context) error {
+	childStore, embedder := r.components()
+	return rag.CheckDimension(ctx, embedder, childStore)
+}
+
+// components 返回当前的子块存储和嵌入器（Reindex 可能替换二者）
+func (r *ParentDocRetriever) components() (vector.Store, vector.Embedder) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	return r.childStore, r.embedder
+}
+
+// Retrieve 检索相关的父文档
+// 先检索子块，然后返回对应的父文档。
+// 与 Index 一致，向量化和向量检索在锁外执行，仅读取父文档时短暂持有读锁。
+func (r *ParentDocRetriever) Retrieve(ctx context.Context, query string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
+	cfg := r.retrieveConfig(opts)
+	childStore, embedder := r.components()
 
-	// 向量化查询
-	embedding, err := r.embedder.EmbedOne(ctx, query)
+	// 向量化查询（在锁外执行）
+	embedding, err := embedder.EmbedOne(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("向量化查询失败: %w", err)
 	}
+	if err := rag.CheckEmbeddings(childStore, embedding); err != nil {
+		return nil, err
+	}
+
+	return r.retrieveByEmbedding(ctx, childStore, embedding, cfg)
+}
+
+// RetrieveBatch 批量检索相关的父文档
+// 所有查询通过一次 Embed 调用完成向量化，结果顺序与 queries 一致
+func (r *ParentDocRetriever) RetrieveBatch(ctx context.Context, queries []string, opts ...rag.RetrieveOption) ([][]rag.Document, error) {
+	if len(queries) == 0 {
+		return [][]rag.Document{}, nil
+	}
+
+	cfg := r.retrieveConfig(opts)
+	childStore, embedder := r.components()
+
+	embeddings, err := embedder.Embed(ctx, queries)
+	if err != nil {
+		return nil, fmt.Errorf("批量向量化查询失败: %w", err)
+	}
+	if len(embeddings) != len(queries) {
+		return nil, fmt.Errorf("向量数量不匹配: 期望 %d, 实际 %d", len(queries), len(embeddings))
+	}
+	if err := rag.CheckEmbeddings(childStore, embeddings...); err != nil {
+		return nil, err
+	}
+
+	results := make([][]rag.Document, len(queries))
+	for i, embedding := range embeddings {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		docs, err := r.retrieveByEmbedding(ctx, childStore, embedding, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("查询 %d: %w", i, err)
+		}
+		results[i] = docs
+	}
+
+	return results, nil
+}
 
+// retrieveConfig 合并默认配置和检索选项
+func (r *ParentDocRetriever) retrieveConfig(opts []rag.RetrieveOption) *rag.RetrieveConfig {
+	cfg := &rag.RetrieveConfig{
+		TopK:     r.parentTopK,
+		MinScore: r.minScore,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// retrieveByEmbedding 使用查询向量检索子块并聚合为父文档
+// 子块检索在锁外执行，仅在读取父文档时持有读锁
+func (r *ParentDocRetriever) retrieveByEmbedding(ctx context.Context, childStore vector.Store, embedding []float32, cfg *rag.RetrieveConfig) ([]rag.Document, error) {
 	// 检索子文档
 	searchOpts := []vector.SearchOption{
 		vector.WithMinScore(cfg.MinScore),
@@ -283,32 +675,26 @@ func (r *ParentDocRetriever) Retrieve(ctx context.Context, query string, opts ..
 		searchOpts = append(searchOpts, vector.WithFilter(cfg.Filter))
 	}
 
-	childDocs, err := r.childStore.Search(ctx, embedding, r.childTopK, searchOpts...)
+	fetch := r.childFetchCount(cfg.TopK)
+	childDocs, err := childStore.Search(ctx, embedding, fetch, searchOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("检索子文档失败: %w", err)
 	}
+	hits := collectParentHits(childDocs)
 
-	// 收集父文档 ID 和最高分数
-	parentScores := make(map[string]float32)
-	for _, child := range childDocs {
-		parentID, ok := child.Metadata["parent_id"].(string)
-		if !ok {
-			continue
-		}
-		// 记录每个父文档的最高子文档分数
-		if child.Score > parentScores[parentID] {
-			parentScores[parentID] = child.Score
+	// 父文档不足且子块满额时，扩大范围二次检索
+	if len(hits) < cfg.TopK && len(childDocs) >= fetch && r.childRefetchLimit > fetch {
+		childDocs, err = childStore.Search(ctx, embedding, r.childRefetchLimit, searchOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("二次检索子文档失败: %w", err)
 		}
+		hits = collectParentHits(childDocs)
 	}
 
-	// 按分数排序父文档 ID
-	type scoredParent struct {
-		id    string
-		score float32
-	}
-	var scored []scoredParent
-	for id, score := range parentScores {
-		scored = append(scored, scoredParent{id: id, score: score})
+	// 按分数排序父文档
+	scored := make([]*parentHit, 0, len(hits))
+	for _, hit := range hits {
+		scored = append(scored, hit)
 	}
 	sort.Slice(scored, func(i, j int) bool {
 		return scored[i].score > scored[j].score
@@ -321,36 +707,122 @@ func (r *ParentDocRetriever) Retrieve(ctx context.Context, query string, opts ..
 	}
 
 	parentDocs := make([]rag.Document, 0, k)
+	matched := make([][]MatchedChild, 0, k)
+	r.mu.RLock()
 	for i := 0; i < k; i++ {
 		parent, ok := r.parentStore.Get(scored[i].id)
 		if ok {
 			parent.Score = scored[i].score
-			// 添加检索元数据
-			if parent.Metadata == nil {
-				parent.Metadata = make(map[string]any)
-			}
-			parent.Metadata["retrieval_type"] = "parent_doc"
 			parentDocs = append(parentDocs, parent)
+			matched = append(matched, scored[i].children)
+		}
+	}
+	r.mu.RUnlock()
+
+	// 添加检索元数据（复制元数据，避免并发检索写入共享的父文档 map）
+	for i := range parentDocs {
+		metadata := make(map[string]any, len(parentDocs[i].Metadata)+2)
+		for key, v := range parentDocs[i].Metadata {
+			metadata[key] = v
 		}
+		metadata["retrieval_type"] = "parent_doc"
+		metadata[MetadataMatchedChildren] = matched[i]
+		parentDocs[i].Metadata = metadata
 	}
 
 	return parentDocs, nil
 }
 
+// childFetchCount 计算首次检索的子块数量
+func (r *ParentDocRetriever) childFetchCount(topK int) int {
+	return max(r.childTopK, topK*r.childFetchMultiplier)
+}
+
+// parentHit 检索命中的父文档
+type parentHit struct {
+	id       string
+	score    float32 // 子块最高分数
+	children []MatchedChild
+}
+
+// collectParentHits 按父文档聚合命中的子块
+// 子块按检索结果顺序（分数降序）记录
+func collectParentHits(childDocs []vector.Document) map[string]*parentHit {
+	hits := make(map[string]*parentHit)
+	for _, child := range childDocs {
+		parentID, ok := child.Metadata["parent_id"].(string)
+		if !ok {
+			continue
+		}
+		hit, seen := hits[parentID]
+		if !seen {
+			hit = &parentHit{id: parentID, score: child.Score}
+			hits[parentID] = hit
+		}
+		// 记录每个父文档的最高子文档分数
+		if child.Score > hit.score {
+			hit.score = child.Score
+		}
+		hit.children = append(hit.children, MatchedChild{
+			ID:         child.ID,
+			ChunkIndex: chunkIndex(child.Metadata),
+			Score:      child.Score,
+			Content:    child.Content,
+		})
+	}
+	return hits
+}
+
+// chunkIndex 读取子块序号，兼容序列化后的数值类型
+func chunkIndex(metadata map[string]any) int {
+	return metadataInt(metadata, "chunk_index")
+}
+
+// metadataInt 读取整数元数据，兼容序列化后的数值类型，不存在时返回 -1
+func metadataInt(metadata map[string]any, key string) int {
+	switch v := metadata[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return -1
+}
+
 // Delete 删除文档（包括父文档和所有子块）
+//
+// 子块按索引时记录的 ID 删除；父文档来自外部持久化（未经本实例索引）时，
+// 按 GetChildren 的方式查找子块后删除。子块删除失败时保留父文档，可重试。
 func (r *ParentDocRetriever) Delete(ctx context.Context, ids []string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
+	childStore, _ := r.components()
 	for _, id := range ids {
-		// 删除父文档
-		r.parentStore.Delete(id)
+		r.mu.RLock()
+		childIDs, tracked := r.childIDs[id]
+		childIDs = append([]string(nil), childIDs...)
+		r.mu.RUnlock()
 
-		// 删除子块（通过 parent_id 过滤）
-		// 注意：这需要向量存储支持按元数据删除
-		// 如果不支持，可以记录子块 ID 后逐个删除
-	}
+		if !tracked {
+			children, err := r.GetChildren(ctx, id)
+			if err != nil {
+				return fmt.Errorf("查找文档 %s 的子块失败: %w", id, err)
+			}
+			for _, child := range children {
+				childIDs = append(childIDs, child.ID)
+			}
+		}
+		if len(childIDs) > 0 {
+			if err := childStore.Delete(ctx, childIDs); err != nil {
+				return fmt.Errorf("删除文档 %s 的子块失败: %w", id, err)
+			}
+		}
 
+		r.mu.Lock()
+		r.parentStore.Delete(id)
+		delete(r.childIDs, id)
+		r.mu.Unlock()
+	}
 	return nil
 }
 
@@ -360,6 +832,7 @@ func (r *ParentDocRetriever) Clear(ctx context.Context) error {
 	defer r.mu.Unlock()
 
 	r.parentStore.Clear()
+	r.childIDs = make(map[string][]string)
 	return r.childStore.Clear(ctx)
 }
 
@@ -370,15 +843,90 @@ func (r *ParentDocRetriever) Count(ctx context.Context) (int, error) {
 	return r.parentStore.Count(), nil
 }
 
+// Stats 返回父文档数量和向量存储中的子块数量
+//
+// 用于诊断父文档与子块是否同步：子块数量远多于预期通常意味着存在孤立子块
+// （例如绕过 Delete 直接修改了父文档存储）。childCount 来自向量存储的 Count，
+// 共享向量存储时包含其他来源的文档。
+func (r *ParentDocRetriever) Stats(ctx context.Context) (parentCount, childCount int, err error) {
+	r.mu.RLock()
+	parentCount = r.parentStore.Count()
+	childStore := r.childStore
+	r.mu.RUnlock()
+
+	childCount, err = childStore.Count(ctx)
+	if err != nil {
+		return parentCount, 0, fmt.Errorf("统计子块数量失败: %w", err)
+	}
+	return parentCount, childCount, nil
+}
+
 // GetParentStore 获取父文档存储（用于序列化/持久化）
 func (r *ParentDocRetriever) GetParentStore() *DocumentStore {
 	return r.parentStore
 }
 
-// generateDocID 生成文档 ID
-func generateDocID(content string) string {
-	hash := sha256.Sum256([]byte(content))
-	return "doc_" + hex.EncodeToString(hash[:8])
+// GetChildStore 获取子块向量存储（用于调试和检查子块）
+func (r *ParentDocRetriever) GetChildStore() vector.Store {
+	childStore, _ := r.components()
+	return childStore
+}
+
+// maxChildrenPerParent 未记录子块 ID 时按元数据过滤检索的子块上限
+const maxChildrenPerParent = 1000
+
+// GetChildren 返回父文档切分出的子块，按 chunk_index 升序排列
+//
+// 优先使用索引时记录的子块 ID 逐个读取；父文档来自外部持久化（未经本实例索引）时，
+// 以父文档内容向量检索并按 parent_id 元数据过滤，最多返回 maxChildrenPerParent 个子块。
+// 父文档不存在时返回空列表。
+func (r *ParentDocRetriever) GetChildren(ctx context.Context, parentID string) ([]rag.Document, error) {
+	r.mu.RLock()
+	ids, tracked := r.childIDs[parentID]
+	ids = append([]string(nil), ids...)
+	parent, exists := r.parentStore.Get(parentID)
+	childStore, embedder := r.childStore, r.embedder
+	r.mu.RUnlock()
+
+	var children []rag.Document
+	switch {
+	case tracked:
+		children = make([]rag.Document, 0, len(ids))
+		for _, id := range ids {
+			vd, err := childStore.Get(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("获取子块 %s 失败: %w", id, err)
+			}
+			if vd != nil {
+				children = append(children, vectorDocToRagDoc(*vd))
+			}
+		}
+
+	case exists:
+		embedding, err := embedder.EmbedOne(ctx, parent.Content)
+		if err != nil {
+			return nil, fmt.Errorf("向量化父文档失败: %w", err)
+		}
+		vectorDocs, err := childStore.Search(ctx, embedding, maxChildrenPerParent,
+			vector.WithFilter(map[string]any{"parent_id": parentID}),
+			vector.WithMetadata(true),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("检索子块失败: %w", err)
+		}
+		children = make([]rag.Document, len(vectorDocs))
+		for i, vd := range vectorDocs {
+			children[i] = vectorDocToRagDoc(vd)
+		}
+		sort.SliceStable(children, func(i, j int) bool {
+			return chunkIndex(children[i].Metadata) < chunkIndex(children[j].Metadata)
+		})
+
+	default:
+		return []rag.Document{}, nil
+	}
+
+	return children, nil
 }
 
 // ragDocToVectorDoc 将 rag.Document 转换为 vector.Document
@@ -407,3 +955,4 @@ func vectorDocToRagDoc(doc vector.Document) rag.Document {
 
 // 确保实现了 Retriever 接口
 var _ rag.Retriever = (*ParentDocRetriever)(nil)
+var _ rag.BatchRetriever = (*ParentDocRetriever)(nil)
@@ -0,0 +1,126 @@
+package retriever
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// ParentDocSnapshotVersion Export 写出的快照格式版本
+const ParentDocSnapshotVersion = 1
+
+// ErrSnapshotVersion 快照格式版本与当前版本不一致
+var ErrSnapshotVersion = core.NewCategoryError("unsupported snapshot version", core.ErrInvalidInput)
+
+// parentDocSnapshot ParentDocRetriever 元数据层快照
+type parentDocSnapshot struct {
+	Version  int                     `json:"version"`
+	Config   parentDocSnapshotConfig `json:"config"`
+	Parents  []rag.Document          `json:"parents"`
+	ChildIDs map[string][]string     `json:"child_ids,omitempty"`
+}
+
+// parentDocSnapshotConfig 快照中可序列化的检索配置
+type parentDocSnapshotConfig struct {
+	ChildTopK            int           `json:"child_top_k"`
+	ParentTopK           int           `json:"parent_top_k"`
+	ChildFetchMultiplier int           `json:"child_fetch_multiplier,omitempty"`
+	ChildRefetchLimit    int           `json:"child_refetch_limit,omitempty"`
+	MinScore             float32       `json:"min_score,omitempty"`
+	Dedup                rag.DedupMode `json:"dedup,omitempty"`
+}
+
+// Export 将父文档、父子块 ID 映射和检索配置以 JSON 写入 w
+//
+// 快照不包含子块向量、向量存储、嵌入器、分割器和 ID 生成器：
+// 子块可以在 Import 后通过 Reindex 重新生成，或由向量存储单独导出。
+// 导出期间 Index 和 Reindex 被阻塞，Retrieve 不受影响。
+func (r *ParentDocRetriever) Export(ctx context.Context, w io.Writer) error {
+	r.reindexMu.Lock()
+	defer r.reindexMu.Unlock()
+
+	r.mu.RLock()
+	snapshot := parentDocSnapshot{
+		Version: ParentDocSnapshotVersion,
+		Config: parentDocSnapshotConfig{
+			ChildTopK:            r.childTopK,
+			ParentTopK:           r.parentTopK,
+			ChildFetchMultiplier: r.childFetchMultiplier,
+			ChildRefetchLimit:    r.childRefetchLimit,
+			MinScore:             r.minScore,
+			Dedup:                r.dedup,
+		},
+		Parents:  r.parentStore.List(),
+		ChildIDs: make(map[string][]string, len(r.childIDs)),
+	}
+	for id, children := range r.childIDs {
+		snapshot.ChildIDs[id] = append([]string(nil), children...)
+	}
+	r.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return fmt.Errorf("写入快照失败: %w", err)
+	}
+	return nil
+}
+
+// Import 从 Export 写出的快照恢复父文档、父子块 ID 映射和检索配置
+//
+// 已有的父文档和映射被整体替换，父文档存储的索引配置保留；子块存储不做改动。
+// 快照版本与 ParentDocSnapshotVersion 不一致时返回 ErrSnapshotVersion，不修改任何状态。
+// 元数据经过 JSON 往返，数字类型的值恢复为 float64。
+//
+// 迁移到新环境时，若子块未随向量存储一起导出，Import 后调用 Reindex 重建：
+//
+//	if err := r.Import(ctx, f); err != nil {
+//	    return err
+//	}
+//	err := r.Reindex(ctx)
+func (r *ParentDocRetriever) Import(ctx context.Context, rd io.Reader) error {
+	var snapshot parentDocSnapshot
+	if err := json.NewDecoder(rd).Decode(&snapshot); err != nil {
+		return fmt.Errorf("读取快照失败: %w", err)
+	}
+	if snapshot.Version != ParentDocSnapshotVersion {
+		return fmt.Errorf("%w: got %d, want %d", ErrSnapshotVersion, snapshot.Version, ParentDocSnapshotVersion)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	childIDs := snapshot.ChildIDs
+	if childIDs == nil {
+		childIDs = make(map[string][]string)
+	}
+
+	r.reindexMu.Lock()
+	defer r.reindexMu.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.parentStore.Clear()
+	for _, doc := range snapshot.Parents {
+		r.parentStore.Save(doc)
+	}
+	r.childIDs = childIDs
+
+	cfg := snapshot.Config
+	if cfg.ChildTopK > 0 {
+		r.childTopK = cfg.ChildTopK
+	}
+	if cfg.ParentTopK > 0 {
+		r.parentTopK = cfg.ParentTopK
+	}
+	r.childFetchMultiplier = cfg.ChildFetchMultiplier
+	r.childRefetchLimit = cfg.ChildRefetchLimit
+	r.minScore = cfg.MinScore
+	r.dedup = cfg.Dedup
+	return nil
+}
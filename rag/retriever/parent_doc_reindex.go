@@ -0,0 +1,120 @@
+package retriever
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// ReindexOption Reindex 选项
+type ReindexOption func(*reindexConfig)
+
+type reindexConfig struct {
+	target     vector.Store
+	embedder   vector.Embedder
+	onProgress func(done, total int)
+}
+
+// WithReindexTarget 将子块重建到新的向量存储，完成后再原子切换
+//
+// 重建期间检索继续使用旧存储，不会看到缺失的子块；失败时不切换，旧存储保持不变。
+// 旧存储在切换后不会被清空或关闭，由调用方处理。
+// 默认在原存储上就地重建：先清空再重新写入，重建期间检索结果不完整。
+func WithReindexTarget(store vector.Store) ReindexOption {
+	return func(c *reindexConfig) {
+		c.target = store
+	}
+}
+
+// WithReindexEmbedder 使用新的嵌入器重建子块，完成后检索也切换到该嵌入器
+// 用于嵌入模型迁移；通常与 WithReindexTarget 一起使用，避免新旧向量混在同一存储中
+func WithReindexEmbedder(embedder vector.Embedder) ReindexOption {
+	return func(c *reindexConfig) {
+		c.embedder = embedder
+	}
+}
+
+// WithReindexProgress 设置进度回调，每个父文档重建完成后调用
+func WithReindexProgress(fn func(done, total int)) ReindexOption {
+	return func(c *reindexConfig) {
+		c.onProgress = fn
+	}
+}
+
+// Reindex 从父文档存储重建所有子块
+//
+// 依次对每个父文档重新分割、向量化并写入子块存储，无需重新获取原始文档，
+// 用于嵌入模型变更或向量存储损坏后的恢复。
+// 重建与 Index 互斥，但不阻塞 Retrieve；检索在重建期间能否看到完整结果取决于
+// 是否使用 WithReindexTarget。
+//
+// context 取消或任一父文档失败时立即返回错误。就地重建此时子块存储只包含已完成的部分，
+// 可以再次调用 Reindex 恢复。
+//
+// 使用示例：
+//
+//	err := retriever.Reindex(ctx,
+//	    retriever.WithReindexTarget(newStore),
+//	    retriever.WithReindexEmbedder(newEmbedder),
+//	    retriever.WithReindexProgress(func(done, total int) {
+//	        log.Printf("reindex %d/%d", done, total)
+//	    }),
+//	)
+func (r *ParentDocRetriever) Reindex(ctx context.Context, opts ...ReindexOption) error {
+	cfg := &reindexConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	r.reindexMu.Lock()
+	defer r.reindexMu.Unlock()
+
+	r.mu.RLock()
+	parents := r.parentStore.List()
+	target, embedder := r.childStore, r.embedder
+	r.mu.RUnlock()
+
+	swap := cfg.target != nil
+	if swap {
+		target = cfg.target
+	}
+	if cfg.embedder != nil {
+		embedder = cfg.embedder
+	}
+
+	if err := target.Clear(ctx); err != nil {
+		return fmt.Errorf("清空子块存储失败: %w", err)
+	}
+
+	childIDs := make(map[string][]string, len(parents))
+	var err error
+	for i, doc := range parents {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		var ids []string
+		if ids, err = r.indexChildren(ctx, target, embedder, doc); err != nil {
+			break
+		}
+		childIDs[doc.ID] = ids
+		if cfg.onProgress != nil {
+			cfg.onProgress(i+1, len(parents))
+		}
+	}
+
+	switch {
+	case err == nil:
+		r.mu.Lock()
+		r.childStore, r.embedder, r.childIDs = target, embedder, childIDs
+		r.mu.Unlock()
+		return nil
+
+	case !swap:
+		// 就地重建的旧子块已被清空，记录已完成部分以保持检索和 GetChildren 一致
+		r.mu.Lock()
+		r.embedder, r.childIDs = embedder, childIDs
+		r.mu.Unlock()
+	}
+	return fmt.Errorf("重建子块失败: %w", err)
+}
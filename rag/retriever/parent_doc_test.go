@@ -1,8 +1,14 @@
 package retriever
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hexagon-codes/hexagon/rag"
 	"github.com/hexagon-codes/hexagon/store/vector"
@@ -194,6 +200,211 @@ func TestParentDocRetriever_Retrieve(t *testing.T) {
 	}
 }
 
+// countingEmbedder 记录 Embed 调用次数的嵌入器
+type countingEmbedder struct {
+	mockEmbedder
+	calls int
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	return e.mockEmbedder.Embed(ctx, texts)
+}
+
+func (e *countingEmbedder) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// gatedEmbedder 查询向量化阻塞直到 release 关闭，模拟慢速嵌入服务
+type gatedEmbedder struct {
+	mockEmbedder
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (e *gatedEmbedder) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	e.once.Do(func() { close(e.started) })
+	select {
+	case <-e.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	embeddings, err := e.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+func TestParentDocRetriever_RetrieveDoesNotBlockIndex(t *testing.T) {
+	store := vector.NewMemoryStore(8)
+	embedder := &gatedEmbedder{
+		mockEmbedder: mockEmbedder{dimension: 8},
+		started:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	r := NewParentDocRetriever(store, embedder)
+
+	ctx := context.Background()
+	if err := r.Index(ctx, []rag.Document{{ID: "p1", Content: "first parent"}}); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	type retrieveResult struct {
+		docs []rag.Document
+		err  error
+	}
+	resultCh := make(chan retrieveResult, 1)
+	go func() {
+		docs, err := r.Retrieve(ctx, "first")
+		resultCh <- retrieveResult{docs, err}
+	}()
+	<-embedder.started
+
+	// 检索阻塞在向量化时，索引和计数不应被阻塞
+	indexDone := make(chan error, 1)
+	go func() {
+		indexDone <- r.Index(ctx, []rag.Document{{ID: "p2", Content: "second parent"}})
+	}()
+	select {
+	case err := <-indexDone:
+		if err != nil {
+			t.Fatalf("Index failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Index blocked by in-flight Retrieve")
+	}
+	if n, _ := r.Count(ctx); n != 2 {
+		t.Errorf("expected 2 parents, got %d", n)
+	}
+
+	close(embedder.release)
+	res := <-resultCh
+	if res.err != nil {
+		t.Fatalf("Retrieve failed: %v", res.err)
+	}
+	if len(res.docs) == 0 {
+		t.Fatal("expected results")
+	}
+
+	// 检索元数据不应写回父文档存储
+	stored, _ := r.GetParentStore().Get("p1")
+	if _, ok := stored.Metadata["retrieval_type"]; ok {
+		t.Error("retrieval metadata leaked into parent store")
+	}
+}
+
+func TestParentDocRetriever_RetrieveBatch(t *testing.T) {
+	store := vector.NewMemoryStore(128)
+	embedder := &countingEmbedder{mockEmbedder: mockEmbedder{dimension: 128}}
+
+	r := NewParentDocRetriever(store, embedder, WithParentTopK(1))
+
+	ctx := context.Background()
+	docs := []rag.Document{
+		{ID: "go-doc", Content: "Go is a programming language designed at Google."},
+		{ID: "python-doc", Content: "Python is a high-level programming language."},
+	}
+	if err := r.Index(ctx, docs); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	queries := []string{
+		"Go is a programming language designed at Google.",
+		"Python is a high-level programming language.",
+	}
+	embedder.calls = 0
+	results, err := r.RetrieveBatch(ctx, queries)
+	if err != nil {
+		t.Fatalf("RetrieveBatch failed: %v", err)
+	}
+	if embedder.calls != 1 {
+		t.Errorf("expected 1 Embed call, got %d", embedder.calls)
+	}
+	if len(results) != len(queries) {
+		t.Fatalf("expected %d result sets, got %d", len(queries), len(results))
+	}
+
+	// 批量结果应与逐条检索一致
+	for i, q := range queries {
+		single, err := r.Retrieve(ctx, q)
+		if err != nil {
+			t.Fatalf("Retrieve failed: %v", err)
+		}
+		if len(single) != len(results[i]) {
+			t.Fatalf("query %d: expected %d docs, got %d", i, len(single), len(results[i]))
+		}
+		for j := range single {
+			if single[j].ID != results[i][j].ID {
+				t.Errorf("query %d: expected doc %s, got %s", i, single[j].ID, results[i][j].ID)
+			}
+		}
+	}
+	if results[0][0].ID != "go-doc" || results[1][0].ID != "python-doc" {
+		t.Errorf("unexpected ordering: %s, %s", results[0][0].ID, results[1][0].ID)
+	}
+
+	// 空查询列表
+	empty, err := r.RetrieveBatch(ctx, nil)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("expected empty result, got %v, %v", empty, err)
+	}
+}
+
+func TestParentDocRetriever_IndexWithDedup(t *testing.T) {
+	store := vector.NewMemoryStore(128)
+	embedder := &countingEmbedder{mockEmbedder: mockEmbedder{dimension: 128}}
+
+	r := NewParentDocRetriever(store, embedder, WithParentDedup(rag.DedupSkip))
+
+	ctx := context.Background()
+	docs := []rag.Document{
+		{Content: "Go is a programming language."},
+		{Content: "Go is a programming language."},
+		{ID: "py", Content: "Python is a programming language."},
+	}
+
+	result, err := r.IndexWithResult(ctx, docs)
+	if err != nil {
+		t.Fatalf("IndexWithResult failed: %v", err)
+	}
+	if *result != (rag.IndexResult{Added: 2, Skipped: 1}) {
+		t.Errorf("unexpected first result: %+v", *result)
+	}
+
+	// 再次索引：全部跳过，不触发向量化
+	embedder.calls = 0
+	result, err = r.IndexWithResult(ctx, docs)
+	if err != nil {
+		t.Fatalf("IndexWithResult failed: %v", err)
+	}
+	if *result != (rag.IndexResult{Skipped: 3}) {
+		t.Errorf("unexpected second result: %+v", *result)
+	}
+	if embedder.calls != 0 {
+		t.Errorf("expected no Embed calls for skipped docs, got %d", embedder.calls)
+	}
+
+	// 同 ID 内容变化视为更新
+	result, err = r.IndexWithResult(ctx, []rag.Document{{ID: "py", Content: "Python 3 is a programming language."}})
+	if err != nil {
+		t.Fatalf("IndexWithResult failed: %v", err)
+	}
+	if *result != (rag.IndexResult{Updated: 1}) {
+		t.Errorf("unexpected update result: %+v", *result)
+	}
+
+	count, _ := r.Count(ctx)
+	if count != 2 {
+		t.Errorf("expected 2 parent docs, got %d", count)
+	}
+}
+
 func TestParentDocRetriever_Clear(t *testing.T) {
 	store := vector.NewMemoryStore(128)
 	embedder := &mockEmbedder{dimension: 128}
@@ -261,6 +472,51 @@ func TestDocumentStore(t *testing.T) {
 	}
 }
 
+func TestDocumentStore_Query(t *testing.T) {
+	store := NewDocumentStore(WithIndex("source"))
+	ids := func(docs []rag.Document) []string {
+		var out []string
+		for _, d := range docs {
+			out = append(out, d.ID)
+		}
+		return out
+	}
+
+	store.Save(rag.Document{ID: "b", Metadata: map[string]any{"source": "manual", "page": 2}})
+	store.Save(rag.Document{ID: "a", Metadata: map[string]any{"source": "manual", "page": 1}})
+	store.Save(rag.Document{ID: "c", Metadata: map[string]any{"source": "wiki", "tags": []string{"x"}}})
+
+	if got := ids(store.Query("source", "manual")); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Query(source=manual) = %v, want [a b]", got)
+	}
+	// 未索引的字段扫描全部文档
+	if got := ids(store.Query("page", 1)); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Query(page=1) = %v, want [a]", got)
+	}
+	if got := store.Query("tags", []string{"x"}); len(got) != 0 {
+		t.Errorf("non-comparable value should not match, got %v", ids(got))
+	}
+
+	// 覆盖保存和删除后索引保持一致
+	store.Save(rag.Document{ID: "b", Metadata: map[string]any{"source": "wiki"}})
+	store.Delete("c")
+	if got := ids(store.Query("source", "manual")); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("after re-save, Query(source=manual) = %v, want [a]", got)
+	}
+	if got := ids(store.Query("source", "wiki")); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("after delete, Query(source=wiki) = %v, want [b]", got)
+	}
+
+	store.Clear()
+	if got := store.Query("source", "manual"); len(got) != 0 {
+		t.Errorf("expected empty result after clear, got %v", ids(got))
+	}
+	store.Save(rag.Document{ID: "d", Metadata: map[string]any{"source": "manual"}})
+	if got := ids(store.Query("source", "manual")); !reflect.DeepEqual(got, []string{"d"}) {
+		t.Errorf("index should keep working after clear, got %v", got)
+	}
+}
+
 func TestGenerateDocID(t *testing.T) {
 	id1 := generateDocID("content1")
 	id2 := generateDocID("content2")
@@ -276,3 +532,470 @@ func TestGenerateDocID(t *testing.T) {
 		t.Errorf("ID should start with 'doc_', got %q", id1)
 	}
 }
+
+func TestIDGenerators(t *testing.T) {
+	if id := generateDocID("content"); len(id) != len("doc_")+32 {
+		t.Errorf("default ID should use 16 hash bytes, got %q", id)
+	}
+	if id := FullHashIDGenerator()("content"); len(id) != len("doc_")+64 {
+		t.Errorf("full hash ID should use 32 bytes, got %q", id)
+	}
+	if id := HashIDGenerator(100)("content"); id != FullHashIDGenerator()("content") {
+		t.Errorf("hash bytes should be clamped to 32, got %q", id)
+	}
+
+	uuid := UUIDIDGenerator()
+	if a, b := uuid("same"), uuid("same"); a == b || len(a) != len("doc_")+36 {
+		t.Errorf("UUID IDs should be unique, got %q and %q", a, b)
+	}
+
+	seq := SequenceIDGenerator("p")
+	if a, b := seq("x"), seq("x"); a != "p_1" || b != "p_2" {
+		t.Errorf("expected p_1, p_2, got %q, %q", a, b)
+	}
+}
+
+func TestParentDocRetriever_WithIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	r := NewParentDocRetriever(vector.NewMemoryStore(128), &mockEmbedder{dimension: 128},
+		WithChildSplitter(&mockSplitter{chunkSize: 100}),
+		WithIDGenerator(SequenceIDGenerator("parent")))
+
+	if err := r.Index(ctx, []rag.Document{{Content: "first"}, {Content: "second"}}); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	for _, id := range []string{"parent_1", "parent_2"} {
+		if _, ok := r.parentStore.Get(id); !ok {
+			t.Errorf("expected parent %s in store", id)
+		}
+	}
+}
+
+// recordingStore 记录每次 Search 请求的 k
+type recordingStore struct {
+	vector.Store
+	ks []int
+}
+
+func (s *recordingStore) Search(ctx context.Context, query []float32, k int, opts ...vector.SearchOption) ([]vector.Document, error) {
+	s.ks = append(s.ks, k)
+	return s.Store.Search(ctx, query, k, opts...)
+}
+
+func TestParentDocRetriever_ChildFetch(t *testing.T) {
+	// p1 切分出 6 个相同子块，占满小的 childTopK
+	docs := []rag.Document{
+		{ID: "p1", Content: "aaaaaaaaaaaaaaaaaaaaaaaa"},
+		{ID: "p2", Content: "abababab"},
+	}
+
+	tests := []struct {
+		name    string
+		opts    []ParentDocOption
+		wantKs  []int
+		parents int
+	}{
+		{"fixed childTopK", nil, []int{2}, 1},
+		{"multiplier", []ParentDocOption{WithChildFetchMultiplier(4)}, []int{8}, 2},
+		{"refetch", []ParentDocOption{WithChildRefetch(10)}, []int{2, 10}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &recordingStore{Store: vector.NewMemoryStore(4)}
+			opts := append([]ParentDocOption{
+				WithChildSplitter(&mockSplitter{chunkSize: 4}),
+				WithChildTopK(2),
+				WithParentTopK(2),
+			}, tt.opts...)
+			r := NewParentDocRetriever(store, &mockEmbedder{dimension: 4}, opts...)
+
+			ctx := context.Background()
+			if err := r.Index(ctx, docs); err != nil {
+				t.Fatalf("Index failed: %v", err)
+			}
+
+			results, err := r.Retrieve(ctx, "aaaa")
+			if err != nil {
+				t.Fatalf("Retrieve failed: %v", err)
+			}
+			if len(results) != tt.parents {
+				t.Errorf("expected %d parents, got %d", tt.parents, len(results))
+			}
+			if len(store.ks) != len(tt.wantKs) {
+				t.Fatalf("expected searches %v, got %v", tt.wantKs, store.ks)
+			}
+			for i, k := range tt.wantKs {
+				if store.ks[i] != k {
+					t.Errorf("search %d: expected k=%d, got %d", i, k, store.ks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParentDocRetriever_GetChildren(t *testing.T) {
+	ctx := context.Background()
+	store := vector.NewMemoryStore(4)
+	parents := NewDocumentStore()
+	r := NewParentDocRetriever(store, &mockEmbedder{dimension: 4},
+		WithChildSplitter(&mockSplitter{chunkSize: 4}),
+		WithParentStore(parents),
+		WithParentDedup(rag.DedupUpdate),
+	)
+
+	if err := r.Index(ctx, []rag.Document{{ID: "p1", Content: "abcddcbaaazz"}}); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	children, err := r.GetChildren(ctx, "p1")
+	if err != nil {
+		t.Fatalf("GetChildren failed: %v", err)
+	}
+	if len(children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(children))
+	}
+	for i, c := range children {
+		if c.Metadata["parent_id"] != "p1" || chunkIndex(c.Metadata) != i {
+			t.Errorf("child %d: unexpected metadata %v", i, c.Metadata)
+		}
+	}
+
+	// 检索结果附带命中的子块
+	results, err := r.Retrieve(ctx, "dcba")
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Retrieve: %v, %d results", err, len(results))
+	}
+	matched, ok := results[0].Metadata[MetadataMatchedChildren].([]MatchedChild)
+	if !ok || len(matched) != 3 {
+		t.Fatalf("expected 3 matched children, got %v", results[0].Metadata[MetadataMatchedChildren])
+	}
+	if matched[0].Content != "dcba" || matched[0].Score < matched[1].Score {
+		t.Errorf("expected best child first, got %+v", matched[0])
+	}
+
+	// 未经本实例索引的父文档通过元数据过滤查找子块
+	other := NewParentDocRetriever(store, &mockEmbedder{dimension: 4}, WithParentStore(parents))
+	children, err = other.GetChildren(ctx, "p1")
+	if err != nil {
+		t.Fatalf("GetChildren fallback failed: %v", err)
+	}
+	if len(children) != 3 || children[2].Content != "aazz" {
+		t.Errorf("unexpected fallback children: %+v", children)
+	}
+
+	if children, _ := r.GetChildren(ctx, "missing"); len(children) != 0 {
+		t.Errorf("expected no children for unknown parent, got %d", len(children))
+	}
+
+	// 重新索引为更少的子块时清理旧子块
+	if err := r.Index(ctx, []rag.Document{{ID: "p1", Content: "dddd"}}); err != nil {
+		t.Fatalf("re-Index failed: %v", err)
+	}
+	if n, _ := store.Count(ctx); n != 1 {
+		t.Errorf("expected stale children to be removed, store has %d", n)
+	}
+}
+
+func TestParentDocRetriever_Stats(t *testing.T) {
+	ctx := context.Background()
+	r := NewParentDocRetriever(vector.NewMemoryStore(4), &mockEmbedder{dimension: 4},
+		WithChildSplitter(&mockSplitter{chunkSize: 4}),
+	)
+
+	if err := r.Index(ctx, []rag.Document{
+		{ID: "p1", Content: "aaaabbbb"},
+		{ID: "p2", Content: "cccc"},
+	}); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	parents, children, err := r.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if parents != 2 || children != 3 {
+		t.Errorf("expected 2 parents and 3 children, got %d and %d", parents, children)
+	}
+
+	if err := r.Clear(ctx); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if parents, children, _ = r.Stats(ctx); parents != 0 || children != 0 {
+		t.Errorf("expected empty stats after clear, got %d and %d", parents, children)
+	}
+}
+
+func TestParentDocRetriever_Delete(t *testing.T) {
+	ctx := context.Background()
+	r := NewParentDocRetriever(vector.NewMemoryStore(4), &mockEmbedder{dimension: 4},
+		WithChildSplitter(&mockSplitter{chunkSize: 4}),
+	)
+
+	if err := r.Index(ctx, []rag.Document{
+		{ID: "p1", Content: "aaaabbbb"},
+		{ID: "p2", Content: "cccc"},
+	}); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	if err := r.Delete(ctx, []string{"p1"}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if parents, children, _ := r.Stats(ctx); parents != 1 || children != 1 {
+		t.Errorf("expected p1 and its children removed, got %d parents and %d children", parents, children)
+	}
+	if children, _ := r.GetChildren(ctx, "p1"); len(children) != 0 {
+		t.Errorf("expected no children for deleted parent, got %d", len(children))
+	}
+	if children, _ := r.GetChildren(ctx, "p2"); len(children) != 1 {
+		t.Errorf("expected p2 children kept, got %d", len(children))
+	}
+}
+
+func TestParentDocRetriever_Reindex(t *testing.T) {
+	ctx := context.Background()
+	store := vector.NewMemoryStore(128)
+	r := NewParentDocRetriever(store, &mockEmbedder{dimension: 128},
+		WithChildSplitter(&mockSplitter{chunkSize: 10}))
+
+	docs := []rag.Document{
+		{ID: "doc1", Content: "The quick brown fox jumps over the lazy dog"},
+		{ID: "doc2", Content: "Go is an open source programming language"},
+	}
+	if err := r.Index(ctx, docs); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	_, wantChildren, _ := r.Stats(ctx)
+
+	t.Run("就地重建", func(t *testing.T) {
+		// 模拟向量存储损坏
+		if err := store.Clear(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		var progress []int
+		err := r.Reindex(ctx, WithReindexProgress(func(done, total int) {
+			if total != 2 {
+				t.Errorf("total = %d, want 2", total)
+			}
+			progress = append(progress, done)
+		}))
+		if err != nil {
+			t.Fatalf("Reindex failed: %v", err)
+		}
+		if len(progress) != 2 || progress[1] != 2 {
+			t.Errorf("progress = %v, want [1 2]", progress)
+		}
+		if _, children, _ := r.Stats(ctx); children != wantChildren {
+			t.Errorf("children = %d, want %d", children, wantChildren)
+		}
+		results, err := r.Retrieve(ctx, "The quick brown fox")
+		if err != nil || len(results) == 0 || results[0].ID != "doc1" {
+			t.Errorf("Retrieve after reindex = %v, %v", results, err)
+		}
+	})
+
+	t.Run("切换到新存储和嵌入器", func(t *testing.T) {
+		newStore := vector.NewMemoryStore(64)
+		if err := r.Reindex(ctx,
+			WithReindexTarget(newStore),
+			WithReindexEmbedder(&mockEmbedder{dimension: 64}),
+		); err != nil {
+			t.Fatalf("Reindex failed: %v", err)
+		}
+		if r.GetChildStore() != newStore {
+			t.Error("child store was not swapped")
+		}
+		if n, _ := newStore.Count(ctx); n != wantChildren {
+			t.Errorf("new store children = %d, want %d", n, wantChildren)
+		}
+		children, err := r.GetChildren(ctx, "doc1")
+		if err != nil || len(children) == 0 || len(children[0].Embedding) != 64 {
+			t.Errorf("GetChildren after swap = %v, %v", children, err)
+		}
+		if _, err := r.Retrieve(ctx, "Go programming"); err != nil {
+			t.Errorf("Retrieve after swap failed: %v", err)
+		}
+	})
+
+	t.Run("取消时不切换", func(t *testing.T) {
+		current := r.GetChildStore()
+		cancelCtx, cancel := context.WithCancel(ctx)
+		err := r.Reindex(cancelCtx,
+			WithReindexTarget(vector.NewMemoryStore(64)),
+			WithReindexProgress(func(done, total int) { cancel() }),
+		)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+		if r.GetChildStore() != current {
+			t.Error("child store swapped after failed reindex")
+		}
+	})
+}
+
+func TestParentDocRetriever_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	src := NewParentDocRetriever(vector.NewMemoryStore(128), &mockEmbedder{dimension: 128},
+		WithChildSplitter(&mockSplitter{chunkSize: 10}),
+		WithParentTopK(3),
+		WithParentDedup(rag.DedupSkip),
+	)
+	docs := []rag.Document{
+		{ID: "doc1", Content: "The quick brown fox jumps over the lazy dog", Metadata: map[string]any{"source": "manual"}},
+		{ID: "doc2", Content: "Go is an open source programming language"},
+	}
+	if err := src.Index(ctx, docs); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(ctx, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	// 目标环境使用新的向量存储，导入后重建子块
+	dst := NewParentDocRetriever(vector.NewMemoryStore(128), &mockEmbedder{dimension: 128},
+		WithChildSplitter(&mockSplitter{chunkSize: 10}),
+		WithParentStore(NewDocumentStore(WithIndex("source"))),
+	)
+	if err := dst.Import(ctx, &buf); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if n, _ := dst.Count(ctx); n != 2 {
+		t.Errorf("Count = %d, want 2", n)
+	}
+	if !reflect.DeepEqual(dst.childIDs, src.childIDs) {
+		t.Errorf("childIDs = %v, want %v", dst.childIDs, src.childIDs)
+	}
+	if dst.parentTopK != 3 || dst.dedup != rag.DedupSkip {
+		t.Errorf("config not restored: parentTopK=%d dedup=%v", dst.parentTopK, dst.dedup)
+	}
+	if got := dst.GetParentStore().Query("source", "manual"); len(got) != 1 || got[0].ID != "doc1" {
+		t.Errorf("parent store index not rebuilt: %v", got)
+	}
+
+	if err := dst.Reindex(ctx); err != nil {
+		t.Fatalf("Reindex failed: %v", err)
+	}
+	results, err := dst.Retrieve(ctx, "The quick brown fox")
+	if err != nil || len(results) == 0 || results[0].ID != "doc1" {
+		t.Errorf("Retrieve after import = %v, %v", results, err)
+	}
+}
+
+func TestParentDocRetriever_ImportVersionMismatch(t *testing.T) {
+	ctx := context.Background()
+	r := NewParentDocRetriever(vector.NewMemoryStore(4), &mockEmbedder{dimension: 4})
+	r.GetParentStore().Save(rag.Document{ID: "keep"})
+
+	err := r.Import(ctx, strings.NewReader(`{"version": 99, "parents": [{"id": "new"}]}`))
+	if !errors.Is(err, ErrSnapshotVersion) {
+		t.Fatalf("expected ErrSnapshotVersion, got %v", err)
+	}
+	if _, ok := r.GetParentStore().Get("keep"); !ok {
+		t.Error("failed import should not modify the retriever")
+	}
+}
+
+func TestParentDocRetriever_InheritedMetadata(t *testing.T) {
+	ctx := context.Background()
+	docs := []rag.Document{
+		{ID: "p1", Content: "aaaabbbb", Source: "chunk", Metadata: map[string]any{"category": "faq", "source": "parent", "owner": "x"}},
+		{ID: "p2", Content: "aaaacccc", Metadata: map[string]any{"category": "blog"}},
+	}
+
+	t.Run("默认继承全部", func(t *testing.T) {
+		r := NewParentDocRetriever(vector.NewMemoryStore(4), &mockEmbedder{dimension: 4},
+			WithChildSplitter(&mockSplitter{chunkSize: 4}))
+		if err := r.Index(ctx, docs); err != nil {
+			t.Fatal(err)
+		}
+		children, err := r.GetChildren(ctx, "p1")
+		if err != nil || len(children) != 2 {
+			t.Fatalf("GetChildren = %v, %v", children, err)
+		}
+		md := children[0].Metadata
+		// 子块自身的键优先于父文档
+		if md["category"] != "faq" || md["owner"] != "x" || md["source"] != "chunk" {
+			t.Errorf("unexpected child metadata: %v", md)
+		}
+
+		results, err := r.Retrieve(ctx, "aaaa", rag.WithFilter(map[string]any{"category": "blog"}))
+		if err != nil || len(results) != 1 || results[0].ID != "p2" {
+			t.Errorf("filtered Retrieve = %v, %v", results, err)
+		}
+	})
+
+	t.Run("只继承指定键", func(t *testing.T) {
+		r := NewParentDocRetriever(vector.NewMemoryStore(4), &mockEmbedder{dimension: 4},
+			WithChildSplitter(&mockSplitter{chunkSize: 4}),
+			WithInheritedMetadata("category"))
+		if err := r.Index(ctx, docs[:1]); err != nil {
+			t.Fatal(err)
+		}
+		children, _ := r.GetChildren(ctx, "p1")
+		if len(children) == 0 {
+			t.Fatal("expected children")
+		}
+		if md := children[0].Metadata; md["category"] != "faq" || md["owner"] != nil || md["parent_id"] != "p1" {
+			t.Errorf("unexpected child metadata: %v", md)
+		}
+	})
+
+	t.Run("移除分割器复制的父文档键", func(t *testing.T) {
+		r := NewParentDocRetriever(vector.NewMemoryStore(4), &mockEmbedder{dimension: 4},
+			WithChildSplitter(copyingSplitter{}),
+			WithInheritedMetadata("category"))
+		if err := r.Index(ctx, docs[:1]); err != nil {
+			t.Fatal(err)
+		}
+		children, _ := r.GetChildren(ctx, "p1")
+		if len(children) != 1 {
+			t.Fatalf("expected 1 child, got %d", len(children))
+		}
+		md := children[0].Metadata
+		if md["category"] != "faq" || md["owner"] != nil || md["source"] != nil || md["section"] != "intro" {
+			t.Errorf("unexpected child metadata: %v", md)
+		}
+	})
+}
+
+// copyingSplitter 像内置分割器一样把父文档元数据复制到子块，并添加自身的键
+type copyingSplitter struct{}
+
+func (copyingSplitter) Name() string { return "copying_splitter" }
+
+func (copyingSplitter) Split(_ context.Context, docs []rag.Document) ([]rag.Document, error) {
+	var result []rag.Document
+	for _, doc := range docs {
+		metadata := map[string]any{"section": "intro"}
+		for k, v := range doc.Metadata {
+			metadata[k] = v
+		}
+		result = append(result, rag.Document{Content: doc.Content, Metadata: metadata})
+	}
+	return result, nil
+}
+
+func TestParentDocRetriever_DimensionCheck(t *testing.T) {
+	ctx := context.Background()
+
+	r := NewParentDocRetriever(vector.NewMemoryStore(128), &mockEmbedder{dimension: 128})
+	if err := r.Validate(ctx); err != nil {
+		t.Fatalf("expected matching dimensions to validate, got %v", err)
+	}
+
+	r = NewParentDocRetriever(vector.NewMemoryStore(384), &mockEmbedder{dimension: 1536})
+	err := r.Validate(ctx)
+	if !errors.Is(err, rag.ErrDimensionMismatch) || !strings.Contains(err.Error(), "embedder dim 1536 != store dim 384") {
+		t.Fatalf("expected dimension mismatch from Validate, got %v", err)
+	}
+	if err := r.Index(ctx, []rag.Document{{ID: "p1", Content: "parent"}}); !errors.Is(err, rag.ErrDimensionMismatch) {
+		t.Fatalf("expected dimension mismatch from Index, got %v", err)
+	}
+	if _, err := r.Retrieve(ctx, "parent"); !errors.Is(err, rag.ErrDimensionMismatch) {
+		t.Fatalf("expected dimension mismatch from Retrieve, got %v", err)
+	}
+}
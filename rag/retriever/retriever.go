@@ -3,6 +3,7 @@
 // Retriever 用于从向量存储中检索相关文档：
 //   - VectorRetriever: 基于向量相似度检索
 //   - KeywordRetriever: 基于关键词检索
+//   - BM25Retriever: 基于倒排索引的 BM25 关键词检索
 //   - HybridRetriever: 混合检索（向量 + 关键词）
 //   - MultiRetriever: 多源检索聚合
 package retriever
@@ -0,0 +1,386 @@
+package retriever
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/rag/splitter"
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// 句子窗口检索使用的元数据键
+const (
+	// MetadataSentenceIndex 句子在原文档中的序号（从 0 开始）
+	MetadataSentenceIndex = "sentence_index"
+
+	// MetadataPrevSentenceID 前一个句子的 ID，首句没有此键
+	MetadataPrevSentenceID = "prev_id"
+
+	// MetadataNextSentenceID 后一个句子的 ID，末句没有此键
+	MetadataNextSentenceID = "next_id"
+
+	// MetadataWindowStart / MetadataWindowEnd 检索结果覆盖的句子序号范围（闭区间）
+	MetadataWindowStart = "window_start"
+	MetadataWindowEnd   = "window_end"
+
+	// MetadataMatchedSentences 检索结果中命中的句子，值类型为 []MatchedChild，按分数降序排列
+	MetadataMatchedSentences = "matched_sentences"
+)
+
+// SentenceWindowRetriever 句子窗口检索器
+// 以单个句子做向量匹配，返回命中句子及其前后若干句组成的窗口
+//
+// 工作原理：
+//  1. 索引时：将文档切分为句子，每个句子单独向量化存储，
+//     元数据中记录所属文档（parent_id）、序号和前后句子的 ID
+//  2. 检索时：检索最相关的句子，沿前后句子 ID 将每个命中扩展为 windowSize 句的窗口
+//  3. 同一文档中重叠或相邻的窗口合并为一个结果，分数取命中句子的最高分
+//
+// 与 ParentDocRetriever 相比粒度更细：匹配更精确，返回的上下文也更紧凑。
+// 参考 LlamaIndex 的 SentenceWindowNodeParser 设计
+//
+// 使用示例：
+//
+//	retriever := NewSentenceWindowRetriever(vectorStore, embedder, WithWindowSize(2))
+//	retriever.Index(ctx, docs)
+//	windows, err := retriever.Retrieve(ctx, "query")
+type SentenceWindowRetriever struct {
+	// store 句子向量存储
+	store vector.Store
+
+	// embedder 向量嵌入器
+	embedder vector.Embedder
+
+	// splitter 句子分割器
+	splitter rag.Splitter
+
+	// windowSize 命中句子前后各扩展的句子数
+	windowSize int
+
+	// topK 检索句子数量
+	topK int
+
+	// minScore 最小相关性分数
+	minScore float32
+}
+
+// SentenceWindowOption SentenceWindowRetriever 配置选项
+type SentenceWindowOption func(*SentenceWindowRetriever)
+
+// WithWindowSize 设置命中句子前后各扩展的句子数
+// 默认值: 2
+func WithWindowSize(n int) SentenceWindowOption {
+	return func(r *SentenceWindowRetriever) {
+		if n >= 0 {
+			r.windowSize = n
+		}
+	}
+}
+
+// WithSentenceSplitter 设置句子分割器，每个输出块视为一个句子
+// 默认使用 splitter.SentenceSplitter 按句子结束符切分
+func WithSentenceSplitter(s rag.Splitter) SentenceWindowOption {
+	return func(r *SentenceWindowRetriever) {
+		r.splitter = s
+	}
+}
+
+// WithSentenceTopK 设置检索句子数量，窗口合并后返回的结果可能更少
+// 默认值: 5
+func WithSentenceTopK(k int) SentenceWindowOption {
+	return func(r *SentenceWindowRetriever) {
+		if k > 0 {
+			r.topK = k
+		}
+	}
+}
+
+// WithSentenceMinScore 设置最小相关性分数
+func WithSentenceMinScore(score float32) SentenceWindowOption {
+	return func(r *SentenceWindowRetriever) {
+		r.minScore = score
+	}
+}
+
+// NewSentenceWindowRetriever 创建句子窗口检索器
+//
+// 参数：
+//   - store: 句子向量存储
+//   - embedder: 向量嵌入器
+//   - opts: 配置选项
+func NewSentenceWindowRetriever(store vector.Store, embedder vector.Embedder, opts ...SentenceWindowOption) *SentenceWindowRetriever {
+	r := &SentenceWindowRetriever{
+		store:    store,
+		embedder: embedder,
+		splitter: splitter.NewSentenceSplitter(
+			splitter.WithSentenceChunkSize(1),
+			splitter.WithSentenceChunkOverlap(0),
+		),
+		windowSize: 2,
+		topK:       5,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Index 索引文档
+// 将文档切分为句子，向量化后连同位置和前后句子 ID 存入向量存储
+func (r *SentenceWindowRetriever) Index(ctx context.Context, docs []rag.Document) error {
+	for _, doc := range docs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if doc.ID == "" {
+			doc.ID = generateDocID(doc.Content)
+		}
+
+		sentences, err := r.splitter.Split(ctx, []rag.Document{doc})
+		if err != nil {
+			return fmt.Errorf("分割文档 %s 失败: %w", doc.ID, err)
+		}
+		if len(sentences) == 0 {
+			continue
+		}
+
+		ids := make([]string, len(sentences))
+		texts := make([]string, len(sentences))
+		for i := range sentences {
+			ids[i] = fmt.Sprintf("%s_sent_%d", doc.ID, i)
+			texts[i] = sentences[i].Content
+		}
+
+		embeddings, err := r.embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("向量化文档 %s 的句子失败: %w", doc.ID, err)
+		}
+
+		now := time.Now()
+		vectorDocs := make([]vector.Document, len(sentences))
+		for i, s := range sentences {
+			metadata := make(map[string]any, len(doc.Metadata)+5)
+			for k, v := range doc.Metadata {
+				metadata[k] = v
+			}
+			if doc.Source != "" {
+				metadata["source"] = doc.Source
+			}
+			metadata["parent_id"] = doc.ID
+			metadata[MetadataSentenceIndex] = i
+			if i > 0 {
+				metadata[MetadataPrevSentenceID] = ids[i-1]
+			}
+			if i < len(sentences)-1 {
+				metadata[MetadataNextSentenceID] = ids[i+1]
+			}
+
+			vectorDocs[i] = vector.Document{
+				ID:        ids[i],
+				Content:   s.Content,
+				Metadata:  metadata,
+				CreatedAt: now,
+			}
+			if i < len(embeddings) {
+				vectorDocs[i].Embedding = embeddings[i]
+			}
+		}
+
+		if err := r.store.Add(ctx, vectorDocs); err != nil {
+			return fmt.Errorf("存储文档 %s 的句子失败: %w", doc.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Retrieve 检索相关句子并扩展为窗口
+// 结果按分数降序排列，Content 为窗口内的句子按原顺序以空格拼接
+func (r *SentenceWindowRetriever) Retrieve(ctx context.Context, query string, opts ...rag.RetrieveOption) ([]rag.Document, error) {
+	cfg := &rag.RetrieveConfig{
+		TopK:     r.topK,
+		MinScore: r.minScore,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	embedding, err := r.embedder.EmbedOne(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("向量化查询失败: %w", err)
+	}
+
+	searchOpts := []vector.SearchOption{
+		vector.WithMinScore(cfg.MinScore),
+		vector.WithMetadata(true),
+	}
+	if cfg.Filter != nil {
+		searchOpts = append(searchOpts, vector.WithFilter(cfg.Filter))
+	}
+	hits, err := r.store.Search(ctx, embedding, cfg.TopK, searchOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("检索句子失败: %w", err)
+	}
+
+	// 沿前后句子 ID 扩展窗口，同一次检索中共享已读取的句子
+	sentences := make(map[string]vector.Document)
+	windows := make(map[string][]*sentenceWindow)
+	var order []string
+	for _, hit := range hits {
+		parentID, _ := hit.Metadata["parent_id"].(string)
+		index := metadataInt(hit.Metadata, MetadataSentenceIndex)
+		if parentID == "" || index < 0 {
+			continue
+		}
+		sentences[hit.ID] = hit
+
+		w := &sentenceWindow{
+			start: index,
+			end:   index,
+			texts: map[int]string{index: hit.Content},
+			score: hit.Score,
+			matched: []MatchedChild{{
+				ID:         hit.ID,
+				ChunkIndex: index,
+				Score:      hit.Score,
+				Content:    hit.Content,
+			}},
+			metadata: hit.Metadata,
+		}
+		if err := r.expand(ctx, w, hit, MetadataPrevSentenceID, sentences); err != nil {
+			return nil, err
+		}
+		if err := r.expand(ctx, w, hit, MetadataNextSentenceID, sentences); err != nil {
+			return nil, err
+		}
+
+		if _, ok := windows[parentID]; !ok {
+			order = append(order, parentID)
+		}
+		windows[parentID] = append(windows[parentID], w)
+	}
+
+	var results []rag.Document
+	for _, parentID := range order {
+		for _, w := range mergeSentenceWindows(windows[parentID]) {
+			results = append(results, w.document(parentID))
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results, nil
+}
+
+// expand 沿 key 指向的相邻句子扩展窗口，最多 windowSize 句
+func (r *SentenceWindowRetriever) expand(ctx context.Context, w *sentenceWindow, from vector.Document, key string, cache map[string]vector.Document) error {
+	current := from
+	for step := 0; step < r.windowSize; step++ {
+		id, _ := current.Metadata[key].(string)
+		if id == "" {
+			return nil
+		}
+
+		next, ok := cache[id]
+		if !ok {
+			doc, err := r.store.Get(ctx, id)
+			if err != nil {
+				return fmt.Errorf("获取句子 %s 失败: %w", id, err)
+			}
+			if doc == nil {
+				return nil
+			}
+			next = *doc
+			cache[id] = next
+		}
+
+		index := metadataInt(next.Metadata, MetadataSentenceIndex)
+		if index < 0 {
+			return nil
+		}
+		w.texts[index] = next.Content
+		w.start = min(w.start, index)
+		w.end = max(w.end, index)
+		current = next
+	}
+	return nil
+}
+
+// Clear 清空所有句子
+func (r *SentenceWindowRetriever) Clear(ctx context.Context) error {
+	return r.store.Clear(ctx)
+}
+
+// sentenceWindow 一个命中句子扩展出的窗口
+type sentenceWindow struct {
+	start, end int
+	texts      map[int]string
+	score      float32
+	matched    []MatchedChild
+	metadata   map[string]any
+}
+
+// mergeSentenceWindows 合并同一文档中重叠或相邻的窗口
+func mergeSentenceWindows(windows []*sentenceWindow) []*sentenceWindow {
+	sort.Slice(windows, func(i, j int) bool { return windows[i].start < windows[j].start })
+
+	merged := []*sentenceWindow{windows[0]}
+	for _, w := range windows[1:] {
+		last := merged[len(merged)-1]
+		if w.start > last.end+1 {
+			merged = append(merged, w)
+			continue
+		}
+		last.end = max(last.end, w.end)
+		for i, text := range w.texts {
+			last.texts[i] = text
+		}
+		last.score = max(last.score, w.score)
+		last.matched = append(last.matched, w.matched...)
+	}
+	return merged
+}
+
+// document 将窗口转换为检索结果
+func (w *sentenceWindow) document(parentID string) rag.Document {
+	parts := make([]string, 0, w.end-w.start+1)
+	for i := w.start; i <= w.end; i++ {
+		if text, ok := w.texts[i]; ok {
+			parts = append(parts, text)
+		}
+	}
+	sort.SliceStable(w.matched, func(i, j int) bool { return w.matched[i].Score > w.matched[j].Score })
+
+	metadata := make(map[string]any, len(w.metadata)+4)
+	for k, v := range w.metadata {
+		switch k {
+		case MetadataSentenceIndex, MetadataPrevSentenceID, MetadataNextSentenceID:
+			continue
+		}
+		metadata[k] = v
+	}
+	metadata["retrieval_type"] = "sentence_window"
+	metadata[MetadataWindowStart] = w.start
+	metadata[MetadataWindowEnd] = w.end
+	metadata[MetadataMatchedSentences] = w.matched
+
+	source, _ := metadata["source"].(string)
+	return rag.Document{
+		ID:       fmt.Sprintf("%s_window_%d_%d", parentID, w.start, w.end),
+		Content:  strings.Join(parts, " "),
+		Metadata: metadata,
+		Score:    w.score,
+		Source:   source,
+	}
+}
+
+// 确保实现了 Retriever 接口
+var _ rag.Retriever = (*SentenceWindowRetriever)(nil)
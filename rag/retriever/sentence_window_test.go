@@ -0,0 +1,93 @@
+package retriever
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/rag"
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// keywordEmbedder 按关键词生成向量，包含 "target" 的文本与查询最相似
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	result := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec := []float32{0.1, 0}
+		if strings.Contains(text, "target") {
+			vec[1] = 1
+		}
+		result[i] = vec
+	}
+	return result, nil
+}
+
+func (e keywordEmbedder) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	v, _ := e.Embed(ctx, []string{text})
+	return v[0], nil
+}
+
+func (keywordEmbedder) Dimension() int { return 2 }
+
+func TestSentenceWindowRetriever(t *testing.T) {
+	ctx := context.Background()
+	r := NewSentenceWindowRetriever(vector.NewMemoryStore(2), keywordEmbedder{}, WithWindowSize(2))
+	err := r.Index(ctx, []rag.Document{{
+		ID:      "doc",
+		Source:  "story.txt",
+		Content: "Zero. One. Two. Three target. Four. Five. Six.",
+	}})
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	results, err := r.Retrieve(ctx, "target", rag.WithTopK(1))
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 window, got %d", len(results))
+	}
+	got := results[0]
+	if got.Content != "One. Two. Three target. Four. Five." {
+		t.Errorf("unexpected window content: %q", got.Content)
+	}
+	if got.Metadata[MetadataWindowStart] != 1 || got.Metadata[MetadataWindowEnd] != 5 {
+		t.Errorf("unexpected window range: %v-%v", got.Metadata[MetadataWindowStart], got.Metadata[MetadataWindowEnd])
+	}
+	if got.Source != "story.txt" || got.Metadata["parent_id"] != "doc" {
+		t.Errorf("expected source and parent id to be kept, got %q %v", got.Source, got.Metadata["parent_id"])
+	}
+	matched := got.Metadata[MetadataMatchedSentences].([]MatchedChild)
+	if len(matched) != 1 || matched[0].ChunkIndex != 3 {
+		t.Errorf("unexpected matched sentences: %+v", matched)
+	}
+}
+
+func TestSentenceWindowRetriever_MergesOverlaps(t *testing.T) {
+	ctx := context.Background()
+	r := NewSentenceWindowRetriever(vector.NewMemoryStore(2), keywordEmbedder{}, WithWindowSize(1))
+	err := r.Index(ctx, []rag.Document{{
+		ID:      "doc",
+		Content: "Zero. One target. Two. Three target. Four. Five. Six.",
+	}})
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	results, err := r.Retrieve(ctx, "target", rag.WithTopK(2))
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected overlapping windows to merge, got %d results", len(results))
+	}
+	if results[0].Content != "Zero. One target. Two. Three target. Four." {
+		t.Errorf("unexpected merged content: %q", results[0].Content)
+	}
+	if matched := results[0].Metadata[MetadataMatchedSentences].([]MatchedChild); len(matched) != 2 {
+		t.Errorf("expected 2 matched sentences, got %d", len(matched))
+	}
+}
@@ -0,0 +1,227 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/tool"
+	"github.com/hexagon-codes/hexagon/core"
+)
+
+// SearchToolInput 知识库检索工具的输入参数
+type SearchToolInput struct {
+	// Query 检索查询
+	Query string `json:"query" desc:"Search query. Rephrase or narrow it and search again if the results are not relevant" required:"true"`
+
+	// TopK 返回的文档数量
+	TopK int `json:"top_k,omitempty" desc:"Number of documents to return"`
+
+	// Filter 元数据过滤条件
+	Filter map[string]any `json:"filter,omitempty" desc:"Only return documents whose metadata fields equal these values"`
+}
+
+// SearchToolOption 检索工具选项
+type SearchToolOption func(*searchTool)
+
+// WithSearchToolName 设置工具名称
+// 默认值: "knowledge_search"
+func WithSearchToolName(name string) SearchToolOption {
+	return func(t *searchTool) {
+		if name != "" {
+			t.name = name
+		}
+	}
+}
+
+// WithSearchToolDescription 设置工具描述，用于告诉模型知识库的内容和使用时机
+func WithSearchToolDescription(desc string) SearchToolOption {
+	return func(t *searchTool) {
+		if desc != "" {
+			t.description = desc
+		}
+	}
+}
+
+// WithSearchToolTopK 设置默认返回数量和模型可请求的上限
+// 默认值: 5, 20
+func WithSearchToolTopK(defaultK, maxK int) SearchToolOption {
+	return func(t *searchTool) {
+		if defaultK > 0 {
+			t.defaultTopK = defaultK
+		}
+		if maxK > 0 {
+			t.maxTopK = maxK
+		}
+		t.defaultTopK = min(t.defaultTopK, t.maxTopK)
+	}
+}
+
+// WithSearchToolMinScore 设置最小相关性分数
+func WithSearchToolMinScore(score float32) SearchToolOption {
+	return func(t *searchTool) {
+		t.minScore = score
+	}
+}
+
+// WithSearchToolFilter 设置固定的元数据过滤条件（如租户隔离）
+// 与模型传入的 filter 合并，同名字段以此处为准，模型无法覆盖
+func WithSearchToolFilter(filter map[string]any) SearchToolOption {
+	return func(t *searchTool) {
+		t.filter = filter
+	}
+}
+
+// WithSearchToolMaxContentLength 设置每个文档在结果中的最大字符数，0 表示不截断
+// 默认值: 2000
+func WithSearchToolMaxContentLength(n int) SearchToolOption {
+	return func(t *searchTool) {
+		t.maxContentLength = max(n, 0)
+	}
+}
+
+// NewSearchTool 将检索器包装为 Agent 可调用的知识库检索工具
+//
+// 与在调用 Agent 前固定检索一次不同，模型可以自行决定何时检索、
+// 改写查询并多次检索（Agentic RAG）。
+//
+// 参数 query 必填，top_k 和 filter 可选（filter 透传为 WithFilter）。
+// 结果按编号列出文档内容和来源，模型可用 [n] 引用：
+//
+//	[1] source: handbook.md (score: 0.87)
+//	...文档内容...
+//
+// 使用示例：
+//
+//	search := rag.NewSearchTool(engine,
+//	    rag.WithSearchToolDescription("Search the product handbook"),
+//	)
+//	a := agent.NewReAct(agent.WithLLM(provider), agent.WithTools(search))
+func NewSearchTool(r Retriever, opts ...SearchToolOption) tool.Tool {
+	t := &searchTool{
+		retriever:        r,
+		name:             "knowledge_search",
+		description:      "Search the knowledge base for documents relevant to a query. Results are numbered; cite them as [n] in your answer.",
+		defaultTopK:      5,
+		maxTopK:          20,
+		maxContentLength: 2000,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// searchTool 知识库检索工具实现
+type searchTool struct {
+	retriever        Retriever
+	name             string
+	description      string
+	defaultTopK      int
+	maxTopK          int
+	minScore         float32
+	filter           map[string]any
+	maxContentLength int
+}
+
+func (t *searchTool) Name() string {
+	return t.name
+}
+
+func (t *searchTool) Description() string {
+	return t.description
+}
+
+func (t *searchTool) Schema() *llm.Schema {
+	return llm.SchemaOf[SearchToolInput]()
+}
+
+func (t *searchTool) Validate(args map[string]any) error {
+	query, _ := args["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("%w: query is required", core.ErrInvalidInput)
+	}
+	if v, ok := args["filter"]; ok && v != nil {
+		if _, ok := v.(map[string]any); !ok {
+			return fmt.Errorf("filter must be an object")
+		}
+	}
+	return nil
+}
+
+func (t *searchTool) Execute(ctx context.Context, args map[string]any) (tool.Result, error) {
+	if err := t.Validate(args); err != nil {
+		return tool.NewErrorResult(err), nil
+	}
+	query, _ := args["query"].(string)
+
+	topK := t.defaultTopK
+	if k, ok := toInt(args["top_k"]); ok && k > 0 {
+		topK = min(k, t.maxTopK)
+	}
+
+	opts := []RetrieveOption{WithTopK(topK)}
+	if t.minScore > 0 {
+		opts = append(opts, WithMinScore(t.minScore))
+	}
+	modelFilter, _ := args["filter"].(map[string]any)
+	if filter := mergeFilters(modelFilter, t.filter); len(filter) > 0 {
+		opts = append(opts, WithFilter(filter))
+	}
+
+	docs, err := t.retriever.Retrieve(ctx, query, opts...)
+	if err != nil {
+		return tool.NewErrorResult(fmt.Errorf("search failed: %w", err)), nil
+	}
+	return tool.NewResult(t.format(docs)), nil
+}
+
+// format 将检索结果格式化为带编号引用的文本
+func (t *searchTool) format(docs []Document) string {
+	if len(docs) == 0 {
+		return "No relevant documents found."
+	}
+
+	var sb strings.Builder
+	for i, doc := range docs {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		fmt.Fprintf(&sb, "[%d] source: %s (score: %.2f)\n", i+1, docSource(doc), doc.Score)
+		content := doc.Content
+		if t.maxContentLength > 0 && len([]rune(content)) > t.maxContentLength {
+			content = string([]rune(content)[:t.maxContentLength]) + "..."
+		}
+		sb.WriteString(content)
+	}
+	return sb.String()
+}
+
+// mergeFilters 合并过滤条件，fixed 中的字段优先
+func mergeFilters(filter, fixed map[string]any) map[string]any {
+	if len(filter) == 0 {
+		return fixed
+	}
+	merged := maps.Clone(filter)
+	maps.Copy(merged, fixed)
+	return merged
+}
+
+// toInt 将 JSON 数字参数转换为 int
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
+
+// 确保实现了 Tool 接口
+var _ tool.Tool = (*searchTool)(nil)
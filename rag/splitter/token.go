@@ -5,6 +5,7 @@
 //   - Tokenizer: 分词器接口，支持 tiktoken/sentencepiece 等
 //   - SimpleTokenizer: 基于空格/标点的简单分词器
 //   - TiktokenTokenizer: 对接 OpenAI tiktoken 的分词器
+//   - CounterTokenizer: 适配框架统一的 tokenizer.Counter
 //
 // 对标 LangChain/LlamaIndex 的 Token-based Splitting。
 //
@@ -28,13 +29,13 @@ package splitter
 
 import (
 	"context"
-	"math"
 	"strings"
 	"time"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/hexagon-codes/hexagon/internal/util"
+	"github.com/hexagon-codes/hexagon/llm/tokenizer"
 	"github.com/hexagon-codes/hexagon/rag"
 )
 
@@ -164,78 +165,22 @@ func (t *SimpleTokenizer) tokenize(text string) []string {
 
 // ============== Tiktoken 分词器 ==============
 
-// tokenRatios 按字符类型的 token 比率
-// 不同的 BPE 编码对不同字符类型有不同的压缩率
-type tokenRatios struct {
-	// asciiWord 英文单词中每个字符的 token 比率（如 "hello" → ~1.3 token）
-	asciiWord float64
-	// cjk CJK 字符（中日韩）每个字符的 token 比率
-	cjk float64
-	// digit 数字每个字符的 token 比率
-	digit float64
-	// punctuation 标点符号每个字符的 token 比率
-	punctuation float64
-	// whitespace 空白字符每个字符的 token 比率
-	whitespace float64
-}
-
-// cl100kRatios cl100k_base 编码的 token 比率（GPT-4, GPT-3.5-turbo 系列）
-// 基于实际 tiktoken 统计的近似值
-var cl100kRatios = tokenRatios{
-	asciiWord:   0.25, // 平均 4 字符 = 1 token
-	cjk:         0.5,  // 平均 2 字符 = 1 token（CJK 通常 1-2 字符/token）
-	digit:       0.33, // 平均 3 位数字 = 1 token
-	punctuation: 1.0,  // 每个标点约 1 token
-	whitespace:  0.25, // 空白通常与前后词合并
-}
-
-// o200kRatios o200k_base 编码的 token 比率（GPT-4o 系列）
-// 更大的词表带来更好的压缩率
-var o200kRatios = tokenRatios{
-	asciiWord:   0.22, // 略优于 cl100k
-	cjk:         0.45, // 略优于 cl100k
-	digit:       0.28,
-	punctuation: 0.9,
-	whitespace:  0.2,
-}
-
-// modelToRatios 模型名到 token 比率的映射
-var modelToRatios = map[string]*tokenRatios{
-	// cl100k_base 模型
-	"gpt-4":                  &cl100kRatios,
-	"gpt-4-turbo":            &cl100kRatios,
-	"gpt-3.5-turbo":          &cl100kRatios,
-	"text-embedding-ada-002": &cl100kRatios,
-	// o200k_base 模型
-	"gpt-4o":      &o200kRatios,
-	"gpt-4o-mini": &o200kRatios,
-}
-
-// TiktokenTokenizer 基于 tiktoken 的分词器
-// 使用按字符类型差异化的 token 比率进行估算。
+// TiktokenTokenizer 按 tiktoken 编码估算的分词器
+// 计数委托给 tokenizer.Estimator，与框架其他模块的 Token 计数保持一致。
 // 无实际 BPE 词表，Encode 降级为 fallback 编码。
-// 如需精确分词，建议集成 Go 原生 tiktoken 库。
+// 如需精确计数，使用 NewCounterTokenizer 包装 tokenizer.NewTiktoken 创建的计数器。
 type TiktokenTokenizer struct {
 	model    string
-	ratios   *tokenRatios
+	counter  *tokenizer.Estimator
 	fallback *SimpleTokenizer
 }
 
 // NewTiktokenTokenizer 创建 tiktoken 分词器
-// 根据模型名自动选择合适的 token 比率
-func NewTiktokenTokenizer(model string) *TiktokenTokenizer {
-	ratios := &cl100kRatios // 默认 cl100k
-	// 按前缀匹配模型
-	for prefix, r := range modelToRatios {
-		if strings.HasPrefix(model, prefix) {
-			ratios = r
-			break
-		}
-	}
-
+// 根据模型名自动选择编码，opts 透传给 tokenizer.NewEstimator
+func NewTiktokenTokenizer(model string, opts ...tokenizer.EstimatorOption) *TiktokenTokenizer {
 	return &TiktokenTokenizer{
 		model:    model,
-		ratios:   ratios,
+		counter:  tokenizer.NewEstimator(model, opts...),
 		fallback: NewSimpleTokenizer(),
 	}
 }
@@ -250,50 +195,60 @@ func (t *TiktokenTokenizer) Decode(tokens []int) string {
 	return t.fallback.Decode(tokens)
 }
 
-// CountTokens 按字符类型差异化估算 token 数
-// 逐字符分类（ASCII 词、CJK、数字、标点、空白），乘以对应比率
+// CountTokens 计算 token 数
 func (t *TiktokenTokenizer) CountTokens(text string) int {
-	if text == "" {
-		return 0
-	}
+	return t.counter.Count(text)
+}
 
-	var asciiChars, cjkChars, digitChars, punctChars, spaceChars int
+// Name 返回名称
+func (t *TiktokenTokenizer) Name() string {
+	return "tiktoken-" + t.model
+}
 
-	for _, r := range text {
-		switch {
-		case unicode.IsSpace(r):
-			spaceChars++
-		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
-			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
-			cjkChars++
-		case unicode.IsDigit(r):
-			digitChars++
-		case unicode.IsPunct(r) || unicode.IsSymbol(r):
-			punctChars++
-		default:
-			asciiChars++
-		}
-	}
+// ============== Counter 适配 ==============
 
-	total := float64(asciiChars)*t.ratios.asciiWord +
-		float64(cjkChars)*t.ratios.cjk +
-		float64(digitChars)*t.ratios.digit +
-		float64(punctChars)*t.ratios.punctuation +
-		float64(spaceChars)*t.ratios.whitespace
+// CounterTokenizer 将 tokenizer.Counter 适配为 Tokenizer
+// 仅用于计数，Encode / Decode 降级为 SimpleTokenizer
+type CounterTokenizer struct {
+	counter  tokenizer.Counter
+	name     string
+	fallback *SimpleTokenizer
+}
 
-	// 至少 1 个 token（非空文本）
-	result := int(math.Ceil(total))
-	if result < 1 {
-		result = 1
+// NewCounterTokenizer 创建基于 Counter 的分词器，counter 为 nil 时使用默认计数器
+func NewCounterTokenizer(counter tokenizer.Counter, name string) *CounterTokenizer {
+	if name == "" {
+		name = "counter"
+	}
+	return &CounterTokenizer{
+		counter:  tokenizer.OrDefault(counter),
+		name:     name,
+		fallback: NewSimpleTokenizer(),
 	}
-	return result
+}
+
+// Encode 编码（降级为 fallback）
+func (t *CounterTokenizer) Encode(text string) []int {
+	return t.fallback.Encode(text)
+}
+
+// Decode 解码
+func (t *CounterTokenizer) Decode(tokens []int) string {
+	return t.fallback.Decode(tokens)
+}
+
+// CountTokens 计算 token 数
+func (t *CounterTokenizer) CountTokens(text string) int {
+	return t.counter.Count(text)
 }
 
 // Name 返回名称
-func (t *TiktokenTokenizer) Name() string {
-	return "tiktoken-" + t.model
+func (t *CounterTokenizer) Name() string {
+	return t.name
 }
 
+var _ Tokenizer = (*CounterTokenizer)(nil)
+
 // 确保 TiktokenTokenizer 实现 Tokenizer 接口
 var _ Tokenizer = (*TiktokenTokenizer)(nil)
 
@@ -332,6 +287,13 @@ func WithTokenizerOpt(tokenizer Tokenizer) TokenSplitterOption {
 	}
 }
 
+// WithTokenCounter 使用框架 Token 计数器计算分块大小
+func WithTokenCounter(counter tokenizer.Counter) TokenSplitterOption {
+	return func(s *TokenSplitter) {
+		s.tokenizer = NewCounterTokenizer(counter, "")
+	}
+}
+
 // WithTokenSeparator 设置首选分割点
 func WithTokenSeparator(sep string) TokenSplitterOption {
 	return func(s *TokenSplitter) {
@@ -0,0 +1,70 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+)
+
+// Transformer 是文档转换器接口
+// 位于加载和索引之间，用于补充派生元数据、过滤或改写文档
+type Transformer interface {
+	// Transform 转换文档
+	Transform(ctx context.Context, docs []Document) ([]Document, error)
+
+	// Name 返回转换器名称
+	Name() string
+}
+
+// TransformerFunc 函数式转换器
+type TransformerFunc func(ctx context.Context, docs []Document) ([]Document, error)
+
+// Transform 实现 Transformer 接口
+func (f TransformerFunc) Transform(ctx context.Context, docs []Document) ([]Document, error) {
+	return f(ctx, docs)
+}
+
+// Name 返回转换器名称
+func (f TransformerFunc) Name() string {
+	return "TransformerFunc"
+}
+
+// CompositeTransformer 复合转换器
+// 按顺序依次执行多个转换器，前一个的输出作为后一个的输入
+type CompositeTransformer struct {
+	transformers []Transformer
+}
+
+// NewCompositeTransformer 创建复合转换器
+func NewCompositeTransformer(transformers ...Transformer) *CompositeTransformer {
+	return &CompositeTransformer{
+		transformers: transformers,
+	}
+}
+
+// Add 追加转换器
+func (c *CompositeTransformer) Add(t Transformer) {
+	c.transformers = append(c.transformers, t)
+}
+
+// Transform 依次执行所有转换器
+func (c *CompositeTransformer) Transform(ctx context.Context, docs []Document) ([]Document, error) {
+	var err error
+	for _, t := range c.transformers {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		docs, err = t.Transform(ctx, docs)
+		if err != nil {
+			return nil, fmt.Errorf("transformer %s failed: %w", t.Name(), err)
+		}
+	}
+	return docs, nil
+}
+
+// Name 返回转换器名称
+func (c *CompositeTransformer) Name() string {
+	return "CompositeTransformer"
+}
+
+var _ Transformer = (*CompositeTransformer)(nil)
+var _ Transformer = TransformerFunc(nil)
@@ -21,6 +21,10 @@ type ProviderSelection struct {
 }
 
 // ProviderSelector selects the primary provider and optional fallback.
+//
+// When a provider call fails, the runner calls Fallback repeatedly, passing the
+// provider that just failed, until a call succeeds or Fallback returns an error
+// (typically ErrNoFallback) to end the chain.
 type ProviderSelector interface {
 	Select(ctx context.Context, req Request) (ProviderSelection, error)
 	Fallback(ctx context.Context, failed ProviderSelection, err error) (ProviderSelection, error)
@@ -27,10 +27,40 @@ type Request struct {
 	ModelName    string
 	Metadata     map[string]any
 	Limits       Limits
+	Sampling     Sampling
 	Strategy     Strategy
 	StreamMode   StreamMode
 }
 
+// MetadataSeed is the completion request metadata key carrying Sampling.Seed.
+// CompletionRequest has no seed field; providers that support seeded sampling read it from metadata.
+const MetadataSeed = "seed"
+
+// SeedSupporter is implemented by providers that read MetadataSeed and pass it to the model.
+// Providers that do not implement it ignore the seed.
+type SeedSupporter interface {
+	SupportsSeed() bool
+}
+
+// SupportsSeed reports whether the provider honors MetadataSeed.
+func SupportsSeed(p llm.Provider) bool {
+	s, ok := p.(SeedSupporter)
+	return ok && s.SupportsSeed()
+}
+
+// Sampling overrides provider sampling parameters for every LLM call of a run.
+// Zero values leave the provider defaults in place.
+type Sampling struct {
+	Temperature *float64
+	MaxTokens   int
+	Seed        *int64
+}
+
+// IsZero reports whether no sampling parameter is overridden.
+func (s Sampling) IsZero() bool {
+	return s.Temperature == nil && s.MaxTokens <= 0 && s.Seed == nil
+}
+
 // Limits constrains a runtime run.
 type Limits struct {
 	MaxTurns int
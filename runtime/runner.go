@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/llm/limiter"
 )
 
 // Runner executes agent requests using a unified state machine.
@@ -127,10 +128,12 @@ func (r *DefaultRunner) runStateMachine(ctx context.Context, req Request, sink E
 		}
 
 		callReq := llm.CompletionRequest{
-			Model:    selection.Model,
-			Messages: state.Messages,
-			Tools:    req.Tools,
-			Metadata: req.Metadata,
+			Model:       selection.Model,
+			Messages:    state.Messages,
+			Tools:       req.Tools,
+			MaxTokens:   req.Sampling.MaxTokens,
+			Temperature: req.Sampling.Temperature,
+			Metadata:    callMetadata(req),
 		}
 		if err := emitter.emit(ctx, Event{
 			Type:  EventLLMStarted,
@@ -144,25 +147,27 @@ func (r *DefaultRunner) runStateMachine(ctx context.Context, req Request, sink E
 			return nil, err
 		}
 		resp, err := r.callProvider(ctx, req, selection, callReq, state, emitter)
-		if err != nil {
+		// Walk the fallback chain until a provider succeeds or the selector has none left.
+		for err != nil {
 			next, fbErr := r.selector.Fallback(ctx, selection, err)
-			if fbErr == nil && next.Provider != nil {
-				_ = emitter.emit(ctx, Event{
-					Type:         EventProviderFallback,
-					State:        state,
-					Error:        err,
-					RuntimeError: runtimeError("provider_call_failed", err),
-					Metadata: map[string]any{
-						"from": selection.Name,
-						"to":   next.Name,
-					},
-				})
-				selection = next
-				state.Attributes["provider"] = selection.Name
-				state.Attributes["model"] = selection.Model
-				callReq.Model = selection.Model
-				resp, err = r.callProvider(ctx, req, selection, callReq, state, emitter)
+			if fbErr != nil || next.Provider == nil {
+				break
 			}
+			_ = emitter.emit(ctx, Event{
+				Type:         EventProviderFallback,
+				State:        state,
+				Error:        err,
+				RuntimeError: runtimeError("provider_call_failed", err),
+				Metadata: map[string]any{
+					"from": selection.Name,
+					"to":   next.Name,
+				},
+			})
+			selection = next
+			state.Attributes["provider"] = selection.Name
+			state.Attributes["model"] = selection.Model
+			callReq.Model = selection.Model
+			resp, err = r.callProvider(ctx, req, selection, callReq, state, emitter)
 		}
 		if err != nil {
 			runErr := fmt.Errorf("llm complete: %w", err)
@@ -182,6 +187,9 @@ func (r *DefaultRunner) runStateMachine(ctx context.Context, req Request, sink E
 		if len(resp.ToolCalls) == 0 {
 			state.Final = true
 			state.FinalText = resp.Content
+			if resp.FinishReason != "" {
+				state.Attributes["finish_reason"] = resp.FinishReason
+			}
 			if err := strategy.AfterLLM(ctx, state); err != nil {
 				_ = emitter.emit(ctx, Event{Type: EventRunFailed, State: state, Error: err, RuntimeError: runtimeError("strategy_after_llm", err)})
 				return nil, err
@@ -229,6 +237,14 @@ func (r *DefaultRunner) runStateMachine(ctx context.Context, req Request, sink E
 }
 
 func (r *DefaultRunner) callProvider(ctx context.Context, req Request, selection ProviderSelection, callReq llm.CompletionRequest, state *State, emitter *runEmitter) (*llm.CompletionResponse, error) {
+	// Respect the process-wide (or context-scoped) LLM limiter; the slot is
+	// held until the stream has been fully consumed.
+	release, err := limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	if req.StreamMode != StreamModeTokens {
 		return selection.Provider.Complete(ctx, callReq)
 	}
@@ -404,3 +420,17 @@ func toolCallRefs(calls []llm.ToolCall) []llm.ToolCallRef {
 	}
 	return refs
 }
+
+// callMetadata returns the request metadata, adding the sampling seed when set.
+// The request metadata is copied so the caller's map is never mutated.
+func callMetadata(req Request) map[string]any {
+	if req.Sampling.Seed == nil {
+		return req.Metadata
+	}
+	metadata := make(map[string]any, len(req.Metadata)+1)
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataSeed] = *req.Sampling.Seed
+	return metadata
+}
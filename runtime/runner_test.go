@@ -173,6 +173,35 @@ func TestRunnerNoToolPath(t *testing.T) {
 	}
 }
 
+func TestRunnerAppliesSampling(t *testing.T) {
+	provider := &fakeProvider{name: "fake", responses: []*llm.CompletionResponse{{Content: "hello"}}}
+	runner := NewRunner(Config{
+		ProviderSelector: StaticProviderSelector{Provider: provider, Name: "fake", Model: "fake-model"},
+	})
+
+	temperature, seed := 0.0, int64(7)
+	metadata := map[string]any{"trace": "t1"}
+	_, err := runner.Run(context.Background(), Request{
+		Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}},
+		Metadata: metadata,
+		Sampling: Sampling{Temperature: &temperature, MaxTokens: 64, Seed: &seed},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	req := provider.lastRequest
+	if req.Temperature == nil || *req.Temperature != 0 || req.MaxTokens != 64 {
+		t.Fatalf("sampling not applied: temperature=%v max_tokens=%d", req.Temperature, req.MaxTokens)
+	}
+	if req.Metadata[MetadataSeed] != int64(7) || req.Metadata["trace"] != "t1" {
+		t.Fatalf("Metadata = %v, want seed and trace", req.Metadata)
+	}
+	if _, ok := metadata[MetadataSeed]; ok {
+		t.Fatal("request metadata must not be mutated")
+	}
+}
+
 type testPrefixStrategy struct{}
 
 func (testPrefixStrategy) Name() string                                      { return "test-prefix" }
@@ -13,6 +13,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/llm/tokenizer"
 	"github.com/hexagon-codes/toolkit/util/rate"
 )
 
@@ -42,6 +44,9 @@ type Controller struct {
 
 	// 定价表（每 1000 Token）
 	pricing map[string]ModelPricing
+
+	// Token 计数器，为 nil 时按模型选择（tokenizer.ForModel）
+	counter tokenizer.Counter
 }
 
 // ModelPricing 模型定价
@@ -140,6 +145,14 @@ func WithPricing(pricing map[string]ModelPricing) ControllerOption {
 	}
 }
 
+// WithTokenCounter 设置预估请求 Token 数使用的计数器
+// 默认按模型通过 tokenizer.ForModel 选择
+func WithTokenCounter(counter tokenizer.Counter) ControllerOption {
+	return func(c *Controller) {
+		c.counter = counter
+	}
+}
+
 // OnBudgetExceeded 设置预算超限回调
 func OnBudgetExceeded(fn func(used, budget float64)) ControllerOption {
 	return func(c *Controller) {
@@ -200,6 +213,20 @@ func (c *Controller) CheckRequest(ctx context.Context, estimatedTokens int64) er
 	return nil
 }
 
+// EstimateTokens 预估请求消息的 Token 数
+func (c *Controller) EstimateTokens(model string, messages []llm.Message) int64 {
+	counter := c.counter
+	if counter == nil {
+		counter = tokenizer.ForModel(model)
+	}
+	return int64(counter.CountMessages(messages))
+}
+
+// CheckMessages 预估请求消息的 Token 数并检查是否可以发起请求
+func (c *Controller) CheckMessages(ctx context.Context, model string, messages []llm.Message) error {
+	return c.CheckRequest(ctx, c.EstimateTokens(model, messages))
+}
+
 // RecordUsage 记录使用量
 //
 // 注意：此方法采用"先检查后扣费"的原子操作模式，确保不会超额消费。
@@ -2,8 +2,11 @@ package cost
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
 )
 
 func TestNewController(t *testing.T) {
@@ -519,3 +522,35 @@ func TestController_ConcurrentAccess(t *testing.T) {
 		}
 	}
 }
+
+func TestControllerCheckMessages(t *testing.T) {
+	c := NewController(WithMaxTokensPerRequest(20))
+
+	short := []llm.Message{{Role: llm.RoleUser, Content: "hello"}}
+	if err := c.CheckMessages(context.Background(), "gpt-4o", short); err != nil {
+		t.Fatalf("expected short request to pass: %v", err)
+	}
+
+	long := []llm.Message{{Role: llm.RoleUser, Content: strings.Repeat("hello world ", 50)}}
+	if err := c.CheckMessages(context.Background(), "gpt-4o", long); err == nil {
+		t.Error("expected long request to exceed per-request limit")
+	}
+
+	fixed := NewController(WithTokenCounter(countFunc(func(string) int { return 100 })))
+	if got := fixed.EstimateTokens("gpt-4o", short); got < 100 {
+		t.Errorf("expected custom counter to be used, got %d", got)
+	}
+}
+
+// countFunc 测试用计数器
+type countFunc func(string) int
+
+func (f countFunc) Count(text string) int { return f(text) }
+
+func (f countFunc) CountMessages(messages []llm.Message) int {
+	total := 0
+	for _, m := range messages {
+		total += f(m.Content)
+	}
+	return total
+}
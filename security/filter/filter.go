@@ -5,7 +5,9 @@ package filter
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"unicode"
@@ -70,6 +72,9 @@ type Finding struct {
 
 	// Category 类别
 	Category string `json:"category"`
+
+	// Action 对该问题执行的动作（敏感词过滤器设置）
+	Action FilterAction `json:"action,omitempty"`
 }
 
 // ContentCategory 内容类别
@@ -100,6 +105,7 @@ const (
 	FindingScam          FindingType = "scam"
 	FindingPersonalInfo  FindingType = "personal_info"
 	FindingMalware       FindingType = "malware"
+	FindingPattern       FindingType = "pattern"
 )
 
 // Severity 严重程度
@@ -121,6 +127,10 @@ const (
 	ActionRedact FilterAction = "redact"
 	ActionBlock  FilterAction = "block"
 	ActionReview FilterAction = "review"
+	// ActionFlag 仅记录发现，不影响过滤结果
+	ActionFlag FilterAction = "flag"
+	// ActionMask 将匹配内容替换为 *，是 ActionRedact 的别名
+	ActionMask = ActionRedact
 )
 
 // FilterConfig 过滤器配置
@@ -170,10 +180,26 @@ type SensitiveWordFilter struct {
 	// Categories 词汇分类
 	categories map[string][]string
 
+	// patterns 正则规则
+	patterns []*patternRule
+
+	// categoryActions 类别动作
+	categoryActions map[string]FilterAction
+
 	mu sync.RWMutex
 }
 
+// patternRule 正则规则
+type patternRule struct {
+	pattern  *regexp.Regexp
+	category string
+	severity Severity
+	action   FilterAction
+}
+
 // SensitiveWord 敏感词
+//
+// Action 为空时依次使用类别动作（SetCategoryAction）和过滤器默认动作
 type SensitiveWord struct {
 	Word     string       `json:"word"`
 	Category string       `json:"category"`
@@ -184,9 +210,10 @@ type SensitiveWord struct {
 // NewSensitiveWordFilter 创建敏感词过滤器
 func NewSensitiveWordFilter(opts ...FilterOption) *SensitiveWordFilter {
 	f := &SensitiveWordFilter{
-		config:     DefaultFilterConfig(),
-		words:      make(map[string]SensitiveWord),
-		categories: make(map[string][]string),
+		config:          DefaultFilterConfig(),
+		words:           make(map[string]SensitiveWord),
+		categories:      make(map[string][]string),
+		categoryActions: make(map[string]FilterAction),
 	}
 
 	for _, opt := range opts {
@@ -242,9 +269,53 @@ func (f *SensitiveWordFilter) AddWord(word, category string, severity Severity)
 		Word:     word,
 		Category: category,
 		Severity: severity,
-		Action:   f.config.Action,
 	}
 	f.categories[category] = append(f.categories[category], word)
+	f.rebuildTrieLocked()
+}
+
+// AddWordWithAction 添加指定动作的敏感词
+func (f *SensitiveWordFilter) AddWordWithAction(word, category string, severity Severity, action FilterAction) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.words[strings.ToLower(word)] = SensitiveWord{
+		Word:     word,
+		Category: category,
+		Severity: severity,
+		Action:   action,
+	}
+	f.categories[category] = append(f.categories[category], word)
+	f.rebuildTrieLocked()
+}
+
+// AddPattern 添加正则规则
+//
+// 匹配在原始内容上进行，需要不区分大小写时请在表达式中使用 (?i)。
+// action 为空时依次使用类别动作和过滤器默认动作。
+func (f *SensitiveWordFilter) AddPattern(pattern, category string, severity Severity, action FilterAction) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patterns = append(f.patterns, &patternRule{
+		pattern:  re,
+		category: category,
+		severity: severity,
+		action:   action,
+	})
+	return nil
+}
+
+// SetCategoryAction 设置类别的处理动作
+// 对该类别下未指定动作的敏感词和正则规则生效
+func (f *SensitiveWordFilter) SetCategoryAction(category string, action FilterAction) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.categoryActions[category] = action
 }
 
 // AddWords 批量添加敏感词
@@ -259,16 +330,11 @@ func (f *SensitiveWordFilter) AddWords(words []string, category string, severity
 			Word:     word,
 			Category: category,
 			Severity: severity,
-			Action:   f.config.Action,
 		}
 		f.categories[category] = append(f.categories[category], word)
 	}
 	// 在持锁状态下重建 trie，确保原子性
-	allWords := make([]string, 0, len(f.words))
-	for w := range f.words {
-		allWords = append(allWords, w)
-	}
-	f.trie = NewACTrie(allWords)
+	f.rebuildTrieLocked()
 	f.mu.Unlock()
 }
 
@@ -280,6 +346,15 @@ func (f *SensitiveWordFilter) RemoveWord(word string) {
 }
 
 // Filter 过滤内容
+//
+// 每条命中的敏感词或正则规则按其动作处理：
+//   - ActionBlock: 结果不通过，Action 为 ActionBlock
+//   - ActionMask (ActionRedact): 在 Filtered 中将匹配内容替换为 *，结果 Action 为 ActionRedact
+//   - ActionFlag 等其他动作: 仅记录到 Findings
+//
+// 未指定动作（且类别也未设置动作）的命中沿用默认动作：默认动作为 ActionRedact 时
+// 总是在 Filtered 中脱敏；只有分数达到阈值时结果才不通过，并将 Action 设为默认动作。
+// 每条 Finding 的 Action 记录实际执行的动作。
 func (f *SensitiveWordFilter) Filter(ctx context.Context, content string) (*FilterResult, error) {
 	result := &FilterResult{
 		Original: content,
@@ -304,68 +379,140 @@ func (f *SensitiveWordFilter) Filter(ctx context.Context, content string) (*Filt
 		}
 	}
 
-	// 使用 Trie 查找敏感词
-	lowerContent := strings.ToLower(content)
-	matches := f.trie.Match(lowerContent)
+	f.mu.RLock()
+	hits := f.collectHits(content)
+	f.mu.RUnlock()
 
-	if len(matches) == 0 {
+	if len(hits) == 0 {
 		return result, nil
 	}
 
 	// 处理匹配结果
 	maxSeverity := SeverityLow
-	filtered := content
-
-	for _, match := range matches {
-		f.mu.RLock()
-		sw, ok := f.words[match.Word]
-		f.mu.RUnlock()
-
-		if !ok {
-			continue
-		}
+	var masks []span
+	blocked, masked := false, false
+	defaultHits := 0
 
-		finding := Finding{
-			Type:     FindingSensitiveWord,
-			Content:  match.Word,
-			Position: match.Position,
-			Length:   len(match.Word),
-			Severity: sw.Severity,
-			Category: sw.Category,
+	for _, h := range hits {
+		if severityLevel(h.finding.Severity) > severityLevel(maxSeverity) {
+			maxSeverity = h.finding.Severity
 		}
-		result.Findings = append(result.Findings, finding)
 
-		// 更新最高严重程度
-		if severityLevel(sw.Severity) > severityLevel(maxSeverity) {
-			maxSeverity = sw.Severity
+		action := h.action
+		if action == "" {
+			defaultHits++
+			action = f.config.Action
 		}
 
-		// 脱敏处理
-		if f.config.Action == ActionRedact {
-			filtered = redactWord(filtered, match.Word, match.Position)
+		finding := h.finding
+		finding.Action = action
+		switch action {
+		case ActionBlock:
+			if h.action != "" {
+				blocked = true
+			}
+		case ActionMask:
+			masks = append(masks, span{start: finding.Position, end: finding.Position + finding.Length})
+			if h.action != "" {
+				masked = true
+			}
+			if finding.Type == FindingPattern {
+				// 不在结果中保留被脱敏的原文
+				finding.Content = strings.Repeat("*", utf8.RuneCountInString(finding.Content))
+			}
 		}
+		result.Findings = append(result.Findings, finding)
 	}
 
 	// 计算分数
-	result.Score = float64(len(matches)) / float64(len(strings.Fields(content))+1)
+	result.Score = float64(len(hits)) / float64(len(strings.Fields(content))+1)
 	if result.Score > 1 {
 		result.Score = 1
 	}
 
 	// 确定动作
-	if result.Score >= f.config.Threshold {
+	defaultScore := float64(defaultHits) / float64(len(strings.Fields(content))+1)
+	switch {
+	case blocked:
+		result.Passed = false
+		result.Action = ActionBlock
+		result.Category = CategorySensitive
+	case defaultHits > 0 && defaultScore >= f.config.Threshold:
 		result.Passed = false
 		result.Action = f.config.Action
 		result.Category = CategorySensitive
+	case masked:
+		result.Action = ActionRedact
 	}
 
-	result.Filtered = filtered
-	result.Metadata["match_count"] = len(matches)
+	result.Filtered = maskSpans(content, masks)
+	result.Metadata["match_count"] = len(hits)
 	result.Metadata["max_severity"] = maxSeverity
 
 	return result, nil
 }
 
+// filterHit 一次命中
+type filterHit struct {
+	finding Finding
+	action  FilterAction // 为空表示使用默认动作
+}
+
+// collectHits 收集敏感词和正则规则的命中（调用方需持有读锁）
+func (f *SensitiveWordFilter) collectHits(content string) []filterHit {
+	var hits []filterHit
+
+	// 使用 Trie 查找敏感词
+	if f.trie != nil {
+		for _, match := range f.trie.Match(strings.ToLower(content)) {
+			sw, ok := f.words[match.Word]
+			if !ok {
+				continue
+			}
+			hits = append(hits, filterHit{
+				finding: Finding{
+					Type:     FindingSensitiveWord,
+					Content:  match.Word,
+					Position: match.Position,
+					Length:   len(match.Word),
+					Severity: sw.Severity,
+					Category: sw.Category,
+				},
+				action: f.ruleAction(sw.Action, sw.Category),
+			})
+		}
+	}
+
+	for _, rule := range f.patterns {
+		for _, loc := range rule.pattern.FindAllStringIndex(content, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			hits = append(hits, filterHit{
+				finding: Finding{
+					Type:     FindingPattern,
+					Content:  content[loc[0]:loc[1]],
+					Position: loc[0],
+					Length:   loc[1] - loc[0],
+					Severity: rule.severity,
+					Category: rule.category,
+				},
+				action: f.ruleAction(rule.action, rule.category),
+			})
+		}
+	}
+
+	return hits
+}
+
+// ruleAction 解析规则动作：规则自身动作优先，其次是类别动作
+func (f *SensitiveWordFilter) ruleAction(action FilterAction, category string) FilterAction {
+	if action != "" {
+		return action
+	}
+	return f.categoryActions[category]
+}
+
 // FilterBatch 批量过滤
 func (f *SensitiveWordFilter) FilterBatch(ctx context.Context, contents []string) ([]*FilterResult, error) {
 	results := make([]*FilterResult, len(contents))
@@ -399,7 +546,11 @@ func (f *SensitiveWordFilter) initDefaultWords() {
 func (f *SensitiveWordFilter) buildTrie() {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	f.rebuildTrieLocked()
+}
 
+// rebuildTrieLocked 根据当前词表重建 Trie（调用方需持有写锁）
+func (f *SensitiveWordFilter) rebuildTrieLocked() {
 	words := make([]string, 0, len(f.words))
 	for word := range f.words {
 		words = append(words, word)
@@ -568,11 +719,31 @@ func categoryToSeverity(c ContentCategory) Severity {
 	}
 }
 
-func redactWord(content, word string, position int) string {
-	// 用 * 替换敏感词，使用字符数（而非字节数）确保中文脱敏正确
-	runeCount := utf8.RuneCountInString(word)
-	replacement := strings.Repeat("*", runeCount)
-	return content[:position] + replacement + content[position+len(word):]
+// span 字节区间 [start, end)
+type span struct {
+	start, end int
+}
+
+// maskSpans 将各区间替换为等字符数的 *，重叠区间会被合并
+func maskSpans(content string, spans []span) string {
+	if len(spans) == 0 {
+		return content
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var sb strings.Builder
+	last := 0
+	for _, sp := range spans {
+		start, end := max(sp.start, last), min(sp.end, len(content))
+		if start >= end {
+			continue
+		}
+		sb.WriteString(content[last:start])
+		sb.WriteString(strings.Repeat("*", utf8.RuneCountInString(content[start:end])))
+		last = end
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
 }
 
 // ============== Toxicity Filter ==============
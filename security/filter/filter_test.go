@@ -152,6 +152,22 @@ func TestSensitiveWordFilter_Filter_Redact(t *testing.T) {
 	}
 }
 
+func TestSensitiveWordFilter_Filter_RedactBelowThreshold(t *testing.T) {
+	f := NewSensitiveWordFilter(WithFilterAction(ActionRedact), WithFilterThreshold(0.9))
+	f.AddWords([]string{"secret"}, "test", SeverityHigh)
+
+	result, err := f.Filter(context.Background(), "This is secret info")
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if !result.Passed || result.Action != ActionAllow {
+		t.Errorf("expected untriggered result with ActionAllow, got passed=%v action=%s", result.Passed, result.Action)
+	}
+	if result.Filtered != "This is ****** info" {
+		t.Errorf("expected default redaction in Filtered, got %q", result.Filtered)
+	}
+}
+
 func TestSensitiveWordFilter_FilterBatch(t *testing.T) {
 	f := NewSensitiveWordFilter()
 	ctx := context.Background()
@@ -466,15 +482,6 @@ func TestSeverityLevel(t *testing.T) {
 	}
 }
 
-func TestRedactWord(t *testing.T) {
-	content := "This is secret data"
-	result := redactWord(content, "secret", 8)
-
-	if result != "This is ****** data" {
-		t.Errorf("redactWord result = %q, want %q", result, "This is ****** data")
-	}
-}
-
 func TestFilterResult(t *testing.T) {
 	result := &FilterResult{
 		Original: "test",
@@ -647,3 +654,49 @@ func TestInterfaceImplementation(t *testing.T) {
 	var _ ContentFilter = (*FilterChain)(nil)
 	var _ ToxicityClassifier = (*RuleBasedClassifier)(nil)
 }
+
+func TestSensitiveWordFilter_Patterns(t *testing.T) {
+	f := NewSensitiveWordFilter()
+	if err := f.AddPattern(`\b\d{4}-\d{4}-\d{4}-\d{4}\b`, "payment", SeverityCritical, ActionMask); err != nil {
+		t.Fatalf("AddPattern failed: %v", err)
+	}
+	if err := f.AddPattern(`(?i)casino`, "spam", SeverityLow, ActionFlag); err != nil {
+		t.Fatalf("AddPattern failed: %v", err)
+	}
+	if err := f.AddPattern(`(`, "broken", SeverityLow, ActionFlag); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+	ctx := context.Background()
+
+	result, err := f.Filter(ctx, "卡号 1234-5678-9012-3456，欢迎来 Casino 玩")
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if !result.Passed {
+		t.Errorf("mask and flag should not fail the result: %+v", result)
+	}
+	if result.Filtered != "卡号 *******************，欢迎来 Casino 玩" {
+		t.Errorf("unexpected filtered text: %q", result.Filtered)
+	}
+	if result.Action != ActionRedact {
+		t.Errorf("expected action=redact, got %s", result.Action)
+	}
+	actions := map[string]FilterAction{}
+	for _, finding := range result.Findings {
+		actions[finding.Category] = finding.Action
+	}
+	if actions["payment"] != ActionMask || actions["spam"] != ActionFlag {
+		t.Errorf("unexpected finding actions: %v", actions)
+	}
+
+	// 类别动作：spam 改为阻止
+	f.AddWordWithAction("lottery", "spam", SeverityMedium, "")
+	f.SetCategoryAction("spam", ActionBlock)
+	result, err = f.Filter(ctx, "win the lottery now, plenty of words here to stay under threshold")
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if result.Passed || result.Action != ActionBlock {
+		t.Errorf("expected blocked result, got passed=%v action=%s", result.Passed, result.Action)
+	}
+}
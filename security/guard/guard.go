@@ -10,6 +10,8 @@
 //   - Guard: 守卫接口，执行安全检查
 //   - GuardChain: 守卫链，按顺序执行多个守卫
 //   - CheckResult: 检查结果，包含通过状态、风险分数和发现的问题
+//   - Transformer: 可选接口，清洗输入（脱敏、移除注入指令）后放行而不是拒绝
+//   - ScanDocuments: 入库前并发扫描一批 RAG 文档
 //
 // 守卫链模式：
 //   - ChainModeAll: 所有守卫都必须通过
@@ -62,6 +64,9 @@ type CheckResult struct {
 
 	// Metadata 额外元数据
 	Metadata map[string]any `json:"metadata,omitempty"`
+
+	// Modified 输入是否被 Transform 改写，改写的内容见 Findings
+	Modified bool `json:"modified,omitempty"`
 }
 
 // Finding 发现的问题
@@ -132,6 +137,12 @@ func (c *GuardChain) Add(g Guard) {
 //
 // 线程安全：在迭代前创建守卫列表的副本
 func (c *GuardChain) Check(ctx context.Context, input string) (*CheckResult, error) {
+	_, result, err := c.run(ctx, input, false)
+	return result, err
+}
+
+// run 按链模式依次执行守卫，transform 为 true 时每个守卫接收上一个守卫清洗后的文本
+func (c *GuardChain) run(ctx context.Context, input string, transform bool) (string, *CheckResult, error) {
 	c.mu.RLock()
 	guards := make([]Guard, len(c.guards))
 	copy(guards, c.guards)
@@ -142,6 +153,8 @@ func (c *GuardChain) Check(ctx context.Context, input string) (*CheckResult, err
 	passedCount := 0
 	enabledCount := 0
 	var lastFailedResult *CheckResult
+	text := input
+	modified := false
 
 	for _, guard := range guards {
 		if !guard.Enabled() {
@@ -149,10 +162,17 @@ func (c *GuardChain) Check(ctx context.Context, input string) (*CheckResult, err
 		}
 		enabledCount++
 
-		result, err := guard.Check(ctx, input)
+		var result *CheckResult
+		var err error
+		if transform {
+			text, result, err = Transform(ctx, guard, text)
+		} else {
+			result, err = guard.Check(ctx, text)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("guard %s failed: %w", guard.Name(), err)
+			return "", nil, fmt.Errorf("guard %s failed: %w", guard.Name(), err)
 		}
+		modified = modified || result.Modified
 
 		allFindings = append(allFindings, result.Findings...)
 		if result.Score > maxScore {
@@ -163,22 +183,24 @@ func (c *GuardChain) Check(ctx context.Context, input string) (*CheckResult, err
 			passedCount++
 			if c.mode == ChainModeAny {
 				// Any 模式：任一通过即可返回成功
-				return &CheckResult{
+				return text, &CheckResult{
 					Passed:   true,
 					Score:    maxScore,
 					Findings: allFindings,
+					Modified: modified,
 				}, nil
 			}
 		} else {
 			lastFailedResult = result
 			if c.mode == ChainModeFirst {
 				// First 模式：第一个失败就停止
-				return &CheckResult{
+				return text, &CheckResult{
 					Passed:   false,
 					Score:    maxScore,
 					Category: result.Category,
 					Reason:   result.Reason,
 					Findings: allFindings,
+					Modified: modified,
 				}, nil
 			}
 		}
@@ -186,7 +208,7 @@ func (c *GuardChain) Check(ctx context.Context, input string) (*CheckResult, err
 
 	// 没有启用的守卫，默认通过
 	if enabledCount == 0 {
-		return &CheckResult{
+		return text, &CheckResult{
 			Passed:   true,
 			Score:    0,
 			Findings: allFindings,
@@ -218,12 +240,13 @@ func (c *GuardChain) Check(ctx context.Context, input string) (*CheckResult, err
 		passed = true
 	}
 
-	return &CheckResult{
+	return text, &CheckResult{
 		Passed:   passed,
 		Score:    maxScore,
 		Category: category,
 		Reason:   reason,
 		Findings: allFindings,
+		Modified: modified,
 	}, nil
 }
 
@@ -302,8 +325,21 @@ func DefaultConfig() *GuardConfig {
 type Middleware func(ctx context.Context, input string, next func(context.Context, string) (string, error)) (string, error)
 
 // ToMiddleware 将守卫转换为中间件
+// ActionRedact 时使用 Transform 清洗输入，将清洗后的文本交给 next
 func ToMiddleware(g Guard, action GuardAction) Middleware {
 	return func(ctx context.Context, input string, next func(context.Context, string) (string, error)) (string, error) {
+		if action == ActionRedact {
+			// 清洗后继续：守卫改写输入，改写后仍未通过时拒绝
+			cleaned, result, err := Transform(ctx, g, input)
+			if err != nil {
+				return "", fmt.Errorf("guard check failed: %w", err)
+			}
+			if !result.Passed {
+				return "", fmt.Errorf("blocked by guard %s: %s", g.Name(), result.Reason)
+			}
+			return next(ctx, cleaned)
+		}
+
 		result, err := g.Check(ctx, input)
 		if err != nil {
 			return "", fmt.Errorf("guard check failed: %w", err)
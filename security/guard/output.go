@@ -0,0 +1,134 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrOutputBlocked 模型输出未通过守卫检查而被拒绝
+var ErrOutputBlocked = errors.New("guard: output blocked")
+
+// OutputChecker 支持输出检查的守卫
+//
+// 输出检查与输入检查的规则可能不同（如原因描述、阈值），
+// 未实现此接口的守卫在输出检查时使用 Check。
+type OutputChecker interface {
+	// CheckOutput 检查模型输出
+	CheckOutput(ctx context.Context, output string) (*CheckResult, error)
+}
+
+// Redactor 支持脱敏的守卫
+type Redactor interface {
+	// Redact 返回脱敏后的文本
+	Redact(text string) string
+}
+
+// CheckOutput 使用守卫检查模型输出
+// 守卫实现了 OutputChecker 时调用 CheckOutput，否则调用 Check
+func CheckOutput(ctx context.Context, g Guard, output string) (*CheckResult, error) {
+	if oc, ok := g.(OutputChecker); ok {
+		return oc.CheckOutput(ctx, output)
+	}
+	return g.Check(ctx, output)
+}
+
+// GuardOutput 检查模型输出并按动作处理
+//
+// 返回处理后的输出和检查结果：
+//   - 通过检查：原样返回
+//   - ActionBlock：返回 ErrOutputBlocked
+//   - ActionRedact：使用守卫的 Redact 脱敏后重新检查，仍未通过（或守卫不支持脱敏）时返回 ErrOutputBlocked
+//   - ActionWarn / ActionLog：原样返回，由调用方根据检查结果记录
+func GuardOutput(ctx context.Context, g Guard, output string, action GuardAction) (string, *CheckResult, error) {
+	result, err := CheckOutput(ctx, g, output)
+	if err != nil {
+		return "", nil, fmt.Errorf("guard %s output check failed: %w", g.Name(), err)
+	}
+	if result.Passed {
+		return output, result, nil
+	}
+
+	switch action {
+	case ActionWarn, ActionLog:
+		return output, result, nil
+	case ActionRedact:
+		r, ok := g.(Redactor)
+		if !ok {
+			break
+		}
+		redacted := r.Redact(output)
+		recheck, err := CheckOutput(ctx, g, redacted)
+		if err != nil {
+			return "", result, fmt.Errorf("guard %s output check failed: %w", g.Name(), err)
+		}
+		if recheck.Passed {
+			return redacted, result, nil
+		}
+		result = recheck
+	}
+
+	return "", result, fmt.Errorf("%w by %s: %s", ErrOutputBlocked, g.Name(), result.Reason)
+}
+
+// CheckOutput 使用链中的守卫检查模型输出
+// 与 Check 的链模式语义相同，但对每个守卫调用 CheckOutput
+func (c *GuardChain) CheckOutput(ctx context.Context, output string) (*CheckResult, error) {
+	c.mu.RLock()
+	guards := make([]Guard, len(c.guards))
+	for i, g := range c.guards {
+		guards[i] = outputGuardAdapter{g}
+	}
+	c.mu.RUnlock()
+
+	return (&GuardChain{guards: guards, mode: c.mode}).Check(ctx, output)
+}
+
+// Redact 依次应用链中所有支持脱敏的守卫
+func (c *GuardChain) Redact(text string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, g := range c.guards {
+		if !g.Enabled() {
+			continue
+		}
+		if r, ok := g.(Redactor); ok {
+			text = r.Redact(text)
+		}
+	}
+	return text
+}
+
+// outputGuardAdapter 将 Check 转发到 CheckOutput
+type outputGuardAdapter struct {
+	Guard
+}
+
+// Check 执行输出检查
+func (a outputGuardAdapter) Check(ctx context.Context, input string) (*CheckResult, error) {
+	return CheckOutput(ctx, a.Guard, input)
+}
+
+// CheckOutput 检查模型输出中的 PII
+func (g *PIIGuard) CheckOutput(ctx context.Context, output string) (*CheckResult, error) {
+	result, err := g.Check(ctx, output)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Passed {
+		result.Reason = "PII detected in output"
+	}
+	return result, nil
+}
+
+// IsOutputGuard 标记为输出守卫
+func (g *PIIGuard) IsOutputGuard() {}
+
+var (
+	_ OutputGuard   = (*PIIGuard)(nil)
+	_ OutputChecker = (*PIIGuard)(nil)
+	_ Redactor      = (*PIIGuard)(nil)
+	_ OutputChecker = (*GuardChain)(nil)
+	_ Redactor      = (*GuardChain)(nil)
+)
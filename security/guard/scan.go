@@ -0,0 +1,106 @@
+package guard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hexagon-codes/hexagon/internal/pool"
+	"github.com/hexagon-codes/hexagon/rag"
+)
+
+// defaultScanConcurrency ScanDocuments 默认的最大并发数
+const defaultScanConcurrency = 8
+
+// ScanResult 单个文档的扫描结果
+type ScanResult struct {
+	// DocumentID 文档 ID
+	DocumentID string `json:"document_id"`
+
+	// Index 文档在输入中的下标
+	Index int `json:"index"`
+
+	// Result 守卫检查结果
+	Result *CheckResult `json:"result"`
+
+	// Content 清洗后的内容，仅在 WithScanTransform 时设置
+	Content string `json:"content,omitempty"`
+}
+
+// Flagged 文档是否未通过检查
+func (r ScanResult) Flagged() bool {
+	return r.Result != nil && !r.Result.Passed
+}
+
+// ScanOption ScanDocuments 配置选项
+type ScanOption func(*scanConfig)
+
+type scanConfig struct {
+	concurrency int
+	transform   bool
+}
+
+// WithScanConcurrency 设置同时扫描的最大文档数，默认 8
+func WithScanConcurrency(n int) ScanOption {
+	return func(c *scanConfig) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithScanTransform 使用 Transform 扫描，结果中附带清洗后的内容
+// 可用于入库前直接替换违规文档的内容
+func WithScanTransform(transform bool) ScanOption {
+	return func(c *scanConfig) {
+		c.transform = transform
+	}
+}
+
+// ScanDocuments 并发使用守卫扫描文档内容，结果按输入顺序返回
+//
+// 用于入库前的批量审核：根据每个文档的 Findings 隔离或脱敏违规文档。
+// 守卫执行出错（而不是检查未通过）时取消其余扫描并返回错误。
+//
+// 使用示例：
+//
+//	chain := guard.NewGuardChain(guard.ChainModeAll, guard.NewPIIGuard(), guard.NewPromptInjectionGuard())
+//	results, err := guard.ScanDocuments(ctx, chain, docs)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, r := range results {
+//	    if r.Flagged() {
+//	        quarantine(docs[r.Index], r.Result.Findings)
+//	    }
+//	}
+func ScanDocuments(ctx context.Context, g Guard, docs []rag.Document, opts ...ScanOption) ([]ScanResult, error) {
+	cfg := &scanConfig{concurrency: defaultScanConcurrency}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	indexes := make([]int, len(docs))
+	for i := range docs {
+		indexes[i] = i
+	}
+
+	results, err := pool.Run(ctx, indexes, cfg.concurrency, func(ctx context.Context, i int) (ScanResult, error) {
+		doc := docs[i]
+		scan := ScanResult{DocumentID: doc.ID, Index: i}
+
+		var err error
+		if cfg.transform {
+			scan.Content, scan.Result, err = Transform(ctx, g, doc.Content)
+		} else {
+			scan.Result, err = g.Check(ctx, doc.Content)
+		}
+		if err != nil {
+			return scan, fmt.Errorf("scan document %q: %w", doc.ID, err)
+		}
+		return scan, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
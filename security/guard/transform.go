@@ -0,0 +1,119 @@
+package guard
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Transformer 支持清洗输入的守卫
+//
+// Check 只给出通过或拒绝，Transform 则返回清洗后的文本（如脱敏 PII、移除注入指令），
+// 调用方用清洗后的文本继续执行，而不是直接拒绝请求。
+// 返回的 CheckResult 描述清洗后文本能否放行：Findings 记录被改写的片段
+// （位置相对原始输入），Modified 表示文本是否被改写。
+type Transformer interface {
+	// Transform 返回清洗后的文本和检查结果
+	Transform(ctx context.Context, input string) (string, *CheckResult, error)
+}
+
+// Transform 使用守卫清洗输入
+// 守卫实现了 Transformer 时调用 Transform，否则调用 Check 并原样返回输入
+//
+// 使用示例：
+//
+//	chain := guard.NewGuardChain(guard.ChainModeAll, guard.NewPIIGuard(), guard.NewPromptInjectionGuard())
+//	cleaned, result, err := guard.Transform(ctx, chain, userInput)
+//	if err != nil || !result.Passed {
+//	    return reject(result)
+//	}
+//	agent.Run(ctx, agent.Input{Query: cleaned})
+func Transform(ctx context.Context, g Guard, input string) (string, *CheckResult, error) {
+	if t, ok := g.(Transformer); ok {
+		return t.Transform(ctx, input)
+	}
+	result, err := g.Check(ctx, input)
+	if err != nil {
+		return "", nil, err
+	}
+	return input, result, nil
+}
+
+// Transform 依次使用链中的守卫清洗输入
+// 每个守卫接收上一个守卫清洗后的文本，链模式语义与 Check 相同
+func (c *GuardChain) Transform(ctx context.Context, input string) (string, *CheckResult, error) {
+	return c.run(ctx, input, true)
+}
+
+// Transform 脱敏输入中的 PII 后放行
+func (g *PIIGuard) Transform(ctx context.Context, input string) (string, *CheckResult, error) {
+	result, err := g.Check(ctx, input)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(result.Findings) == 0 {
+		return input, result, nil
+	}
+
+	cleaned := g.Redact(input)
+	result.Modified = cleaned != input
+	if !result.Passed {
+		result.Passed = true
+		result.Reason = "PII redacted from input"
+	}
+	return cleaned, result, nil
+}
+
+// Transform 移除输入中匹配注入模式的片段，并重新检查移除后的文本
+// 启发式规则命中的内容无法定位，移除后仍可能未通过
+func (g *PromptInjectionGuard) Transform(ctx context.Context, input string) (string, *CheckResult, error) {
+	result, err := g.Check(ctx, input)
+	if err != nil {
+		return "", nil, err
+	}
+	if result.Passed || len(result.Findings) == 0 {
+		return input, result, nil
+	}
+
+	cleaned := removeSpans(input, result.Findings)
+	recheck, err := g.Check(ctx, cleaned)
+	if err != nil {
+		return "", nil, err
+	}
+	recheck.Findings = append(result.Findings, recheck.Findings...)
+	recheck.Modified = cleaned != input
+	if recheck.Passed {
+		recheck.Reason = "Prompt injection removed from input"
+	}
+	return cleaned, recheck, nil
+}
+
+// removeSpans 删除 findings 标记的片段（合并重叠区间），并整理多余空白
+func removeSpans(input string, findings []Finding) string {
+	spans := make([]Position, 0, len(findings))
+	for _, f := range findings {
+		if f.Position.End > f.Position.Start {
+			spans = append(spans, f.Position)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Start < spans[j].Start
+	})
+
+	var sb strings.Builder
+	last := 0
+	for _, span := range spans {
+		if span.Start > last {
+			sb.WriteString(input[last:span.Start])
+		}
+		last = max(last, span.End)
+	}
+	sb.WriteString(input[last:])
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+var (
+	_ Transformer = (*GuardChain)(nil)
+	_ Transformer = (*PIIGuard)(nil)
+	_ Transformer = (*PromptInjectionGuard)(nil)
+)
@@ -0,0 +1,214 @@
+package rbac
+
+import (
+	"sync"
+	"time"
+)
+
+// ============== Audit ==============
+
+// AuditSink 授权决策审计接口
+//
+// Authorize 在每次决策后调用 Record（不持有 RBAC 锁）。
+// 实现需并发安全，且不应阻塞过久；写数据库或文件的实现可自行异步化。
+type AuditSink interface {
+	Record(req AccessRequest, result AccessResult)
+}
+
+// AuditQuerier 支持查询的审计实现
+type AuditQuerier interface {
+	QueryAudit(filter AuditFilter) []AuditRecord
+}
+
+// AuditSinkFunc 函数形式的 AuditSink
+type AuditSinkFunc func(req AccessRequest, result AccessResult)
+
+// Record 实现 AuditSink
+func (f AuditSinkFunc) Record(req AccessRequest, result AccessResult) {
+	f(req, result)
+}
+
+// AuditRecord 审计记录
+type AuditRecord struct {
+	// Timestamp 决策时间
+	Timestamp time.Time `json:"timestamp"`
+
+	// Subject 主体
+	Subject string `json:"subject"`
+
+	// Resource 资源
+	Resource string `json:"resource"`
+
+	// Action 操作
+	Action string `json:"action"`
+
+	// Allowed 是否允许
+	Allowed bool `json:"allowed"`
+
+	// Reason 原因
+	Reason string `json:"reason"`
+
+	// MatchedRole 授予权限的角色
+	MatchedRole string `json:"matched_role,omitempty"`
+
+	// MatchedPermission 匹配的权限
+	MatchedPermission *Permission `json:"matched_permission,omitempty"`
+
+	// MatchedPolicy 匹配的策略
+	MatchedPolicy string `json:"matched_policy,omitempty"`
+}
+
+// NewAuditRecord 根据访问请求和结果创建审计记录
+func NewAuditRecord(req AccessRequest, result AccessResult) AuditRecord {
+	ts := result.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	return AuditRecord{
+		Timestamp:         ts,
+		Subject:           req.Subject,
+		Resource:          req.Resource,
+		Action:            req.Action,
+		Allowed:           result.Allowed,
+		Reason:            result.Reason,
+		MatchedRole:       result.MatchedRole,
+		MatchedPermission: result.MatchedPermission,
+		MatchedPolicy:     result.MatchedPolicy,
+	}
+}
+
+// AuditFilter 审计查询条件，零值字段不参与过滤
+type AuditFilter struct {
+	// Subject 主体
+	Subject string
+
+	// Resource 资源，支持 "tool:*" 形式的前缀通配
+	Resource string
+
+	// Action 操作
+	Action string
+
+	// Allowed 只返回允许（true）或拒绝（false）的记录
+	Allowed *bool
+
+	// Since 起始时间（含）
+	Since time.Time
+
+	// Until 截止时间（不含）
+	Until time.Time
+
+	// Limit 最多返回最近的条数，<= 0 表示不限制
+	Limit int
+}
+
+// Match 判断记录是否满足条件
+func (f AuditFilter) Match(rec AuditRecord) bool {
+	if f.Subject != "" && rec.Subject != f.Subject {
+		return false
+	}
+	if f.Resource != "" && !matchWildcard(f.Resource, rec.Resource) {
+		return false
+	}
+	if f.Action != "" && rec.Action != f.Action {
+		return false
+	}
+	if f.Allowed != nil && rec.Allowed != *f.Allowed {
+		return false
+	}
+	if !f.Since.IsZero() && rec.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !rec.Timestamp.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// MemoryAuditSink 基于环形缓冲区的内存审计
+// 容量满后覆盖最旧的记录
+type MemoryAuditSink struct {
+	mu      sync.RWMutex
+	records []AuditRecord
+	next    int
+	full    bool
+}
+
+// NewMemoryAuditSink 创建内存审计，capacity <= 0 时使用 1000
+func NewMemoryAuditSink(capacity int) *MemoryAuditSink {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &MemoryAuditSink{records: make([]AuditRecord, capacity)}
+}
+
+// Record 实现 AuditSink
+func (s *MemoryAuditSink) Record(req AccessRequest, result AccessResult) {
+	rec := NewAuditRecord(req, result)
+
+	s.mu.Lock()
+	s.records[s.next] = rec
+	s.next = (s.next + 1) % len(s.records)
+	if s.next == 0 {
+		s.full = true
+	}
+	s.mu.Unlock()
+}
+
+// Len 返回当前记录数
+func (s *MemoryAuditSink) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.full {
+		return len(s.records)
+	}
+	return s.next
+}
+
+// QueryAudit 按条件查询审计记录，按时间先后返回
+func (s *MemoryAuditSink) QueryAudit(filter AuditFilter) []AuditRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n, start := s.next, 0
+	if s.full {
+		n, start = len(s.records), s.next
+	}
+
+	var result []AuditRecord
+	// 从最新向最旧遍历，便于应用 Limit
+	for i := n - 1; i >= 0; i-- {
+		rec := s.records[(start+i)%len(s.records)]
+		if !filter.Match(rec) {
+			continue
+		}
+		result = append(result, rec)
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+
+	// 反转为时间先后顺序
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// QueryAudit 查询已设置审计中的授权记录
+// 未设置审计或审计不支持查询（未实现 AuditQuerier）时返回 nil
+func (r *RBAC) QueryAudit(filter AuditFilter) []AuditRecord {
+	r.mu.RLock()
+	sink := r.audit
+	r.mu.RUnlock()
+
+	if q, ok := sink.(AuditQuerier); ok {
+		return q.QueryAudit(filter)
+	}
+	return nil
+}
+
+var (
+	_ AuditSink    = (*MemoryAuditSink)(nil)
+	_ AuditQuerier = (*MemoryAuditSink)(nil)
+	_ AuditSink    = AuditSinkFunc(nil)
+)
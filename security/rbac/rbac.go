@@ -35,6 +35,12 @@ type RBAC struct {
 	// regexCache 缓存编译后的正则表达式，避免 OpMatches 每次重新编译
 	regexCache sync.Map // pattern -> *regexp.Regexp
 
+	// now 当前时间（测试可替换），用于判断角色分配是否过期
+	now func() time.Time
+
+	// audit 授权决策审计，为 nil 时不记录
+	audit AuditSink
+
 	mu sync.RWMutex
 }
 
@@ -62,6 +68,7 @@ func NewRBAC() *RBAC {
 		users:         make(map[string]*User),
 		policies:      make(map[string]*Policy),
 		roleHierarchy: make(map[string][]string),
+		now:           time.Now,
 	}
 
 	// 初始化默认角色
@@ -75,6 +82,13 @@ func (r *RBAC) SetStore(store RBACStore) {
 	r.store = store
 }
 
+// SetAuditSink 设置授权决策审计，传入 nil 关闭审计
+func (r *RBAC) SetAuditSink(sink AuditSink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.audit = sink
+}
+
 // initDefaultRoles 初始化默认角色
 // 此方法在 NewRBAC 构造期间调用，此时 store 尚未设置，
 // 使用 context.Background() 作为初始化上下文。
@@ -341,6 +355,9 @@ type User struct {
 	// Roles 角色列表
 	Roles []string `json:"roles" yaml:"roles"`
 
+	// RoleExpiry 限时角色的过期时间（角色名 -> 过期时间），不在其中的角色永久有效
+	RoleExpiry map[string]time.Time `json:"role_expiry,omitempty" yaml:"role_expiry,omitempty"`
+
 	// Attributes 用户属性
 	Attributes map[string]any `json:"attributes,omitempty" yaml:"attributes,omitempty"`
 
@@ -433,9 +450,31 @@ func (r *RBAC) GetUser(id string) (*User, bool) {
 	return user, ok
 }
 
+// AssignOption 角色分配选项
+type AssignOption func(*assignOptions)
+
+type assignOptions struct {
+	expiresAt time.Time
+}
+
+// WithExpiry 设置角色分配的过期时间
+// 过期后 Authorize 和 GetUserRoles 将忽略该角色，CleanupExpiredRoles 会将其移除
+func WithExpiry(expiresAt time.Time) AssignOption {
+	return func(o *assignOptions) {
+		o.expiresAt = expiresAt
+	}
+}
+
 // AssignRole 分配角色给用户
 // ctx 用于控制 store 持久化操作的超时和取消。
-func (r *RBAC) AssignRole(ctx context.Context, userID, roleName string) error {
+//
+// 重复分配已有角色时以本次的过期设置为准（不带 WithExpiry 即改为永久）。
+func (r *RBAC) AssignRole(ctx context.Context, userID, roleName string, opts ...AssignOption) error {
+	var o assignOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -448,13 +487,29 @@ func (r *RBAC) AssignRole(ctx context.Context, userID, roleName string) error {
 		return fmt.Errorf("role %s not found", roleName)
 	}
 
+	if !o.expiresAt.IsZero() && !o.expiresAt.After(r.now()) {
+		return fmt.Errorf("expiry %s is not in the future", o.expiresAt.Format(time.RFC3339))
+	}
+
+	_, hadExpiry := user.RoleExpiry[roleName]
+	if o.expiresAt.IsZero() {
+		delete(user.RoleExpiry, roleName)
+	} else {
+		if user.RoleExpiry == nil {
+			user.RoleExpiry = make(map[string]time.Time)
+		}
+		user.RoleExpiry[roleName] = o.expiresAt
+	}
+
 	// 检查是否已有该角色
 	if slices.Contains(user.Roles, roleName) {
-		return nil
+		if !hadExpiry && o.expiresAt.IsZero() {
+			return nil
+		}
+	} else {
+		user.Roles = append(user.Roles, roleName)
 	}
 
-	user.Roles = append(user.Roles, roleName)
-
 	if r.store != nil {
 		return r.store.SaveUser(ctx, user)
 	}
@@ -480,6 +535,7 @@ func (r *RBAC) RevokeRole(ctx context.Context, userID, roleName string) error {
 		}
 	}
 	user.Roles = newRoles
+	delete(user.RoleExpiry, roleName)
 
 	if r.store != nil {
 		return r.store.SaveUser(ctx, user)
@@ -504,8 +560,12 @@ func (r *RBAC) getUserRolesLocked(userID string) []string {
 		return nil
 	}
 
+	now := r.now()
 	allRoles := make(map[string]bool)
 	for _, roleName := range user.Roles {
+		if roleExpired(user, roleName, now) {
+			continue
+		}
 		// 使用不加锁的内部方法，因为我们已经持有读锁
 		for _, inherited := range r.getInheritedRolesLocked(roleName) {
 			allRoles[inherited] = true
@@ -519,6 +579,99 @@ func (r *RBAC) getUserRolesLocked(userID string) []string {
 	return result
 }
 
+// RoleAssignment 用户的直接角色分配
+type RoleAssignment struct {
+	// Role 角色名
+	Role string `json:"role"`
+
+	// ExpiresAt 过期时间，nil 表示永久有效
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ListUserRoles 列出用户当前有效（未过期）的直接角色分配，不包括继承的角色
+func (r *RBAC) ListUserRoles(userID string) []RoleAssignment {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return nil
+	}
+
+	now := r.now()
+	result := make([]RoleAssignment, 0, len(user.Roles))
+	for _, roleName := range user.Roles {
+		if roleExpired(user, roleName, now) {
+			continue
+		}
+		a := RoleAssignment{Role: roleName}
+		if exp, ok := user.RoleExpiry[roleName]; ok {
+			a.ExpiresAt = &exp
+		}
+		result = append(result, a)
+	}
+	return result
+}
+
+// CleanupExpiredRoles 移除所有已过期的角色分配，返回移除的数量
+// ctx 用于控制 store 持久化操作的超时和取消。
+func (r *RBAC) CleanupExpiredRoles(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	removed := 0
+	for _, user := range r.users {
+		kept := user.Roles[:0]
+		changed := false
+		for _, roleName := range user.Roles {
+			if roleExpired(user, roleName, now) {
+				delete(user.RoleExpiry, roleName)
+				removed++
+				changed = true
+				continue
+			}
+			kept = append(kept, roleName)
+		}
+		user.Roles = kept
+
+		if changed && r.store != nil {
+			if err := r.store.SaveUser(ctx, user); err != nil {
+				return removed, fmt.Errorf("save user %s: %w", user.ID, err)
+			}
+		}
+	}
+	return removed, nil
+}
+
+// StartExpiryCleanup 在后台按 interval 定期清理过期角色分配，ctx 取消时停止
+// 不调用此方法时过期分配仍会在授权时被忽略，只是不会从用户数据中移除
+// interval <= 0 时返回错误，不启动清理
+func (r *RBAC) StartExpiryCleanup(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid expiry cleanup interval %v: must be positive", interval)
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.CleanupExpiredRoles(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// roleExpired 判断用户的角色分配是否已过期
+func roleExpired(user *User, roleName string, now time.Time) bool {
+	exp, ok := user.RoleExpiry[roleName]
+	return ok && !now.Before(exp)
+}
+
 // ============== Authorization ==============
 
 // AccessRequest 访问请求
@@ -547,8 +700,14 @@ type AccessResult struct {
 	// MatchedPolicy 匹配的策略
 	MatchedPolicy string
 
+	// MatchedRole 授予权限的角色
+	MatchedRole string
+
 	// MatchedPermission 匹配的权限
 	MatchedPermission *Permission
+
+	// Timestamp 决策时间
+	Timestamp time.Time
 }
 
 // Authorize 授权检查
@@ -556,13 +715,26 @@ type AccessResult struct {
 // 安全说明：此方法要求 Subject 必须是已验证的用户 ID。
 // 调用者应该通过 AuthorizeFromContext 方法进行授权检查，
 // 该方法会从 context 中获取已验证的用户身份。
+//
+// 设置了 AuditSink 时，每次决策都会在释放锁后记录到审计。
 func (r *RBAC) Authorize(req AccessRequest) AccessResult {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
+	result := r.authorizeLocked(req)
+	sink := r.audit
+	r.mu.RUnlock()
+
+	if sink != nil {
+		sink.Record(req, result)
+	}
+	return result
+}
 
+// authorizeLocked 执行授权检查（调用者必须已持有读锁）
+func (r *RBAC) authorizeLocked(req AccessRequest) AccessResult {
 	result := AccessResult{
-		Allowed: false,
-		Reason:  "no matching permission",
+		Allowed:   false,
+		Reason:    "no matching permission",
+		Timestamp: r.now(),
 	}
 
 	// 获取用户
@@ -592,6 +764,7 @@ func (r *RBAC) Authorize(req AccessRequest) AccessResult {
 			if r.matchPermission(perm, req) {
 				result.Allowed = true
 				result.Reason = fmt.Sprintf("permitted by role %s", roleName)
+				result.MatchedRole = roleName
 				result.MatchedPermission = &perm
 				return result
 			}
@@ -3,6 +3,7 @@ package rbac
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 func TestNewRBAC(t *testing.T) {
@@ -489,3 +490,108 @@ func TestConditionOperators(t *testing.T) {
 		}
 	}
 }
+
+func TestRBACRoleExpiry(t *testing.T) {
+	rbac := NewRBAC()
+	now := time.Now()
+	rbac.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	rbac.AddUser(ctx, &User{ID: "contractor", Roles: []string{"guest"}})
+
+	if err := rbac.AssignRole(ctx, "contractor", "user", WithExpiry(now.Add(-time.Minute))); err == nil {
+		t.Error("expected error for expiry in the past")
+	}
+	if err := rbac.AssignRole(ctx, "contractor", "user", WithExpiry(now.Add(7*24*time.Hour))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := AccessRequest{Subject: "contractor", Resource: "agent", Action: "run"}
+	if !rbac.Authorize(req).Allowed {
+		t.Error("expected temporary role to grant access")
+	}
+	assignments := rbac.ListUserRoles("contractor")
+	if len(assignments) != 2 || assignments[1].Role != "user" || assignments[1].ExpiresAt == nil {
+		t.Errorf("unexpected assignments: %+v", assignments)
+	}
+
+	// 一周后过期
+	now = now.Add(8 * 24 * time.Hour)
+	if rbac.Authorize(req).Allowed {
+		t.Error("expected expired role to be ignored")
+	}
+	if assignments := rbac.ListUserRoles("contractor"); len(assignments) != 1 || assignments[0].Role != "guest" {
+		t.Errorf("expected only guest to remain active, got %+v", assignments)
+	}
+
+	removed, err := rbac.CleanupExpiredRoles(ctx)
+	if err != nil || removed != 1 {
+		t.Errorf("expected 1 removed, got %d, %v", removed, err)
+	}
+	user, _ := rbac.GetUser("contractor")
+	if len(user.Roles) != 1 || len(user.RoleExpiry) != 0 {
+		t.Errorf("expected expired grant to be removed, got %+v", user)
+	}
+}
+
+func TestRBACStartExpiryCleanup(t *testing.T) {
+	rbac := NewRBAC()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := rbac.StartExpiryCleanup(ctx, interval); err == nil {
+			t.Errorf("expected error for interval %v", interval)
+		}
+	}
+	if err := rbac.StartExpiryCleanup(ctx, time.Hour); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRBACAuditSink(t *testing.T) {
+	rbac := NewRBAC()
+	ctx := context.Background()
+	rbac.AddUser(ctx, &User{ID: "alice", Roles: []string{"user"}})
+
+	sink := NewMemoryAuditSink(2)
+	rbac.SetAuditSink(sink)
+
+	rbac.Authorize(AccessRequest{Subject: "alice", Resource: "agent", Action: "run"})
+	rbac.Authorize(AccessRequest{Subject: "alice", Resource: "admin", Action: "write"})
+	rbac.Authorize(AccessRequest{Subject: "bob", Resource: "agent", Action: "run"})
+
+	// 容量为 2，最旧的记录被覆盖
+	if sink.Len() != 2 {
+		t.Fatalf("expected 2 records, got %d", sink.Len())
+	}
+	records := sink.QueryAudit(AuditFilter{})
+	if records[0].Resource != "admin" || records[1].Subject != "bob" {
+		t.Errorf("unexpected records: %+v", records)
+	}
+
+	sink = NewMemoryAuditSink(10)
+	rbac.SetAuditSink(sink)
+	rbac.Authorize(AccessRequest{Subject: "alice", Resource: "agent", Action: "run"})
+	rbac.Authorize(AccessRequest{Subject: "alice", Resource: "admin", Action: "write"})
+
+	allowed := true
+	records = sink.QueryAudit(AuditFilter{Subject: "alice", Allowed: &allowed})
+	if len(records) != 1 {
+		t.Fatalf("expected 1 allowed record, got %d", len(records))
+	}
+	rec := records[0]
+	if rec.MatchedRole != "user" || rec.MatchedPermission == nil || rec.Timestamp.IsZero() {
+		t.Errorf("expected matched role, permission and timestamp, got %+v", rec)
+	}
+
+	if got := rbac.QueryAudit(AuditFilter{Resource: "adm*"}); len(got) != 1 || got[0].Allowed {
+		t.Errorf("expected one denied admin record, got %+v", got)
+	}
+
+	rbac.SetAuditSink(nil)
+	rbac.Authorize(AccessRequest{Subject: "alice", Resource: "agent", Action: "run"})
+	if sink.Len() != 2 {
+		t.Error("expected no records after removing sink")
+	}
+}
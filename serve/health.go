@@ -0,0 +1,226 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// ============== 健康检查 ==============
+
+// 健康检查端点路径
+const (
+	// LivenessPath 存活探针，进程能响应即返回 200
+	LivenessPath = "/healthz"
+
+	// ReadinessPath 就绪探针，所有依赖检查通过时返回 200，否则返回 503
+	ReadinessPath = "/readyz"
+)
+
+// defaultReadinessTimeout 单个依赖检查的默认超时
+const defaultReadinessTimeout = 3 * time.Second
+
+// HealthCheck 依赖检查函数，返回 nil 表示依赖可用
+type HealthCheck func(ctx context.Context) error
+
+// readinessCheck 已注册的依赖检查
+type readinessCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// CheckStatus 单个依赖检查的结果
+type CheckStatus struct {
+	// Status "ok" 或 "failed"
+	Status string `json:"status"`
+
+	// DurationMs 检查耗时（毫秒）
+	DurationMs int64 `json:"duration_ms"`
+
+	// Error 失败原因
+	Error string `json:"error,omitempty"`
+}
+
+// ReadinessReport /readyz 的响应
+type ReadinessReport struct {
+	// Status "ready" 或 "unavailable"
+	Status string `json:"status"`
+
+	// Checks 依赖名称 -> 检查结果
+	Checks map[string]CheckStatus `json:"checks,omitempty"`
+
+	// Failed 未通过的依赖名称，按名称排序
+	Failed []string `json:"failed,omitempty"`
+}
+
+// WithReadinessCheck 注册 /readyz 的依赖检查
+//
+// 各检查并发执行，每个检查有独立的超时（见 WithReadinessTimeout），
+// 一个依赖变慢不会拖垮其他检查。同名检查后注册的覆盖先注册的。
+//
+// 示例：
+//
+//	srv := serve.NewChatServer(myAgent,
+//	    serve.WithReadinessCheck("embedder", serve.EmbedderCheck(embedder)),
+//	    serve.WithReadinessCheck("vector_store", serve.VectorStoreCheck(store)),
+//	    serve.WithReadinessCheck("llm_key", serve.EnvCheck("OPENAI_API_KEY")),
+//	)
+func WithReadinessCheck(name string, check HealthCheck) Option {
+	return func(o *options) {
+		if name == "" || check == nil {
+			return
+		}
+		for i, c := range o.readinessChecks {
+			if c.name == name {
+				o.readinessChecks[i].check = check
+				return
+			}
+		}
+		o.readinessChecks = append(o.readinessChecks, readinessCheck{name: name, check: check})
+	}
+}
+
+// WithReadinessTimeout 设置单个依赖检查的超时时间
+// 默认值: 3s
+func WithReadinessTimeout(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.readinessTimeout = d
+		}
+	}
+}
+
+// EmbedderCheck 检查嵌入器能否向量化一段测试文本
+func EmbedderCheck(e vector.Embedder) HealthCheck {
+	return func(ctx context.Context) error {
+		embeddings, err := e.Embed(ctx, []string{"health check"})
+		if err != nil {
+			return err
+		}
+		if len(embeddings) != 1 || len(embeddings[0]) == 0 {
+			return errors.New("embedder returned no vector")
+		}
+		return nil
+	}
+}
+
+// VectorStoreCheck 检查向量存储能否访问
+func VectorStoreCheck(s vector.Store) HealthCheck {
+	return func(ctx context.Context) error {
+		_, err := s.Count(ctx)
+		return err
+	}
+}
+
+// EnvCheck 检查环境变量均已设置且非空，如 LLM 的 API Key
+func EnvCheck(keys ...string) HealthCheck {
+	return func(ctx context.Context) error {
+		var missing []string
+		for _, key := range keys {
+			if os.Getenv(key) == "" {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing environment variables: %v", missing)
+		}
+		return nil
+	}
+}
+
+// handleLiveness 存活探针
+// GET /healthz
+func (s *ChatServer) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadiness 就绪探针
+// GET /readyz
+func (s *ChatServer) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	closing := s.closing
+	s.mu.Unlock()
+	if closing {
+		writeJSON(w, http.StatusServiceUnavailable, ReadinessReport{Status: "unavailable", Failed: []string{"server"}})
+		return
+	}
+
+	report := s.Ready(r.Context())
+	status := http.StatusOK
+	if len(report.Failed) > 0 {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// Ready 并发执行所有依赖检查并返回就绪报告
+func (s *ChatServer) Ready(ctx context.Context) ReadinessReport {
+	checks := s.options.readinessChecks
+	report := ReadinessReport{Status: "ready"}
+	if len(checks) == 0 {
+		return report
+	}
+
+	report.Checks = make(map[string]CheckStatus, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := runCheck(ctx, c.check, s.options.readinessTimeout)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.name] = status
+			if status.Error != "" {
+				report.Failed = append(report.Failed, c.name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(report.Failed) > 0 {
+		report.Status = "unavailable"
+		sort.Strings(report.Failed)
+	}
+	return report
+}
+
+// runCheck 在独立超时内执行检查，检查不响应 ctx 时也按超时返回
+func runCheck(ctx context.Context, check HealthCheck, timeout time.Duration) CheckStatus {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	status := CheckStatus{Status: "ok", DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		status.Status = "failed"
+		status.Error = err.Error()
+	}
+	return status
+}
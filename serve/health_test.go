@@ -0,0 +1,89 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func getReadiness(t *testing.T, url string) (int, ReadinessReport) {
+	t.Helper()
+	resp, err := http.Get(url + ReadinessPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var report ReadinessReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, report
+}
+
+func TestChatServer_Health(t *testing.T) {
+	t.Setenv("HEXAGON_TEST_KEY", "sk-test")
+	srv := newChatServer(t, newEchoAgent(mock.FixedProvider("hi")),
+		WithReadinessTimeout(50*time.Millisecond),
+		WithReadinessCheck("llm_key", EnvCheck("HEXAGON_TEST_KEY")),
+		WithReadinessCheck("db", func(ctx context.Context) error { return nil }),
+	)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + LivenessPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("liveness status = %d", resp.StatusCode)
+	}
+
+	if code, report := getReadiness(t, ts.URL); code != http.StatusOK || report.Status != "ready" || len(report.Checks) != 2 {
+		t.Errorf("readiness = %d %+v", code, report)
+	}
+}
+
+func TestChatServer_NotReady(t *testing.T) {
+	srv := newChatServer(t, newEchoAgent(mock.FixedProvider("hi")),
+		WithReadinessTimeout(20*time.Millisecond),
+		WithReadinessCheck("llm_key", EnvCheck("HEXAGON_TEST_MISSING_KEY")),
+		WithReadinessCheck("vector_store", func(ctx context.Context) error { return errors.New("connection refused") }),
+		// 不响应 ctx 的检查按超时失败，不阻塞其他检查
+		WithReadinessCheck("slow", func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}),
+		WithReadinessCheck("ok", func(ctx context.Context) error { return nil }),
+	)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	start := time.Now()
+	code, report := getReadiness(t, ts.URL)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("readiness took %v, checks should time out independently", elapsed)
+	}
+	if code != http.StatusServiceUnavailable || report.Status != "unavailable" {
+		t.Errorf("readiness = %d %+v", code, report)
+	}
+	if want := []string{"llm_key", "slow", "vector_store"}; !slices.Equal(report.Failed, want) {
+		t.Errorf("Failed = %v, want %v", report.Failed, want)
+	}
+	if report.Checks["ok"].Status != "ok" || report.Checks["vector_store"].Error != "connection refused" {
+		t.Errorf("unexpected checks: %+v", report.Checks)
+	}
+
+	// 关闭中的服务器不再就绪
+	_ = srv.Shutdown(context.Background())
+	if code, _ := getReadiness(t, ts.URL); code != http.StatusServiceUnavailable {
+		t.Errorf("readiness during shutdown = %d", code)
+	}
+}
@@ -0,0 +1,542 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/core"
+	"github.com/hexagon-codes/hexagon/internal/util"
+)
+
+// ============== OpenAI 兼容接口 ==============
+//
+// OpenAIHandler 以 OpenAI Chat Completions API 的格式暴露 Agent，
+// 现有的 OpenAI SDK、工具和前端无需修改即可接入：
+//
+//	POST {base}/chat/completions   聊天补全（支持 stream: true）
+//	GET  {base}/models             列出可用模型
+//
+// 请求映射：
+//   - model 选择 WithModel 注册的 Agent，未注册时使用默认 Agent
+//   - 最后一条 user 消息作为 agent.Input.Query
+//   - system / developer 消息作为本次运行的附加系统指令（agent.ContextWithInstructions）
+//   - 其余之前的消息作为本次请求的历史记忆（通过 agent.ContextWithMemory 注入），服务端不保存会话状态；
+//     Agent 读取历史的条数由其自身决定（ReActAgent 为最近 10 条）。
+//     Agent 不支持按请求注入记忆（agent.SupportsContextMemory）时，带历史的请求返回 400
+//   - temperature、seed、max_tokens（max_completion_tokens）作为单次运行选项
+//   - 工具由 Agent 在服务端执行，不支持客户端工具：请求中的 tools、tool_choice、functions、
+//     function_call 以及 response_format、n > 1 返回 400；其余采样参数忽略
+//
+// 响应映射：
+//   - Agent 回复作为 assistant 消息内容；流式时逐块发送 LLM 生成的文本增量，
+//     包括调用工具前的中间回复。不支持 Token 回调或配置了输出守卫、输出解析器的 Agent
+//     在结束时一次发送经过处理的完整回复
+//   - Agent 在服务端执行过的工具调用放在非标准字段 executed_tool_calls 中，仅供展示；
+//     标准的 tool_calls 始终为空，避免 OpenAI SDK 客户端将其当作待执行的调用再执行一次
+//   - finish_reason 为 LLM 的结束原因 length 或 content_filter，其余情况为 "stop"
+
+// ChatCompletionRequest OpenAI 聊天补全请求
+type ChatCompletionRequest struct {
+	Model               string                  `json:"model"`
+	Messages            []ChatCompletionMessage `json:"messages"`
+	Stream              bool                    `json:"stream,omitempty"`
+	StreamOptions       *StreamOptions          `json:"stream_options,omitempty"`
+	User                string                  `json:"user,omitempty"`
+	Temperature         *float64                `json:"temperature,omitempty"`
+	Seed                *int64                  `json:"seed,omitempty"`
+	MaxTokens           *int                    `json:"max_tokens,omitempty"`
+	MaxCompletionTokens *int                    `json:"max_completion_tokens,omitempty"`
+	N                   *int                    `json:"n,omitempty"`
+
+	// 以下字段不受支持，非空时返回 400
+	Tools          json.RawMessage `json:"tools,omitempty"`
+	ToolChoice     json.RawMessage `json:"tool_choice,omitempty"`
+	Functions      json.RawMessage `json:"functions,omitempty"`
+	FunctionCall   json.RawMessage `json:"function_call,omitempty"`
+	ResponseFormat json.RawMessage `json:"response_format,omitempty"`
+}
+
+// StreamOptions 流式选项
+type StreamOptions struct {
+	// IncludeUsage 在 [DONE] 之前额外发送一个包含 usage 的块
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// ChatCompletionMessage OpenAI 消息
+//
+// 请求中 Content 可以是字符串或内容片段数组（仅使用 text 片段）
+type ChatCompletionMessage struct {
+	Role       string                   `json:"role,omitempty"`
+	Content    any                      `json:"content,omitempty"`
+	Name       string                   `json:"name,omitempty"`
+	ToolCalls  []ChatCompletionToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string                   `json:"tool_call_id,omitempty"`
+
+	// ExecutedToolCalls 非标准字段：Agent 在服务端已执行的工具调用，仅出现在响应中
+	ExecutedToolCalls []ChatCompletionToolCall `json:"executed_tool_calls,omitempty"`
+}
+
+// ChatCompletionToolCall OpenAI 工具调用
+type ChatCompletionToolCall struct {
+	// Index 流式增量中的工具调用序号
+	Index    *int                       `json:"index,omitempty"`
+	ID       string                     `json:"id,omitempty"`
+	Type     string                     `json:"type,omitempty"`
+	Function ChatCompletionFunctionCall `json:"function"`
+}
+
+// ChatCompletionFunctionCall 函数调用
+type ChatCompletionFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatCompletionResponse 聊天补全响应（object 为 chat.completion 或 chat.completion.chunk）
+type ChatCompletionResponse struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []ChatCompletionChoice `json:"choices"`
+	Usage   *ChatCompletionUsage   `json:"usage,omitempty"`
+}
+
+// ChatCompletionChoice 补全选项，非流式使用 Message，流式使用 Delta
+type ChatCompletionChoice struct {
+	Index        int                    `json:"index"`
+	Message      *ChatCompletionMessage `json:"message,omitempty"`
+	Delta        *ChatCompletionMessage `json:"delta,omitempty"`
+	FinishReason *string                `json:"finish_reason"`
+}
+
+// ChatCompletionUsage Token 使用统计
+type ChatCompletionUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// OpenAIHandler OpenAI 兼容的 HTTP 处理器
+type OpenAIHandler struct {
+	defaultAgent agent.Agent
+	models       map[string]agent.Agent
+	modelOrder   []string
+	basePath     string
+	mux          *http.ServeMux
+}
+
+// OpenAIOption OpenAIHandler 配置选项
+type OpenAIOption func(*OpenAIHandler)
+
+// WithModel 将请求中的 model 名称映射到 Agent
+// 可多次调用注册多个模型，/models 按注册顺序列出
+func WithModel(name string, a agent.Agent) OpenAIOption {
+	return func(h *OpenAIHandler) {
+		if name == "" || a == nil {
+			return
+		}
+		if _, ok := h.models[name]; !ok {
+			h.modelOrder = append(h.modelOrder, name)
+		}
+		h.models[name] = a
+	}
+}
+
+// WithBasePath 设置 API 路径前缀
+// 默认值: "/v1"
+func WithBasePath(path string) OpenAIOption {
+	return func(h *OpenAIHandler) {
+		h.basePath = "/" + strings.Trim(path, "/")
+		if h.basePath == "/" {
+			h.basePath = ""
+		}
+	}
+}
+
+// NewOpenAIHandler 创建 OpenAI 兼容处理器
+//
+// 参数：
+//   - defaultAgent: model 未注册时使用的 Agent，为 nil 时未注册的 model 返回 404
+//   - opts: 配置选项
+func NewOpenAIHandler(defaultAgent agent.Agent, opts ...OpenAIOption) *OpenAIHandler {
+	h := &OpenAIHandler{
+		defaultAgent: defaultAgent,
+		models:       make(map[string]agent.Agent),
+		basePath:     "/v1",
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux = http.NewServeMux()
+	h.mux.HandleFunc(h.basePath+"/chat/completions", h.handleChatCompletions)
+	h.mux.HandleFunc(h.basePath+"/models", h.handleModels)
+	return h
+}
+
+// ServeHTTP 实现 http.Handler
+func (h *OpenAIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// resolve 根据 model 选择 Agent，返回 Agent 和响应中使用的模型名
+func (h *OpenAIHandler) resolve(model string) (agent.Agent, string) {
+	if a, ok := h.models[model]; ok {
+		return a, model
+	}
+	if h.defaultAgent == nil {
+		return nil, model
+	}
+	if model == "" {
+		model = h.defaultAgent.Name()
+	}
+	return h.defaultAgent, model
+}
+
+// handleModels 列出模型
+// GET {base}/models
+func (h *OpenAIHandler) handleModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeOpenAIError(w, http.StatusMethodNotAllowed, "invalid_request_error", "", "method not allowed")
+		return
+	}
+
+	names := append([]string(nil), h.modelOrder...)
+	if h.defaultAgent != nil {
+		if _, ok := h.models[h.defaultAgent.Name()]; !ok {
+			names = append(names, h.defaultAgent.Name())
+		}
+	}
+	data := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		data = append(data, map[string]any{
+			"id":       name,
+			"object":   "model",
+			"created":  0,
+			"owned_by": "hexagon",
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data})
+}
+
+// handleChatCompletions 处理聊天补全
+// POST {base}/chat/completions
+func (h *OpenAIHandler) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeOpenAIError(w, http.StatusMethodNotAllowed, "invalid_request_error", "", "method not allowed")
+		return
+	}
+
+	var req ChatCompletionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<20)).Decode(&req); err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", "", "invalid request body: "+err.Error())
+		return
+	}
+
+	if param := unsupportedParam(req); param != "" {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", "unsupported_parameter",
+			fmt.Sprintf("%s is not supported: tools run on the server", param))
+		return
+	}
+
+	a, model := h.resolve(req.Model)
+	if a == nil {
+		writeOpenAIError(w, http.StatusNotFound, "invalid_request_error", "model_not_found",
+			fmt.Sprintf("the model %q does not exist", req.Model))
+		return
+	}
+
+	input, instructions, history, err := toAgentInput(req)
+	if err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", "", err.Error())
+		return
+	}
+
+	ctx := agent.ContextWithRunOptions(r.Context(), runOptions(req)...)
+	if instructions != "" {
+		ctx = agent.ContextWithInstructions(ctx, instructions)
+	}
+	if agent.SupportsContextMemory(a) {
+		// 每个请求使用独立的记忆，避免写入 Agent 配置的共享记忆
+		ctx = agent.ContextWithMemory(ctx, history)
+	} else if history.Stats().EntryCount > 0 {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", "unsupported_parameter",
+			fmt.Sprintf("the model %q does not support conversation history; send a single user message", model))
+		return
+	}
+
+	id := util.GenerateID("chatcmpl")
+	created := time.Now().Unix()
+	if req.Stream {
+		h.stream(ctx, w, a, input, req, id, model, created)
+		return
+	}
+
+	output, err := a.Run(ctx, input)
+	if err != nil {
+		writeOpenAIError(w, http.StatusInternalServerError, "server_error", "", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, ChatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: created,
+		Model:   model,
+		Choices: []ChatCompletionChoice{{
+			Message: &ChatCompletionMessage{
+				Role:              "assistant",
+				Content:           output.Content,
+				ExecutedToolCalls: toOpenAIToolCalls(output.ToolCalls),
+			},
+			FinishReason: outputFinishReason(output),
+		}},
+		Usage: toOpenAIUsage(output),
+	})
+}
+
+// stream 以 chat.completion.chunk 格式流式返回
+func (h *OpenAIHandler) stream(ctx context.Context, w http.ResponseWriter, a agent.Agent, input agent.Input, req ChatCompletionRequest, id, model string, created int64) {
+	sse, ok := newSSEWriter(w)
+	if !ok {
+		writeOpenAIError(w, http.StatusInternalServerError, "server_error", "", "streaming not supported")
+		return
+	}
+
+	chunk := func(delta *ChatCompletionMessage, finish *string) ChatCompletionResponse {
+		return ChatCompletionResponse{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   model,
+			Choices: []ChatCompletionChoice{{Delta: delta, FinishReason: finish}},
+		}
+	}
+
+	if err := sse.sendData(chunk(&ChatCompletionMessage{Role: "assistant", Content: ""}, nil)); err != nil {
+		return
+	}
+
+	streamed := false
+	ctx = agent.ContextWithTokenHandler(ctx, func(content string) error {
+		streamed = true
+		return sse.sendData(chunk(&ChatCompletionMessage{Content: content}, nil))
+	})
+	output, err := a.Run(ctx, input)
+	if err != nil {
+		_ = sse.sendData(openAIError("server_error", "", err.Error()))
+		_ = sse.sendRaw([]byte("[DONE]"))
+		return
+	}
+
+	// 未逐 Token 推送时（见 agent.ContextWithTokenHandler）在结束时一次发送完整回复
+	if !streamed && output.Content != "" {
+		if err := sse.sendData(chunk(&ChatCompletionMessage{Content: output.Content}, nil)); err != nil {
+			return
+		}
+	}
+	if calls := toOpenAIToolCalls(output.ToolCalls); len(calls) > 0 {
+		if err := sse.sendData(chunk(&ChatCompletionMessage{ExecutedToolCalls: calls}, nil)); err != nil {
+			return
+		}
+	}
+	if err := sse.sendData(chunk(&ChatCompletionMessage{}, outputFinishReason(output))); err != nil {
+		return
+	}
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		final := chunk(nil, nil)
+		final.Choices = []ChatCompletionChoice{}
+		final.Usage = toOpenAIUsage(output)
+		if err := sse.sendData(final); err != nil {
+			return
+		}
+	}
+	_ = sse.sendRaw([]byte("[DONE]"))
+}
+
+// toAgentInput 将 OpenAI 消息转换为 Agent 输入
+// 最后一条消息必须来自 user，作为 Query；system / developer 消息合并为附加系统指令，
+// 其余之前的消息写入一个仅用于本次请求的记忆
+func toAgentInput(req ChatCompletionRequest) (agent.Input, string, memory.Memory, error) {
+	if len(req.Messages) == 0 {
+		return agent.Input{}, "", nil, fmt.Errorf("messages is required")
+	}
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role != "user" {
+		return agent.Input{}, "", nil, fmt.Errorf("last message must have role \"user\", got %q", last.Role)
+	}
+	query, err := messageText(last.Content)
+	if err != nil {
+		return agent.Input{}, "", nil, fmt.Errorf("messages[%d]: %w", len(req.Messages)-1, err)
+	}
+
+	prior := req.Messages[:len(req.Messages)-1]
+	history := memory.NewBuffer(max(len(prior), 1))
+	var instructions []string
+	for i, msg := range prior {
+		content, err := messageText(msg.Content)
+		if err != nil {
+			return agent.Input{}, "", nil, fmt.Errorf("messages[%d]: %w", i, err)
+		}
+		switch msg.Role {
+		case "system", "developer":
+			if content != "" {
+				instructions = append(instructions, content)
+			}
+			continue
+		case "user", "tool":
+		case "assistant":
+			// 历史中的工具调用以文本形式保留，与 Agent 写入记忆的格式一致
+			var sb strings.Builder
+			for _, tc := range msg.ToolCalls {
+				fmt.Fprintf(&sb, "Called %s: %s\n", tc.Function.Name, tc.Function.Arguments)
+			}
+			content = sb.String() + content
+		default:
+			return agent.Input{}, "", nil, fmt.Errorf("messages[%d]: unsupported role %q", i, msg.Role)
+		}
+		if content == "" {
+			continue
+		}
+		// 写入内存 Buffer 不会失败
+		_ = history.Save(context.Background(), memory.NewEntry(msg.Role, content))
+	}
+
+	input := agent.Input{Query: query}
+	if req.User != "" {
+		input.Context = map[string]any{"user": req.User}
+	}
+	return input, strings.Join(instructions, "\n\n"), history, nil
+}
+
+// unsupportedParam 返回请求中第一个不受支持的参数名，全部支持时返回空字符串
+func unsupportedParam(req ChatCompletionRequest) string {
+	for _, p := range []struct {
+		name string
+		raw  json.RawMessage
+	}{
+		{"tools", req.Tools},
+		{"tool_choice", req.ToolChoice},
+		{"functions", req.Functions},
+		{"function_call", req.FunctionCall},
+		{"response_format", req.ResponseFormat},
+	} {
+		switch strings.TrimSpace(string(p.raw)) {
+		case "", "null", "[]":
+		default:
+			return p.name
+		}
+	}
+	if req.N != nil && *req.N > 1 {
+		return "n"
+	}
+	return ""
+}
+
+// runOptions 将请求中的采样参数转换为单次运行选项
+func runOptions(req ChatCompletionRequest) []core.Option {
+	var opts []core.Option
+	if req.Temperature != nil {
+		opts = append(opts, agent.WithRunTemperature(*req.Temperature))
+	}
+	if req.Seed != nil {
+		opts = append(opts, agent.WithRunSeed(*req.Seed))
+	}
+	if n := req.MaxCompletionTokens; n != nil {
+		opts = append(opts, agent.WithRunMaxTokens(*n))
+	} else if n := req.MaxTokens; n != nil {
+		opts = append(opts, agent.WithRunMaxTokens(*n))
+	}
+	return opts
+}
+
+// messageText 提取消息文本：字符串直接返回，片段数组拼接所有 text 片段
+func messageText(content any) (string, error) {
+	switch c := content.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return c, nil
+	case []any:
+		var parts []string
+		for _, p := range c {
+			part, ok := p.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("invalid content part")
+			}
+			if part["type"] != "text" {
+				return "", fmt.Errorf("unsupported content part type %v", part["type"])
+			}
+			text, _ := part["text"].(string)
+			parts = append(parts, text)
+		}
+		return strings.Join(parts, "\n"), nil
+	default:
+		return "", fmt.Errorf("content must be a string or an array of content parts")
+	}
+}
+
+// toOpenAIToolCalls 将 Agent 的工具调用记录转换为 OpenAI 工具调用格式
+func toOpenAIToolCalls(records []agent.ToolCallRecord) []ChatCompletionToolCall {
+	if len(records) == 0 {
+		return nil
+	}
+	calls := make([]ChatCompletionToolCall, len(records))
+	for i, rec := range records {
+		args, err := json.Marshal(rec.Arguments)
+		if err != nil || rec.Arguments == nil {
+			args = []byte("{}")
+		}
+		calls[i] = ChatCompletionToolCall{
+			ID:   util.GenerateID("call"),
+			Type: "function",
+			Function: ChatCompletionFunctionCall{
+				Name:      rec.Name,
+				Arguments: string(args),
+			},
+		}
+	}
+	return calls
+}
+
+// toOpenAIUsage 转换 Token 使用统计
+func toOpenAIUsage(output agent.Output) *ChatCompletionUsage {
+	return &ChatCompletionUsage{
+		PromptTokens:     output.Usage.PromptTokens,
+		CompletionTokens: output.Usage.CompletionTokens,
+		TotalTokens:      output.Usage.TotalTokens,
+	}
+}
+
+func finishReason(reason string) *string {
+	return &reason
+}
+
+// outputFinishReason 返回 Agent 输出对应的 finish_reason
+// LLM 因长度或内容过滤截断时原样返回；其余为 "stop"，工具调用已在服务端完成
+func outputFinishReason(output agent.Output) *string {
+	switch reason, _ := output.Metadata[agent.MetadataFinishReason].(string); reason {
+	case "length", "content_filter":
+		return finishReason(reason)
+	}
+	return finishReason("stop")
+}
+
+// openAIError 构造 OpenAI 格式的错误体
+func openAIError(errType, code, message string) map[string]any {
+	body := map[string]any{"message": message, "type": errType}
+	if code != "" {
+		body["code"] = code
+	}
+	return map[string]any{"error": body}
+}
+
+// writeOpenAIError 写入 OpenAI 格式的错误响应
+func writeOpenAIError(w http.ResponseWriter, status int, errType, code, message string) {
+	writeJSON(w, status, openAIError(errType, code, message))
+}
@@ -0,0 +1,244 @@
+package serve
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+func postOpenAI(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url+"/v1/chat/completions", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	return resp
+}
+
+func TestOpenAIHandler_Completion(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").
+		AddToolCallResponse([]llm.ToolCall{{ID: "1", Name: "search", Arguments: `{"q":"go"}`}}).
+		AddResponse("answer")
+	a := agent.NewReAct(agent.WithName("assistant"), agent.WithLLM(provider), agent.WithTools(mock.NewTool("search")))
+	ts := httptest.NewServer(NewOpenAIHandler(a))
+	defer ts.Close()
+
+	resp := postOpenAI(t, ts.URL, `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var out ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Object != "chat.completion" || out.Model != "gpt-4o" || !strings.HasPrefix(out.ID, "chatcmpl-") {
+		t.Errorf("unexpected envelope: %+v", out)
+	}
+	if len(out.Choices) != 1 || out.Choices[0].Message.Content != "answer" || *out.Choices[0].FinishReason != "stop" {
+		t.Fatalf("unexpected choices: %+v", out.Choices)
+	}
+	// 服务端已执行的调用不能出现在标准 tool_calls 中，否则客户端会再执行一次
+	msg := out.Choices[0].Message
+	if len(msg.ToolCalls) != 0 {
+		t.Errorf("expected no tool_calls for server-executed tools, got %+v", msg.ToolCalls)
+	}
+	calls := msg.ExecutedToolCalls
+	if len(calls) != 1 || calls[0].Function.Name != "search" || calls[0].Function.Arguments != `{"q":"go"}` {
+		t.Errorf("expected executed tool call, got %+v", calls)
+	}
+}
+
+func TestOpenAIHandler_Stream(t *testing.T) {
+	a := agent.NewReAct(agent.WithLLM(mock.NewLLMProvider("mock").AddResponse("hello")))
+	ts := httptest.NewServer(NewOpenAIHandler(a))
+	defer ts.Close()
+
+	resp := postOpenAI(t, ts.URL, `{"messages":[{"role":"user","content":"hi"}],"stream":true,"stream_options":{"include_usage":true}}`)
+	defer resp.Body.Close()
+
+	var chunks []ChatCompletionResponse
+	done := false
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			done = true
+			break
+		}
+		var chunk ChatCompletionResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatalf("invalid chunk %q: %v", data, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	if !done {
+		t.Fatal("expected [DONE] terminator")
+	}
+
+	var content strings.Builder
+	finished := false
+	for _, c := range chunks {
+		if c.Object != "chat.completion.chunk" {
+			t.Errorf("unexpected object %q", c.Object)
+		}
+		for _, choice := range c.Choices {
+			if s, ok := choice.Delta.Content.(string); ok {
+				content.WriteString(s)
+			}
+			if choice.FinishReason != nil && *choice.FinishReason == "stop" {
+				finished = true
+			}
+		}
+	}
+	if content.String() != "hello" || !finished {
+		t.Errorf("expected streamed content and stop, got %q finished=%v", content.String(), finished)
+	}
+	if last := chunks[len(chunks)-1]; last.Usage == nil || len(last.Choices) != 0 {
+		t.Errorf("expected trailing usage chunk, got %+v", last)
+	}
+}
+
+func TestOpenAIHandler_History(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").AddResponse("ok")
+	a := agent.NewReAct(agent.WithLLM(provider))
+	ts := httptest.NewServer(NewOpenAIHandler(a))
+	defer ts.Close()
+
+	body := `{"messages":[
+		{"role":"user","content":"my name is Ada"},
+		{"role":"assistant","content":"hello Ada"},
+		{"role":"user","content":[{"type":"text","text":"what is my name?"}]}
+	]}`
+	resp := postOpenAI(t, ts.URL, body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	messages := provider.LastCall().Messages
+	var roles []string
+	for _, m := range messages[1:] {
+		roles = append(roles, string(m.Role)+":"+m.Content)
+	}
+	want := []string{"user:my name is Ada", "assistant:hello Ada", "user:what is my name?"}
+	if strings.Join(roles, "|") != strings.Join(want, "|") {
+		t.Errorf("expected history %v, got %v", want, roles)
+	}
+}
+
+func TestOpenAIHandler_SystemAndSampling(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").AddResponse("ok")
+	a := agent.NewReAct(agent.WithLLM(provider), agent.WithSystemPrompt("You are helpful."))
+	ts := httptest.NewServer(NewOpenAIHandler(a))
+	defer ts.Close()
+
+	body := `{"temperature":0.2,"max_tokens":64,"messages":[
+		{"role":"system","content":"Answer in French."},
+		{"role":"user","content":"hi"}
+	]}`
+	resp := postOpenAI(t, ts.URL, body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	call := provider.LastCall()
+	if len(call.Messages) != 2 || call.Messages[0].Content != "You are helpful.\n\nAnswer in French." {
+		t.Errorf("expected system message as instructions, got %+v", call.Messages)
+	}
+	if call.Temperature == nil || *call.Temperature != 0.2 || call.MaxTokens != 64 {
+		t.Errorf("expected sampling options, got temperature=%v max_tokens=%d", call.Temperature, call.MaxTokens)
+	}
+}
+
+func TestOpenAIHandler_FinishReason(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		return &llm.CompletionResponse{Content: "trunc", FinishReason: "length"}, nil
+	})
+	ts := httptest.NewServer(NewOpenAIHandler(agent.NewReAct(agent.WithLLM(provider))))
+	defer ts.Close()
+
+	resp := postOpenAI(t, ts.URL, `{"messages":[{"role":"user","content":"hi"}]}`)
+	defer resp.Body.Close()
+	var out ChatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Choices) != 1 || *out.Choices[0].FinishReason != "length" {
+		t.Errorf("expected finish_reason length, got %+v", out.Choices)
+	}
+}
+
+func TestOpenAIHandler_ModelSelection(t *testing.T) {
+	fast := agent.NewReAct(agent.WithLLM(mock.NewLLMProvider("fast").AddResponse("fast")))
+	smart := agent.NewReAct(agent.WithLLM(mock.NewLLMProvider("smart").AddResponse("smart")))
+	ts := httptest.NewServer(NewOpenAIHandler(nil, WithModel("fast", fast), WithModel("smart", smart)))
+	defer ts.Close()
+
+	resp := postOpenAI(t, ts.URL, `{"model":"smart","messages":[{"role":"user","content":"hi"}]}`)
+	var out ChatCompletionResponse
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	resp.Body.Close()
+	if len(out.Choices) != 1 || out.Choices[0].Message.Content != "smart" {
+		t.Errorf("expected smart agent, got %+v", out.Choices)
+	}
+
+	resp = postOpenAI(t, ts.URL, `{"model":"unknown","messages":[{"role":"user","content":"hi"}]}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown model, got %d", resp.StatusCode)
+	}
+
+	resp, err := http.Get(ts.URL + "/v1/models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&list)
+	if len(list.Data) != 2 || list.Data[0].ID != "fast" || list.Data[1].ID != "smart" {
+		t.Errorf("unexpected model list: %+v", list.Data)
+	}
+}
+
+func TestOpenAIHandler_BadRequests(t *testing.T) {
+	a := agent.NewReAct(agent.WithLLM(mock.NewLLMProvider("mock")))
+	reflect := agent.NewReflection([]agent.Option{agent.WithLLM(mock.NewLLMProvider("mock"))})
+	ts := httptest.NewServer(NewOpenAIHandler(a, WithModel("reflect", reflect)))
+	defer ts.Close()
+
+	for _, body := range []string{
+		`{`,
+		`{"messages":[]}`,
+		`{"messages":[{"role":"assistant","content":"hi"}]}`,
+		`{"messages":[{"role":"user","content":[{"type":"image_url"}]}]}`,
+		`{"messages":[{"role":"user","content":"hi"}],"tools":[{"type":"function","function":{"name":"f"}}]}`,
+		`{"messages":[{"role":"user","content":"hi"}],"tool_choice":"auto"}`,
+		`{"messages":[{"role":"user","content":"hi"}],"n":2}`,
+		`{"model":"reflect","messages":[{"role":"user","content":"a"},{"role":"assistant","content":"b"},{"role":"user","content":"c"}]}`,
+	} {
+		resp := postOpenAI(t, ts.URL, body)
+		var out map[string]map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || out["error"]["type"] != "invalid_request_error" {
+			t.Errorf("body %s: expected 400 invalid_request_error, got %d %v", body, resp.StatusCode, out)
+		}
+	}
+}
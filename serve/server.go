@@ -0,0 +1,481 @@
+// Package serve 将 Agent 发布为可部署的流式聊天 HTTP 服务
+//
+// 包含两种接入方式：
+//   - ChatServer：有状态的会话聊天服务，SSE 推送事件
+//   - OpenAIHandler：兼容 OpenAI Chat Completions API，供现有 SDK 和前端直接调用（见 openai.go）
+//
+// ChatServer 提供：
+//   - POST {path}：接收消息和会话 ID，以 SSE 流式返回回复
+//   - DELETE {path}/sessions/{id}：清除会话记忆
+//   - GET /healthz、GET /readyz：存活和就绪探针，就绪检查可插拔（见 health.go）
+//   - 按会话隔离的记忆（同一 Agent 实例服务所有会话）
+//   - 并发上限、Hook 事件和优雅关闭
+//
+// 请求格式：
+//
+//	POST /chat
+//	{"session_id": "s1", "message": "你好", "context": {"lang": "zh"}}
+//
+// SSE 事件：
+//
+//	event: start
+//	data: {"session_id":"s1","run_id":"chat-xxx"}
+//
+//	event: token
+//	data: {"content":"你"}
+//
+//	event: token
+//	data: {"content":"好！"}
+//
+//	event: done
+//	data: {"session_id":"s1","run_id":"chat-xxx","output":{...}}
+//
+// token 事件为 LLM 流式生成的文本增量（包括调用工具前的中间回复）；Agent 配置了输出守卫或
+// 输出解析器时，为避免未经处理的内容提前发出，只在运行结束后发送一个包含完整回复的 token 事件。
+// done 事件中的 output 为最终结果。出错时发送 event: error，data 为 {"error":"..."}。
+//
+// Agent 须支持按会话注入记忆（agent.SupportsContextMemory，如 ReActAgent），否则 NewChatServer 返回 ErrUnsupportedAgent。
+//
+// 使用示例：
+//
+//	srv, err := serve.NewChatServer(myAgent, serve.WithMaxConcurrency(32))
+//	if err != nil {
+//		return err
+//	}
+//	go srv.ListenAndServe(":8080")
+//	defer srv.Shutdown(context.Background())
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/internal/util"
+	hexmemory "github.com/hexagon-codes/hexagon/memory"
+)
+
+// ErrServerClosed 服务器已关闭或正在关闭
+var ErrServerClosed = errors.New("serve: chat server closed")
+
+// ErrUnsupportedAgent Agent 不支持按会话注入记忆，无法隔离会话历史
+var ErrUnsupportedAgent = errors.New("serve: agent does not support per-session memory")
+
+// ChatRequest 聊天请求
+type ChatRequest struct {
+	// SessionID 会话 ID，为空时由服务器生成并在 start 事件中返回
+	SessionID string `json:"session_id,omitempty"`
+
+	// Message 用户消息
+	Message string `json:"message"`
+
+	// Context 额外上下文，传递给 agent.Input.Context
+	Context map[string]any `json:"context,omitempty"`
+}
+
+// ChatServer 将 Agent 包装为流式聊天服务
+type ChatServer struct {
+	agent   agent.Agent
+	options *options
+
+	// sessions 会话存储
+	sessions *sessionStore
+
+	// slots 并发槽位，nil 表示不限制
+	slots chan struct{}
+
+	// baseCtx 所有运行的根 context，强制关闭时取消
+	baseCtx    context.Context
+	cancelRuns context.CancelFunc
+
+	// inflight 正在处理的请求
+	inflight sync.WaitGroup
+
+	mu      sync.Mutex
+	closing bool
+	server  *http.Server
+	mux     *http.ServeMux
+}
+
+// options ChatServer 配置
+type options struct {
+	path           string
+	maxConcurrency int
+	maxSessions    int
+	newMemory      func(sessionID string) memory.Memory
+	hookManager    *hooks.Manager
+	runTimeout     time.Duration
+
+	readinessChecks  []readinessCheck
+	readinessTimeout time.Duration
+}
+
+// Option ChatServer 配置选项
+type Option func(*options)
+
+// WithPath 设置聊天端点路径
+// 默认值: "/chat"
+func WithPath(path string) Option {
+	return func(o *options) {
+		if path != "" {
+			o.path = "/" + strings.Trim(path, "/")
+		}
+	}
+}
+
+// WithMaxConcurrency 设置同时处理的最大请求数，超出时返回 429
+// n <= 0 表示不限制
+// 默认值: 64
+func WithMaxConcurrency(n int) Option {
+	return func(o *options) {
+		o.maxConcurrency = n
+	}
+}
+
+// WithMaxSessions 设置保留的最大会话数，超出时淘汰最久未使用的会话
+// 默认值: 1000
+func WithMaxSessions(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxSessions = n
+		}
+	}
+}
+
+// WithSessionMemory 设置新会话的记忆工厂
+// 默认每个会话使用 memory.NewWindowMemory(20)
+func WithSessionMemory(fn func(sessionID string) memory.Memory) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.newMemory = fn
+		}
+	}
+}
+
+// WithHooks 设置 Hook 管理器
+// 每次聊天触发 StreamStart / StreamEnd 事件，Agent 内部的运行、LLM、工具事件也通过该管理器发出
+func WithHooks(m *hooks.Manager) Option {
+	return func(o *options) {
+		o.hookManager = m
+	}
+}
+
+// WithRunTimeout 设置单次聊天的超时时间，0 表示不限制
+func WithRunTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.runTimeout = d
+	}
+}
+
+// NewChatServer 创建聊天服务
+//
+// 参数：
+//   - a: 处理消息的 Agent，所有会话共享同一实例，会话历史通过 agent.ContextWithMemory 隔离
+//   - opts: 配置选项
+//
+// a 为 nil 或不支持按会话注入记忆（见 agent.SupportsContextMemory）时返回 ErrUnsupportedAgent。
+func NewChatServer(a agent.Agent, opts ...Option) (*ChatServer, error) {
+	if a == nil || !agent.SupportsContextMemory(a) {
+		return nil, ErrUnsupportedAgent
+	}
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &ChatServer{
+		agent:    a,
+		options:  o,
+		sessions: newSessionStore(o.maxSessions, o.newMemory),
+	}
+	if o.maxConcurrency > 0 {
+		s.slots = make(chan struct{}, o.maxConcurrency)
+	}
+	s.baseCtx, s.cancelRuns = context.WithCancel(context.Background())
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc(o.path, s.handleChat)
+	s.mux.HandleFunc(o.path+"/sessions/", s.handleSession)
+	s.mux.HandleFunc(LivenessPath, s.handleLiveness)
+	s.mux.HandleFunc(ReadinessPath, s.handleReadiness)
+	return s, nil
+}
+
+// defaultOptions 返回 ChatServer 的默认配置
+func defaultOptions() *options {
+	return &options{
+		path:           "/chat",
+		maxConcurrency: 64,
+		maxSessions:    1000,
+		newMemory: func(string) memory.Memory {
+			return hexmemory.NewWindowMemory(20)
+		},
+		readinessTimeout: defaultReadinessTimeout,
+	}
+}
+
+// ServeHTTP 实现 http.Handler，可挂载到已有的路由中
+func (s *ChatServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe 在 addr 上启动 HTTP 服务，阻塞直到 Shutdown
+// 正常关闭时返回 nil
+func (s *ChatServer) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve 在 ln 上提供服务，阻塞直到 Shutdown
+// 正常关闭时返回 nil
+func (s *ChatServer) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	if s.server != nil {
+		s.mu.Unlock()
+		ln.Close()
+		return fmt.Errorf("serve: chat server already running")
+	}
+	s.server = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	srv := s.server
+	s.mu.Unlock()
+
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown 优雅关闭
+//
+// 立即拒绝新请求（503），等待进行中的聊天完成；
+// ctx 结束时取消仍在运行的聊天并返回 ctx.Err()。
+func (s *ChatServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	srv := s.server
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.cancelRuns()
+		<-done
+		err = ctx.Err()
+	}
+
+	if srv != nil {
+		// 聊天已全部结束，剩余的只有空闲连接
+		if shutdownErr := srv.Shutdown(ctx); err == nil && !errors.Is(shutdownErr, context.Canceled) && !errors.Is(shutdownErr, context.DeadlineExceeded) {
+			err = shutdownErr
+		}
+	}
+	s.cancelRuns()
+	return err
+}
+
+// handleChat 处理聊天请求
+// POST {path}
+func (s *ChatServer) handleChat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req ChatRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Message) == "" {
+		writeError(w, http.StatusBadRequest, "message is required")
+		return
+	}
+	if req.SessionID == "" {
+		req.SessionID = util.SessionID()
+	}
+
+	if !s.begin() {
+		writeError(w, http.StatusServiceUnavailable, ErrServerClosed.Error())
+		return
+	}
+	defer s.inflight.Done()
+
+	if !s.acquire() {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, "too many concurrent requests")
+		return
+	}
+	defer s.release()
+
+	sess, ok := s.sessions.get(req.SessionID)
+	if !ok {
+		writeError(w, http.StatusConflict, "session is busy")
+		return
+	}
+	defer sess.mu.Unlock()
+
+	sse, ok := newSSEWriter(w)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	s.run(r, sse, sess, req)
+}
+
+// run 执行一次聊天并以 SSE 推送结果
+func (s *ChatServer) run(r *http.Request, sse *sseWriter, sess *session, req ChatRequest) {
+	// 客户端断开或服务器强制关闭时都取消运行
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	stop := context.AfterFunc(s.baseCtx, cancel)
+	defer stop()
+	if s.options.runTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.options.runTimeout)
+		defer cancel()
+	}
+
+	ctx = agent.ContextWithMemory(ctx, sess.memory)
+	if m := s.options.hookManager; m != nil {
+		ctx = hooks.ContextWithManager(ctx, m)
+	}
+
+	runID := util.GenerateID("chat")
+	start := time.Now()
+	s.triggerStreamStart(ctx, runID, req)
+
+	_ = sse.send("start", map[string]any{"session_id": req.SessionID, "run_id": runID})
+
+	chunks := 0
+	ctx = agent.ContextWithTokenHandler(ctx, func(content string) error {
+		chunks++
+		return sse.send("token", map[string]any{"content": content})
+	})
+	output, err := s.agent.Run(ctx, agent.Input{Query: req.Message, Context: req.Context})
+	if err == nil && chunks == 0 && output.Content != "" {
+		// 未逐 Token 推送（Agent 不支持或配置了输出守卫、解析器）时一次发送完整回复
+		chunks++
+		_ = sse.send("token", map[string]any{"content": output.Content})
+	}
+	s.triggerStreamEnd(ctx, runID, req, chunks, start, err)
+	if err != nil {
+		_ = sse.send("error", map[string]any{"session_id": req.SessionID, "run_id": runID, "error": err.Error()})
+		return
+	}
+	_ = sse.send("done", map[string]any{"session_id": req.SessionID, "run_id": runID, "output": output})
+}
+
+// handleSession 处理会话管理请求
+// DELETE {path}/sessions/{id}
+func (s *ChatServer) handleSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, s.options.path+"/sessions/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	if !s.sessions.delete(id) {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// begin 登记一个进行中的请求，关闭中返回 false
+func (s *ChatServer) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return false
+	}
+	s.inflight.Add(1)
+	return true
+}
+
+// acquire 获取并发槽位，已满时立即返回 false
+func (s *ChatServer) acquire() bool {
+	if s.slots == nil {
+		return true
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release 释放并发槽位
+func (s *ChatServer) release() {
+	if s.slots != nil {
+		<-s.slots
+	}
+}
+
+func (s *ChatServer) triggerStreamStart(ctx context.Context, runID string, req ChatRequest) {
+	if m := s.options.hookManager; m != nil {
+		_ = m.TriggerStreamStart(ctx, &hooks.RunStreamStartEvent{
+			RunID:    runID,
+			AgentID:  s.agent.ID(),
+			Input:    req.Message,
+			IsStream: true,
+			Metadata: map[string]any{"session_id": req.SessionID},
+		})
+	}
+}
+
+func (s *ChatServer) triggerStreamEnd(ctx context.Context, runID string, req ChatRequest, chunks int, start time.Time, err error) {
+	if m := s.options.hookManager; m != nil {
+		_ = m.TriggerStreamEnd(context.WithoutCancel(ctx), &hooks.RunStreamEndEvent{
+			RunID:      runID,
+			AgentID:    s.agent.ID(),
+			ChunkCount: chunks,
+			Duration:   time.Since(start).Milliseconds(),
+			Error:      err,
+			Metadata:   map[string]any{"session_id": req.SessionID},
+		})
+	}
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+// writeError 写入错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
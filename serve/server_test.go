@@ -0,0 +1,454 @@
+package serve
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/hooks"
+	"github.com/hexagon-codes/hexagon/security/guard"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+type sseEvent struct {
+	Event string
+	Data  map[string]any
+}
+
+// readEvents 解析 SSE 响应
+func readEvents(t *testing.T, resp *http.Response) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &current.Data); err != nil {
+				t.Fatalf("invalid event data %q: %v", line, err)
+			}
+		case line == "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	return events
+}
+
+func postChat(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	return resp
+}
+
+func newEchoAgent(provider *mock.LLMProvider) agent.Agent {
+	return agent.NewReAct(agent.WithName("chat"), agent.WithLLM(provider))
+}
+
+func newChatServer(t *testing.T, a agent.Agent, opts ...Option) *ChatServer {
+	t.Helper()
+	srv, err := NewChatServer(a, opts...)
+	if err != nil {
+		t.Fatalf("NewChatServer: %v", err)
+	}
+	return srv
+}
+
+func TestChatServer_StreamsReply(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").AddResponse("你好！")
+	srv := newChatServer(t, newEchoAgent(provider))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp := postChat(t, ts.URL+"/chat", `{"session_id":"s1","message":"hi"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+
+	events := readEvents(t, resp)
+	if len(events) != 5 {
+		t.Fatalf("expected start, 3 tokens and done, got %+v", events)
+	}
+	if events[0].Event != "start" || events[0].Data["session_id"] != "s1" {
+		t.Errorf("unexpected start event: %+v", events[0])
+	}
+	var streamed strings.Builder
+	for _, e := range events[1:4] {
+		if e.Event != "token" {
+			t.Fatalf("expected token event, got %+v", e)
+		}
+		streamed.WriteString(e.Data["content"].(string))
+	}
+	if streamed.String() != "你好！" {
+		t.Errorf("streamed tokens = %q", streamed.String())
+	}
+	done := events[4]
+	if done.Event != "done" {
+		t.Fatalf("expected done event, got %+v", done)
+	}
+	if output, _ := done.Data["output"].(map[string]any); output["content"] != "你好！" {
+		t.Errorf("unexpected done output: %+v", done.Data)
+	}
+}
+
+func TestChatServer_OutputGuardBuffersTokens(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").AddResponse("邮箱是 leak@example.com")
+	a := agent.NewReAct(agent.WithLLM(provider),
+		agent.WithOutputGuards(guard.NewPIIGuard()), agent.WithOutputGuardAction(guard.ActionRedact))
+	ts := httptest.NewServer(newChatServer(t, a))
+	defer ts.Close()
+
+	resp := postChat(t, ts.URL+"/chat", `{"session_id":"s1","message":"hi"}`)
+	defer resp.Body.Close()
+
+	events := readEvents(t, resp)
+	var tokens []string
+	for _, e := range events {
+		if e.Event == "token" {
+			tokens = append(tokens, e.Data["content"].(string))
+		}
+	}
+	// 只发送经过守卫处理的完整回复
+	if len(tokens) != 1 || strings.Contains(tokens[0], "leak@example.com") {
+		t.Errorf("expected a single redacted token event, got %q", tokens)
+	}
+}
+
+func TestNewChatServer_RejectsAgentWithoutSessionMemory(t *testing.T) {
+	provider := mock.NewLLMProvider("mock")
+	for name, a := range map[string]agent.Agent{
+		"nil":        nil,
+		"reflection": agent.NewReflection([]agent.Option{agent.WithLLM(provider)}),
+		"plan":       agent.NewPlanExecute([]agent.Option{agent.WithLLM(provider)}),
+	} {
+		if _, err := NewChatServer(a); !errors.Is(err, ErrUnsupportedAgent) {
+			t.Errorf("%s: expected ErrUnsupportedAgent, got %v", name, err)
+		}
+	}
+}
+
+func TestChatServer_GeneratesSessionID(t *testing.T) {
+	srv := newChatServer(t, newEchoAgent(mock.NewLLMProvider("mock").AddResponse("ok")))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp := postChat(t, ts.URL+"/chat", `{"message":"hi"}`)
+	defer resp.Body.Close()
+	events := readEvents(t, resp)
+	if len(events) == 0 || events[0].Data["session_id"] == "" || events[0].Data["session_id"] == nil {
+		t.Fatalf("expected generated session id, got %+v", events)
+	}
+}
+
+func TestChatServer_SessionMemoryIsolated(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		return &llm.CompletionResponse{Content: "reply"}, nil
+	})
+	srv := newChatServer(t, newEchoAgent(provider))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	send := func(session, message string) {
+		resp := postChat(t, ts.URL+"/chat", `{"session_id":"`+session+`","message":"`+message+`"}`)
+		readEvents(t, resp)
+		resp.Body.Close()
+	}
+	contains := func(messages []llm.Message, text string) bool {
+		for _, m := range messages {
+			if strings.Contains(m.Content, text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	send("a", "apple")
+	send("a", "again")
+	if !contains(provider.LastCall().Messages, "apple") {
+		t.Error("expected session a history in second request")
+	}
+
+	send("b", "banana")
+	if contains(provider.LastCall().Messages, "apple") {
+		t.Error("session b must not see session a history")
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/chat/sessions/a", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+
+	send("a", "fresh")
+	if contains(provider.LastCall().Messages, "apple") {
+		t.Error("deleted session should start without history")
+	}
+}
+
+func TestChatServer_BadRequests(t *testing.T) {
+	srv := newChatServer(t, newEchoAgent(mock.NewLLMProvider("mock")))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"wrong method", http.MethodGet, "/chat", "", http.StatusMethodNotAllowed},
+		{"invalid json", http.MethodPost, "/chat", "{", http.StatusBadRequest},
+		{"empty message", http.MethodPost, "/chat", `{"message":"  "}`, http.StatusBadRequest},
+		{"unknown session", http.MethodDelete, "/chat/sessions/missing", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, ts.URL+tt.path, strings.NewReader(tt.body))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
+
+// blockingProvider 阻塞直到 release 关闭或 ctx 取消
+func blockingProvider(started chan<- struct{}, release <-chan struct{}) *mock.LLMProvider {
+	return mock.NewLLMProvider("mock").WithResponseFn(func(req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+		started <- struct{}{}
+		<-release
+		return &llm.CompletionResponse{Content: "late"}, nil
+	})
+}
+
+func TestChatServer_ConcurrencyLimit(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := newChatServer(t, newEchoAgent(blockingProvider(started, release)), WithMaxConcurrency(1))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp := postChat(t, ts.URL+"/chat", `{"session_id":"a","message":"hi"}`)
+		readEvents(t, resp)
+		resp.Body.Close()
+	}()
+	<-started
+
+	resp := postChat(t, ts.URL+"/chat", `{"session_id":"b","message":"hi"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", resp.StatusCode)
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestChatServer_SessionBusy(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := newChatServer(t, newEchoAgent(blockingProvider(started, release)))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp := postChat(t, ts.URL+"/chat", `{"session_id":"a","message":"hi"}`)
+		readEvents(t, resp)
+		resp.Body.Close()
+	}()
+	<-started
+
+	resp := postChat(t, ts.URL+"/chat", `{"session_id":"a","message":"again"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409, got %d", resp.StatusCode)
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestChatServer_ErrorEvent(t *testing.T) {
+	provider := mock.NewLLMProvider("mock").AddErrorResponse(context.DeadlineExceeded)
+	srv := newChatServer(t, newEchoAgent(provider))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp := postChat(t, ts.URL+"/chat", `{"session_id":"s","message":"hi"}`)
+	defer resp.Body.Close()
+	events := readEvents(t, resp)
+	last := events[len(events)-1]
+	if last.Event != "error" || last.Data["error"] == "" {
+		t.Errorf("expected error event, got %+v", events)
+	}
+}
+
+type streamRecorder struct {
+	mu     sync.Mutex
+	starts []*hooks.RunStreamStartEvent
+	ends   []*hooks.RunStreamEndEvent
+}
+
+func (h *streamRecorder) Name() string  { return "recorder" }
+func (h *streamRecorder) Enabled() bool { return true }
+func (h *streamRecorder) OnStart(context.Context, *hooks.RunStartEvent) error {
+	return nil
+}
+func (h *streamRecorder) OnEnd(context.Context, *hooks.RunEndEvent) error { return nil }
+func (h *streamRecorder) OnError(context.Context, *hooks.ErrorEvent) error {
+	return nil
+}
+func (h *streamRecorder) OnStreamStart(_ context.Context, e *hooks.RunStreamStartEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.starts = append(h.starts, e)
+	return nil
+}
+func (h *streamRecorder) OnStreamEnd(_ context.Context, e *hooks.RunStreamEndEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ends = append(h.ends, e)
+	return nil
+}
+
+func TestChatServer_Hooks(t *testing.T) {
+	recorder := &streamRecorder{}
+	manager := hooks.NewManager()
+	manager.RegisterRunHook(recorder)
+
+	srv := newChatServer(t, newEchoAgent(mock.NewLLMProvider("mock").AddResponse("ok")), WithHooks(manager))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp := postChat(t, ts.URL+"/chat", `{"session_id":"s1","message":"hi"}`)
+	readEvents(t, resp)
+	resp.Body.Close()
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.starts) != 1 || len(recorder.ends) != 1 {
+		t.Fatalf("expected one start and end event, got %d/%d", len(recorder.starts), len(recorder.ends))
+	}
+	if recorder.starts[0].Metadata["session_id"] != "s1" {
+		t.Errorf("expected session metadata, got %+v", recorder.starts[0].Metadata)
+	}
+	if recorder.ends[0].ChunkCount != 2 || recorder.ends[0].Error != nil {
+		t.Errorf("unexpected end event: %+v", recorder.ends[0])
+	}
+}
+
+func TestChatServer_Shutdown(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := newChatServer(t, newEchoAgent(blockingProvider(started, release)))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	done := make(chan []sseEvent, 1)
+	go func() {
+		resp := postChat(t, ts.URL+"/chat", `{"session_id":"a","message":"hi"}`)
+		defer resp.Body.Close()
+		done <- readEvents(t, resp)
+	}()
+	<-started
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- srv.Shutdown(context.Background()) }()
+
+	// 关闭中拒绝新请求
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp := postChat(t, ts.URL+"/chat", `{"session_id":"b","message":"hi"}`)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 503 during shutdown, got %d", resp.StatusCode)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("shutdown returned before in-flight chat finished: %v", err)
+	default:
+	}
+
+	close(release)
+	if err := <-shutdownErr; err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	events := <-done
+	if events[len(events)-1].Event != "done" {
+		t.Errorf("in-flight chat should complete, got %+v", events)
+	}
+}
+
+func TestSessionStore_Evicts(t *testing.T) {
+	store := newSessionStore(2, defaultOptions().newMemory)
+	use := func(id string) {
+		sess, ok := store.get(id)
+		if !ok {
+			t.Fatalf("session %s unexpectedly busy", id)
+		}
+		sess.mu.Unlock()
+	}
+	use("a")
+	use("b")
+	use("a")
+	use("c")
+
+	if store.len() != 2 {
+		t.Fatalf("expected 2 sessions, got %d", store.len())
+	}
+	if store.delete("b") {
+		t.Error("least recently used session b should be evicted")
+	}
+
+	// 运行中的会话不会被淘汰，也不能被再次获取
+	busy, _ := store.get("a")
+	defer busy.mu.Unlock()
+	if _, ok := store.get("a"); ok {
+		t.Error("expected busy session to be rejected")
+	}
+	use("d")
+	if !store.delete("d") || store.delete("c") || !store.delete("a") {
+		t.Error("expected idle session c evicted and busy session a kept")
+	}
+}
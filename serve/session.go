@@ -0,0 +1,95 @@
+package serve
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/memory"
+)
+
+// session 单个会话的状态
+type session struct {
+	// mu 保证同一会话同时只有一个聊天在运行
+	mu sync.Mutex
+
+	memory   memory.Memory
+	lastUsed time.Time
+}
+
+// sessionStore 会话存储，超出容量时淘汰最久未使用的空闲会话
+type sessionStore struct {
+	mu        sync.Mutex
+	sessions  map[string]*session
+	max       int
+	newMemory func(sessionID string) memory.Memory
+}
+
+func newSessionStore(max int, newMemory func(string) memory.Memory) *sessionStore {
+	return &sessionStore{
+		sessions:  make(map[string]*session),
+		max:       max,
+		newMemory: newMemory,
+	}
+}
+
+// get 获取会话并标记为运行中，不存在时创建
+//
+// 在持有存储锁时锁定会话，使其在返回后不会被淘汰；调用方用完后须调用 sess.mu.Unlock。
+// 会话正在运行时返回 false。
+func (s *sessionStore) get(id string) (*session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		if len(s.sessions) >= s.max {
+			s.evictLocked()
+		}
+		sess = &session{memory: s.newMemory(id)}
+		s.sessions[id] = sess
+	}
+	if !sess.mu.TryLock() {
+		return nil, false
+	}
+	sess.lastUsed = time.Now()
+	return sess, true
+}
+
+// delete 删除会话，返回会话是否存在
+func (s *sessionStore) delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[id]; !ok {
+		return false
+	}
+	delete(s.sessions, id)
+	return true
+}
+
+// len 返回会话数
+func (s *sessionStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// evictLocked 淘汰最久未使用的会话（调用方须持有 s.mu）
+// 正在运行的会话不会被淘汰
+func (s *sessionStore) evictLocked() {
+	var (
+		oldestID string
+		oldest   time.Time
+	)
+	for id, sess := range s.sessions {
+		if !sess.mu.TryLock() {
+			continue
+		}
+		sess.mu.Unlock()
+		if oldestID == "" || sess.lastUsed.Before(oldest) {
+			oldestID, oldest = id, sess.lastUsed
+		}
+	}
+	if oldestID != "" {
+		delete(s.sessions, oldestID)
+	}
+}
@@ -307,8 +307,12 @@ func (s *Store) Search(ctx context.Context, embedding []float32, limit int, opts
 		NResults:        limit,
 	}
 
-	if cfg.Filter != nil {
-		req.Where = cfg.Filter
+	filter, err := vector.ParseFilter(cfg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		req.Where = buildWhere(filter)
 	}
 
 	// 发送请求
@@ -705,5 +709,26 @@ func (s *Store) Stats(ctx context.Context) (map[string]any, error) {
 	return stats, nil
 }
 
+// buildWhere 将过滤表达式翻译为 Chroma where 条件
+//
+// 对应 $eq、$in、$gt、$lt、$and、$or；Chroma 要求 $and / $or 至少两个操作数，单个时直接展开。
+func buildWhere(f *vector.Filter) map[string]any {
+	switch f.Op {
+	case vector.OpAnd, vector.OpOr:
+		if len(f.Filters) == 1 {
+			return buildWhere(f.Filters[0])
+		}
+		operands := make([]map[string]any, len(f.Filters))
+		for i, sub := range f.Filters {
+			operands[i] = buildWhere(sub)
+		}
+		return map[string]any{"$" + string(f.Op): operands}
+	case vector.OpIn:
+		return map[string]any{f.Field: map[string]any{"$in": f.Values}}
+	default:
+		return map[string]any{f.Field: map[string]any{"$" + string(f.Op): f.Value}}
+	}
+}
+
 // 确保实现了 vector.Store 接口
 var _ vector.Store = (*Store)(nil)
@@ -180,6 +180,23 @@ func TestStoreSearch(t *testing.T) {
 	})
 }
 
+// TestBuildWhere 测试过滤表达式翻译为 Chroma where 条件
+func TestBuildWhere(t *testing.T) {
+	f := vector.And(
+		vector.Eq("source", "wiki"),
+		vector.Or(vector.Gt("year", 2020), vector.In("tag", "go", "rust")),
+		vector.And(vector.Lt("score", 0.9)),
+	)
+	got, err := json.Marshal(buildWhere(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"$and":[{"source":{"$eq":"wiki"}},{"$or":[{"year":{"$gt":2020}},{"tag":{"$in":["go","rust"]}}]},{"score":{"$lt":0.9}}]}`
+	if string(got) != want {
+		t.Errorf("buildWhere = %s, want %s", got, want)
+	}
+}
+
 // TestStoreGet 测试获取文档
 func TestStoreGet(t *testing.T) {
 	server := createMockGetServer()
@@ -0,0 +1,56 @@
+package vector
+
+// EarlyStop 搜索提前停止条件
+type EarlyStop struct {
+	// MinAcceptable 可接受的最低分数
+	MinAcceptable float32
+
+	// SufficientCount 达到该数量的可接受结果即停止搜索
+	SufficientCount int
+}
+
+// WithEarlyStop 设置搜索提前停止条件
+//
+// 存储找到 sufficientCount 个分数不低于 minAcceptable 的结果后即可停止搜索，
+// 以召回完整性换取延迟。返回的结果可能少于 topK，且不保证是全局最相似的 topK。
+//
+// 支持该选项的存储：
+//   - FilterMemoryStore：结果中已有 sufficientCount 个可接受结果时只返回这些结果
+//   - faiss：内置内存引擎精确扫描时满足条件即结束扫描
+//   - milvus：HNSW 索引映射为更低的搜索强度（ef）
+//
+// 其他存储忽略该选项，按常规方式搜索。sufficientCount <= 0 时不生效。
+func WithEarlyStop(minAcceptable float32, sufficientCount int) SearchOption {
+	es := EarlyStop{MinAcceptable: minAcceptable, SufficientCount: sufficientCount}
+	return func(cfg *SearchConfig) {
+		if p, ok := cfg.Filter[earlyStopProbeKey].(*EarlyStop); ok {
+			*p = es
+		}
+	}
+}
+
+// earlyStopProbeKey 解析提前停止条件时探测配置使用的保留键
+//
+// SearchConfig 来自 ai-core，无法增加字段；EarlyStopFromOptions 在探测配置的 Filter 中
+// 放入该键，WithEarlyStop 只对带该键的配置生效，对存储的真实配置是空操作。
+const earlyStopProbeKey = "$early_stop"
+
+// EarlyStopFromOptions 从搜索选项中取出提前停止条件，供存储实现使用
+// 未设置或 SufficientCount <= 0 时返回 false
+func EarlyStopFromOptions(opts ...SearchOption) (EarlyStop, bool) {
+	var es EarlyStop
+	for _, opt := range opts {
+		// 每个选项使用新的探测配置，避免 WithFilter 等选项覆盖保留键
+		opt(&SearchConfig{Filter: map[string]any{earlyStopProbeKey: &es}})
+	}
+	return es, es.SufficientCount > 0
+}
+
+// applyEarlyStop 按提前停止条件截取按分数降序排列的结果
+// 前 SufficientCount 个结果均可接受时只返回这些结果，否则原样返回
+func applyEarlyStop(docs []Document, es EarlyStop) []Document {
+	if len(docs) < es.SufficientCount || docs[es.SufficientCount-1].Score < es.MinAcceptable {
+		return docs
+	}
+	return docs[:es.SufficientCount]
+}
@@ -0,0 +1,62 @@
+package vector
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MetadataExpiresAt 文档过期时间的元数据键，值为 Unix 秒
+//
+// Document 来自 ai-core，无法增加字段，过期时间随元数据一起存储，
+// 因此所有存储都能保存它，支持范围过滤的存储还可以在服务端排除过期文档（见 NotExpired）。
+const MetadataExpiresAt = "expires_at"
+
+// SetExpiresAt 设置文档的过期时间，t 为零值时清除
+// 元数据会被复制，不修改调用方共享的 map
+func SetExpiresAt(doc *Document, t time.Time) {
+	metadata := make(map[string]any, len(doc.Metadata)+1)
+	for k, v := range doc.Metadata {
+		metadata[k] = v
+	}
+	if t.IsZero() {
+		delete(metadata, MetadataExpiresAt)
+	} else {
+		metadata[MetadataExpiresAt] = t.Unix()
+	}
+	doc.Metadata = metadata
+}
+
+// ExpiresAt 返回文档的过期时间，未设置时返回 false
+//
+// 兼容经 JSON 往返后的数值（float64、json.Number）、time.Time 和 RFC3339 字符串。
+func ExpiresAt(doc Document) (time.Time, bool) {
+	switch v := doc.Metadata[MetadataExpiresAt].(type) {
+	case nil:
+		return time.Time{}, false
+	case time.Time:
+		return v, !v.IsZero()
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		return t, err == nil
+	case json.Number:
+		n, err := v.Int64()
+		return time.Unix(n, 0), err == nil
+	default:
+		n, ok := NumericValue(v)
+		return time.Unix(int64(n), 0), ok
+	}
+}
+
+// IsExpired 判断文档在 now 时是否已过期，未设置过期时间的文档永不过期
+func IsExpired(doc Document, now time.Time) bool {
+	t, ok := ExpiresAt(doc)
+	return ok && !t.After(now)
+}
+
+// NotExpired 返回排除 now 时已过期文档的过滤表达式，供支持 Gt 的存储在服务端过滤
+//
+// 表达式要求文档带有 MetadataExpiresAt，未设置过期时间的文档同样会被排除，
+// 因此只适用于所有文档都写入了过期时间的集合（永不过期的文档写入一个足够远的时间）。
+func NotExpired(now time.Time) *Filter {
+	return Gt(MetadataExpiresAt, now.Unix())
+}
@@ -0,0 +1,82 @@
+package vector_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// TestExpiresAt 测试过期时间的写入与读取
+func TestExpiresAt(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	shared := map[string]any{"lang": "en"}
+	doc := vector.Document{ID: "a", Metadata: shared}
+	vector.SetExpiresAt(&doc, at)
+	if _, ok := shared[vector.MetadataExpiresAt]; ok {
+		t.Error("SetExpiresAt 不应修改共享的元数据")
+	}
+
+	// 经 JSON 往返后数值变为 float64
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded vector.Document
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := vector.ExpiresAt(decoded); !ok || !got.Equal(at) {
+		t.Errorf("ExpiresAt = %v, %v, want %v", got, ok, at)
+	}
+	if !vector.IsExpired(decoded, at) || vector.IsExpired(decoded, at.Add(-time.Second)) {
+		t.Error("IsExpired 应以过期时间为界")
+	}
+	if vector.IsExpired(vector.Document{}, time.Now()) {
+		t.Error("未设置过期时间的文档不应过期")
+	}
+
+	vector.SetExpiresAt(&doc, time.Time{})
+	if _, ok := vector.ExpiresAt(doc); ok {
+		t.Error("零值应清除过期时间")
+	}
+}
+
+// TestFilterMemoryStore_Expiry 测试内存存储清除过期文档
+func TestFilterMemoryStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store := vector.NewFilterMemoryStore(2)
+	defer store.Close()
+
+	stale := vector.Document{ID: "stale", Embedding: []float32{1, 0}}
+	vector.SetExpiresAt(&stale, time.Now().Add(-time.Second))
+	fresh := vector.Document{ID: "fresh", Embedding: []float32{1, 0.1}}
+	vector.SetExpiresAt(&fresh, time.Now().Add(time.Hour))
+	if err := store.Add(ctx, []vector.Document{stale, fresh, {ID: "plain", Embedding: []float32{0, 1}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := store.Search(ctx, []float32{1, 0}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 || docs[0].ID != "fresh" {
+		t.Errorf("期望排除过期文档, got %v", docs)
+	}
+	if doc, _ := store.Get(ctx, "stale"); doc != nil {
+		t.Error("过期文档应已被清除")
+	}
+	if n, err := store.Reap(ctx); err != nil || n != 0 {
+		t.Errorf("Reap = %d, %v, want 0", n, err)
+	}
+
+	// 重新写入不带过期时间的同 ID 文档后不再过期
+	if err := store.Add(ctx, []vector.Document{{ID: "fresh", Embedding: []float32{1, 0.1}}}); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := store.Count(ctx); n != 2 {
+		t.Errorf("Count = %d, want 2", n)
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"os"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/hexagon-codes/hexagon/store/vector"
 )
@@ -231,7 +232,13 @@ func (s *Store) Add(ctx context.Context, docs []vector.Document) error {
 }
 
 // Search 搜索相似文档
-func (s *Store) Search(ctx context.Context, embedding []float32, topK int, filter map[string]any) ([]vector.Document, error) {
+//
+// filter 为字段到值的等值过滤，或 vector.Filter.Map() 生成的过滤表达式，在内存中求值。
+// 已过期的文档（见 vector.SetExpiresAt）不会返回。
+//
+// 支持 vector.WithEarlyStop：内置内存引擎精确扫描时找到足够的可接受结果即停止，
+// 此时返回的结果可能少于 topK；其他引擎忽略该选项。
+func (s *Store) Search(ctx context.Context, embedding []float32, topK int, filter map[string]any, opts ...vector.SearchOption) ([]vector.Document, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -244,7 +251,22 @@ func (s *Store) Search(ctx context.Context, embedding []float32, topK int, filte
 			len(embedding), s.dimension)
 	}
 
-	results, err := s.engine.Search(embedding, topK)
+	f, err := vector.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var results []SearchResult
+	es, earlyStop := vector.EarlyStopFromOptions(opts...)
+	if engine, ok := s.engine.(earlyStopSearcher); ok && earlyStop {
+		results, err = engine.searchUntil(embedding, topK, es, func(id string) bool {
+			doc, ok := s.docs[id]
+			return ok && f.Match(doc.Metadata) && !vector.IsExpired(*doc, now)
+		})
+	} else {
+		results, err = s.engine.Search(embedding, topK)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("搜索失败: %w", err)
 	}
@@ -257,8 +279,8 @@ func (s *Store) Search(ctx context.Context, embedding []float32, topK int, filte
 			continue
 		}
 
-		// 应用过滤条件
-		if len(filter) > 0 && !matchFilter(doc.Metadata, filter) {
+		// 应用过滤条件，排除已过期的文档
+		if !f.Match(doc.Metadata) || vector.IsExpired(*doc, now) {
 			continue
 		}
 
@@ -317,6 +339,11 @@ func (s *Store) Clear(ctx context.Context) error {
 	return nil
 }
 
+// Dimension 返回配置的向量维度
+func (s *Store) Dimension() int {
+	return s.dimension
+}
+
 // Count 返回文档数量
 func (s *Store) Count(ctx context.Context) (int, error) {
 	s.mu.RLock()
@@ -544,6 +571,46 @@ func (e *memoryEngine) Search(query []float32, topK int) ([]SearchResult, error)
 	return searchResults, nil
 }
 
+// earlyStopSearcher 支持提前停止的引擎
+type earlyStopSearcher interface {
+	// searchUntil 搜索最近邻，找到 es.SufficientCount 个可接受结果即停止
+	// accept 判断结果是否计入（如是否满足过滤条件）
+	searchUntil(query []float32, topK int, es vector.EarlyStop, accept func(id string) bool) ([]SearchResult, error)
+}
+
+// searchUntil 按插入顺序精确扫描，找到足够的可接受结果后立即结束
+// 扫描完仍不足时退化为常规搜索，返回 accept 通过的最近 topK 个结果
+func (e *memoryEngine) searchUntil(query []float32, topK int, es vector.EarlyStop, accept func(id string) bool) ([]SearchResult, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var all, acceptable []SearchResult
+	for i, vec := range e.vectors {
+		if !accept(e.ids[i]) {
+			continue
+		}
+		distance := e.computeDistance(query, vec)
+		r := SearchResult{ID: e.ids[i], Distance: distance, Score: e.distanceToScore(distance)}
+		all = append(all, r)
+		if r.Score < es.MinAcceptable {
+			continue
+		}
+		acceptable = append(acceptable, r)
+		if len(acceptable) >= es.SufficientCount {
+			all = acceptable
+			break
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Distance < all[j].Distance
+	})
+	if topK < len(all) {
+		all = all[:topK]
+	}
+	return all, nil
+}
+
 func (e *memoryEngine) Remove(ids []string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -716,20 +783,3 @@ func innerProduct(a, b []float32) float32 {
 	}
 	return float32(sum)
 }
-
-// matchFilter 检查文档元数据是否匹配过滤条件
-func matchFilter(metadata map[string]any, filter map[string]any) bool {
-	if metadata == nil {
-		return false
-	}
-	for key, value := range filter {
-		metaVal, ok := metadata[key]
-		if !ok {
-			return false
-		}
-		if fmt.Sprintf("%v", metaVal) != fmt.Sprintf("%v", value) {
-			return false
-		}
-	}
-	return true
-}
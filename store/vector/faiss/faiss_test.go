@@ -0,0 +1,54 @@
+package faiss
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+func TestStoreSearchEarlyStop(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewStore(WithDimension(2), WithMetric(MetricCosine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.Add(ctx, []vector.Document{
+		{ID: "far", Embedding: []float32{0, 1}},
+		{ID: "good", Embedding: []float32{1, 0.2}, Metadata: map[string]any{"lang": "en"}},
+		{ID: "filtered", Embedding: []float32{1, 0.1}, Metadata: map[string]any{"lang": "zh"}},
+		{ID: "best", Embedding: []float32{1, 0}, Metadata: map[string]any{"lang": "en"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	query := []float32{1, 0}
+
+	// 常规搜索返回全局最近的结果
+	docs, err := store.Search(ctx, query, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 || docs[0].ID != "best" {
+		t.Fatalf("Search() = %v, want 3 results starting with best", docs)
+	}
+
+	// 扫描到第一个可接受结果即停止，结果少于 topK
+	docs, err = store.Search(ctx, query, 3, map[string]any{"lang": "en"}, vector.WithEarlyStop(0.9, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].ID != "good" {
+		t.Fatalf("early stop Search() = %v, want [good]", docs)
+	}
+
+	// 条件无法满足时退化为常规搜索
+	docs, err = store.Search(ctx, query, 3, nil, vector.WithEarlyStop(0.99, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 || docs[0].ID != "best" {
+		t.Fatalf("unsatisfied early stop Search() = %v, want 3 results starting with best", docs)
+	}
+}
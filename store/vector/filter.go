@@ -0,0 +1,359 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedFilter 存储无法表达该过滤条件
+//
+// 存储返回的错误包装本错误，可以用 errors.Is 判断。
+var ErrUnsupportedFilter = errors.New("unsupported filter")
+
+// FilterOp 过滤操作符
+type FilterOp string
+
+const (
+	// OpEq 字段等于值
+	OpEq FilterOp = "eq"
+
+	// OpIn 字段等于任一值
+	OpIn FilterOp = "in"
+
+	// OpGt 字段大于值
+	OpGt FilterOp = "gt"
+
+	// OpLt 字段小于值
+	OpLt FilterOp = "lt"
+
+	// OpAnd 所有子条件都满足
+	OpAnd FilterOp = "and"
+
+	// OpOr 任一子条件满足
+	OpOr FilterOp = "or"
+)
+
+// Filter 元数据过滤表达式
+//
+// 由 Eq、In、Gt、Lt、And、Or 构造，各存储翻译为自身的查询语言
+// （chroma / pinecone 的 where 操作符、weaviate 的 GraphQL where、pgvector 的 SQL 等），
+// 同一个表达式可以在不同后端之间移植。无法表达的条件返回 ErrUnsupportedFilter。
+//
+// 比较语义：
+//   - Eq / In 按值的字符串形式（%v）比较，与 WithFilter 的历史行为一致
+//   - Gt / Lt 数值与数值比较、字符串与字符串比较，类型不一致或字段缺失时不匹配
+//
+// 使用示例：
+//
+//	f := vector.And(
+//	    vector.Eq("source", "wiki"),
+//	    vector.Or(vector.Gt("year", 2020), vector.In("tag", "go", "rust")),
+//	)
+//	docs, err := store.Search(ctx, embedding, 5, vector.WithFilterExpr(f))
+type Filter struct {
+	// Op 操作符
+	Op FilterOp `json:"op"`
+
+	// Field 元数据字段名（Eq、In、Gt、Lt）
+	Field string `json:"field,omitempty"`
+
+	// Value 比较值（Eq、Gt、Lt）
+	Value any `json:"value,omitempty"`
+
+	// Values 候选值（In）
+	Values []any `json:"values,omitempty"`
+
+	// Filters 子条件（And、Or）
+	Filters []*Filter `json:"filters,omitempty"`
+}
+
+// Eq 字段等于 value
+func Eq(field string, value any) *Filter {
+	return &Filter{Op: OpEq, Field: field, Value: value}
+}
+
+// In 字段等于 values 中的任一值
+func In(field string, values ...any) *Filter {
+	return &Filter{Op: OpIn, Field: field, Values: values}
+}
+
+// Gt 字段大于 value
+func Gt(field string, value any) *Filter {
+	return &Filter{Op: OpGt, Field: field, Value: value}
+}
+
+// Lt 字段小于 value
+func Lt(field string, value any) *Filter {
+	return &Filter{Op: OpLt, Field: field, Value: value}
+}
+
+// And 所有子条件都满足
+func And(filters ...*Filter) *Filter {
+	return &Filter{Op: OpAnd, Filters: filters}
+}
+
+// Or 任一子条件满足
+func Or(filters ...*Filter) *Filter {
+	return &Filter{Op: OpOr, Filters: filters}
+}
+
+// filterExprKey WithFilterExpr 在 SearchConfig.Filter 中存放表达式的保留键
+//
+// SearchConfig 来自 ai-core，无法增加字段；只认识等值过滤的存储把它当作普通字段，
+// 由于元数据中没有该字段，结果为空而不是忽略过滤条件。
+const filterExprKey = "$filter"
+
+// WithFilterExpr 设置过滤表达式
+//
+// 仅由 Eq 和 And 组成的表达式等价于 WithFilter 的等值过滤，所有存储都支持；
+// 其他表达式需要存储支持（见 ParseFilter），否则返回 ErrUnsupportedFilter。
+// 与 WithFilter 同时使用时以后设置的为准。
+func WithFilterExpr(f *Filter) SearchOption {
+	return func(cfg *SearchConfig) {
+		cfg.Filter = f.Map()
+	}
+}
+
+// Map 将表达式转为 SearchConfig.Filter 使用的 map
+//
+// 等值合取直接展开为字段到值的 map，其他表达式存放在保留键下，由 ParseFilter 还原。
+// 可以传给接收 map 过滤参数的存储（如 faiss、pgvector、redis 的 Search）。
+func (f *Filter) Map() map[string]any {
+	if f == nil {
+		return nil
+	}
+	if m, ok := f.Equalities(); ok {
+		return m
+	}
+	return map[string]any{filterExprKey: f}
+}
+
+// ParseFilter 将 SearchConfig.Filter 解析为过滤表达式，供存储实现使用
+//
+// 普通 map 是多个 Eq 的 And（按字段名排序，单个字段时为 Eq 本身）；
+// WithFilterExpr 设置的表达式原样返回。filter 为空时返回 nil。
+// 表达式不合法时返回包装 ErrUnsupportedFilter 的错误。
+func ParseFilter(filter map[string]any) (*Filter, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	if f, ok := filter[filterExprKey].(*Filter); ok && len(filter) == 1 {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) == 1 {
+		return Eq(keys[0], filter[keys[0]]), nil
+	}
+	conds := make([]*Filter, len(keys))
+	for i, k := range keys {
+		conds[i] = Eq(k, filter[k])
+	}
+	return And(conds...), nil
+}
+
+// Validate 检查表达式结构
+func (f *Filter) Validate() error {
+	if f == nil {
+		return fmt.Errorf("%w: nil filter", ErrUnsupportedFilter)
+	}
+	switch f.Op {
+	case OpEq, OpIn, OpGt, OpLt:
+		if f.Field == "" {
+			return fmt.Errorf("%w: %s without field", ErrUnsupportedFilter, f.Op)
+		}
+		if f.Op == OpIn && len(f.Values) == 0 {
+			return fmt.Errorf("%w: in %q without values", ErrUnsupportedFilter, f.Field)
+		}
+	case OpAnd, OpOr:
+		if len(f.Filters) == 0 {
+			return fmt.Errorf("%w: %s without operands", ErrUnsupportedFilter, f.Op)
+		}
+		for _, sub := range f.Filters {
+			if err := sub.Validate(); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown operator %q", ErrUnsupportedFilter, f.Op)
+	}
+	return nil
+}
+
+// Equalities 表达式仅由 Eq 和 And 组成时，返回字段到值的 map
+// 同一字段出现不同值时返回 false
+func (f *Filter) Equalities() (map[string]any, bool) {
+	m := make(map[string]any)
+	if !f.collectEqualities(m) {
+		return nil, false
+	}
+	return m, true
+}
+
+func (f *Filter) collectEqualities(m map[string]any) bool {
+	if f == nil {
+		return false
+	}
+	switch f.Op {
+	case OpEq:
+		if f.Field == "" {
+			return false
+		}
+		if v, ok := m[f.Field]; ok && !equalValues(v, f.Value) {
+			return false
+		}
+		m[f.Field] = f.Value
+		return true
+	case OpAnd:
+		if len(f.Filters) == 0 {
+			return false
+		}
+		for _, sub := range f.Filters {
+			if !sub.collectEqualities(m) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// Match 判断元数据是否满足表达式，供无原生过滤能力的存储在内存中求值
+func (f *Filter) Match(metadata map[string]any) bool {
+	if f == nil {
+		return true
+	}
+	switch f.Op {
+	case OpAnd:
+		for _, sub := range f.Filters {
+			if !sub.Match(metadata) {
+				return false
+			}
+		}
+		return true
+	case OpOr:
+		for _, sub := range f.Filters {
+			if sub.Match(metadata) {
+				return true
+			}
+		}
+		return false
+	}
+
+	v, ok := metadata[f.Field]
+	if !ok {
+		return false
+	}
+	switch f.Op {
+	case OpEq:
+		return equalValues(v, f.Value)
+	case OpIn:
+		for _, candidate := range f.Values {
+			if equalValues(v, candidate) {
+				return true
+			}
+		}
+		return false
+	case OpGt:
+		c, ok := compareValues(v, f.Value)
+		return ok && c > 0
+	case OpLt:
+		c, ok := compareValues(v, f.Value)
+		return ok && c < 0
+	default:
+		return false
+	}
+}
+
+// String 返回表达式的可读形式，如 and(eq(source, wiki), gt(year, 2020))
+func (f *Filter) String() string {
+	if f == nil {
+		return "<nil>"
+	}
+	switch f.Op {
+	case OpAnd, OpOr:
+		parts := make([]string, len(f.Filters))
+		for i, sub := range f.Filters {
+			parts[i] = sub.String()
+		}
+		return fmt.Sprintf("%s(%s)", f.Op, strings.Join(parts, ", "))
+	case OpIn:
+		return fmt.Sprintf("in(%s, %v)", f.Field, f.Values)
+	default:
+		return fmt.Sprintf("%s(%s, %v)", f.Op, f.Field, f.Value)
+	}
+}
+
+// equalValues 按字符串形式比较两个值
+func equalValues(a, b any) bool {
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+// compareValues 比较数值或字符串，类型不可比较时返回 false
+func compareValues(a, b any) (int, bool) {
+	if x, ok := NumericValue(a); ok {
+		y, ok := NumericValue(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+	x, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+// NumericValue 将数值类型转为 float64，供存储翻译 Gt / Lt 时判断值类型
+func NumericValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,114 @@
+package vector_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// TestParseFilter 测试 map 与过滤表达式之间的转换
+func TestParseFilter(t *testing.T) {
+	f, err := vector.ParseFilter(map[string]any{"b": 2, "a": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.String(); got != "and(eq(a, x), eq(b, 2))" {
+		t.Errorf("map 应解析为等值合取, got %s", got)
+	}
+
+	if f, _ := vector.ParseFilter(nil); f != nil {
+		t.Errorf("空 map 应返回 nil, got %s", f)
+	}
+
+	// 等值合取展开为普通 map，其他表达式原样还原
+	eq := vector.And(vector.Eq("a", 1), vector.Eq("b", 2))
+	if m := eq.Map(); len(m) != 2 || m["a"] != 1 {
+		t.Errorf("等值合取应展开为字段 map, got %v", m)
+	}
+	expr := vector.Or(vector.Eq("a", 1), vector.Gt("year", 2020))
+	if f, err := vector.ParseFilter(expr.Map()); err != nil || f != expr {
+		t.Errorf("表达式应原样还原, got %v, %v", f, err)
+	}
+
+	if _, err := vector.ParseFilter(vector.And().Map()); !errors.Is(err, vector.ErrUnsupportedFilter) {
+		t.Errorf("空 And 应返回 ErrUnsupportedFilter, got %v", err)
+	}
+	if _, err := vector.ParseFilter(vector.In("tag").Map()); !errors.Is(err, vector.ErrUnsupportedFilter) {
+		t.Errorf("无候选值的 In 应返回 ErrUnsupportedFilter, got %v", err)
+	}
+}
+
+// TestFilterMatch 测试过滤表达式在内存中的求值
+func TestFilterMatch(t *testing.T) {
+	metadata := map[string]any{"source": "wiki", "year": 2022, "score": 0.5, "tag": "go"}
+
+	tests := []struct {
+		name   string
+		filter *vector.Filter
+		want   bool
+	}{
+		{"eq", vector.Eq("source", "wiki"), true},
+		{"eq 按字符串形式比较", vector.Eq("year", "2022"), true},
+		{"eq 字段缺失", vector.Eq("lang", "en"), false},
+		{"in", vector.In("tag", "rust", "go"), true},
+		{"in 不匹配", vector.In("tag", "rust", "zig"), false},
+		{"gt 整数与浮点", vector.Gt("year", 2020.5), true},
+		{"lt", vector.Lt("score", 0.3), false},
+		{"gt 类型不一致", vector.Gt("source", 1), false},
+		{"gt 字符串", vector.Gt("source", "blog"), true},
+		{"and", vector.And(vector.Eq("source", "wiki"), vector.Lt("year", 2023)), true},
+		{"or", vector.Or(vector.Eq("source", "blog"), vector.In("tag", "go")), true},
+		{"嵌套", vector.And(vector.Eq("source", "wiki"), vector.Or(vector.Gt("year", 2023), vector.Lt("score", 0.1))), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(metadata); got != tt.want {
+				t.Errorf("%s.Match() = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFilterMemoryStore_FilterExpr 测试内存存储的过滤表达式搜索
+func TestFilterMemoryStore_FilterExpr(t *testing.T) {
+	ctx := context.Background()
+	store := vector.NewFilterMemoryStore(2)
+	defer store.Close()
+
+	if err := store.Add(ctx, []vector.Document{
+		{ID: "old", Embedding: []float32{1, 0}, Metadata: map[string]any{"year": 2018, "lang": "en"}},
+		{ID: "new", Embedding: []float32{1, 0.1}, Metadata: map[string]any{"year": 2023, "lang": "en"}},
+		{ID: "zh", Embedding: []float32{1, 0.2}, Metadata: map[string]any{"year": 2024, "lang": "zh"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := store.Search(ctx, []float32{1, 0}, 10,
+		vector.WithFilterExpr(vector.And(vector.Gt("year", 2020), vector.In("lang", "en", "fr"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].ID != "new" {
+		t.Errorf("期望只返回 new, got %v", docs)
+	}
+
+	docs, err = store.Search(ctx, []float32{1, 0}, 1,
+		vector.WithFilterExpr(vector.Or(vector.Eq("lang", "zh"), vector.Lt("year", 2020))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].ID != "old" {
+		t.Errorf("期望按相似度返回 old, got %v", docs)
+	}
+
+	// 等值合取与 WithFilter 等价
+	docs, err = store.Search(ctx, []float32{1, 0}, 10, vector.WithFilterExpr(vector.Eq("lang", "zh")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].ID != "zh" {
+		t.Errorf("期望只返回 zh, got %v", docs)
+	}
+}
@@ -335,6 +335,11 @@ func (s *Store) Add(ctx context.Context, docs []vector.Document) error {
 //
 // 使用向量相似度在 Milvus 中搜索最近邻文档。
 // 返回结果按相似度降序排列。
+//
+// 元数据以 JSON 字符串存储，不支持元数据过滤，设置过滤条件时返回 vector.ErrUnsupportedFilter。
+//
+// 支持 vector.WithEarlyStop：HNSW 索引的搜索强度 ef 降为 max(k, sufficientCount)，
+// 并只返回分数不低于 minAcceptable 的结果，结果可能少于 k。
 func (s *Store) Search(ctx context.Context, query []float32, k int, opts ...vector.SearchOption) ([]vector.Document, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -354,6 +359,15 @@ func (s *Store) Search(ctx context.Context, query []float32, k int, opts ...vect
 		opt(cfg)
 	}
 
+	// 元数据序列化为 VarChar 字段 metadata_json，无法在服务端按字段过滤
+	filter, err := vector.ParseFilter(cfg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		return nil, fmt.Errorf("milvus: %w: %s", vector.ErrUnsupportedFilter, filter)
+	}
+
 	outputFields := []string{"id", "content", "metadata_json"}
 	if cfg.IncludeEmbedding {
 		outputFields = append(outputFields, "vector")
@@ -367,9 +381,13 @@ func (s *Store) Search(ctx context.Context, query []float32, k int, opts ...vect
 		"outputFields":   outputFields,
 		"searchParams": map[string]any{
 			"metricType": s.metricType,
-			"params":     map[string]any{},
+			"params":     s.searchParams(k, opts...),
 		},
 	}
+	minScore := cfg.MinScore
+	if es, ok := vector.EarlyStopFromOptions(opts...); ok {
+		minScore = max(minScore, es.MinAcceptable)
+	}
 
 	respData, err := s.doPost(ctx, "/v2/vectordb/entities/search", req)
 	if err != nil {
@@ -414,7 +432,7 @@ func (s *Store) Search(ctx context.Context, query []float32, k int, opts ...vect
 		}
 
 		// 应用最小分数过滤
-		if doc.Score >= cfg.MinScore {
+		if doc.Score >= minScore {
 			docs = append(docs, doc)
 		}
 	}
@@ -422,6 +440,16 @@ func (s *Store) Search(ctx context.Context, query []float32, k int, opts ...vect
 	return docs, nil
 }
 
+// searchParams 返回索引搜索参数
+// 设置了提前停止时，HNSW 的 ef 取允许的最小值 max(k, sufficientCount)
+func (s *Store) searchParams(k int, opts ...vector.SearchOption) map[string]any {
+	params := map[string]any{}
+	if es, ok := vector.EarlyStopFromOptions(opts...); ok && s.indexType == "HNSW" {
+		params["ef"] = max(k, es.SufficientCount)
+	}
+	return params
+}
+
 // Get 根据 ID 获取文档
 func (s *Store) Get(ctx context.Context, id string) (*vector.Document, error) {
 	s.mu.RLock()
@@ -565,6 +593,11 @@ func (s *Store) Update(ctx context.Context, docs []vector.Document) error {
 	return nil
 }
 
+// Dimension 返回配置的向量维度
+func (s *Store) Dimension() int {
+	return s.dimension
+}
+
 // Count 统计文档数量
 //
 // 通过查询所有 ID 来统计文档总数。
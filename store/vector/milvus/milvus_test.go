@@ -1034,3 +1034,22 @@ func TestGenerateID(t *testing.T) {
 		}
 	})
 }
+
+// TestStoreSearchParamsEarlyStop 测试提前停止映射为 HNSW 搜索强度
+func TestStoreSearchParamsEarlyStop(t *testing.T) {
+	s := &Store{indexType: "HNSW"}
+	if params := s.searchParams(10); len(params) != 0 {
+		t.Errorf("searchParams() = %v, want empty", params)
+	}
+	if params := s.searchParams(10, vector.WithEarlyStop(0.8, 3)); params["ef"] != 10 {
+		t.Errorf("ef = %v, want 10", params["ef"])
+	}
+	if params := s.searchParams(2, vector.WithEarlyStop(0.8, 5)); params["ef"] != 5 {
+		t.Errorf("ef = %v, want 5", params["ef"])
+	}
+
+	s.indexType = "IVF_FLAT"
+	if params := s.searchParams(10, vector.WithEarlyStop(0.8, 3)); len(params) != 0 {
+		t.Errorf("IVF_FLAT searchParams() = %v, want empty", params)
+	}
+}
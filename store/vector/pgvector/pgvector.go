@@ -274,6 +274,8 @@ func (s *Store) Add(ctx context.Context, docs []vector.Document) error {
 }
 
 // Search 搜索相似文档
+//
+// filter 为字段到值的等值过滤，或 vector.Filter.Map() 生成的过滤表达式，翻译为 SQL 条件。
 func (s *Store) Search(ctx context.Context, embedding []float32, topK int, filter map[string]any) ([]vector.Document, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -305,19 +307,15 @@ func (s *Store) Search(ctx context.Context, embedding []float32, topK int, filte
 
 	// 添加过滤条件
 	args := []any{embeddingStr}
-	argIdx := 2
-	if len(filter) > 0 {
-		var conditions []string
-		for key, value := range filter {
-			conditions = append(conditions,
-				fmt.Sprintf("metadata->>'%s' = $%d", key, argIdx))
-			args = append(args, fmt.Sprintf("%v", value))
-			argIdx++
-		}
-		querySQL += " WHERE " + strings.Join(conditions, " AND ")
+	f, err := vector.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		querySQL += " WHERE " + buildWhere(f, &args)
 	}
 
-	querySQL += fmt.Sprintf(" ORDER BY embedding %s $1 LIMIT $%d", distOp, argIdx)
+	querySQL += fmt.Sprintf(" ORDER BY embedding %s $1 LIMIT $%d", distOp, len(args)+1)
 	args = append(args, topK)
 
 	rows, err := s.db.QueryContext(ctx, querySQL, args...)
@@ -388,6 +386,11 @@ func (s *Store) Clear(ctx context.Context) error {
 	return err
 }
 
+// Dimension 返回配置的向量维度
+func (s *Store) Dimension() int {
+	return s.dimension
+}
+
 // Count 返回文档数量
 func (s *Store) Count(ctx context.Context) (int, error) {
 	s.mu.RLock()
@@ -446,3 +449,42 @@ func distanceToScore(distance float64, metric DistanceMetric) float32 {
 		return float32(1 - distance)
 	}
 }
+
+// buildWhere 将过滤表达式翻译为 SQL 条件，值以参数追加到 args
+//
+// Eq / In 按文本比较 metadata->>'field'；Gt / Lt 的值为数值时转为 numeric 比较，否则按文本比较。
+func buildWhere(f *vector.Filter, args *[]any) string {
+	switch f.Op {
+	case vector.OpAnd, vector.OpOr:
+		conditions := make([]string, len(f.Filters))
+		for i, sub := range f.Filters {
+			conditions[i] = buildWhere(sub, args)
+		}
+		return "(" + strings.Join(conditions, " "+strings.ToUpper(string(f.Op))+" ") + ")"
+	}
+
+	field := fmt.Sprintf("metadata->>'%s'", strings.ReplaceAll(f.Field, "'", "''"))
+	param := func(v any) string {
+		*args = append(*args, v)
+		return fmt.Sprintf("$%d", len(*args))
+	}
+	switch f.Op {
+	case vector.OpIn:
+		params := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			params[i] = param(fmt.Sprintf("%v", v))
+		}
+		return fmt.Sprintf("%s IN (%s)", field, strings.Join(params, ", "))
+	case vector.OpGt, vector.OpLt:
+		op := ">"
+		if f.Op == vector.OpLt {
+			op = "<"
+		}
+		if n, ok := vector.NumericValue(f.Value); ok {
+			return fmt.Sprintf("(%s)::numeric %s %s", field, op, param(n))
+		}
+		return fmt.Sprintf("%s %s %s", field, op, param(fmt.Sprintf("%v", f.Value)))
+	default:
+		return fmt.Sprintf("%s = %s", field, param(fmt.Sprintf("%v", f.Value)))
+	}
+}
@@ -233,8 +233,13 @@ func (s *Store) Search(ctx context.Context, embedding []float32, topK int, opts
 	}
 
 	// 如果有过滤条件
-	if cfg.Filter != nil {
-		queryReq.Filter = cfg.Filter
+	filter, err := vector.ParseFilter(cfg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		queryReq.Filter = buildFilter(filter)
+		queryReq.expr = filter
 	}
 
 	// 发送查询请求
@@ -333,6 +338,11 @@ func (s *Store) Clear(ctx context.Context) error {
 	return nil
 }
 
+// Dimension 返回配置的向量维度
+func (s *Store) Dimension() int {
+	return s.dimension
+}
+
 // Count 返回文档数量
 func (s *Store) Count(ctx context.Context) (int, error) {
 	s.mu.RLock()
@@ -357,6 +367,22 @@ func (s *Store) Close() error {
 
 var _ vector.Store = (*Store)(nil)
 
+// buildFilter 将过滤表达式翻译为 Pinecone 元数据过滤条件（$eq、$in、$gt、$lt、$and、$or）
+func buildFilter(f *vector.Filter) map[string]any {
+	switch f.Op {
+	case vector.OpAnd, vector.OpOr:
+		operands := make([]map[string]any, len(f.Filters))
+		for i, sub := range f.Filters {
+			operands[i] = buildFilter(sub)
+		}
+		return map[string]any{"$" + string(f.Op): operands}
+	case vector.OpIn:
+		return map[string]any{f.Field: map[string]any{"$in": f.Values}}
+	default:
+		return map[string]any{f.Field: map[string]any{"$" + string(f.Op): f.Value}}
+	}
+}
+
 // ============== Pinecone API 类型 ==============
 
 // pineconeVector Pinecone 向量格式
@@ -380,6 +406,9 @@ type queryRequest struct {
 	Filter          map[string]any `json:"filter,omitempty"`
 	IncludeValues   bool           `json:"includeValues"`
 	IncludeMetadata bool           `json:"includeMetadata"`
+
+	// expr 过滤表达式，供本地模拟搜索求值
+	expr *vector.Filter
 }
 
 // queryMatch 查询匹配结果
@@ -507,6 +536,9 @@ func (s *Store) localSearch(req *queryRequest) ([]queryMatch, error) {
 
 	var scores []scored
 	for id, doc := range s.documents {
+		if !req.expr.Match(doc.Metadata) {
+			continue
+		}
 		score := cosineSimilarity(req.Vector, doc.Embedding)
 		scores = append(scores, scored{id: id, score: score})
 	}
@@ -0,0 +1,232 @@
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hexagon-codes/hexagon/store/vector"
+)
+
+// FilterStore 支持过滤表达式的 Qdrant 存储
+//
+// 包装 ai-core 的 Qdrant 存储，Search 将过滤表达式翻译为 Qdrant 原生条件：
+//   - Eq 翻译为 match value，In 翻译为 match any
+//   - Gt / Lt 翻译为 range 条件，只支持数值比较，其他值返回 vector.ErrUnsupportedFilter
+//   - And 翻译为 must，Or 翻译为 should，可以任意嵌套
+//
+// 等值合取仍由 ai-core 存储发送，其他表达式由 FilterStore 直接请求 Qdrant 搜索接口。
+type FilterStore struct {
+	*Store
+
+	config  Config
+	client  *http.Client
+	baseURL string
+}
+
+var _ vector.Store = (*FilterStore)(nil)
+
+// NewFilterStore 创建支持过滤表达式的 Qdrant 存储
+func NewFilterStore(cfg Config) (*FilterStore, error) {
+	s, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 与 ai-core 存储使用相同的默认值
+	if cfg.Host == "" {
+		cfg.Host = "localhost"
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 6333
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	scheme := "http"
+	if cfg.HTTPS {
+		scheme = "https"
+	}
+
+	return &FilterStore{
+		Store:   s,
+		config:  cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		baseURL: fmt.Sprintf("%s://%s:%d", scheme, cfg.Host, cfg.Port),
+	}, nil
+}
+
+// NewFilterStoreWithOptions 使用选项创建支持过滤表达式的 Qdrant 存储
+func NewFilterStoreWithOptions(opts ...Option) (*FilterStore, error) {
+	cfg := Config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return NewFilterStore(cfg)
+}
+
+// Search 搜索相似文档
+// 过滤表达式无法翻译时返回包装 vector.ErrUnsupportedFilter 的错误，不发送请求
+func (s *FilterStore) Search(ctx context.Context, query []float32, k int, opts ...vector.SearchOption) ([]vector.Document, error) {
+	cfg := &vector.SearchConfig{IncludeMetadata: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	f, err := vector.ParseFilter(cfg.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("qdrant: %w", err)
+	}
+	if _, ok := f.Equalities(); f == nil || ok {
+		return s.Store.Search(ctx, query, k, opts...)
+	}
+
+	filter, err := buildFilter(f)
+	if err != nil {
+		return nil, fmt.Errorf("qdrant: %w", err)
+	}
+	req := map[string]any{
+		"vector":       query,
+		"limit":        k,
+		"with_payload": true,
+		"with_vector":  cfg.IncludeEmbedding,
+		"filter":       filter,
+	}
+	if cfg.MinScore > 0 {
+		req["score_threshold"] = cfg.MinScore
+	}
+
+	resp, err := s.doRequest(ctx, "/collections/"+s.config.Collection+"/points/search", req)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	var searchResp searchResponse
+	if err := json.Unmarshal(resp, &searchResp); err != nil {
+		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	}
+
+	docs := make([]vector.Document, len(searchResp.Result))
+	for i, point := range searchResp.Result {
+		docs[i] = point.document(cfg)
+	}
+	return docs, nil
+}
+
+// doRequest 发送 POST 请求
+func (s *FilterStore) doRequest(ctx context.Context, path string, body any) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.APIKey != "" {
+		req.Header.Set("api-key", s.config.APIKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// buildFilter 将过滤表达式翻译为 Qdrant 过滤对象
+func buildFilter(f *vector.Filter) (map[string]any, error) {
+	switch f.Op {
+	case vector.OpAnd, vector.OpOr:
+		conditions := make([]map[string]any, len(f.Filters))
+		for i, sub := range f.Filters {
+			cond, err := buildCondition(sub)
+			if err != nil {
+				return nil, err
+			}
+			conditions[i] = cond
+		}
+		clause := "must"
+		if f.Op == vector.OpOr {
+			clause = "should"
+		}
+		return map[string]any{clause: conditions}, nil
+	}
+
+	cond, err := buildCondition(f)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"must": []map[string]any{cond}}, nil
+}
+
+// buildCondition 将过滤表达式翻译为 Qdrant 条件，And / Or 翻译为嵌套过滤对象
+func buildCondition(f *vector.Filter) (map[string]any, error) {
+	switch f.Op {
+	case vector.OpAnd, vector.OpOr:
+		return buildFilter(f)
+	case vector.OpIn:
+		return map[string]any{"key": f.Field, "match": map[string]any{"any": f.Values}}, nil
+	case vector.OpGt, vector.OpLt:
+		n, ok := vector.NumericValue(f.Value)
+		if !ok {
+			return nil, fmt.Errorf("%w: range on %q requires a numeric value", vector.ErrUnsupportedFilter, f.Field)
+		}
+		return map[string]any{"key": f.Field, "range": map[string]any{string(f.Op): n}}, nil
+	default:
+		return map[string]any{"key": f.Field, "match": map[string]any{"value": f.Value}}, nil
+	}
+}
+
+type searchResponse struct {
+	Result []searchPoint `json:"result"`
+}
+
+type searchPoint struct {
+	ID      any            `json:"id"`
+	Score   float32        `json:"score"`
+	Payload map[string]any `json:"payload"`
+	Vector  []float32      `json:"vector"`
+}
+
+// document 按 ai-core 存储的 payload 约定还原文档
+func (p searchPoint) document(cfg *vector.SearchConfig) vector.Document {
+	doc := vector.Document{ID: fmt.Sprintf("%v", p.ID), Score: p.Score}
+	if id, ok := p.Payload["_original_id"].(string); ok && id != "" {
+		doc.ID = id
+	} else if n, ok := p.ID.(float64); ok {
+		doc.ID = fmt.Sprintf("%d", int64(n))
+	}
+	if content, ok := p.Payload["content"].(string); ok {
+		doc.Content = content
+	}
+	if createdAt, ok := p.Payload["created_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
+			doc.CreatedAt = t
+		}
+	}
+	if cfg.IncludeMetadata && p.Payload != nil {
+		doc.Metadata = make(map[string]any)
+		for k, v := range p.Payload {
+			if k != "content" && k != "created_at" && k != "_original_id" {
+				doc.Metadata[k] = v
+			}
+		}
+	}
+	if cfg.IncludeEmbedding {
+		doc.Embedding = p.Vector
+	}
+	return doc
+}
@@ -14,6 +14,10 @@
 //	    log.Fatal(err)
 //	}
 //	defer store.Close()
+//
+// Store 的元数据过滤只支持等值合取：vector.WithFilter，或仅由 vector.Eq / vector.And 组成的
+// vector.WithFilterExpr 表达式；其他表达式会原样发送，由服务端拒绝。
+// 需要 In、Gt、Lt、Or 等表达式时使用 NewFilterStore（见 FilterStore）。
 package qdrant
 
 import (
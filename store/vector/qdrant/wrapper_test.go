@@ -2,9 +2,18 @@ package qdrant_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/hexagon-codes/hexagon/store/vector"
 	"github.com/hexagon-codes/hexagon/store/vector/qdrant"
 )
 
@@ -386,3 +395,87 @@ func TestQdrantStore_Progress(t *testing.T) {
 		_ = errorCalled // 避免未使用警告
 	})
 }
+
+// newFilterStoreServer 创建记录搜索请求过滤条件的 Qdrant 模拟服务
+func newFilterStoreServer(t *testing.T, result string) (*qdrant.FilterStore, *[]map[string]any) {
+	t.Helper()
+	var mu sync.Mutex
+	var filters []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/points/search") {
+			var req struct {
+				Filter map[string]any `json:"filter"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			filters = append(filters, req.Filter)
+			mu.Unlock()
+			w.Write([]byte(result))
+			return
+		}
+		w.Write([]byte(`{"result":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	store, err := qdrant.NewFilterStore(qdrant.Config{Host: u.Hostname(), Port: port, Collection: "docs", Dimension: 2})
+	if err != nil {
+		t.Fatalf("NewFilterStore failed: %v", err)
+	}
+	return store, &filters
+}
+
+// TestFilterStore_FilterExpr 过滤表达式翻译为 Qdrant 原生条件
+func TestFilterStore_FilterExpr(t *testing.T) {
+	store, filters := newFilterStoreServer(t, `{"result":[{"id":1,"score":0.9,"payload":{"content":"hello","_original_id":"doc-1","year":2024}}]}`)
+	ctx := context.Background()
+
+	docs, err := store.Search(ctx, []float32{1, 0}, 5, vector.WithFilterExpr(
+		vector.And(vector.Gt("year", 2020), vector.Or(vector.In("tag", "go", "rust"), vector.Lt("score", 0.5)))))
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].ID != "doc-1" || docs[0].Content != "hello" || docs[0].Metadata["year"] != float64(2024) {
+		t.Errorf("unexpected docs: %+v", docs)
+	}
+
+	got, _ := json.Marshal((*filters)[0])
+	want := `{"must":[{"key":"year","range":{"gt":2020}},{"should":[{"key":"tag","match":{"any":["go","rust"]}},{"key":"score","range":{"lt":0.5}}]}]}`
+	if string(got) != want {
+		t.Errorf("filter = %s, want %s", got, want)
+	}
+
+	// 非数值的范围条件无法翻译，不发送请求
+	_, err = store.Search(ctx, []float32{1, 0}, 5, vector.WithFilterExpr(vector.Gt("source", "blog")))
+	if !errors.Is(err, vector.ErrUnsupportedFilter) {
+		t.Errorf("expected ErrUnsupportedFilter, got %v", err)
+	}
+	if len(*filters) != 1 {
+		t.Errorf("unsupported filter should not reach the server, got %d requests", len(*filters))
+	}
+
+	// 等值过滤仍由 ai-core 存储发送
+	if _, err := store.Search(ctx, []float32{1, 0}, 5, vector.WithFilterExpr(vector.Eq("source", "wiki"))); err != nil {
+		t.Errorf("equality filter failed: %v", err)
+	}
+	if len(*filters) != 2 {
+		t.Errorf("expected 2 search requests, got %d", len(*filters))
+	}
+}
+
+// TestFilterStore_NotExpired 过期过滤翻译为 expires_at 的 range 条件
+func TestFilterStore_NotExpired(t *testing.T) {
+	store, filters := newFilterStoreServer(t, `{"result":[]}`)
+
+	now := time.Unix(1700000000, 0)
+	if _, err := store.Search(context.Background(), []float32{1, 0}, 5, vector.WithFilterExpr(vector.NotExpired(now))); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	got, _ := json.Marshal((*filters)[0])
+	want := `{"must":[{"key":"expires_at","range":{"gt":1700000000}}]}`
+	if string(got) != want {
+		t.Errorf("filter = %s, want %s", got, want)
+	}
+}
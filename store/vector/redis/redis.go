@@ -249,6 +249,9 @@ func (s *Store) Add(ctx context.Context, docs []vector.Document) error {
 }
 
 // Search 搜索相似文档
+//
+// filter 为字段到值的等值过滤，或 vector.Filter.Map() 生成的过滤表达式。
+// 元数据以文本字段存储，只支持 Eq、In、And、Or，Gt / Lt 返回 vector.ErrUnsupportedFilter。
 func (s *Store) Search(ctx context.Context, embedding []float32, topK int, filter map[string]any) ([]vector.Document, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -263,12 +266,14 @@ func (s *Store) Search(ctx context.Context, embedding []float32, topK int, filte
 	// FT.SEARCH idx "(@filter:value)=>[KNN 5 @embedding $vec AS score]"
 	// PARAMS 2 vec <bytes> SORTBY score LIMIT 0 5 DIALECT 2
 	queryFilter := "*"
-	if len(filter) > 0 {
-		var parts []string
-		for key, value := range filter {
-			parts = append(parts, fmt.Sprintf("@metadata:{%s\\:%v}", key, value))
+	f, err := vector.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		if queryFilter, err = buildQueryFilter(f); err != nil {
+			return nil, err
 		}
-		queryFilter = strings.Join(parts, " ")
 	}
 
 	query := fmt.Sprintf("(%s)=>[KNN %d @embedding $vec AS score]", queryFilter, topK)
@@ -377,6 +382,11 @@ func (s *Store) Clear(ctx context.Context) error {
 	return s.createIndex(ctx)
 }
 
+// Dimension 返回配置的向量维度
+func (s *Store) Dimension() int {
+	return s.dimension
+}
+
 // Count 返回文档数量
 func (s *Store) Count(ctx context.Context) (int, error) {
 	s.mu.RLock()
@@ -438,3 +448,33 @@ func float32SliceToBytes(floats []float32) []byte {
 	}
 	return buf
 }
+
+// buildQueryFilter 将过滤表达式翻译为 RediSearch 查询条件
+func buildQueryFilter(f *vector.Filter) (string, error) {
+	switch f.Op {
+	case vector.OpEq:
+		return fmt.Sprintf("@metadata:{%s\\:%v}", f.Field, f.Value), nil
+	case vector.OpIn:
+		parts := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			parts[i] = fmt.Sprintf("%s\\:%v", f.Field, v)
+		}
+		return fmt.Sprintf("@metadata:{%s}", strings.Join(parts, " | ")), nil
+	case vector.OpAnd, vector.OpOr:
+		parts := make([]string, len(f.Filters))
+		for i, sub := range f.Filters {
+			part, err := buildQueryFilter(sub)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = part
+		}
+		sep := " "
+		if f.Op == vector.OpOr {
+			sep = " | "
+		}
+		return "(" + strings.Join(parts, sep) + ")", nil
+	default:
+		return "", fmt.Errorf("redis: %w: %s", vector.ErrUnsupportedFilter, f)
+	}
+}
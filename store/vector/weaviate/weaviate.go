@@ -291,7 +291,10 @@ func (s *Store) Search(ctx context.Context, embedding []float32, topK int, opts
 	}
 
 	// 使用 GraphQL 查询
-	query := s.buildGraphQLQuery(embedding, topK, cfg)
+	query, err := s.buildGraphQLQuery(embedding, topK, cfg)
+	if err != nil {
+		return nil, err
+	}
 	results, err := s.executeGraphQL(ctx, query)
 	if err != nil {
 		// 如果 GraphQL 失败，使用本地搜索
@@ -495,11 +498,11 @@ func (s *Store) createObject(ctx context.Context, obj *weaviateObject) error {
 }
 
 // buildGraphQLQuery 构建 GraphQL 查询
-func (s *Store) buildGraphQLQuery(embedding []float32, topK int, cfg *vector.SearchConfig) string {
-	var where string
-	if cfg.Filter != nil {
-		// 构建过滤条件
-		where = s.buildWhereClause(cfg.Filter)
+func (s *Store) buildGraphQLQuery(embedding []float32, topK int, cfg *vector.SearchConfig) (string, error) {
+	// 构建过滤条件
+	where, err := s.buildWhereClause(cfg.Filter)
+	if err != nil {
+		return "", err
 	}
 
 	query := fmt.Sprintf(`{
@@ -521,25 +524,64 @@ func (s *Store) buildGraphQLQuery(embedding []float32, topK int, cfg *vector.Sea
 		}
 	}`, s.className, embedding, topK, where)
 
-	return query
+	return query, nil
 }
 
 // buildWhereClause 构建 where 子句
-func (s *Store) buildWhereClause(filter map[string]any) string {
-	if len(filter) == 0 {
-		return ""
+func (s *Store) buildWhereClause(filter map[string]any) (string, error) {
+	f, err := vector.ParseFilter(filter)
+	if err != nil || f == nil {
+		return "", err
 	}
+	return "where: " + buildWhereOperand(f), nil
+}
 
-	var conditions []string
-	for k, v := range filter {
-		conditions = append(conditions, fmt.Sprintf(`{path: ["%s"], operator: Equal, valueString: "%v"}`, k, v))
+// buildWhereOperand 将过滤表达式翻译为 GraphQL where 操作数
+//
+// In 翻译为多个 Equal 的 Or，值按 Go 类型选择 valueInt、valueNumber、valueBoolean 或 valueString。
+func buildWhereOperand(f *vector.Filter) string {
+	switch f.Op {
+	case vector.OpAnd, vector.OpOr:
+		if len(f.Filters) == 1 {
+			return buildWhereOperand(f.Filters[0])
+		}
+		operands := make([]string, len(f.Filters))
+		for i, sub := range f.Filters {
+			operands[i] = buildWhereOperand(sub)
+		}
+		operator := "And"
+		if f.Op == vector.OpOr {
+			operator = "Or"
+		}
+		return fmt.Sprintf("{operator: %s, operands: [%s]}", operator, strings.Join(operands, ", "))
+	case vector.OpIn:
+		eqs := make([]*vector.Filter, len(f.Values))
+		for i, v := range f.Values {
+			eqs[i] = vector.Eq(f.Field, v)
+		}
+		return buildWhereOperand(vector.Or(eqs...))
 	}
 
-	if len(conditions) == 1 {
-		return fmt.Sprintf("where: %s", conditions[0])
-	}
+	operator := map[vector.FilterOp]string{
+		vector.OpEq: "Equal",
+		vector.OpGt: "GreaterThan",
+		vector.OpLt: "LessThan",
+	}[f.Op]
+	return fmt.Sprintf(`{path: [%q], operator: %s, %s}`, f.Field, operator, whereValue(f.Value))
+}
 
-	return fmt.Sprintf("where: {operator: And, operands: [%s]}", strings.Join(conditions, ", "))
+// whereValue 按值类型生成 GraphQL where 的值字段
+func whereValue(v any) string {
+	switch n := v.(type) {
+	case bool:
+		return fmt.Sprintf("valueBoolean: %t", n)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("valueInt: %d", n)
+	case float32, float64:
+		return fmt.Sprintf("valueNumber: %v", n)
+	default:
+		return fmt.Sprintf("valueString: %q", fmt.Sprintf("%v", v))
+	}
 }
 
 // executeGraphQL 执行 GraphQL 查询
@@ -618,8 +660,16 @@ func (s *Store) localSearch(embedding []float32, topK int, cfg *vector.SearchCon
 		score float64
 	}
 
+	filter, err := vector.ParseFilter(cfg.Filter)
+	if err != nil {
+		return nil, err
+	}
+
 	var scores []scored
 	for id, doc := range s.documents {
+		if !filter.Match(doc.Metadata) {
+			continue
+		}
 		score := weaviateCosineSimilarity(embedding, doc.Embedding)
 		scores = append(scores, scored{id: id, score: score})
 	}
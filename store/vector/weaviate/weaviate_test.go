@@ -3,6 +3,7 @@ package weaviate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -1169,16 +1170,22 @@ func TestBuildWhereClause(t *testing.T) {
 	store := &Store{className: "Test"}
 
 	t.Run("空过滤", func(t *testing.T) {
-		got := store.buildWhereClause(nil)
+		got, err := store.buildWhereClause(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
 		if got != "" {
 			t.Errorf("空过滤应返回空字符串, got %q", got)
 		}
 	})
 
 	t.Run("单条件", func(t *testing.T) {
-		got := store.buildWhereClause(map[string]any{
+		got, err := store.buildWhereClause(map[string]any{
 			"source": "test",
 		})
+		if err != nil {
+			t.Fatal(err)
+		}
 		if !strings.Contains(got, "where:") {
 			t.Error("应包含 'where:'")
 		}
@@ -1195,10 +1202,13 @@ func TestBuildWhereClause(t *testing.T) {
 	})
 
 	t.Run("多条件", func(t *testing.T) {
-		got := store.buildWhereClause(map[string]any{
+		got, err := store.buildWhereClause(map[string]any{
 			"source": "test",
 			"page":   "1",
 		})
+		if err != nil {
+			t.Fatal(err)
+		}
 		if !strings.Contains(got, "And") {
 			t.Error("多条件应包含 And")
 		}
@@ -1208,12 +1218,34 @@ func TestBuildWhereClause(t *testing.T) {
 	})
 }
 
+func TestBuildWhereOperand(t *testing.T) {
+	f := vector.And(
+		vector.Eq("source", "wiki"),
+		vector.Or(vector.Gt("year", 2020), vector.Lt("score", 0.5)),
+		vector.In("tag", "go", true),
+	)
+	want := `{operator: And, operands: [` +
+		`{path: ["source"], operator: Equal, valueString: "wiki"}, ` +
+		`{operator: Or, operands: [{path: ["year"], operator: GreaterThan, valueInt: 2020}, {path: ["score"], operator: LessThan, valueNumber: 0.5}]}, ` +
+		`{operator: Or, operands: [{path: ["tag"], operator: Equal, valueString: "go"}, {path: ["tag"], operator: Equal, valueBoolean: true}]}]}`
+	if got := buildWhereOperand(f); got != want {
+		t.Errorf("buildWhereOperand =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := (&Store{}).buildWhereClause(vector.Or().Map()); !errors.Is(err, vector.ErrUnsupportedFilter) {
+		t.Errorf("空 Or 应返回 ErrUnsupportedFilter, got %v", err)
+	}
+}
+
 func TestBuildGraphQLQuery(t *testing.T) {
 	store := &Store{className: "TestDoc"}
 
 	t.Run("基本查询", func(t *testing.T) {
-		query := store.buildGraphQLQuery(
+		query, err := store.buildGraphQLQuery(
 			[]float32{1, 0, 0}, 5, &vector.SearchConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
 
 		if !strings.Contains(query, "TestDoc") {
 			t.Error("查询应包含类名")
@@ -1233,11 +1265,14 @@ func TestBuildGraphQLQuery(t *testing.T) {
 	})
 
 	t.Run("带过滤查询", func(t *testing.T) {
-		query := store.buildGraphQLQuery(
+		query, err := store.buildGraphQLQuery(
 			[]float32{1, 0}, 3,
 			&vector.SearchConfig{
 				Filter: map[string]any{"source": "test"},
 			})
+		if err != nil {
+			t.Fatal(err)
+		}
 
 		if !strings.Contains(query, "where:") {
 			t.Error("应包含过滤条件")
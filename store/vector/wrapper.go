@@ -1,6 +1,7 @@
 // Package vector 提供向量存储抽象
 //
 // 本包重新导出 ai-core/store/vector 的实现，保持向后兼容性。
+// 元数据过滤使用可移植的过滤表达式（见 Filter），由各存储翻译为原生查询。
 //
 // 使用示例:
 //
@@ -14,6 +15,10 @@
 package vector
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	aicoreVector "github.com/hexagon-codes/ai-core/store/vector"
 )
 
@@ -49,7 +54,7 @@ var (
 	// NewEmbedderFunc 创建函数式 Embedder
 	NewEmbedderFunc = aicoreVector.NewEmbedderFunc
 
-	// WithFilter 设置过滤条件
+	// WithFilter 设置元数据等值过滤条件，多个字段之间为 AND；更多操作符见 WithFilterExpr
 	WithFilter = aicoreVector.WithFilter
 
 	// WithMinScore 设置最小分数
@@ -61,3 +66,164 @@ var (
 	// WithMetadata 设置是否返回元数据
 	WithMetadata = aicoreVector.WithMetadata
 )
+
+// FilterMemoryStore 支持过滤表达式的内存向量存储
+//
+// 包装 ai-core 的内存存储（MemoryStore）：
+//   - 在内存中对元数据求值，支持全部过滤表达式（见 Filter）
+//   - 带过期时间（见 SetExpiresAt）的文档过期后在下一次 Search、Get、Count 时被清除
+//   - 支持 WithEarlyStop
+//
+// MemoryStore 只支持等值过滤，需要上述能力时使用 NewFilterMemoryStore。
+type FilterMemoryStore struct {
+	*MemoryStore
+
+	mu sync.Mutex
+	// expiries 带过期时间的文档 ID 到过期时间
+	expiries map[string]time.Time
+}
+
+var _ Store = (*FilterMemoryStore)(nil)
+
+// NewFilterMemoryStore 创建支持过滤表达式的内存向量存储
+func NewFilterMemoryStore(dimension int) *FilterMemoryStore {
+	return &FilterMemoryStore{
+		MemoryStore: NewMemoryStore(dimension),
+		expiries:    make(map[string]time.Time),
+	}
+}
+
+// Add 添加文档，同 ID 文档会被覆盖
+func (s *FilterMemoryStore) Add(ctx context.Context, docs []Document) error {
+	if err := s.MemoryStore.Add(ctx, docs); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range docs {
+		if t, ok := ExpiresAt(doc); ok {
+			s.expiries[doc.ID] = t
+		} else {
+			delete(s.expiries, doc.ID)
+		}
+	}
+	return nil
+}
+
+// Get 根据 ID 获取文档，已过期的文档视为不存在
+func (s *FilterMemoryStore) Get(ctx context.Context, id string) (*Document, error) {
+	if _, err := s.Reap(ctx); err != nil {
+		return nil, err
+	}
+	return s.MemoryStore.Get(ctx, id)
+}
+
+// Delete 删除文档
+func (s *FilterMemoryStore) Delete(ctx context.Context, ids []string) error {
+	if err := s.MemoryStore.Delete(ctx, ids); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.expiries, id)
+	}
+	return nil
+}
+
+// Clear 清空所有文档
+func (s *FilterMemoryStore) Clear(ctx context.Context) error {
+	if err := s.MemoryStore.Clear(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.expiries)
+	return nil
+}
+
+// Count 返回未过期的文档数量
+func (s *FilterMemoryStore) Count(ctx context.Context) (int, error) {
+	if _, err := s.Reap(ctx); err != nil {
+		return 0, err
+	}
+	return s.MemoryStore.Count(ctx)
+}
+
+// Reap 删除已过期的文档，返回删除的数量
+func (s *FilterMemoryStore) Reap(ctx context.Context) (int, error) {
+	now := time.Now()
+	s.mu.Lock()
+	var expired []string
+	for id, t := range s.expiries {
+		if !t.After(now) {
+			expired = append(expired, id)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	if err := s.Delete(ctx, expired); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
+
+// Search 搜索相似文档，不返回已过期的文档
+//
+// 设置 WithEarlyStop 时仍精确计算全部相似度，已有足够的可接受结果时只返回这些结果。
+func (s *FilterMemoryStore) Search(ctx context.Context, query []float32, k int, opts ...SearchOption) ([]Document, error) {
+	docs, err := s.search(ctx, query, k, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if es, ok := EarlyStopFromOptions(opts...); ok {
+		docs = applyEarlyStop(docs, es)
+	}
+	return docs, nil
+}
+
+// search 按过滤条件搜索相似文档
+func (s *FilterMemoryStore) search(ctx context.Context, query []float32, k int, opts ...SearchOption) ([]Document, error) {
+	if _, err := s.Reap(ctx); err != nil {
+		return nil, err
+	}
+
+	cfg := &SearchConfig{IncludeMetadata: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	f, err := ParseFilter(cfg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.Equalities(); f == nil || ok {
+		return s.MemoryStore.Search(ctx, query, k, opts...)
+	}
+
+	// 等值以外的表达式：取全部候选后按表达式过滤
+	n, err := s.MemoryStore.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts[:len(opts):len(opts)], WithFilter(nil), WithMetadata(true))
+	candidates, err := s.MemoryStore.Search(ctx, query, n, opts...)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]Document, 0, min(max(k, 0), len(candidates)))
+	for _, doc := range candidates {
+		if len(docs) >= k {
+			break
+		}
+		if f.Match(doc.Metadata) {
+			if !cfg.IncludeMetadata {
+				doc.Metadata = nil
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
@@ -360,3 +360,48 @@ func BenchmarkMemoryStore(b *testing.B) {
 		}
 	})
 }
+
+// TestEarlyStopFromOptions 测试提前停止选项解析
+func TestEarlyStopFromOptions(t *testing.T) {
+	es, ok := vector.EarlyStopFromOptions(vector.WithMinScore(0.1), vector.WithEarlyStop(0.8, 3))
+	if !ok {
+		t.Fatal("EarlyStopFromOptions() ok = false, want true")
+	}
+	if es.MinAcceptable != 0.8 || es.SufficientCount != 3 {
+		t.Errorf("EarlyStop = %+v, want {0.8 3}", es)
+	}
+
+	if _, ok := vector.EarlyStopFromOptions(vector.WithMinScore(0.1)); ok {
+		t.Error("未设置时 ok 应为 false")
+	}
+	if _, ok := vector.EarlyStopFromOptions(vector.WithEarlyStop(0.8, 0)); ok {
+		t.Error("sufficientCount <= 0 时 ok 应为 false")
+	}
+
+	// FilterMemoryStore 已有足够的可接受结果时只返回这些结果
+	store := vector.NewFilterMemoryStore(2)
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Add(ctx, []vector.Document{
+		{ID: "a", Embedding: []float32{1, 0}},
+		{ID: "b", Embedding: []float32{1, 0.1}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	results, err := store.Search(ctx, []float32{1, 0}, 2, vector.WithFilter(map[string]any{}), vector.WithEarlyStop(0.5, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "a" {
+		t.Errorf("early stop results = %v, want [a]", results)
+	}
+
+	// 条件无法满足时返回常规结果
+	results, err = store.Search(ctx, []float32{1, 0}, 2, vector.WithEarlyStop(0.9999, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("len(results) = %d, want 2", len(results))
+	}
+}
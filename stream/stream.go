@@ -3,7 +3,8 @@
 // 本包实现了完整的流处理系统，包括：
 //   - StreamReader[T]: 泛型流读取器，支持多种底层实现
 //   - StreamWriter[T]: 泛型流写入器
-//   - 流操作符：Map、Filter、Reduce、Copy、Merge、Buffer、Timeout
+//   - 流操作符：Map、Filter、Reduce、Copy、Merge、MergeOrdered、Buffer、Timeout
+//   - 消费：Collect、CollectN、CollectInto、ForEach，提前终止时关闭流并通知上游停止
 //   - 类型注册：注册自定义类型的合并、分块函数
 //
 // 设计借鉴：
@@ -28,8 +29,10 @@
 package stream
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"sync"
 	"sync/atomic"
@@ -99,6 +102,7 @@ const (
 	readerTypeWindow
 	readerTypeDebounce
 	readerTypeThrottle
+	readerTypeOrdered
 )
 
 // StreamReader 泛型流读取器
@@ -128,6 +132,7 @@ type StreamReader[T any] struct {
 	windowR       any // *windowReader[T] 会产生 []T
 	debounceR     *debounceReader[T]
 	throttleR     *throttleReader[T]
+	orderedR      *orderedReader[T]
 
 	// 元信息
 	source string // 流来源标识
@@ -191,6 +196,8 @@ func (sr *StreamReader[T]) Recv() (T, error) {
 		return sr.debounceR.recv()
 	case readerTypeThrottle:
 		return sr.throttleR.recv()
+	case readerTypeOrdered:
+		return sr.orderedR.recv()
 	default:
 		var zero T
 		return zero, ErrStreamClosed
@@ -242,6 +249,8 @@ func (sr *StreamReader[T]) Close() error {
 		return sr.debounceR.close()
 	case readerTypeThrottle:
 		return sr.throttleR.close()
+	case readerTypeOrdered:
+		return sr.orderedR.close()
 	default:
 		return nil
 	}
@@ -259,48 +268,78 @@ func (sr *StreamReader[T]) SetSource(source string) *StreamReader[T] {
 }
 
 // Collect 收集所有元素到切片
+// 流可能无界时使用 CollectN 或 ForEach，避免无限制地占用内存
 func (sr *StreamReader[T]) Collect(ctx context.Context) ([]T, error) {
 	var items []T
-	for {
-		select {
-		case <-ctx.Done():
-			return items, ctx.Err()
-		default:
-			item, err := sr.Recv()
-			if err == io.EOF {
-				return items, nil
-			}
-			if _, ok := IsSourceEOF(err); ok {
-				continue // 忽略源 EOF，继续读取
-			}
-			if err != nil {
-				return items, err
-			}
-			items = append(items, item)
-		}
+	err := sr.CollectInto(ctx, &items)
+	return items, err
+}
+
+// CollectN 收集最多 n 个元素
+// 收集满 n 个后停止读取并关闭流，通知上游生产者停止；流提前结束时返回已收集的元素。
+// n <= 0 时直接关闭流并返回空结果。
+func (sr *StreamReader[T]) CollectN(ctx context.Context, n int) ([]T, error) {
+	if n <= 0 {
+		sr.Close()
+		return nil, nil
 	}
+	items := make([]T, 0, min(n, 64))
+	err := sr.consume(ctx, func(item T) (bool, error) {
+		items = append(items, item)
+		return len(items) < n, nil
+	})
+	return items, err
+}
+
+// CollectInto 将所有元素追加到 *dst
+// 调用方可复用已分配的切片（如传入 buf[:0]），出错时 *dst 保留已收到的元素
+func (sr *StreamReader[T]) CollectInto(ctx context.Context, dst *[]T) error {
+	return sr.consume(ctx, func(item T) (bool, error) {
+		*dst = append(*dst, item)
+		return true, nil
+	})
 }
 
-// ForEach 对每个元素执行操作
+// ForEach 逐个处理元素，不缓存到切片
+// fn 返回错误时停止读取、关闭流并返回该错误
 func (sr *StreamReader[T]) ForEach(ctx context.Context, fn func(T) error) error {
+	return sr.consume(ctx, func(item T) (bool, error) {
+		return true, fn(item)
+	})
+}
+
+// consume 逐个读取元素并交给 fn，直到流结束、fn 要求停止或返回错误
+//
+// 提前终止（fn 停止、出错或 ctx 取消）时关闭流，通知上游生产者停止。
+// ctx 取消会立即关闭流，唤醒阻塞在 Recv 上的读取，返回 ctx 的错误。
+func (sr *StreamReader[T]) consume(ctx context.Context, fn func(T) (bool, error)) error {
+	closeOnce := sync.OnceFunc(func() { sr.Close() })
+	stop := context.AfterFunc(ctx, closeOnce)
+	defer stop()
+
 	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			item, err := sr.Recv()
+		if err := ctx.Err(); err != nil {
+			closeOnce()
+			return err
+		}
+		item, err := sr.Recv()
+		if _, ok := IsSourceEOF(err); ok {
+			continue // 忽略源 EOF，继续读取
+		}
+		if err != nil {
+			// 被 ctx 取消关闭的流返回 EOF 或关闭错误，以取消原因为准
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
 			if err == io.EOF {
 				return nil
 			}
-			if _, ok := IsSourceEOF(err); ok {
-				continue
-			}
-			if err != nil {
-				return err
-			}
-			if err := fn(item); err != nil {
-				return err
-			}
+			return err
+		}
+		more, err := fn(item)
+		if err != nil || !more {
+			closeOnce()
+			return err
 		}
 	}
 }
@@ -583,6 +622,144 @@ func (mr *multiReader[T]) close() error {
 	return nil
 }
 
+// ============== Ordered 有序合并流 ==============
+
+// MergeOrdered 按选择函数有序合并多个流
+//
+// 每次读取时先确保每个未结束的源流都有一个待发送元素，
+// 再由 selector 从候选元素（按源流顺序排列）中选出要发送的下标。
+// 因此输出顺序只取决于各源流的内容和 selector，与到达时间无关；
+// 代价是需要等待所有未结束的源流都产出元素后才能发送。
+//
+// 源流结束后从候选中移除（不返回 SourceEOF），全部结束后返回 io.EOF。
+// 任一源流返回错误时合并流以该错误结束。
+func MergeOrdered[T any](sources []*StreamReader[T], selector func(candidates []T) int) *StreamReader[T] {
+	return newOrderedMerge(sources, func(_ []int, candidates []T) int {
+		return selector(candidates)
+	})
+}
+
+// MergeRoundRobin 按源流顺序轮流发送，已结束的源流被跳过
+func MergeRoundRobin[T any](sources []*StreamReader[T]) *StreamReader[T] {
+	last := -1
+	return newOrderedMerge(sources, func(active []int, _ []T) int {
+		pick := 0
+		for i, src := range active {
+			if src > last {
+				pick = i
+				break
+			}
+		}
+		last = active[pick]
+		return pick
+	})
+}
+
+// MergeSorted 合并按 key 升序排列的多个流，输出整体按 key 升序
+// key 相同时优先发送靠前的源流。降序输入（如按分数排列的检索结果）可让 key 返回相反数：
+//
+//	merged := stream.MergeSorted(sources, func(d rag.Document) float32 { return -d.Score })
+func MergeSorted[T any, K cmp.Ordered](sources []*StreamReader[T], key func(T) K) *StreamReader[T] {
+	return MergeOrdered(sources, func(candidates []T) int {
+		pick := 0
+		for i := 1; i < len(candidates); i++ {
+			if cmp.Less(key(candidates[i]), key(candidates[pick])) {
+				pick = i
+			}
+		}
+		return pick
+	})
+}
+
+// newOrderedMerge 创建有序合并流，pick 额外接收候选元素对应的源流下标
+func newOrderedMerge[T any](sources []*StreamReader[T], pick func(active []int, candidates []T) int) *StreamReader[T] {
+	return &StreamReader[T]{
+		typ: readerTypeOrdered,
+		orderedR: &orderedReader[T]{
+			sources: sources,
+			pick:    pick,
+			heads:   make([]T, len(sources)),
+			ready:   make([]bool, len(sources)),
+			done:    make([]bool, len(sources)),
+		},
+	}
+}
+
+type orderedReader[T any] struct {
+	sources []*StreamReader[T]
+	pick    func(active []int, candidates []T) int
+
+	// heads 各源流的待发送元素，ready 标记是否有效
+	heads []T
+	ready []bool
+	done  []bool
+	err   error
+	mu    sync.Mutex
+}
+
+func (om *orderedReader[T]) recv() (T, error) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	var zero T
+	if om.err != nil {
+		return zero, om.err
+	}
+
+	active := make([]int, 0, len(om.sources))
+	candidates := make([]T, 0, len(om.sources))
+	for i, src := range om.sources {
+		if om.done[i] {
+			continue
+		}
+		if !om.ready[i] {
+			item, err := recvSkipSourceEOF(src)
+			if err == io.EOF {
+				om.done[i] = true
+				continue
+			}
+			if err != nil {
+				om.err = err
+				return zero, err
+			}
+			om.heads[i], om.ready[i] = item, true
+		}
+		active = append(active, i)
+		candidates = append(candidates, om.heads[i])
+	}
+	if len(active) == 0 {
+		return zero, io.EOF
+	}
+
+	idx := om.pick(active, candidates)
+	if idx < 0 || idx >= len(active) {
+		om.err = fmt.Errorf("stream: selector returned index %d for %d candidates", idx, len(active))
+		return zero, om.err
+	}
+	src := active[idx]
+	item := om.heads[src]
+	om.heads[src], om.ready[src] = zero, false
+	return item, nil
+}
+
+func (om *orderedReader[T]) close() error {
+	for _, r := range om.sources {
+		r.Close()
+	}
+	return nil
+}
+
+// recvSkipSourceEOF 读取下一个元素，跳过嵌套合并流的 SourceEOF
+func recvSkipSourceEOF[T any](sr *StreamReader[T]) (T, error) {
+	for {
+		item, err := sr.Recv()
+		if _, ok := IsSourceEOF(err); ok {
+			continue
+		}
+		return item, err
+	}
+}
+
 // ============== Child 子流（用于 Copy）- 零分配 Linked List 设计 ==============
 // 借鉴 Eino 的 sync.Once + linked list 实现
 // 所有子 Reader 共享同一链表，只追踪不同的读取位置
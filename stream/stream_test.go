@@ -270,6 +270,97 @@ func TestStreamReader_ForEach_回调返回错误(t *testing.T) {
 	}
 }
 
+// TestStreamReader_CollectN_达到上限 验证 CollectN 收集满 n 个后关闭流并停止上游生产者
+func TestStreamReader_CollectN_达到上限(t *testing.T) {
+	reader, writer := Pipe[int](0)
+
+	producerErr := make(chan error, 1)
+	go func() {
+		for i := 0; ; i++ {
+			if err := writer.Send(i); err != nil {
+				producerErr <- err
+				return
+			}
+		}
+	}()
+
+	got, err := reader.CollectN(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("CollectN 失败: %v", err)
+	}
+	if !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("期望 [0 1 2]，得到 %v", got)
+	}
+
+	select {
+	case err := <-producerErr:
+		if !errors.Is(err, ErrStreamClosed) {
+			t.Errorf("期望生产者收到 ErrStreamClosed，得到 %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CollectN 返回后上游生产者未停止")
+	}
+}
+
+// TestStreamReader_CollectN_流提前结束 验证元素不足 n 个时返回全部元素
+func TestStreamReader_CollectN_流提前结束(t *testing.T) {
+	got, err := FromSlice([]int{1, 2}).CollectN(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("CollectN 失败: %v", err)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("期望 [1 2]，得到 %v", got)
+	}
+
+	got, err = FromSlice([]int{1, 2}).CollectN(context.Background(), 0)
+	if err != nil || len(got) != 0 {
+		t.Errorf("n=0 期望空结果，得到 %v, %v", got, err)
+	}
+}
+
+// TestStreamReader_CollectInto_复用切片 验证 CollectInto 追加到调用方的切片
+func TestStreamReader_CollectInto_复用切片(t *testing.T) {
+	buf := make([]int, 0, 8)
+	buf = append(buf, 0)
+
+	if err := FromSlice([]int{1, 2, 3}).CollectInto(context.Background(), &buf); err != nil {
+		t.Fatalf("CollectInto 失败: %v", err)
+	}
+	if !reflect.DeepEqual(buf, []int{0, 1, 2, 3}) {
+		t.Errorf("期望 [0 1 2 3]，得到 %v", buf)
+	}
+	if cap(buf) != 8 {
+		t.Errorf("期望复用原切片，cap 为 8，得到 %d", cap(buf))
+	}
+}
+
+// TestStreamReader_ForEach_阻塞时取消 验证 Recv 阻塞时取消上下文能立即返回并关闭流
+func TestStreamReader_ForEach_阻塞时取消(t *testing.T) {
+	reader, writer := Pipe[int](0)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- reader.ForEach(ctx, func(int) error { return nil })
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("期望 context.Canceled，得到 %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("取消上下文后 ForEach 未返回")
+	}
+
+	if err := writer.Send(1); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("期望流已关闭，Send 返回 %v", err)
+	}
+}
+
 // TestStreamReader_ForEach_上下文取消 验证 ForEach 在上下文取消时终止
 func TestStreamReader_ForEach_上下文取消(t *testing.T) {
 	reader, writer := Pipe[int](10)
@@ -1437,6 +1528,95 @@ func TestMerge_SourceEOF(t *testing.T) {
 	}
 }
 
+// TestMergeRoundRobin_轮流发送 验证按源流顺序轮流发送并跳过已结束的源流
+func TestMergeRoundRobin_轮流发送(t *testing.T) {
+	merged := MergeRoundRobin([]*StreamReader[int]{
+		FromSlice([]int{1, 2, 3}),
+		FromSlice([]int{10}),
+		FromSlice([]int{20, 21}),
+	})
+
+	got, err := merged.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect 失败: %v", err)
+	}
+	expected := []int{1, 10, 20, 2, 21, 3}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期望 %v，得到 %v", expected, got)
+	}
+}
+
+// TestMergeSorted_有序合并 验证合并有序流后整体有序，且与到达时间无关
+func TestMergeSorted_有序合并(t *testing.T) {
+	slow, writer := Pipe[int](0)
+	go func() {
+		for _, v := range []int{2, 5, 8} {
+			time.Sleep(5 * time.Millisecond)
+			writer.Send(v)
+		}
+		writer.Close()
+	}()
+
+	merged := MergeSorted([]*StreamReader[int]{
+		FromSlice([]int{1, 4, 9}),
+		slow,
+		FromSlice([]int{3, 5, 6}),
+	}, func(v int) int { return v })
+
+	got, err := merged.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect 失败: %v", err)
+	}
+	expected := []int{1, 2, 3, 4, 5, 5, 6, 8, 9}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("期望 %v，得到 %v", expected, got)
+	}
+}
+
+// TestMergeOrdered_自定义选择 验证自定义选择函数、错误传播和非法下标
+func TestMergeOrdered_自定义选择(t *testing.T) {
+	// 降序合并
+	merged := MergeOrdered([]*StreamReader[int]{
+		FromSlice([]int{9, 3}),
+		FromSlice([]int{7, 5, 1}),
+	}, func(c []int) int {
+		pick := 0
+		for i := range c {
+			if c[i] > c[pick] {
+				pick = i
+			}
+		}
+		return pick
+	})
+	got, err := merged.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect 失败: %v", err)
+	}
+	if expected := []int{9, 7, 5, 3, 1}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("期望 %v，得到 %v", expected, got)
+	}
+
+	// 源流错误结束合并流
+	failing, writer := Pipe[int](1)
+	boom := errors.New("boom")
+	writer.CloseWithError(boom)
+	merged = MergeOrdered([]*StreamReader[int]{FromSlice([]int{1}), failing}, func(c []int) int { return 0 })
+	if _, err := merged.Recv(); !errors.Is(err, boom) {
+		t.Errorf("期望源流错误，得到 %v", err)
+	}
+
+	// 非法下标
+	merged = MergeOrdered([]*StreamReader[int]{FromSlice([]int{1})}, func(c []int) int { return 5 })
+	if _, err := merged.Recv(); err == nil || err == io.EOF {
+		t.Errorf("期望非法下标错误，得到 %v", err)
+	}
+
+	// 空源流
+	if _, err := MergeOrdered(nil, func(c []int) int { return 0 }).Recv(); err != io.EOF {
+		t.Errorf("期望 io.EOF，得到 %v", err)
+	}
+}
+
 // =============================================================================
 // 分片操作测试
 // =============================================================================
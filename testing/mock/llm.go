@@ -3,6 +3,7 @@ package mock
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
@@ -20,6 +21,9 @@ type LLMProvider struct {
 
 	// 自定义响应函数
 	responseFn func(req llm.CompletionRequest) (*llm.CompletionResponse, error)
+
+	// supportsSeed 是否声明支持请求元数据中的随机种子
+	supportsSeed bool
 }
 
 // MockResponse 模拟响应
@@ -69,6 +73,17 @@ func (p *LLMProvider) WithResponseFn(fn func(req llm.CompletionRequest) (*llm.Co
 	return p
 }
 
+// WithSeedSupport 声明支持随机种子（实现 runtime.SeedSupporter）
+func (p *LLMProvider) WithSeedSupport() *LLMProvider {
+	p.supportsSeed = true
+	return p
+}
+
+// SupportsSeed 是否支持随机种子
+func (p *LLMProvider) SupportsSeed() bool {
+	return p.supportsSeed
+}
+
 // Name 返回 Provider 名称
 func (p *LLMProvider) Name() string {
 	return p.name
@@ -112,18 +127,44 @@ func (p *LLMProvider) Complete(ctx context.Context, req llm.CompletionRequest) (
 }
 
 // Stream 模拟流式请求
+// 以 OpenAI 流式格式返回下一个响应：内容按字符逐块发送，随后是工具调用和结束原因
 func (p *LLMProvider) Stream(ctx context.Context, req llm.CompletionRequest) (*llm.Stream, error) {
-	// 简化实现：直接返回完整响应作为流
 	resp, err := p.Complete(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	// 创建一个模拟的 SSE 响应
-	sseData := fmt.Sprintf("data: {\"content\":\"%s\"}\n\ndata: [DONE]\n\n", resp.Content)
-	reader := strings.NewReader(sseData)
+	var sse strings.Builder
+	writeDelta := func(delta map[string]any, finish string) {
+		choice := map[string]any{"index": 0, "delta": delta}
+		if finish != "" {
+			choice["finish_reason"] = finish
+		}
+		data, _ := json.Marshal(map[string]any{"choices": []any{choice}})
+		fmt.Fprintf(&sse, "data: %s\n\n", data)
+	}
+	for _, r := range resp.Content {
+		writeDelta(map[string]any{"content": string(r)}, "")
+	}
+	for i, tc := range resp.ToolCalls {
+		writeDelta(map[string]any{"tool_calls": []any{map[string]any{
+			"index": i,
+			"id":    tc.ID,
+			"type":  "function",
+			"function": map[string]any{
+				"name":      tc.Name,
+				"arguments": tc.Arguments,
+			},
+		}}}, "")
+	}
+	finish := "stop"
+	if len(resp.ToolCalls) > 0 {
+		finish = "tool_calls"
+	}
+	writeDelta(map[string]any{}, finish)
+	sse.WriteString("data: [DONE]\n\n")
 
-	return llm.NewStream(reader, llm.StreamOpenAIFormat), nil
+	return llm.NewStream(strings.NewReader(sse.String()), llm.StreamOpenAIFormat), nil
 }
 
 // Models 返回支持的模型列表
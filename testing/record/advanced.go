@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hexagon-codes/ai-core/tool"
 	"github.com/hexagon-codes/hexagon/rag"
 )
 
@@ -20,6 +21,7 @@ type ToolInteraction struct {
 	ToolName    string         `json:"tool_name"`
 	Args        map[string]any `json:"args"`
 	Result      string         `json:"result"`
+	Output      *tool.Result   `json:"output,omitempty"`
 	Error       string         `json:"error,omitempty"`
 	Duration    time.Duration  `json:"duration"`
 	Timestamp   time.Time      `json:"timestamp"`
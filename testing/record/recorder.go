@@ -1,9 +1,22 @@
 // Package record 提供测试的录制和回放功能
 //
-// Record 系统允许录制和回放 LLM 调用，便于测试和调试：
-//   - Recorder: 录制 LLM 调用
-//   - Replayer: 回放录制的调用
+// Record 系统允许录制和回放 LLM 调用与工具调用，便于测试和调试：
+//   - Recorder: 录制 LLM 调用，WrapTools 录制工具调用
+//   - Replayer: 回放录制的调用，WrapTools 回放工具结果而不执行真实工具
 //   - Cassette: 存储录制的会话
+//
+// 确定性回放（CI 回归测试）：
+//
+//	rec := record.NewRecorder(provider, "flaky-run")
+//	a := agent.NewReAct(agent.WithLLM(rec), agent.WithTools(rec.WrapTools(tools...)...))
+//	a.Run(ctx, input)
+//	rec.Save("testdata/flaky-run.json")
+//
+//	cassette, _ := record.LoadCassette("testdata/flaky-run.json")
+//	rep := record.NewReplayer(cassette, record.WithReplayMode(record.ReplayModeSequential))
+//	a := agent.NewReAct(agent.WithLLM(rep), agent.WithTools(rep.WrapTools(tools...)...))
+//	a.Run(ctx, input) // 请求与录制不一致时返回 ErrReplayMismatch
+//	rep.Verify()      // 录制未被完整消费时返回错误
 package record
 
 import (
@@ -11,6 +24,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -18,6 +32,7 @@ import (
 	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	agentruntime "github.com/hexagon-codes/hexagon/runtime"
 )
 
 // Interaction 表示一次 LLM 交互
@@ -55,6 +70,9 @@ type Cassette struct {
 	// Interactions 交互列表
 	Interactions []Interaction `json:"interactions"`
 
+	// Tools 工具调用列表，按调用顺序排列
+	Tools []ToolInteraction `json:"tools,omitempty"`
+
 	// Metadata 元数据
 	Metadata map[string]any `json:"metadata,omitempty"`
 
@@ -145,6 +163,11 @@ func (r *Recorder) Name() string {
 	return r.provider.Name() + "_recorder"
 }
 
+// SupportsSeed 与被录制的 Provider 一致
+func (r *Recorder) SupportsSeed() bool {
+	return agentruntime.SupportsSeed(r.provider)
+}
+
 // Complete 执行并录制请求
 func (r *Recorder) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
 	start := time.Now()
@@ -217,8 +240,15 @@ const (
 
 	// ReplayModeFallback 回退模式：找不到时使用真实 Provider
 	ReplayModeFallback ReplayMode = "fallback"
+
+	// ReplayModeSequential 顺序模式：按录制顺序逐条回放，
+	// 请求与下一条录制不一致时返回 ErrReplayMismatch
+	ReplayModeSequential ReplayMode = "sequential"
 )
 
+// ErrReplayMismatch 实际请求与录制不一致，或录制已耗尽
+var ErrReplayMismatch = errors.New("record: request does not match recording")
+
 // Replayer 回放录制的 LLM 调用
 type Replayer struct {
 	cassette    *Cassette
@@ -228,6 +258,10 @@ type Replayer struct {
 	missCount   int
 	hitCount    int
 	usedIndices map[int]bool
+
+	// next / nextTool 顺序模式下下一条待回放的 LLM 交互和工具调用
+	next     int
+	nextTool int
 }
 
 // ReplayerOption Replayer 选项
@@ -273,6 +307,10 @@ func (r *Replayer) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 	// 计算请求哈希
 	hash := hashRequest(req)
 
+	if r.mode == ReplayModeSequential {
+		return r.completeSequential(hash)
+	}
+
 	// 查找匹配的交互
 	interaction := r.cassette.FindByHash(hash)
 
@@ -295,6 +333,43 @@ func (r *Replayer) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 	return nil, fmt.Errorf("no matching recording found for request (hash: %s)", hash)
 }
 
+// completeSequential 按顺序回放下一条 LLM 交互，调用方需持有锁
+func (r *Replayer) completeSequential(hash string) (*llm.CompletionResponse, error) {
+	if r.next >= len(r.cassette.Interactions) {
+		r.missCount++
+		return nil, fmt.Errorf("%w: unexpected llm call #%d, recording has %d", ErrReplayMismatch, r.next+1, len(r.cassette.Interactions))
+	}
+	interaction := r.cassette.Interactions[r.next]
+	if interaction.RequestHash != hash {
+		r.missCount++
+		return nil, fmt.Errorf("%w: llm call #%d diverged (recorded hash %s, got %s)", ErrReplayMismatch, r.next+1, interaction.RequestHash, hash)
+	}
+	r.next++
+	r.hitCount++
+
+	if interaction.Error != "" {
+		return nil, fmt.Errorf("%s", interaction.Error)
+	}
+	return interaction.Response, nil
+}
+
+// Verify 检查顺序模式下录制是否被完整回放
+// 实际运行提前结束（少于录制的调用次数）时返回 ErrReplayMismatch，其他模式下始终返回 nil
+func (r *Replayer) Verify() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode != ReplayModeSequential {
+		return nil
+	}
+	if r.next < len(r.cassette.Interactions) {
+		return fmt.Errorf("%w: %d of %d llm calls replayed", ErrReplayMismatch, r.next, len(r.cassette.Interactions))
+	}
+	if r.nextTool < len(r.cassette.Tools) {
+		return fmt.Errorf("%w: %d of %d tool calls replayed", ErrReplayMismatch, r.nextTool, len(r.cassette.Tools))
+	}
+	return nil
+}
+
 // Stream 回放流式请求
 func (r *Replayer) Stream(ctx context.Context, req llm.CompletionRequest) (*llm.Stream, error) {
 	// 简化处理：使用回退 Provider
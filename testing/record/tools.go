@@ -0,0 +1,163 @@
+package record
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/tool"
+)
+
+// ============== Tool Recording ==============
+
+// WrapTool 包装工具，执行时将参数和结果按顺序录制到 Cassette.Tools
+func (r *Recorder) WrapTool(t tool.Tool) tool.Tool {
+	return &recordedTool{Tool: t, recorder: r}
+}
+
+// WrapTools 批量包装工具，参见 WrapTool
+func (r *Recorder) WrapTools(tools ...tool.Tool) []tool.Tool {
+	wrapped := make([]tool.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = r.WrapTool(t)
+	}
+	return wrapped
+}
+
+// recordedTool 录制调用的工具
+type recordedTool struct {
+	tool.Tool
+	recorder *Recorder
+}
+
+// Execute 执行并录制工具调用
+func (t *recordedTool) Execute(ctx context.Context, args map[string]any) (tool.Result, error) {
+	start := time.Now()
+	result, err := t.Tool.Execute(ctx, args)
+
+	interaction := ToolInteraction{
+		ID:          fmt.Sprintf("tool_%d", time.Now().UnixNano()),
+		ToolName:    t.Name(),
+		Args:        args,
+		Result:      result.String(),
+		Duration:    time.Since(start),
+		Timestamp:   start,
+		RequestHash: hashToolCall(t.Name(), args),
+	}
+	if err != nil {
+		interaction.Error = err.Error()
+	} else {
+		interaction.Output = &result
+	}
+
+	t.recorder.mu.Lock()
+	t.recorder.cassette.Tools = append(t.recorder.cassette.Tools, interaction)
+	t.recorder.cassette.UpdatedAt = time.Now()
+	t.recorder.mu.Unlock()
+
+	return result, err
+}
+
+var _ tool.Tool = (*recordedTool)(nil)
+
+// ============== Tool Replay ==============
+
+// WrapTool 包装工具，执行时回放录制的结果，不调用真实工具
+//
+// 返回的工具保留原工具的名称、描述和参数 Schema。
+// 顺序模式下按录制顺序回放，工具名或参数与录制不一致时返回 ErrReplayMismatch；
+// 其他模式下按工具名和参数查找录制，找不到时在回退模式下执行真实工具。
+func (r *Replayer) WrapTool(t tool.Tool) tool.Tool {
+	return &replayedTool{Tool: t, replayer: r}
+}
+
+// WrapTools 批量包装工具，参见 WrapTool
+func (r *Replayer) WrapTools(tools ...tool.Tool) []tool.Tool {
+	wrapped := make([]tool.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = r.WrapTool(t)
+	}
+	return wrapped
+}
+
+// replayedTool 回放调用结果的工具
+type replayedTool struct {
+	tool.Tool
+	replayer *Replayer
+}
+
+// Execute 回放工具调用
+func (t *replayedTool) Execute(ctx context.Context, args map[string]any) (tool.Result, error) {
+	interaction, err := t.replayer.nextToolInteraction(t.Name(), args)
+	if err != nil {
+		if errors.Is(err, errToolNotRecorded) && t.replayer.mode == ReplayModeFallback {
+			return t.Tool.Execute(ctx, args)
+		}
+		return tool.Result{}, err
+	}
+
+	if interaction.Error != "" {
+		return tool.Result{}, fmt.Errorf("%s", interaction.Error)
+	}
+	if interaction.Output != nil {
+		return *interaction.Output, nil
+	}
+	return tool.NewResult(interaction.Result), nil
+}
+
+var _ tool.Tool = (*replayedTool)(nil)
+
+// errToolNotRecorded 非顺序模式下找不到匹配的工具录制
+var errToolNotRecorded = errors.New("record: no matching tool recording found")
+
+// nextToolInteraction 查找待回放的工具调用
+func (r *Replayer) nextToolInteraction(name string, args map[string]any) (*ToolInteraction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash := hashToolCall(name, args)
+	if r.mode != ReplayModeSequential {
+		for i := range r.cassette.Tools {
+			if r.cassette.Tools[i].RequestHash == hash {
+				r.hitCount++
+				return &r.cassette.Tools[i], nil
+			}
+		}
+		r.missCount++
+		return nil, fmt.Errorf("%w: %s (hash: %s)", errToolNotRecorded, name, hash)
+	}
+
+	if r.nextTool >= len(r.cassette.Tools) {
+		r.missCount++
+		return nil, fmt.Errorf("%w: unexpected tool call #%d %s, recording has %d",
+			ErrReplayMismatch, r.nextTool+1, name, len(r.cassette.Tools))
+	}
+	interaction := &r.cassette.Tools[r.nextTool]
+	if interaction.RequestHash != hash {
+		r.missCount++
+		return nil, fmt.Errorf("%w: tool call #%d diverged (recorded %s, got %s)",
+			ErrReplayMismatch, r.nextTool+1, interaction.ToolName, name)
+	}
+	r.nextTool++
+	r.hitCount++
+	return interaction, nil
+}
+
+// hashToolCall 计算工具调用的哈希值
+func hashToolCall(name string, args map[string]any) string {
+	data := struct {
+		Name string
+		Args map[string]any
+	}{
+		Name: name,
+		Args: args,
+	}
+
+	b, _ := json.Marshal(data)
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:8])
+}
@@ -0,0 +1,112 @@
+package record
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/tool"
+	"github.com/hexagon-codes/hexagon/agent"
+	"github.com/hexagon-codes/hexagon/testing/mock"
+)
+
+// recordAgentRun 用 mock LLM 和工具录制一次 ReAct 运行并保存到文件
+func recordAgentRun(t *testing.T) (string, agent.Output) {
+	t.Helper()
+	provider := mock.NewLLMProvider("live").
+		AddToolCallResponse([]llm.ToolCall{{ID: "call_1", Name: "weather", Arguments: `{"city":"Paris"}`}}).
+		AddResponse("It is sunny in Paris")
+	weather := mock.NewTool("weather")
+	weather.AddResult(map[string]any{"forecast": "sunny"})
+
+	rec := NewRecorder(provider, "weather-run")
+	a := agent.NewReAct(agent.WithLLM(rec), agent.WithTools(rec.WrapTools(weather)...))
+	output, err := a.Run(context.Background(), agent.Input{Query: "Weather in Paris?"})
+	if err != nil {
+		t.Fatalf("recorded run failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "weather-run.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	return path, output
+}
+
+// offlineTool 回放时不应被执行的工具
+func offlineTool(t *testing.T) tool.Tool {
+	return mock.NewTool("weather", mock.WithToolExecuteFn(func(ctx context.Context, args map[string]any) (tool.Result, error) {
+		t.Error("real tool must not run during replay")
+		return tool.Result{}, errors.New("offline")
+	}))
+}
+
+func TestRecordReplayAgentRun(t *testing.T) {
+	path, recorded := recordAgentRun(t)
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	if len(cassette.Interactions) != 2 || len(cassette.Tools) != 1 {
+		t.Fatalf("expected 2 llm and 1 tool interactions, got %d/%d", len(cassette.Interactions), len(cassette.Tools))
+	}
+
+	rep := NewReplayer(cassette, WithReplayMode(ReplayModeSequential))
+	a := agent.NewReAct(agent.WithLLM(rep), agent.WithTools(rep.WrapTools(offlineTool(t))...))
+	output, err := a.Run(context.Background(), agent.Input{Query: "Weather in Paris?"})
+	if err != nil {
+		t.Fatalf("replayed run failed: %v", err)
+	}
+	if output.Content != recorded.Content {
+		t.Errorf("expected %q, got %q", recorded.Content, output.Content)
+	}
+	if len(output.ToolCalls) != 1 || output.ToolCalls[0].Result.String() != recorded.ToolCalls[0].Result.String() {
+		t.Errorf("expected replayed tool result, got %+v", output.ToolCalls)
+	}
+	if err := rep.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+func TestReplaySequentialDiverged(t *testing.T) {
+	path, _ := recordAgentRun(t)
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+
+	rep := NewReplayer(cassette, WithReplayMode(ReplayModeSequential))
+	a := agent.NewReAct(agent.WithLLM(rep), agent.WithTools(rep.WrapTools(offlineTool(t))...))
+	if _, err := a.Run(context.Background(), agent.Input{Query: "Weather in Rome?"}); !errors.Is(err, ErrReplayMismatch) {
+		t.Fatalf("expected ErrReplayMismatch, got %v", err)
+	}
+	if err := rep.Verify(); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("expected Verify to report unconsumed recording, got %v", err)
+	}
+}
+
+func TestReplayToolDiverged(t *testing.T) {
+	cassette := NewCassette("tools")
+	cassette.Tools = append(cassette.Tools, ToolInteraction{
+		ToolName:    "weather",
+		Args:        map[string]any{"city": "Paris"},
+		Result:      "sunny",
+		RequestHash: hashToolCall("weather", map[string]any{"city": "Paris"}),
+	})
+	rep := NewReplayer(cassette, WithReplayMode(ReplayModeSequential))
+	weather := rep.WrapTool(offlineTool(t))
+
+	if _, err := weather.Execute(context.Background(), map[string]any{"city": "Rome"}); !errors.Is(err, ErrReplayMismatch) {
+		t.Fatalf("expected ErrReplayMismatch, got %v", err)
+	}
+	result, err := weather.Execute(context.Background(), map[string]any{"city": "Paris"})
+	if err != nil || result.Output != "sunny" {
+		t.Fatalf("expected recorded result, got %v, %v", result, err)
+	}
+	if _, err := weather.Execute(context.Background(), map[string]any{"city": "Paris"}); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("expected exhausted recording error, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package tool
+
+import (
+	aitool "github.com/hexagon-codes/ai-core/tool"
+)
+
+// ============== 结果缓存标记 ==============
+
+// Cacheable 声明工具结果能否被缓存
+//
+// Agent 启用工具结果缓存（agent.WithToolCache）时，默认缓存所有工具；
+// 有副作用的工具（发送邮件、写入数据库）或结果随时间变化的工具应实现此接口并返回 false，
+// 或用 WithCacheable(t, false) 包装。
+type Cacheable interface {
+	Cacheable() bool
+}
+
+// WithCacheable 标记工具结果能否被缓存
+//
+// 标记在被 WithTimeout、WithRetry、WithRateLimit 或 WithMiddleware 再次包装后仍然有效
+// （见 IsCacheable）。
+//
+// 示例：
+//
+//	sendEmail = tool.WithCacheable(sendEmail, false)
+func WithCacheable(t aitool.Tool, cacheable bool) aitool.Tool {
+	return &cacheableTool{Tool: t, cacheable: cacheable}
+}
+
+// Unwrapper 由包装其他工具的中间件实现，返回被包装的工具
+type Unwrapper interface {
+	Unwrap() aitool.Tool
+}
+
+// IsCacheable 判断工具结果能否被缓存，未声明的工具视为可缓存
+//
+// 沿 Unwrapper 链向内查找，最外层的 Cacheable 声明生效。
+func IsCacheable(t aitool.Tool) bool {
+	for t != nil {
+		if c, ok := t.(Cacheable); ok {
+			return c.Cacheable()
+		}
+		u, ok := t.(Unwrapper)
+		if !ok {
+			break
+		}
+		t = u.Unwrap()
+	}
+	return true
+}
+
+type cacheableTool struct {
+	aitool.Tool
+	cacheable bool
+}
+
+func (t *cacheableTool) Cacheable() bool { return t.cacheable }
+
+func (t *cacheableTool) Unwrap() aitool.Tool { return t.Tool }
+
+var (
+	_ Cacheable = (*cacheableTool)(nil)
+	_ Unwrapper = (*cacheableTool)(nil)
+)
@@ -58,6 +58,9 @@ func (t *timeoutTool) Execute(ctx context.Context, args map[string]any) (aitool.
 	return t.inner.Execute(ctx, args)
 }
 
+// Unwrap 返回被包装的工具
+func (t *timeoutTool) Unwrap() aitool.Tool { return t.inner }
+
 // ============== 重试中间件 ==============
 
 // WithRetry 为工具添加失败重试能力
@@ -101,6 +104,9 @@ func (t *retryTool) Execute(ctx context.Context, args map[string]any) (aitool.Re
 	return result, err
 }
 
+// Unwrap 返回被包装的工具
+func (t *retryTool) Unwrap() aitool.Tool { return t.inner }
+
 // ============== 速率限制中间件 ==============
 
 // WithRateLimit 为工具添加速率限制
@@ -145,6 +151,9 @@ func (t *rateLimitTool) Execute(ctx context.Context, args map[string]any) (aitoo
 	return t.inner.Execute(ctx, args)
 }
 
+// Unwrap 返回被包装的工具
+func (t *rateLimitTool) Unwrap() aitool.Tool { return t.inner }
+
 // waitForToken 等待获取速率令牌
 func (t *rateLimitTool) waitForToken(ctx context.Context) error {
 	for {
@@ -27,6 +27,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/hexagon-codes/hexagon/core"
 )
 
 // ============== 错误定义 ==============
@@ -36,10 +38,10 @@ var (
 	ErrSearchFailed = errors.New("search failed")
 
 	// ErrRateLimited 被限流
-	ErrRateLimited = errors.New("rate limited")
+	ErrRateLimited = core.ErrRateLimited
 
 	// ErrInvalidAPIKey 无效的 API Key
-	ErrInvalidAPIKey = errors.New("invalid API key")
+	ErrInvalidAPIKey = core.NewCategoryError("invalid API key", core.ErrUnauthorized)
 
 	// ErrNoResults 无搜索结果
 	ErrNoResults = errors.New("no results found")